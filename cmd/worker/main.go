@@ -1,5 +1,40 @@
 package main
 
+import (
+	"context"
+	"fiber-usermanagement/internal/config"
+	"fiber-usermanagement/internal/container"
+	"fiber-usermanagement/internal/worker"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"go.uber.org/zap"
+)
+
 func main() {
+	appContainer, err := config.NewAppContainer()
+	if err != nil {
+		panic("Failed to initialize application: " + err.Error())
+	}
+
+	// Ensure cleanup on exit
+	defer func() {
+		if err := appContainer.Close(); err != nil {
+			appContainer.Logger.Error("Error during worker shutdown", zap.Error(err))
+		}
+	}()
+
+	businessContainer, err := container.NewContainer(appContainer)
+	if err != nil {
+		appContainer.Logger.Fatal("Failed to initialize business container", zap.Error(err))
+	}
+
+	// Stop scheduling new runs on interrupt and wait for running jobs to finish
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
 
+	appContainer.Logger.Info("Starting worker")
+	worker.NewScheduler(appContainer.Logger, businessContainer.ScheduledJobs()...).Run(ctx)
+	appContainer.Logger.Info("Worker exited")
 }
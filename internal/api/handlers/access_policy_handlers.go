@@ -0,0 +1,168 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AccessPolicyHandler menangani permintaan HTTP untuk kebijakan akses ABAC dan pemeriksaan akses oleh layanan lain.
+type AccessPolicyHandler struct {
+	policyInteractor *interactors.AccessPolicyInteractor
+	authzInteractor  *interactors.AuthorizationInteractor
+}
+
+// NewAccessPolicyHandler membuat instance baru dari AccessPolicyHandler.
+func NewAccessPolicyHandler(pi *interactors.AccessPolicyInteractor, ai *interactors.AuthorizationInteractor) *AccessPolicyHandler {
+	return &AccessPolicyHandler{policyInteractor: pi, authzInteractor: ai}
+}
+
+// accessPolicyRequest adalah body permintaan membuat atau mengganti kebijakan akses.
+type accessPolicyRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Effect      string   `json:"effect"`
+	Actions     []string `json:"actions"`
+	Condition   string   `json:"condition"`
+	Enabled     *bool    `json:"enabled"` // Default true
+}
+
+func (r *accessPolicyRequest) input() interactors.AccessPolicyInput {
+	return interactors.AccessPolicyInput{
+		Name:        r.Name,
+		Description: r.Description,
+		Effect:      r.Effect,
+		Actions:     r.Actions,
+		Condition:   r.Condition,
+		Enabled:     r.Enabled == nil || *r.Enabled,
+	}
+}
+
+// authzCheckRequest adalah body permintaan POST /authz/check.
+type authzCheckRequest struct {
+	SubjectID      uuid.UUID      `json:"subject_id"`
+	Action         string         `json:"action"`                    // Permission konkret, misalnya users:write
+	OrganizationID *uuid.UUID     `json:"organization_id,omitempty"` // Periksa Role anggota di organisasi ini
+	Resource       map[string]any `json:"resource,omitempty"`        // Atribut resource; "id" dan "type" dipakai khusus
+	Environment    map[string]any `json:"environment,omitempty"`     // Atribut env tambahan, misalnya ip pengguna akhir
+}
+
+// CreatePolicy menangani pembuatan kebijakan akses oleh admin.
+func (h *AccessPolicyHandler) CreatePolicy(c *fiber.Ctx) error {
+	req := new(accessPolicyRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	policy, err := h.policyInteractor.Create(req.input())
+	if err != nil {
+		return accessPolicyErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(policy)
+}
+
+// ListPolicies menangani pengambilan semua kebijakan akses.
+func (h *AccessPolicyHandler) ListPolicies(c *fiber.Ctx) error {
+	policies, err := h.policyInteractor.List()
+	if err != nil {
+		return accessPolicyErrorResponse(c, err)
+	}
+	return c.JSON(policies)
+}
+
+// GetPolicy menangani pengambilan satu kebijakan akses.
+func (h *AccessPolicyHandler) GetPolicy(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID kebijakan tidak valid"})
+	}
+
+	policy, err := h.policyInteractor.Get(id)
+	if err != nil {
+		return accessPolicyErrorResponse(c, err)
+	}
+	return c.JSON(policy)
+}
+
+// UpdatePolicy menangani penggantian isi kebijakan akses.
+func (h *AccessPolicyHandler) UpdatePolicy(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID kebijakan tidak valid"})
+	}
+
+	req := new(accessPolicyRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	policy, err := h.policyInteractor.Update(id, req.input())
+	if err != nil {
+		return accessPolicyErrorResponse(c, err)
+	}
+	return c.JSON(policy)
+}
+
+// DeletePolicy menangani penghapusan kebijakan akses.
+func (h *AccessPolicyHandler) DeletePolicy(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID kebijakan tidak valid"})
+	}
+
+	if err := h.policyInteractor.Delete(id); err != nil {
+		return accessPolicyErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// Check menangani pertanyaan keputusan akses dari layanan lain: bolehkah subjek melakukan aksi pada resource.
+func (h *AccessPolicyHandler) Check(c *fiber.Ctx) error {
+	req := new(authzCheckRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	access := interactors.AccessRequest{
+		Permission:  req.Action,
+		Resource:    req.Resource,
+		Environment: req.Environment,
+	}
+	if req.OrganizationID != nil {
+		access.OrganizationID = *req.OrganizationID
+	}
+	if id, ok := req.Resource["id"].(string); ok {
+		access.ResourceID = id
+	}
+
+	decision, err := h.authzInteractor.Check(req.SubjectID, access)
+	if err != nil {
+		return accessPolicyErrorResponse(c, err)
+	}
+	return c.JSON(decision)
+}
+
+// accessPolicyErrorResponse memetakan error kebijakan akses dan pemeriksaan akses ke respons HTTP.
+func accessPolicyErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrAccessPolicyNotFound),
+		errors.Is(err, interactors.ErrAuthzSubjectNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrAccessPolicyNameRequired),
+		errors.Is(err, interactors.ErrAccessPolicyEffectInvalid),
+		errors.Is(err, interactors.ErrAccessPolicyActionsRequired),
+		errors.Is(err, interactors.ErrAccessPolicyActionInvalid),
+		errors.Is(err, interactors.ErrPolicyConditionInvalid),
+		errors.Is(err, interactors.ErrAuthzActionInvalid):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrAccessPolicyNameTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan kebijakan akses di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses kebijakan akses"})
+	}
+}
@@ -0,0 +1,199 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AccessReviewHandler menangani permintaan HTTP untuk kampanye tinjauan akses.
+type AccessReviewHandler struct {
+	reviewInteractor *interactors.AccessReviewInteractor
+}
+
+// NewAccessReviewHandler membuat instance baru dari AccessReviewHandler.
+func NewAccessReviewHandler(ri *interactors.AccessReviewInteractor) *AccessReviewHandler {
+	return &AccessReviewHandler{reviewInteractor: ri}
+}
+
+// accessReviewRequest adalah body permintaan membuat kampanye tinjauan akses.
+type accessReviewRequest struct {
+	Name             string    `json:"name"`
+	Description      string    `json:"description"`
+	Roles            []string  `json:"roles"`             // Role dalam cakupan; kosong berarti semua Role
+	ReviewerStrategy string    `json:"reviewer_strategy"` // manager atau role_owner
+	FallbackReviewer string    `json:"fallback_reviewer"` // Username atau email peninjau cadangan
+	DueAt            time.Time `json:"due_at"`
+	RevokeOnExpiry   bool      `json:"revoke_on_expiry"`
+}
+
+func (r *accessReviewRequest) input() interactors.AccessReviewInput {
+	return interactors.AccessReviewInput{
+		Name:             r.Name,
+		Description:      r.Description,
+		Roles:            r.Roles,
+		ReviewerStrategy: r.ReviewerStrategy,
+		FallbackReviewer: r.FallbackReviewer,
+		DueAt:            r.DueAt,
+		RevokeOnExpiry:   r.RevokeOnExpiry,
+	}
+}
+
+// accessReviewDecisionRequest adalah body permintaan memutuskan item tinjauan akses.
+type accessReviewDecisionRequest struct {
+	Decision string `json:"decision"` // keep atau revoke
+	Comment  string `json:"comment"`
+}
+
+// CreateCampaign menangani pembuatan kampanye tinjauan akses oleh admin.
+func (h *AccessReviewHandler) CreateCampaign(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(accessReviewRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	campaign, err := h.reviewInteractor.Create(adminID, req.input())
+	if err != nil {
+		return accessReviewErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(campaign)
+}
+
+// ListCampaigns menangani pengambilan semua kampanye tinjauan akses.
+func (h *AccessReviewHandler) ListCampaigns(c *fiber.Ctx) error {
+	campaigns, err := h.reviewInteractor.List()
+	if err != nil {
+		return accessReviewErrorResponse(c, err)
+	}
+	return c.JSON(campaigns)
+}
+
+// GetCampaign menangani pengambilan kampanye beserta seluruh item dan keputusannya.
+func (h *AccessReviewHandler) GetCampaign(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID kampanye tidak valid"})
+	}
+
+	campaign, err := h.reviewInteractor.Get(id)
+	if err != nil {
+		return accessReviewErrorResponse(c, err)
+	}
+	return c.JSON(campaign)
+}
+
+// CloseCampaign menangani penyelesaian kampanye sebelum batas waktunya.
+func (h *AccessReviewHandler) CloseCampaign(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID kampanye tidak valid"})
+	}
+
+	campaign, err := h.reviewInteractor.Close(adminID, id)
+	if err != nil {
+		return accessReviewErrorResponse(c, err)
+	}
+	return c.JSON(campaign)
+}
+
+// ExportCampaign menangani ekspor laporan CSV kampanye. Tanda tangan laporan dikirim di header
+// X-Signature (base64url) dan bisa diverifikasi dengan kunci X-Signature-Key-Id dari /jwks.json.
+func (h *AccessReviewHandler) ExportCampaign(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID kampanye tidak valid"})
+	}
+
+	report, err := h.reviewInteractor.Export(adminID, id)
+	if err != nil {
+		return accessReviewErrorResponse(c, err)
+	}
+
+	c.Set(fiber.HeaderContentType, "text/csv; charset=utf-8")
+	c.Set(fiber.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", report.Filename))
+	c.Set("X-Signature", report.Signature.Value)
+	c.Set("X-Signature-Key-Id", report.Signature.KeyID)
+	c.Set("X-Signature-Algorithm", report.Signature.Algorithm)
+	return c.Send(report.Content)
+}
+
+// ListMyReviewItems menangani pengambilan item tinjauan yang menunggu keputusan pengguna saat ini.
+func (h *AccessReviewHandler) ListMyReviewItems(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	items, err := h.reviewInteractor.ListPending(userID)
+	if err != nil {
+		return accessReviewErrorResponse(c, err)
+	}
+	return c.JSON(items)
+}
+
+// DecideReviewItem menangani keputusan keep atau revoke peninjau atas satu item tinjauan.
+func (h *AccessReviewHandler) DecideReviewItem(c *fiber.Ctx) error {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID item tidak valid"})
+	}
+
+	req := new(accessReviewDecisionRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	item, err := h.reviewInteractor.Decide(reviewerID, id, req.Decision, req.Comment)
+	if err != nil {
+		return accessReviewErrorResponse(c, err)
+	}
+	return c.JSON(item)
+}
+
+// accessReviewErrorResponse memetakan error tinjauan akses ke respons HTTP.
+func accessReviewErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrAccessReviewNotFound),
+		errors.Is(err, interactors.ErrAccessReviewItemNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrAccessReviewNameRequired),
+		errors.Is(err, interactors.ErrAccessReviewStrategyInvalid),
+		errors.Is(err, interactors.ErrAccessReviewDueInvalid),
+		errors.Is(err, interactors.ErrAccessReviewRoleNotFound),
+		errors.Is(err, interactors.ErrAccessReviewReviewerNotFound),
+		errors.Is(err, interactors.ErrAccessReviewEmpty),
+		errors.Is(err, interactors.ErrAccessReviewDecisionInvalid):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrAccessReviewForbidden):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrAccessReviewClosed),
+		errors.Is(err, interactors.ErrAccessReviewItemDecided):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan tinjauan akses di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses tinjauan akses"})
+	}
+}
@@ -0,0 +1,148 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// APIKeyHandler menangani permintaan HTTP untuk API key milik pengguna dan service account.
+type APIKeyHandler struct {
+	apiKeyInteractor *interactors.APIKeyInteractor
+}
+
+// NewAPIKeyHandler membuat instance baru dari APIKeyHandler.
+func NewAPIKeyHandler(ai *interactors.APIKeyInteractor) *APIKeyHandler {
+	return &APIKeyHandler{apiKeyInteractor: ai}
+}
+
+// createAPIKeyRequest adalah body permintaan pembuatan API key.
+type createAPIKeyRequest struct {
+	Name           string    `json:"name"`
+	Scopes         []string  `json:"scopes"`
+	ExpiresInDays  int       `json:"expires_in_days"` // 0 berarti masa berlaku default
+	OrganizationID uuid.UUID `json:"organization_id"` // Kosong berarti kunci tidak terikat organisasi
+}
+
+// createdAPIKeyResponse adalah API key yang baru dibuat beserta kunci plaintext-nya.
+type createdAPIKeyResponse struct {
+	*entities.APIKey
+	Key string `json:"key"` // Hanya ditampilkan sekali
+}
+
+// CreateAPIKey menangani pembuatan API key untuk pengguna yang sedang login.
+func (h *APIKeyHandler) CreateAPIKey(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	return h.create(c, userID)
+}
+
+// ListAPIKeys menangani pengambilan API key milik pengguna yang sedang login.
+func (h *APIKeyHandler) ListAPIKeys(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	return h.list(c, userID)
+}
+
+// RevokeAPIKey menangani pencabutan API key milik pengguna yang sedang login.
+func (h *APIKeyHandler) RevokeAPIKey(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	return h.revoke(c, userID, c.Params("id"))
+}
+
+// CreateUserAPIKey menangani pembuatan API key untuk pengguna atau service account mana pun oleh admin.
+func (h *APIKeyHandler) CreateUserAPIKey(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+	return h.create(c, userID)
+}
+
+// ListUserAPIKeys menangani pengambilan API key milik pengguna mana pun oleh admin.
+func (h *APIKeyHandler) ListUserAPIKeys(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+	return h.list(c, userID)
+}
+
+// RevokeUserAPIKey menangani pencabutan API key milik pengguna mana pun oleh admin.
+func (h *APIKeyHandler) RevokeUserAPIKey(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+	return h.revoke(c, userID, c.Params("keyId"))
+}
+
+// create membuat API key untuk userID dari body permintaan.
+func (h *APIKeyHandler) create(c *fiber.Ctx, userID uuid.UUID) error {
+	req := new(createAPIKeyRequest)
+	if err := c.BodyParser(req); err != nil || req.ExpiresInDays < 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	expiresIn := time.Duration(req.ExpiresInDays) * 24 * time.Hour
+	key, plain, err := h.apiKeyInteractor.Create(userID, req.OrganizationID, req.Name, req.Scopes, expiresIn)
+	if err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(createdAPIKeyResponse{APIKey: key, Key: plain})
+}
+
+// list mengembalikan API key milik userID.
+func (h *APIKeyHandler) list(c *fiber.Ctx, userID uuid.UUID) error {
+	keys, err := h.apiKeyInteractor.List(userID)
+	if err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+	return c.JSON(keys)
+}
+
+// revoke mencabut API key milik userID.
+func (h *APIKeyHandler) revoke(c *fiber.Ctx, userID uuid.UUID, rawID string) error {
+	id, err := uuid.Parse(rawID)
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID API key tidak valid"})
+	}
+
+	if err := h.apiKeyInteractor.Revoke(userID, id); err != nil {
+		return apiKeyErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// apiKeyErrorResponse memetakan error API key ke respons HTTP.
+func apiKeyErrorResponse(c *fiber.Ctx, err error) error {
+	var notGranted *interactors.ScopeNotGrantedError
+
+	switch {
+	case errors.Is(err, interactors.ErrAPIKeyNameRequired),
+		errors.Is(err, interactors.ErrAPIKeyScopesRequired),
+		errors.Is(err, interactors.ErrAPIKeyExpiryTooLong):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.As(err, &notGranted),
+		errors.Is(err, interactors.ErrNotOrganizationMember):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrAPIKeyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan API key di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses API key"})
+	}
+}
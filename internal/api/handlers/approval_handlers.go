@@ -0,0 +1,245 @@
+package handlers
+
+import (
+	"errors"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ApprovalHandler menangani permintaan HTTP untuk kebijakan persetujuan dan permintaan penetapan sensitif.
+type ApprovalHandler struct {
+	approvalInteractor *interactors.ApprovalInteractor
+}
+
+// NewApprovalHandler membuat instance baru dari ApprovalHandler.
+func NewApprovalHandler(ai *interactors.ApprovalInteractor) *ApprovalHandler {
+	return &ApprovalHandler{approvalInteractor: ai}
+}
+
+// approvalPolicyRequest adalah body permintaan membuat atau mengganti kebijakan persetujuan. Role kosong
+// berarti kebijakan status superuser; Role diabaikan saat mengganti kebijakan.
+type approvalPolicyRequest struct {
+	Role              string   `json:"role"`
+	RequiredApprovals int      `json:"required_approvals"`
+	ApproverRoles     []string `json:"approver_roles"`
+}
+
+func (r *approvalPolicyRequest) input() interactors.ApprovalPolicyInput {
+	return interactors.ApprovalPolicyInput{
+		Role:              r.Role,
+		RequiredApprovals: r.RequiredApprovals,
+		ApproverRoles:     r.ApproverRoles,
+	}
+}
+
+// assignmentRequestRequest adalah body permintaan penetapan Role atau status superuser.
+type assignmentRequestRequest struct {
+	UserID    uuid.UUID  `json:"user_id"`
+	Superuser bool       `json:"superuser"`
+	Role      string     `json:"role"`
+	StartsAt  *time.Time `json:"starts_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Hours     int        `json:"hours"`
+	Reason    string     `json:"reason"`
+}
+
+// assignmentDecisionRequest adalah body permintaan menyetujui atau menolak penetapan.
+type assignmentDecisionRequest struct {
+	Comment string `json:"comment"`
+}
+
+// CreatePolicy menangani pembuatan kebijakan persetujuan oleh admin.
+func (h *ApprovalHandler) CreatePolicy(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(approvalPolicyRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	policy, err := h.approvalInteractor.CreatePolicy(adminID, req.input())
+	if err != nil {
+		return approvalErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(policy)
+}
+
+// ListPolicies menangani pengambilan semua kebijakan persetujuan.
+func (h *ApprovalHandler) ListPolicies(c *fiber.Ctx) error {
+	policies, err := h.approvalInteractor.ListPolicies()
+	if err != nil {
+		return approvalErrorResponse(c, err)
+	}
+	return c.JSON(policies)
+}
+
+// UpdatePolicy menangani penggantian jumlah persetujuan dan role peninjau kebijakan.
+func (h *ApprovalHandler) UpdatePolicy(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID kebijakan tidak valid"})
+	}
+
+	req := new(approvalPolicyRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	policy, err := h.approvalInteractor.UpdatePolicy(adminID, id, req.input())
+	if err != nil {
+		return approvalErrorResponse(c, err)
+	}
+	return c.JSON(policy)
+}
+
+// DeletePolicy menangani penghapusan kebijakan persetujuan.
+func (h *ApprovalHandler) DeletePolicy(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID kebijakan tidak valid"})
+	}
+
+	if err := h.approvalInteractor.DeletePolicy(adminID, id); err != nil {
+		return approvalErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// CreateAssignmentRequest menangani pengajuan penetapan role atau status superuser yang memerlukan persetujuan.
+func (h *ApprovalHandler) CreateAssignmentRequest(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(assignmentRequestRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	request, err := h.approvalInteractor.Submit(adminID, interactors.AssignmentInput{
+		UserID:    req.UserID,
+		Superuser: req.Superuser,
+		Grant: interactors.RoleGrantInput{
+			Role:      req.Role,
+			StartsAt:  req.StartsAt,
+			ExpiresAt: req.ExpiresAt,
+			Hours:     req.Hours,
+			Reason:    req.Reason,
+		},
+	})
+	if err != nil {
+		return approvalErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(request)
+}
+
+// ListAssignmentRequests menangani pengambilan permintaan penetapan yang boleh dilihat pengguna,
+// opsional disaring dengan query status.
+func (h *ApprovalHandler) ListAssignmentRequests(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	requests, err := h.approvalInteractor.List(userID, c.Query("status"))
+	if err != nil {
+		return approvalErrorResponse(c, err)
+	}
+	return c.JSON(requests)
+}
+
+// ApproveAssignmentRequest menangani persetujuan permintaan penetapan dengan komentar opsional.
+func (h *ApprovalHandler) ApproveAssignmentRequest(c *fiber.Ctx) error {
+	return h.decide(c, h.approvalInteractor.Approve)
+}
+
+// RejectAssignmentRequest menangani penolakan permintaan penetapan. Komentar wajib diisi.
+func (h *ApprovalHandler) RejectAssignmentRequest(c *fiber.Ctx) error {
+	return h.decide(c, h.approvalInteractor.Reject)
+}
+
+// CancelAssignmentRequest menangani pembatalan permintaan penetapan oleh pengajunya.
+func (h *ApprovalHandler) CancelAssignmentRequest(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID permintaan tidak valid"})
+	}
+
+	if err := h.approvalInteractor.Cancel(userID, id); err != nil {
+		return approvalErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+func (h *ApprovalHandler) decide(c *fiber.Ctx, decision func(reviewerID, id uuid.UUID, comment string) (*entities.AssignmentRequest, error)) error {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID permintaan tidak valid"})
+	}
+
+	req := new(assignmentDecisionRequest)
+	if len(c.Body()) > 0 {
+		if err := c.BodyParser(req); err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+		}
+	}
+
+	request, err := decision(reviewerID, id, req.Comment)
+	if err != nil {
+		return approvalErrorResponse(c, err)
+	}
+	return c.JSON(request)
+}
+
+// approvalErrorResponse memetakan error alur persetujuan ke respons HTTP. Error penetapan role diteruskan
+// ke roleGrantErrorResponse.
+func approvalErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrApprovalPolicyNotFound),
+		errors.Is(err, interactors.ErrAssignmentNotFound),
+		errors.Is(err, interactors.ErrAssignmentUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrApprovalPolicyInvalid),
+		errors.Is(err, interactors.ErrApprovalRoleNotFound),
+		errors.Is(err, interactors.ErrAssignmentCommentRequired),
+		errors.Is(err, interactors.ErrAssignmentReasonRequired),
+		errors.Is(err, interactors.ErrAssignmentWindowUnsupported):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrAssignmentForbidden),
+		errors.Is(err, interactors.ErrAssignmentSelfReview):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrApprovalPolicyExists),
+		errors.Is(err, interactors.ErrAssignmentDuplicate),
+		errors.Is(err, interactors.ErrAssignmentNotPending),
+		errors.Is(err, interactors.ErrAssignmentAlreadyDecided),
+		errors.Is(err, interactors.ErrAssignmentAlreadySuperuser):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return roleGrantErrorResponse(c, err)
+	}
+}
@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AuditHandler menangani permintaan HTTP untuk jejak audit.
+type AuditHandler struct {
+	auditInteractor *interactors.AuditInteractor
+}
+
+// NewAuditHandler membuat instance baru dari AuditHandler.
+func NewAuditHandler(ai *interactors.AuditInteractor) *AuditHandler {
+	return &AuditHandler{auditInteractor: ai}
+}
+
+// ListAuditLogs menangani pengambilan jejak audit, terbaru lebih dulu. Query action, actor_id,
+// target_type, target_id, since dan until (RFC 3339) serta limit menyaring hasilnya.
+func (h *AuditHandler) ListAuditLogs(c *fiber.Ctx) error {
+	filter := repositories.AuditLogFilter{
+		Action:     c.Query("action"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		Limit:      c.QueryInt("limit"),
+	}
+	if value := c.Query("actor_id"); value != "" {
+		actorID, err := uuid.Parse(value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID aktor tidak valid"})
+		}
+		filter.ActorID = &actorID
+	}
+	for key, target := range map[string]**time.Time{"since": &filter.Since, "until": &filter.Until} {
+		value := c.Query(key)
+		if value == "" {
+			continue
+		}
+		parsed, err := time.Parse(time.RFC3339, value)
+		if err != nil {
+			return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Waktu " + key + " tidak valid"})
+		}
+		*target = &parsed
+	}
+
+	entries, err := h.auditInteractor.List(filter)
+	if err != nil {
+		log.Printf("Kesalahan jejak audit di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil jejak audit"})
+	}
+	return c.JSON(entries)
+}
@@ -0,0 +1,233 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"math"
+	"strconv"
+	"time"
+
+	"fiber-usermanagement/internal/domain/services"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// AuthHandler menangani permintaan HTTP terkait autentikasi.
+type AuthHandler struct {
+	authInteractor *interactors.AuthInteractor
+}
+
+// NewAuthHandler membuat instance baru dari AuthHandler.
+func NewAuthHandler(ai *interactors.AuthInteractor) *AuthHandler {
+	return &AuthHandler{authInteractor: ai}
+}
+
+// loginRequest adalah body permintaan login. Username boleh diisi username atau email.
+type loginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+// Login menangani login dengan username/email dan password dari permintaan HTTP POST.
+func (h *AuthHandler) Login(c *fiber.Ctx) error {
+	req := new(loginRequest)
+	if err := c.BodyParser(req); err != nil {
+		log.Printf("Kesalahan BodyParser saat login: %v", err)
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	result, err := h.authInteractor.Login(c.UserContext(), req.Username, req.Password, clientInfo(c))
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+	return loginResponse(c, result)
+}
+
+// mfaChallengeRequest adalah body permintaan langkah MFA saat login.
+type mfaChallengeRequest struct {
+	MFAToken     string `json:"mfa_token"`
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// VerifyMFA menangani langkah kedua login dengan kode TOTP atau kode pemulihan.
+func (h *AuthHandler) VerifyMFA(c *fiber.Ctx) error {
+	req := new(mfaChallengeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	result, err := h.authInteractor.VerifyMFA(c.UserContext(), req.MFAToken, req.Code, req.RecoveryCode, clientInfo(c))
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+	return loginResponse(c, result)
+}
+
+// BeginMFAEnrollment menangani enrollment TOTP bagi pengguna yang diwajibkan MFA saat login.
+func (h *AuthHandler) BeginMFAEnrollment(c *fiber.Ctx) error {
+	req := new(mfaChallengeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	enrollment, err := h.authInteractor.BeginMFAEnrollment(c.UserContext(), req.MFAToken)
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{
+		"secret":           enrollment.Secret,
+		"provisioning_uri": enrollment.ProvisioningURI,
+	})
+}
+
+// ConfirmMFAEnrollment menangani konfirmasi enrollment TOTP saat login lalu menerbitkan token akses.
+func (h *AuthHandler) ConfirmMFAEnrollment(c *fiber.Ctx) error {
+	req := new(mfaChallengeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	result, err := h.authInteractor.ConfirmMFAEnrollment(c.UserContext(), req.MFAToken, req.Code, clientInfo(c))
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+	return loginResponse(c, result)
+}
+
+// passkeyLoginRequest adalah body permintaan penyelesaian login dengan passkey.
+// Credential adalah PublicKeyCredential dari navigator.credentials.get() dalam bentuk JSON.
+type passkeyLoginRequest struct {
+	SessionID  string          `json:"session_id"`
+	MFAToken   string          `json:"mfa_token"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+// BeginPasskeyLogin menangani permulaan login tanpa password dengan passkey.
+func (h *AuthHandler) BeginPasskeyLogin(c *fiber.Ctx) error {
+	assertion, sessionID, err := h.authInteractor.BeginPasskeyLogin(c.UserContext(), c.IP())
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{
+		"session_id": sessionID,
+		"options":    assertion,
+	})
+}
+
+// PasskeyLogin menangani penyelesaian login tanpa password dengan passkey.
+func (h *AuthHandler) PasskeyLogin(c *fiber.Ctx) error {
+	req := new(passkeyLoginRequest)
+	if err := c.BodyParser(req); err != nil || req.SessionID == "" || len(req.Credential) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	result, err := h.authInteractor.LoginWithPasskey(c.UserContext(), req.SessionID, req.Credential, clientInfo(c))
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+	return loginResponse(c, result)
+}
+
+// BeginPasskeyMFA menangani permulaan verifikasi passkey sebagai langkah kedua login.
+func (h *AuthHandler) BeginPasskeyMFA(c *fiber.Ctx) error {
+	req := new(passkeyLoginRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	assertion, err := h.authInteractor.BeginPasskeyMFA(c.UserContext(), req.MFAToken)
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"options": assertion})
+}
+
+// VerifyPasskeyMFA menangani langkah kedua login dengan passkey.
+func (h *AuthHandler) VerifyPasskeyMFA(c *fiber.Ctx) error {
+	req := new(passkeyLoginRequest)
+	if err := c.BodyParser(req); err != nil || len(req.Credential) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	result, err := h.authInteractor.VerifyPasskeyMFA(c.UserContext(), req.MFAToken, req.Credential, clientInfo(c))
+	if err != nil {
+		return loginErrorResponse(c, err)
+	}
+	return loginResponse(c, result)
+}
+
+// UnlockUser menangani pembukaan kunci akun oleh admin dari permintaan HTTP POST.
+func (h *AuthHandler) UnlockUser(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	user, err := h.authInteractor.UnlockAccount(c.UserContext(), id)
+	if err != nil {
+		log.Printf("Kesalahan UnlockUser di handler: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Pengguna tidak ditemukan"})
+	}
+	return c.JSON(user)
+}
+
+// loginResponse menulis token akses, atau token tantangan jika login belum selesai.
+func loginResponse(c *fiber.Ctx, result *interactors.LoginResult) error {
+	if result.ChallengeToken != nil {
+		response := fiber.Map{
+			"mfa_required":            result.Challenge == services.ChallengeMFA,
+			"mfa_enrollment_required": result.Challenge == services.ChallengeMFAEnrollment,
+			"mfa_token":               result.ChallengeToken.Token,
+			"expires_in":              int(time.Until(result.ChallengeToken.ExpiresAt).Seconds()),
+		}
+		if len(result.MFAMethods) > 0 {
+			response["mfa_methods"] = result.MFAMethods
+		}
+		return c.JSON(response)
+	}
+
+	response := fiber.Map{
+		"access_token": result.Token.Token,
+		"token_type":   "Bearer",
+		"expires_in":   int(time.Until(result.Token.ExpiresAt).Seconds()),
+	}
+	if len(result.RecoveryCodes) > 0 {
+		response["recovery_codes"] = result.RecoveryCodes
+	}
+	return c.JSON(response)
+}
+
+// loginErrorResponse memetakan error dari proses login ke respons HTTP.
+func loginErrorResponse(c *fiber.Ctx, err error) error {
+	var locked *interactors.AccountLockedError
+	var throttled *interactors.LoginThrottledError
+
+	switch {
+	case errors.Is(err, interactors.ErrInvalidCredentials):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrAccountInactive):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrInvalidChallenge),
+		errors.Is(err, interactors.ErrInvalidMFACode),
+		errors.Is(err, interactors.ErrPasskeyVerificationFailed),
+		errors.Is(err, interactors.ErrPasskeyCeremonyExpired):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrMFAAlreadyEnabled),
+		errors.Is(err, interactors.ErrMFANotEnabled),
+		errors.Is(err, interactors.ErrMFAEnrollmentNotStarted),
+		errors.Is(err, interactors.ErrPasskeyNotRegistered):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.As(err, &locked):
+		return c.Status(fiber.StatusLocked).JSON(fiber.Map{"error": "Akun dikunci sementara", "locked_until": locked.Until})
+	case errors.As(err, &throttled):
+		c.Set(fiber.HeaderRetryAfter, strconv.Itoa(int(math.Ceil(throttled.RetryAfter.Seconds()))))
+		return c.Status(fiber.StatusTooManyRequests).JSON(fiber.Map{"error": "Terlalu banyak percobaan login, coba lagi nanti"})
+	default:
+		log.Printf("Kesalahan Login di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses login"})
+	}
+}
@@ -0,0 +1,25 @@
+package handlers
+
+import (
+	"fiber-usermanagement/internal/api/middlewares"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// currentUserID mengambil ID pengguna yang sudah diautentikasi oleh AuthMiddleware.
+func currentUserID(c *fiber.Ctx) (uuid.UUID, bool) {
+	id, ok := c.Locals(middlewares.LocalsUserID).(uuid.UUID)
+	return id, ok
+}
+
+// currentOrganizationID mengambil ID organisasi yang sudah ditetapkan oleh TenantResolver.
+func currentOrganizationID(c *fiber.Ctx) (uuid.UUID, bool) {
+	return middlewares.OrganizationFromContext(c)
+}
+
+// clientInfo mengambil IP dan User-Agent klien untuk pencatatan sesi login.
+func clientInfo(c *fiber.Ctx) interactors.ClientInfo {
+	return interactors.ClientInfo{IP: c.IP(), UserAgent: c.Get(fiber.HeaderUserAgent)}
+}
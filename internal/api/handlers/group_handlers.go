@@ -0,0 +1,257 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// GroupHandler menangani permintaan HTTP untuk grup pengguna, subgrup dan Role grup.
+type GroupHandler struct {
+	groupInteractor *interactors.GroupInteractor
+}
+
+// NewGroupHandler membuat instance baru dari GroupHandler.
+func NewGroupHandler(gi *interactors.GroupInteractor) *GroupHandler {
+	return &GroupHandler{groupInteractor: gi}
+}
+
+// groupRequest adalah body permintaan membuat atau memperbarui grup.
+type groupRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// groupMemberRequest adalah body permintaan menambah anggota grup.
+type groupMemberRequest struct {
+	UserID uuid.UUID `json:"user_id"`
+}
+
+// subgroupRequest adalah body permintaan menambah subgrup.
+type subgroupRequest struct {
+	GroupID uuid.UUID `json:"group_id"`
+}
+
+// groupRolesRequest adalah body permintaan mengganti Role grup.
+type groupRolesRequest struct {
+	Roles []string `json:"roles"`
+}
+
+// CreateGroup menangani pembuatan grup oleh admin.
+func (h *GroupHandler) CreateGroup(c *fiber.Ctx) error {
+	req := new(groupRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	group, err := h.groupInteractor.Create(req.Name, req.Description)
+	if err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(group)
+}
+
+// ListGroups menangani pengambilan semua grup oleh admin.
+func (h *GroupHandler) ListGroups(c *fiber.Ctx) error {
+	groups, err := h.groupInteractor.List()
+	if err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.JSON(groups)
+}
+
+// GetGroup menangani pengambilan satu grup beserta anggota, subgrup dan Role-nya.
+func (h *GroupHandler) GetGroup(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID grup tidak valid"})
+	}
+
+	group, err := h.groupInteractor.Get(id)
+	if err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.JSON(group)
+}
+
+// UpdateGroup menangani perubahan nama dan deskripsi grup.
+func (h *GroupHandler) UpdateGroup(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID grup tidak valid"})
+	}
+
+	req := new(groupRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	group, err := h.groupInteractor.Update(id, req.Name, req.Description)
+	if err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.JSON(group)
+}
+
+// DeleteGroup menangani penghapusan grup.
+func (h *GroupHandler) DeleteGroup(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID grup tidak valid"})
+	}
+
+	if err := h.groupInteractor.Delete(id); err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// AddMember menangani penambahan pengguna ke grup.
+func (h *GroupHandler) AddMember(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID grup tidak valid"})
+	}
+
+	req := new(groupMemberRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	group, err := h.groupInteractor.AddMember(id, req.UserID)
+	if err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.JSON(group)
+}
+
+// RemoveMember menangani pengeluaran pengguna dari grup.
+func (h *GroupHandler) RemoveMember(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID grup tidak valid"})
+	}
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	if err := h.groupInteractor.RemoveMember(id, userID); err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// AddSubgroup menangani penambahan subgrup ke grup.
+func (h *GroupHandler) AddSubgroup(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID grup tidak valid"})
+	}
+
+	req := new(subgroupRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	group, err := h.groupInteractor.AddSubgroup(id, req.GroupID)
+	if err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.JSON(group)
+}
+
+// RemoveSubgroup menangani pelepasan subgrup dari grup.
+func (h *GroupHandler) RemoveSubgroup(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID grup tidak valid"})
+	}
+	subgroupID, err := uuid.Parse(c.Params("subgroupId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID subgrup tidak valid"})
+	}
+
+	if err := h.groupInteractor.RemoveSubgroup(id, subgroupID); err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// SetRoles menangani penggantian Role yang diberikan ke grup.
+func (h *GroupHandler) SetRoles(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID grup tidak valid"})
+	}
+
+	req := new(groupRolesRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	group, err := h.groupInteractor.SetRoles(id, req.Roles)
+	if err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.JSON(group)
+}
+
+// GetUserPermissions menangani pengambilan permission efektif pengguna, termasuk yang diterima lewat grup.
+func (h *GroupHandler) GetUserPermissions(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	permissions, err := h.groupInteractor.EffectivePermissions(userID)
+	if err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"permissions": permissions})
+}
+
+// ExplainUserPermission menangani penjelasan dari mana pengguna menerima sebuah permission.
+func (h *GroupHandler) ExplainUserPermission(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+	permission := c.Query("permission")
+	if permission == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Parameter permission wajib diisi"})
+	}
+
+	explanation, err := h.groupInteractor.Explain(userID, permission)
+	if err != nil {
+		return groupErrorResponse(c, err)
+	}
+	return c.JSON(explanation)
+}
+
+// groupErrorResponse memetakan error grup ke respons HTTP.
+func groupErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrGroupNotFound),
+		errors.Is(err, interactors.ErrGroupMemberNotFound),
+		errors.Is(err, interactors.ErrSubgroupNotFound),
+		errors.Is(err, interactors.ErrGroupUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrGroupNameRequired),
+		errors.Is(err, interactors.ErrGroupRoleNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrGroupNameTaken),
+		errors.Is(err, interactors.ErrGroupCycle),
+		errors.Is(err, interactors.ErrSoDViolation):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrRoleGrantApprovalRequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan grup di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses grup"})
+	}
+}
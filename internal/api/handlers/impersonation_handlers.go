@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"fiber-usermanagement/internal/api/middlewares"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// ImpersonationHandler menangani permintaan HTTP untuk impersonasi pengguna oleh superuser.
+type ImpersonationHandler struct {
+	impersonationInteractor *interactors.ImpersonationInteractor
+}
+
+// NewImpersonationHandler membuat instance baru dari ImpersonationHandler.
+func NewImpersonationHandler(ii *interactors.ImpersonationInteractor) *ImpersonationHandler {
+	return &ImpersonationHandler{impersonationInteractor: ii}
+}
+
+// impersonationRequest adalah body permintaan memulai impersonasi.
+type impersonationRequest struct {
+	Reason  string `json:"reason"`
+	Minutes int    `json:"minutes"` // Opsional; nol berarti batas maksimum dari konfigurasi
+}
+
+func (r *impersonationRequest) input() interactors.ImpersonationInput {
+	return interactors.ImpersonationInput{Reason: r.Reason, Minutes: r.Minutes}
+}
+
+// StartImpersonation menangani dimulainya sesi impersonasi dan mengembalikan token akses pengguna target.
+func (h *ImpersonationHandler) StartImpersonation(c *fiber.Ctx) error {
+	actorID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	subjectID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	req := new(impersonationRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	result, err := h.impersonationInteractor.Start(c.UserContext(), actorID, subjectID, req.input(), clientInfo(c))
+	if err != nil {
+		return impersonationErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(fiber.Map{
+		"access_token": result.Token.Token,
+		"token_type":   "Bearer",
+		"expires_in":   int(time.Until(result.Token.ExpiresAt).Seconds()),
+		"expires_at":   result.Token.ExpiresAt,
+		"session_id":   result.SessionID,
+		"subject":      fiber.Map{"id": result.Subject.ID, "username": result.Subject.Username},
+		"banner":       result.Banner,
+	})
+}
+
+// GetImpersonation menangani pengambilan keterangan impersonasi dari token yang sedang dipakai,
+// untuk ditampilkan sebagai penanda oleh aplikasi.
+func (h *ImpersonationHandler) GetImpersonation(c *fiber.Ctx) error {
+	claims := middlewares.ClaimsFromContext(c)
+	if claims == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	if !claims.IsImpersonation() {
+		return impersonationErrorResponse(c, interactors.ErrNotImpersonating)
+	}
+	return c.JSON(fiber.Map{
+		"actor":      fiber.Map{"id": claims.ActorID, "username": claims.ActorUsername},
+		"subject":    fiber.Map{"id": claims.UserID, "username": claims.Username},
+		"banner":     claims.Banner,
+		"expires_at": claims.ExpiresAt,
+	})
+}
+
+// EndImpersonation menangani pengakhiran sesi impersonasi sebelum batas waktunya.
+func (h *ImpersonationHandler) EndImpersonation(c *fiber.Ctx) error {
+	claims := middlewares.ClaimsFromContext(c)
+	if claims == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	if err := h.impersonationInteractor.End(c.UserContext(), claims); err != nil {
+		return impersonationErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// impersonationErrorResponse memetakan error impersonasi ke respons HTTP.
+func impersonationErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrImpersonationUserNotFound),
+		errors.Is(err, interactors.ErrNotImpersonating),
+		errors.Is(err, interactors.ErrSessionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrImpersonationReasonRequired),
+		errors.Is(err, interactors.ErrImpersonationDurationInvalid):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrImpersonationForbidden),
+		errors.Is(err, interactors.ErrImpersonationTargetProtected):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrImpersonationSelf),
+		errors.Is(err, interactors.ErrImpersonationTargetInactive):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan impersonasi di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses impersonasi"})
+	}
+}
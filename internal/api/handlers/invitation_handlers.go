@@ -0,0 +1,184 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// InvitationHandler menangani permintaan HTTP untuk undangan organisasi.
+type InvitationHandler struct {
+	invitationInteractor *interactors.InvitationInteractor
+}
+
+// NewInvitationHandler membuat instance baru dari InvitationHandler.
+func NewInvitationHandler(ii *interactors.InvitationInteractor) *InvitationHandler {
+	return &InvitationHandler{invitationInteractor: ii}
+}
+
+// inviteRequest adalah body permintaan membuat undangan.
+type inviteRequest struct {
+	Email string   `json:"email"`
+	Roles []string `json:"roles"`
+}
+
+// acceptInvitationRequest adalah body permintaan menerima undangan.
+// Data akun hanya dipakai saat undangan diterima tanpa login untuk membuat akun baru.
+type acceptInvitationRequest struct {
+	Token     string `json:"token"`
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
+// CreateInvitation menangani pembuatan undangan ke organisasi aktif.
+func (h *InvitationHandler) CreateInvitation(c *fiber.Ctx) error {
+	organizationID, ok := currentOrganizationID(c)
+	if !ok {
+		return organizationRequired(c)
+	}
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(inviteRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	invitation, err := h.invitationInteractor.Invite(organizationID, userID, req.Email, req.Roles)
+	if err != nil {
+		return invitationErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(invitation)
+}
+
+// ListInvitations menangani pengambilan undangan organisasi aktif yang masih menunggu.
+func (h *InvitationHandler) ListInvitations(c *fiber.Ctx) error {
+	organizationID, ok := currentOrganizationID(c)
+	if !ok {
+		return organizationRequired(c)
+	}
+
+	invitations, err := h.invitationInteractor.ListPending(organizationID)
+	if err != nil {
+		return invitationErrorResponse(c, err)
+	}
+	return c.JSON(invitations)
+}
+
+// ResendInvitation menangani pengiriman ulang undangan dengan tautan baru.
+func (h *InvitationHandler) ResendInvitation(c *fiber.Ctx) error {
+	organizationID, ok := currentOrganizationID(c)
+	if !ok {
+		return organizationRequired(c)
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID undangan tidak valid"})
+	}
+
+	invitation, err := h.invitationInteractor.Resend(organizationID, id)
+	if err != nil {
+		return invitationErrorResponse(c, err)
+	}
+	return c.JSON(invitation)
+}
+
+// RevokeInvitation menangani pencabutan undangan yang belum diterima.
+func (h *InvitationHandler) RevokeInvitation(c *fiber.Ctx) error {
+	organizationID, ok := currentOrganizationID(c)
+	if !ok {
+		return organizationRequired(c)
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID undangan tidak valid"})
+	}
+
+	if err := h.invitationInteractor.Revoke(organizationID, id); err != nil {
+		return invitationErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// PreviewInvitation menangani pengambilan isi undangan dari token tautannya sebelum diterima.
+func (h *InvitationHandler) PreviewInvitation(c *fiber.Ctx) error {
+	preview, err := h.invitationInteractor.Preview(c.Query("token"))
+	if err != nil {
+		return invitationErrorResponse(c, err)
+	}
+	return c.JSON(preview)
+}
+
+// AcceptInvitation menangani penerimaan undangan sekaligus pembuatan akun baru untuk email undangan.
+func (h *InvitationHandler) AcceptInvitation(c *fiber.Ctx) error {
+	req := new(acceptInvitationRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	member, err := h.invitationInteractor.AcceptWithNewAccount(req.Token, interactors.NewAccount{
+		Username:  req.Username,
+		Password:  req.Password,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+	})
+	if err != nil {
+		return invitationErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(member)
+}
+
+// AcceptMyInvitation menangani penerimaan undangan oleh pengguna yang sedang login.
+func (h *InvitationHandler) AcceptMyInvitation(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(acceptInvitationRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	member, err := h.invitationInteractor.Accept(req.Token, userID)
+	if err != nil {
+		return invitationErrorResponse(c, err)
+	}
+	return c.JSON(member)
+}
+
+// invitationErrorResponse memetakan error undangan ke respons HTTP. Error organisasi diteruskan ke organizationErrorResponse.
+func invitationErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrInvitationNotFound),
+		errors.Is(err, interactors.ErrInvalidInvitation):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrInvitationEmailInvalid),
+		errors.Is(err, interactors.ErrInvitationAccountRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrInvitationEmailMismatch):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrInvitationPending),
+		errors.Is(err, interactors.ErrInvitationLoginRequired),
+		errors.Is(err, interactors.ErrInvitationUsernameTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrOrganizationNotFound),
+		errors.Is(err, interactors.ErrOrganizationRoleNotFound),
+		errors.Is(err, interactors.ErrOrganizationMemberExists),
+		errors.Is(err, interactors.ErrRoleGrantApprovalRequired),
+		errors.Is(err, interactors.ErrOrganizationRoleNotAssignable),
+		errors.Is(err, interactors.ErrSoDViolation):
+		return organizationErrorResponse(c, err)
+	default:
+		log.Printf("Kesalahan undangan di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses undangan"})
+	}
+}
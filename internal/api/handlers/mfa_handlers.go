@@ -0,0 +1,117 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// MFAHandler menangani permintaan HTTP untuk pengelolaan MFA oleh pengguna yang sudah login.
+type MFAHandler struct {
+	mfaInteractor *interactors.MFAInteractor
+}
+
+// NewMFAHandler membuat instance baru dari MFAHandler.
+func NewMFAHandler(mi *interactors.MFAInteractor) *MFAHandler {
+	return &MFAHandler{mfaInteractor: mi}
+}
+
+// mfaCodeRequest adalah body permintaan yang membawa kode TOTP atau kode pemulihan.
+type mfaCodeRequest struct {
+	Code         string `json:"code"`
+	RecoveryCode string `json:"recovery_code"`
+}
+
+// BeginTOTPEnrollment menangani pembuatan secret TOTP dan URI provisioning untuk QR code.
+func (h *MFAHandler) BeginTOTPEnrollment(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	enrollment, err := h.mfaInteractor.BeginTOTPEnrollment(userID)
+	if err != nil {
+		return mfaErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{
+		"secret":           enrollment.Secret,
+		"provisioning_uri": enrollment.ProvisioningURI,
+	})
+}
+
+// ConfirmTOTPEnrollment menangani konfirmasi enrollment dengan kode dari aplikasi authenticator.
+// Kode pemulihan hanya ditampilkan sekali di respons ini.
+func (h *MFAHandler) ConfirmTOTPEnrollment(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(mfaCodeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	codes, err := h.mfaInteractor.ConfirmTOTPEnrollment(c.UserContext(), userID, req.Code)
+	if err != nil {
+		return mfaErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"recovery_codes": codes})
+}
+
+// DisableTOTP menangani penonaktifan MFA dengan verifikasi kode TOTP atau kode pemulihan.
+func (h *MFAHandler) DisableTOTP(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(mfaCodeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	if err := h.mfaInteractor.DisableTOTP(c.UserContext(), userID, req.Code, req.RecoveryCode); err != nil {
+		return mfaErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// RegenerateRecoveryCodes menangani pembuatan ulang kode pemulihan.
+func (h *MFAHandler) RegenerateRecoveryCodes(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(mfaCodeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	codes, err := h.mfaInteractor.RegenerateRecoveryCodes(c.UserContext(), userID, req.Code)
+	if err != nil {
+		return mfaErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"recovery_codes": codes})
+}
+
+// mfaErrorResponse memetakan error MFA ke respons HTTP.
+func mfaErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrInvalidMFACode):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrMFAAlreadyEnabled),
+		errors.Is(err, interactors.ErrMFANotEnabled),
+		errors.Is(err, interactors.ErrMFAEnrollmentNotStarted):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrMFARequired):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan MFA di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses MFA"})
+	}
+}
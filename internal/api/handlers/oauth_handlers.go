@@ -0,0 +1,390 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// OAuthHandler menangani permintaan HTTP authorization server OAuth 2.0.
+type OAuthHandler struct {
+	oauthInteractor *interactors.OAuthInteractor
+	tokenInteractor *interactors.TokenInteractor
+}
+
+// NewOAuthHandler membuat instance baru dari OAuthHandler.
+func NewOAuthHandler(oi *interactors.OAuthInteractor, ti *interactors.TokenInteractor) *OAuthHandler {
+	return &OAuthHandler{oauthInteractor: oi, tokenInteractor: ti}
+}
+
+// registerClientRequest adalah body permintaan pendaftaran klien OAuth.
+type registerClientRequest struct {
+	Name         string   `json:"name"`
+	RedirectURIs []string `json:"redirect_uris"`
+	GrantTypes   []string `json:"grant_types"`
+	Scopes       []string `json:"scopes"`
+	Confidential bool     `json:"confidential"`
+	// Introspection mengizinkan klien (biasanya resource server) memeriksa dan mencabut token milik klien lain
+	Introspection bool `json:"introspection"`
+}
+
+// registeredClientResponse adalah klien yang baru didaftarkan beserta secret plaintext-nya.
+type registeredClientResponse struct {
+	*entities.OAuthClient
+	ClientSecret string `json:"client_secret,omitempty"` // Hanya ditampilkan sekali
+}
+
+// authorizeRequest adalah parameter permintaan otorisasi, dari query string (GET) atau body (POST).
+type authorizeRequest struct {
+	ResponseType        string `json:"response_type" query:"response_type" form:"response_type"`
+	ClientID            string `json:"client_id" query:"client_id" form:"client_id"`
+	RedirectURI         string `json:"redirect_uri" query:"redirect_uri" form:"redirect_uri"`
+	Scope               string `json:"scope" query:"scope" form:"scope"`
+	State               string `json:"state" query:"state" form:"state"`
+	CodeChallenge       string `json:"code_challenge" query:"code_challenge" form:"code_challenge"`
+	CodeChallengeMethod string `json:"code_challenge_method" query:"code_challenge_method" form:"code_challenge_method"`
+	Nonce               string `json:"nonce" query:"nonce" form:"nonce"`
+	Approve             bool   `json:"approve" form:"approve"` // Keputusan pengguna di layar persetujuan
+}
+
+func (r *authorizeRequest) toInteractor() interactors.AuthorizeRequest {
+	return interactors.AuthorizeRequest{
+		ResponseType:        r.ResponseType,
+		ClientID:            r.ClientID,
+		RedirectURI:         r.RedirectURI,
+		Scope:               r.Scope,
+		State:               r.State,
+		CodeChallenge:       r.CodeChallenge,
+		CodeChallengeMethod: r.CodeChallengeMethod,
+		Nonce:               r.Nonce,
+	}
+}
+
+// RegisterClient menangani pendaftaran klien OAuth oleh admin.
+func (h *OAuthHandler) RegisterClient(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(registerClientRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	client, secret, err := h.oauthInteractor.RegisterClient(userID, interactors.OAuthClientRegistration{
+		Name:          req.Name,
+		RedirectURIs:  req.RedirectURIs,
+		GrantTypes:    req.GrantTypes,
+		Scopes:        req.Scopes,
+		Confidential:  req.Confidential,
+		Introspection: req.Introspection,
+	})
+	if err != nil {
+		return oauthClientErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(registeredClientResponse{OAuthClient: client, ClientSecret: secret})
+}
+
+// ListClients menangani pengambilan semua klien OAuth oleh admin.
+func (h *OAuthHandler) ListClients(c *fiber.Ctx) error {
+	clients, err := h.oauthInteractor.ListClients()
+	if err != nil {
+		return oauthClientErrorResponse(c, err)
+	}
+	return c.JSON(clients)
+}
+
+// DeleteClient menangani penghapusan klien OAuth oleh admin.
+func (h *OAuthHandler) DeleteClient(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID klien tidak valid"})
+	}
+
+	if err := h.oauthInteractor.DeleteClient(id); err != nil {
+		return oauthClientErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// Authorize menangani permintaan otorisasi dari halaman consent frontend.
+// Jika pengguna sudah pernah menyetujui scope yang diminta, respons langsung berisi redirect_to;
+// jika belum, respons berisi data klien dan scope untuk ditampilkan di layar persetujuan.
+func (h *OAuthHandler) Authorize(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(authorizeRequest)
+	if err := c.QueryParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	prompt, err := h.oauthInteractor.PrepareAuthorization(c.UserContext(), userID, req.toInteractor())
+	if err != nil {
+		return authorizeErrorResponse(c, err)
+	}
+	if !prompt.ConsentRequired {
+		return c.JSON(fiber.Map{"redirect_to": prompt.RedirectURL})
+	}
+	return c.JSON(fiber.Map{
+		"consent_required": true,
+		"client":           fiber.Map{"client_id": prompt.Client.ClientID, "name": prompt.Client.Name},
+		"scopes":           prompt.Scopes,
+	})
+}
+
+// Consent menangani keputusan pengguna di layar persetujuan dan mengembalikan alamat redirect ke klien.
+func (h *OAuthHandler) Consent(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(authorizeRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	redirectURL, err := h.oauthInteractor.Authorize(c.UserContext(), userID, req.toInteractor(), req.Approve)
+	if err != nil {
+		return authorizeErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"redirect_to": redirectURL})
+}
+
+// Token menangani token endpoint OAuth 2.0. Body berformat application/x-www-form-urlencoded dan
+// klien diautentikasi dengan HTTP Basic atau client_id/client_secret di body (RFC 6749 bagian 2.3.1).
+func (h *OAuthHandler) Token(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+	c.Set(fiber.HeaderPragma, "no-cache")
+
+	req := interactors.TokenRequest{
+		GrantType:    c.FormValue("grant_type"),
+		Code:         c.FormValue("code"),
+		RedirectURI:  c.FormValue("redirect_uri"),
+		CodeVerifier: c.FormValue("code_verifier"),
+		RefreshToken: c.FormValue("refresh_token"),
+		Scope:        c.FormValue("scope"),
+	}
+
+	clientID, clientSecret, basicAuth, err := clientCredentials(c)
+	if err != nil {
+		return oauthErrorResponse(c, err, basicAuth)
+	}
+	req.ClientID, req.ClientSecret = clientID, clientSecret
+	if req.GrantType == "" {
+		return oauthErrorResponse(c, &interactors.OAuthError{Code: interactors.OAuthErrInvalidRequest, Description: "grant_type wajib diisi"}, basicAuth)
+	}
+
+	tokens, err := h.oauthInteractor.Token(c.UserContext(), req, clientInfo(c))
+	if err != nil {
+		return oauthErrorResponse(c, err, basicAuth)
+	}
+
+	response := fiber.Map{
+		"access_token": tokens.AccessToken.Token,
+		"token_type":   "Bearer",
+		"expires_in":   int(time.Until(tokens.AccessToken.ExpiresAt).Seconds()),
+		"scope":        strings.Join(tokens.Scopes, " "),
+	}
+	if tokens.RefreshToken != "" {
+		response["refresh_token"] = tokens.RefreshToken
+	}
+	if tokens.IDToken != "" {
+		response["id_token"] = tokens.IDToken
+	}
+	return c.JSON(response)
+}
+
+// Introspect menangani introspeksi token (RFC 7662) untuk resource server. Hanya klien confidential
+// yang boleh memanggilnya; token yang tidak aktif atau bukan milik klien dilaporkan {"active": false}.
+func (h *OAuthHandler) Introspect(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "no-store")
+
+	client, basicAuth, err := h.authenticateClient(c)
+	if err != nil {
+		return oauthErrorResponse(c, err, basicAuth)
+	}
+	if !client.Confidential {
+		return oauthErrorResponse(c, &interactors.OAuthError{Code: interactors.OAuthErrUnauthorizedClient, Description: "introspeksi hanya untuk klien confidential"}, basicAuth)
+	}
+
+	token := c.FormValue("token")
+	if token == "" {
+		return oauthErrorResponse(c, &interactors.OAuthError{Code: interactors.OAuthErrInvalidRequest, Description: "token wajib diisi"}, basicAuth)
+	}
+
+	result, err := h.tokenInteractor.Introspect(c.UserContext(), client, token)
+	if err != nil {
+		return oauthErrorResponse(c, err, basicAuth)
+	}
+	if !result.Active {
+		return c.JSON(fiber.Map{"active": false})
+	}
+
+	response := fiber.Map{
+		"active":     true,
+		"token_type": result.TokenType,
+		"sub":        result.Subject.String(),
+		"iat":        result.IssuedAt.Unix(),
+		"jti":        result.TokenID,
+	}
+	if result.Scopes != nil {
+		response["scope"] = strings.Join(result.Scopes, " ")
+	}
+	if result.ClientID != "" {
+		response["client_id"] = result.ClientID
+	}
+	if result.Username != "" {
+		response["username"] = result.Username
+	}
+	if !result.ExpiresAt.IsZero() {
+		response["exp"] = result.ExpiresAt.Unix()
+	}
+	return c.JSON(response)
+}
+
+// Revoke menangani pencabutan token (RFC 7009). Token yang tidak dikenal atau sudah tidak aktif
+// tetap dijawab 200 agar klien tidak bisa menebak token yang valid.
+func (h *OAuthHandler) Revoke(c *fiber.Ctx) error {
+	client, basicAuth, err := h.authenticateClient(c)
+	if err != nil {
+		return oauthErrorResponse(c, err, basicAuth)
+	}
+
+	token := c.FormValue("token")
+	if token == "" {
+		return oauthErrorResponse(c, &interactors.OAuthError{Code: interactors.OAuthErrInvalidRequest, Description: "token wajib diisi"}, basicAuth)
+	}
+
+	if err := h.tokenInteractor.Revoke(c.UserContext(), client, token); err != nil {
+		return oauthErrorResponse(c, err, basicAuth)
+	}
+	return c.SendStatus(fiber.StatusOK)
+}
+
+// authenticateClient mengautentikasi klien pemanggil endpoint introspeksi dan pencabutan.
+func (h *OAuthHandler) authenticateClient(c *fiber.Ctx) (*entities.OAuthClient, bool, error) {
+	clientID, clientSecret, basicAuth, err := clientCredentials(c)
+	if err != nil {
+		return nil, basicAuth, err
+	}
+	client, err := h.oauthInteractor.AuthenticateClient(clientID, clientSecret)
+	return client, basicAuth, err
+}
+
+// clientCredentials mengambil kredensial klien dari HTTP Basic atau client_id/client_secret di body
+// (RFC 6749 bagian 2.3.1). Nilai bool menandai kredensial dari header Basic.
+func clientCredentials(c *fiber.Ctx) (string, string, bool, error) {
+	clientID, clientSecret := c.FormValue("client_id"), c.FormValue("client_secret")
+
+	id, secret, ok := clientBasicAuth(c.Get(fiber.HeaderAuthorization))
+	if !ok {
+		return clientID, clientSecret, false, nil
+	}
+	if clientSecret != "" || (clientID != "" && clientID != id) {
+		return "", "", true, &interactors.OAuthError{Code: interactors.OAuthErrInvalidRequest, Description: "gunakan satu metode autentikasi klien"}
+	}
+	return id, secret, true, nil
+}
+
+// clientBasicAuth mengambil kredensial klien dari header Authorization skema Basic.
+// client_id dan client_secret di-encode dengan application/x-www-form-urlencoded sebelum base64.
+func clientBasicAuth(header string) (string, string, bool) {
+	scheme, encoded, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Basic") {
+		return "", "", false
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.TrimSpace(encoded))
+	if err != nil {
+		return "", "", false
+	}
+	rawID, rawSecret, found := strings.Cut(string(decoded), ":")
+	if !found {
+		return "", "", false
+	}
+	id, err := url.QueryUnescape(rawID)
+	if err != nil {
+		return "", "", false
+	}
+	secret, err := url.QueryUnescape(rawSecret)
+	if err != nil {
+		return "", "", false
+	}
+	return id, secret, true
+}
+
+// oauthErrorResponse menulis error token, introspeksi dan pencabutan dalam format RFC 6749 bagian 5.2.
+func oauthErrorResponse(c *fiber.Ctx, err error, basicAuth bool) error {
+	var oauthErr *interactors.OAuthError
+	if !errors.As(err, &oauthErr) {
+		log.Printf("Kesalahan token endpoint OAuth: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "server_error"})
+	}
+
+	status := fiber.StatusBadRequest
+	if oauthErr.Code == interactors.OAuthErrInvalidClient {
+		status = fiber.StatusUnauthorized
+		if basicAuth {
+			c.Set(fiber.HeaderWWWAuthenticate, `Basic realm="oauth"`)
+		}
+	}
+	return c.Status(status).JSON(fiber.Map{"error": oauthErr.Code, "error_description": oauthErr.Description})
+}
+
+// authorizeErrorResponse memetakan error otorisasi. Error yang aman dikirim ke klien dikembalikan
+// sebagai redirect_to; error klien atau redirect_uri ditampilkan ke pengguna tanpa redirect.
+func authorizeErrorResponse(c *fiber.Ctx, err error) error {
+	var oauthErr *interactors.OAuthError
+	if errors.As(err, &oauthErr) {
+		if redirectURL, ok := oauthErr.RedirectURL(); ok {
+			return c.JSON(fiber.Map{"redirect_to": redirectURL})
+		}
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": oauthErr.Code, "error_description": oauthErr.Description})
+	}
+
+	switch {
+	case errors.Is(err, interactors.ErrOAuthClientNotFound),
+		errors.Is(err, interactors.ErrInvalidRedirectURI):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan otorisasi OAuth di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses otorisasi"})
+	}
+}
+
+// oauthClientErrorResponse memetakan error pengelolaan klien OAuth ke respons HTTP.
+func oauthClientErrorResponse(c *fiber.Ctx, err error) error {
+	var unknown *interactors.UnknownScopeError
+	var oauthErr *interactors.OAuthError
+
+	switch {
+	case errors.Is(err, interactors.ErrOAuthClientNameRequired),
+		errors.Is(err, interactors.ErrOAuthScopesRequired),
+		errors.Is(err, interactors.ErrOAuthRedirectURIRequired),
+		errors.Is(err, interactors.ErrOAuthPublicClientCredentials),
+		errors.Is(err, interactors.ErrOAuthPublicClientIntrospection),
+		errors.Is(err, interactors.ErrInvalidRedirectURI),
+		errors.As(err, &unknown):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.As(err, &oauthErr):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": oauthErr.Description})
+	case errors.Is(err, interactors.ErrOAuthClientNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan klien OAuth di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses klien OAuth"})
+	}
+}
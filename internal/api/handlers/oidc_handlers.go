@@ -0,0 +1,58 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/api/middlewares"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// OIDCHandler menangani endpoint publik OpenID Provider.
+type OIDCHandler struct {
+	oidcInteractor *interactors.OIDCInteractor
+}
+
+// NewOIDCHandler membuat instance baru dari OIDCHandler.
+func NewOIDCHandler(oi *interactors.OIDCInteractor) *OIDCHandler {
+	return &OIDCHandler{oidcInteractor: oi}
+}
+
+// Discovery menangani dokumen discovery OpenID Connect.
+func (h *OIDCHandler) Discovery(c *fiber.Ctx) error {
+	c.Set(fiber.HeaderCacheControl, "public, max-age=3600")
+	return c.JSON(h.oidcInteractor.Metadata())
+}
+
+// JWKS menangani daftar kunci publik untuk memverifikasi token. Masa cache lebih pendek dari
+// waktu publikasi awal kunci baru sehingga verifier sudah mengenalnya sebelum kunci dipakai.
+func (h *OIDCHandler) JWKS(c *fiber.Ctx) error {
+	keys, err := h.oidcInteractor.PublicKeys()
+	if err != nil {
+		log.Printf("Kesalahan JWKS di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memuat kunci publik"})
+	}
+	c.Set(fiber.HeaderCacheControl, "public, max-age=900")
+	return c.JSON(fiber.Map{"keys": keys})
+}
+
+// UserInfo menangani endpoint userinfo OpenID Connect untuk token yang sudah diautentikasi.
+func (h *OIDCHandler) UserInfo(c *fiber.Ctx) error {
+	claims := middlewares.ClaimsFromContext(c)
+	if claims == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	info, err := h.oidcInteractor.UserInfo(claims)
+	if err != nil {
+		if errors.Is(err, interactors.ErrOpenIDScopeRequired) {
+			c.Set(fiber.HeaderWWWAuthenticate, `Bearer error="insufficient_scope", scope="openid"`)
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "insufficient_scope", "error_description": err.Error()})
+		}
+		log.Printf("Kesalahan UserInfo di handler: %v", err)
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Pengguna tidak ditemukan"})
+	}
+	return c.JSON(info)
+}
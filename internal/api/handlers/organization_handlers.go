@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// OrganizationHandler menangani permintaan HTTP untuk organisasi (tenant) dan anggotanya.
+type OrganizationHandler struct {
+	organizationInteractor *interactors.OrganizationInteractor
+}
+
+// NewOrganizationHandler membuat instance baru dari OrganizationHandler.
+func NewOrganizationHandler(oi *interactors.OrganizationInteractor) *OrganizationHandler {
+	return &OrganizationHandler{organizationInteractor: oi}
+}
+
+// organizationRequest adalah body permintaan membuat atau mengganti nama organisasi.
+type organizationRequest struct {
+	Name string `json:"name"`
+	Slug string `json:"slug"` // Diabaikan saat mengganti nama
+}
+
+// memberRequest adalah body permintaan menambah anggota atau mengganti Role-nya.
+type memberRequest struct {
+	UserID uuid.UUID `json:"user_id"` // Diabaikan saat mengganti Role
+	Roles  []string  `json:"roles"`
+}
+
+// CreateOrganization menangani pembuatan organisasi oleh admin.
+func (h *OrganizationHandler) CreateOrganization(c *fiber.Ctx) error {
+	req := new(organizationRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	organization, err := h.organizationInteractor.Create(req.Name, req.Slug)
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(organization)
+}
+
+// ListOrganizations menangani pengambilan semua organisasi oleh admin.
+func (h *OrganizationHandler) ListOrganizations(c *fiber.Ctx) error {
+	organizations, err := h.organizationInteractor.List()
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+	return c.JSON(organizations)
+}
+
+// RenameOrganization menangani penggantian nama organisasi oleh admin.
+func (h *OrganizationHandler) RenameOrganization(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID organisasi tidak valid"})
+	}
+
+	req := new(organizationRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	organization, err := h.organizationInteractor.Rename(id, req.Name)
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+	return c.JSON(organization)
+}
+
+// DeleteOrganization menangani penghapusan organisasi oleh admin.
+func (h *OrganizationHandler) DeleteOrganization(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID organisasi tidak valid"})
+	}
+
+	if err := h.organizationInteractor.Delete(id); err != nil {
+		return organizationErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// ListMyOrganizations menangani pengambilan organisasi tempat pengguna yang sedang login menjadi anggota.
+func (h *OrganizationHandler) ListMyOrganizations(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	organizations, err := h.organizationInteractor.ListForUser(userID)
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+	return c.JSON(organizations)
+}
+
+// ListMembers menangani pengambilan anggota organisasi aktif.
+func (h *OrganizationHandler) ListMembers(c *fiber.Ctx) error {
+	organizationID, ok := currentOrganizationID(c)
+	if !ok {
+		return organizationRequired(c)
+	}
+
+	members, err := h.organizationInteractor.Members(organizationID)
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+	return c.JSON(members)
+}
+
+// AddMember menangani penambahan pengguna yang sudah ada ke organisasi aktif.
+func (h *OrganizationHandler) AddMember(c *fiber.Ctx) error {
+	organizationID, ok := currentOrganizationID(c)
+	if !ok {
+		return organizationRequired(c)
+	}
+
+	req := new(memberRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	member, err := h.organizationInteractor.AddMember(organizationID, req.UserID, req.Roles)
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(member)
+}
+
+// UpdateMemberRoles menangani penggantian Role anggota di organisasi aktif.
+func (h *OrganizationHandler) UpdateMemberRoles(c *fiber.Ctx) error {
+	organizationID, ok := currentOrganizationID(c)
+	if !ok {
+		return organizationRequired(c)
+	}
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	req := new(memberRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	member, err := h.organizationInteractor.SetMemberRoles(organizationID, userID, req.Roles)
+	if err != nil {
+		return organizationErrorResponse(c, err)
+	}
+	return c.JSON(member)
+}
+
+// RemoveMember menangani pengeluaran anggota dari organisasi aktif.
+func (h *OrganizationHandler) RemoveMember(c *fiber.Ctx) error {
+	organizationID, ok := currentOrganizationID(c)
+	if !ok {
+		return organizationRequired(c)
+	}
+	userID, err := uuid.Parse(c.Params("userId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	if err := h.organizationInteractor.RemoveMember(organizationID, userID); err != nil {
+		return organizationErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// organizationRequired menolak rute anggota yang dipanggil superuser tanpa memilih organisasi.
+func organizationRequired(c *fiber.Ctx) error {
+	return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Organisasi wajib dipilih"})
+}
+
+// organizationErrorResponse memetakan error organisasi ke respons HTTP.
+func organizationErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrOrganizationNotFound),
+		errors.Is(err, interactors.ErrNotOrganizationMember),
+		errors.Is(err, interactors.ErrOrganizationUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrOrganizationNameRequired),
+		errors.Is(err, interactors.ErrOrganizationSlugInvalid),
+		errors.Is(err, interactors.ErrOrganizationRoleNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrOrganizationSlugTaken),
+		errors.Is(err, interactors.ErrOrganizationMemberExists),
+		errors.Is(err, interactors.ErrSoDViolation):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrRoleGrantApprovalRequired),
+		errors.Is(err, interactors.ErrOrganizationRoleNotAssignable):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan organisasi di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses organisasi"})
+	}
+}
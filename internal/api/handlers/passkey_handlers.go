@@ -0,0 +1,139 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// PasskeyHandler menangani permintaan HTTP untuk pendaftaran dan pengelolaan passkey milik pengguna yang sudah login.
+type PasskeyHandler struct {
+	webAuthnInteractor *interactors.WebAuthnInteractor
+}
+
+// NewPasskeyHandler membuat instance baru dari PasskeyHandler.
+func NewPasskeyHandler(wi *interactors.WebAuthnInteractor) *PasskeyHandler {
+	return &PasskeyHandler{webAuthnInteractor: wi}
+}
+
+// passkeyRequest adalah body permintaan registrasi dan penggantian nama passkey.
+// Credential adalah PublicKeyCredential dari navigator.credentials.create() dalam bentuk JSON.
+type passkeyRequest struct {
+	Name       string          `json:"name"`
+	Credential json.RawMessage `json:"credential"`
+}
+
+// BeginRegistration menangani permulaan registrasi passkey dan mengembalikan opsi untuk navigator.credentials.create().
+func (h *PasskeyHandler) BeginRegistration(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(passkeyRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	creation, err := h.webAuthnInteractor.BeginRegistration(c.UserContext(), userID, req.Name)
+	if err != nil {
+		return passkeyErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"options": creation})
+}
+
+// FinishRegistration menangani penyelesaian registrasi passkey.
+func (h *PasskeyHandler) FinishRegistration(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(passkeyRequest)
+	if err := c.BodyParser(req); err != nil || len(req.Credential) == 0 {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	credential, err := h.webAuthnInteractor.FinishRegistration(c.UserContext(), userID, req.Credential)
+	if err != nil {
+		return passkeyErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(credential)
+}
+
+// ListPasskeys menangani pengambilan semua passkey milik pengguna.
+func (h *PasskeyHandler) ListPasskeys(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	credentials, err := h.webAuthnInteractor.ListCredentials(userID)
+	if err != nil {
+		return passkeyErrorResponse(c, err)
+	}
+	return c.JSON(credentials)
+}
+
+// RenamePasskey menangani penggantian nama passkey.
+func (h *PasskeyHandler) RenamePasskey(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID passkey tidak valid"})
+	}
+
+	req := new(passkeyRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	credential, err := h.webAuthnInteractor.RenameCredential(userID, id, req.Name)
+	if err != nil {
+		return passkeyErrorResponse(c, err)
+	}
+	return c.JSON(credential)
+}
+
+// DeletePasskey menangani penghapusan passkey.
+func (h *PasskeyHandler) DeletePasskey(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID passkey tidak valid"})
+	}
+
+	if err := h.webAuthnInteractor.DeleteCredential(userID, id); err != nil {
+		return passkeyErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// passkeyErrorResponse memetakan error passkey ke respons HTTP.
+func passkeyErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrPasskeyNameRequired),
+		errors.Is(err, interactors.ErrPasskeyVerificationFailed):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrPasskeyCeremonyExpired):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrPasskeyNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan passkey di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses passkey"})
+	}
+}
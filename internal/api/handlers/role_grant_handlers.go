@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+	"time"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RoleGrantHandler menangani permintaan HTTP untuk penetapan role langsung ke pengguna oleh admin.
+type RoleGrantHandler struct {
+	grantInteractor    *interactors.RoleGrantInteractor
+	approvalInteractor *interactors.ApprovalInteractor
+}
+
+// NewRoleGrantHandler membuat instance baru dari RoleGrantHandler.
+func NewRoleGrantHandler(gi *interactors.RoleGrantInteractor, ai *interactors.ApprovalInteractor) *RoleGrantHandler {
+	return &RoleGrantHandler{grantInteractor: gi, approvalInteractor: ai}
+}
+
+// roleGrantRequest adalah body permintaan menetapkan role ke pengguna. Tanpa expires_at dan hours
+// penetapan bersifat permanen.
+type roleGrantRequest struct {
+	Role      string     `json:"role"`
+	StartsAt  *time.Time `json:"starts_at"`
+	ExpiresAt *time.Time `json:"expires_at"`
+	Hours     int        `json:"hours"`
+	Reason    string     `json:"reason"`
+}
+
+func (r *roleGrantRequest) input() interactors.RoleGrantInput {
+	return interactors.RoleGrantInput{
+		Role:      r.Role,
+		StartsAt:  r.StartsAt,
+		ExpiresAt: r.ExpiresAt,
+		Hours:     r.Hours,
+		Reason:    r.Reason,
+	}
+}
+
+// ListUserRoles menangani pengambilan penetapan role langsung pengguna beserta jendela waktunya.
+func (h *RoleGrantHandler) ListUserRoles(c *fiber.Ctx) error {
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	grants, err := h.grantInteractor.List(userID)
+	if err != nil {
+		return roleGrantErrorResponse(c, err)
+	}
+	return c.JSON(grants)
+}
+
+// GrantUserRole menangani penetapan role ke pengguna, permanen atau sementara. Role yang punya kebijakan
+// persetujuan tidak langsung diberikan; permintaan penetapannya dikembalikan dengan status 202.
+func (h *RoleGrantHandler) GrantUserRole(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	req := new(roleGrantRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	grant, request, err := h.approvalInteractor.AssignRole(adminID, userID, req.input())
+	if err != nil {
+		return approvalErrorResponse(c, err)
+	}
+	if request != nil {
+		return c.Status(fiber.StatusAccepted).JSON(request)
+	}
+	return c.Status(fiber.StatusCreated).JSON(grant)
+}
+
+// RevokeUserRole menangani pencabutan role dari pengguna sebelum waktunya berakhir.
+func (h *RoleGrantHandler) RevokeUserRole(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	userID, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+	roleID, err := uuid.Parse(c.Params("roleId"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID role tidak valid"})
+	}
+
+	if err := h.grantInteractor.Revoke(userID, roleID, adminID); err != nil {
+		return roleGrantErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// roleGrantErrorResponse memetakan error penetapan role ke respons HTTP.
+func roleGrantErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrRoleGrantNotFound),
+		errors.Is(err, interactors.ErrRoleGrantUserNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrRoleGrantRoleNotFound),
+		errors.Is(err, interactors.ErrRoleGrantWindowInvalid):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrSoDViolation):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan penetapan role di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses penetapan role"})
+	}
+}
@@ -0,0 +1,237 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RoleHandler menangani permintaan HTTP untuk role, permission-nya dan pewarisan antar role.
+type RoleHandler struct {
+	roleInteractor *interactors.RoleInteractor
+}
+
+// NewRoleHandler membuat instance baru dari RoleHandler.
+func NewRoleHandler(ri *interactors.RoleInteractor) *RoleHandler {
+	return &RoleHandler{roleInteractor: ri}
+}
+
+// roleRequest adalah body permintaan membuat atau memperbarui role.
+type roleRequest struct {
+	Name        string `json:"name"`
+	Description string `json:"description"`
+}
+
+// rolePermissionsRequest adalah body permintaan mengganti permission langsung role.
+type rolePermissionsRequest struct {
+	Permissions []string `json:"permissions"`
+}
+
+// roleParentsRequest adalah body permintaan mengganti role induk yang diwarisi.
+type roleParentsRequest struct {
+	Parents []string `json:"parents"`
+}
+
+// roleOwnerRequest adalah body permintaan menetapkan pemilik role.
+type roleOwnerRequest struct {
+	Owner string `json:"owner"` // Username atau email; kosong untuk menghapus pemilik
+}
+
+// roleSCIMRequest adalah body permintaan mengatur apakah role dikelola klien SCIM.
+type roleSCIMRequest struct {
+	SCIMManaged bool `json:"scim_managed"`
+}
+
+// roleTenantRequest adalah body permintaan mengatur apakah role boleh diberikan ke anggota organisasi.
+type roleTenantRequest struct {
+	TenantRole bool `json:"tenant_role"`
+}
+
+// CreateRole menangani pembuatan role oleh admin.
+func (h *RoleHandler) CreateRole(c *fiber.Ctx) error {
+	req := new(roleRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	role, err := h.roleInteractor.Create(req.Name, req.Description)
+	if err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(role)
+}
+
+// ListRoles menangani pengambilan semua role beserta permission langsung dan warisannya.
+func (h *RoleHandler) ListRoles(c *fiber.Ctx) error {
+	roles, err := h.roleInteractor.List()
+	if err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.JSON(roles)
+}
+
+// GetRole menangani pengambilan satu role beserta permission langsung dan warisannya.
+func (h *RoleHandler) GetRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID role tidak valid"})
+	}
+
+	role, err := h.roleInteractor.Get(id)
+	if err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.JSON(role)
+}
+
+// UpdateRole menangani perubahan nama dan deskripsi role.
+func (h *RoleHandler) UpdateRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID role tidak valid"})
+	}
+
+	req := new(roleRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	role, err := h.roleInteractor.Update(id, req.Name, req.Description)
+	if err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.JSON(role)
+}
+
+// DeleteRole menangani penghapusan role.
+func (h *RoleHandler) DeleteRole(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID role tidak valid"})
+	}
+
+	if err := h.roleInteractor.Delete(id); err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// SetPermissions menangani penggantian permission langsung role.
+func (h *RoleHandler) SetPermissions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID role tidak valid"})
+	}
+
+	req := new(rolePermissionsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	role, err := h.roleInteractor.SetPermissions(id, req.Permissions)
+	if err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.JSON(role)
+}
+
+// SetParents menangani penggantian role induk yang diwarisi role.
+func (h *RoleHandler) SetParents(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID role tidak valid"})
+	}
+
+	req := new(roleParentsRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	role, err := h.roleInteractor.SetParents(id, req.Parents)
+	if err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.JSON(role)
+}
+
+// SetRoleOwner menangani penetapan pemilik role yang meninjau penetapannya dalam tinjauan akses.
+func (h *RoleHandler) SetRoleOwner(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID role tidak valid"})
+	}
+
+	req := new(roleOwnerRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	role, err := h.roleInteractor.SetOwner(id, req.Owner)
+	if err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.JSON(role)
+}
+
+// SetRoleSCIM menangani pengaturan apakah role boleh dikelola klien SCIM sebagai Group.
+func (h *RoleHandler) SetRoleSCIM(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID role tidak valid"})
+	}
+
+	req := new(roleSCIMRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	role, err := h.roleInteractor.SetSCIMManaged(id, req.SCIMManaged)
+	if err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.JSON(role)
+}
+
+// SetRoleTenant menangani pengaturan apakah role boleh diberikan ke anggota organisasi.
+func (h *RoleHandler) SetRoleTenant(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID role tidak valid"})
+	}
+
+	req := new(roleTenantRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	role, err := h.roleInteractor.SetTenantRole(id, req.TenantRole)
+	if err != nil {
+		return roleErrorResponse(c, err)
+	}
+	return c.JSON(role)
+}
+
+// roleErrorResponse memetakan error role ke respons HTTP.
+func roleErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrRoleNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrRoleNameRequired),
+		errors.Is(err, interactors.ErrRoleParentNotFound),
+		errors.Is(err, interactors.ErrRoleOwnerNotFound),
+		errors.Is(err, interactors.ErrPermissionNameInvalid):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrRoleNameTaken),
+		errors.Is(err, interactors.ErrRoleCycle),
+		errors.Is(err, interactors.ErrRoleParentApprovalRequired),
+		errors.Is(err, interactors.ErrSoDViolation):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan role di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses role"})
+	}
+}
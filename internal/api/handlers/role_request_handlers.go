@@ -0,0 +1,145 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// RoleRequestHandler menangani permintaan HTTP untuk permintaan role sementara dan peninjauannya.
+type RoleRequestHandler struct {
+	requestInteractor *interactors.RoleRequestInteractor
+}
+
+// NewRoleRequestHandler membuat instance baru dari RoleRequestHandler.
+func NewRoleRequestHandler(ri *interactors.RoleRequestInteractor) *RoleRequestHandler {
+	return &RoleRequestHandler{requestInteractor: ri}
+}
+
+// createRoleRequestRequest adalah body permintaan role sementara.
+type createRoleRequestRequest struct {
+	Role   string `json:"role"`
+	Hours  int    `json:"hours"`
+	Reason string `json:"reason"`
+}
+
+// CreateRoleRequest menangani permintaan pengguna untuk memegang role selama beberapa jam.
+func (h *RoleRequestHandler) CreateRoleRequest(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(createRoleRequestRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	request, err := h.requestInteractor.Create(userID, req.Role, req.Hours, req.Reason)
+	if err != nil {
+		return roleRequestErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(request)
+}
+
+// ListMyRoleRequests menangani pengambilan permintaan role milik pengguna yang sedang login.
+func (h *RoleRequestHandler) ListMyRoleRequests(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	requests, err := h.requestInteractor.ListMine(userID)
+	if err != nil {
+		return roleRequestErrorResponse(c, err)
+	}
+	return c.JSON(requests)
+}
+
+// CancelRoleRequest menangani pembatalan permintaan role milik pengguna yang masih menunggu.
+func (h *RoleRequestHandler) CancelRoleRequest(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID permintaan tidak valid"})
+	}
+
+	if err := h.requestInteractor.Cancel(userID, id); err != nil {
+		return roleRequestErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// ListPendingRoleRequests menangani pengambilan permintaan role yang menunggu keputusan peninjau.
+func (h *RoleRequestHandler) ListPendingRoleRequests(c *fiber.Ctx) error {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	requests, err := h.requestInteractor.ListPending(reviewerID)
+	if err != nil {
+		return roleRequestErrorResponse(c, err)
+	}
+	return c.JSON(requests)
+}
+
+// ApproveRoleRequest menangani persetujuan permintaan role; role langsung diberikan sampai durasinya habis.
+func (h *RoleRequestHandler) ApproveRoleRequest(c *fiber.Ctx) error {
+	return h.review(c, h.requestInteractor.Approve)
+}
+
+// RejectRoleRequest menangani penolakan permintaan role.
+func (h *RoleRequestHandler) RejectRoleRequest(c *fiber.Ctx) error {
+	return h.review(c, h.requestInteractor.Reject)
+}
+
+// review menjalankan keputusan peninjau untuk permintaan di parameter :id.
+func (h *RoleRequestHandler) review(c *fiber.Ctx, decide func(reviewerID, id uuid.UUID) (*entities.RoleRequest, error)) error {
+	reviewerID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID permintaan tidak valid"})
+	}
+
+	request, err := decide(reviewerID, id)
+	if err != nil {
+		return roleRequestErrorResponse(c, err)
+	}
+	return c.JSON(request)
+}
+
+// roleRequestErrorResponse memetakan error permintaan role ke respons HTTP.
+func roleRequestErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrRoleRequestNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrRoleRequestRoleNotFound),
+		errors.Is(err, interactors.ErrRoleRequestHoursInvalid),
+		errors.Is(err, interactors.ErrRoleRequestReasonRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrRoleRequestForbidden),
+		errors.Is(err, interactors.ErrRoleRequestSelfReview):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrRoleRequestAlreadyHeld),
+		errors.Is(err, interactors.ErrRoleRequestDuplicate),
+		errors.Is(err, interactors.ErrRoleRequestNotPending),
+		errors.Is(err, interactors.ErrAssignmentDuplicate),
+		errors.Is(err, interactors.ErrSoDViolation):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan permintaan role di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses permintaan role"})
+	}
+}
@@ -0,0 +1,171 @@
+package handlers
+
+import (
+	"errors"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/services"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SAMLHandler menangani permintaan HTTP login SAML 2.0 dan pengelolaan koneksi SAML.
+type SAMLHandler struct {
+	samlInteractor *interactors.SAMLInteractor
+}
+
+// NewSAMLHandler membuat instance baru dari SAMLHandler.
+func NewSAMLHandler(si *interactors.SAMLInteractor) *SAMLHandler {
+	return &SAMLHandler{samlInteractor: si}
+}
+
+// samlConnectionRequest adalah body permintaan membuat atau memperbarui koneksi SAML.
+type samlConnectionRequest struct {
+	Name              string                        `json:"name"` // Diabaikan saat memperbarui koneksi
+	DisplayName       string                        `json:"display_name"`
+	MetadataXML       string                        `json:"metadata_xml"`
+	MetadataURL       string                        `json:"metadata_url"`
+	AllowIDPInitiated bool                          `json:"allow_idp_initiated"`
+	AllowSignup       bool                          `json:"allow_signup"`
+	AttributeMapping  entities.SAMLAttributeMapping `json:"attribute_mapping"`
+	GroupRoles        map[string][]string           `json:"group_roles"`
+	EmailDomains      []string                      `json:"email_domains"`
+}
+
+// toInteractor mengubah body permintaan menjadi input interactor.
+func (r *samlConnectionRequest) toInteractor() interactors.SAMLConnectionInput {
+	return interactors.SAMLConnectionInput{
+		Name:              r.Name,
+		DisplayName:       r.DisplayName,
+		MetadataXML:       r.MetadataXML,
+		MetadataURL:       r.MetadataURL,
+		AllowIDPInitiated: r.AllowIDPInitiated,
+		AllowSignup:       r.AllowSignup,
+		AttributeMapping:  r.AttributeMapping,
+		GroupRoles:        r.GroupRoles,
+		EmailDomains:      r.EmailDomains,
+	}
+}
+
+// ListConnections menangani pengambilan koneksi SAML untuk halaman login.
+func (h *SAMLHandler) ListConnections(c *fiber.Ctx) error {
+	connections, err := h.samlInteractor.Connections()
+	if err != nil {
+		return samlErrorResponse(c, err)
+	}
+	return c.JSON(connections)
+}
+
+// Metadata menangani pengambilan metadata service provider untuk didaftarkan di IdP.
+func (h *SAMLHandler) Metadata(c *fiber.Ctx) error {
+	metadata, err := h.samlInteractor.Metadata(c.Params("connection"))
+	if err != nil {
+		return samlErrorResponse(c, err)
+	}
+	c.Set(fiber.HeaderContentType, "application/samlmetadata+xml")
+	return c.Send(metadata)
+}
+
+// BeginLogin menangani permulaan login SP-initiated. Frontend mengarahkan browser ke redirect_to.
+func (h *SAMLHandler) BeginLogin(c *fiber.Ctx) error {
+	redirectURL, err := h.samlInteractor.BeginLogin(c.UserContext(), c.Params("connection"))
+	if err != nil {
+		return samlErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"redirect_to": redirectURL})
+}
+
+// ACS menangani SAMLResponse yang dikirim IdP lewat binding HTTP-POST. Hasilnya berupa respons
+// login yang sama dengan POST /auth/login.
+func (h *SAMLHandler) ACS(c *fiber.Ctx) error {
+	samlResponse := c.FormValue("SAMLResponse")
+	if samlResponse == "" {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "SAMLResponse wajib diisi"})
+	}
+
+	result, err := h.samlInteractor.ACS(c.UserContext(), c.Params("connection"), samlResponse, c.FormValue("RelayState"), clientInfo(c))
+	if err != nil {
+		return samlErrorResponse(c, err)
+	}
+	return loginResponse(c, result)
+}
+
+// CreateConnection menangani pembuatan koneksi SAML oleh admin.
+func (h *SAMLHandler) CreateConnection(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(samlConnectionRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	connection, err := h.samlInteractor.CreateConnection(c.UserContext(), userID, req.toInteractor())
+	if err != nil {
+		return samlErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(connection)
+}
+
+// ListAllConnections menangani pengambilan semua koneksi SAML beserta pengaturannya oleh admin.
+func (h *SAMLHandler) ListAllConnections(c *fiber.Ctx) error {
+	connections, err := h.samlInteractor.ListConnections()
+	if err != nil {
+		return samlErrorResponse(c, err)
+	}
+	return c.JSON(connections)
+}
+
+// UpdateConnection menangani perubahan koneksi SAML oleh admin.
+func (h *SAMLHandler) UpdateConnection(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID koneksi tidak valid"})
+	}
+
+	req := new(samlConnectionRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	connection, err := h.samlInteractor.UpdateConnection(c.UserContext(), id, req.toInteractor())
+	if err != nil {
+		return samlErrorResponse(c, err)
+	}
+	return c.JSON(connection)
+}
+
+// DeleteConnection menangani penghapusan koneksi SAML oleh admin.
+func (h *SAMLHandler) DeleteConnection(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID koneksi tidak valid"})
+	}
+
+	if err := h.samlInteractor.DeleteConnection(id); err != nil {
+		return samlErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// samlErrorResponse memetakan error SAML ke respons HTTP. Error login eksternal diteruskan ke socialLoginErrorResponse.
+func samlErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrSAMLConnectionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrSAMLConnectionNameInvalid),
+		errors.Is(err, interactors.ErrSAMLMetadataRequired),
+		errors.Is(err, services.ErrSAMLInvalidMetadata):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrSAMLConnectionNameTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrInvalidSAMLRelayState):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return socialLoginErrorResponse(c, err)
+	}
+}
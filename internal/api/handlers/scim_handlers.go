@@ -0,0 +1,335 @@
+package handlers
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"strconv"
+	"strings"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// scimContentType adalah media type respons SCIM (RFC 7644 bagian 3.1).
+const scimContentType = "application/scim+json"
+
+// SCIMHandler menangani endpoint provisioning SCIM 2.0 di bawah /scim/v2.
+type SCIMHandler struct {
+	scimInteractor *interactors.SCIMInteractor
+}
+
+// NewSCIMHandler membuat instance baru dari SCIMHandler.
+func NewSCIMHandler(si *interactors.SCIMInteractor) *SCIMHandler {
+	return &SCIMHandler{scimInteractor: si}
+}
+
+// scimPatchRequest adalah body permintaan PATCH (PatchOp).
+type scimPatchRequest struct {
+	Schemas    []string                         `json:"schemas"`
+	Operations []interactors.SCIMPatchOperation `json:"Operations"`
+}
+
+// ServiceProviderConfig menangani dokumen fitur SCIM yang didukung.
+func (h *SCIMHandler) ServiceProviderConfig(c *fiber.Ctx) error {
+	return scimJSON(c, fiber.StatusOK, h.scimInteractor.ServiceProviderConfig())
+}
+
+// ResourceTypes menangani daftar resource type.
+func (h *SCIMHandler) ResourceTypes(c *fiber.Ctx) error {
+	resourceTypes := h.scimInteractor.ResourceTypes()
+	resources := make([]any, 0, len(resourceTypes))
+	for _, resourceType := range resourceTypes {
+		resources = append(resources, resourceType)
+	}
+	return scimJSON(c, fiber.StatusOK, scimList(resources))
+}
+
+// ResourceType menangani satu resource type.
+func (h *SCIMHandler) ResourceType(c *fiber.Ctx) error {
+	resourceType, err := h.scimInteractor.ResourceType(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, resourceType)
+}
+
+// Schemas menangani daftar definisi skema.
+func (h *SCIMHandler) Schemas(c *fiber.Ctx) error {
+	schemas := h.scimInteractor.Schemas()
+	resources := make([]any, 0, len(schemas))
+	for _, schema := range schemas {
+		resources = append(resources, schema)
+	}
+	return scimJSON(c, fiber.StatusOK, scimList(resources))
+}
+
+// Schema menangani satu definisi skema berdasarkan URN-nya.
+func (h *SCIMHandler) Schema(c *fiber.Ctx) error {
+	schema, err := h.scimInteractor.Schema(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, schema)
+}
+
+// ListUsers menangani query pengguna dengan filter dan pagination.
+func (h *SCIMHandler) ListUsers(c *fiber.Ctx) error {
+	client, _ := currentUserID(c)
+	list, err := h.scimInteractor.ListUsers(client, scimQuery(c))
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, list)
+}
+
+// GetUser menangani pengambilan satu pengguna.
+func (h *SCIMHandler) GetUser(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMUserNotFound)
+	}
+
+	client, _ := currentUserID(c)
+	user, err := h.scimInteractor.GetUser(client, id)
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, user)
+}
+
+// CreateUser menangani pembuatan pengguna oleh klien provisioning.
+func (h *SCIMHandler) CreateUser(c *fiber.Ctx) error {
+	input := new(interactors.SCIMUser)
+	if err := json.Unmarshal(c.Body(), input); err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMInvalidSyntax)
+	}
+
+	client, _ := currentUserID(c)
+	user, err := h.scimInteractor.CreateUser(client, input)
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	c.Set(fiber.HeaderLocation, user.Meta.Location)
+	return scimJSON(c, fiber.StatusCreated, user)
+}
+
+// ReplaceUser menangani penggantian pengguna (PUT).
+func (h *SCIMHandler) ReplaceUser(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMUserNotFound)
+	}
+	input := new(interactors.SCIMUser)
+	if err := json.Unmarshal(c.Body(), input); err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMInvalidSyntax)
+	}
+
+	client, _ := currentUserID(c)
+	user, err := h.scimInteractor.ReplaceUser(c.UserContext(), client, id, input)
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, user)
+}
+
+// PatchUser menangani perubahan sebagian pengguna, termasuk deaktivasi.
+func (h *SCIMHandler) PatchUser(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMUserNotFound)
+	}
+	req := new(scimPatchRequest)
+	if err := json.Unmarshal(c.Body(), req); err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMInvalidSyntax)
+	}
+
+	client, _ := currentUserID(c)
+	user, err := h.scimInteractor.PatchUser(c.UserContext(), client, id, req.Operations)
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, user)
+}
+
+// DeleteUser menangani penghapusan pengguna oleh klien provisioning.
+func (h *SCIMHandler) DeleteUser(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMUserNotFound)
+	}
+
+	if err := h.scimInteractor.DeleteUser(c.UserContext(), id); err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// ListGroups menangani query grup dengan filter dan pagination.
+func (h *SCIMHandler) ListGroups(c *fiber.Ctx) error {
+	list, err := h.scimInteractor.ListGroups(scimQuery(c), scimWithMembers(c))
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, list)
+}
+
+// GetGroup menangani pengambilan satu grup.
+func (h *SCIMHandler) GetGroup(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMGroupNotFound)
+	}
+
+	group, err := h.scimInteractor.GetGroup(id, scimWithMembers(c))
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, group)
+}
+
+// CreateGroup menangani pembuatan grup (Role) oleh klien provisioning.
+func (h *SCIMHandler) CreateGroup(c *fiber.Ctx) error {
+	input := new(interactors.SCIMGroup)
+	if err := json.Unmarshal(c.Body(), input); err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMInvalidSyntax)
+	}
+
+	group, err := h.scimInteractor.CreateGroup(input)
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	c.Set(fiber.HeaderLocation, group.Meta.Location)
+	return scimJSON(c, fiber.StatusCreated, group)
+}
+
+// ReplaceGroup menangani penggantian nama dan anggota grup (PUT).
+func (h *SCIMHandler) ReplaceGroup(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMGroupNotFound)
+	}
+	input := new(interactors.SCIMGroup)
+	if err := json.Unmarshal(c.Body(), input); err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMInvalidSyntax)
+	}
+
+	group, err := h.scimInteractor.ReplaceGroup(id, input)
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, group)
+}
+
+// PatchGroup menangani perubahan sebagian grup, misalnya menambah atau mengeluarkan anggota.
+func (h *SCIMHandler) PatchGroup(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMGroupNotFound)
+	}
+	req := new(scimPatchRequest)
+	if err := json.Unmarshal(c.Body(), req); err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMInvalidSyntax)
+	}
+
+	group, err := h.scimInteractor.PatchGroup(id, req.Operations)
+	if err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return scimJSON(c, fiber.StatusOK, group)
+}
+
+// DeleteGroup menangani penghapusan grup oleh klien provisioning.
+func (h *SCIMHandler) DeleteGroup(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return scimErrorResponse(c, interactors.ErrSCIMGroupNotFound)
+	}
+
+	if err := h.scimInteractor.DeleteGroup(id); err != nil {
+		return scimErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// scimQuery membaca parameter filter, startIndex dan count dari query string.
+func scimQuery(c *fiber.Ctx) interactors.SCIMQuery {
+	return interactors.SCIMQuery{
+		Filter:     c.Query("filter"),
+		StartIndex: c.QueryInt("startIndex", 1),
+		Count:      c.QueryInt("count", -1),
+	}
+}
+
+// scimWithMembers mengembalikan false jika klien meminta excludedAttributes=members.
+func scimWithMembers(c *fiber.Ctx) bool {
+	for _, attribute := range strings.Split(c.Query("excludedAttributes"), ",") {
+		if strings.EqualFold(strings.TrimSpace(attribute), "members") {
+			return false
+		}
+	}
+	return true
+}
+
+// scimList membungkus dokumen discovery dalam ListResponse tanpa pagination.
+func scimList(resources []any) *interactors.SCIMListResponse {
+	return &interactors.SCIMListResponse{
+		Schemas:      []string{interactors.SCIMMessageListResponse},
+		TotalResults: len(resources),
+		StartIndex:   1,
+		ItemsPerPage: len(resources),
+		Resources:    resources,
+	}
+}
+
+// scimJSON mengirim respons dengan media type SCIM.
+func scimJSON(c *fiber.Ctx, status int, body any) error {
+	return c.Status(status).JSON(body, scimContentType)
+}
+
+// scimErrorResponse memetakan error SCIM ke respons error SCIM (RFC 7644 bagian 3.12).
+func scimErrorResponse(c *fiber.Ctx, err error) error {
+	status, scimType := fiber.StatusInternalServerError, ""
+	switch {
+	case errors.Is(err, interactors.ErrSCIMUserNotFound),
+		errors.Is(err, interactors.ErrSCIMGroupNotFound),
+		errors.Is(err, interactors.ErrSCIMSchemaNotFound),
+		errors.Is(err, interactors.ErrSCIMResourceTypeNotFound):
+		status = fiber.StatusNotFound
+	case errors.Is(err, interactors.ErrSCIMUniqueness):
+		status, scimType = fiber.StatusConflict, "uniqueness"
+	case errors.Is(err, interactors.ErrSoDViolation):
+		status = fiber.StatusConflict
+	case errors.Is(err, interactors.ErrSCIMInvalidFilter):
+		status, scimType = fiber.StatusBadRequest, "invalidFilter"
+	case errors.Is(err, interactors.ErrSCIMInvalidPath):
+		status, scimType = fiber.StatusBadRequest, "invalidPath"
+	case errors.Is(err, interactors.ErrSCIMNoTarget):
+		status, scimType = fiber.StatusBadRequest, "noTarget"
+	case errors.Is(err, interactors.ErrSCIMInvalidValue):
+		status, scimType = fiber.StatusBadRequest, "invalidValue"
+	case errors.Is(err, interactors.ErrSCIMInvalidSyntax):
+		status, scimType = fiber.StatusBadRequest, "invalidSyntax"
+	case errors.Is(err, interactors.ErrSCIMSuperuserProtected),
+		errors.Is(err, interactors.ErrRoleGrantApprovalRequired):
+		status = fiber.StatusForbidden
+	}
+
+	detail := err.Error()
+	if status == fiber.StatusInternalServerError {
+		log.Printf("Kesalahan SCIM di handler: %v", err)
+		detail = "Terjadi kesalahan pada server"
+	}
+
+	body := fiber.Map{
+		"schemas": []string{interactors.SCIMMessageError},
+		"status":  strconv.Itoa(status),
+		"detail":  detail,
+	}
+	if scimType != "" {
+		body["scimType"] = scimType
+	}
+	return scimJSON(c, status, body)
+}
@@ -0,0 +1,97 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/api/middlewares"
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SessionHandler menangani permintaan HTTP untuk daftar dan pencabutan sesi login.
+type SessionHandler struct {
+	sessionInteractor *interactors.SessionInteractor
+}
+
+// NewSessionHandler membuat instance baru dari SessionHandler.
+func NewSessionHandler(si *interactors.SessionInteractor) *SessionHandler {
+	return &SessionHandler{sessionInteractor: si}
+}
+
+// sessionResponse adalah sesi beserta penanda apakah sesi itu dipakai oleh permintaan saat ini.
+type sessionResponse struct {
+	entities.Session
+	Current bool `json:"current"`
+}
+
+// ListSessions menangani pengambilan semua sesi aktif milik pengguna yang sedang login.
+func (h *SessionHandler) ListSessions(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	sessions, err := h.sessionInteractor.ListActive(c.UserContext(), userID)
+	if err != nil {
+		return sessionErrorResponse(c, err)
+	}
+
+	var currentSessionID uuid.UUID
+	if claims := middlewares.ClaimsFromContext(c); claims != nil {
+		currentSessionID = claims.SessionID
+	}
+
+	response := make([]sessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response = append(response, sessionResponse{Session: session, Current: session.ID == currentSessionID})
+	}
+	return c.JSON(response)
+}
+
+// RevokeSession menangani pencabutan satu sesi milik pengguna yang sedang login.
+// Mencabut sesi saat ini sama dengan logout.
+func (h *SessionHandler) RevokeSession(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID sesi tidak valid"})
+	}
+
+	if err := h.sessionInteractor.Revoke(c.UserContext(), userID, id); err != nil {
+		return sessionErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// RevokeUserSessions menangani pencabutan semua sesi milik pengguna mana pun oleh admin.
+func (h *SessionHandler) RevokeUserSessions(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	revoked, err := h.sessionInteractor.RevokeAll(c.UserContext(), id)
+	if err != nil {
+		return sessionErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"revoked_sessions": revoked})
+}
+
+// sessionErrorResponse memetakan error sesi ke respons HTTP.
+func sessionErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrSessionNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan sesi di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses sesi"})
+	}
+}
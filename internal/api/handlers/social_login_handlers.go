@@ -0,0 +1,120 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SocialLoginHandler menangani permintaan HTTP login sosial dan identitas eksternal yang terhubung.
+type SocialLoginHandler struct {
+	socialInteractor *interactors.SocialLoginInteractor
+}
+
+// NewSocialLoginHandler membuat instance baru dari SocialLoginHandler.
+func NewSocialLoginHandler(si *interactors.SocialLoginInteractor) *SocialLoginHandler {
+	return &SocialLoginHandler{socialInteractor: si}
+}
+
+// ListProviders menangani pengambilan provider login sosial untuk halaman login.
+func (h *SocialLoginHandler) ListProviders(c *fiber.Ctx) error {
+	return c.JSON(h.socialInteractor.Providers())
+}
+
+// BeginLogin menangani permulaan login sosial. Frontend mengarahkan browser ke redirect_to.
+func (h *SocialLoginHandler) BeginLogin(c *fiber.Ctx) error {
+	redirectURL, err := h.socialInteractor.BeginLogin(c.UserContext(), c.Params("provider"))
+	if err != nil {
+		return socialLoginErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"redirect_to": redirectURL})
+}
+
+// Callback menangani kembalinya pengguna dari provider. Hasilnya berupa respons login yang sama
+// dengan POST /auth/login, atau identitas yang baru dihubungkan jika permintaan dimulai dari BeginLink.
+func (h *SocialLoginHandler) Callback(c *fiber.Ctx) error {
+	if providerErr := c.Query("error"); providerErr != "" {
+		log.Printf("Provider %s menolak login: %s %s", c.Params("provider"), providerErr, c.Query("error_description"))
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": interactors.ErrExternalLoginFailed.Error()})
+	}
+
+	result, err := h.socialInteractor.Callback(c.UserContext(), c.Params("provider"), c.Query("code"), c.Query("state"), clientInfo(c))
+	if err != nil {
+		return socialLoginErrorResponse(c, err)
+	}
+	if result.Linked != nil {
+		return c.Status(fiber.StatusCreated).JSON(fiber.Map{"linked": result.Linked})
+	}
+	return loginResponse(c, result.Login)
+}
+
+// ListIdentities menangani pengambilan identitas eksternal milik pengguna yang sedang login.
+func (h *SocialLoginHandler) ListIdentities(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	identities, err := h.socialInteractor.ListIdentities(userID)
+	if err != nil {
+		return socialLoginErrorResponse(c, err)
+	}
+	return c.JSON(identities)
+}
+
+// BeginLink menangani permulaan penghubungan akun eksternal ke pengguna yang sedang login.
+func (h *SocialLoginHandler) BeginLink(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	redirectURL, err := h.socialInteractor.BeginLink(c.UserContext(), userID, c.Params("provider"))
+	if err != nil {
+		return socialLoginErrorResponse(c, err)
+	}
+	return c.JSON(fiber.Map{"redirect_to": redirectURL})
+}
+
+// Unlink menangani pemutusan identitas eksternal dari pengguna yang sedang login.
+func (h *SocialLoginHandler) Unlink(c *fiber.Ctx) error {
+	userID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID identitas tidak valid"})
+	}
+
+	if err := h.socialInteractor.Unlink(userID, id); err != nil {
+		return socialLoginErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// socialLoginErrorResponse memetakan error login sosial ke respons HTTP. Error login
+// (akun dikunci, tidak aktif, throttling) diteruskan ke loginErrorResponse.
+func socialLoginErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrIdentityProviderNotFound),
+		errors.Is(err, interactors.ErrLinkedIdentityNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrInvalidSocialState),
+		errors.Is(err, interactors.ErrExternalLoginFailed),
+		errors.Is(err, interactors.ErrExternalEmailNotVerified),
+		errors.Is(err, interactors.ErrExternalAccountNotFound),
+		errors.Is(err, interactors.ErrExternalLinkRequired):
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrIdentityAlreadyLinked),
+		errors.Is(err, interactors.ErrLastLoginMethod):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		return loginErrorResponse(c, err)
+	}
+}
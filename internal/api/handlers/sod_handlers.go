@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"errors"
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// SoDHandler menangani permintaan HTTP untuk aturan pemisahan tugas dan laporan pelanggarannya.
+type SoDHandler struct {
+	sodInteractor *interactors.SoDInteractor
+}
+
+// NewSoDHandler membuat instance baru dari SoDHandler.
+func NewSoDHandler(si *interactors.SoDInteractor) *SoDHandler {
+	return &SoDHandler{sodInteractor: si}
+}
+
+// sodRuleRequest adalah body permintaan membuat atau mengganti aturan pemisahan tugas.
+type sodRuleRequest struct {
+	Name        string   `json:"name"`
+	Description string   `json:"description"`
+	Roles       []string `json:"roles"` // Minimal dua role yang tidak boleh dipegang bersamaan
+}
+
+func (r *sodRuleRequest) input() interactors.SoDRuleInput {
+	return interactors.SoDRuleInput{Name: r.Name, Description: r.Description, Roles: r.Roles}
+}
+
+// CreateRule menangani pembuatan aturan pemisahan tugas oleh admin.
+func (h *SoDHandler) CreateRule(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	req := new(sodRuleRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	rule, err := h.sodInteractor.CreateRule(adminID, req.input())
+	if err != nil {
+		return sodErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusCreated).JSON(rule)
+}
+
+// ListRules menangani pengambilan semua aturan pemisahan tugas.
+func (h *SoDHandler) ListRules(c *fiber.Ctx) error {
+	rules, err := h.sodInteractor.ListRules()
+	if err != nil {
+		return sodErrorResponse(c, err)
+	}
+	return c.JSON(rules)
+}
+
+// GetRule menangani pengambilan satu aturan pemisahan tugas.
+func (h *SoDHandler) GetRule(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID aturan tidak valid"})
+	}
+
+	rule, err := h.sodInteractor.GetRule(id)
+	if err != nil {
+		return sodErrorResponse(c, err)
+	}
+	return c.JSON(rule)
+}
+
+// UpdateRule menangani penggantian isi aturan pemisahan tugas.
+func (h *SoDHandler) UpdateRule(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID aturan tidak valid"})
+	}
+
+	req := new(sodRuleRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	rule, err := h.sodInteractor.UpdateRule(adminID, id, req.input())
+	if err != nil {
+		return sodErrorResponse(c, err)
+	}
+	return c.JSON(rule)
+}
+
+// DeleteRule menangani penghapusan aturan pemisahan tugas.
+func (h *SoDHandler) DeleteRule(c *fiber.Ctx) error {
+	adminID, ok := currentUserID(c)
+	if !ok {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID aturan tidak valid"})
+	}
+
+	if err := h.sodInteractor.DeleteRule(adminID, id); err != nil {
+		return sodErrorResponse(c, err)
+	}
+	return c.Status(fiber.StatusNoContent).SendString("")
+}
+
+// ListViolations menangani laporan pengguna yang saat ini melanggar aturan pemisahan tugas.
+func (h *SoDHandler) ListViolations(c *fiber.Ctx) error {
+	violations, err := h.sodInteractor.Violations()
+	if err != nil {
+		return sodErrorResponse(c, err)
+	}
+	return c.JSON(violations)
+}
+
+// sodErrorResponse memetakan error aturan pemisahan tugas ke respons HTTP.
+func sodErrorResponse(c *fiber.Ctx, err error) error {
+	switch {
+	case errors.Is(err, interactors.ErrSoDRuleNotFound):
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrSoDRuleNameRequired),
+		errors.Is(err, interactors.ErrSoDRuleRolesInvalid),
+		errors.Is(err, interactors.ErrSoDRuleRoleNotFound):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrSoDRuleNameTaken):
+		return c.Status(fiber.StatusConflict).JSON(fiber.Map{"error": err.Error()})
+	default:
+		log.Printf("Kesalahan aturan pemisahan tugas di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memproses aturan pemisahan tugas"})
+	}
+}
@@ -1,13 +1,14 @@
 package handlers
 
 import (
+	"errors"
 	"log"
-	"strconv" // Untuk konversi string ke uint
 
 	"fiber-usermanagement/internal/domain/entities"
 	"fiber-usermanagement/internal/usecase/interactors"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
 // UserHandler menangani permintaan HTTP terkait entitas User.
@@ -20,15 +21,34 @@ func NewUserHandler(ui *interactors.UserInteractor) *UserHandler {
 	return &UserHandler{userInteractor: ui}
 }
 
+// createUserRequest adalah body permintaan pembuatan pengguna.
+// Terpisah dari entities.User karena field Password tidak pernah diserialisasi dari entitas.
+type createUserRequest struct {
+	Username  string `json:"username"`
+	Email     string `json:"email"`
+	Password  string `json:"password"`
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+}
+
 // CreateUser menangani pembuatan pengguna baru dari permintaan HTTP POST.
 func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
-	user := new(entities.User)
-	// Parse body permintaan ke struct User
-	if err := c.BodyParser(user); err != nil {
+	req := new(createUserRequest)
+	// Parse body permintaan ke struct createUserRequest
+	if err := c.BodyParser(req); err != nil {
 		log.Printf("Kesalahan BodyParser saat membuat pengguna: %v", err)
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
 	}
 
+	user := &entities.User{
+		Username:  req.Username,
+		Email:     req.Email,
+		Password:  req.Password,
+		FirstName: req.FirstName,
+		LastName:  req.LastName,
+		IsActive:  true,
+	}
+
 	// Panggil use case untuk membuat pengguna
 	createdUser, err := h.userInteractor.CreateUser(user)
 	if err != nil {
@@ -40,17 +60,40 @@ func (h *UserHandler) CreateUser(c *fiber.Ctx) error {
 	return c.Status(fiber.StatusCreated).JSON(createdUser)
 }
 
+// createServiceAccountRequest adalah body permintaan pembuatan service account.
+type createServiceAccountRequest struct {
+	Username    string `json:"username"`
+	Email       string `json:"email"` // Opsional
+	DisplayName string `json:"display_name"`
+}
+
+// CreateServiceAccount menangani pembuatan service account oleh admin.
+// Service account tidak bisa login; API key untuknya dibuat lewat POST /:id/api-keys.
+func (h *UserHandler) CreateServiceAccount(c *fiber.Ctx) error {
+	req := new(createServiceAccountRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	account, err := h.userInteractor.CreateServiceAccount(req.Username, req.Email, req.DisplayName)
+	if err != nil {
+		log.Printf("Kesalahan CreateServiceAccount di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal membuat service account"})
+	}
+	return c.Status(fiber.StatusCreated).JSON(account)
+}
+
 // GetUserByID menangani pengambilan pengguna berdasarkan ID dari permintaan HTTP GET.
 func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 	idStr := c.Params("id")
-	// Konversi ID dari string parameter ke uint
-	id, err := strconv.ParseUint(idStr, 10, 64)
+	// Konversi ID dari string parameter ke UUID
+	id, err := uuid.Parse(idStr)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
 	}
 
 	// Panggil use case untuk mendapatkan pengguna
-	user, err := h.userInteractor.GetUserByID(uint(id))
+	user, err := h.users(c).GetUserByID(id)
 	if err != nil {
 		log.Printf("Kesalahan GetUserByID di handler: %v", err)
 		// Jika pengguna tidak ditemukan, kembalikan 404 Not Found
@@ -63,7 +106,7 @@ func (h *UserHandler) GetUserByID(c *fiber.Ctx) error {
 // GetAllUsers menangani pengambilan semua pengguna dari permintaan HTTP GET.
 func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
 	// Panggil use case untuk mendapatkan semua pengguna
-	users, err := h.userInteractor.GetAllUsers()
+	users, err := h.users(c).GetAllUsers()
 	if err != nil {
 		log.Printf("Kesalahan GetAllUsers di handler: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal mengambil daftar pengguna"})
@@ -75,7 +118,7 @@ func (h *UserHandler) GetAllUsers(c *fiber.Ctx) error {
 // UpdateUser menangani pembaruan pengguna yang ada dari permintaan HTTP PUT.
 func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 64)
+	id, err := uuid.Parse(idStr)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
 	}
@@ -88,7 +131,13 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 	}
 
 	// Panggil use case untuk memperbarui pengguna
-	updatedUser, err := h.userInteractor.UpdateUser(uint(id), user)
+	updatedUser, err := h.users(c).UpdateUser(id, user)
+	switch {
+	case errors.Is(err, interactors.ErrUserEmailRequired):
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": err.Error()})
+	case errors.Is(err, interactors.ErrUserEmailShared):
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+	}
 	if err != nil {
 		log.Printf("Kesalahan UpdateUser di handler: %v", err)
 		// Sesuaikan status error berdasarkan jenis error dari use case
@@ -101,13 +150,13 @@ func (h *UserHandler) UpdateUser(c *fiber.Ctx) error {
 // DeleteUser menangani penghapusan pengguna berdasarkan ID dari permintaan HTTP DELETE.
 func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	idStr := c.Params("id")
-	id, err := strconv.ParseUint(idStr, 10, 64)
+	id, err := uuid.Parse(idStr)
 	if err != nil {
 		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
 	}
 
 	// Panggil use case untuk menghapus pengguna
-	err = h.userInteractor.DeleteUser(uint(id))
+	err = h.users(c).DeleteUser(id)
 	if err != nil {
 		log.Printf("Kesalahan DeleteUser di handler: %v", err)
 		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menghapus pengguna"})
@@ -115,3 +164,40 @@ func (h *UserHandler) DeleteUser(c *fiber.Ctx) error {
 	// Kembalikan status 204 No Content untuk penghapusan yang berhasil
 	return c.Status(fiber.StatusNoContent).SendString("")
 }
+
+// userAttributesRequest adalah body permintaan mengganti atribut pengguna.
+type userAttributesRequest struct {
+	Attributes map[string]any `json:"attributes"`
+}
+
+// SetUserAttributes menangani penggantian atribut bebas pengguna yang dipakai kebijakan ABAC (admin).
+func (h *UserHandler) SetUserAttributes(c *fiber.Ctx) error {
+	id, err := uuid.Parse(c.Params("id"))
+	if err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "ID pengguna tidak valid"})
+	}
+
+	req := new(userAttributesRequest)
+	if err := c.BodyParser(req); err != nil {
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Permintaan tidak valid"})
+	}
+
+	user, err := h.userInteractor.SetAttributes(id, req.Attributes)
+	if errors.Is(err, interactors.ErrUserNotFound) {
+		return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": "Pengguna tidak ditemukan"})
+	}
+	if err != nil {
+		log.Printf("Kesalahan SetUserAttributes di handler: %v", err)
+		return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memperbarui atribut pengguna"})
+	}
+	return c.JSON(user)
+}
+
+// users mengembalikan UserInteractor yang dibatasi ke organisasi permintaan.
+// Tanpa organisasi (hanya mungkin untuk superuser) semua pengguna bisa diakses.
+func (h *UserHandler) users(c *fiber.Ctx) *interactors.UserInteractor {
+	if organizationID, ok := currentOrganizationID(c); ok {
+		return h.userInteractor.ForOrganization(organizationID)
+	}
+	return h.userInteractor
+}
@@ -1,24 +1,145 @@
 package middlewares
 
 import (
+	"errors"
 	"log"
+	"strings"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/services"
+	"fiber-usermanagement/internal/usecase/interactors"
 
 	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
 )
 
-// AuthMiddleware adalah contoh middleware untuk autentikasi.
-// Dalam aplikasi produksi nyata, Anda akan mengimplementasikan logika yang lebih kompleks
-// seperti memverifikasi JSON Web Token (JWT), memeriksa sesi, atau mengintegrasikan dengan
-// penyedia identitas eksternal.
-func AuthMiddleware(c *fiber.Ctx) error {
-	// Contoh sederhana: Periksa header "Authorization"
-	// Anda harus mengganti logika ini dengan metode autentikasi yang aman dan sesuai.
-	authHeader := c.Get("Authorization")
-	if authHeader == "" || authHeader != "Bearer my-secret-token" { // Ganti "my-secret-token" dengan token nyata dari database/konfigurasi
-		log.Println("Akses tidak sah: Token tidak ada atau tidak valid")
+// NewAuthMiddleware membuat middleware autentikasi yang menerima token akses dari
+// "Authorization: Bearer <token>" atau API key dari header yang sama maupun "X-API-Key".
+// Token akses hanya diterima selama sesi login-nya belum dicabut; token OAuth yang diterbitkan atas nama
+// pengguna juga terikat ke sesi sehingga ikut tercabut, sedangkan token client_credentials yang dicabut
+// lewat /oauth/revoke ditolak sampai kedaluwarsa. Jika valid, ID pengguna dan
+// klaim disimpan di c.Locals untuk dipakai middleware dan handler berikutnya.
+func NewAuthMiddleware(tokens *interactors.TokenInteractor, apiKeys *interactors.APIKeyInteractor) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		token, ok := bearerToken(c.Get(fiber.HeaderAuthorization))
+		if !ok {
+			token = strings.TrimSpace(c.Get(HeaderAPIKey))
+			if token == "" {
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+			}
+		}
+
+		if apiKeys.IsAPIKey(token) {
+			return authenticateAPIKey(c, apiKeys, token)
+		}
+
+		claims, err := tokens.ValidateAccessToken(c.UserContext(), token)
+		if err != nil {
+			switch {
+			case errors.Is(err, interactors.ErrSessionRevoked):
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Sesi sudah berakhir"})
+			case errors.Is(err, interactors.ErrInvalidToken), errors.Is(err, interactors.ErrTokenRevoked):
+				log.Printf("Akses tidak sah: %v", err)
+				return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+			default:
+				log.Printf("Gagal memeriksa token: %v", err)
+				return c.Status(fiber.StatusServiceUnavailable).JSON(fiber.Map{"error": "Gagal memeriksa sesi"})
+			}
+		}
+
+		c.Locals(LocalsUserID, claims.UserID)
+		c.Locals(LocalsClaims, claims)
+
+		// Jika autentikasi berhasil, lanjutkan ke handler berikutnya dalam rantai middleware/rute.
+		return c.Next()
+	}
+}
+
+// authenticateAPIKey memverifikasi API key dan menyimpan klaim yang dibatasi scope kunci.
+func authenticateAPIKey(c *fiber.Ctx, apiKeys *interactors.APIKeyInteractor, token string) error {
+	key, user, err := apiKeys.Authenticate(token, c.IP())
+	if err != nil {
+		if !errors.Is(err, interactors.ErrInvalidAPIKey) {
+			log.Printf("Gagal memeriksa API key: %v", err)
+		}
 		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
 	}
 
-	// Jika autentikasi berhasil, lanjutkan ke handler berikutnya dalam rantai middleware/rute.
+	claims := &services.TokenClaims{
+		ID:          key.ID.String(),
+		UserID:      user.ID,
+		Username:    user.Username,
+		IsSuperuser: user.IsSuperuser,
+		IssuedAt:    key.CreatedAt,
+		APIKeyID:    key.ID,
+		Scopes:      key.Scopes,
+	}
+	if key.ExpiresAt != nil {
+		claims.ExpiresAt = *key.ExpiresAt
+	}
+	if key.OrganizationID != nil {
+		claims.OrganizationID = *key.OrganizationID
+	}
+
+	c.Locals(LocalsUserID, claims.UserID)
+	c.Locals(LocalsClaims, claims)
+	return c.Next()
+}
+
+// RequireSuperuser menolak permintaan dari pengguna yang bukan superuser.
+// API key milik superuser hanya diterima jika memiliki scope penuh (entities.ScopeAll) dan tidak terikat
+// ke organisasi, karena rute superuser bekerja lintas tenant.
+// Harus dipasang setelah middleware autentikasi.
+func RequireSuperuser(c *fiber.Ctx) error {
+	claims := ClaimsFromContext(c)
+	if claims == nil || !claims.IsSuperuser || !claims.HasScope(entities.ScopeAll) || claims.OrganizationID != uuid.Nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Akses ditolak"})
+	}
 	return c.Next()
 }
+
+// RequireSession menolak permintaan yang diautentikasi dengan API key atau token OAuth.
+// Dipakai untuk rute pengelolaan kredensial (MFA, passkey, sesi, API key, consent OAuth) agar
+// kredensial turunan yang bocor tidak bisa dipakai untuk membuat kredensial baru.
+func RequireSession(c *fiber.Ctx) error {
+	claims := ClaimsFromContext(c)
+	if claims == nil || claims.IsDelegated() {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Rute ini memerlukan login interaktif"})
+	}
+	return c.Next()
+}
+
+// RequireAPIKey menolak permintaan yang tidak diautentikasi dengan API key, misalnya untuk rute
+// provisioning SCIM yang hanya boleh dipanggil klien mesin lewat service account-nya sendiri.
+func RequireAPIKey(c *fiber.Ctx) error {
+	claims := ClaimsFromContext(c)
+	if claims == nil || !claims.IsAPIKey() {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Rute ini memerlukan API key"})
+	}
+	return c.Next()
+}
+
+// RequireDelegated menolak permintaan dengan token login, misalnya untuk rute yang hanya dipanggil layanan
+// lain lewat API key atau token OAuth sehingga aksesnya selalu dibatasi scope.
+func RequireDelegated(c *fiber.Ctx) error {
+	claims := ClaimsFromContext(c)
+	if claims == nil || !claims.IsDelegated() {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Rute ini memerlukan API key atau token OAuth"})
+	}
+	return c.Next()
+}
+
+// ClaimsFromContext mengembalikan klaim token dari permintaan yang sudah terautentikasi.
+func ClaimsFromContext(c *fiber.Ctx) *services.TokenClaims {
+	claims, _ := c.Locals(LocalsClaims).(*services.TokenClaims)
+	return claims
+}
+
+// bearerToken mengambil token dari nilai header Authorization dengan skema Bearer.
+func bearerToken(header string) (string, bool) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", false
+	}
+	return strings.TrimSpace(token), true
+}
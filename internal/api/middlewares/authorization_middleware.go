@@ -0,0 +1,55 @@
+package middlewares
+
+import (
+	"log"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// Authorizer membuat middleware pemeriksaan permission per rute.
+type Authorizer struct {
+	authz *interactors.AuthorizationInteractor
+}
+
+// NewAuthorizer membuat instance baru dari Authorizer.
+func NewAuthorizer(authz *interactors.AuthorizationInteractor) *Authorizer {
+	return &Authorizer{authz: authz}
+}
+
+// Require menolak permintaan yang tidak boleh memakai permission tersebut.
+// Harus dipasang setelah middleware autentikasi, dan setelah TenantResolver.Require untuk rute organisasi
+// agar permission diperiksa terhadap Role pengguna di organisasi tersebut. Kebijakan ABAC ikut dievaluasi
+// dengan parameter :id rute sebagai ID resource dan IP klien sebagai env.ip.
+func (a *Authorizer) Require(permission string) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := ClaimsFromContext(c)
+		if claims == nil {
+			return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+		}
+
+		req := interactors.AccessRequest{
+			Permission:  permission,
+			ResourceID:  c.Params("id"),
+			Environment: map[string]any{"ip": c.IP()},
+		}
+		if organizationID, ok := OrganizationFromContext(c); ok {
+			req.OrganizationID = organizationID
+		} else if claims.OrganizationID != uuid.Nil {
+			// Kredensial yang terikat ke organisasi tidak pernah memakai Role global pemiliknya
+			req.OrganizationID = claims.OrganizationID
+		}
+
+		decision, err := a.authz.Decide(claims, req)
+		if err != nil {
+			log.Printf("Gagal memeriksa permission %s untuk %s: %v", permission, claims.UserID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal memeriksa akses"})
+		}
+		if !decision.Allowed {
+			return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Akses ditolak", "required_permission": permission})
+		}
+		return c.Next()
+	}
+}
@@ -1,16 +1,214 @@
 package middlewares
 
-import "github.com/gofiber/fiber/v2"
+import (
+	"regexp"
+	"strconv"
+	"strings"
 
-func CorsMiddleware(c *fiber.Ctx) error {
-	c.Set("Access-Control-Allow-Origin", "*")
-	c.Set("Access-Control-Allow-Credentials", "true")
-	c.Set("Access-Control-Allow-Headers", "Content-Type, Content-Length, Accept-Encoding, X-CSRF-Token, Authorization, accept, origin, Cache-Control, X-Requested-With")
-	c.Set("Access-Control-Allow-Methods", "POST, OPTIONS, GET, PUT, DELETE")
+	"fiber-usermanagement/internal/config"
 
-	if c.Method() == "OPTIONS" {
+	"github.com/gofiber/fiber/v2"
+)
+
+// corsPolicy adalah bentuk terkompilasi dari config.CorsConfig yang dipakai oleh middleware.
+type corsPolicy struct {
+	allowAllOrigins  bool
+	origins          map[string]struct{}
+	originPatterns   []*regexp.Regexp
+	allowedMethods   map[string]struct{}
+	allowAllHeaders  bool
+	allowedHeaders   map[string]struct{}
+	methods          string
+	headers          string
+	exposedHeaders   string
+	allowCredentials bool
+	maxAge           int
+}
+
+// NewCorsMiddleware membuat middleware CORS berdasarkan konfigurasi.
+// Origin yang diizinkan selalu dipantulkan kembali (bukan "*") sehingga kombinasi dengan
+// Access-Control-Allow-Credentials tetap diterima oleh browser.
+func NewCorsMiddleware(cfg config.CorsConfig) fiber.Handler {
+	policy := newCorsPolicy(cfg)
+
+	return func(c *fiber.Ctx) error {
+		origin := c.Get(fiber.HeaderOrigin)
+		c.Vary(fiber.HeaderOrigin)
+
+		// Bukan permintaan lintas origin, tidak ada header CORS yang perlu ditambahkan
+		if origin == "" {
+			return c.Next()
+		}
+
+		preflight := c.Method() == fiber.MethodOptions && c.Get(fiber.HeaderAccessControlRequestMethod) != ""
+		if !policy.isOriginAllowed(origin) {
+			// Tanpa header CORS browser akan memblokir respons
+			if preflight {
+				return c.SendStatus(fiber.StatusNoContent)
+			}
+			return c.Next()
+		}
+
+		if preflight {
+			return policy.handlePreflight(c, origin)
+		}
+
+		policy.setOriginHeaders(c, origin)
+		if policy.exposedHeaders != "" {
+			c.Set(fiber.HeaderAccessControlExposeHeaders, policy.exposedHeaders)
+		}
+
+		return c.Next()
+	}
+}
+
+// handlePreflight menjawab permintaan OPTIONS preflight tanpa meneruskannya ke handler.
+func (p *corsPolicy) handlePreflight(c *fiber.Ctx, origin string) error {
+	c.Vary(fiber.HeaderAccessControlRequestMethod, fiber.HeaderAccessControlRequestHeaders)
+
+	requestMethod := strings.ToUpper(c.Get(fiber.HeaderAccessControlRequestMethod))
+	if _, ok := p.allowedMethods[requestMethod]; !ok {
 		return c.SendStatus(fiber.StatusNoContent)
 	}
 
-	return c.Next()
+	requestHeaders := c.Get(fiber.HeaderAccessControlRequestHeaders)
+	if !p.areHeadersAllowed(requestHeaders) {
+		return c.SendStatus(fiber.StatusNoContent)
+	}
+
+	p.setOriginHeaders(c, origin)
+	c.Set(fiber.HeaderAccessControlAllowMethods, p.methods)
+
+	if p.allowAllHeaders {
+		// "*" tidak berlaku untuk permintaan dengan kredensial, jadi header yang diminta dipantulkan
+		if requestHeaders != "" {
+			c.Set(fiber.HeaderAccessControlAllowHeaders, requestHeaders)
+		}
+	} else if p.headers != "" {
+		c.Set(fiber.HeaderAccessControlAllowHeaders, p.headers)
+	}
+
+	if p.maxAge > 0 {
+		c.Set(fiber.HeaderAccessControlMaxAge, strconv.Itoa(p.maxAge))
+	}
+
+	return c.SendStatus(fiber.StatusNoContent)
+}
+
+// setOriginHeaders menulis header origin dan kredensial untuk origin yang sudah divalidasi.
+func (p *corsPolicy) setOriginHeaders(c *fiber.Ctx, origin string) {
+	if p.allowAllOrigins && !p.allowCredentials {
+		c.Set(fiber.HeaderAccessControlAllowOrigin, "*")
+	} else {
+		c.Set(fiber.HeaderAccessControlAllowOrigin, origin)
+	}
+
+	if p.allowCredentials {
+		c.Set(fiber.HeaderAccessControlAllowCredentials, "true")
+	}
+}
+
+// isOriginAllowed memeriksa origin terhadap daftar origin persis dan pola wildcard.
+func (p *corsPolicy) isOriginAllowed(origin string) bool {
+	if p.allowAllOrigins {
+		return true
+	}
+
+	origin = strings.ToLower(origin)
+	if _, ok := p.origins[origin]; ok {
+		return true
+	}
+
+	for _, pattern := range p.originPatterns {
+		if pattern.MatchString(origin) {
+			return true
+		}
+	}
+	return false
+}
+
+// areHeadersAllowed memeriksa setiap header dari Access-Control-Request-Headers.
+func (p *corsPolicy) areHeadersAllowed(requestHeaders string) bool {
+	if p.allowAllHeaders || requestHeaders == "" {
+		return true
+	}
+
+	for _, header := range strings.Split(requestHeaders, ",") {
+		header = strings.ToLower(strings.TrimSpace(header))
+		if header == "" {
+			continue
+		}
+		if _, ok := p.allowedHeaders[header]; !ok {
+			return false
+		}
+	}
+	return true
+}
+
+// newCorsPolicy mengompilasi konfigurasi CORS menjadi struktur yang siap dipakai per permintaan.
+func newCorsPolicy(cfg config.CorsConfig) *corsPolicy {
+	policy := &corsPolicy{
+		origins:        make(map[string]struct{}),
+		allowedMethods: make(map[string]struct{}),
+		allowedHeaders: make(map[string]struct{}),
+		exposedHeaders: strings.Join(cfg.ExposedHeaders, ", "),
+	}
+
+	if cfg.AllowCredentials != nil {
+		policy.allowCredentials = *cfg.AllowCredentials
+	}
+	if cfg.MaxAge != nil {
+		policy.maxAge = *cfg.MaxAge
+	}
+
+	for _, origin := range cfg.AllowedOrigins {
+		origin = strings.ToLower(strings.TrimSuffix(strings.TrimSpace(origin), "/"))
+		switch {
+		case origin == "":
+			continue
+		case origin == "*":
+			policy.allowAllOrigins = true
+		case strings.Contains(origin, "*"):
+			policy.originPatterns = append(policy.originPatterns, compileOriginPattern(origin))
+		default:
+			policy.origins[origin] = struct{}{}
+		}
+	}
+
+	methods := make([]string, 0, len(cfg.AllowedMethods))
+	for _, method := range cfg.AllowedMethods {
+		method = strings.ToUpper(strings.TrimSpace(method))
+		if method == "" {
+			continue
+		}
+		policy.allowedMethods[method] = struct{}{}
+		methods = append(methods, method)
+	}
+	policy.methods = strings.Join(methods, ", ")
+
+	headers := make([]string, 0, len(cfg.AllowedHeaders))
+	for _, header := range cfg.AllowedHeaders {
+		header = strings.TrimSpace(header)
+		if header == "" {
+			continue
+		}
+		if header == "*" {
+			policy.allowAllHeaders = true
+			continue
+		}
+		policy.allowedHeaders[strings.ToLower(header)] = struct{}{}
+		headers = append(headers, header)
+	}
+	policy.headers = strings.Join(headers, ", ")
+
+	return policy
+}
+
+// compileOriginPattern mengubah pola seperti "https://*.example.com" atau "http://localhost:*"
+// menjadi regexp. Wildcard hanya cocok dengan karakter host/port sehingga tidak bisa melompati
+// batas skema atau path.
+func compileOriginPattern(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+	quoted = strings.ReplaceAll(quoted, `\*`, `[a-z0-9.-]+`)
+	return regexp.MustCompile("^" + quoted + "$")
 }
@@ -0,0 +1,49 @@
+package middlewares
+
+import (
+	"errors"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+)
+
+// HeaderImpersonatedBy dikirim di setiap respons untuk token impersonasi, berisi username pelakunya,
+// agar aplikasi selalu bisa menampilkan penanda impersonasi.
+const HeaderImpersonatedBy = "X-Impersonated-By"
+
+// NewImpersonationAuditor membuat middleware yang mencatat setiap permintaan dengan token impersonasi
+// ke jejak audit atas nama pelakunya, termasuk permintaan yang ditolak. Permintaan biasa diteruskan
+// tanpa dicatat. Harus dipasang setelah middleware autentikasi.
+func NewImpersonationAuditor(impersonation *interactors.ImpersonationInteractor) fiber.Handler {
+	return func(c *fiber.Ctx) error {
+		claims := ClaimsFromContext(c)
+		if claims == nil || !claims.IsImpersonation() {
+			return c.Next()
+		}
+
+		c.Set(HeaderImpersonatedBy, claims.ActorUsername)
+		err := c.Next()
+
+		status := c.Response().StatusCode()
+		var fiberErr *fiber.Error
+		if errors.As(err, &fiberErr) {
+			status = fiberErr.Code
+		} else if err != nil {
+			status = fiber.StatusInternalServerError
+		}
+		impersonation.RecordRequest(claims, c.Method(), c.OriginalURL(), status)
+		return err
+	}
+}
+
+// RejectImpersonation menolak permintaan dengan token impersonasi. Dipasang pada tindakan sensitif
+// seperti pengelolaan MFA, passkey dan kredensial lain yang hanya boleh dilakukan pemilik akun sendiri.
+// Harus dipasang setelah middleware autentikasi.
+func RejectImpersonation(c *fiber.Ctx) error {
+	claims := ClaimsFromContext(c)
+	if claims != nil && claims.IsImpersonation() {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Tindakan ini tidak diizinkan selama impersonasi"})
+	}
+	return c.Next()
+}
@@ -0,0 +1,17 @@
+package middlewares
+
+// Kunci c.Locals yang dipakai bersama oleh middleware dan handler.
+const (
+	// LocalsUserID menyimpan ID pengguna yang sudah terautentikasi (uuid.UUID).
+	LocalsUserID = "user_id"
+	// LocalsClaims menyimpan klaim token akses yang sudah diverifikasi (*services.TokenClaims).
+	LocalsClaims = "claims"
+	// LocalsOrganizationID menyimpan ID organisasi (tenant) tempat permintaan berjalan (uuid.UUID).
+	LocalsOrganizationID = "organization_id"
+)
+
+// HeaderAPIKey adalah header alternatif untuk mengirim API key selain Authorization: Bearer.
+const HeaderAPIKey = "X-API-Key"
+
+// HeaderOrganization memilih organisasi (ID atau slug) untuk permintaan yang tidak memakai subdomain.
+const HeaderOrganization = "X-Organization"
@@ -0,0 +1,112 @@
+package middlewares
+
+import (
+	"errors"
+	"log"
+	"strings"
+
+	"fiber-usermanagement/internal/usecase/interactors"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+)
+
+// TenantResolver menentukan organisasi (tenant) tempat permintaan berjalan.
+//
+// Data yang dibatasi per organisasi adalah pengguna (UserRepository.InOrganization), keanggotaan beserta
+// Role organisasi, dan undangan. Data lain tidak dimiliki tenant: sesi, API key, passkey, identitas terhubung
+// dan permintaan role milik akun itu sendiri (rute /me, dibatasi ke pemanggil), sedangkan grup, Role global,
+// jejak audit, kebijakan dan kampanye tinjauan adalah data lintas tenant yang hanya bisa diakses superuser
+// dengan kredensial yang tidak terikat ke organisasi (RequireSuperuser, RejectOrganizationBound).
+type TenantResolver struct {
+	organizations *interactors.OrganizationInteractor
+	baseDomain    string // Misalnya "example.com" agar acme.example.com berarti organisasi "acme"; kosong menonaktifkan subdomain
+}
+
+// NewTenantResolver membuat instance baru dari TenantResolver.
+func NewTenantResolver(organizations *interactors.OrganizationInteractor, baseDomain string) *TenantResolver {
+	return &TenantResolver{organizations: organizations, baseDomain: strings.ToLower(strings.TrimPrefix(baseDomain, "."))}
+}
+
+// Require menetapkan organisasi permintaan dan memastikan pengguna anggotanya.
+// Urutan sumber: organisasi yang terikat pada kredensial (API key), header X-Organization, lalu subdomain.
+// Superuser boleh masuk ke organisasi mana pun dan boleh berjalan tanpa organisasi (lintas tenant).
+// Harus dipasang setelah middleware autentikasi.
+func (t *TenantResolver) Require(c *fiber.Ctx) error {
+	claims := ClaimsFromContext(c)
+	if claims == nil {
+		return c.Status(fiber.StatusUnauthorized).JSON(fiber.Map{"error": "Tidak sah"})
+	}
+
+	identifier := strings.TrimSpace(c.Get(HeaderOrganization))
+	if identifier == "" {
+		identifier = t.subdomain(c.Hostname())
+	}
+
+	var organizationID uuid.UUID
+	switch {
+	case claims.OrganizationID != uuid.Nil:
+		organizationID = claims.OrganizationID
+		if identifier != "" {
+			organization, err := t.organizations.Resolve(identifier)
+			if err != nil || organization.ID != organizationID {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Kredensial ini terikat ke organisasi lain"})
+			}
+		}
+	case identifier != "":
+		organization, err := t.organizations.Resolve(identifier)
+		if err != nil {
+			if errors.Is(err, interactors.ErrOrganizationNotFound) {
+				return c.Status(fiber.StatusNotFound).JSON(fiber.Map{"error": err.Error()})
+			}
+			log.Printf("Gagal menentukan organisasi %q: %v", identifier, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menentukan organisasi"})
+		}
+		organizationID = organization.ID
+	case claims.IsSuperuser:
+		return c.Next() // Tanpa organisasi: superuser bekerja lintas tenant
+	default:
+		return c.Status(fiber.StatusBadRequest).JSON(fiber.Map{"error": "Organisasi wajib dipilih lewat header " + HeaderOrganization + " atau subdomain"})
+	}
+
+	if !claims.IsSuperuser {
+		if _, err := t.organizations.Membership(organizationID, claims.UserID); err != nil {
+			if errors.Is(err, interactors.ErrNotOrganizationMember) {
+				return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": err.Error()})
+			}
+			log.Printf("Gagal memeriksa keanggotaan %s di organisasi %s: %v", claims.UserID, organizationID, err)
+			return c.Status(fiber.StatusInternalServerError).JSON(fiber.Map{"error": "Gagal menentukan organisasi"})
+		}
+	}
+
+	c.Locals(LocalsOrganizationID, organizationID)
+	return c.Next()
+}
+
+// subdomain mengambil label pertama dari host di bawah baseDomain, misalnya "acme" dari acme.example.com.
+func (t *TenantResolver) subdomain(host string) string {
+	if t.baseDomain == "" {
+		return ""
+	}
+	label, ok := strings.CutSuffix(strings.ToLower(host), "."+t.baseDomain)
+	if !ok || label == "" || strings.Contains(label, ".") {
+		return ""
+	}
+	return label
+}
+
+// RejectOrganizationBound menolak kredensial yang terikat ke satu organisasi pada rute lintas tenant,
+// misalnya provisioning SCIM yang membuat dan menonaktifkan akun secara global.
+func RejectOrganizationBound(c *fiber.Ctx) error {
+	claims := ClaimsFromContext(c)
+	if claims == nil || claims.OrganizationID != uuid.Nil {
+		return c.Status(fiber.StatusForbidden).JSON(fiber.Map{"error": "Kredensial ini terikat ke organisasi dan tidak bisa dipakai di luar organisasi tersebut"})
+	}
+	return c.Next()
+}
+
+// OrganizationFromContext mengembalikan organisasi yang sudah ditetapkan oleh TenantResolver.
+func OrganizationFromContext(c *fiber.Ctx) (uuid.UUID, bool) {
+	id, ok := c.Locals(LocalsOrganizationID).(uuid.UUID)
+	return id, ok
+}
@@ -2,28 +2,267 @@ package routes
 
 import (
 	"fiber-usermanagement/internal/api/handlers"
+	"fiber-usermanagement/internal/api/middlewares"
+	"fiber-usermanagement/internal/domain/entities"
 
 	"github.com/gofiber/fiber/v2"
 )
 
 type RouteConfig struct {
-	App         *fiber.App
-	UserHandler *handlers.UserHandler
+	App             *fiber.App
+	UserHandler     *handlers.UserHandler
+	AuthHandler     *handlers.AuthHandler
+	MFAHandler      *handlers.MFAHandler
+	PasskeyHandler  *handlers.PasskeyHandler
+	SessionHandler  *handlers.SessionHandler
+	APIKeyHandler   *handlers.APIKeyHandler
+	OAuthHandler    *handlers.OAuthHandler
+	OIDCHandler     *handlers.OIDCHandler
+	SocialHandler   *handlers.SocialLoginHandler
+	SAMLHandler     *handlers.SAMLHandler
+	SCIMHandler     *handlers.SCIMHandler
+	OrgHandler      *handlers.OrganizationHandler
+	InviteHandler   *handlers.InvitationHandler
+	GroupHandler    *handlers.GroupHandler
+	RoleHandler     *handlers.RoleHandler
+	PolicyHandler   *handlers.AccessPolicyHandler
+	GrantHandler    *handlers.RoleGrantHandler
+	RequestHandler  *handlers.RoleRequestHandler
+	ApprovalHandler *handlers.ApprovalHandler
+	AuditHandler    *handlers.AuditHandler
+	SoDHandler      *handlers.SoDHandler
+	ReviewHandler   *handlers.AccessReviewHandler
+	ImpersonHandler *handlers.ImpersonationHandler
+	CorsMiddleware  fiber.Handler
+	AuthMiddleware  fiber.Handler
+	RateLimiter     *middlewares.RateLimiter
+	Authorizer      *middlewares.Authorizer
+	TenantResolver  *middlewares.TenantResolver
+	ImpersonAudit   fiber.Handler // Mencatat setiap permintaan dengan token impersonasi ke jejak audit
 }
 
 func (c *RouteConfig) Setup() {
+	c.SetupMiddleware()
+	c.SetupOIDCRoute() // Sebelum rute tamu agar /userinfo dan /jwks.json tidak tertangkap GET /:id
 	c.SetupGuestRoute()
 	c.SetupAuthRoute()
+	c.SetupSCIMRoute()
+}
+
+func (c *RouteConfig) SetupMiddleware() {
+	c.App.Use(c.CorsMiddleware)       // CORS harus dipasang sebelum rute agar preflight OPTIONS terjawab
+	c.App.Use(c.RateLimiter.Global()) // Setelah CORS agar respons 429 tetap terbaca oleh browser
+}
+
+func (c *RouteConfig) SetupOIDCRoute() {
+	auth := []fiber.Handler{c.AuthMiddleware, c.RateLimiter.PerUser(), c.ImpersonAudit}
+
+	c.App.Get("/.well-known/openid-configuration", c.OIDCHandler.Discovery) // GET /.well-known/openid-configuration untuk dokumen discovery OpenID Connect
+	c.App.Get("/jwks.json", c.OIDCHandler.JWKS)                             // GET /jwks.json untuk kunci publik penanda tangan token
+	c.App.Get("/userinfo", with(auth, c.OIDCHandler.UserInfo)...)           // GET /userinfo untuk klaim pengguna pemilik token
+	c.App.Post("/userinfo", with(auth, c.OIDCHandler.UserInfo)...)          // POST /userinfo untuk klaim pengguna pemilik token
 }
 
 func (c *RouteConfig) SetupGuestRoute() {
-	c.App.Post("/", c.UserHandler.CreateUser)    // POST /api/v1/users untuk membuat pengguna baru
-	c.App.Get("/:id", c.UserHandler.GetUserByID) // GET /api/v1/users/:id untuk mendapatkan pengguna berdasarkan ID
+	c.App.Post("/auth/login", c.RateLimiter.Route("login"), c.AuthHandler.Login)                                   // POST /auth/login untuk login dengan username/email dan password
+	c.App.Post("/auth/mfa/verify", c.RateLimiter.Route("mfa"), c.AuthHandler.VerifyMFA)                            // POST /auth/mfa/verify untuk langkah kedua login (TOTP/kode pemulihan)
+	c.App.Post("/auth/mfa/enroll", c.RateLimiter.Route("mfa"), c.AuthHandler.BeginMFAEnrollment)                   // POST /auth/mfa/enroll untuk enrollment MFA yang diwajibkan saat login
+	c.App.Post("/auth/mfa/enroll/confirm", c.RateLimiter.Route("mfa"), c.AuthHandler.ConfirmMFAEnrollment)         // POST /auth/mfa/enroll/confirm untuk menyelesaikan enrollment dan login
+	c.App.Post("/auth/mfa/passkey/begin", c.RateLimiter.Route("mfa"), c.AuthHandler.BeginPasskeyMFA)               // POST /auth/mfa/passkey/begin untuk memulai passkey sebagai langkah kedua login
+	c.App.Post("/auth/mfa/passkey/finish", c.RateLimiter.Route("mfa"), c.AuthHandler.VerifyPasskeyMFA)             // POST /auth/mfa/passkey/finish untuk menyelesaikan langkah kedua login dengan passkey
+	c.App.Post("/auth/passkey/login/begin", c.RateLimiter.Route("passkey_login"), c.AuthHandler.BeginPasskeyLogin) // POST /auth/passkey/login/begin untuk memulai login tanpa password
+	c.App.Post("/auth/passkey/login/finish", c.RateLimiter.Route("passkey_login"), c.AuthHandler.PasskeyLogin)     // POST /auth/passkey/login/finish untuk menyelesaikan login tanpa password
+
+	c.App.Get("/auth/social/providers", c.SocialHandler.ListProviders)                                 // GET /auth/social/providers untuk melihat provider login sosial
+	c.App.Get("/auth/social/:provider/login", c.RateLimiter.Route("sso"), c.SocialHandler.BeginLogin)  // GET /auth/social/:provider/login untuk memulai login sosial
+	c.App.Get("/auth/social/:provider/callback", c.RateLimiter.Route("sso"), c.SocialHandler.Callback) // GET /auth/social/:provider/callback untuk menyelesaikan login atau penghubungan akun sosial
+
+	c.App.Get("/auth/saml/connections", c.SAMLHandler.ListConnections)                              // GET /auth/saml/connections untuk melihat koneksi SSO SAML
+	c.App.Get("/auth/saml/:connection/metadata", c.SAMLHandler.Metadata)                            // GET /auth/saml/:connection/metadata untuk metadata service provider yang didaftarkan di IdP
+	c.App.Get("/auth/saml/:connection/login", c.RateLimiter.Route("sso"), c.SAMLHandler.BeginLogin) // GET /auth/saml/:connection/login untuk memulai login SP-initiated
+	c.App.Post("/auth/saml/:connection/acs", c.RateLimiter.Route("sso"), c.SAMLHandler.ACS)         // POST /auth/saml/:connection/acs untuk menerima assertion dari IdP (SP- maupun IdP-initiated)
+
+	c.App.Post("/oauth/token", c.RateLimiter.Route("oauth_token"), c.OAuthHandler.Token)           // POST /oauth/token untuk token endpoint OAuth 2.0 (klien diautentikasi di handler)
+	c.App.Post("/oauth/introspect", c.RateLimiter.Route("oauth_token"), c.OAuthHandler.Introspect) // POST /oauth/introspect untuk introspeksi token oleh resource server (RFC 7662)
+	c.App.Post("/oauth/revoke", c.RateLimiter.Route("oauth_token"), c.OAuthHandler.Revoke)         // POST /oauth/revoke untuk mencabut token akses, refresh token atau API key (RFC 7009)
+
+	c.App.Get("/invitations/preview", c.RateLimiter.Route("invitation"), c.InviteHandler.PreviewInvitation) // GET /invitations/preview?token= untuk melihat undangan organisasi sebelum diterima
+	c.App.Post("/invitations/accept", c.RateLimiter.Route("invitation"), c.InviteHandler.AcceptInvitation)  // POST /invitations/accept untuk menerima undangan sekaligus membuat akun baru
+
+	c.App.Post("/", c.UserHandler.CreateUser) // POST /api/v1/users untuk membuat pengguna baru
 }
 
 func (c *RouteConfig) SetupAuthRoute() {
-	c.App.Put("/:id", c.UserHandler.UpdateUser)    // PUT /api/v1/users/:id untuk memperbarui pengguna
-	c.App.Delete("/:id", c.UserHandler.DeleteUser) // DELETE /api/v1/users/:id untuk menghapus pengguna
-	c.App.Get("/", c.UserHandler.GetAllUsers)      // GET /api/v1/users untuk mendapatkan semua pengguna
+	auth := []fiber.Handler{c.AuthMiddleware, c.RateLimiter.PerUser(), c.ImpersonAudit}
+	session := with(auth, middlewares.RequireSession)             // Pengelolaan kredensial tidak boleh memakai API key
+	credentials := with(session, middlewares.RejectImpersonation) // Tindakan sensitif dan keputusan persetujuan hanya oleh pemilik akun, tidak selama impersonasi
+	admin := with(auth, middlewares.RequireSuperuser)
+	tenant := with(auth, c.TenantResolver.Require) // Permission diperiksa terhadap Role di organisasi aktif
+
+	c.App.Post("/me/mfa/totp", with(credentials, c.MFAHandler.BeginTOTPEnrollment)...)               // POST /me/mfa/totp untuk memulai enrollment TOTP
+	c.App.Post("/me/mfa/totp/confirm", with(credentials, c.MFAHandler.ConfirmTOTPEnrollment)...)     // POST /me/mfa/totp/confirm untuk mengaktifkan TOTP
+	c.App.Delete("/me/mfa/totp", with(credentials, c.MFAHandler.DisableTOTP)...)                     // DELETE /me/mfa/totp untuk menonaktifkan TOTP
+	c.App.Post("/me/mfa/recovery-codes", with(credentials, c.MFAHandler.RegenerateRecoveryCodes)...) // POST /me/mfa/recovery-codes untuk membuat ulang kode pemulihan
+
+	c.App.Post("/me/passkeys/register/begin", with(credentials, c.PasskeyHandler.BeginRegistration)...)   // POST /me/passkeys/register/begin untuk memulai registrasi passkey
+	c.App.Post("/me/passkeys/register/finish", with(credentials, c.PasskeyHandler.FinishRegistration)...) // POST /me/passkeys/register/finish untuk menyimpan passkey baru
+	c.App.Get("/me/passkeys", with(credentials, c.PasskeyHandler.ListPasskeys)...)                        // GET /me/passkeys untuk melihat semua passkey
+	c.App.Patch("/me/passkeys/:id", with(credentials, c.PasskeyHandler.RenamePasskey)...)                 // PATCH /me/passkeys/:id untuk mengganti nama passkey
+	c.App.Delete("/me/passkeys/:id", with(credentials, c.PasskeyHandler.DeletePasskey)...)                // DELETE /me/passkeys/:id untuk menghapus passkey
+
+	c.App.Get("/me/sessions", with(session, c.SessionHandler.ListSessions)...)             // GET /me/sessions untuk melihat perangkat tempat pengguna login
+	c.App.Delete("/me/sessions/:id", with(credentials, c.SessionHandler.RevokeSession)...) // DELETE /me/sessions/:id untuk mengeluarkan satu perangkat
+
+	c.App.Get("/me/identities", with(credentials, c.SocialHandler.ListIdentities)...)       // GET /me/identities untuk melihat akun sosial yang terhubung
+	c.App.Post("/me/identities/:provider", with(credentials, c.SocialHandler.BeginLink)...) // POST /me/identities/:provider untuk memulai penghubungan akun sosial
+	c.App.Delete("/me/identities/:id", with(credentials, c.SocialHandler.Unlink)...)        // DELETE /me/identities/:id untuk memutus akun sosial
+
+	c.App.Post("/me/api-keys", with(credentials, c.APIKeyHandler.CreateAPIKey)...)       // POST /me/api-keys untuk membuat API key (kunci hanya ditampilkan sekali)
+	c.App.Get("/me/api-keys", with(credentials, c.APIKeyHandler.ListAPIKeys)...)         // GET /me/api-keys untuk melihat API key
+	c.App.Delete("/me/api-keys/:id", with(credentials, c.APIKeyHandler.RevokeAPIKey)...) // DELETE /me/api-keys/:id untuk mencabut API key
+
+	c.App.Get("/oauth/authorize", with(credentials, c.OAuthHandler.Authorize)...) // GET /oauth/authorize untuk memeriksa permintaan otorisasi klien OAuth
+	c.App.Post("/oauth/authorize", with(credentials, c.OAuthHandler.Consent)...)  // POST /oauth/authorize untuk menyetujui atau menolak klien OAuth
+
+	c.App.Post("/oauth/clients", with(admin, c.OAuthHandler.RegisterClient)...)     // POST /oauth/clients untuk mendaftarkan klien OAuth (admin, secret hanya ditampilkan sekali)
+	c.App.Get("/oauth/clients", with(admin, c.OAuthHandler.ListClients)...)         // GET /oauth/clients untuk melihat klien OAuth (admin)
+	c.App.Delete("/oauth/clients/:id", with(admin, c.OAuthHandler.DeleteClient)...) // DELETE /oauth/clients/:id untuk menghapus klien OAuth (admin)
+
+	c.App.Post("/saml/connections", with(admin, c.SAMLHandler.CreateConnection)...)       // POST /saml/connections untuk menambah koneksi SAML dari metadata IdP (admin)
+	c.App.Get("/saml/connections", with(admin, c.SAMLHandler.ListAllConnections)...)      // GET /saml/connections untuk melihat koneksi SAML beserta pengaturannya (admin)
+	c.App.Put("/saml/connections/:id", with(admin, c.SAMLHandler.UpdateConnection)...)    // PUT /saml/connections/:id untuk memperbarui koneksi SAML (admin)
+	c.App.Delete("/saml/connections/:id", with(admin, c.SAMLHandler.DeleteConnection)...) // DELETE /saml/connections/:id untuk menghapus koneksi SAML (admin)
+
+	c.App.Get("/me/organizations", with(auth, c.OrgHandler.ListMyOrganizations)...)                // GET /me/organizations untuk melihat organisasi tempat pengguna menjadi anggota
+	c.App.Post("/me/invitations/accept", with(credentials, c.InviteHandler.AcceptMyInvitation)...) // POST /me/invitations/accept untuk menerima undangan dengan akun yang sedang login
+
+	c.App.Post("/organizations", with(admin, c.OrgHandler.CreateOrganization)...)       // POST /organizations untuk membuat organisasi (admin)
+	c.App.Get("/organizations", with(admin, c.OrgHandler.ListOrganizations)...)         // GET /organizations untuk melihat semua organisasi (admin)
+	c.App.Put("/organizations/:id", with(admin, c.OrgHandler.RenameOrganization)...)    // PUT /organizations/:id untuk mengganti nama organisasi (admin)
+	c.App.Delete("/organizations/:id", with(admin, c.OrgHandler.DeleteOrganization)...) // DELETE /organizations/:id untuk menghapus organisasi beserta keanggotaannya (admin)
+
+	c.App.Get("/organization/members", with(tenant, c.Authorizer.Require(entities.PermissionMembersRead), c.OrgHandler.ListMembers)...)                // GET /organization/members untuk melihat anggota organisasi aktif
+	c.App.Post("/organization/members", with(tenant, c.Authorizer.Require(entities.PermissionMembersWrite), c.OrgHandler.AddMember)...)                // POST /organization/members untuk menambah pengguna ke organisasi aktif
+	c.App.Put("/organization/members/:userId", with(tenant, c.Authorizer.Require(entities.PermissionMembersWrite), c.OrgHandler.UpdateMemberRoles)...) // PUT /organization/members/:userId untuk mengganti Role anggota
+	c.App.Delete("/organization/members/:userId", with(tenant, c.Authorizer.Require(entities.PermissionMembersWrite), c.OrgHandler.RemoveMember)...)   // DELETE /organization/members/:userId untuk mengeluarkan anggota
+
+	c.App.Post("/organization/invitations", with(tenant, c.Authorizer.Require(entities.PermissionMembersWrite), c.InviteHandler.CreateInvitation)...)            // POST /organization/invitations untuk mengundang email ke organisasi aktif dengan Role tertentu
+	c.App.Get("/organization/invitations", with(tenant, c.Authorizer.Require(entities.PermissionMembersRead), c.InviteHandler.ListInvitations)...)               // GET /organization/invitations untuk melihat undangan yang masih menunggu
+	c.App.Post("/organization/invitations/:id/resend", with(tenant, c.Authorizer.Require(entities.PermissionMembersWrite), c.InviteHandler.ResendInvitation)...) // POST /organization/invitations/:id/resend untuk mengirim ulang undangan dengan tautan baru
+	c.App.Delete("/organization/invitations/:id", with(tenant, c.Authorizer.Require(entities.PermissionMembersWrite), c.InviteHandler.RevokeInvitation)...)      // DELETE /organization/invitations/:id untuk mencabut undangan
+
+	c.App.Post("/groups", with(admin, c.GroupHandler.CreateGroup)...)                                // POST /groups untuk membuat grup pengguna (admin)
+	c.App.Get("/groups", with(admin, c.GroupHandler.ListGroups)...)                                  // GET /groups untuk melihat semua grup (admin)
+	c.App.Get("/groups/:id", with(admin, c.GroupHandler.GetGroup)...)                                // GET /groups/:id untuk melihat anggota, subgrup dan Role grup (admin)
+	c.App.Put("/groups/:id", with(admin, c.GroupHandler.UpdateGroup)...)                             // PUT /groups/:id untuk mengganti nama dan deskripsi grup (admin)
+	c.App.Delete("/groups/:id", with(admin, c.GroupHandler.DeleteGroup)...)                          // DELETE /groups/:id untuk menghapus grup (admin)
+	c.App.Post("/groups/:id/members", with(admin, c.GroupHandler.AddMember)...)                      // POST /groups/:id/members untuk menambah anggota grup (admin)
+	c.App.Delete("/groups/:id/members/:userId", with(admin, c.GroupHandler.RemoveMember)...)         // DELETE /groups/:id/members/:userId untuk mengeluarkan anggota grup (admin)
+	c.App.Post("/groups/:id/subgroups", with(admin, c.GroupHandler.AddSubgroup)...)                  // POST /groups/:id/subgroups untuk menambah subgrup, ditolak jika membentuk siklus (admin)
+	c.App.Delete("/groups/:id/subgroups/:subgroupId", with(admin, c.GroupHandler.RemoveSubgroup)...) // DELETE /groups/:id/subgroups/:subgroupId untuk melepas subgrup (admin)
+	c.App.Put("/groups/:id/roles", with(admin, c.GroupHandler.SetRoles)...)                          // PUT /groups/:id/roles untuk mengganti Role yang diberikan ke grup (admin)
+
+	c.App.Post("/roles", with(admin, c.RoleHandler.CreateRole)...)                    // POST /roles untuk membuat role (admin)
+	c.App.Get("/roles", with(admin, c.RoleHandler.ListRoles)...)                      // GET /roles untuk melihat semua role beserta permission langsung dan warisannya (admin)
+	c.App.Get("/roles/:id", with(admin, c.RoleHandler.GetRole)...)                    // GET /roles/:id untuk melihat role beserta permission langsung dan warisannya (admin)
+	c.App.Put("/roles/:id", with(admin, c.RoleHandler.UpdateRole)...)                 // PUT /roles/:id untuk mengganti nama dan deskripsi role (admin)
+	c.App.Delete("/roles/:id", with(admin, c.RoleHandler.DeleteRole)...)              // DELETE /roles/:id untuk menghapus role (admin)
+	c.App.Put("/roles/:id/permissions", with(admin, c.RoleHandler.SetPermissions)...) // PUT /roles/:id/permissions untuk mengganti permission langsung role (admin)
+	c.App.Put("/roles/:id/parents", with(admin, c.RoleHandler.SetParents)...)         // PUT /roles/:id/parents untuk mengganti role induk yang diwarisi, ditolak jika membentuk siklus (admin)
+	c.App.Put("/roles/:id/owner", with(admin, c.RoleHandler.SetRoleOwner)...)         // PUT /roles/:id/owner untuk menetapkan pemilik role yang meninjau penetapannya (admin)
+	c.App.Put("/roles/:id/scim", with(admin, c.RoleHandler.SetRoleSCIM)...)           // PUT /roles/:id/scim untuk mengizinkan klien SCIM mengelola role sebagai Group (admin)
+	c.App.Put("/roles/:id/tenant", with(admin, c.RoleHandler.SetRoleTenant)...)       // PUT /roles/:id/tenant untuk mengizinkan role diberikan ke anggota organisasi (admin)
+
+	c.App.Post("/me/role-requests", with(credentials, c.RequestHandler.CreateRoleRequest)...)           // POST /me/role-requests untuk meminta role sementara selama beberapa jam
+	c.App.Get("/me/role-requests", with(session, c.RequestHandler.ListMyRoleRequests)...)               // GET /me/role-requests untuk melihat permintaan role milik sendiri
+	c.App.Delete("/me/role-requests/:id", with(session, c.RequestHandler.CancelRoleRequest)...)         // DELETE /me/role-requests/:id untuk membatalkan permintaan role yang masih menunggu
+	c.App.Get("/role-requests", with(credentials, c.RequestHandler.ListPendingRoleRequests)...)         // GET /role-requests untuk melihat permintaan role yang menunggu keputusan (permission role-requests:approve)
+	c.App.Post("/role-requests/:id/approve", with(credentials, c.RequestHandler.ApproveRoleRequest)...) // POST /role-requests/:id/approve untuk menyetujui permintaan role; role berakhir otomatis (permission role-requests:approve)
+	c.App.Post("/role-requests/:id/reject", with(credentials, c.RequestHandler.RejectRoleRequest)...)   // POST /role-requests/:id/reject untuk menolak permintaan role (permission role-requests:approve)
+
+	c.App.Post("/approval-policies", with(admin, c.ApprovalHandler.CreatePolicy)...)                                 // POST /approval-policies untuk mewajibkan persetujuan sebelum role atau status superuser diberikan (admin)
+	c.App.Get("/approval-policies", with(admin, c.ApprovalHandler.ListPolicies)...)                                  // GET /approval-policies untuk melihat semua kebijakan persetujuan (admin)
+	c.App.Put("/approval-policies/:id", with(admin, c.ApprovalHandler.UpdatePolicy)...)                              // PUT /approval-policies/:id untuk mengganti jumlah persetujuan dan role peninjau (admin)
+	c.App.Delete("/approval-policies/:id", with(admin, c.ApprovalHandler.DeletePolicy)...)                           // DELETE /approval-policies/:id untuk menghapus kebijakan persetujuan (admin)
+	c.App.Post("/assignment-requests", with(admin, c.ApprovalHandler.CreateAssignmentRequest)...)                    // POST /assignment-requests untuk mengajukan penetapan role atau status superuser yang memerlukan persetujuan (admin)
+	c.App.Get("/assignment-requests", with(credentials, c.ApprovalHandler.ListAssignmentRequests)...)                // GET /assignment-requests?status= untuk melihat permintaan penetapan yang boleh diputuskan atau diajukan sendiri
+	c.App.Post("/assignment-requests/:id/approve", with(credentials, c.ApprovalHandler.ApproveAssignmentRequest)...) // POST /assignment-requests/:id/approve untuk menyetujui penetapan; diterapkan setelah kebijakan terpenuhi (role peninjau)
+	c.App.Post("/assignment-requests/:id/reject", with(credentials, c.ApprovalHandler.RejectAssignmentRequest)...)   // POST /assignment-requests/:id/reject untuk menolak penetapan dengan komentar (role peninjau)
+	c.App.Delete("/assignment-requests/:id", with(credentials, c.ApprovalHandler.CancelAssignmentRequest)...)        // DELETE /assignment-requests/:id untuk membatalkan permintaan penetapan milik sendiri
+
+	c.App.Post("/sod-rules", with(admin, c.SoDHandler.CreateRule)...)               // POST /sod-rules untuk membuat aturan pemisahan tugas antar role (admin)
+	c.App.Get("/sod-rules", with(admin, c.SoDHandler.ListRules)...)                 // GET /sod-rules untuk melihat semua aturan pemisahan tugas (admin)
+	c.App.Get("/sod-rules/violations", with(admin, c.SoDHandler.ListViolations)...) // GET /sod-rules/violations untuk melaporkan pengguna yang memegang role yang saling bertentangan (admin)
+	c.App.Get("/sod-rules/:id", with(admin, c.SoDHandler.GetRule)...)               // GET /sod-rules/:id untuk melihat satu aturan pemisahan tugas (admin)
+	c.App.Put("/sod-rules/:id", with(admin, c.SoDHandler.UpdateRule)...)            // PUT /sod-rules/:id untuk mengganti nama, deskripsi dan role aturan (admin)
+	c.App.Delete("/sod-rules/:id", with(admin, c.SoDHandler.DeleteRule)...)         // DELETE /sod-rules/:id untuk menghapus aturan pemisahan tugas (admin)
+
+	c.App.Post("/access-reviews", with(admin, c.ReviewHandler.CreateCampaign)...)                           // POST /access-reviews untuk membuat kampanye tinjauan akses dari snapshot penetapan role (admin)
+	c.App.Get("/access-reviews", with(admin, c.ReviewHandler.ListCampaigns)...)                             // GET /access-reviews untuk melihat semua kampanye tinjauan akses (admin)
+	c.App.Get("/access-reviews/:id", with(admin, c.ReviewHandler.GetCampaign)...)                           // GET /access-reviews/:id untuk melihat item dan keputusan kampanye (admin)
+	c.App.Post("/access-reviews/:id/close", with(admin, c.ReviewHandler.CloseCampaign)...)                  // POST /access-reviews/:id/close untuk menyelesaikan kampanye sebelum batas waktunya (admin)
+	c.App.Get("/access-reviews/:id/export", with(admin, c.ReviewHandler.ExportCampaign)...)                 // GET /access-reviews/:id/export untuk mengunduh laporan CSV bertanda tangan (admin)
+	c.App.Get("/me/access-review-items", with(credentials, c.ReviewHandler.ListMyReviewItems)...)           // GET /me/access-review-items untuk melihat penetapan yang menunggu tinjauan sendiri
+	c.App.Post("/access-review-items/:id/decision", with(credentials, c.ReviewHandler.DecideReviewItem)...) // POST /access-review-items/:id/decision untuk memutuskan keep atau revoke (peninjau item)
+
+	c.App.Get("/me/impersonation", with(auth, c.ImpersonHandler.GetImpersonation)...)    // GET /me/impersonation untuk keterangan penanda impersonasi dari token yang sedang dipakai
+	c.App.Delete("/me/impersonation", with(auth, c.ImpersonHandler.EndImpersonation)...) // DELETE /me/impersonation untuk mengakhiri sesi impersonasi sebelum batas waktunya
+
+	c.App.Get("/audit-logs", with(admin, c.AuditHandler.ListAuditLogs)...) // GET /audit-logs untuk melihat jejak audit dengan filter action, actor_id, target, since, until dan limit (admin)
+
+	c.App.Post("/policies", with(admin, c.PolicyHandler.CreatePolicy)...)                                                                               // POST /policies untuk membuat kebijakan akses ABAC (admin)
+	c.App.Get("/policies", with(admin, c.PolicyHandler.ListPolicies)...)                                                                                // GET /policies untuk melihat semua kebijakan akses (admin)
+	c.App.Get("/policies/:id", with(admin, c.PolicyHandler.GetPolicy)...)                                                                               // GET /policies/:id untuk melihat satu kebijakan akses (admin)
+	c.App.Put("/policies/:id", with(admin, c.PolicyHandler.UpdatePolicy)...)                                                                            // PUT /policies/:id untuk mengganti kebijakan akses (admin)
+	c.App.Delete("/policies/:id", with(admin, c.PolicyHandler.DeletePolicy)...)                                                                         // DELETE /policies/:id untuk menghapus kebijakan akses (admin)
+	c.App.Post("/authz/check", with(auth, middlewares.RequireDelegated, c.Authorizer.Require(entities.PermissionAuthzCheck), c.PolicyHandler.Check)...) // POST /authz/check untuk keputusan akses bagi layanan lain (API key atau token OAuth dengan scope authz:check)
+
+	c.App.Post("/service-accounts", with(admin, c.UserHandler.CreateServiceAccount)...)                              // POST /api/v1/users/service-accounts untuk membuat service account (admin)
+	c.App.Post("/:id/api-keys", with(admin, c.APIKeyHandler.CreateUserAPIKey)...)                                    // POST /api/v1/users/:id/api-keys untuk membuat API key bagi pengguna lain (admin)
+	c.App.Get("/:id/api-keys", with(admin, c.APIKeyHandler.ListUserAPIKeys)...)                                      // GET /api/v1/users/:id/api-keys untuk melihat API key pengguna lain (admin)
+	c.App.Delete("/:id/api-keys/:keyId", with(admin, c.APIKeyHandler.RevokeUserAPIKey)...)                           // DELETE /api/v1/users/:id/api-keys/:keyId untuk mencabut API key pengguna lain (admin)
+	c.App.Delete("/:id/sessions", with(admin, c.SessionHandler.RevokeUserSessions)...)                               // DELETE /api/v1/users/:id/sessions untuk mengeluarkan pengguna dari semua perangkat (admin)
+	c.App.Get("/:id/permissions", with(admin, c.GroupHandler.GetUserPermissions)...)                                 // GET /api/v1/users/:id/permissions untuk melihat permission efektif pengguna termasuk dari grup (admin)
+	c.App.Get("/:id/permissions/explain", with(admin, c.GroupHandler.ExplainUserPermission)...)                      // GET /api/v1/users/:id/permissions/explain?permission= untuk melihat dari mana pengguna menerima permission (admin)
+	c.App.Get("/:id/roles", with(admin, c.GrantHandler.ListUserRoles)...)                                            // GET /api/v1/users/:id/roles untuk melihat penetapan role langsung beserta jendela waktunya (admin)
+	c.App.Post("/:id/roles", with(admin, c.GrantHandler.GrantUserRole)...)                                           // POST /api/v1/users/:id/roles untuk menetapkan role permanen atau sementara (admin)
+	c.App.Delete("/:id/roles/:roleId", with(admin, c.GrantHandler.RevokeUserRole)...)                                // DELETE /api/v1/users/:id/roles/:roleId untuk mencabut role langsung (admin)
+	c.App.Put("/:id/attributes", with(admin, c.UserHandler.SetUserAttributes)...)                                    // PUT /api/v1/users/:id/attributes untuk mengganti atribut pengguna yang dipakai kebijakan ABAC (admin)
+	c.App.Post("/:id/impersonate", with(admin, middlewares.RequireSession, c.ImpersonHandler.StartImpersonation)...) // POST /api/v1/users/:id/impersonate untuk masuk sebagai pengguna dengan batas waktu (superuser dengan permission users:impersonate)
+	c.App.Post("/:id/unlock", with(admin, c.AuthHandler.UnlockUser)...)                                              // POST /api/v1/users/:id/unlock untuk membuka kunci akun (admin)
+
+	c.App.Get("/:id", with(tenant, c.Authorizer.Require(entities.PermissionUsersRead), c.UserHandler.GetUserByID)...)     // GET /api/v1/users/:id untuk mendapatkan pengguna di organisasi aktif
+	c.App.Put("/:id", with(tenant, c.Authorizer.Require(entities.PermissionUsersWrite), c.UserHandler.UpdateUser)...)     // PUT /api/v1/users/:id untuk memperbarui pengguna di organisasi aktif
+	c.App.Delete("/:id", with(tenant, c.Authorizer.Require(entities.PermissionUsersDelete), c.UserHandler.DeleteUser)...) // DELETE /api/v1/users/:id untuk mengeluarkan pengguna dari organisasi aktif (superuser tanpa organisasi: menghapus akun)
+	c.App.Get("/", with(tenant, c.Authorizer.Require(entities.PermissionUsersRead), c.UserHandler.GetAllUsers)...)        // GET /api/v1/users untuk mendapatkan semua pengguna di organisasi aktif
+}
+
+func (c *RouteConfig) SetupSCIMRoute() {
+	auth := []fiber.Handler{c.AuthMiddleware, c.RateLimiter.PerUser()}
+	scim := with(auth, middlewares.RequireAPIKey, middlewares.RejectOrganizationBound, c.Authorizer.Require(entities.PermissionSCIMProvision)) // Setiap klien provisioning memakai API key service account-nya sendiri
+
+	c.App.Get("/scim/v2/ServiceProviderConfig", with(scim, c.SCIMHandler.ServiceProviderConfig)...) // GET /scim/v2/ServiceProviderConfig untuk fitur SCIM yang didukung
+	c.App.Get("/scim/v2/ResourceTypes", with(scim, c.SCIMHandler.ResourceTypes)...)                 // GET /scim/v2/ResourceTypes untuk resource yang bisa di-provision
+	c.App.Get("/scim/v2/ResourceTypes/:id", with(scim, c.SCIMHandler.ResourceType)...)              // GET /scim/v2/ResourceTypes/:id untuk satu resource type
+	c.App.Get("/scim/v2/Schemas", with(scim, c.SCIMHandler.Schemas)...)                             // GET /scim/v2/Schemas untuk definisi atribut User dan Group
+	c.App.Get("/scim/v2/Schemas/:id", with(scim, c.SCIMHandler.Schema)...)                          // GET /scim/v2/Schemas/:id untuk satu definisi skema
+
+	c.App.Get("/scim/v2/Users", with(scim, c.SCIMHandler.ListUsers)...)         // GET /scim/v2/Users untuk mencari pengguna (filter, startIndex, count)
+	c.App.Post("/scim/v2/Users", with(scim, c.SCIMHandler.CreateUser)...)       // POST /scim/v2/Users untuk membuat pengguna
+	c.App.Get("/scim/v2/Users/:id", with(scim, c.SCIMHandler.GetUser)...)       // GET /scim/v2/Users/:id untuk satu pengguna
+	c.App.Put("/scim/v2/Users/:id", with(scim, c.SCIMHandler.ReplaceUser)...)   // PUT /scim/v2/Users/:id untuk mengganti atribut pengguna
+	c.App.Patch("/scim/v2/Users/:id", with(scim, c.SCIMHandler.PatchUser)...)   // PATCH /scim/v2/Users/:id untuk mengubah sebagian atribut, termasuk deaktivasi
+	c.App.Delete("/scim/v2/Users/:id", with(scim, c.SCIMHandler.DeleteUser)...) // DELETE /scim/v2/Users/:id untuk menghapus pengguna
+
+	c.App.Get("/scim/v2/Groups", with(scim, c.SCIMHandler.ListGroups)...)         // GET /scim/v2/Groups untuk mencari grup (Role)
+	c.App.Post("/scim/v2/Groups", with(scim, c.SCIMHandler.CreateGroup)...)       // POST /scim/v2/Groups untuk membuat grup
+	c.App.Get("/scim/v2/Groups/:id", with(scim, c.SCIMHandler.GetGroup)...)       // GET /scim/v2/Groups/:id untuk satu grup
+	c.App.Put("/scim/v2/Groups/:id", with(scim, c.SCIMHandler.ReplaceGroup)...)   // PUT /scim/v2/Groups/:id untuk mengganti nama dan anggota grup
+	c.App.Patch("/scim/v2/Groups/:id", with(scim, c.SCIMHandler.PatchGroup)...)   // PATCH /scim/v2/Groups/:id untuk menambah atau mengeluarkan anggota
+	c.App.Delete("/scim/v2/Groups/:id", with(scim, c.SCIMHandler.DeleteGroup)...) // DELETE /scim/v2/Groups/:id untuk menghapus grup
+}
 
+// with menggabungkan rantai middleware dengan handler tanpa berbagi backing array antar rute.
+func with(middleware []fiber.Handler, handlers ...fiber.Handler) []fiber.Handler {
+	return append(append([]fiber.Handler{}, middleware...), handlers...)
 }
@@ -0,0 +1,111 @@
+package routes
+
+import (
+	"net/http/httptest"
+	"testing"
+
+	"fiber-usermanagement/internal/api/middlewares"
+	"fiber-usermanagement/internal/config"
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/gofiber/fiber/v2"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// newRouteTestApp memasang semua rute dengan middleware autentikasi yang selalu memakai claims.
+func newRouteTestApp(claims *services.TokenClaims) *fiber.App {
+	disabled := false
+	app := fiber.New()
+	next := func(c *fiber.Ctx) error { return c.Next() }
+	routes := &RouteConfig{
+		App:            app,
+		CorsMiddleware: next,
+		ImpersonAudit:  next,
+		AuthMiddleware: func(c *fiber.Ctx) error {
+			c.Locals(middlewares.LocalsUserID, claims.UserID)
+			c.Locals(middlewares.LocalsClaims, claims)
+			return c.Next()
+		},
+		RateLimiter:    middlewares.NewRateLimiter(nil, config.RateLimitConfig{Enabled: &disabled}, zap.NewNop()),
+		Authorizer:     middlewares.NewAuthorizer(nil),
+		TenantResolver: middlewares.NewTenantResolver(nil, ""),
+	}
+	routes.Setup()
+	return app
+}
+
+func TestApprovalRoutesRejectImpersonation(t *testing.T) {
+	// Superuser yang meng-impersonasi peninjau tidak boleh memutuskan permintaan atas nama peninjau itu,
+	// termasuk permintaannya sendiri
+	claims := &services.TokenClaims{
+		UserID:    uuid.New(),
+		SessionID: uuid.New(),
+		ActorID:   uuid.New(),
+	}
+	app := newRouteTestApp(claims)
+
+	id := uuid.NewString()
+	tests := []struct {
+		method string
+		path   string
+	}{
+		{fiber.MethodPost, "/me/role-requests"},
+		{fiber.MethodGet, "/role-requests"},
+		{fiber.MethodPost, "/role-requests/" + id + "/approve"},
+		{fiber.MethodPost, "/role-requests/" + id + "/reject"},
+		{fiber.MethodGet, "/assignment-requests"},
+		{fiber.MethodPost, "/assignment-requests/" + id + "/approve"},
+		{fiber.MethodPost, "/assignment-requests/" + id + "/reject"},
+		{fiber.MethodDelete, "/assignment-requests/" + id},
+		{fiber.MethodGet, "/me/access-review-items"},
+		{fiber.MethodPost, "/access-review-items/" + id + "/decision"},
+		{fiber.MethodPost, "/me/invitations/accept"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			resp, err := app.Test(httptest.NewRequest(tt.method, tt.path, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != fiber.StatusForbidden {
+				t.Fatalf("status = %d, ingin %d", resp.StatusCode, fiber.StatusForbidden)
+			}
+		})
+	}
+}
+
+func TestApprovalRoutesRequireSuperuserOrSession(t *testing.T) {
+	member := &services.TokenClaims{UserID: uuid.New(), SessionID: uuid.New()}
+	apiKey := &services.TokenClaims{UserID: uuid.New(), APIKeyID: uuid.New(), IsSuperuser: true, Scopes: []string{entities.ScopeAll}}
+	id := uuid.NewString()
+	tests := []struct {
+		name   string
+		claims *services.TokenClaims
+		method string
+		path   string
+	}{
+		// Kebijakan dan pengajuan penetapan hanya untuk superuser
+		{"bukan superuser", member, fiber.MethodPost, "/approval-policies"},
+		{"bukan superuser", member, fiber.MethodGet, "/approval-policies"},
+		{"bukan superuser", member, fiber.MethodPut, "/approval-policies/" + id},
+		{"bukan superuser", member, fiber.MethodDelete, "/approval-policies/" + id},
+		{"bukan superuser", member, fiber.MethodPost, "/assignment-requests"},
+		// Keputusan persetujuan tidak boleh memakai API key, meskipun milik superuser
+		{"API key", apiKey, fiber.MethodPost, "/assignment-requests/" + id + "/approve"},
+		{"API key", apiKey, fiber.MethodPost, "/assignment-requests/" + id + "/reject"},
+		{"API key", apiKey, fiber.MethodPost, "/role-requests/" + id + "/approve"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name+" "+tt.method+" "+tt.path, func(t *testing.T) {
+			resp, err := newRouteTestApp(tt.claims).Test(httptest.NewRequest(tt.method, tt.path, nil))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if resp.StatusCode != fiber.StatusForbidden {
+				t.Fatalf("status = %d, ingin %d", resp.StatusCode, fiber.StatusForbidden)
+			}
+		})
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"log"
 	"strings"
+	"time"
 
 	"github.com/joho/godotenv"
 	"github.com/spf13/viper"
@@ -11,15 +12,33 @@ import (
 
 // Config represents the main configuration structure
 type Config struct {
-	AppEnv   *string        `mapstructure:"app_env"`
-	Port     *string        `mapstructure:"port"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Storage  StorageConfig  `mapstructure:"storage"`
-	JWT      JWTConfig      `mapstructure:"jwt"`
-	Email    EmailConfig    `mapstructure:"email"`
-	RabbitMQ RabbitMQConfig `mapstructure:"rabbitmq"`
-	Log      LogConfig      `mapstructure:"log"` // Add LogConfig here
-	Redis    RedisConfig    `mapstructure:"redis"`
+	AppEnv      *string           `mapstructure:"app_env"`
+	Port        *string           `mapstructure:"port"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Storage     StorageConfig     `mapstructure:"storage"`
+	JWT         JWTConfig         `mapstructure:"jwt"`
+	Email       EmailConfig       `mapstructure:"email"`
+	RabbitMQ    RabbitMQConfig    `mapstructure:"rabbitmq"`
+	Log         LogConfig         `mapstructure:"log"` // Add LogConfig here
+	Redis       RedisConfig       `mapstructure:"redis"`
+	Cors        CorsConfig        `mapstructure:"cors"`
+	RateLimit   RateLimitConfig   `mapstructure:"rate_limit"`
+	Lockout     LockoutConfig     `mapstructure:"lockout"`
+	MFA         MFAConfig         `mapstructure:"mfa"`
+	WebAuthn    WebAuthnConfig    `mapstructure:"webauthn"`
+	APIKeys     APIKeyConfig      `mapstructure:"api_keys"`
+	OAuth       OAuthConfig       `mapstructure:"oauth"`
+	SocialLogin SocialLoginConfig `mapstructure:"social_login"`
+	LDAP        LDAPConfig        `mapstructure:"ldap"`
+	SAML        SAMLConfig        `mapstructure:"saml"`
+	SCIM        SCIMConfig        `mapstructure:"scim"`
+
+	Organizations OrganizationConfig  `mapstructure:"organizations"`
+	Invitations   InvitationConfig    `mapstructure:"invitations"`
+	Policies      PolicyConfig        `mapstructure:"policies"`
+	RoleRequests  RoleRequestConfig   `mapstructure:"role_requests"`
+	Impersonation ImpersonationConfig `mapstructure:"impersonation"`
+	Events        EventConfig         `mapstructure:"events"`
 }
 
 // DatabaseConfig represents database configuration
@@ -42,10 +61,15 @@ type StorageConfig struct {
 	MaxUploadSize *int64  `json:"max_upload_size" mapstructure:"max_upload_size"`
 }
 
-// JWTConfig represents JWT configuration
+// JWTConfig represents token signing configuration. Tokens are signed with asymmetric keys that are
+// stored in the database and rotated by the worker; there is no shared secret.
 type JWTConfig struct {
-	Secret     *string `mapstructure:"secret"`
-	Expiration *int    `mapstructure:"expiration"` // in hours
+	Issuer             *string `mapstructure:"issuer"`     // public base URL, used as "iss" and in OIDC discovery
+	Expiration         *int    `mapstructure:"expiration"` // in hours
+	IDTokenMinutes     *int    `mapstructure:"id_token_minutes"`
+	SigningAlgorithm   *string `mapstructure:"signing_algorithm"`    // RS256 or EdDSA
+	KeyRotationDays    *int    `mapstructure:"key_rotation_days"`    // age of a signing key before it is replaced
+	KeyPrepublishHours *int    `mapstructure:"key_prepublish_hours"` // new keys appear in JWKS this long before they sign
 }
 
 // EmailConfig represents email configuration
@@ -53,6 +77,7 @@ type EmailConfig struct {
 	Host         *string `mapstructure:"host"`
 	Port         *int    `mapstructure:"port"`
 	SenderName   *string `mapstructure:"sender_name"`
+	SenderEmail  *string `mapstructure:"sender_email"` // falls back to auth_email when empty
 	AuthEmail    *string `mapstructure:"auth_email"`
 	AuthPassword *string `mapstructure:"auth_password"`
 }
@@ -68,6 +93,183 @@ type RedisConfig struct {
 	DB       *int    `json:"db" mapstructure:"db"`
 }
 
+// CorsConfig represents CORS configuration
+type CorsConfig struct {
+	AllowedOrigins   []string `json:"allowed_origins" mapstructure:"allowed_origins"` // exact origins or patterns, e.g. https://*.example.com
+	AllowedMethods   []string `json:"allowed_methods" mapstructure:"allowed_methods"`
+	AllowedHeaders   []string `json:"allowed_headers" mapstructure:"allowed_headers"`
+	ExposedHeaders   []string `json:"exposed_headers" mapstructure:"exposed_headers"`
+	AllowCredentials *bool    `json:"allow_credentials" mapstructure:"allow_credentials"`
+	MaxAge           *int     `json:"max_age" mapstructure:"max_age"` // preflight cache in seconds
+}
+
+// RateLimitConfig represents rate limiting configuration
+type RateLimitConfig struct {
+	Enabled           *bool                    `json:"enabled" mapstructure:"enabled"`
+	Store             *string                  `json:"store" mapstructure:"store"` // redis, memory (single instance)
+	RequestsPerMinute *int                     `json:"requests_per_minute" mapstructure:"requests_per_minute"`
+	Burst             *int                     `json:"burst" mapstructure:"burst"` // extra requests allowed on top of requests_per_minute
+	Routes            map[string]RateLimitRule `json:"routes" mapstructure:"routes"`
+}
+
+// RateLimitRule represents a stricter policy for a named route (e.g. login)
+type RateLimitRule struct {
+	RequestsPerMinute *int `json:"requests_per_minute" mapstructure:"requests_per_minute"`
+	Burst             *int `json:"burst" mapstructure:"burst"`
+}
+
+// LockoutConfig represents brute-force protection configuration for login
+type LockoutConfig struct {
+	MaxAttempts      *int `json:"max_attempts" mapstructure:"max_attempts"`             // failed logins per account before lockout
+	LockoutMinutes   *int `json:"lockout_minutes" mapstructure:"lockout_minutes"`       // how long an account stays locked
+	IPMaxAttempts    *int `json:"ip_max_attempts" mapstructure:"ip_max_attempts"`       // failed logins per IP before the IP is blocked
+	WindowMinutes    *int `json:"window_minutes" mapstructure:"window_minutes"`         // how long failures are remembered
+	DelayAfter       *int `json:"delay_after" mapstructure:"delay_after"`               // failures before progressive delay starts
+	BaseDelaySeconds *int `json:"base_delay_seconds" mapstructure:"base_delay_seconds"` // first delay, doubled on each further failure
+	MaxDelaySeconds  *int `json:"max_delay_seconds" mapstructure:"max_delay_seconds"`
+}
+
+// MFAConfig represents multi-factor authentication configuration
+type MFAConfig struct {
+	Issuer                *string  `json:"issuer" mapstructure:"issuer"` // name shown in authenticator apps
+	RequiredForSuperusers *bool    `json:"required_for_superusers" mapstructure:"required_for_superusers"`
+	RequiredRoles         []string `json:"required_roles" mapstructure:"required_roles"`
+	ChallengeMinutes      *int     `json:"challenge_minutes" mapstructure:"challenge_minutes"` // lifetime of the MFA challenge token
+	RecoveryCodeCount     *int     `json:"recovery_code_count" mapstructure:"recovery_code_count"`
+}
+
+// WebAuthnConfig represents WebAuthn (passkey) relying party configuration
+type WebAuthnConfig struct {
+	RPID           *string  `json:"rp_id" mapstructure:"rp_id"` // effective domain without scheme and port, e.g. example.com
+	RPDisplayName  *string  `json:"rp_display_name" mapstructure:"rp_display_name"`
+	RPOrigins      []string `json:"rp_origins" mapstructure:"rp_origins"`           // fully qualified origins allowed to run ceremonies
+	TimeoutMinutes *int     `json:"timeout_minutes" mapstructure:"timeout_minutes"` // lifetime of a registration/login ceremony
+}
+
+// APIKeyConfig represents personal access token / API key configuration
+type APIKeyConfig struct {
+	Prefix            *string `json:"prefix" mapstructure:"prefix"`                           // identifies keys in the Authorization header, e.g. umk_<lookup>_<secret>
+	DefaultExpiryDays *int    `json:"default_expiry_days" mapstructure:"default_expiry_days"` // used when the client does not ask for an expiry
+	MaxExpiryDays     *int    `json:"max_expiry_days" mapstructure:"max_expiry_days"`
+}
+
+// OAuthConfig represents OAuth 2.0 authorization server configuration
+type OAuthConfig struct {
+	AuthorizationURL         *string `json:"authorization_url" mapstructure:"authorization_url"` // consent page advertised in discovery; defaults to <issuer>/oauth/authorize
+	AccessTokenMinutes       *int    `json:"access_token_minutes" mapstructure:"access_token_minutes"`
+	RefreshTokenDays         *int    `json:"refresh_token_days" mapstructure:"refresh_token_days"` // absolute lifetime of a consent, rotation does not extend it
+	AuthorizationCodeSeconds *int    `json:"authorization_code_seconds" mapstructure:"authorization_code_seconds"`
+}
+
+// SocialLoginConfig represents sign-in with external OpenID Connect identity providers
+type SocialLoginConfig struct {
+	StateMinutes *int                              `json:"state_minutes" mapstructure:"state_minutes"` // time allowed between the redirect to the provider and the callback
+	Providers    map[string]IdentityProviderConfig `json:"providers" mapstructure:"providers"`         // keyed by the name used in /auth/social/:provider
+}
+
+// IdentityProviderConfig represents one upstream OpenID Connect provider, e.g. Google, Microsoft or Keycloak
+type IdentityProviderConfig struct {
+	DisplayName  *string  `json:"display_name" mapstructure:"display_name"`
+	Issuer       *string  `json:"issuer" mapstructure:"issuer"` // discovery base URL, e.g. https://accounts.google.com
+	ClientID     *string  `json:"client_id" mapstructure:"client_id"`
+	ClientSecret *string  `json:"client_secret" mapstructure:"client_secret"`
+	RedirectURL  *string  `json:"redirect_url" mapstructure:"redirect_url"` // must point to /auth/social/<name>/callback or a page that forwards code and state to it
+	Scopes       []string `json:"scopes" mapstructure:"scopes"`             // defaults to openid email profile
+	AllowSignup  *bool    `json:"allow_signup" mapstructure:"allow_signup"` // create a user on first login when no account has the verified email
+	TrustEmail   *bool    `json:"trust_email" mapstructure:"trust_email"`   // treat email as verified when the provider omits email_verified (e.g. single-tenant Microsoft Entra ID)
+}
+
+// GetDisplayName returns the provider label shown on the login page
+func (p IdentityProviderConfig) GetDisplayName() string {
+	return getStringValue(p.DisplayName)
+}
+
+// GetClientSecret returns the client secret, empty for providers that use PKCE only
+func (p IdentityProviderConfig) GetClientSecret() string {
+	return getStringValue(p.ClientSecret)
+}
+
+// AllowsSignup reports whether unknown users are created on first login
+func (p IdentityProviderConfig) AllowsSignup() bool {
+	return getBoolValue(p.AllowSignup)
+}
+
+// TrustsEmail reports whether a missing email_verified claim counts as verified
+func (p IdentityProviderConfig) TrustsEmail() bool {
+	return getBoolValue(p.TrustEmail)
+}
+
+// LDAPConfig represents password login against an LDAP or Active Directory server
+type LDAPConfig struct {
+	Enabled             *bool               `json:"enabled" mapstructure:"enabled"`
+	URL                 *string             `json:"url" mapstructure:"url"` // ldap://host:389 or ldaps://host:636
+	StartTLS            *bool               `json:"start_tls" mapstructure:"start_tls"`
+	InsecureSkipVerify  *bool               `json:"insecure_skip_verify" mapstructure:"insecure_skip_verify"` // test directories with self-signed certificates only
+	BindDN              *string             `json:"bind_dn" mapstructure:"bind_dn"`                           // service account used to look up users
+	BindPassword        *string             `json:"bind_password" mapstructure:"bind_password"`
+	BaseDN              *string             `json:"base_dn" mapstructure:"base_dn"`
+	UserFilter          *string             `json:"user_filter" mapstructure:"user_filter"` // active users only; users outside it are deactivated by the sync job
+	UsernameAttribute   *string             `json:"username_attribute" mapstructure:"username_attribute"`
+	EmailAttribute      *string             `json:"email_attribute" mapstructure:"email_attribute"`
+	FirstNameAttribute  *string             `json:"first_name_attribute" mapstructure:"first_name_attribute"`
+	LastNameAttribute   *string             `json:"last_name_attribute" mapstructure:"last_name_attribute"`
+	GroupAttribute      *string             `json:"group_attribute" mapstructure:"group_attribute"`
+	GroupRoles          map[string][]string `json:"group_roles" mapstructure:"group_roles"` // group DN -> role names; mapped roles are granted and revoked by the directory
+	TimeoutSeconds      *int                `json:"timeout_seconds" mapstructure:"timeout_seconds"`
+	SyncIntervalMinutes *int                `json:"sync_interval_minutes" mapstructure:"sync_interval_minutes"`
+}
+
+// IsEnabled reports whether LDAP login and the directory sync job are active
+func (l LDAPConfig) IsEnabled() bool {
+	return getBoolValue(l.Enabled)
+}
+
+// SAMLConfig represents the SAML 2.0 service provider; IdP connections are managed through the admin API
+type SAMLConfig struct {
+	BaseURL         *string `json:"base_url" mapstructure:"base_url"`                 // public URL serving /auth/saml/<name>/acs; defaults to the JWT issuer
+	CertificateFile *string `json:"certificate_file" mapstructure:"certificate_file"` // optional PEM pair; enables signed AuthnRequests and encrypted assertions
+	PrivateKeyFile  *string `json:"private_key_file" mapstructure:"private_key_file"`
+	StateMinutes    *int    `json:"state_minutes" mapstructure:"state_minutes"` // time allowed between the redirect to the IdP and the assertion
+}
+
+// SCIMConfig represents the SCIM 2.0 provisioning endpoints; clients authenticate with service account
+// API keys that carry the scim:provision scope
+type SCIMConfig struct {
+	BaseURL    *string `json:"base_url" mapstructure:"base_url"`       // public URL serving /scim/v2, used in meta.location; defaults to the JWT issuer
+	MaxResults *int    `json:"max_results" mapstructure:"max_results"` // upper bound for count on list queries
+}
+
+// OrganizationConfig represents tenant resolution for organization-scoped routes
+type OrganizationConfig struct {
+	BaseDomain *string `json:"base_domain" mapstructure:"base_domain"` // e.g. "example.com" so acme.example.com selects organization "acme"; empty disables subdomain lookup
+}
+
+// InvitationConfig represents emailed invitations to join an organization
+type InvitationConfig struct {
+	AcceptURL   *string `json:"accept_url" mapstructure:"accept_url"`     // page that accepts the invitation; the link token is appended as ?token=. Defaults to <issuer>/invitations/accept
+	ExpiryHours *int    `json:"expiry_hours" mapstructure:"expiry_hours"` // link lifetime, restarted on resend
+}
+
+// PolicyConfig represents evaluation of attribute-based access policies
+type PolicyConfig struct {
+	Timezone *string `json:"timezone" mapstructure:"timezone"` // IANA zone for env.hour, env.weekday and env.date, e.g. "Asia/Jakarta"
+}
+
+// RoleRequestConfig represents self-service requests for temporary elevated roles
+type RoleRequestConfig struct {
+	MaxHours *int `json:"max_hours" mapstructure:"max_hours"` // longest grant a user may request; approved grants expire automatically
+}
+
+// ImpersonationConfig represents superusers signing in as another user for support
+type ImpersonationConfig struct {
+	MaxMinutes *int `json:"max_minutes" mapstructure:"max_minutes"` // hard limit of an impersonation session; its token cannot be refreshed
+}
+
+// EventConfig represents publishing of domain events such as expired role grants
+type EventConfig struct {
+	Queue *string `json:"queue" mapstructure:"queue"` // RabbitMQ queue receiving events as JSON (requires rabbitmq.url); empty only logs them
+}
+
 // ConfigManager handles configuration loading and management
 type ConfigManager struct {
 	viper  *viper.Viper
@@ -147,13 +349,18 @@ func (cm *ConfigManager) setDefaults() {
 	cm.viper.SetDefault("storage.max_upload_size", int64(10*1024*1024)) // 10MB
 
 	// JWT defaults
-	cm.viper.SetDefault("jwt.secret", "your-secret-key")
+	cm.viper.SetDefault("jwt.issuer", "http://localhost:8080")
 	cm.viper.SetDefault("jwt.expiration", 24) // 24 hours
+	cm.viper.SetDefault("jwt.id_token_minutes", 60)
+	cm.viper.SetDefault("jwt.signing_algorithm", "RS256")
+	cm.viper.SetDefault("jwt.key_rotation_days", 30)
+	cm.viper.SetDefault("jwt.key_prepublish_hours", 24)
 
 	// Email defaults
 	cm.viper.SetDefault("email.host", "localhost")
 	cm.viper.SetDefault("email.port", 587)
 	cm.viper.SetDefault("email.sender_name", "MyApp")
+	cm.viper.SetDefault("email.sender_email", "")
 	cm.viper.SetDefault("email.auth_email", "")
 	cm.viper.SetDefault("email.auth_password", "")
 
@@ -162,6 +369,117 @@ func (cm *ConfigManager) setDefaults() {
 	cm.viper.SetDefault("redis.port", 6379)
 	cm.viper.SetDefault("redis.password", "")
 	cm.viper.SetDefault("redis.db", 0)
+
+	// CORS defaults (allowed origins depend on app_env, see applyCorsDefaults)
+	cm.viper.SetDefault("cors.allowed_origins", []string{})
+	cm.viper.SetDefault("cors.allowed_methods", []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"})
+	cm.viper.SetDefault("cors.allowed_headers", []string{"Origin", "Content-Type", "Accept", "Authorization", "X-Requested-With", "X-CSRF-Token"})
+	cm.viper.SetDefault("cors.exposed_headers", []string{})
+	cm.viper.SetDefault("cors.allow_credentials", true)
+	cm.viper.SetDefault("cors.max_age", 600) // 10 minutes
+
+	// Rate limit defaults
+	cm.viper.SetDefault("rate_limit.enabled", true)
+	cm.viper.SetDefault("rate_limit.store", "redis")
+	cm.viper.SetDefault("rate_limit.requests_per_minute", 60)
+	cm.viper.SetDefault("rate_limit.burst", 10)
+	cm.viper.SetDefault("rate_limit.routes.login.requests_per_minute", 5)
+	cm.viper.SetDefault("rate_limit.routes.login.burst", 0)
+	cm.viper.SetDefault("rate_limit.routes.mfa.requests_per_minute", 10)
+	cm.viper.SetDefault("rate_limit.routes.mfa.burst", 0)
+	cm.viper.SetDefault("rate_limit.routes.passkey_login.requests_per_minute", 10)
+	cm.viper.SetDefault("rate_limit.routes.passkey_login.burst", 0)
+	cm.viper.SetDefault("rate_limit.routes.sso.requests_per_minute", 20)
+	cm.viper.SetDefault("rate_limit.routes.sso.burst", 0)
+	cm.viper.SetDefault("rate_limit.routes.invitation.requests_per_minute", 10)
+	cm.viper.SetDefault("rate_limit.routes.invitation.burst", 0)
+	cm.viper.SetDefault("rate_limit.routes.oauth_token.requests_per_minute", 30)
+	cm.viper.SetDefault("rate_limit.routes.oauth_token.burst", 10)
+
+	// Lockout defaults
+	cm.viper.SetDefault("lockout.max_attempts", 5)
+	cm.viper.SetDefault("lockout.lockout_minutes", 15)
+	cm.viper.SetDefault("lockout.ip_max_attempts", 20)
+	cm.viper.SetDefault("lockout.window_minutes", 15)
+	cm.viper.SetDefault("lockout.delay_after", 2)
+	cm.viper.SetDefault("lockout.base_delay_seconds", 1)
+	cm.viper.SetDefault("lockout.max_delay_seconds", 30)
+
+	// MFA defaults
+	cm.viper.SetDefault("mfa.issuer", "User Management")
+	cm.viper.SetDefault("mfa.required_for_superusers", false)
+	cm.viper.SetDefault("mfa.required_roles", []string{})
+	cm.viper.SetDefault("mfa.challenge_minutes", 5)
+	cm.viper.SetDefault("mfa.recovery_code_count", 10)
+
+	// WebAuthn defaults
+	cm.viper.SetDefault("webauthn.rp_id", "localhost")
+	cm.viper.SetDefault("webauthn.rp_display_name", "User Management")
+	cm.viper.SetDefault("webauthn.rp_origins", []string{"http://localhost:3000"})
+	cm.viper.SetDefault("webauthn.timeout_minutes", 5)
+
+	// API key defaults
+	cm.viper.SetDefault("api_keys.prefix", "umk")
+	cm.viper.SetDefault("api_keys.default_expiry_days", 90)
+	cm.viper.SetDefault("api_keys.max_expiry_days", 365)
+
+	// OAuth defaults
+	cm.viper.SetDefault("oauth.authorization_url", "")
+	cm.viper.SetDefault("oauth.access_token_minutes", 60)
+	cm.viper.SetDefault("oauth.refresh_token_days", 30)
+	cm.viper.SetDefault("oauth.authorization_code_seconds", 60)
+
+	// Social login defaults
+	cm.viper.SetDefault("social_login.state_minutes", 10)
+	cm.viper.SetDefault("social_login.providers", map[string]interface{}{})
+
+	// LDAP defaults (Active Directory attribute names)
+	cm.viper.SetDefault("ldap.enabled", false)
+	cm.viper.SetDefault("ldap.url", "")
+	cm.viper.SetDefault("ldap.start_tls", false)
+	cm.viper.SetDefault("ldap.insecure_skip_verify", false)
+	cm.viper.SetDefault("ldap.bind_dn", "")
+	cm.viper.SetDefault("ldap.bind_password", "")
+	cm.viper.SetDefault("ldap.base_dn", "")
+	cm.viper.SetDefault("ldap.user_filter", "(&(objectClass=user)(!(userAccountControl:1.2.840.113556.1.4.803:=2)))")
+	cm.viper.SetDefault("ldap.username_attribute", "sAMAccountName")
+	cm.viper.SetDefault("ldap.email_attribute", "mail")
+	cm.viper.SetDefault("ldap.first_name_attribute", "givenName")
+	cm.viper.SetDefault("ldap.last_name_attribute", "sn")
+	cm.viper.SetDefault("ldap.group_attribute", "memberOf")
+	cm.viper.SetDefault("ldap.group_roles", map[string]interface{}{})
+	cm.viper.SetDefault("ldap.timeout_seconds", 10)
+	cm.viper.SetDefault("ldap.sync_interval_minutes", 60)
+
+	// SAML defaults
+	cm.viper.SetDefault("saml.base_url", "")
+	cm.viper.SetDefault("saml.certificate_file", "")
+	cm.viper.SetDefault("saml.private_key_file", "")
+	cm.viper.SetDefault("saml.state_minutes", 10)
+
+	// SCIM defaults
+	cm.viper.SetDefault("scim.base_url", "")
+	cm.viper.SetDefault("scim.max_results", 100)
+
+	// Organization defaults
+	cm.viper.SetDefault("organizations.base_domain", "")
+
+	// Invitation defaults
+	cm.viper.SetDefault("invitations.accept_url", "")
+	cm.viper.SetDefault("invitations.expiry_hours", 168)
+
+	// Access policy defaults
+	cm.viper.SetDefault("policies.timezone", "UTC")
+
+	// Role request defaults
+	cm.viper.SetDefault("role_requests.max_hours", 24)
+
+	// Impersonation defaults
+	cm.viper.SetDefault("impersonation.max_minutes", 30)
+
+	// Event defaults
+	cm.viper.SetDefault("rabbitmq.url", "")
+	cm.viper.SetDefault("events.queue", "")
 }
 
 // loadConfig loads configuration from various sources and unmarshals to struct
@@ -208,6 +526,29 @@ func (cm *ConfigManager) postProcessConfig(config *Config) {
 			config.Database.DatabaseURL = &dbURL
 		}
 	}
+
+	cm.applyCorsDefaults(config)
+}
+
+// applyCorsDefaults fills allowed origins based on the environment when none are configured.
+// Non-production environments allow local frontends, production allows no cross-origin
+// requests until origins are configured explicitly.
+func (cm *ConfigManager) applyCorsDefaults(config *Config) {
+	if len(config.Cors.AllowedOrigins) > 0 {
+		return
+	}
+
+	if config.IsProduction() {
+		config.Cors.AllowedOrigins = []string{}
+		return
+	}
+
+	config.Cors.AllowedOrigins = []string{
+		"http://localhost",
+		"http://localhost:*",
+		"http://127.0.0.1",
+		"http://127.0.0.1:*",
+	}
 }
 
 // Helper methods for the Config struct
@@ -220,6 +561,55 @@ func (c *Config) GetDatabaseURL() string {
 	return ""
 }
 
+// GetSenderEmail returns the From address for outgoing email
+func (c *Config) GetSenderEmail() string {
+	if sender := getStringValue(c.Email.SenderEmail); sender != "" {
+		return sender
+	}
+	return getStringValue(c.Email.AuthEmail)
+}
+
+// GetAuthorizationURL returns the authorization endpoint advertised to OAuth/OIDC clients
+func (c *Config) GetAuthorizationURL() string {
+	if url := getStringValue(c.OAuth.AuthorizationURL); url != "" {
+		return url
+	}
+	return strings.TrimSuffix(getStringValue(c.JWT.Issuer), "/") + "/oauth/authorize"
+}
+
+// GetSAMLBaseURL returns the public URL of the SAML service provider endpoints
+func (c *Config) GetSAMLBaseURL() string {
+	if baseURL := getStringValue(c.SAML.BaseURL); baseURL != "" {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+	return strings.TrimSuffix(getStringValue(c.JWT.Issuer), "/")
+}
+
+// GetSCIMBaseURL returns the public URL of the SCIM endpoints
+func (c *Config) GetSCIMBaseURL() string {
+	if baseURL := getStringValue(c.SCIM.BaseURL); baseURL != "" {
+		return strings.TrimSuffix(baseURL, "/")
+	}
+	return strings.TrimSuffix(getStringValue(c.JWT.Issuer), "/")
+}
+
+// GetInvitationAcceptURL returns the page linked from invitation emails
+func (c *Config) GetInvitationAcceptURL() string {
+	if url := getStringValue(c.Invitations.AcceptURL); url != "" {
+		return url
+	}
+	return strings.TrimSuffix(getStringValue(c.JWT.Issuer), "/") + "/invitations/accept"
+}
+
+// GetPolicyLocation returns the time zone used for policy environment attributes
+func (c *Config) GetPolicyLocation() *time.Location {
+	location, err := time.LoadLocation(getStringValue(c.Policies.Timezone))
+	if err != nil {
+		return time.UTC
+	}
+	return location
+}
+
 // GetServerAddress returns formatted server address
 func (c *Config) GetServerAddress() string {
 	port := "8080"
@@ -250,8 +640,55 @@ func (c *Config) ValidateConfig() error {
 		return fmt.Errorf("database name is required")
 	}
 
-	if c.JWT.Secret == nil || *c.JWT.Secret == "" {
-		return fmt.Errorf("JWT secret is required")
+	if c.JWT.Issuer == nil || *c.JWT.Issuer == "" {
+		return fmt.Errorf("JWT issuer is required")
+	}
+
+	if algorithm := getStringValue(c.JWT.SigningAlgorithm); algorithm != "RS256" && algorithm != "EdDSA" {
+		return fmt.Errorf("unsupported JWT signing algorithm %q", algorithm)
+	}
+
+	for name, provider := range c.SocialLogin.Providers {
+		if getStringValue(provider.Issuer) == "" || getStringValue(provider.ClientID) == "" || getStringValue(provider.RedirectURL) == "" {
+			return fmt.Errorf("social login provider %q requires issuer, client_id and redirect_url", name)
+		}
+	}
+
+	if (getStringValue(c.SAML.CertificateFile) == "") != (getStringValue(c.SAML.PrivateKeyFile) == "") {
+		return fmt.Errorf("SAML certificate_file and private_key_file must be set together")
+	}
+
+	if getIntValue(c.SCIM.MaxResults) <= 0 {
+		return fmt.Errorf("SCIM max_results must be positive")
+	}
+
+	if getIntValue(c.Invitations.ExpiryHours) <= 0 {
+		return fmt.Errorf("invitation expiry_hours must be positive")
+	}
+
+	if _, err := time.LoadLocation(getStringValue(c.Policies.Timezone)); err != nil {
+		return fmt.Errorf("invalid policy timezone: %w", err)
+	}
+
+	if getIntValue(c.RoleRequests.MaxHours) <= 0 {
+		return fmt.Errorf("role request max_hours must be positive")
+	}
+
+	if getIntValue(c.Impersonation.MaxMinutes) <= 0 {
+		return fmt.Errorf("impersonation max_minutes must be positive")
+	}
+
+	if getStringValue(c.Events.Queue) != "" && getStringValue(c.RabbitMQ.URL) == "" {
+		return fmt.Errorf("events queue requires rabbitmq url")
+	}
+
+	if c.LDAP.IsEnabled() {
+		if getStringValue(c.LDAP.URL) == "" || getStringValue(c.LDAP.BaseDN) == "" || getStringValue(c.LDAP.BindDN) == "" {
+			return fmt.Errorf("LDAP requires url, base_dn and bind_dn when enabled")
+		}
+		if getIntValue(c.LDAP.SyncIntervalMinutes) <= 0 {
+			return fmt.Errorf("LDAP sync interval must be positive")
+		}
 	}
 
 	return nil
@@ -279,15 +716,109 @@ func (c *Config) PrintConfig() {
 	fmt.Printf("    Max Upload Size: %d\n", getInt64Value(c.Storage.MaxUploadSize))
 
 	fmt.Println("  JWT:")
-	fmt.Printf("    Secret: ****\n")
+	fmt.Printf("    Issuer: %s\n", getStringValue(c.JWT.Issuer))
 	fmt.Printf("    Expiration: %d hours\n", getIntValue(c.JWT.Expiration))
+	fmt.Printf("    ID Token Lifetime: %d minutes\n", getIntValue(c.JWT.IDTokenMinutes))
+	fmt.Printf("    Signing Algorithm: %s\n", getStringValue(c.JWT.SigningAlgorithm))
+	fmt.Printf("    Key Rotation: every %d days, published %d hours ahead\n", getIntValue(c.JWT.KeyRotationDays), getIntValue(c.JWT.KeyPrepublishHours))
 
 	fmt.Println("  Email:")
 	fmt.Printf("    Host: %s\n", getStringValue(c.Email.Host))
 	fmt.Printf("    Port: %d\n", getIntValue(c.Email.Port))
 	fmt.Printf("    Sender Name: %s\n", getStringValue(c.Email.SenderName))
+	fmt.Printf("    Sender Email: %s\n", c.GetSenderEmail())
 	fmt.Printf("    Auth Email: %s\n", getStringValue(c.Email.AuthEmail))
 	fmt.Printf("    Auth Password: ****\n")
+
+	fmt.Println("  CORS:")
+	fmt.Printf("    Allowed Origins: %s\n", strings.Join(c.Cors.AllowedOrigins, ", "))
+	fmt.Printf("    Allowed Methods: %s\n", strings.Join(c.Cors.AllowedMethods, ", "))
+	fmt.Printf("    Allowed Headers: %s\n", strings.Join(c.Cors.AllowedHeaders, ", "))
+	fmt.Printf("    Exposed Headers: %s\n", strings.Join(c.Cors.ExposedHeaders, ", "))
+	fmt.Printf("    Allow Credentials: %t\n", getBoolValue(c.Cors.AllowCredentials))
+	fmt.Printf("    Max Age: %d seconds\n", getIntValue(c.Cors.MaxAge))
+
+	fmt.Println("  Rate Limit:")
+	fmt.Printf("    Enabled: %t\n", getBoolValue(c.RateLimit.Enabled))
+	fmt.Printf("    Store: %s\n", getStringValue(c.RateLimit.Store))
+	fmt.Printf("    Requests Per Minute: %d\n", getIntValue(c.RateLimit.RequestsPerMinute))
+	fmt.Printf("    Burst: %d\n", getIntValue(c.RateLimit.Burst))
+	for name, rule := range c.RateLimit.Routes {
+		fmt.Printf("    Route %s: %d/min (burst %d)\n", name, getIntValue(rule.RequestsPerMinute), getIntValue(rule.Burst))
+	}
+
+	fmt.Println("  Lockout:")
+	fmt.Printf("    Max Attempts: %d (lock %d minutes)\n", getIntValue(c.Lockout.MaxAttempts), getIntValue(c.Lockout.LockoutMinutes))
+	fmt.Printf("    IP Max Attempts: %d\n", getIntValue(c.Lockout.IPMaxAttempts))
+	fmt.Printf("    Window: %d minutes\n", getIntValue(c.Lockout.WindowMinutes))
+	fmt.Printf("    Progressive Delay: after %d failures, %d-%d seconds\n", getIntValue(c.Lockout.DelayAfter), getIntValue(c.Lockout.BaseDelaySeconds), getIntValue(c.Lockout.MaxDelaySeconds))
+
+	fmt.Println("  MFA:")
+	fmt.Printf("    Issuer: %s\n", getStringValue(c.MFA.Issuer))
+	fmt.Printf("    Required For Superusers: %t\n", getBoolValue(c.MFA.RequiredForSuperusers))
+	fmt.Printf("    Required Roles: %s\n", strings.Join(c.MFA.RequiredRoles, ", "))
+	fmt.Printf("    Challenge Lifetime: %d minutes\n", getIntValue(c.MFA.ChallengeMinutes))
+	fmt.Printf("    Recovery Codes: %d\n", getIntValue(c.MFA.RecoveryCodeCount))
+
+	fmt.Println("  WebAuthn:")
+	fmt.Printf("    RP ID: %s\n", getStringValue(c.WebAuthn.RPID))
+	fmt.Printf("    RP Display Name: %s\n", getStringValue(c.WebAuthn.RPDisplayName))
+	fmt.Printf("    RP Origins: %s\n", strings.Join(c.WebAuthn.RPOrigins, ", "))
+	fmt.Printf("    Ceremony Timeout: %d minutes\n", getIntValue(c.WebAuthn.TimeoutMinutes))
+
+	fmt.Println("  API Keys:")
+	fmt.Printf("    Prefix: %s\n", getStringValue(c.APIKeys.Prefix))
+	fmt.Printf("    Expiry: default %d days, max %d days\n", getIntValue(c.APIKeys.DefaultExpiryDays), getIntValue(c.APIKeys.MaxExpiryDays))
+
+	fmt.Println("  OAuth:")
+	fmt.Printf("    Authorization URL: %s\n", c.GetAuthorizationURL())
+	fmt.Printf("    Access Token Lifetime: %d minutes\n", getIntValue(c.OAuth.AccessTokenMinutes))
+	fmt.Printf("    Refresh Token Lifetime: %d days\n", getIntValue(c.OAuth.RefreshTokenDays))
+	fmt.Printf("    Authorization Code Lifetime: %d seconds\n", getIntValue(c.OAuth.AuthorizationCodeSeconds))
+
+	fmt.Println("  Social Login:")
+	fmt.Printf("    State Lifetime: %d minutes\n", getIntValue(c.SocialLogin.StateMinutes))
+	for name, provider := range c.SocialLogin.Providers {
+		fmt.Printf("    Provider %s: %s (signup %t)\n", name, getStringValue(provider.Issuer), provider.AllowsSignup())
+	}
+
+	fmt.Println("  LDAP:")
+	fmt.Printf("    Enabled: %t\n", c.LDAP.IsEnabled())
+	fmt.Printf("    URL: %s (StartTLS %t)\n", getStringValue(c.LDAP.URL), getBoolValue(c.LDAP.StartTLS))
+	fmt.Printf("    Bind DN: %s\n", getStringValue(c.LDAP.BindDN))
+	fmt.Printf("    Bind Password: ****\n")
+	fmt.Printf("    Base DN: %s\n", getStringValue(c.LDAP.BaseDN))
+	fmt.Printf("    User Filter: %s\n", getStringValue(c.LDAP.UserFilter))
+	fmt.Printf("    Mapped Groups: %d\n", len(c.LDAP.GroupRoles))
+	fmt.Printf("    Sync Interval: %d minutes\n", getIntValue(c.LDAP.SyncIntervalMinutes))
+
+	fmt.Println("  SAML:")
+	fmt.Printf("    Base URL: %s\n", c.GetSAMLBaseURL())
+	fmt.Printf("    Certificate: %s\n", getStringValue(c.SAML.CertificateFile))
+	fmt.Printf("    State Lifetime: %d minutes\n", getIntValue(c.SAML.StateMinutes))
+
+	fmt.Println("  SCIM:")
+	fmt.Printf("    Base URL: %s\n", c.GetSCIMBaseURL())
+	fmt.Printf("    Max Results: %d\n", getIntValue(c.SCIM.MaxResults))
+
+	fmt.Println("  Organizations:")
+	fmt.Printf("    Base Domain: %s\n", getStringValue(c.Organizations.BaseDomain))
+
+	fmt.Println("  Invitations:")
+	fmt.Printf("    Accept URL: %s\n", c.GetInvitationAcceptURL())
+	fmt.Printf("    Expiry: %d hours\n", getIntValue(c.Invitations.ExpiryHours))
+
+	fmt.Println("  Policies:")
+	fmt.Printf("    Timezone: %s\n", c.GetPolicyLocation())
+
+	fmt.Println("  Role Requests:")
+	fmt.Printf("    Max Duration: %d hours\n", getIntValue(c.RoleRequests.MaxHours))
+
+	fmt.Println("  Impersonation:")
+	fmt.Printf("    Max Duration: %d minutes\n", getIntValue(c.Impersonation.MaxMinutes))
+
+	fmt.Println("  Events:")
+	fmt.Printf("    Queue: %s\n", getStringValue(c.Events.Queue))
 }
 
 // Helper functions to safely get values from pointers
@@ -311,3 +842,10 @@ func getInt64Value(ptr *int64) int64 {
 	}
 	return 0
 }
+
+func getBoolValue(ptr *bool) bool {
+	if ptr != nil {
+		return *ptr
+	}
+	return false
+}
@@ -2,14 +2,29 @@
 package container
 
 import (
+	"context"
 	"fiber-usermanagement/internal/api/handlers"
+	"fiber-usermanagement/internal/api/middlewares"
 	"fiber-usermanagement/internal/api/routes"
 	"fiber-usermanagement/internal/config"
 	"fiber-usermanagement/internal/domain/entities"
 	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+	"fiber-usermanagement/internal/infrastructure/directory"
+	"fiber-usermanagement/internal/infrastructure/events"
+	"fiber-usermanagement/internal/infrastructure/mail"
 	"fiber-usermanagement/internal/infrastructure/persistence"
+	"fiber-usermanagement/internal/infrastructure/ratelimit"
+	"fiber-usermanagement/internal/infrastructure/security"
+	"fiber-usermanagement/internal/infrastructure/sso"
 	"fiber-usermanagement/internal/usecase/interactors"
+	"fiber-usermanagement/internal/worker"
 	"fmt"
+	"time"
+
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/gofiber/fiber/v2"
+	"go.uber.org/zap"
 )
 
 // BusinessContainer holds all business logic dependencies
@@ -17,13 +32,106 @@ type BusinessContainer struct {
 	appContainer *config.AppContainer
 
 	// Repositories
-	userRepo repositories.UserRepository
+	userRepo         repositories.UserRepository
+	loginAttemptRepo repositories.LoginAttemptRepository
+	recoveryCodeRepo repositories.RecoveryCodeRepository
+	webAuthnCredRepo repositories.WebAuthnCredentialRepository
+	challengeStore   repositories.ChallengeStore
+	sessionRepo      repositories.SessionRepository
+	apiKeyRepo       repositories.APIKeyRepository
+	permissionRepo   repositories.PermissionRepository
+	oauthClientRepo  repositories.OAuthClientRepository
+	oauthTokenRepo   repositories.OAuthRefreshTokenRepository
+	oauthConsentRepo repositories.OAuthConsentRepository
+	signingKeyRepo   repositories.SigningKeyRepository
+	tokenDenylist    repositories.TokenDenylist
+	identityRepo     repositories.LinkedIdentityRepository
+	roleRepo         repositories.RoleRepository
+	samlConnRepo     repositories.SAMLConnectionRepository
+	organizationRepo repositories.OrganizationRepository
+	orgMemberRepo    repositories.OrganizationMemberRepository
+	invitationRepo   repositories.InvitationRepository
+	groupRepo        repositories.GroupRepository
+	accessPolicyRepo repositories.AccessPolicyRepository
+	roleRequestRepo  repositories.RoleRequestRepository
+	auditLogRepo     repositories.AuditLogRepository
+	approvalRepo     repositories.ApprovalPolicyRepository
+	assignmentRepo   repositories.AssignmentRequestRepository
+	sodRuleRepo      repositories.SoDRuleRepository
+	accessReviewRepo repositories.AccessReviewRepository
+
+	// Services
+	keyRing           *security.KeyRing
+	passwordHasher    services.PasswordHasher
+	tokenService      services.TokenService
+	mailer            services.Mailer
+	otpService        services.OTPService
+	webAuthn          *webauthn.WebAuthn
+	identityProviders []services.IdentityProvider
+	directory         services.Directory // nil when LDAP is disabled
+	samlSP            services.SAMLServiceProvider
+	events            services.EventPublisher
 
 	// Interactors/Use Cases
-	userInteractor *interactors.UserInteractor
+	userInteractor       *interactors.UserInteractor
+	authInteractor       *interactors.AuthInteractor
+	mfaInteractor        *interactors.MFAInteractor
+	webAuthnInteractor   *interactors.WebAuthnInteractor
+	sessionInteractor    *interactors.SessionInteractor
+	apiKeyInteractor     *interactors.APIKeyInteractor
+	authzInteractor      *interactors.AuthorizationInteractor
+	policyInteractor     *interactors.AccessPolicyInteractor
+	oauthInteractor      *interactors.OAuthInteractor
+	oidcInteractor       *interactors.OIDCInteractor
+	tokenInteractor      *interactors.TokenInteractor
+	socialInteractor     *interactors.SocialLoginInteractor
+	directoryInteractor  *interactors.DirectoryInteractor
+	samlInteractor       *interactors.SAMLInteractor
+	scimInteractor       *interactors.SCIMInteractor
+	orgInteractor        *interactors.OrganizationInteractor
+	invitationInteractor *interactors.InvitationInteractor
+	groupInteractor      *interactors.GroupInteractor
+	roleInteractor       *interactors.RoleInteractor
+	grantInteractor      *interactors.RoleGrantInteractor
+	requestInteractor    *interactors.RoleRequestInteractor
+	auditInteractor      *interactors.AuditInteractor
+	approvalInteractor   *interactors.ApprovalInteractor
+	sodInteractor        *interactors.SoDInteractor
+	reviewInteractor     *interactors.AccessReviewInteractor
+	impersonInteractor   *interactors.ImpersonationInteractor
 
 	// Handlers
-	userHandler *handlers.UserHandler
+	userHandler     *handlers.UserHandler
+	authHandler     *handlers.AuthHandler
+	mfaHandler      *handlers.MFAHandler
+	passkeyHandler  *handlers.PasskeyHandler
+	sessionHandler  *handlers.SessionHandler
+	apiKeyHandler   *handlers.APIKeyHandler
+	oauthHandler    *handlers.OAuthHandler
+	oidcHandler     *handlers.OIDCHandler
+	socialHandler   *handlers.SocialLoginHandler
+	samlHandler     *handlers.SAMLHandler
+	scimHandler     *handlers.SCIMHandler
+	orgHandler      *handlers.OrganizationHandler
+	inviteHandler   *handlers.InvitationHandler
+	groupHandler    *handlers.GroupHandler
+	roleHandler     *handlers.RoleHandler
+	policyHandler   *handlers.AccessPolicyHandler
+	grantHandler    *handlers.RoleGrantHandler
+	requestHandler  *handlers.RoleRequestHandler
+	approvalHandler *handlers.ApprovalHandler
+	auditHandler    *handlers.AuditHandler
+	sodHandler      *handlers.SoDHandler
+	reviewHandler   *handlers.AccessReviewHandler
+	impersonHandler *handlers.ImpersonationHandler
+
+	// Middlewares
+	corsMiddleware fiber.Handler
+	authMiddleware fiber.Handler
+	rateLimiter    *middlewares.RateLimiter
+	authorizer     *middlewares.Authorizer
+	tenantResolver *middlewares.TenantResolver
+	impersonAudit  fiber.Handler
 }
 
 // NewContainer creates a new business container with all dependencies
@@ -37,6 +145,11 @@ func NewContainer(appContainer *config.AppContainer) (*BusinessContainer, error)
 		return nil, fmt.Errorf("failed to initialize repositories: %w", err)
 	}
 
+	// Initialize services
+	if err := container.initServices(); err != nil {
+		return nil, fmt.Errorf("failed to initialize services: %w", err)
+	}
+
 	// Initialize interactors
 	if err := container.initInteractors(); err != nil {
 		return nil, fmt.Errorf("failed to initialize interactors: %w", err)
@@ -47,11 +160,21 @@ func NewContainer(appContainer *config.AppContainer) (*BusinessContainer, error)
 		return nil, fmt.Errorf("failed to initialize handlers: %w", err)
 	}
 
+	// Initialize middlewares
+	if err := container.initMiddlewares(); err != nil {
+		return nil, fmt.Errorf("failed to initialize middlewares: %w", err)
+	}
+
 	// Run database migrations
 	if err := container.runMigrations(); err != nil {
 		return nil, fmt.Errorf("failed to run migrations: %w", err)
 	}
 
+	// Ensure a signing key exists before the first token is issued
+	if err := container.initSigningKeys(); err != nil {
+		return nil, fmt.Errorf("failed to initialize signing keys: %w", err)
+	}
+
 	appContainer.Logger.Info("Business container initialized successfully")
 
 	return container, nil
@@ -60,14 +183,383 @@ func NewContainer(appContainer *config.AppContainer) (*BusinessContainer, error)
 // initRepositories initializes all repository implementations
 func (c *BusinessContainer) initRepositories() error {
 	c.userRepo = persistence.NewUserRepository(c.appContainer.DB)
+	c.loginAttemptRepo = persistence.NewLoginAttemptRepository(c.appContainer.Redis)
+	c.recoveryCodeRepo = persistence.NewRecoveryCodeRepository(c.appContainer.DB)
+	c.webAuthnCredRepo = persistence.NewWebAuthnCredentialRepository(c.appContainer.DB)
+	c.challengeStore = persistence.NewChallengeStore(c.appContainer.Redis)
+	c.sessionRepo = persistence.NewSessionRepository(c.appContainer.DB, c.appContainer.Redis)
+	c.apiKeyRepo = persistence.NewAPIKeyRepository(c.appContainer.DB)
+	c.permissionRepo = persistence.NewPermissionRepository(c.appContainer.DB)
+	c.oauthClientRepo = persistence.NewOAuthClientRepository(c.appContainer.DB)
+	c.oauthTokenRepo = persistence.NewOAuthRefreshTokenRepository(c.appContainer.DB)
+	c.oauthConsentRepo = persistence.NewOAuthConsentRepository(c.appContainer.DB)
+	c.signingKeyRepo = persistence.NewSigningKeyRepository(c.appContainer.DB)
+	c.tokenDenylist = persistence.NewTokenDenylist(c.appContainer.Redis)
+	c.identityRepo = persistence.NewLinkedIdentityRepository(c.appContainer.DB)
+	c.roleRepo = persistence.NewRoleRepository(c.appContainer.DB)
+	c.samlConnRepo = persistence.NewSAMLConnectionRepository(c.appContainer.DB)
+	c.organizationRepo = persistence.NewOrganizationRepository(c.appContainer.DB)
+	c.orgMemberRepo = persistence.NewOrganizationMemberRepository(c.appContainer.DB)
+	c.invitationRepo = persistence.NewInvitationRepository(c.appContainer.DB)
+	c.groupRepo = persistence.NewGroupRepository(c.appContainer.DB)
+	c.accessPolicyRepo = persistence.NewAccessPolicyRepository(c.appContainer.DB)
+	c.roleRequestRepo = persistence.NewRoleRequestRepository(c.appContainer.DB)
+	c.auditLogRepo = persistence.NewAuditLogRepository(c.appContainer.DB)
+	c.approvalRepo = persistence.NewApprovalPolicyRepository(c.appContainer.DB)
+	c.assignmentRepo = persistence.NewAssignmentRequestRepository(c.appContainer.DB)
+	c.sodRuleRepo = persistence.NewSoDRuleRepository(c.appContainer.DB)
+	c.accessReviewRepo = persistence.NewAccessReviewRepository(c.appContainer.DB)
 
 	c.appContainer.Logger.Info("Repositories initialized")
 	return nil
 }
 
+// initServices initializes domain services backed by infrastructure
+func (c *BusinessContainer) initServices() error {
+	cfg := c.appContainer.Config
+
+	keyRing, err := security.NewKeyRing(c.signingKeyRepo, security.KeyRotationPolicy{
+		Algorithm:        *cfg.JWT.SigningAlgorithm,
+		RotationInterval: time.Duration(*cfg.JWT.KeyRotationDays) * 24 * time.Hour,
+		Prepublish:       time.Duration(*cfg.JWT.KeyPrepublishHours) * time.Hour,
+		Retention:        c.longestTokenLifetime(),
+	})
+	if err != nil {
+		return fmt.Errorf("invalid signing key configuration: %w", err)
+	}
+	c.keyRing = keyRing
+
+	c.passwordHasher = security.NewBcryptHasher()
+	c.tokenService = security.NewJWTTokenService(
+		c.keyRing,
+		*cfg.JWT.Issuer,
+		time.Duration(*cfg.JWT.Expiration)*time.Hour,
+	)
+	c.mailer = mail.NewSMTPMailer(
+		*cfg.Email.Host,
+		*cfg.Email.Port,
+		*cfg.Email.AuthEmail,
+		*cfg.Email.AuthPassword,
+		cfg.GetSenderEmail(),
+		*cfg.Email.SenderName,
+	)
+	c.otpService = security.NewTOTPService(*cfg.MFA.Issuer)
+
+	ceremonyTimeout := time.Duration(*cfg.WebAuthn.TimeoutMinutes) * time.Minute
+	webAuthn, err := webauthn.New(&webauthn.Config{
+		RPID:          *cfg.WebAuthn.RPID,
+		RPDisplayName: *cfg.WebAuthn.RPDisplayName,
+		RPOrigins:     cfg.WebAuthn.RPOrigins,
+		Timeouts: webauthn.TimeoutsConfig{
+			Login:        webauthn.TimeoutConfig{Enforce: true, Timeout: ceremonyTimeout},
+			Registration: webauthn.TimeoutConfig{Enforce: true, Timeout: ceremonyTimeout},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("invalid webauthn configuration: %w", err)
+	}
+	c.webAuthn = webAuthn
+
+	for name, provider := range cfg.SocialLogin.Providers {
+		c.identityProviders = append(c.identityProviders, sso.NewOIDCIdentityProvider(sso.OIDCProviderConfig{
+			Name:         name,
+			DisplayName:  provider.GetDisplayName(),
+			Issuer:       *provider.Issuer,
+			ClientID:     *provider.ClientID,
+			ClientSecret: provider.GetClientSecret(),
+			RedirectURL:  *provider.RedirectURL,
+			Scopes:       provider.Scopes,
+			TrustEmail:   provider.TrustsEmail(),
+		}))
+	}
+
+	samlConfig := sso.SAMLConfig{BaseURL: cfg.GetSAMLBaseURL()}
+	if certFile := *cfg.SAML.CertificateFile; certFile != "" {
+		certificate, key, err := sso.LoadSAMLKeyPair(certFile, *cfg.SAML.PrivateKeyFile)
+		if err != nil {
+			return err
+		}
+		samlConfig.Certificate = certificate
+		samlConfig.Key = key
+	}
+	c.samlSP = sso.NewSAMLServiceProvider(samlConfig)
+
+	if ldap := cfg.LDAP; ldap.IsEnabled() {
+		c.directory = directory.NewLDAPDirectory(directory.LDAPConfig{
+			URL:                *ldap.URL,
+			StartTLS:           *ldap.StartTLS,
+			InsecureSkipVerify: *ldap.InsecureSkipVerify,
+			BindDN:             *ldap.BindDN,
+			BindPassword:       *ldap.BindPassword,
+			BaseDN:             *ldap.BaseDN,
+			UserFilter:         *ldap.UserFilter,
+			UsernameAttribute:  *ldap.UsernameAttribute,
+			EmailAttribute:     *ldap.EmailAttribute,
+			FirstNameAttribute: *ldap.FirstNameAttribute,
+			LastNameAttribute:  *ldap.LastNameAttribute,
+			GroupAttribute:     *ldap.GroupAttribute,
+			Timeout:            time.Duration(*ldap.TimeoutSeconds) * time.Second,
+		})
+	}
+
+	if queue := *cfg.Events.Queue; queue != "" {
+		c.events = events.NewRabbitMQPublisher(*cfg.RabbitMQ.URL, queue)
+	} else {
+		c.events = events.NewLogPublisher(c.appContainer.Logger)
+	}
+
+	c.appContainer.Logger.Info("Services initialized")
+	return nil
+}
+
+// longestTokenLifetime returns how long a retired signing key must stay valid so that every token
+// it signed can still be verified
+func (c *BusinessContainer) longestTokenLifetime() time.Duration {
+	cfg := c.appContainer.Config
+
+	longest := time.Duration(*cfg.JWT.Expiration) * time.Hour
+	for _, lifetime := range []time.Duration{
+		time.Duration(*cfg.JWT.IDTokenMinutes) * time.Minute,
+		time.Duration(*cfg.OAuth.AccessTokenMinutes) * time.Minute,
+		time.Duration(*cfg.MFA.ChallengeMinutes) * time.Minute,
+	} {
+		if lifetime > longest {
+			longest = lifetime
+		}
+	}
+	return longest
+}
+
 // initInteractors initializes all use case interactors
 func (c *BusinessContainer) initInteractors() error {
-	c.userInteractor = interactors.NewUserInteractor(c.userRepo)
+	lockout := c.appContainer.Config.Lockout
+	mfa := c.appContainer.Config.MFA
+	apiKeys := c.appContainer.Config.APIKeys
+	oauth := c.appContainer.Config.OAuth
+
+	c.userInteractor = interactors.NewUserInteractor(c.userRepo, c.passwordHasher)
+	c.mfaInteractor = interactors.NewMFAInteractor(
+		c.userRepo,
+		c.recoveryCodeRepo,
+		c.challengeStore,
+		c.otpService,
+		interactors.MFAPolicy{
+			RequiredForSuperusers: *mfa.RequiredForSuperusers,
+			RequiredRoles:         mfa.RequiredRoles,
+			RecoveryCodeCount:     *mfa.RecoveryCodeCount,
+		},
+	)
+	c.sessionInteractor = interactors.NewSessionInteractor(c.sessionRepo)
+	c.policyInteractor = interactors.NewAccessPolicyInteractor(c.accessPolicyRepo, c.appContainer.Config.GetPolicyLocation())
+	c.authzInteractor = interactors.NewAuthorizationInteractor(
+		c.permissionRepo,
+		c.userRepo,
+		c.roleRepo,
+		c.orgMemberRepo,
+		c.policyInteractor,
+	)
+	c.apiKeyInteractor = interactors.NewAPIKeyInteractor(
+		c.apiKeyRepo,
+		c.userRepo,
+		c.permissionRepo,
+		c.orgMemberRepo,
+		interactors.APIKeyPolicy{
+			Prefix:          *apiKeys.Prefix,
+			DefaultLifetime: time.Duration(*apiKeys.DefaultExpiryDays) * 24 * time.Hour,
+			MaxLifetime:     time.Duration(*apiKeys.MaxExpiryDays) * 24 * time.Hour,
+		},
+	)
+	c.auditInteractor = interactors.NewAuditInteractor(c.auditLogRepo)
+	c.sodInteractor = interactors.NewSoDInteractor(c.sodRuleRepo, c.roleRepo, c.groupRepo, c.userRepo, c.auditInteractor)
+	c.grantInteractor = interactors.NewRoleGrantInteractor(
+		c.roleRepo,
+		c.userRepo,
+		c.approvalRepo,
+		c.sodInteractor,
+		c.auditInteractor,
+		c.events,
+	)
+	c.roleInteractor = interactors.NewRoleInteractor(c.roleRepo, c.permissionRepo, c.userRepo, c.sodInteractor, c.grantInteractor)
+	c.webAuthnInteractor = interactors.NewWebAuthnInteractor(
+		c.userRepo,
+		c.webAuthnCredRepo,
+		c.challengeStore,
+		c.webAuthn,
+		time.Duration(*c.appContainer.Config.WebAuthn.TimeoutMinutes)*time.Minute,
+	)
+	if c.directory != nil {
+		c.directoryInteractor = interactors.NewDirectoryInteractor(
+			c.directory,
+			c.identityRepo,
+			c.userRepo,
+			c.roleRepo,
+			c.userInteractor,
+			c.sessionInteractor,
+			c.grantInteractor,
+			interactors.DirectoryPolicy{GroupRoles: c.appContainer.Config.LDAP.GroupRoles},
+		)
+	}
+	c.authInteractor = interactors.NewAuthInteractor(
+		c.userRepo,
+		c.loginAttemptRepo,
+		c.passwordHasher,
+		c.tokenService,
+		c.mailer,
+		c.mfaInteractor,
+		c.webAuthnInteractor,
+		c.sessionInteractor,
+		c.directoryInteractor,
+		interactors.LockoutPolicy{
+			MaxAttempts:     *lockout.MaxAttempts,
+			LockoutDuration: time.Duration(*lockout.LockoutMinutes) * time.Minute,
+			IPMaxAttempts:   *lockout.IPMaxAttempts,
+			Window:          time.Duration(*lockout.WindowMinutes) * time.Minute,
+			DelayAfter:      *lockout.DelayAfter,
+			BaseDelay:       time.Duration(*lockout.BaseDelaySeconds) * time.Second,
+			MaxDelay:        time.Duration(*lockout.MaxDelaySeconds) * time.Second,
+		},
+		time.Duration(*mfa.ChallengeMinutes)*time.Minute,
+	)
+	c.oauthInteractor = interactors.NewOAuthInteractor(
+		c.oauthClientRepo,
+		c.oauthTokenRepo,
+		c.oauthConsentRepo,
+		c.userRepo,
+		c.permissionRepo,
+		c.challengeStore,
+		c.tokenService,
+		c.userInteractor,
+		c.sessionInteractor,
+		interactors.OAuthPolicy{
+			AccessTokenTTL:  time.Duration(*oauth.AccessTokenMinutes) * time.Minute,
+			RefreshTokenTTL: time.Duration(*oauth.RefreshTokenDays) * 24 * time.Hour,
+			CodeTTL:         time.Duration(*oauth.AuthorizationCodeSeconds) * time.Second,
+			IDTokenTTL:      time.Duration(*c.appContainer.Config.JWT.IDTokenMinutes) * time.Minute,
+		},
+	)
+	c.tokenInteractor = interactors.NewTokenInteractor(
+		c.tokenService,
+		c.sessionInteractor,
+		c.apiKeyInteractor,
+		c.oauthTokenRepo,
+		c.tokenDenylist,
+	)
+	allowSignup := make(map[string]bool)
+	for name, provider := range c.appContainer.Config.SocialLogin.Providers {
+		allowSignup[name] = provider.AllowsSignup()
+	}
+	c.socialInteractor = interactors.NewSocialLoginInteractor(
+		c.identityProviders,
+		c.identityRepo,
+		c.userRepo,
+		c.challengeStore,
+		c.userInteractor,
+		c.authInteractor,
+		interactors.SocialLoginPolicy{
+			StateTTL:    time.Duration(*c.appContainer.Config.SocialLogin.StateMinutes) * time.Minute,
+			AllowSignup: allowSignup,
+		},
+	)
+	c.samlInteractor = interactors.NewSAMLInteractor(
+		c.samlConnRepo,
+		c.identityRepo,
+		c.userRepo,
+		c.roleRepo,
+		c.challengeStore,
+		c.samlSP,
+		c.userInteractor,
+		c.authInteractor,
+		c.grantInteractor,
+		interactors.SAMLPolicy{
+			StateTTL: time.Duration(*c.appContainer.Config.SAML.StateMinutes) * time.Minute,
+		},
+	)
+	c.orgInteractor = interactors.NewOrganizationInteractor(
+		c.organizationRepo,
+		c.orgMemberRepo,
+		c.userRepo,
+		c.roleRepo,
+		c.grantInteractor,
+	)
+	c.invitationInteractor = interactors.NewInvitationInteractor(
+		c.invitationRepo,
+		c.userRepo,
+		c.roleRepo,
+		c.orgInteractor,
+		c.userInteractor,
+		c.mailer,
+		interactors.InvitationPolicy{
+			TTL:       time.Duration(*c.appContainer.Config.Invitations.ExpiryHours) * time.Hour,
+			AcceptURL: c.appContainer.Config.GetInvitationAcceptURL(),
+		},
+	)
+	c.groupInteractor = interactors.NewGroupInteractor(
+		c.groupRepo,
+		c.userRepo,
+		c.roleRepo,
+		c.permissionRepo,
+		c.sodInteractor,
+		c.grantInteractor,
+	)
+	c.scimInteractor = interactors.NewSCIMInteractor(
+		c.userRepo,
+		c.roleRepo,
+		c.identityRepo,
+		c.userInteractor,
+		c.sessionInteractor,
+		c.grantInteractor,
+		interactors.SCIMPolicy{
+			BaseURL:    c.appContainer.Config.GetSCIMBaseURL(),
+			MaxResults: *c.appContainer.Config.SCIM.MaxResults,
+		},
+	)
+	c.approvalInteractor = interactors.NewApprovalInteractor(
+		c.approvalRepo,
+		c.assignmentRepo,
+		c.roleRepo,
+		c.userRepo,
+		c.grantInteractor,
+		c.auditInteractor,
+	)
+	c.requestInteractor = interactors.NewRoleRequestInteractor(
+		c.roleRequestRepo,
+		c.roleRepo,
+		c.userRepo,
+		c.permissionRepo,
+		c.grantInteractor,
+		c.approvalInteractor,
+		c.events,
+		interactors.RoleRequestPolicy{MaxHours: *c.appContainer.Config.RoleRequests.MaxHours},
+	)
+	c.reviewInteractor = interactors.NewAccessReviewInteractor(
+		c.accessReviewRepo,
+		c.roleRepo,
+		c.groupRepo,
+		c.organizationRepo,
+		c.orgMemberRepo,
+		c.userRepo,
+		c.grantInteractor,
+		c.auditInteractor,
+		c.keyRing,
+	)
+	c.impersonInteractor = interactors.NewImpersonationInteractor(
+		c.userRepo,
+		c.permissionRepo,
+		c.sessionInteractor,
+		c.tokenService,
+		c.auditInteractor,
+		interactors.ImpersonationPolicy{
+			MaxDuration: time.Duration(*c.appContainer.Config.Impersonation.MaxMinutes) * time.Minute,
+		},
+	)
+	c.oidcInteractor = interactors.NewOIDCInteractor(
+		c.keyRing,
+		c.userRepo,
+		interactors.OIDCProvider{
+			Issuer:           *c.appContainer.Config.JWT.Issuer,
+			AuthorizationURL: c.appContainer.Config.GetAuthorizationURL(),
+		},
+	)
 
 	c.appContainer.Logger.Info("Interactors initialized")
 	return nil
@@ -76,17 +568,87 @@ func (c *BusinessContainer) initInteractors() error {
 // initHandlers initializes all HTTP handlers
 func (c *BusinessContainer) initHandlers() error {
 	c.userHandler = handlers.NewUserHandler(c.userInteractor)
+	c.authHandler = handlers.NewAuthHandler(c.authInteractor)
+	c.mfaHandler = handlers.NewMFAHandler(c.mfaInteractor)
+	c.passkeyHandler = handlers.NewPasskeyHandler(c.webAuthnInteractor)
+	c.sessionHandler = handlers.NewSessionHandler(c.sessionInteractor)
+	c.apiKeyHandler = handlers.NewAPIKeyHandler(c.apiKeyInteractor)
+	c.oauthHandler = handlers.NewOAuthHandler(c.oauthInteractor, c.tokenInteractor)
+	c.oidcHandler = handlers.NewOIDCHandler(c.oidcInteractor)
+	c.socialHandler = handlers.NewSocialLoginHandler(c.socialInteractor)
+	c.samlHandler = handlers.NewSAMLHandler(c.samlInteractor)
+	c.scimHandler = handlers.NewSCIMHandler(c.scimInteractor)
+	c.orgHandler = handlers.NewOrganizationHandler(c.orgInteractor)
+	c.inviteHandler = handlers.NewInvitationHandler(c.invitationInteractor)
+	c.groupHandler = handlers.NewGroupHandler(c.groupInteractor)
+	c.roleHandler = handlers.NewRoleHandler(c.roleInteractor)
+	c.policyHandler = handlers.NewAccessPolicyHandler(c.policyInteractor, c.authzInteractor)
+	c.grantHandler = handlers.NewRoleGrantHandler(c.grantInteractor, c.approvalInteractor)
+	c.requestHandler = handlers.NewRoleRequestHandler(c.requestInteractor)
+	c.approvalHandler = handlers.NewApprovalHandler(c.approvalInteractor)
+	c.auditHandler = handlers.NewAuditHandler(c.auditInteractor)
+	c.sodHandler = handlers.NewSoDHandler(c.sodInteractor)
+	c.reviewHandler = handlers.NewAccessReviewHandler(c.reviewInteractor)
+	c.impersonHandler = handlers.NewImpersonationHandler(c.impersonInteractor)
 
 	c.appContainer.Logger.Info("Handlers initialized")
 	return nil
 }
 
+// initMiddlewares initializes all HTTP middlewares
+func (c *BusinessContainer) initMiddlewares() error {
+	c.corsMiddleware = middlewares.NewCorsMiddleware(c.appContainer.Config.Cors)
+	c.authMiddleware = middlewares.NewAuthMiddleware(c.tokenInteractor, c.apiKeyInteractor)
+	c.authorizer = middlewares.NewAuthorizer(c.authzInteractor)
+	c.tenantResolver = middlewares.NewTenantResolver(c.orgInteractor, *c.appContainer.Config.Organizations.BaseDomain)
+	c.rateLimiter = middlewares.NewRateLimiter(c.newRateLimitStore(), c.appContainer.Config.RateLimit, c.appContainer.Logger)
+	c.impersonAudit = middlewares.NewImpersonationAuditor(c.impersonInteractor)
+
+	c.appContainer.Logger.Info("Middlewares initialized")
+	return nil
+}
+
+// newRateLimitStore selects the rate limit backend, falling back to memory for single-instance mode
+func (c *BusinessContainer) newRateLimitStore() ratelimit.Store {
+	store := c.appContainer.Config.RateLimit.Store
+	if (store != nil && *store == "memory") || c.appContainer.Redis == nil {
+		c.appContainer.Logger.Info("Using in-memory rate limit store")
+		return ratelimit.NewMemoryStore()
+	}
+
+	return ratelimit.NewRedisStore(c.appContainer.Redis)
+}
+
 // runMigrations runs database migrations
 func (c *BusinessContainer) runMigrations() error {
 	entities := []interface{}{
 		&entities.User{},
 		&entities.Role{},
+		&entities.UserRole{}, // Adds the time window columns to the join table created for User.Roles
 		&entities.Permission{},
+		&entities.RecoveryCode{},
+		&entities.WebAuthnCredential{},
+		&entities.Session{},
+		&entities.APIKey{},
+		&entities.OAuthClient{},
+		&entities.OAuthRefreshToken{},
+		&entities.OAuthConsent{},
+		&entities.SigningKey{},
+		&entities.LinkedIdentity{},
+		&entities.SAMLConnection{},
+		&entities.Organization{},
+		&entities.OrganizationMember{},
+		&entities.Invitation{},
+		&entities.Group{},
+		&entities.AccessPolicy{},
+		&entities.RoleRequest{},
+		&entities.AuditLog{},
+		&entities.ApprovalPolicy{},
+		&entities.AssignmentRequest{},
+		&entities.AssignmentDecision{},
+		&entities.SoDRule{},
+		&entities.AccessReviewCampaign{},
+		&entities.AccessReviewItem{},
 	}
 
 	for _, entity := range entities {
@@ -99,12 +661,112 @@ func (c *BusinessContainer) runMigrations() error {
 	return nil
 }
 
+// initSigningKeys creates the first signing key when none is valid; later rotations run in the worker
+func (c *BusinessContainer) initSigningKeys() error {
+	if err := c.keyRing.Rotate(time.Now()); err != nil {
+		return err
+	}
+
+	c.appContainer.Logger.Info("Signing keys ready", zap.String("algorithm", c.keyRing.Algorithm()))
+	return nil
+}
+
+// ScheduledJobs returns the background jobs run by cmd/worker
+func (c *BusinessContainer) ScheduledJobs() []worker.Job {
+	jobs := []worker.Job{
+		{
+			Name:     "signing-key-rotation",
+			Interval: time.Hour,
+			Run: func(ctx context.Context) error {
+				return c.keyRing.Rotate(time.Now())
+			},
+		},
+	}
+
+	jobs = append(jobs, worker.Job{
+		Name:     "role-grant-expiry",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			expired, err := c.grantInteractor.ExpireDue(time.Now())
+			if err != nil {
+				return err
+			}
+			if expired > 0 {
+				c.appContainer.Logger.Info("Expired role grants removed", zap.Int("count", expired))
+			}
+			return nil
+		},
+	})
+
+	jobs = append(jobs, worker.Job{
+		Name:     "access-review-expiry",
+		Interval: time.Minute,
+		Run: func(ctx context.Context) error {
+			expired, err := c.reviewInteractor.ExpireDue(time.Now())
+			if err != nil {
+				return err
+			}
+			if expired > 0 {
+				c.appContainer.Logger.Info("Expired access review campaigns completed", zap.Int("count", expired))
+			}
+			return nil
+		},
+	})
+
+	if c.directoryInteractor != nil {
+		jobs = append(jobs, worker.Job{
+			Name:     "ldap-sync",
+			Interval: time.Duration(*c.appContainer.Config.LDAP.SyncIntervalMinutes) * time.Minute,
+			Run: func(ctx context.Context) error {
+				result, err := c.directoryInteractor.Sync(ctx)
+				if err != nil {
+					return err
+				}
+				c.appContainer.Logger.Info("Directory synchronized",
+					zap.Int("updated", result.Updated),
+					zap.Int("deactivated", result.Deactivated),
+				)
+				return nil
+			},
+		})
+	}
+	return jobs
+}
+
 // SetupRoutes configures all application routes
 func (c *BusinessContainer) SetupRoutes() {
 	routeConfig := &routes.RouteConfig{
 		App: c.appContainer.App,
 		// Logger:      c.appContainer.Logger,
-		UserHandler: c.userHandler,
+		UserHandler:     c.userHandler,
+		AuthHandler:     c.authHandler,
+		MFAHandler:      c.mfaHandler,
+		PasskeyHandler:  c.passkeyHandler,
+		SessionHandler:  c.sessionHandler,
+		APIKeyHandler:   c.apiKeyHandler,
+		OAuthHandler:    c.oauthHandler,
+		OIDCHandler:     c.oidcHandler,
+		SocialHandler:   c.socialHandler,
+		SAMLHandler:     c.samlHandler,
+		SCIMHandler:     c.scimHandler,
+		OrgHandler:      c.orgHandler,
+		InviteHandler:   c.inviteHandler,
+		GroupHandler:    c.groupHandler,
+		RoleHandler:     c.roleHandler,
+		PolicyHandler:   c.policyHandler,
+		GrantHandler:    c.grantHandler,
+		RequestHandler:  c.requestHandler,
+		ApprovalHandler: c.approvalHandler,
+		AuditHandler:    c.auditHandler,
+		SoDHandler:      c.sodHandler,
+		ReviewHandler:   c.reviewHandler,
+		ImpersonHandler: c.impersonHandler,
+		CorsMiddleware:  c.corsMiddleware,
+		AuthMiddleware:  c.authMiddleware,
+		RateLimiter:     c.rateLimiter,
+		Authorizer:      c.authorizer,
+		TenantResolver:  c.tenantResolver,
+		ImpersonAudit:   c.impersonAudit,
 		// Add other handlers as needed
 	}
 
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Efek AccessPolicy saat kondisinya terpenuhi.
+const (
+	PolicyEffectAllow = "allow"
+	PolicyEffectDeny  = "deny"
+)
+
+// AccessPolicy adalah aturan ABAC yang dievaluasi bersama Role dan Permission. Condition adalah ekspresi
+// atas atribut subject, resource, action dan env, misalnya
+// `subject.department == resource.department && env.hour >= 9 && env.hour < 17`.
+// Kebijakan allow memberi akses walaupun Role tidak memberikannya; kebijakan deny selalu menolak.
+type AccessPolicy struct {
+	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name        string         `gorm:"unique;not null" json:"name"`
+	Description string         `json:"description"`
+	Effect      string         `gorm:"not null" json:"effect"`
+	Actions     []string       `gorm:"serializer:json;not null" json:"actions"` // Pola permission yang dicakup, misalnya "users:write" atau "users:*"
+	Condition   string         `gorm:"not null;default:''" json:"condition"`    // Kosong berarti selalu terpenuhi
+	Enabled     bool           `gorm:"not null;default:true" json:"enabled"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// AppliesTo mengembalikan true jika salah satu Actions mencakup permission.
+func (p *AccessPolicy) AppliesTo(permission string) bool {
+	for _, action := range p.Actions {
+		if PermissionMatches(action, permission) {
+			return true
+		}
+	}
+	return false
+}
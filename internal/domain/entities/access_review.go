@@ -0,0 +1,93 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status AccessReviewCampaign.
+const (
+	AccessReviewActive    = "active"
+	AccessReviewCompleted = "completed"
+)
+
+// Cara menentukan peninjau setiap penetapan dalam AccessReviewCampaign.
+const (
+	ReviewerManager   = "manager"    // Manajer pengguna dari atribut UserAttributeManager
+	ReviewerRoleOwner = "role_owner" // Pemilik Role (Role.OwnerID)
+)
+
+// Keputusan atas AccessReviewItem. Decision kosong berarti masih menunggu tinjauan.
+const (
+	ReviewDecisionKeep    = "keep"
+	ReviewDecisionRevoke  = "revoke"
+	ReviewDecisionExpired = "expired" // Tidak ditinjau sebelum kampanye berakhir
+)
+
+// Sumber akses AccessReviewItem.
+const (
+	ReviewSourceDirect       = "direct"       // Penetapan Role langsung ke User
+	ReviewSourceGroup        = "group"        // Keanggotaan langsung di Group pemberi Role
+	ReviewSourceNestedGroup  = "nested_group" // Keanggotaan di Subgroup dari Group pemberi Role
+	ReviewSourceOrganization = "organization" // Role anggota Organization
+)
+
+// UserAttributeManager adalah atribut User berisi username atau ID manajernya.
+const UserAttributeManager = "manager"
+
+// AccessReviewCampaign adalah tinjauan akses berkala: penetapan Role dalam cakupan Roles, baik langsung,
+// lewat Group maupun sebagai Role anggota organisasi, diambil snapshot-nya saat kampanye dibuat dan setiap
+// penetapan ditinjau oleh peninjau yang ditunjuk.
+type AccessReviewCampaign struct {
+	ID                 uuid.UUID          `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name               string             `gorm:"not null" json:"name"`
+	Description        string             `json:"description"`
+	Roles              []string           `gorm:"serializer:json" json:"roles"` // Nama Role dalam cakupan; kosong berarti semua Role
+	ReviewerStrategy   string             `gorm:"not null" json:"reviewer_strategy"`
+	FallbackReviewerID *uuid.UUID         `gorm:"type:uuid" json:"fallback_reviewer_id,omitempty"` // Peninjau jika manajer atau pemilik tidak ada
+	DueAt              time.Time          `gorm:"not null;index" json:"due_at"`
+	RevokeOnExpiry     bool               `gorm:"not null;default:false" json:"revoke_on_expiry"` // Cabut penetapan yang belum ditinjau saat kampanye berakhir
+	Status             string             `gorm:"not null;index;default:active" json:"status"`
+	CreatedBy          uuid.UUID          `gorm:"type:uuid;not null" json:"created_by"`
+	CompletedAt        *time.Time         `json:"completed_at,omitempty"`
+	Items              []AccessReviewItem `gorm:"foreignKey:CampaignID" json:"items,omitempty"`
+	CreatedAt          time.Time          `json:"created_at"`
+	UpdatedAt          time.Time          `json:"updated_at"`
+}
+
+// IsActive mengembalikan true jika kampanye masih menerima keputusan.
+func (c *AccessReviewCampaign) IsActive() bool {
+	return c.Status == AccessReviewActive
+}
+
+// AccessReviewItem adalah satu sumber akses User ke Role dalam AccessReviewCampaign. Username, nama Role,
+// nama Group dan nama Organization disalin saat snapshot agar laporan tetap utuh meskipun semuanya
+// kemudian dihapus.
+type AccessReviewItem struct {
+	ID               uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	CampaignID       uuid.UUID  `gorm:"type:uuid;not null;index" json:"campaign_id"`
+	UserID           uuid.UUID  `gorm:"type:uuid;not null" json:"user_id"`
+	Username         string     `json:"username"`
+	RoleID           uuid.UUID  `gorm:"type:uuid;not null" json:"role_id"`
+	RoleName         string     `json:"role_name"`
+	Source           string     `gorm:"not null;default:direct" json:"source"` // ReviewSourceDirect, ReviewSourceGroup, ReviewSourceNestedGroup atau ReviewSourceOrganization
+	GroupID          *uuid.UUID `gorm:"type:uuid" json:"group_id,omitempty"`   // Group tempat User menjadi anggota langsung; dicabut lewat keanggotaan ini
+	GroupName        string     `json:"group_name,omitempty"`
+	OrganizationID   *uuid.UUID `gorm:"type:uuid" json:"organization_id,omitempty"` // Organization tempat User memegang Role anggota; dicabut dari keanggotaan ini
+	OrganizationName string     `json:"organization_name,omitempty"`
+	GrantedAt        *time.Time `json:"granted_at,omitempty"`
+	ExpiresAt        *time.Time `json:"expires_at,omitempty"`
+	ReviewerID       *uuid.UUID `gorm:"type:uuid;index" json:"reviewer_id,omitempty"` // Kosong berarti hanya superuser yang bisa memutuskan
+	Decision         string     `gorm:"not null;default:''" json:"decision"`
+	Comment          string     `json:"comment,omitempty"`
+	DecidedBy        *uuid.UUID `gorm:"type:uuid" json:"decided_by,omitempty"` // Kosong untuk keputusan otomatis saat kampanye berakhir
+	DecidedAt        *time.Time `json:"decided_at,omitempty"`
+	RevokedAt        *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt        time.Time  `json:"created_at"`
+}
+
+// IsPending mengembalikan true jika penetapan belum diputuskan.
+func (i *AccessReviewItem) IsPending() bool {
+	return i.Decision == ""
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// APIKey adalah personal access token milik seorang User atau service account untuk klien mesin.
+// Hanya hash kunci yang disimpan; Prefix disimpan apa adanya untuk pencarian dan ditampilkan
+// agar pemilik bisa mengenali kunci tanpa melihat rahasianya.
+type APIKey struct {
+	ID             uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID         uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name           string     `gorm:"not null" json:"name"`
+	Prefix         string     `gorm:"not null;uniqueIndex" json:"prefix"`
+	KeyHash        string     `gorm:"not null" json:"-"`
+	Scopes         []string   `gorm:"serializer:json;not null" json:"scopes"`           // Nama Permission yang boleh dipakai kunci ini
+	OrganizationID *uuid.UUID `gorm:"type:uuid;index" json:"organization_id,omitempty"` // Jika terisi, kunci hanya berlaku di organisasi ini
+	ExpiresAt      *time.Time `json:"expires_at,omitempty"`
+	LastUsedAt     *time.Time `json:"last_used_at,omitempty"`
+	LastUsedIP     string     `json:"last_used_ip,omitempty"`
+	RevokedAt      *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time  `json:"created_at"`
+	UpdatedAt      time.Time  `json:"updated_at"`
+}
+
+// IsActive mengembalikan true jika kunci belum dicabut dan belum kedaluwarsa pada waktu now.
+func (k *APIKey) IsActive(now time.Time) bool {
+	return k.RevokedAt == nil && (k.ExpiresAt == nil || now.Before(*k.ExpiresAt))
+}
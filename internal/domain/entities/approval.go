@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status AssignmentRequest.
+const (
+	AssignmentPending   = "pending"
+	AssignmentApproved  = "approved" // Kebijakan terpenuhi dan penetapan sudah diterapkan
+	AssignmentRejected  = "rejected"
+	AssignmentCancelled = "cancelled"
+)
+
+// ApprovalPolicy mewajibkan persetujuan beberapa orang sebelum Role diberikan ke pengguna. Kebijakan tanpa
+// RoleID berlaku untuk pemberian status superuser.
+type ApprovalPolicy struct {
+	ID                uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RoleID            *uuid.UUID `gorm:"type:uuid;uniqueIndex" json:"role_id,omitempty"`
+	Role              *Role      `json:"role,omitempty"`
+	RequiredApprovals int        `gorm:"not null;default:1" json:"required_approvals"`
+	ApproverRoles     []string   `gorm:"serializer:json" json:"approver_roles"` // Nama Role yang boleh menyetujui; superuser selalu boleh
+	CreatedAt         time.Time  `json:"created_at"`
+	UpdatedAt         time.Time  `json:"updated_at"`
+}
+
+// AssignmentRequest adalah penetapan sensitif yang menunggu persetujuan sesuai ApprovalPolicy. Jumlah
+// persetujuan dan Role peninjau disalin dari kebijakan saat diajukan, sehingga perubahan kebijakan tidak
+// mengubah permintaan yang sedang berjalan.
+type AssignmentRequest struct {
+	ID                uuid.UUID            `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID            uuid.UUID            `gorm:"type:uuid;not null;index" json:"user_id"` // Penerima penetapan
+	User              *User                `json:"user,omitempty"`
+	RoleID            *uuid.UUID           `gorm:"type:uuid" json:"role_id,omitempty"` // Kosong berarti pemberian status superuser
+	Role              *Role                `json:"role,omitempty"`
+	StartsAt          *time.Time           `json:"starts_at,omitempty"`
+	ExpiresAt         *time.Time           `json:"expires_at,omitempty"`
+	Hours             int                  `json:"hours,omitempty"` // Durasi yang dihitung sejak penetapan diterapkan
+	Reason            string               `json:"reason"`
+	RequestedBy       uuid.UUID            `gorm:"type:uuid;not null" json:"requested_by"`
+	Status            string               `gorm:"not null;index;default:pending" json:"status"`
+	RequiredApprovals int                  `gorm:"not null" json:"required_approvals"`
+	ApproverRoles     []string             `gorm:"serializer:json" json:"approver_roles"`
+	Decisions         []AssignmentDecision `gorm:"foreignKey:RequestID" json:"decisions,omitempty"`
+	DecidedAt         *time.Time           `json:"decided_at,omitempty"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// IsPending mengembalikan true jika permintaan masih menunggu keputusan.
+func (r *AssignmentRequest) IsPending() bool {
+	return r.Status == AssignmentPending
+}
+
+// IsSuperuser mengembalikan true jika permintaan memberikan status superuser, bukan Role.
+func (r *AssignmentRequest) IsSuperuser() bool {
+	return r.RoleID == nil
+}
+
+// Approvals mengembalikan jumlah persetujuan yang sudah diberikan.
+func (r *AssignmentRequest) Approvals() int {
+	approvals := 0
+	for _, decision := range r.Decisions {
+		if decision.Approved {
+			approvals++
+		}
+	}
+	return approvals
+}
+
+// AssignmentDecision adalah keputusan satu peninjau atas AssignmentRequest beserta komentarnya.
+// Setiap peninjau hanya bisa memutuskan satu kali per permintaan.
+type AssignmentDecision struct {
+	ID         uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	RequestID  uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_assignment_decisions_reviewer" json:"request_id"`
+	ApproverID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_assignment_decisions_reviewer" json:"approver_id"`
+	Approved   bool      `gorm:"not null" json:"approved"`
+	Comment    string    `json:"comment,omitempty"`
+	CreatedAt  time.Time `json:"created_at"`
+}
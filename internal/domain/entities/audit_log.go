@@ -0,0 +1,19 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditLog adalah catatan tindakan sensitif yang tidak pernah diubah atau dihapus lewat API, misalnya
+// penetapan Role dan keputusan persetujuan.
+type AuditLog struct {
+	ID         uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ActorID    *uuid.UUID     `gorm:"type:uuid;index" json:"actor_id,omitempty"` // Kosong untuk tindakan sistem, misalnya worker
+	Action     string         `gorm:"not null;index" json:"action"`              // Misalnya "assignment.approved"
+	TargetType string         `gorm:"not null;index:idx_audit_logs_target" json:"target_type"`
+	TargetID   string         `gorm:"not null;index:idx_audit_logs_target" json:"target_id"`
+	Data       map[string]any `gorm:"serializer:json" json:"data,omitempty"`
+	CreatedAt  time.Time      `gorm:"index" json:"created_at"`
+}
@@ -0,0 +1,22 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Group adalah tim atau kelompok pengguna yang menerima Role secara bersama-sama.
+// Group bisa berisi Group lain (Subgroups); anggota Subgroup ikut menerima Role dari semua Group induknya.
+type Group struct {
+	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name        string         `gorm:"unique;not null" json:"name"`
+	Description string         `json:"description"`
+	Members     []*User        `gorm:"many2many:group_members;" json:"members,omitempty"`
+	Subgroups   []*Group       `gorm:"many2many:group_subgroups;joinForeignKey:GroupID;joinReferences:SubgroupID" json:"subgroups,omitempty"`
+	Roles       []*Role        `gorm:"many2many:group_roles;" json:"roles,omitempty"`
+	CreatedAt   time.Time      `json:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at"`
+	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+}
@@ -0,0 +1,31 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Invitation adalah undangan bergabung ke Organization untuk alamat email yang mungkin belum punya akun.
+// Hanya hash token tautan yang disimpan; tautan lama tidak berlaku lagi setiap kali undangan dikirim ulang.
+type Invitation struct {
+	ID             uuid.UUID     `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrganizationID uuid.UUID     `gorm:"type:uuid;not null;index" json:"organization_id"`
+	Organization   *Organization `json:"organization,omitempty"`
+	Email          string        `gorm:"not null;index" json:"email"`           // Disimpan dalam huruf kecil
+	Roles          []string      `gorm:"serializer:json;not null" json:"roles"` // Nama Role yang diberikan saat undangan diterima
+	TokenHash      string        `gorm:"not null;uniqueIndex" json:"-"`
+	InvitedBy      uuid.UUID     `gorm:"type:uuid;not null" json:"invited_by"`
+	ExpiresAt      time.Time     `gorm:"not null" json:"expires_at"`
+	SentAt         time.Time     `gorm:"not null" json:"sent_at"` // Waktu pengiriman terakhir, termasuk kirim ulang
+	AcceptedAt     *time.Time    `json:"accepted_at,omitempty"`
+	AcceptedBy     *uuid.UUID    `gorm:"type:uuid" json:"accepted_by,omitempty"`
+	RevokedAt      *time.Time    `json:"revoked_at,omitempty"`
+	CreatedAt      time.Time     `json:"created_at"`
+	UpdatedAt      time.Time     `json:"updated_at"`
+}
+
+// IsPending mengembalikan true jika undangan belum diterima, belum dicabut dan belum kedaluwarsa pada waktu now.
+func (i *Invitation) IsPending(now time.Time) bool {
+	return i.AcceptedAt == nil && i.RevokedAt == nil && now.Before(i.ExpiresAt)
+}
@@ -0,0 +1,20 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// LinkedIdentity menghubungkan User dengan akun di identity provider eksternal (login sosial).
+// Satu akun eksternal hanya bisa terhubung ke satu User; satu User boleh memiliki banyak identitas.
+type LinkedIdentity struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID      uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Provider    string     `gorm:"not null;uniqueIndex:idx_linked_identity_provider_subject" json:"provider"` // Nama provider di konfigurasi, misalnya "google"
+	Subject     string     `gorm:"not null;uniqueIndex:idx_linked_identity_provider_subject" json:"-"`        // Klaim "sub" dari provider, stabil meski email berubah
+	Email       string     `json:"email"`                                                                     // Email dari provider saat terakhir login, hanya untuk ditampilkan
+	Provisioned bool       `gorm:"not null;default:false" json:"provisioned"`                                 // Akun User dibuat saat login pertama lewat identitas ini, sehingga password-nya tidak diketahui pemilik
+	CreatedAt   time.Time  `json:"created_at"`
+	LastLoginAt *time.Time `json:"last_login_at,omitempty"`
+}
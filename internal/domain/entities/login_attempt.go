@@ -0,0 +1,11 @@
+package entities
+
+import "time"
+
+// LoginAttempts merangkum percobaan login gagal untuk satu subjek (akun atau alamat IP).
+// Data ini disimpan di Redis agar konsisten di semua replika, bukan di database.
+type LoginAttempts struct {
+	Failures      int           // Jumlah kegagalan dalam window saat ini
+	LastFailureAt time.Time     // Waktu kegagalan terakhir
+	ExpiresIn     time.Duration // Sisa waktu sebelum penghitung direset otomatis
+}
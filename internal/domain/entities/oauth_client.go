@@ -0,0 +1,60 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Grant type OAuth 2.0 yang didukung authorization server.
+const (
+	GrantTypeAuthorizationCode = "authorization_code"
+	GrantTypeRefreshToken      = "refresh_token"
+	GrantTypeClientCredentials = "client_credentials"
+)
+
+// OAuthClient adalah aplikasi pihak ketiga yang terdaftar di authorization server.
+// Klien confidential memiliki secret (hanya hash-nya yang disimpan); klien publik
+// (SPA, aplikasi mobile) tidak punya secret dan wajib memakai PKCE.
+type OAuthClient struct {
+	ID               uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	ClientID         string         `gorm:"not null;uniqueIndex" json:"client_id"`
+	SecretHash       string         `json:"-"`
+	Name             string         `gorm:"not null" json:"name"`
+	RedirectURIs     []string       `gorm:"serializer:json;not null" json:"redirect_uris"`
+	GrantTypes       []string       `gorm:"serializer:json;not null" json:"grant_types"`
+	Scopes           []string       `gorm:"serializer:json;not null" json:"scopes"` // Nama Permission yang boleh diminta klien
+	Confidential     bool           `gorm:"not null" json:"confidential"`
+	ServiceAccountID *uuid.UUID     `gorm:"type:uuid" json:"service_account_id,omitempty"` // Subjek token client_credentials
+	Introspection    bool           `gorm:"not null;default:false" json:"introspection"`   // Resource server yang boleh memeriksa dan mencabut token apa pun
+	CreatedBy        uuid.UUID      `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt        time.Time      `json:"created_at"`
+	UpdatedAt        time.Time      `json:"updated_at"`
+	DeletedAt        gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// AllowsGrant mengembalikan true jika klien terdaftar untuk grant type tersebut.
+func (c *OAuthClient) AllowsGrant(grantType string) bool {
+	return containsString(c.GrantTypes, grantType)
+}
+
+// AllowsRedirectURI mengembalikan true jika uri sama persis dengan salah satu redirect URI terdaftar.
+func (c *OAuthClient) AllowsRedirectURI(uri string) bool {
+	return containsString(c.RedirectURIs, uri)
+}
+
+// AllowsScope mengembalikan true jika klien boleh meminta scope tersebut.
+func (c *OAuthClient) AllowsScope(scope string) bool {
+	return containsString(c.Scopes, scope)
+}
+
+// containsString mengembalikan true jika value ada di values.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,28 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthConsent adalah persetujuan pengguna agar klien memakai scope tertentu atas namanya.
+// Persetujuan yang sudah mencakup scope yang diminta membuat langkah consent dilewati.
+type OAuthConsent struct {
+	ID        uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID    uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_oauth_consent_user_client" json:"user_id"`
+	ClientID  string    `gorm:"not null;uniqueIndex:idx_oauth_consent_user_client" json:"client_id"`
+	Scopes    []string  `gorm:"serializer:json;not null" json:"scopes"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Covers mengembalikan true jika persetujuan sudah mencakup semua scope.
+func (c *OAuthConsent) Covers(scopes []string) bool {
+	for _, scope := range scopes {
+		if !containsString(c.Scopes, scope) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,29 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// OAuthRefreshToken adalah refresh token yang diterbitkan untuk klien OAuth atas nama pengguna.
+// Token dirotasi setiap dipakai; semua token hasil rotasi berbagi FamilyID sehingga pemakaian ulang
+// token lama (tanda token dicuri) bisa mencabut seluruh keluarga sekaligus.
+type OAuthRefreshToken struct {
+	ID        uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	TokenHash string     `gorm:"not null;uniqueIndex" json:"-"`
+	FamilyID  uuid.UUID  `gorm:"type:uuid;not null;index" json:"family_id"`
+	ClientID  string     `gorm:"not null;index" json:"client_id"`
+	UserID    uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	SessionID uuid.UUID  `gorm:"type:uuid;not null" json:"session_id"`
+	Scopes    []string   `gorm:"serializer:json;not null" json:"scopes"`
+	ExpiresAt time.Time  `gorm:"not null" json:"expires_at"`
+	UsedAt    *time.Time `json:"used_at,omitempty"` // Terisi saat token dirotasi
+	RevokedAt *time.Time `json:"revoked_at,omitempty"`
+	CreatedAt time.Time  `json:"created_at"`
+}
+
+// IsActive mengembalikan true jika token belum dipakai, belum dicabut dan belum kedaluwarsa pada waktu now.
+func (t *OAuthRefreshToken) IsActive(now time.Time) bool {
+	return t.UsedAt == nil && t.RevokedAt == nil && now.Before(t.ExpiresAt)
+}
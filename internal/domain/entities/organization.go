@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// Organization adalah tenant: satu pelanggan dengan anggota dan penetapan Role-nya sendiri.
+// Pengguna bisa menjadi anggota beberapa organisasi sekaligus dengan Role yang berbeda di masing-masing.
+type Organization struct {
+	ID        uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name      string         `gorm:"not null" json:"name"`
+	Slug      string         `gorm:"not null;uniqueIndex" json:"slug"` // Dipakai di subdomain dan header X-Organization
+	CreatedAt time.Time      `json:"created_at"`
+	UpdatedAt time.Time      `json:"updated_at"`
+	DeletedAt gorm.DeletedAt `gorm:"index" json:"-"`
+}
+
+// OrganizationMember adalah keanggotaan User di Organization beserta Role yang berlaku di organisasi tersebut.
+// Role di sini terpisah dari User.Roles yang berlaku di luar konteks organisasi.
+type OrganizationMember struct {
+	ID             uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	OrganizationID uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_organization_member" json:"organization_id"`
+	UserID         uuid.UUID `gorm:"type:uuid;not null;uniqueIndex:idx_organization_member;index" json:"user_id"`
+	User           *User     `json:"user,omitempty"`
+	Roles          []*Role   `gorm:"many2many:organization_member_roles;" json:"roles"`
+	CreatedAt      time.Time `json:"created_at"`
+}
+
+// HasRole mengembalikan true jika anggota memiliki role dengan nama tersebut di organisasinya.
+// Roles harus sudah dimuat (preload) sebelum memanggil metode ini.
+func (m *OrganizationMember) HasRole(name string) bool {
+	for _, role := range m.Roles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
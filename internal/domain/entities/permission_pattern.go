@@ -0,0 +1,173 @@
+package entities
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/google/uuid"
+)
+
+// Tata bahasa nama permission:
+//
+//	permission := ["!"] resource ":" action
+//	resource   := segmen ("/" segmen)*
+//	segmen     := nama | "*" | "**"
+//	action     := nama | "*"
+//
+// Contoh: "users:read", "users:*", "orgs/3f2a.../users:write", "orgs/*/users:read", "!users:delete".
+// "*" cocok dengan tepat satu segmen atau aksi apa pun; "**" hanya boleh menjadi segmen terakhir dan cocok
+// dengan nol atau lebih segmen sisanya, sehingga "**:*" berarti semua permission. Awalan "!" menjadikannya
+// deny eksplisit yang selalu mengalahkan allow.
+const (
+	PermissionDenyPrefix  = "!"
+	PermissionWildcard    = "*"
+	PermissionWildcardAll = "**"
+)
+
+// permissionTokenPattern membatasi nama segmen resource dan aksi.
+var permissionTokenPattern = regexp.MustCompile(`^[A-Za-z0-9_.-]+$`)
+
+// PermissionPattern adalah nama permission yang sudah diurai.
+type PermissionPattern struct {
+	Deny     bool
+	Resource []string // Segmen resource, misalnya ["orgs", "<id>", "users"]
+	Action   string
+}
+
+// ParsePermission mengurai nama permission. ok bernilai false jika nama tidak sesuai tata bahasa.
+func ParsePermission(name string) (pattern PermissionPattern, ok bool) {
+	name, pattern.Deny = strings.CutPrefix(name, PermissionDenyPrefix)
+
+	resource, action, found := strings.Cut(name, ":")
+	if !found || (action != PermissionWildcard && !permissionTokenPattern.MatchString(action)) {
+		return PermissionPattern{}, false
+	}
+	pattern.Action = action
+
+	pattern.Resource = strings.Split(resource, "/")
+	for n, segment := range pattern.Resource {
+		switch {
+		case segment == PermissionWildcard:
+		case segment == PermissionWildcardAll && n == len(pattern.Resource)-1:
+		case permissionTokenPattern.MatchString(segment):
+		default:
+			return PermissionPattern{}, false
+		}
+	}
+	return pattern, true
+}
+
+// IsConcrete mengembalikan true jika pattern adalah allow tanpa wildcard, yaitu bentuk permission yang
+// diperiksa saat otorisasi.
+func (p PermissionPattern) IsConcrete() bool {
+	if p.Deny || p.Action == PermissionWildcard {
+		return false
+	}
+	for _, segment := range p.Resource {
+		if segment == PermissionWildcard || segment == PermissionWildcardAll {
+			return false
+		}
+	}
+	return true
+}
+
+// Matches mengembalikan true jika pattern mencakup permission. Permission harus konkret; wildcard dan
+// tanda deny pada permission tidak pernah cocok.
+func (p PermissionPattern) Matches(permission PermissionPattern) bool {
+	if !permission.IsConcrete() {
+		return false
+	}
+	if p.Action != PermissionWildcard && p.Action != permission.Action {
+		return false
+	}
+	for n, segment := range p.Resource {
+		if segment == PermissionWildcardAll {
+			return true
+		}
+		if n >= len(permission.Resource) {
+			return false
+		}
+		if segment != PermissionWildcard && segment != permission.Resource[n] {
+			return false
+		}
+	}
+	return len(p.Resource) == len(permission.Resource)
+}
+
+// String mengembalikan nama permission dari pattern.
+func (p PermissionPattern) String() string {
+	name := strings.Join(p.Resource, "/") + ":" + p.Action
+	if p.Deny {
+		return PermissionDenyPrefix + name
+	}
+	return name
+}
+
+// PermissionMatches mengembalikan true jika nama permission pattern (allow maupun deny) mencakup permission.
+func PermissionMatches(pattern, permission string) bool {
+	parsedPattern, ok := ParsePermission(pattern)
+	if !ok {
+		return false
+	}
+	parsedPermission, ok := ParsePermission(permission)
+	return ok && parsedPattern.Matches(parsedPermission)
+}
+
+// OrganizationPermission mengembalikan bentuk permission yang dibatasi ke satu organisasi,
+// misalnya "orgs/<id>/users:write" untuk "users:write".
+func OrganizationPermission(organizationID uuid.UUID, permission string) string {
+	return "orgs/" + organizationID.String() + "/" + permission
+}
+
+// PermissionSet adalah kumpulan permission yang diberikan, dipisah menjadi allow dan deny.
+// Nama yang tidak sesuai tata bahasa diabaikan.
+type PermissionSet struct {
+	allow []PermissionPattern
+	deny  []PermissionPattern
+}
+
+// NewPermissionSet membuat PermissionSet dari nama-nama permission.
+func NewPermissionSet(names []string) PermissionSet {
+	var set PermissionSet
+	for _, name := range names {
+		pattern, ok := ParsePermission(name)
+		if !ok {
+			continue
+		}
+		if pattern.Deny {
+			set.deny = append(set.deny, pattern)
+		} else {
+			set.allow = append(set.allow, pattern)
+		}
+	}
+	return set
+}
+
+// Allows mengembalikan true jika permission dicakup salah satu allow dan tidak dicakup deny mana pun.
+func (s PermissionSet) Allows(permission string) bool {
+	parsed, ok := ParsePermission(permission)
+	if !ok || !parsed.IsConcrete() || s.denies(parsed) {
+		return false
+	}
+	for _, pattern := range s.allow {
+		if pattern.Matches(parsed) {
+			return true
+		}
+	}
+	return false
+}
+
+// Denies mengembalikan true jika permission dicakup salah satu deny eksplisit.
+func (s PermissionSet) Denies(permission string) bool {
+	parsed, ok := ParsePermission(permission)
+	return ok && s.denies(parsed)
+}
+
+func (s PermissionSet) denies(permission PermissionPattern) bool {
+	for _, pattern := range s.deny {
+		if pattern.Matches(permission) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,114 @@
+package entities
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/google/uuid"
+)
+
+func TestParsePermission(t *testing.T) {
+	tests := []struct {
+		name string
+		want PermissionPattern
+		ok   bool
+	}{
+		{"users:read", PermissionPattern{Resource: []string{"users"}, Action: "read"}, true},
+		{"users:*", PermissionPattern{Resource: []string{"users"}, Action: "*"}, true},
+		{"*:*", PermissionPattern{Resource: []string{"*"}, Action: "*"}, true},
+		{"**:*", PermissionPattern{Resource: []string{"**"}, Action: "*"}, true},
+		{"orgs/*/users:write", PermissionPattern{Resource: []string{"orgs", "*", "users"}, Action: "write"}, true},
+		{"orgs/**:read", PermissionPattern{Resource: []string{"orgs", "**"}, Action: "read"}, true},
+		{"!users:delete", PermissionPattern{Deny: true, Resource: []string{"users"}, Action: "delete"}, true},
+		{"scim:provision", PermissionPattern{Resource: []string{"scim"}, Action: "provision"}, true},
+
+		{"", PermissionPattern{}, false},
+		{"users", PermissionPattern{}, false},
+		{"users:", PermissionPattern{}, false},
+		{":read", PermissionPattern{}, false},
+		{"users:re*d", PermissionPattern{}, false},
+		{"users:read:write", PermissionPattern{}, false},
+		{"users:**", PermissionPattern{}, false},
+		{"orgs//users:read", PermissionPattern{}, false},
+		{"orgs/**/users:read", PermissionPattern{}, false}, // "**" hanya boleh menjadi segmen terakhir
+		{"us ers:read", PermissionPattern{}, false},
+		{"!!users:read", PermissionPattern{}, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, ok := ParsePermission(tt.name)
+			if ok != tt.ok || !reflect.DeepEqual(got, tt.want) {
+				t.Fatalf("ParsePermission(%q) = %+v, %v; ingin %+v, %v", tt.name, got, ok, tt.want, tt.ok)
+			}
+			if ok && got.String() != tt.name {
+				t.Fatalf("String() = %q, ingin %q", got.String(), tt.name)
+			}
+		})
+	}
+}
+
+func TestPermissionSetAllows(t *testing.T) {
+	org := uuid.MustParse("3f2a0000-0000-4000-8000-000000000001")
+	other := uuid.MustParse("3f2a0000-0000-4000-8000-000000000002")
+
+	tests := []struct {
+		name       string
+		granted    []string
+		permission string
+		want       bool
+	}{
+		{"cocok persis", []string{"users:read"}, "users:read", true},
+		{"aksi lain", []string{"users:read"}, "users:write", false},
+		{"resource lain", []string{"users:read"}, "roles:read", false},
+		{"wildcard aksi", []string{"users:*"}, "users:delete", true},
+		{"wildcard aksi tidak melewati resource", []string{"users:*"}, "roles:read", false},
+		{"wildcard satu segmen", []string{"*:*"}, "roles:write", true},
+		{"wildcard satu segmen tidak mencakup resource bertingkat", []string{"*:*"}, "orgs/" + org.String() + "/users:write", false},
+		{"wildcard semua segmen", []string{"**:*"}, "orgs/" + org.String() + "/users:write", true},
+		{"organisasi tertentu", []string{"orgs/" + org.String() + "/users:write"}, "orgs/" + org.String() + "/users:write", true},
+		{"organisasi lain", []string{"orgs/" + org.String() + "/users:write"}, "orgs/" + other.String() + "/users:write", false},
+		{"semua organisasi", []string{"orgs/*/users:write"}, "orgs/" + org.String() + "/users:write", true},
+		{"semua organisasi aksi lain", []string{"orgs/*/users:read"}, "orgs/" + org.String() + "/users:write", false},
+		{"permission global tidak berlaku di organisasi", []string{"users:write"}, "orgs/" + org.String() + "/users:write", false},
+		{"permission organisasi tidak berlaku global", []string{"orgs/*/users:write"}, "users:write", false},
+		{"deny mengalahkan allow", []string{"users:*", "!users:delete"}, "users:delete", false},
+		{"deny tidak mencakup aksi lain", []string{"users:*", "!users:delete"}, "users:read", true},
+		{"deny wildcard mengalahkan allow persis", []string{"users:delete", "!users:*"}, "users:delete", false},
+		{"deny semua organisasi mengalahkan organisasi tertentu", []string{"orgs/" + org.String() + "/users:write", "!orgs/*/users:write"}, "orgs/" + org.String() + "/users:write", false},
+		{"deny saja tidak mengizinkan", []string{"!users:delete"}, "users:read", false},
+		{"nama tidak valid diabaikan", []string{"users", "users:re*d", "users:read"}, "users:read", true},
+		{"permission yang diperiksa tidak boleh wildcard", []string{"**:*"}, "users:*", false},
+		{"permission yang diperiksa tidak boleh deny", []string{"**:*"}, "!users:read", false},
+		{"permission yang diperiksa tidak valid", []string{"**:*"}, "users", false},
+		{"tanpa permission", nil, "users:read", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := NewPermissionSet(tt.granted).Allows(tt.permission); got != tt.want {
+				t.Fatalf("Allows(%q) dengan %v = %v, ingin %v", tt.permission, tt.granted, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestPermissionSetDenies(t *testing.T) {
+	set := NewPermissionSet([]string{"users:*", "!users:delete", "!orgs/*/roles:write"})
+
+	tests := []struct {
+		permission string
+		want       bool
+	}{
+		{"users:delete", true},
+		{"users:read", false},
+		{"orgs/" + uuid.NewString() + "/roles:write", true},
+		{"roles:write", false},
+		{"users", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.permission, func(t *testing.T) {
+			if got := set.Denies(tt.permission); got != tt.want {
+				t.Fatalf("Denies(%q) = %v, ingin %v", tt.permission, got, tt.want)
+			}
+		})
+	}
+}
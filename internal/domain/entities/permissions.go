@@ -7,6 +7,45 @@ import (
 	"gorm.io/gorm"
 )
 
+// Nama permission bawaan yang dipakai untuk membatasi rute. Scope API key memakai nama yang sama.
+const (
+	PermissionUsersRead   = "users:read"
+	PermissionUsersWrite  = "users:write"
+	PermissionUsersDelete = "users:delete"
+
+	// PermissionMembersRead dan PermissionMembersWrite mengatur pengelolaan anggota organisasi dan Role-nya.
+	PermissionMembersRead  = "members:read"
+	PermissionMembersWrite = "members:write"
+
+	// PermissionSCIMProvision mengizinkan klien provisioning (Okta, Azure AD) mengelola pengguna dan grup lewat SCIM.
+	PermissionSCIMProvision = "scim:provision"
+
+	// PermissionAuthzCheck mengizinkan layanan lain menanyakan keputusan akses lewat POST /authz/check.
+	PermissionAuthzCheck = "authz:check"
+
+	// PermissionRoleRequestsApprove mengizinkan pengguna menyetujui atau menolak permintaan Role sementara orang lain.
+	PermissionRoleRequestsApprove = "role-requests:approve"
+
+	// PermissionUsersImpersonate mengizinkan superuser masuk sebagai pengguna lain. Status superuser saja
+	// tidak cukup; permission ini harus diberikan lewat Role.
+	PermissionUsersImpersonate = "users:impersonate"
+
+	// ScopeAll memberi API key semua permission pemiliknya, termasuk rute khusus superuser.
+	ScopeAll = "*"
+)
+
+// Scope OpenID Connect. Scope ini bukan Permission: hanya mengatur ID token dan klaim /userinfo.
+const (
+	ScopeOpenID  = "openid"
+	ScopeProfile = "profile"
+	ScopeEmail   = "email"
+)
+
+// IsOIDCScope mengembalikan true jika scope adalah scope OpenID Connect, bukan nama Permission.
+func IsOIDCScope(scope string) bool {
+	return scope == ScopeOpenID || scope == ScopeProfile || scope == ScopeEmail
+}
+
 type Permission struct {
 	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
 	Name        string         `gorm:"unique;not null" json:"name"`
@@ -0,0 +1,41 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Status RoleRequest.
+const (
+	RoleRequestPending   = "pending"
+	RoleRequestApproved  = "approved"
+	RoleRequestRejected  = "rejected"
+	RoleRequestCancelled = "cancelled"
+)
+
+// RoleRequest adalah permintaan swalayan pengguna untuk memegang Role tambahan selama Hours jam.
+// Jika disetujui, Role diberikan sebagai UserRole yang berakhir otomatis pada GrantExpiresAt. Untuk Role
+// yang punya ApprovalPolicy, persetujuan meneruskan permintaan ke AssignmentRequest dan Role baru
+// diberikan setelah kebijakannya terpenuhi.
+type RoleRequest struct {
+	ID                  uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID              uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	User                *User      `json:"user,omitempty"`
+	RoleID              uuid.UUID  `gorm:"type:uuid;not null" json:"role_id"`
+	Role                *Role      `json:"role,omitempty"`
+	Hours               int        `gorm:"not null" json:"hours"`
+	Reason              string     `gorm:"not null" json:"reason"`
+	Status              string     `gorm:"not null;index;default:pending" json:"status"`
+	ReviewedBy          *uuid.UUID `gorm:"type:uuid" json:"reviewed_by,omitempty"`
+	ReviewedAt          *time.Time `json:"reviewed_at,omitempty"`
+	GrantExpiresAt      *time.Time `json:"grant_expires_at,omitempty"`                       // Akhir penetapan Role jika disetujui
+	AssignmentRequestID *uuid.UUID `gorm:"type:uuid" json:"assignment_request_id,omitempty"` // Terisi jika Role menunggu persetujuan kebijakan
+	CreatedAt           time.Time  `json:"created_at"`
+	UpdatedAt           time.Time  `json:"updated_at"`
+}
+
+// IsPending mengembalikan true jika permintaan belum diputuskan atau dibatalkan.
+func (r *RoleRequest) IsPending() bool {
+	return r.Status == RoleRequestPending
+}
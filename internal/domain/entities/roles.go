@@ -7,12 +7,19 @@ import (
 	"gorm.io/gorm"
 )
 
+// Role adalah kumpulan Permission yang diberikan ke User, anggota organisasi atau Group.
+// Role bisa mewarisi Role lain lewat Parents, misalnya admin mewarisi editor dan editor mewarisi viewer,
+// sehingga permission efektif sebuah Role adalah Permissions-nya ditambah permission efektif semua Parents.
 type Role struct {
 	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
 	Name        string         `gorm:"unique;not null" json:"name"`
 	Description string         `json:"description"`
+	OwnerID     *uuid.UUID     `gorm:"type:uuid" json:"owner_id,omitempty"`        // Penanggung jawab Role, misalnya peninjau tinjauan akses
+	SCIMManaged bool           `gorm:"not null;default:false" json:"scim_managed"` // Role boleh dilihat dan diubah klien SCIM sebagai Group
+	TenantRole  bool           `gorm:"not null;default:false" json:"tenant_role"`  // Role boleh diberikan admin organisasi ke anggotanya
 	Users       []*User        `gorm:"many2many:user_roles;" json:"users,omitempty"`
 	Permissions []*Permission  `gorm:"many2many:role_permissions;" json:"permissions,omitempty"`
+	Parents     []*Role        `gorm:"many2many:role_parents;joinForeignKey:RoleID;joinReferences:ParentID" json:"parents,omitempty"`
 	CreatedAt   time.Time      `json:"created_at"`
 	UpdatedAt   time.Time      `json:"updated_at"`
 	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
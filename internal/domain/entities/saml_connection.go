@@ -0,0 +1,58 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SAMLAttributeMapping adalah nama atribut assertion SAML yang dipetakan ke field User.
+// Nama dicocokkan dengan Name maupun FriendlyName atribut.
+type SAMLAttributeMapping struct {
+	Username  string `json:"username,omitempty"` // Kosong berarti username diambil dari bagian lokal email
+	Email     string `json:"email"`              // Kosong atau tidak terkirim berarti NameID dipakai jika berupa alamat email
+	FirstName string `json:"first_name"`
+	LastName  string `json:"last_name"`
+	Groups    string `json:"groups"`
+}
+
+// SAMLConnection adalah satu identity provider SAML 2.0 perusahaan yang dipercaya untuk login.
+// Setiap koneksi memiliki metadata service provider dan ACS sendiri di /auth/saml/:name.
+type SAMLConnection struct {
+	ID                uuid.UUID            `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name              string               `gorm:"not null;uniqueIndex" json:"name"` // Dipakai di URL dan sebagai provider identitas "saml:<name>"
+	DisplayName       string               `gorm:"not null" json:"display_name"`
+	IdPEntityID       string               `gorm:"not null" json:"idp_entity_id"`
+	IdPMetadata       string               `gorm:"type:text;not null" json:"-"` // XML EntityDescriptor, sumber sertifikat penanda tangan IdP
+	MetadataURL       string               `json:"metadata_url,omitempty"`      // Asal metadata jika diimpor dari URL
+	AllowIDPInitiated bool                 `gorm:"not null;default:false" json:"allow_idp_initiated"`
+	AllowSignup       bool                 `gorm:"not null;default:false" json:"allow_signup"` // Buat akun saat login pertama jika tidak ada email yang cocok
+	AttributeMapping  SAMLAttributeMapping `gorm:"serializer:json;not null" json:"attribute_mapping"`
+	GroupRoles        map[string][]string  `gorm:"serializer:json;not null" json:"group_roles"` // Nilai atribut grup -> nama Role yang dikelola koneksi ini
+	EmailDomains      []string             `gorm:"serializer:json" json:"email_domains"`        // Domain email milik IdP; hanya email di domain ini yang dipakai untuk menghubungkan akun
+	CreatedBy         uuid.UUID            `gorm:"type:uuid;not null" json:"created_by"`
+	CreatedAt         time.Time            `json:"created_at"`
+	UpdatedAt         time.Time            `json:"updated_at"`
+}
+
+// ProviderName mengembalikan nama provider LinkedIdentity untuk pengguna yang login lewat koneksi ini.
+func (c *SAMLConnection) ProviderName() string {
+	return "saml:" + c.Name
+}
+
+// TrustsEmail mengembalikan true jika domain email termasuk EmailDomains koneksi. IdP hanya berwenang
+// atas alamat di domain perusahaannya sendiri, sehingga email di luar domain itu tidak dianggap terverifikasi.
+func (c *SAMLConnection) TrustsEmail(email string) bool {
+	at := strings.LastIndex(email, "@")
+	if at < 0 {
+		return false
+	}
+	domain := strings.ToLower(email[at+1:])
+	for _, trusted := range c.EmailDomains {
+		if domain == trusted {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Session adalah satu sesi login milik seorang User di sebuah perangkat.
+// Setiap token akses terikat ke satu Session sehingga sesi bisa dicabut dari sisi server.
+type Session struct {
+	ID         uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID     uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Device     string     `json:"device"` // Ringkasan perangkat dari User-Agent, misalnya "Chrome di Windows"
+	UserAgent  string     `json:"user_agent"`
+	IPAddress  string     `json:"ip_address"`
+	CreatedAt  time.Time  `json:"created_at"`
+	LastSeenAt time.Time  `json:"last_seen_at"`
+	ExpiresAt  time.Time  `gorm:"not null;index" json:"expires_at"`
+	RevokedAt  *time.Time `json:"revoked_at,omitempty"`
+
+	// ImpersonatorID terisi jika sesi dibuat oleh superuser yang masuk sebagai UserID (impersonasi)
+	ImpersonatorID *uuid.UUID `gorm:"type:uuid;index" json:"impersonator_id,omitempty"`
+}
+
+// IsImpersonation mengembalikan true jika sesi adalah sesi impersonasi.
+func (s *Session) IsImpersonation() bool {
+	return s.ImpersonatorID != nil
+}
+
+// IsActive mengembalikan true jika sesi belum dicabut dan belum kedaluwarsa pada waktu now.
+func (s *Session) IsActive(now time.Time) bool {
+	return s.RevokedAt == nil && now.Before(s.ExpiresAt)
+}
@@ -0,0 +1,30 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// Algoritma tanda tangan token yang didukung.
+const (
+	SigningAlgorithmRS256 = "RS256"
+	SigningAlgorithmEdDSA = "EdDSA"
+)
+
+// SigningKey adalah kunci asimetris untuk menandatangani token. ID dipakai sebagai "kid" di header JWT.
+// Kunci dipublikasikan di JWKS sejak dibuat, dipakai menandatangani mulai SigningFrom sampai kunci
+// berikutnya aktif, lalu tetap dipublikasikan sampai ExpiresAt agar token yang sudah terbit masih bisa diverifikasi.
+type SigningKey struct {
+	ID          uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"kid"`
+	Algorithm   string     `gorm:"not null" json:"alg"`
+	PrivateKey  []byte     `gorm:"type:bytea;not null" json:"-"` // PKCS#8 DER
+	SigningFrom time.Time  `gorm:"not null;index" json:"signing_from"`
+	ExpiresAt   *time.Time `gorm:"index" json:"expires_at,omitempty"` // Nil selama kunci belum digantikan
+	CreatedAt   time.Time  `json:"created_at"`
+}
+
+// IsValid mengembalikan true jika kunci masih boleh dipakai untuk verifikasi pada waktu now.
+func (k *SigningKey) IsValid(now time.Time) bool {
+	return k.ExpiresAt == nil || now.Before(*k.ExpiresAt)
+}
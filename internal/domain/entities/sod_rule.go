@@ -0,0 +1,34 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// SoDRule adalah aturan pemisahan tugas (separation of duties) statis: tidak ada pengguna yang boleh
+// memegang lebih dari satu Role di Roles, baik secara langsung, lewat Group, maupun lewat pewarisan Role.
+type SoDRule struct {
+	ID          uuid.UUID `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	Name        string    `gorm:"unique;not null" json:"name"`
+	Description string    `json:"description"`
+	Roles       []*Role   `gorm:"many2many:sod_rule_roles;joinForeignKey:RuleID" json:"roles,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+}
+
+// TableName mengembalikan nama tabel SoDRule.
+func (SoDRule) TableName() string {
+	return "sod_rules"
+}
+
+// Conflicts mengembalikan Role aturan yang ada di held. Aturan dilanggar jika hasilnya lebih dari satu.
+func (r *SoDRule) Conflicts(held map[uuid.UUID]bool) []*Role {
+	var conflicting []*Role
+	for _, role := range r.Roles {
+		if held[role.ID] {
+			conflicting = append(conflicting, role)
+		}
+	}
+	return conflicting
+}
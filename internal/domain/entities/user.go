@@ -1,8 +1,10 @@
 package entities
 
 import (
+	"strings"
 	"time"
 
+	"github.com/go-webauthn/webauthn/webauthn"
 	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
@@ -12,16 +14,75 @@ import (
 // Tag `gorm` digunakan untuk pemetaan ORM GORM ke kolom database.
 // Tag `json` digunakan untuk serialisasi/deserialisasi JSON saat berinteraksi dengan API.
 type User struct {
-	ID          uuid.UUID      `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
-	Username    string         `gorm:"unique;not null" json:"username"`
-	Email       string         `gorm:"unique;not null" json:"email"`
-	Password    string         `gorm:"not null" json:"-"`
-	FirstName   string         `json:"first_name"`
-	LastName    string         `json:"last_name"`
-	IsSuperuser bool           `gorm:"not null;default:false" json:"is_superuser"`
-	IsActive    bool           `gorm:"default:true" json:"is_active"`
-	Roles       []*Role        `gorm:"many2many:user_roles;" json:"roles"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                  uuid.UUID            `gorm:"type:uuid;default:uuid_generate_v4();primaryKey"`
+	Username            string               `gorm:"unique;not null" json:"username"`
+	Email               string               `gorm:"unique;not null" json:"email"`
+	Password            string               `gorm:"not null" json:"-"`
+	FirstName           string               `json:"first_name"`
+	LastName            string               `json:"last_name"`
+	IsSuperuser         bool                 `gorm:"not null;default:false" json:"is_superuser"`
+	IsActive            bool                 `gorm:"default:true" json:"is_active"`
+	IsServiceAccount    bool                 `gorm:"not null;default:false" json:"is_service_account"` // Akun mesin, hanya bisa memakai API key
+	FailedLoginAttempts int                  `gorm:"not null;default:0" json:"failed_login_attempts"`
+	LockedUntil         *time.Time           `json:"locked_until,omitempty"`
+	LastFailedLoginAt   *time.Time           `json:"last_failed_login_at,omitempty"`
+	LastLoginAt         *time.Time           `json:"last_login_at,omitempty"`
+	Attributes          map[string]any       `gorm:"serializer:json" json:"attributes,omitempty"` // Atribut bebas untuk kebijakan ABAC, misalnya department
+	MFAEnabled          bool                 `gorm:"not null;default:false" json:"mfa_enabled"`
+	TOTPSecret          string               `json:"-"` // Diisi saat enrollment dimulai, aktif setelah MFAEnabled
+	Roles               []*Role              `gorm:"many2many:user_roles;" json:"roles"`
+	Passkeys            []WebAuthnCredential `gorm:"foreignKey:UserID" json:"-"` // Dimuat hanya saat ceremony WebAuthn
+	CreatedAt           time.Time            `json:"created_at"`
+	UpdatedAt           time.Time            `json:"updated_at"`
+	DeletedAt           gorm.DeletedAt       `gorm:"index" json:"-"`
+}
+
+// IsLocked mengembalikan true jika akun sedang dikunci sementara pada waktu now.
+func (u *User) IsLocked(now time.Time) bool {
+	return u.LockedUntil != nil && now.Before(*u.LockedUntil)
+}
+
+// HasRole mengembalikan true jika pengguna memiliki role dengan nama tersebut.
+// Roles harus sudah dimuat (preload) sebelum memanggil metode ini.
+func (u *User) HasRole(name string) bool {
+	for _, role := range u.Roles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// WebAuthnID mengimplementasikan webauthn.User. User handle adalah 16 byte UUID pengguna
+// sehingga pengguna bisa ditemukan kembali dari passkey tanpa username.
+func (u *User) WebAuthnID() []byte {
+	return u.ID[:]
+}
+
+// WebAuthnName mengimplementasikan webauthn.User.
+func (u *User) WebAuthnName() string {
+	return u.Username
+}
+
+// WebAuthnDisplayName mengimplementasikan webauthn.User.
+func (u *User) WebAuthnDisplayName() string {
+	if name := strings.TrimSpace(u.FirstName + " " + u.LastName); name != "" {
+		return name
+	}
+	return u.Username
+}
+
+// WebAuthnIcon mengimplementasikan webauthn.User. Ikon sudah tidak dipakai oleh spesifikasi.
+func (u *User) WebAuthnIcon() string {
+	return ""
+}
+
+// WebAuthnCredentials mengimplementasikan webauthn.User.
+// Passkeys harus sudah dimuat sebelum memanggil metode ini.
+func (u *User) WebAuthnCredentials() []webauthn.Credential {
+	credentials := make([]webauthn.Credential, 0, len(u.Passkeys))
+	for n := range u.Passkeys {
+		credentials = append(credentials, u.Passkeys[n].ToWebAuthn())
+	}
+	return credentials
 }
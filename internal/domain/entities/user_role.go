@@ -0,0 +1,40 @@
+package entities
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// UserRole adalah penetapan Role langsung ke User, yaitu baris tabel join user_roles. Penetapan bisa
+// dibatasi waktu: Role hanya berlaku mulai StartsAt sampai sebelum ExpiresAt. Nilai kosong berarti tanpa
+// batas, sehingga penetapan lama dan penetapan dari SCIM/LDAP tetap permanen. Penetapan yang sudah lewat
+// ExpiresAt dihapus oleh worker.
+type UserRole struct {
+	UserID    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"user_id"`
+	RoleID    uuid.UUID  `gorm:"type:uuid;primaryKey" json:"role_id"`
+	Role      *Role      `json:"role,omitempty"`
+	StartsAt  *time.Time `json:"starts_at,omitempty"`
+	ExpiresAt *time.Time `gorm:"index" json:"expires_at,omitempty"`
+	Reason    string     `json:"reason,omitempty"`
+	GrantedBy *uuid.UUID `gorm:"type:uuid" json:"granted_by,omitempty"` // Kosong untuk penetapan otomatis, misalnya dari SCIM
+	GrantedAt *time.Time `json:"granted_at,omitempty"`                  // Kosong untuk penetapan sebelum pembatasan waktu ada
+}
+
+// TableName mengembalikan nama tabel join many2many User.Roles.
+func (UserRole) TableName() string {
+	return "user_roles"
+}
+
+// IsActive mengembalikan true jika penetapan berlaku pada waktu now.
+func (g *UserRole) IsActive(now time.Time) bool {
+	if g.StartsAt != nil && now.Before(*g.StartsAt) {
+		return false
+	}
+	return g.ExpiresAt == nil || now.Before(*g.ExpiresAt)
+}
+
+// IsTemporary mengembalikan true jika penetapan akan berakhir dengan sendirinya.
+func (g *UserRole) IsTemporary() bool {
+	return g.ExpiresAt != nil
+}
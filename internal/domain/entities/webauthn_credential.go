@@ -0,0 +1,82 @@
+package entities
+
+import (
+	"strings"
+	"time"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+// WebAuthnCredential adalah passkey (kredensial WebAuthn) yang didaftarkan oleh seorang User.
+// Satu User boleh memiliki beberapa kredensial, masing-masing dengan nama yang mudah dikenali.
+type WebAuthnCredential struct {
+	ID              uuid.UUID  `gorm:"type:uuid;default:uuid_generate_v4();primaryKey" json:"id"`
+	UserID          uuid.UUID  `gorm:"type:uuid;not null;index" json:"user_id"`
+	Name            string     `gorm:"not null" json:"name"`
+	CredentialID    []byte     `gorm:"type:bytea;not null;uniqueIndex" json:"-"`
+	PublicKey       []byte     `gorm:"type:bytea;not null" json:"-"`
+	AttestationType string     `json:"attestation_type"`
+	AAGUID          []byte     `gorm:"type:bytea" json:"-"`
+	SignCount       uint32     `gorm:"not null;default:0" json:"-"`
+	Transports      string     `json:"transports"` // Dipisahkan koma, misalnya "internal,hybrid"
+	BackupEligible  bool       `gorm:"not null;default:false" json:"backup_eligible"`
+	BackupState     bool       `gorm:"not null;default:false" json:"backup_state"`
+	LastUsedAt      *time.Time `json:"last_used_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
+// NewWebAuthnCredential membuat entitas dari kredensial hasil ceremony registrasi.
+func NewWebAuthnCredential(userID uuid.UUID, name string, credential *webauthn.Credential) *WebAuthnCredential {
+	transports := make([]string, 0, len(credential.Transport))
+	for _, transport := range credential.Transport {
+		transports = append(transports, string(transport))
+	}
+
+	return &WebAuthnCredential{
+		UserID:          userID,
+		Name:            name,
+		CredentialID:    credential.ID,
+		PublicKey:       credential.PublicKey,
+		AttestationType: credential.AttestationType,
+		AAGUID:          credential.Authenticator.AAGUID,
+		SignCount:       credential.Authenticator.SignCount,
+		Transports:      strings.Join(transports, ","),
+		BackupEligible:  credential.Flags.BackupEligible,
+		BackupState:     credential.Flags.BackupState,
+	}
+}
+
+// ToWebAuthn mengubah entitas menjadi bentuk yang dipakai library WebAuthn saat verifikasi.
+func (c *WebAuthnCredential) ToWebAuthn() webauthn.Credential {
+	var transports []protocol.AuthenticatorTransport
+	for _, transport := range strings.Split(c.Transports, ",") {
+		if transport != "" {
+			transports = append(transports, protocol.AuthenticatorTransport(transport))
+		}
+	}
+
+	return webauthn.Credential{
+		ID:              c.CredentialID,
+		PublicKey:       c.PublicKey,
+		AttestationType: c.AttestationType,
+		Transport:       transports,
+		Flags: webauthn.CredentialFlags{
+			BackupEligible: c.BackupEligible,
+			BackupState:    c.BackupState,
+		},
+		Authenticator: webauthn.Authenticator{
+			AAGUID:    c.AAGUID,
+			SignCount: c.SignCount,
+		},
+	}
+}
+
+// MarkUsed mencatat hasil assertion yang berhasil: penghitung tanda tangan, status backup dan waktu pakai.
+func (c *WebAuthnCredential) MarkUsed(credential *webauthn.Credential, now time.Time) {
+	c.SignCount = credential.Authenticator.SignCount
+	c.BackupState = credential.Flags.BackupState
+	c.LastUsedAt = &now
+}
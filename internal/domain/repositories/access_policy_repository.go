@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// AccessPolicyRepository mendefinisikan kontrak persistensi AccessPolicy.
+type AccessPolicyRepository interface {
+	// Create menambahkan AccessPolicy baru. Nama kebijakan bersifat unik.
+	Create(policy *entities.AccessPolicy) error
+	// FindByID mencari AccessPolicy berdasarkan ID.
+	FindByID(id uuid.UUID) (*entities.AccessPolicy, error)
+	// FindByName mencari AccessPolicy berdasarkan nama.
+	FindByName(name string) (*entities.AccessPolicy, error)
+	// FindAll mengembalikan semua AccessPolicy, diurutkan berdasarkan nama.
+	FindAll() ([]entities.AccessPolicy, error)
+	// FindEnabled mengembalikan AccessPolicy yang aktif, diurutkan berdasarkan nama.
+	FindEnabled() ([]entities.AccessPolicy, error)
+	// Update menyimpan perubahan AccessPolicy.
+	Update(policy *entities.AccessPolicy) error
+	// Delete menghapus AccessPolicy. Gagal dengan gorm.ErrRecordNotFound jika tidak ada.
+	Delete(id uuid.UUID) error
+}
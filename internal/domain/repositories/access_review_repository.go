@@ -0,0 +1,32 @@
+package repositories
+
+import (
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// AccessReviewRepository mendefinisikan kontrak persistensi kampanye tinjauan akses beserta itemnya.
+type AccessReviewRepository interface {
+	// CreateCampaign menyimpan kampanye beserta seluruh Items-nya dalam satu transaksi.
+	CreateCampaign(campaign *entities.AccessReviewCampaign) error
+	// FindCampaign mencari kampanye berdasarkan ID beserta Items-nya.
+	FindCampaign(id uuid.UUID) (*entities.AccessReviewCampaign, error)
+	// FindCampaigns mengembalikan semua kampanye tanpa Items, terbaru lebih dulu.
+	FindCampaigns() ([]entities.AccessReviewCampaign, error)
+	// FindDueCampaigns mengembalikan kampanye aktif yang batas waktunya sudah lewat pada now.
+	FindDueCampaigns(now time.Time) ([]entities.AccessReviewCampaign, error)
+	// CompleteCampaign menandai kampanye selesai. Mengembalikan false jika kampanye sudah tidak aktif.
+	CompleteCampaign(id uuid.UUID, at time.Time) (bool, error)
+	// FindItem mencari item tinjauan berdasarkan ID.
+	FindItem(id uuid.UUID) (*entities.AccessReviewItem, error)
+	// FindPendingItemsByReviewer mengembalikan item yang belum diputuskan di kampanye aktif untuk peninjau.
+	FindPendingItemsByReviewer(reviewerID uuid.UUID) ([]entities.AccessReviewItem, error)
+	// DecideItem menyimpan keputusan, komentar dan pemutus item. Mengembalikan false jika item sudah
+	// diputuskan lebih dulu.
+	DecideItem(item *entities.AccessReviewItem) (bool, error)
+	// MarkItemRevoked mencatat waktu pencabutan penetapan item.
+	MarkItemRevoked(id uuid.UUID, at time.Time) error
+}
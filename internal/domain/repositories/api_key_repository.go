@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// APIKeyRepository mendefinisikan kontrak persistensi API key.
+type APIKeyRepository interface {
+	// Create menyimpan API key baru.
+	Create(key *entities.APIKey) error
+	// FindByPrefix mencari API key berdasarkan prefix pencarian.
+	FindByPrefix(prefix string) (*entities.APIKey, error)
+	// FindByUser mengembalikan semua API key milik User, yang terbaru lebih dulu.
+	FindByUser(userID uuid.UUID) ([]entities.APIKey, error)
+	// Revoke mencabut API key milik User. Gagal dengan gorm.ErrRecordNotFound jika kunci tidak ada atau sudah dicabut.
+	Revoke(userID, id uuid.UUID, now time.Time) error
+	// Touch mencatat waktu dan IP pemakaian terakhir.
+	Touch(id uuid.UUID, usedAt time.Time, ip string) error
+}
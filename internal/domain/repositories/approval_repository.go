@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// ApprovalPolicyRepository mendefinisikan kontrak persistensi ApprovalPolicy.
+type ApprovalPolicyRepository interface {
+	// Create menambahkan ApprovalPolicy baru. Setiap Role hanya punya satu kebijakan.
+	Create(policy *entities.ApprovalPolicy) error
+	// FindByID mencari ApprovalPolicy berdasarkan ID beserta Role-nya.
+	FindByID(id uuid.UUID) (*entities.ApprovalPolicy, error)
+	// FindByRole mencari ApprovalPolicy untuk Role. roleID nil mencari kebijakan status superuser.
+	FindByRole(roleID *uuid.UUID) (*entities.ApprovalPolicy, error)
+	// FindAll mengembalikan semua ApprovalPolicy beserta Role-nya.
+	FindAll() ([]entities.ApprovalPolicy, error)
+	// Update menyimpan jumlah persetujuan dan Role peninjau ApprovalPolicy.
+	Update(policy *entities.ApprovalPolicy) error
+	// Delete menghapus ApprovalPolicy. Gagal dengan gorm.ErrRecordNotFound jika tidak ada.
+	Delete(id uuid.UUID) error
+}
+
+// AssignmentRequestRepository mendefinisikan kontrak persistensi AssignmentRequest dan keputusannya.
+type AssignmentRequestRepository interface {
+	// Create menyimpan permintaan baru.
+	Create(request *entities.AssignmentRequest) error
+	// FindByID mencari permintaan berdasarkan ID beserta User, Role dan semua keputusannya.
+	FindByID(id uuid.UUID) (*entities.AssignmentRequest, error)
+	// FindByStatus mengembalikan permintaan dengan status tertentu beserta User, Role dan keputusannya,
+	// terlama lebih dulu. status kosong mengembalikan semua permintaan.
+	FindByStatus(status string) ([]entities.AssignmentRequest, error)
+	// FindPendingFor mencari permintaan yang masih menunggu untuk penetapan yang sama.
+	// roleID nil mencari permintaan status superuser.
+	FindPendingFor(userID uuid.UUID, roleID *uuid.UUID) (*entities.AssignmentRequest, error)
+	// AddDecision menyimpan keputusan peninjau. Gagal dengan gorm.ErrDuplicatedKey jika peninjau sudah
+	// memutuskan permintaan ini.
+	AddDecision(decision *entities.AssignmentDecision) error
+	// UpdateStatus menyimpan status akhir permintaan. Gagal dengan gorm.ErrRecordNotFound jika permintaan
+	// sudah tidak menunggu, sehingga satu permintaan tidak bisa diterapkan dua kali.
+	UpdateStatus(request *entities.AssignmentRequest) error
+	// Reopen mengembalikan permintaan yang sudah disetujui ke status menunggu, dipakai jika penetapannya
+	// gagal diterapkan. Gagal dengan gorm.ErrRecordNotFound jika permintaan tidak berstatus disetujui.
+	Reopen(request *entities.AssignmentRequest) error
+}
@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// AuditLogFilter membatasi catatan audit yang dikembalikan. Field kosong tidak membatasi.
+type AuditLogFilter struct {
+	ActorID    *uuid.UUID
+	Action     string
+	TargetType string
+	TargetID   string
+	Since      *time.Time
+	Until      *time.Time
+	Limit      int
+}
+
+// AuditLogRepository mendefinisikan kontrak persistensi AuditLog. Catatan hanya bisa ditambah.
+type AuditLogRepository interface {
+	// Create menambahkan catatan audit.
+	Create(entry *entities.AuditLog) error
+	// Find mengembalikan catatan audit yang sesuai filter, terbaru lebih dulu.
+	Find(filter AuditLogFilter) ([]entities.AuditLog, error)
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// ErrChallengeNotFound dikembalikan jika data tantangan tidak ada, sudah dipakai, atau kedaluwarsa.
+var ErrChallengeNotFound = errors.New("tantangan tidak ditemukan atau kedaluwarsa")
+
+// ChallengeStore menyimpan data sementara milik ceremony multi-langkah (misalnya WebAuthn)
+// di antara dua permintaan HTTP.
+type ChallengeStore interface {
+	// Save menyimpan value di bawah key selama ttl, menimpa value sebelumnya.
+	Save(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// SaveNew menyimpan value hanya jika key belum ada, dan mengembalikan false jika key sudah dipakai.
+	// Dipakai untuk menolak pesan yang dikirim ulang, misalnya assertion SAML.
+	SaveNew(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error)
+	// Take mengambil lalu menghapus value secara atomik sehingga satu tantangan hanya bisa dipakai sekali.
+	Take(ctx context.Context, key string) ([]byte, error)
+}
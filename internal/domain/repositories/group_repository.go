@@ -0,0 +1,41 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// GroupRepository mendefinisikan kontrak persistensi Group, anggotanya, Subgroup dan Role yang diberikan ke Group.
+type GroupRepository interface {
+	// Create menambahkan Group baru. Nama Group bersifat unik.
+	Create(group *entities.Group) error
+	// FindByID mencari Group berdasarkan ID beserta anggota, Subgroup dan Role-nya.
+	FindByID(id uuid.UUID) (*entities.Group, error)
+	// FindByName mencari Group berdasarkan nama.
+	FindByName(name string) (*entities.Group, error)
+	// FindAll mengembalikan semua Group beserta Role-nya, diurutkan berdasarkan nama.
+	FindAll() ([]entities.Group, error)
+	// FindByMember mengembalikan Group tempat User menjadi anggota langsung beserta Role-nya.
+	FindByMember(userID uuid.UUID) ([]entities.Group, error)
+	// FindParents mengembalikan Group yang memuat Group id sebagai Subgroup langsung beserta Role-nya.
+	FindParents(id uuid.UUID) ([]entities.Group, error)
+	// FindAncestorIDs mengembalikan ID semua Group yang memuat Group id secara langsung maupun bertingkat.
+	FindAncestorIDs(id uuid.UUID) ([]uuid.UUID, error)
+	// FindMemberIDs mengembalikan ID User yang menjadi anggota Group id secara langsung maupun lewat Subgroup bertingkat.
+	FindMemberIDs(id uuid.UUID) ([]uuid.UUID, error)
+	// Update memperbarui nama dan deskripsi Group.
+	Update(group *entities.Group) error
+	// Delete menghapus Group beserta keanggotaan, relasi Subgroup dan Role-nya. Gagal dengan gorm.ErrRecordNotFound jika tidak ada.
+	Delete(id uuid.UUID) error
+	// AddMember menambahkan User ke Group. Tidak berbuat apa pun jika User sudah anggota.
+	AddMember(groupID, userID uuid.UUID) error
+	// RemoveMember mengeluarkan User dari Group. Gagal dengan gorm.ErrRecordNotFound jika User bukan anggota.
+	RemoveMember(groupID, userID uuid.UUID) error
+	// AddSubgroup menjadikan Group subgroupID bagian dari Group groupID. Pemeriksaan siklus dilakukan pemanggil.
+	AddSubgroup(groupID, subgroupID uuid.UUID) error
+	// RemoveSubgroup melepas Subgroup dari Group. Gagal dengan gorm.ErrRecordNotFound jika bukan Subgroup-nya.
+	RemoveSubgroup(groupID, subgroupID uuid.UUID) error
+	// ReplaceRoles mengganti seluruh Role yang diberikan ke Group.
+	ReplaceRoles(groupID uuid.UUID, roleIDs []uuid.UUID) error
+}
@@ -0,0 +1,31 @@
+package repositories
+
+import (
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// InvitationRepository mendefinisikan kontrak persistensi undangan organisasi.
+type InvitationRepository interface {
+	// Create menyimpan undangan baru.
+	Create(invitation *entities.Invitation) error
+	// FindByID mencari undangan di organisasi berdasarkan ID.
+	FindByID(organizationID, id uuid.UUID) (*entities.Invitation, error)
+	// FindByHash mencari undangan berdasarkan hash token tautannya, beserta Organization-nya.
+	FindByHash(hash string) (*entities.Invitation, error)
+	// FindPending mengembalikan undangan organisasi yang masih menunggu pada waktu now, terbaru lebih dulu.
+	FindPending(organizationID uuid.UUID, now time.Time) ([]entities.Invitation, error)
+	// FindPendingByEmail mencari undangan yang masih menunggu untuk email di organisasi pada waktu now.
+	FindPendingByEmail(organizationID uuid.UUID, email string, now time.Time) (*entities.Invitation, error)
+	// UpdateToken mengganti token, masa berlaku dan waktu kirim undangan yang dikirim ulang.
+	UpdateToken(invitation *entities.Invitation) error
+	// MarkAccepted menandai undangan diterima oleh userID. Gagal dengan gorm.ErrRecordNotFound jika
+	// undangan sudah diterima atau dicabut, sehingga satu tautan tidak bisa dipakai dua kali.
+	MarkAccepted(id, userID uuid.UUID, now time.Time) error
+	// Revoke mencabut undangan yang belum diterima. Gagal dengan gorm.ErrRecordNotFound jika tidak ada,
+	// sudah diterima atau sudah dicabut.
+	Revoke(organizationID, id uuid.UUID, now time.Time) error
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// LinkedIdentityRepository mendefinisikan kontrak persistensi identitas eksternal yang terhubung ke User.
+type LinkedIdentityRepository interface {
+	// Create menyimpan identitas baru. Pasangan provider dan subject bersifat unik.
+	Create(identity *entities.LinkedIdentity) error
+	// FindBySubject mencari identitas berdasarkan provider dan subject.
+	FindBySubject(provider, subject string) (*entities.LinkedIdentity, error)
+	// FindByUser mengembalikan semua identitas milik User, yang terlama lebih dulu.
+	FindByUser(userID uuid.UUID) ([]entities.LinkedIdentity, error)
+	// FindByProvider mengembalikan semua identitas dari satu provider.
+	FindByProvider(provider string) ([]entities.LinkedIdentity, error)
+	// Touch mencatat waktu login terakhir dan email terbaru dari provider.
+	Touch(id uuid.UUID, email string, loginAt time.Time) error
+	// Delete menghapus identitas milik User. Gagal dengan gorm.ErrRecordNotFound jika tidak ada.
+	Delete(userID, id uuid.UUID) error
+}
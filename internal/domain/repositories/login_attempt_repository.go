@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+)
+
+// LoginAttemptRepository mendefinisikan kontrak penghitung percobaan login gagal.
+// Key adalah subjek yang dilacak, misalnya "user:<id>" atau "ip:<alamat>".
+type LoginAttemptRepository interface {
+	// Get mengembalikan ringkasan percobaan gagal untuk key. Key yang belum ada menghasilkan Failures nol.
+	Get(ctx context.Context, key string) (*entities.LoginAttempts, error)
+	// RegisterFailure menambah satu kegagalan dan memperpanjang masa berlaku penghitung sebesar window.
+	RegisterFailure(ctx context.Context, key string, window time.Duration) (*entities.LoginAttempts, error)
+	// Reset menghapus penghitung untuk key.
+	Reset(ctx context.Context, key string) error
+}
@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// OAuthClientRepository mendefinisikan kontrak persistensi klien OAuth.
+type OAuthClientRepository interface {
+	// Create menyimpan klien baru.
+	Create(client *entities.OAuthClient) error
+	// FindByClientID mencari klien berdasarkan client_id publiknya.
+	FindByClientID(clientID string) (*entities.OAuthClient, error)
+	// FindAll mengembalikan semua klien terdaftar, yang terbaru lebih dulu.
+	FindAll() ([]entities.OAuthClient, error)
+	// Delete menghapus klien berdasarkan ID. Gagal dengan gorm.ErrRecordNotFound jika klien tidak ada.
+	Delete(id uuid.UUID) (*entities.OAuthClient, error)
+}
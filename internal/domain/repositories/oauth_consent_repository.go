@@ -0,0 +1,17 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// OAuthConsentRepository mendefinisikan kontrak persistensi persetujuan pengguna untuk klien OAuth.
+type OAuthConsentRepository interface {
+	// Find mencari persetujuan pengguna untuk klien.
+	Find(userID uuid.UUID, clientID string) (*entities.OAuthConsent, error)
+	// Save membuat atau memperbarui persetujuan pengguna untuk klien.
+	Save(consent *entities.OAuthConsent) error
+	// DeleteByClient menghapus semua persetujuan untuk klien.
+	DeleteByClient(clientID string) error
+}
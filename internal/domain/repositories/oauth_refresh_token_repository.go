@@ -0,0 +1,26 @@
+package repositories
+
+import (
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// OAuthRefreshTokenRepository mendefinisikan kontrak persistensi refresh token OAuth.
+type OAuthRefreshTokenRepository interface {
+	// Create menyimpan refresh token baru.
+	Create(token *entities.OAuthRefreshToken) error
+	// FindByHash mencari refresh token berdasarkan hash-nya.
+	FindByHash(hash string) (*entities.OAuthRefreshToken, error)
+	// MarkUsed menandai token sudah dirotasi. Gagal dengan gorm.ErrRecordNotFound jika token
+	// sudah dipakai atau dicabut, sehingga dua permintaan bersamaan tidak bisa sama-sama berhasil.
+	MarkUsed(id uuid.UUID, now time.Time) error
+	// RevokeFamily mencabut semua token aktif dalam satu keluarga rotasi.
+	RevokeFamily(familyID uuid.UUID, now time.Time) error
+	// RevokeBySession mencabut semua token aktif yang terikat ke sesi.
+	RevokeBySession(sessionID uuid.UUID, now time.Time) error
+	// RevokeByClient mencabut semua token aktif milik klien.
+	RevokeByClient(clientID string, now time.Time) error
+}
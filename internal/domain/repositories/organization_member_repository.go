@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationMemberRepository mendefinisikan kontrak persistensi keanggotaan organisasi dan Role per organisasi.
+type OrganizationMemberRepository interface {
+	// Create menambahkan User ke Organization beserta Role-nya. Pasangan organisasi dan User bersifat unik.
+	Create(member *entities.OrganizationMember) error
+	// Find mencari keanggotaan User di Organization beserta Role-nya.
+	Find(organizationID, userID uuid.UUID) (*entities.OrganizationMember, error)
+	// FindByOrganization mengembalikan semua anggota Organization beserta User dan Role-nya.
+	FindByOrganization(organizationID uuid.UUID) ([]entities.OrganizationMember, error)
+	// ReplaceRoles mengganti semua Role anggota dengan roleIDs.
+	ReplaceRoles(memberID uuid.UUID, roleIDs []uuid.UUID) error
+	// Delete mengeluarkan User dari Organization. Gagal dengan gorm.ErrRecordNotFound jika bukan anggota.
+	Delete(organizationID, userID uuid.UUID) error
+}
@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// OrganizationRepository mendefinisikan kontrak persistensi Organization.
+type OrganizationRepository interface {
+	// Create menambahkan Organization baru. Slug bersifat unik.
+	Create(organization *entities.Organization) error
+	// FindByID mencari Organization berdasarkan ID.
+	FindByID(id uuid.UUID) (*entities.Organization, error)
+	// FindBySlug mencari Organization berdasarkan slug.
+	FindBySlug(slug string) (*entities.Organization, error)
+	// FindAll mengembalikan semua Organization, diurutkan berdasarkan nama.
+	FindAll() ([]entities.Organization, error)
+	// FindByUser mengembalikan Organization tempat User menjadi anggota, diurutkan berdasarkan nama.
+	FindByUser(userID uuid.UUID) ([]entities.Organization, error)
+	// Update memperbarui nama Organization.
+	Update(organization *entities.Organization) error
+	// Delete menghapus Organization beserta seluruh keanggotaannya. Gagal dengan gorm.ErrRecordNotFound jika tidak ada.
+	Delete(id uuid.UUID) error
+}
@@ -0,0 +1,22 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// PermissionRepository mendefinisikan kontrak untuk membaca Permission dan permission yang dimiliki User.
+type PermissionRepository interface {
+	// FindNamesByUser mengembalikan nama semua Permission yang diberikan ke User melalui Role-nya,
+	// baik Role langsung maupun Role dari Group tempat User menjadi anggota (termasuk Group induknya),
+	// ditambah permission dari Role yang diwarisi Role tersebut.
+	FindNamesByUser(userID uuid.UUID) ([]string, error)
+	// FindNamesByMember mengembalikan nama semua Permission yang diberikan ke User melalui Role-nya di Organization,
+	// termasuk permission dari Role yang diwarisi.
+	FindNamesByMember(organizationID, userID uuid.UUID) ([]string, error)
+	// EnsureNames mengembalikan Permission untuk setiap nama di names, membuat yang belum terdaftar.
+	EnsureNames(names []string) ([]entities.Permission, error)
+	// FindExistingNames mengembalikan nama-nama dari names yang terdaftar sebagai Permission.
+	FindExistingNames(names []string) ([]string, error)
+}
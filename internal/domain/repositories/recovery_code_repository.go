@@ -0,0 +1,19 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// RecoveryCodeRepository mendefinisikan kontrak persistensi kode pemulihan MFA.
+type RecoveryCodeRepository interface {
+	// ReplaceForUser menghapus semua kode lama milik User dan menyimpan kode baru.
+	ReplaceForUser(userID uuid.UUID, codes []entities.RecoveryCode) error
+	// FindUnused mencari kode yang belum dipakai berdasarkan User dan hash kode.
+	FindUnused(userID uuid.UUID, codeHash string) (*entities.RecoveryCode, error)
+	// MarkUsed menandai kode sebagai sudah dipakai. Gagal jika kode sudah dipakai sebelumnya.
+	MarkUsed(id uuid.UUID) error
+	// DeleteByUser menghapus semua kode milik User.
+	DeleteByUser(userID uuid.UUID) error
+}
@@ -0,0 +1,79 @@
+package repositories
+
+import (
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// RoleAssignment adalah Role yang dipegang User secara langsung, lewat Group atau sebagai anggota
+// Organization, tanpa pewarisan Role. Pasangan User dan Role yang sama bisa muncul sekali untuk setiap sumbernya.
+type RoleAssignment struct {
+	UserID         uuid.UUID
+	RoleID         uuid.UUID
+	GroupID        *uuid.UUID // Group tempat User menjadi anggota langsung yang memberinya Role; kosong untuk penetapan langsung
+	Nested         bool       // true jika Role diberikan Group induk dari GroupID, bukan GroupID sendiri
+	OrganizationID *uuid.UUID // Organization tempat Role anggota berlaku; kosong untuk Role global
+}
+
+// RoleRepository mendefinisikan kontrak persistensi Role dan penetapannya ke User.
+type RoleRepository interface {
+	// Create menambahkan Role baru. Nama Role bersifat unik.
+	Create(role *entities.Role) error
+	// FindByID mencari Role berdasarkan ID beserta anggotanya.
+	FindByID(id uuid.UUID) (*entities.Role, error)
+	// FindByName mencari Role berdasarkan nama.
+	FindByName(name string) (*entities.Role, error)
+	// FindAll mengembalikan semua Role beserta anggotanya, diurutkan berdasarkan nama.
+	FindAll() ([]entities.Role, error)
+	// FindByUser mengembalikan Role yang diberikan langsung ke User dan sedang berlaku.
+	FindByUser(userID uuid.UUID) ([]entities.Role, error)
+	// FindGraph mengembalikan semua Role beserta Permission langsung dan Parents-nya untuk menghitung pewarisan.
+	FindGraph() ([]entities.Role, error)
+	// FindByNames mengembalikan Role yang namanya ada di names. Nama yang tidak terdaftar diabaikan.
+	FindByNames(names []string) ([]entities.Role, error)
+	// Update memperbarui nama dan deskripsi Role tanpa menyentuh anggota dan permission-nya.
+	Update(role *entities.Role) error
+	// UpdateOwner mengganti pemilik Role. ownerID nil menghapus pemiliknya.
+	UpdateOwner(roleID uuid.UUID, ownerID *uuid.UUID) error
+	// UpdateSCIMManaged mengatur apakah Role boleh dikelola klien SCIM. Gagal dengan gorm.ErrRecordNotFound jika tidak ada.
+	UpdateSCIMManaged(roleID uuid.UUID, managed bool) error
+	// UpdateTenantRole mengatur apakah Role boleh diberikan ke anggota organisasi. Gagal dengan
+	// gorm.ErrRecordNotFound jika tidak ada.
+	UpdateTenantRole(roleID uuid.UUID, tenant bool) error
+	// Delete menghapus Role beserta semua penetapan dan relasi pewarisannya. Gagal dengan gorm.ErrRecordNotFound jika tidak ada.
+	Delete(id uuid.UUID) error
+	// ReplacePermissions mengganti seluruh Permission langsung Role.
+	ReplacePermissions(roleID uuid.UUID, permissionIDs []uuid.UUID) error
+	// ReplaceParents mengganti seluruh Role yang diwarisi Role. Pemeriksaan siklus dilakukan pemanggil.
+	ReplaceParents(roleID uuid.UUID, parentIDs []uuid.UUID) error
+	// UpdateUserRoles menambahkan Role grant dan mencabut Role revoke dari User dalam satu transaksi.
+	UpdateUserRoles(userID uuid.UUID, grant, revoke []uuid.UUID) error
+	// FindGrants mengembalikan semua penetapan Role langsung ke User beserta Role-nya, termasuk yang belum
+	// mulai berlaku.
+	FindGrants(userID uuid.UUID) ([]entities.UserRole, error)
+	// FindGrant mencari penetapan satu Role ke User beserta Role-nya.
+	FindGrant(userID, roleID uuid.UUID) (*entities.UserRole, error)
+	// Grant menetapkan Role ke User, atau mengganti jendela waktu, alasan dan pemberi penetapan yang sudah ada.
+	Grant(grant *entities.UserRole) error
+	// Revoke mencabut Role dari User. Gagal dengan gorm.ErrRecordNotFound jika Role tidak ditetapkan.
+	Revoke(userID, roleID uuid.UUID) error
+	// FindExpiredGrants mengembalikan penetapan yang ExpiresAt-nya sudah lewat pada waktu now, beserta Role-nya.
+	FindExpiredGrants(now time.Time) ([]entities.UserRole, error)
+	// DeleteExpiredGrant menghapus penetapan jika masih kedaluwarsa pada waktu now. Mengembalikan false jika
+	// penetapan sudah dicabut atau diperpanjang sejak dibaca.
+	DeleteExpiredGrant(userID, roleID uuid.UUID, now time.Time) (bool, error)
+	// FindAssignments mengembalikan RoleAssignment User: Role langsung (termasuk yang belum mulai berlaku,
+	// tanpa yang sudah kedaluwarsa), Role lewat Group bertingkat dan Role anggota di setiap Organization,
+	// tanpa Role yang diwarisi.
+	FindAssignments(userID uuid.UUID) ([]RoleAssignment, error)
+	// FindGrantsByRoles mengembalikan penetapan langsung yang belum kedaluwarsa untuk Role roleIDs beserta
+	// Role-nya, atau untuk semua Role jika roleIDs kosong.
+	FindGrantsByRoles(roleIDs []uuid.UUID) ([]entities.UserRole, error)
+	// FindAllAssignments mengembalikan RoleAssignment semua User dengan aturan yang sama seperti FindAssignments.
+	FindAllAssignments() ([]RoleAssignment, error)
+	// UpdateMembers menambahkan User add ke Role dan mengeluarkan User remove dalam satu transaksi.
+	UpdateMembers(roleID uuid.UUID, add, remove []uuid.UUID) error
+}
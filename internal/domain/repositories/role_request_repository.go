@@ -0,0 +1,28 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// RoleRequestRepository mendefinisikan kontrak persistensi permintaan Role sementara.
+type RoleRequestRepository interface {
+	// Create menyimpan permintaan baru.
+	Create(request *entities.RoleRequest) error
+	// FindByID mencari permintaan berdasarkan ID beserta User dan Role-nya.
+	FindByID(id uuid.UUID) (*entities.RoleRequest, error)
+	// FindByUser mengembalikan semua permintaan milik User beserta Role-nya, terbaru lebih dulu.
+	FindByUser(userID uuid.UUID) ([]entities.RoleRequest, error)
+	// FindPending mengembalikan semua permintaan yang masih menunggu beserta User dan Role-nya, terlama lebih dulu.
+	FindPending() ([]entities.RoleRequest, error)
+	// FindPendingByUserAndRole mencari permintaan User yang masih menunggu untuk Role.
+	FindPendingByUserAndRole(userID, roleID uuid.UUID) (*entities.RoleRequest, error)
+	// UpdateDecision menyimpan status, peninjau dan akhir penetapan permintaan. Gagal dengan
+	// gorm.ErrRecordNotFound jika permintaan sudah tidak menunggu, sehingga satu permintaan tidak bisa
+	// diputuskan dua kali.
+	UpdateDecision(request *entities.RoleRequest) error
+	// Reopen mengembalikan permintaan yang sudah disetujui ke status menunggu, dipakai jika Role-nya gagal
+	// ditetapkan. Gagal dengan gorm.ErrRecordNotFound jika permintaan tidak berstatus disetujui.
+	Reopen(request *entities.RoleRequest) error
+}
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// SAMLConnectionRepository mendefinisikan kontrak persistensi koneksi SAML.
+type SAMLConnectionRepository interface {
+	// Create menyimpan koneksi baru.
+	Create(connection *entities.SAMLConnection) error
+	// FindByID mencari koneksi berdasarkan ID.
+	FindByID(id uuid.UUID) (*entities.SAMLConnection, error)
+	// FindByName mencari koneksi berdasarkan nama yang dipakai di URL.
+	FindByName(name string) (*entities.SAMLConnection, error)
+	// FindAll mengembalikan semua koneksi, urut berdasarkan nama.
+	FindAll() ([]entities.SAMLConnection, error)
+	// Update menyimpan perubahan koneksi.
+	Update(connection *entities.SAMLConnection) error
+	// Delete menghapus koneksi berdasarkan ID. Gagal dengan gorm.ErrRecordNotFound jika koneksi tidak ada.
+	Delete(id uuid.UUID) error
+}
@@ -0,0 +1,27 @@
+package repositories
+
+import (
+	"context"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// SessionRepository mendefinisikan kontrak persistensi sesi login.
+// Implementasi boleh menyimpan salinan cepat (cache) selama sumber kebenaran tetap durable.
+type SessionRepository interface {
+	// Create menyimpan sesi baru.
+	Create(ctx context.Context, session *entities.Session) error
+	// FindByID mencari sesi berdasarkan ID, termasuk sesi yang sudah dicabut.
+	FindByID(ctx context.Context, id uuid.UUID) (*entities.Session, error)
+	// FindActiveByUser mengembalikan sesi milik User yang belum dicabut dan belum kedaluwarsa, yang terbaru lebih dulu.
+	FindActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]entities.Session, error)
+	// Touch memperbarui waktu terakhir sesi terlihat. Gagal dengan gorm.ErrRecordNotFound jika sesi sudah dicabut.
+	Touch(ctx context.Context, session *entities.Session, seenAt time.Time) error
+	// Revoke mencabut satu sesi. Gagal dengan gorm.ErrRecordNotFound jika sesi tidak aktif.
+	Revoke(ctx context.Context, id uuid.UUID, now time.Time) error
+	// RevokeAllByUser mencabut semua sesi aktif milik User dan mengembalikan jumlahnya.
+	RevokeAllByUser(ctx context.Context, userID uuid.UUID, now time.Time) (int64, error)
+}
@@ -0,0 +1,21 @@
+package repositories
+
+import (
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// SigningKeyRepository mendefinisikan kontrak persistensi kunci penandatanganan token.
+type SigningKeyRepository interface {
+	// Create menyimpan kunci baru.
+	Create(key *entities.SigningKey) error
+	// FindValid mengembalikan kunci yang belum kedaluwarsa pada waktu now, SigningFrom terbaru lebih dulu.
+	FindValid(now time.Time) ([]entities.SigningKey, error)
+	// Retire menetapkan waktu kedaluwarsa kunci yang sudah digantikan.
+	Retire(id uuid.UUID, expiresAt time.Time) error
+	// DeleteExpired menghapus kunci yang sudah kedaluwarsa pada waktu now.
+	DeleteExpired(now time.Time) (int64, error)
+}
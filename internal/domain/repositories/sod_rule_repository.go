@@ -0,0 +1,25 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// SoDRuleRepository mendefinisikan kontrak persistensi SoDRule beserta Role-nya.
+type SoDRuleRepository interface {
+	// Create menambahkan SoDRule baru tanpa Role. Nama aturan bersifat unik.
+	Create(rule *entities.SoDRule) error
+	// FindByID mencari SoDRule berdasarkan ID beserta Role-nya.
+	FindByID(id uuid.UUID) (*entities.SoDRule, error)
+	// FindByName mencari SoDRule berdasarkan nama.
+	FindByName(name string) (*entities.SoDRule, error)
+	// FindAll mengembalikan semua SoDRule beserta Role-nya, diurutkan berdasarkan nama.
+	FindAll() ([]entities.SoDRule, error)
+	// Update memperbarui nama dan deskripsi SoDRule.
+	Update(rule *entities.SoDRule) error
+	// ReplaceRoles mengganti seluruh Role yang tidak boleh dipegang bersamaan menurut aturan.
+	ReplaceRoles(ruleID uuid.UUID, roleIDs []uuid.UUID) error
+	// Delete menghapus SoDRule beserta relasi Role-nya. Gagal dengan gorm.ErrRecordNotFound jika tidak ada.
+	Delete(id uuid.UUID) error
+}
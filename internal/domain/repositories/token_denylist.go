@@ -0,0 +1,15 @@
+package repositories
+
+import (
+	"context"
+	"time"
+)
+
+// TokenDenylist menyimpan ID (jti) token akses yang dicabut sebelum kedaluwarsa.
+// Dipakai untuk token yang tidak terikat sesi, misalnya token client_credentials.
+type TokenDenylist interface {
+	// Add mencatat token sebagai dicabut sampai waktu until, yaitu saat token kedaluwarsa dengan sendirinya.
+	Add(ctx context.Context, tokenID string, until time.Time) error
+	// Contains mengembalikan true jika token sudah dicabut.
+	Contains(ctx context.Context, tokenID string) (bool, error)
+}
@@ -1,6 +1,10 @@
 package repositories
 
-import "fiber-usermanagement/internal/domain/entities"
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
 
 // UserRepository mendefinisikan kontrak (interface) untuk operasi persistensi data User.
 // Interface ini menjelaskan *apa* yang bisa dilakukan terhadap data User, tanpa
@@ -10,11 +14,24 @@ type UserRepository interface {
 	// Create menambahkan User baru ke penyimpanan. Mengembalikan User yang dibuat atau error.
 	Create(user *entities.User) (*entities.User, error)
 	// FindByID mencari User berdasarkan ID. Mengembalikan User jika ditemukan atau error jika tidak.
-	FindByID(id uint) (*entities.User, error)
+	FindByID(id uuid.UUID) (*entities.User, error)
+	// FindByUsernameOrEmail mencari User yang username atau email-nya sama dengan identifier.
+	FindByUsernameOrEmail(identifier string) (*entities.User, error)
+	// FindByEmail mencari User berdasarkan email tanpa membedakan huruf besar dan kecil.
+	FindByEmail(email string) (*entities.User, error)
 	// FindAll mengembalikan semua User yang ada di penyimpanan. Mengembalikan slice User atau error.
 	FindAll() ([]entities.User, error)
+	// FindAllWithRoles mengembalikan semua User beserta Role-nya, diurutkan berdasarkan username.
+	FindAllWithRoles() ([]entities.User, error)
 	// Update memperbarui data User yang sudah ada. Mengembalikan User yang diperbarui atau error.
 	Update(user *entities.User) (*entities.User, error)
 	// Delete menghapus User berdasarkan ID. Mengembalikan error jika gagal.
-	Delete(id uint) error
+	Delete(id uuid.UUID) error
+	// FindOrganizationIDs mengembalikan ID semua Organization tempat User menjadi anggota, termasuk di
+	// luar tenant repositori ini.
+	FindOrganizationIDs(userID uuid.UUID) ([]uuid.UUID, error)
+	// InOrganization mengembalikan UserRepository yang setiap query-nya dibatasi ke anggota Organization,
+	// sehingga User di organisasi lain tidak pernah terbaca. Create sekaligus menjadikan User anggota,
+	// dan Delete hanya mengeluarkan User dari organisasi tersebut karena akunnya bisa dipakai organisasi lain.
+	InOrganization(organizationID uuid.UUID) UserRepository
 }
@@ -0,0 +1,23 @@
+package repositories
+
+import (
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// WebAuthnCredentialRepository mendefinisikan kontrak persistensi passkey (kredensial WebAuthn).
+type WebAuthnCredentialRepository interface {
+	// Create menyimpan kredensial baru.
+	Create(credential *entities.WebAuthnCredential) error
+	// FindByUser mengembalikan semua kredensial milik User, yang terbaru lebih dulu.
+	FindByUser(userID uuid.UUID) ([]entities.WebAuthnCredential, error)
+	// FindByID mencari kredensial milik User berdasarkan ID.
+	FindByID(userID, id uuid.UUID) (*entities.WebAuthnCredential, error)
+	// CountByUser menghitung jumlah kredensial milik User.
+	CountByUser(userID uuid.UUID) (int64, error)
+	// Update memperbarui kredensial yang sudah ada.
+	Update(credential *entities.WebAuthnCredential) error
+	// Delete menghapus kredensial milik User berdasarkan ID.
+	Delete(userID, id uuid.UUID) error
+}
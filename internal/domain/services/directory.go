@@ -0,0 +1,25 @@
+package services
+
+import "errors"
+
+// ErrDirectoryInvalidCredentials dikembalikan jika direktori menolak bind dengan kredensial pengguna.
+var ErrDirectoryInvalidCredentials = errors.New("kredensial direktori tidak valid")
+
+// DirectoryUser adalah entri pengguna di direktori eksternal (LDAP/Active Directory).
+type DirectoryUser struct {
+	DN        string
+	Username  string // Atribut login yang stabil, misalnya sAMAccountName atau uid
+	Email     string
+	FirstName string
+	LastName  string
+	Groups    []string // DN grup tempat pengguna menjadi anggota
+}
+
+// Directory mendefinisikan kontrak direktori pengguna eksternal.
+type Directory interface {
+	// Authenticate mencari pengguna berdasarkan username lalu melakukan bind dengan password-nya.
+	// Gagal dengan ErrDirectoryInvalidCredentials jika pengguna tidak ada atau password salah.
+	Authenticate(username, password string) (*DirectoryUser, error)
+	// ListUsers mengembalikan semua pengguna yang masih aktif di direktori.
+	ListUsers() ([]DirectoryUser, error)
+}
@@ -0,0 +1,16 @@
+package services
+
+import "time"
+
+// Event adalah kejadian domain yang diberitahukan ke sistem lain, misalnya penetapan Role yang kedaluwarsa.
+type Event struct {
+	Type       string         `json:"type"` // Misalnya "role_grant.expired"
+	OccurredAt time.Time      `json:"occurred_at"`
+	Data       map[string]any `json:"data"`
+}
+
+// EventPublisher mendefinisikan kontrak penerbitan Event.
+type EventPublisher interface {
+	// Publish menerbitkan event. Kegagalan tidak membatalkan perubahan yang sudah tersimpan.
+	Publish(event Event) error
+}
@@ -0,0 +1,27 @@
+package services
+
+import "context"
+
+// ExternalIdentity adalah profil pengguna yang sudah diverifikasi oleh identity provider eksternal.
+type ExternalIdentity struct {
+	Provider          string
+	Subject           string
+	Email             string
+	EmailVerified     bool
+	PreferredUsername string
+	FirstName         string
+	LastName          string
+}
+
+// IdentityProvider mendefinisikan kontrak identity provider OpenID Connect eksternal untuk login sosial.
+type IdentityProvider interface {
+	// Name mengembalikan nama provider di konfigurasi, dipakai di URL dan tabel identitas.
+	Name() string
+	// DisplayName mengembalikan nama provider untuk ditampilkan di tombol login.
+	DisplayName() string
+	// AuthCodeURL mengembalikan alamat login di provider. codeVerifier dipakai untuk PKCE S256.
+	AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error)
+	// Exchange menukar authorization code dengan ID token, lalu memverifikasi tanda tangan,
+	// issuer, audience dan nonce-nya sebelum mengembalikan profil pengguna.
+	Exchange(ctx context.Context, code, codeVerifier, nonce string) (*ExternalIdentity, error)
+}
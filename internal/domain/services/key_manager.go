@@ -0,0 +1,37 @@
+package services
+
+import "time"
+
+// JSONWebKey adalah kunci publik dalam format JWK (RFC 7517) untuk dipublikasikan di JWKS.
+type JSONWebKey struct {
+	KeyType   string `json:"kty"`
+	KeyID     string `json:"kid"`
+	Use       string `json:"use"`
+	Algorithm string `json:"alg"`
+	N         string `json:"n,omitempty"`   // Modulus RSA
+	E         string `json:"e,omitempty"`   // Eksponen RSA
+	Curve     string `json:"crv,omitempty"` // Kurva OKP, misalnya Ed25519
+	X         string `json:"x,omitempty"`   // Kunci publik OKP
+}
+
+// Signature adalah tanda tangan terpisah (detached) atas sebuah dokumen, misalnya laporan ekspor.
+// Tanda tangan bisa diverifikasi dengan kunci publik KeyID dari JWKS.
+type Signature struct {
+	KeyID     string // kid kunci penanda tangan
+	Algorithm string // Algoritma tanda tangan, misalnya RS256
+	Value     string // Tanda tangan dalam base64url tanpa padding
+}
+
+// KeyManager mendefinisikan kontrak pengelolaan kunci penandatanganan token asimetris.
+type KeyManager interface {
+	// Algorithm mengembalikan algoritma tanda tangan untuk kunci baru, misalnya RS256.
+	Algorithm() string
+	// PublicKeys mengembalikan semua kunci publik yang masih berlaku untuk verifikasi,
+	// termasuk kunci berikutnya yang sudah dipublikasikan tetapi belum dipakai menandatangani.
+	PublicKeys() ([]JSONWebKey, error)
+	// Rotate membuat kunci berikutnya jika kunci aktif mendekati akhir masa pakainya,
+	// menetapkan kedaluwarsa kunci yang digantikan dan menghapus kunci yang sudah kedaluwarsa.
+	Rotate(now time.Time) error
+	// Sign menandatangani payload dengan kunci yang sedang aktif.
+	Sign(payload []byte) (*Signature, error)
+}
@@ -0,0 +1,7 @@
+package services
+
+// Mailer mendefinisikan kontrak untuk mengirim email notifikasi ke pengguna.
+type Mailer interface {
+	// Send mengirim email teks biasa ke satu penerima.
+	Send(to, subject, body string) error
+}
@@ -0,0 +1,16 @@
+package services
+
+// TOTPEnrollment adalah data yang ditampilkan ke pengguna saat mendaftarkan aplikasi authenticator.
+type TOTPEnrollment struct {
+	Secret          string // Secret base32 untuk dimasukkan manual
+	ProvisioningURI string // URI otpauth:// untuk dijadikan QR code
+}
+
+// OTPService mendefinisikan kontrak untuk one-time password berbasis waktu (RFC 6238).
+type OTPService interface {
+	// GenerateTOTP membuat secret baru untuk akun dengan nama accountName.
+	GenerateTOTP(accountName string) (*TOTPEnrollment, error)
+	// ValidateTOTP memeriksa kode terhadap secret dengan toleransi satu periode dan mengembalikan
+	// nomor time step kode yang cocok agar pemanggil bisa menolak kode yang dipakai ulang.
+	ValidateTOTP(code, secret string) (step int64, ok bool)
+}
@@ -0,0 +1,9 @@
+package services
+
+// PasswordHasher mendefinisikan kontrak untuk hashing dan verifikasi password.
+type PasswordHasher interface {
+	// Hash mengembalikan hash dari password plaintext.
+	Hash(password string) (string, error)
+	// Compare mengembalikan true jika password cocok dengan hash.
+	Compare(hash, password string) bool
+}
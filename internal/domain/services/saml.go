@@ -0,0 +1,45 @@
+package services
+
+import (
+	"context"
+	"errors"
+)
+
+// ErrSAMLInvalidMetadata dikembalikan jika metadata IdP tidak bisa dibaca atau tidak memiliki
+// endpoint SSO HTTP-Redirect dan sertifikat penanda tangan.
+var ErrSAMLInvalidMetadata = errors.New("metadata IdP SAML tidak valid")
+
+// SAMLIdPMetadata adalah ringkasan metadata identity provider SAML yang sudah divalidasi.
+type SAMLIdPMetadata struct {
+	EntityID string
+	SSOURL   string
+}
+
+// SAMLAuthnRequest adalah permintaan autentikasi yang dikirim ke IdP lewat redirect browser.
+type SAMLAuthnRequest struct {
+	ID          string // Dicocokkan dengan InResponseTo pada assertion
+	RedirectURL string
+}
+
+// SAMLAssertion adalah isi assertion SAML yang tanda tangan, audience, recipient dan masa berlakunya sudah diverifikasi.
+type SAMLAssertion struct {
+	ID         string // Dipakai untuk menolak assertion yang dikirim ulang
+	NameID     string
+	Attributes map[string][]string // Dikunci dengan Name dan FriendlyName atribut
+}
+
+// SAMLServiceProvider mendefinisikan kontrak service provider SAML 2.0. Setiap koneksi memiliki
+// entity ID dan ACS sendiri yang diturunkan dari namanya.
+type SAMLServiceProvider interface {
+	// ParseIdPMetadata memvalidasi XML metadata IdP. Gagal dengan ErrSAMLInvalidMetadata.
+	ParseIdPMetadata(metadata []byte) (*SAMLIdPMetadata, error)
+	// FetchIdPMetadata mengunduh XML metadata IdP dari URL.
+	FetchIdPMetadata(ctx context.Context, metadataURL string) ([]byte, error)
+	// Metadata mengembalikan XML metadata service provider untuk koneksi.
+	Metadata(connection string) ([]byte, error)
+	// AuthnRequest membuat permintaan autentikasi SP-initiated ke IdP dengan binding HTTP-Redirect.
+	AuthnRequest(connection string, idpMetadata []byte, relayState string) (*SAMLAuthnRequest, error)
+	// ParseResponse memverifikasi SAMLResponse (base64) dari binding HTTP-POST. requestID kosong berarti
+	// login IdP-initiated sehingga InResponseTo tidak diperiksa.
+	ParseResponse(connection string, idpMetadata []byte, samlResponse, requestID string) (*SAMLAssertion, error)
+}
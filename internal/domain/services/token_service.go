@@ -0,0 +1,128 @@
+package services
+
+import (
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// TokenClaims adalah isi token akses yang sudah diverifikasi.
+// Struktur ini tidak bergantung pada format token (JWT atau lainnya).
+type TokenClaims struct {
+	ID          string    // ID unik token (jti)
+	UserID      uuid.UUID // Subjek token
+	SessionID   uuid.UUID // Sesi login tempat token diterbitkan; Nil untuk token client_credentials
+	Username    string
+	IsSuperuser bool
+	IssuedAt    time.Time
+	ExpiresAt   time.Time
+	APIKeyID    uuid.UUID // Terisi jika permintaan diautentikasi dengan API key, bukan token login
+	ClientID    string    // Terisi jika token diterbitkan untuk klien OAuth
+	Scopes      []string  // Nil untuk token login; untuk API key dan token OAuth berisi permission yang boleh dipakai
+
+	// OrganizationID terisi jika kredensial terikat ke satu organisasi; permintaan dengan kredensial ini
+	// selalu berjalan di organisasi tersebut
+	OrganizationID uuid.UUID
+
+	// ActorID terisi pada token impersonasi: superuser yang bertindak atas nama UserID. ActorUsername dan
+	// Banner ikut dibawa token agar aplikasi bisa menampilkan penanda impersonasi tanpa query tambahan.
+	ActorID       uuid.UUID
+	ActorUsername string
+	Banner        string
+}
+
+// IsImpersonation mengembalikan true jika token diterbitkan untuk sesi impersonasi.
+func (c *TokenClaims) IsImpersonation() bool {
+	return c.ActorID != uuid.Nil
+}
+
+// IsAPIKey mengembalikan true jika klaim berasal dari API key.
+func (c *TokenClaims) IsAPIKey() bool {
+	return c.APIKeyID != uuid.Nil
+}
+
+// IsDelegated mengembalikan true jika klaim berasal dari kredensial turunan (API key atau token OAuth),
+// bukan dari login interaktif pengguna.
+func (c *TokenClaims) IsDelegated() bool {
+	return c.IsAPIKey() || c.ClientID != ""
+}
+
+// IsClientToken mengembalikan true jika token diterbitkan lewat grant client_credentials,
+// yaitu atas nama klien OAuth itu sendiri, bukan atas nama pengguna.
+func (c *TokenClaims) IsClientToken() bool {
+	return c.ClientID != "" && c.SessionID == uuid.Nil
+}
+
+// HasScope mengembalikan true jika scope token mengizinkan permission tersebut. Scope boleh berupa
+// wildcard dan deny eksplisit (lihat entities.ParsePermission). Token login tidak dibatasi scope;
+// API key dengan entities.ScopeAll mengizinkan semuanya kecuali yang di-deny scope lainnya.
+func (c *TokenClaims) HasScope(permission string) bool {
+	if !c.IsDelegated() {
+		return true
+	}
+	scopes := entities.NewPermissionSet(c.Scopes)
+	for _, scope := range c.Scopes {
+		if scope == entities.ScopeAll {
+			return !scopes.Denies(permission)
+		}
+	}
+	return scopes.Allows(permission)
+}
+
+// IssuedToken adalah token akses yang baru diterbitkan beserta masa berlakunya.
+type IssuedToken struct {
+	Token     string
+	ExpiresAt time.Time
+}
+
+// DelegatedGrant adalah hak akses yang diberikan ke klien OAuth untuk diterbitkan sebagai token akses.
+type DelegatedGrant struct {
+	SessionID uuid.UUID     // Sesi tempat persetujuan pengguna dicatat; Nil untuk client_credentials
+	ClientID  string        // client_id klien penerima token
+	Scopes    []string      // Permission yang boleh dipakai token
+	TTL       time.Duration // Masa berlaku token akses
+}
+
+// ImpersonationGrant adalah data token akses untuk sesi impersonasi: token milik pengguna subjek yang
+// juga membawa identitas superuser pelakunya.
+type ImpersonationGrant struct {
+	SessionID uuid.UUID      // Sesi impersonasi tempat token terikat
+	Actor     *entities.User // Superuser yang melakukan impersonasi
+	Banner    string         // Teks penanda yang ditampilkan aplikasi selama impersonasi
+	TTL       time.Duration  // Batas waktu mutlak; token impersonasi tidak bisa diperpanjang
+}
+
+// IDTokenRequest adalah data ID token OpenID Connect untuk klien OAuth.
+type IDTokenRequest struct {
+	ClientID string                 // Audiens token
+	Nonce    string                 // Nonce dari permintaan otorisasi, dikembalikan apa adanya
+	Claims   map[string]interface{} // Klaim profil pengguna sesuai scope yang disetujui
+	TTL      time.Duration
+}
+
+// Tujuan token tantangan yang diterbitkan di tengah proses login.
+const (
+	ChallengeMFA           = "mfa"            // Pengguna harus memasukkan kode MFA
+	ChallengeMFAEnrollment = "mfa_enrollment" // Pengguna wajib mendaftarkan MFA sebelum login selesai
+)
+
+// TokenService mendefinisikan kontrak untuk menerbitkan dan memverifikasi token akses.
+type TokenService interface {
+	// GenerateAccessToken menerbitkan token akses untuk User yang terikat ke sebuah sesi login.
+	GenerateAccessToken(user *entities.User, sessionID uuid.UUID) (*IssuedToken, error)
+	// GenerateDelegatedToken menerbitkan token akses untuk klien OAuth yang dibatasi scope.
+	GenerateDelegatedToken(user *entities.User, grant DelegatedGrant) (*IssuedToken, error)
+	// GenerateImpersonationToken menerbitkan token akses untuk User yang sedang diimpersonasi.
+	GenerateImpersonationToken(user *entities.User, grant ImpersonationGrant) (*IssuedToken, error)
+	// GenerateIDToken menerbitkan ID token OpenID Connect untuk User.
+	GenerateIDToken(user *entities.User, req IDTokenRequest) (*IssuedToken, error)
+	// ParseAccessToken memverifikasi token dan mengembalikan klaimnya.
+	ParseAccessToken(token string) (*TokenClaims, error)
+	// GenerateChallengeToken menerbitkan token berumur pendek untuk langkah login berikutnya.
+	// Token ini tidak bisa dipakai sebagai token akses.
+	GenerateChallengeToken(user *entities.User, purpose string, ttl time.Duration) (*IssuedToken, error)
+	// ParseChallengeToken memverifikasi token tantangan untuk tujuan tertentu dan mengembalikan ID pengguna.
+	ParseChallengeToken(token, purpose string) (uuid.UUID, error)
+}
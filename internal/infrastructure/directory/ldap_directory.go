@@ -0,0 +1,156 @@
+package directory
+
+import (
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"net"
+	"time"
+
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/go-ldap/ldap/v3"
+)
+
+// ldapPageSize adalah jumlah entri per halaman saat sinkronisasi; Active Directory membatasi 1000 per respons.
+const ldapPageSize = 500
+
+// LDAPConfig adalah konfigurasi koneksi dan pemetaan atribut direktori LDAP/Active Directory.
+type LDAPConfig struct {
+	URL                string // ldap://host:389 atau ldaps://host:636
+	StartTLS           bool
+	InsecureSkipVerify bool
+	BindDN             string // Akun layanan untuk mencari pengguna
+	BindPassword       string
+	BaseDN             string
+	UserFilter         string // Filter pengguna aktif, misalnya (&(objectClass=user)(!(userAccountControl:1.2.840.113556.1.4.803:=2)))
+	UsernameAttribute  string // Misalnya sAMAccountName (AD) atau uid (OpenLDAP)
+	EmailAttribute     string
+	FirstNameAttribute string
+	LastNameAttribute  string
+	GroupAttribute     string // Misalnya memberOf
+	Timeout            time.Duration
+}
+
+// LDAPDirectory adalah implementasi services.Directory dengan LDAP. Setiap operasi membuka
+// koneksi baru agar tidak ada koneksi yang terputus diam-diam di antara login yang jarang.
+type LDAPDirectory struct {
+	config LDAPConfig
+}
+
+// NewLDAPDirectory membuat instance baru dari LDAPDirectory.
+func NewLDAPDirectory(config LDAPConfig) services.Directory {
+	return &LDAPDirectory{config: config}
+}
+
+// Authenticate mengimplementasikan metode Authenticate dari Directory.
+func (d *LDAPDirectory) Authenticate(username, password string) (*services.DirectoryUser, error) {
+	// Bind dengan password kosong adalah unauthenticated bind yang selalu berhasil di banyak server
+	if username == "" || password == "" {
+		return nil, services.ErrDirectoryInvalidCredentials
+	}
+
+	conn, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	filter := fmt.Sprintf("(&%s(%s=%s))", d.config.UserFilter, d.config.UsernameAttribute, ldap.EscapeFilter(username))
+	result, err := conn.Search(ldap.NewSearchRequest(
+		d.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 2, 0, false,
+		filter, d.attributes(), nil,
+	))
+	if err != nil {
+		return nil, fmt.Errorf("gagal mencari pengguna di direktori: %w", err)
+	}
+	if len(result.Entries) != 1 {
+		return nil, services.ErrDirectoryInvalidCredentials
+	}
+
+	entry := result.Entries[0]
+	if err := conn.Bind(entry.DN, password); err != nil {
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, services.ErrDirectoryInvalidCredentials
+		}
+		return nil, fmt.Errorf("gagal bind sebagai pengguna direktori: %w", err)
+	}
+	return d.toUser(entry), nil
+}
+
+// ListUsers mengimplementasikan metode ListUsers dari Directory.
+func (d *LDAPDirectory) ListUsers() ([]services.DirectoryUser, error) {
+	conn, err := d.connect()
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	result, err := conn.SearchWithPaging(ldap.NewSearchRequest(
+		d.config.BaseDN, ldap.ScopeWholeSubtree, ldap.NeverDerefAliases, 0, 0, false,
+		d.config.UserFilter, d.attributes(), nil,
+	), ldapPageSize)
+	if err != nil {
+		return nil, fmt.Errorf("gagal membaca pengguna direktori: %w", err)
+	}
+
+	users := make([]services.DirectoryUser, 0, len(result.Entries))
+	for _, entry := range result.Entries {
+		if user := d.toUser(entry); user.Username != "" {
+			users = append(users, *user)
+		}
+	}
+	return users, nil
+}
+
+// connect membuka koneksi dan melakukan bind dengan akun layanan.
+func (d *LDAPDirectory) connect() (*ldap.Conn, error) {
+	tlsConfig := &tls.Config{InsecureSkipVerify: d.config.InsecureSkipVerify} // Hanya untuk direktori uji dengan sertifikat self-signed
+	conn, err := ldap.DialURL(d.config.URL,
+		ldap.DialWithTLSConfig(tlsConfig),
+		ldap.DialWithDialer(&net.Dialer{Timeout: d.config.Timeout}),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("gagal terhubung ke direktori: %w", err)
+	}
+	conn.SetTimeout(d.config.Timeout)
+
+	if d.config.StartTLS {
+		if err := conn.StartTLS(tlsConfig); err != nil {
+			conn.Close()
+			return nil, fmt.Errorf("gagal StartTLS ke direktori: %w", err)
+		}
+	}
+
+	if err := conn.Bind(d.config.BindDN, d.config.BindPassword); err != nil {
+		conn.Close()
+		if ldap.IsErrorWithCode(err, ldap.LDAPResultInvalidCredentials) {
+			return nil, errors.New("kredensial akun layanan direktori ditolak")
+		}
+		return nil, fmt.Errorf("gagal bind akun layanan direktori: %w", err)
+	}
+	return conn, nil
+}
+
+// attributes mengembalikan atribut yang dibaca dari entri pengguna.
+func (d *LDAPDirectory) attributes() []string {
+	return []string{
+		d.config.UsernameAttribute,
+		d.config.EmailAttribute,
+		d.config.FirstNameAttribute,
+		d.config.LastNameAttribute,
+		d.config.GroupAttribute,
+	}
+}
+
+// toUser memetakan entri LDAP ke DirectoryUser.
+func (d *LDAPDirectory) toUser(entry *ldap.Entry) *services.DirectoryUser {
+	return &services.DirectoryUser{
+		DN:        entry.DN,
+		Username:  entry.GetAttributeValue(d.config.UsernameAttribute),
+		Email:     entry.GetAttributeValue(d.config.EmailAttribute),
+		FirstName: entry.GetAttributeValue(d.config.FirstNameAttribute),
+		LastName:  entry.GetAttributeValue(d.config.LastNameAttribute),
+		Groups:    entry.GetAttributeValues(d.config.GroupAttribute),
+	}
+}
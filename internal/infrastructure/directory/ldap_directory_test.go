@@ -0,0 +1,336 @@
+package directory
+
+import (
+	"errors"
+	"net"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/services"
+
+	ber "github.com/go-asn1-ber/asn1-ber"
+	"github.com/go-ldap/ldap/v3"
+	"github.com/jimlambrt/gldap"
+)
+
+const (
+	testBaseDN       = "ou=people,dc=example,dc=org"
+	testBindDN       = "cn=svc,dc=example,dc=org"
+	testBindPassword = "svc-secret"
+)
+
+// testEntry adalah entri pengguna di direktori uji.
+type testEntry struct {
+	dn       string
+	password string
+	attrs    map[string][]string
+}
+
+// testDirectory adalah server LDAP di dalam proses. Filter pencarian dievaluasi sungguhan sehingga
+// pengujian melihat entri yang benar-benar cocok dengan filter yang dikirim klien.
+type testDirectory struct {
+	entries []testEntry
+
+	mu       sync.Mutex
+	searches []testSearch
+}
+
+// testSearch mencatat satu pencarian: filter yang diterima server dan jumlah entri yang cocok.
+type testSearch struct {
+	filter  string
+	matched int
+}
+
+// newTestDirectory menjalankan server LDAP uji dan mengembalikan konfigurasi LDAPDirectory yang menunjuk ke sana.
+func newTestDirectory(t *testing.T, entries ...testEntry) (*testDirectory, LDAPConfig) {
+	t.Helper()
+	dir := &testDirectory{entries: entries}
+
+	mux, err := gldap.NewMux()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := mux.Bind(dir.bind); err != nil {
+		t.Fatal(err)
+	}
+	if err := mux.Search(dir.search); err != nil {
+		t.Fatal(err)
+	}
+	server, err := gldap.NewServer()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := server.Router(mux); err != nil {
+		t.Fatal(err)
+	}
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	go server.Run(addr)
+	t.Cleanup(func() { server.Stop() })
+	for deadline := time.Now().Add(5 * time.Second); !server.Ready(); {
+		if time.Now().After(deadline) {
+			t.Fatal("server LDAP uji tidak siap")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+
+	return dir, LDAPConfig{
+		URL:                "ldap://" + addr,
+		BindDN:             testBindDN,
+		BindPassword:       testBindPassword,
+		BaseDN:             testBaseDN,
+		UserFilter:         "(objectClass=person)",
+		UsernameAttribute:  "uid",
+		EmailAttribute:     "mail",
+		FirstNameAttribute: "givenName",
+		LastNameAttribute:  "sn",
+		GroupAttribute:     "memberOf",
+		Timeout:            5 * time.Second,
+	}
+}
+
+func (d *testDirectory) bind(w *gldap.ResponseWriter, r *gldap.Request) {
+	resp := r.NewBindResponse(gldap.WithResponseCode(gldap.ResultInvalidCredentials))
+	defer w.Write(resp)
+
+	m, err := r.GetSimpleBindMessage()
+	if err != nil {
+		return
+	}
+	if m.UserName == testBindDN && string(m.Password) == testBindPassword {
+		resp.SetResultCode(gldap.ResultSuccess)
+		return
+	}
+	for _, entry := range d.entries {
+		if strings.EqualFold(entry.dn, m.UserName) && m.Password != "" && string(m.Password) == entry.password {
+			resp.SetResultCode(gldap.ResultSuccess)
+			return
+		}
+	}
+}
+
+func (d *testDirectory) search(w *gldap.ResponseWriter, r *gldap.Request) {
+	resp := r.NewSearchDoneResponse(gldap.WithResponseCode(gldap.ResultOperationsError))
+	defer w.Write(resp)
+
+	m, err := r.GetSearchMessage()
+	if err != nil {
+		return
+	}
+	filter, err := ldap.CompileFilter(m.Filter)
+	if err != nil {
+		resp.SetResultCode(gldap.ResultFilterError)
+		return
+	}
+
+	matched := 0
+	for _, entry := range d.entries {
+		if !strings.HasSuffix(strings.ToLower(entry.dn), strings.ToLower(m.BaseDN)) || !matchFilter(filter, entry.attrs) {
+			continue
+		}
+		matched++
+		w.Write(r.NewSearchResponseEntry(entry.dn, gldap.WithAttributes(entry.attrs)))
+	}
+
+	d.mu.Lock()
+	d.searches = append(d.searches, testSearch{filter: m.Filter, matched: matched})
+	d.mu.Unlock()
+	resp.SetResultCode(gldap.ResultSuccess)
+}
+
+// lastSearch mengembalikan pencarian terakhir yang diterima server.
+func (d *testDirectory) lastSearch(t *testing.T) testSearch {
+	t.Helper()
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	if len(d.searches) == 0 {
+		t.Fatal("server tidak menerima pencarian")
+	}
+	return d.searches[len(d.searches)-1]
+}
+
+// matchFilter mengevaluasi filter hasil kompilasi terhadap atribut entri. Hanya operator yang dipakai
+// LDAPDirectory yang didukung; operator lain dianggap tidak cocok.
+func matchFilter(filter *ber.Packet, attrs map[string][]string) bool {
+	switch filter.Tag {
+	case ldap.FilterAnd:
+		for _, child := range filter.Children {
+			if !matchFilter(child, attrs) {
+				return false
+			}
+		}
+		return true
+	case ldap.FilterOr:
+		for _, child := range filter.Children {
+			if matchFilter(child, attrs) {
+				return true
+			}
+		}
+		return false
+	case ldap.FilterNot:
+		return !matchFilter(filter.Children[0], attrs)
+	case ldap.FilterPresent:
+		return len(attributeValues(attrs, filter.Data.String())) > 0
+	case ldap.FilterEqualityMatch:
+		want := filter.Children[1].Data.String()
+		for _, value := range attributeValues(attrs, filter.Children[0].Data.String()) {
+			if strings.EqualFold(value, want) {
+				return true
+			}
+		}
+		return false
+	}
+	return false
+}
+
+// attributeValues mengembalikan nilai atribut tanpa membedakan huruf besar-kecil nama atribut.
+func attributeValues(attrs map[string][]string, name string) []string {
+	for key, values := range attrs {
+		if strings.EqualFold(key, name) {
+			return values
+		}
+	}
+	return nil
+}
+
+func testPeople() []testEntry {
+	return []testEntry{
+		{
+			dn:       "uid=alice," + testBaseDN,
+			password: "alice-secret",
+			attrs: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"alice"},
+				"mail":        {"alice@example.org"},
+				"givenName":   {"Alice"},
+				"sn":          {"Liddell"},
+				"memberOf":    {"cn=admins,ou=groups,dc=example,dc=org", "cn=staff,ou=groups,dc=example,dc=org"},
+			},
+		},
+		{
+			dn:       "uid=bob," + testBaseDN,
+			password: "bob-secret",
+			attrs: map[string][]string{
+				"objectClass": {"person"},
+				"uid":         {"bob"},
+				"mail":        {"bob@example.org"},
+			},
+		},
+		{
+			dn:       "uid=printer," + testBaseDN,
+			password: "printer-secret",
+			attrs: map[string][]string{
+				"objectClass": {"device"},
+				"uid":         {"printer"},
+			},
+		},
+	}
+}
+
+func TestLDAPDirectoryAuthenticate(t *testing.T) {
+	_, config := newTestDirectory(t, testPeople()...)
+	directory := NewLDAPDirectory(config)
+
+	user, err := directory.Authenticate("alice", "alice-secret")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if user.DN != "uid=alice,"+testBaseDN || user.Username != "alice" || user.Email != "alice@example.org" ||
+		user.FirstName != "Alice" || user.LastName != "Liddell" || len(user.Groups) != 2 {
+		t.Fatalf("pengguna direktori tidak sesuai: %+v", user)
+	}
+}
+
+func TestLDAPDirectoryAuthenticateBindFailure(t *testing.T) {
+	_, config := newTestDirectory(t, testPeople()...)
+	directory := NewLDAPDirectory(config)
+
+	tests := []struct {
+		name     string
+		username string
+		password string
+	}{
+		{"password salah", "alice", "bob-secret"},
+		{"password kosong", "alice", ""},
+		{"pengguna tidak ada", "mallory", "alice-secret"},
+		{"entri di luar filter pengguna", "printer", "printer-secret"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := directory.Authenticate(tt.username, tt.password); !errors.Is(err, services.ErrDirectoryInvalidCredentials) {
+				t.Fatalf("err = %v, ingin ErrDirectoryInvalidCredentials", err)
+			}
+		})
+	}
+}
+
+func TestLDAPDirectoryServiceAccountBindFailure(t *testing.T) {
+	_, config := newTestDirectory(t, testPeople()...)
+	config.BindPassword = "salah"
+	directory := NewLDAPDirectory(config)
+
+	// Akun layanan yang ditolak adalah kesalahan konfigurasi, bukan kredensial pengguna yang salah
+	_, err := directory.Authenticate("alice", "alice-secret")
+	if err == nil || errors.Is(err, services.ErrDirectoryInvalidCredentials) {
+		t.Fatalf("Authenticate err = %v, ingin kesalahan akun layanan", err)
+	}
+	if _, err := directory.ListUsers(); err == nil {
+		t.Fatal("ListUsers berhasil dengan akun layanan yang ditolak")
+	}
+}
+
+func TestLDAPDirectoryAuthenticateEscapesFilter(t *testing.T) {
+	dir, config := newTestDirectory(t, testPeople()...)
+	directory := NewLDAPDirectory(config)
+
+	tests := []struct {
+		username string
+		escaped  string
+	}{
+		// Tanpa escaping filter menjadi (&(objectClass=person)(uid=*)(uid=*)) yang cocok dengan semua pengguna
+		{"*)(uid=*", `(uid=\2a\29\28uid=\2a)`},
+		// Tanpa escaping filter menjadi (&(objectClass=person)(uid=*)(uid=alice)) yang cocok dengan alice
+		{"*)(uid=alice", `(uid=\2a\29\28uid=alice)`},
+		{"alice)(|(objectClass=*", `(uid=alice\29\28|\28objectClass=\2a)`},
+		{"*", `(uid=\2a)`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.username, func(t *testing.T) {
+			if _, err := directory.Authenticate(tt.username, "alice-secret"); !errors.Is(err, services.ErrDirectoryInvalidCredentials) {
+				t.Fatalf("err = %v, ingin ErrDirectoryInvalidCredentials", err)
+			}
+			search := dir.lastSearch(t)
+			if want := "(&(objectClass=person)" + tt.escaped + ")"; search.filter != want {
+				t.Fatalf("filter = %q, ingin %q", search.filter, want)
+			}
+			if search.matched != 0 {
+				t.Fatalf("filter cocok dengan %d entri, ingin 0", search.matched)
+			}
+		})
+	}
+}
+
+func TestLDAPDirectoryListUsers(t *testing.T) {
+	_, config := newTestDirectory(t, testPeople()...)
+	directory := NewLDAPDirectory(config)
+
+	users, err := directory.ListUsers()
+	if err != nil {
+		t.Fatalf("ListUsers: %v", err)
+	}
+	usernames := map[string]bool{}
+	for _, user := range users {
+		usernames[user.Username] = true
+	}
+	if len(users) != 2 || !usernames["alice"] || !usernames["bob"] {
+		t.Fatalf("ListUsers = %+v, ingin alice dan bob saja", users)
+	}
+}
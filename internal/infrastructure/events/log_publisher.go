@@ -0,0 +1,28 @@
+package events
+
+import (
+	"fiber-usermanagement/internal/domain/services"
+
+	"go.uber.org/zap"
+)
+
+// LogPublisher adalah implementasi services.EventPublisher yang hanya mencatat event ke log.
+// Dipakai jika tidak ada antrean event yang dikonfigurasi.
+type LogPublisher struct {
+	logger *zap.Logger
+}
+
+// NewLogPublisher membuat instance baru dari LogPublisher.
+func NewLogPublisher(logger *zap.Logger) services.EventPublisher {
+	return &LogPublisher{logger: logger}
+}
+
+// Publish mengimplementasikan metode Publish dari EventPublisher.
+func (p *LogPublisher) Publish(event services.Event) error {
+	p.logger.Info("Event published",
+		zap.String("type", event.Type),
+		zap.Time("occurred_at", event.OccurredAt),
+		zap.Any("data", event.Data),
+	)
+	return nil
+}
@@ -0,0 +1,90 @@
+package events
+
+import (
+	"encoding/json"
+	"fmt"
+	"sync"
+
+	"fiber-usermanagement/internal/domain/services"
+
+	amqp "github.com/rabbitmq/amqp091-go"
+)
+
+// RabbitMQPublisher adalah implementasi services.EventPublisher yang mengirim event sebagai JSON ke
+// antrean RabbitMQ yang durable. Koneksi dibuka saat event pertama diterbitkan dan dibuka ulang
+// setelah terputus, sehingga broker yang sedang mati tidak menggagalkan start aplikasi.
+type RabbitMQPublisher struct {
+	url   string
+	queue string
+
+	mu      sync.Mutex
+	conn    *amqp.Connection
+	channel *amqp.Channel
+}
+
+// NewRabbitMQPublisher membuat instance baru dari RabbitMQPublisher.
+func NewRabbitMQPublisher(url, queue string) services.EventPublisher {
+	return &RabbitMQPublisher{url: url, queue: queue}
+}
+
+// Publish mengimplementasikan metode Publish dari EventPublisher.
+func (p *RabbitMQPublisher) Publish(event services.Event) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if err := p.connect(); err != nil {
+		return fmt.Errorf("failed to connect to rabbitmq: %w", err)
+	}
+	err = p.channel.Publish("", p.queue, false, false, amqp.Publishing{
+		ContentType:  "application/json",
+		DeliveryMode: amqp.Persistent,
+		Type:         event.Type,
+		Timestamp:    event.OccurredAt,
+		Body:         body,
+	})
+	if err != nil {
+		p.close()
+		return fmt.Errorf("failed to publish event %s: %w", event.Type, err)
+	}
+	return nil
+}
+
+// connect membuka koneksi dan mendeklarasikan antrean jika belum ada koneksi yang hidup.
+func (p *RabbitMQPublisher) connect() error {
+	if p.conn != nil && !p.conn.IsClosed() && p.channel != nil && !p.channel.IsClosed() {
+		return nil
+	}
+	p.close()
+
+	conn, err := amqp.Dial(p.url)
+	if err != nil {
+		return err
+	}
+	channel, err := conn.Channel()
+	if err != nil {
+		_ = conn.Close()
+		return err
+	}
+	if _, err := channel.QueueDeclare(p.queue, true, false, false, false, nil); err != nil {
+		_ = conn.Close()
+		return err
+	}
+
+	p.conn = conn
+	p.channel = channel
+	return nil
+}
+
+// close menutup koneksi yang ada agar Publish berikutnya membuka koneksi baru.
+func (p *RabbitMQPublisher) close() {
+	if p.conn != nil {
+		_ = p.conn.Close()
+	}
+	p.conn = nil
+	p.channel = nil
+}
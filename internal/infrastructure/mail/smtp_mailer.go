@@ -0,0 +1,50 @@
+package mail
+
+import (
+	"fmt"
+	"net/smtp"
+	"strings"
+
+	"fiber-usermanagement/internal/domain/services"
+)
+
+// SMTPMailer adalah implementasi services.Mailer yang mengirim email melalui server SMTP.
+type SMTPMailer struct {
+	addr     string
+	auth     smtp.Auth
+	from     string
+	fromName string
+}
+
+// NewSMTPMailer membuat instance baru dari SMTPMailer.
+// Autentikasi PLAIN hanya dipakai jika username diisi.
+func NewSMTPMailer(host string, port int, username, password, from, fromName string) services.Mailer {
+	var auth smtp.Auth
+	if username != "" {
+		auth = smtp.PlainAuth("", username, password, host)
+	}
+
+	return &SMTPMailer{
+		addr:     fmt.Sprintf("%s:%d", host, port),
+		auth:     auth,
+		from:     from,
+		fromName: fromName,
+	}
+}
+
+// Send mengimplementasikan metode Send dari Mailer.
+func (m *SMTPMailer) Send(to, subject, body string) error {
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s <%s>\r\n", m.fromName, m.from)
+	fmt.Fprintf(&msg, "To: %s\r\n", to)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", subject)
+	msg.WriteString("MIME-Version: 1.0\r\n")
+	msg.WriteString("Content-Type: text/plain; charset=\"utf-8\"\r\n")
+	msg.WriteString("\r\n")
+	msg.WriteString(body)
+
+	if err := smtp.SendMail(m.addr, m.auth, m.from, []string{to}, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send email to %s: %w", to, err)
+	}
+	return nil
+}
@@ -0,0 +1,71 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// AccessPolicyRepositoryImpl adalah implementasi repositories.AccessPolicyRepository dengan GORM.
+type AccessPolicyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAccessPolicyRepository membuat instance baru dari AccessPolicyRepositoryImpl.
+func NewAccessPolicyRepository(db *gorm.DB) repositories.AccessPolicyRepository {
+	return &AccessPolicyRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari AccessPolicyRepository.
+func (r *AccessPolicyRepositoryImpl) Create(policy *entities.AccessPolicy) error {
+	return r.db.Create(policy).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari AccessPolicyRepository.
+func (r *AccessPolicyRepositoryImpl) FindByID(id uuid.UUID) (*entities.AccessPolicy, error) {
+	var policy entities.AccessPolicy
+	result := r.db.First(&policy, "id = ?", id)
+	return &policy, result.Error
+}
+
+// FindByName mengimplementasikan metode FindByName dari AccessPolicyRepository.
+func (r *AccessPolicyRepositoryImpl) FindByName(name string) (*entities.AccessPolicy, error) {
+	var policy entities.AccessPolicy
+	result := r.db.First(&policy, "name = ?", name)
+	return &policy, result.Error
+}
+
+// FindAll mengimplementasikan metode FindAll dari AccessPolicyRepository.
+func (r *AccessPolicyRepositoryImpl) FindAll() ([]entities.AccessPolicy, error) {
+	var policies []entities.AccessPolicy
+	result := r.db.Order("name ASC").Find(&policies)
+	return policies, result.Error
+}
+
+// FindEnabled mengimplementasikan metode FindEnabled dari AccessPolicyRepository.
+func (r *AccessPolicyRepositoryImpl) FindEnabled() ([]entities.AccessPolicy, error) {
+	var policies []entities.AccessPolicy
+	result := r.db.Where("enabled = ?", true).Order("name ASC").Find(&policies)
+	return policies, result.Error
+}
+
+// Update mengimplementasikan metode Update dari AccessPolicyRepository.
+func (r *AccessPolicyRepositoryImpl) Update(policy *entities.AccessPolicy) error {
+	return r.db.Model(policy).
+		Select("name", "description", "effect", "actions", "condition", "enabled").
+		Updates(policy).Error
+}
+
+// Delete mengimplementasikan metode Delete dari AccessPolicyRepository.
+func (r *AccessPolicyRepositoryImpl) Delete(id uuid.UUID) error {
+	result := r.db.Delete(&entities.AccessPolicy{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,111 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// accessReviewItemBatchSize membatasi jumlah baris per INSERT saat snapshot kampanye disimpan.
+const accessReviewItemBatchSize = 500
+
+// AccessReviewRepositoryImpl adalah implementasi repositories.AccessReviewRepository dengan GORM.
+type AccessReviewRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAccessReviewRepository membuat instance baru dari AccessReviewRepositoryImpl.
+func NewAccessReviewRepository(db *gorm.DB) repositories.AccessReviewRepository {
+	return &AccessReviewRepositoryImpl{db: db}
+}
+
+// CreateCampaign mengimplementasikan metode CreateCampaign dari AccessReviewRepository.
+func (r *AccessReviewRepositoryImpl) CreateCampaign(campaign *entities.AccessReviewCampaign) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Create(campaign).Error; err != nil {
+			return err
+		}
+		if len(campaign.Items) == 0 {
+			return nil
+		}
+		for n := range campaign.Items {
+			campaign.Items[n].CampaignID = campaign.ID
+		}
+		return tx.CreateInBatches(&campaign.Items, accessReviewItemBatchSize).Error
+	})
+}
+
+// FindCampaign mengimplementasikan metode FindCampaign dari AccessReviewRepository.
+func (r *AccessReviewRepositoryImpl) FindCampaign(id uuid.UUID) (*entities.AccessReviewCampaign, error) {
+	var campaign entities.AccessReviewCampaign
+	result := r.db.Preload("Items", func(db *gorm.DB) *gorm.DB {
+		return db.Order("username ASC, role_name ASC")
+	}).First(&campaign, "id = ?", id)
+	return &campaign, result.Error
+}
+
+// FindCampaigns mengimplementasikan metode FindCampaigns dari AccessReviewRepository.
+func (r *AccessReviewRepositoryImpl) FindCampaigns() ([]entities.AccessReviewCampaign, error) {
+	var campaigns []entities.AccessReviewCampaign
+	result := r.db.Order("created_at DESC").Find(&campaigns)
+	return campaigns, result.Error
+}
+
+// FindDueCampaigns mengimplementasikan metode FindDueCampaigns dari AccessReviewRepository.
+func (r *AccessReviewRepositoryImpl) FindDueCampaigns(now time.Time) ([]entities.AccessReviewCampaign, error) {
+	var campaigns []entities.AccessReviewCampaign
+	result := r.db.Where("status = ? AND due_at <= ?", entities.AccessReviewActive, now).
+		Order("due_at ASC").
+		Find(&campaigns)
+	return campaigns, result.Error
+}
+
+// CompleteCampaign mengimplementasikan metode CompleteCampaign dari AccessReviewRepository.
+func (r *AccessReviewRepositoryImpl) CompleteCampaign(id uuid.UUID, at time.Time) (bool, error) {
+	result := r.db.Model(&entities.AccessReviewCampaign{}).
+		Where("id = ? AND status = ?", id, entities.AccessReviewActive).
+		Updates(map[string]any{"status": entities.AccessReviewCompleted, "completed_at": at, "updated_at": at})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindItem mengimplementasikan metode FindItem dari AccessReviewRepository.
+func (r *AccessReviewRepositoryImpl) FindItem(id uuid.UUID) (*entities.AccessReviewItem, error) {
+	var item entities.AccessReviewItem
+	result := r.db.First(&item, "id = ?", id)
+	return &item, result.Error
+}
+
+// FindPendingItemsByReviewer mengimplementasikan metode FindPendingItemsByReviewer dari AccessReviewRepository.
+func (r *AccessReviewRepositoryImpl) FindPendingItemsByReviewer(reviewerID uuid.UUID) ([]entities.AccessReviewItem, error) {
+	var items []entities.AccessReviewItem
+	result := r.db.
+		Joins("JOIN access_review_campaigns ON access_review_campaigns.id = access_review_items.campaign_id").
+		Where("access_review_campaigns.status = ?", entities.AccessReviewActive).
+		Where("access_review_items.reviewer_id = ? AND access_review_items.decision = ''", reviewerID).
+		Order("access_review_campaigns.due_at ASC, access_review_items.username ASC, access_review_items.role_name ASC").
+		Find(&items)
+	return items, result.Error
+}
+
+// DecideItem mengimplementasikan metode DecideItem dari AccessReviewRepository.
+func (r *AccessReviewRepositoryImpl) DecideItem(item *entities.AccessReviewItem) (bool, error) {
+	result := r.db.Model(&entities.AccessReviewItem{}).
+		Where("id = ? AND decision = ''", item.ID).
+		Updates(map[string]any{
+			"decision":   item.Decision,
+			"comment":    item.Comment,
+			"decided_by": item.DecidedBy,
+			"decided_at": item.DecidedAt,
+		})
+	return result.RowsAffected > 0, result.Error
+}
+
+// MarkItemRevoked mengimplementasikan metode MarkItemRevoked dari AccessReviewRepository.
+func (r *AccessReviewRepositoryImpl) MarkItemRevoked(id uuid.UUID, at time.Time) error {
+	return r.db.Model(&entities.AccessReviewItem{}).Where("id = ?", id).Update("revoked_at", at).Error
+}
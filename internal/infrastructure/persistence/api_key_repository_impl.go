@@ -0,0 +1,61 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// APIKeyRepositoryImpl adalah implementasi repositories.APIKeyRepository dengan GORM.
+type APIKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAPIKeyRepository membuat instance baru dari APIKeyRepositoryImpl.
+func NewAPIKeyRepository(db *gorm.DB) repositories.APIKeyRepository {
+	return &APIKeyRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari APIKeyRepository.
+func (r *APIKeyRepositoryImpl) Create(key *entities.APIKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindByPrefix mengimplementasikan metode FindByPrefix dari APIKeyRepository.
+func (r *APIKeyRepositoryImpl) FindByPrefix(prefix string) (*entities.APIKey, error) {
+	var key entities.APIKey
+	result := r.db.Where("prefix = ?", prefix).First(&key)
+	return &key, result.Error
+}
+
+// FindByUser mengimplementasikan metode FindByUser dari APIKeyRepository.
+func (r *APIKeyRepositoryImpl) FindByUser(userID uuid.UUID) ([]entities.APIKey, error) {
+	var keys []entities.APIKey
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&keys)
+	return keys, result.Error
+}
+
+// Revoke mengimplementasikan metode Revoke dari APIKeyRepository.
+func (r *APIKeyRepositoryImpl) Revoke(userID, id uuid.UUID, now time.Time) error {
+	result := r.db.Model(&entities.APIKey{}).
+		Where("id = ? AND user_id = ? AND revoked_at IS NULL", id, userID).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Touch mengimplementasikan metode Touch dari APIKeyRepository.
+func (r *APIKeyRepositoryImpl) Touch(id uuid.UUID, usedAt time.Time, ip string) error {
+	return r.db.Model(&entities.APIKey{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"last_used_at": usedAt, "last_used_ip": ip}).Error
+}
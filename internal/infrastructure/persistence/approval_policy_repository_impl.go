@@ -0,0 +1,69 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// ApprovalPolicyRepositoryImpl adalah implementasi repositories.ApprovalPolicyRepository dengan GORM.
+type ApprovalPolicyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewApprovalPolicyRepository membuat instance baru dari ApprovalPolicyRepositoryImpl.
+func NewApprovalPolicyRepository(db *gorm.DB) repositories.ApprovalPolicyRepository {
+	return &ApprovalPolicyRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari ApprovalPolicyRepository.
+func (r *ApprovalPolicyRepositoryImpl) Create(policy *entities.ApprovalPolicy) error {
+	return r.db.Omit(clause.Associations).Create(policy).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari ApprovalPolicyRepository.
+func (r *ApprovalPolicyRepositoryImpl) FindByID(id uuid.UUID) (*entities.ApprovalPolicy, error) {
+	var policy entities.ApprovalPolicy
+	result := r.db.Preload("Role").First(&policy, "id = ?", id)
+	return &policy, result.Error
+}
+
+// FindByRole mengimplementasikan metode FindByRole dari ApprovalPolicyRepository.
+func (r *ApprovalPolicyRepositoryImpl) FindByRole(roleID *uuid.UUID) (*entities.ApprovalPolicy, error) {
+	var policy entities.ApprovalPolicy
+	query := r.db.Preload("Role")
+	if roleID == nil {
+		query = query.Where("role_id IS NULL")
+	} else {
+		query = query.Where("role_id = ?", *roleID)
+	}
+	result := query.First(&policy)
+	return &policy, result.Error
+}
+
+// FindAll mengimplementasikan metode FindAll dari ApprovalPolicyRepository.
+func (r *ApprovalPolicyRepositoryImpl) FindAll() ([]entities.ApprovalPolicy, error) {
+	var policies []entities.ApprovalPolicy
+	result := r.db.Preload("Role").Order("created_at ASC").Find(&policies)
+	return policies, result.Error
+}
+
+// Update mengimplementasikan metode Update dari ApprovalPolicyRepository.
+func (r *ApprovalPolicyRepositoryImpl) Update(policy *entities.ApprovalPolicy) error {
+	return r.db.Model(policy).Select("required_approvals", "approver_roles", "updated_at").Updates(policy).Error
+}
+
+// Delete mengimplementasikan metode Delete dari ApprovalPolicyRepository.
+func (r *ApprovalPolicyRepositoryImpl) Delete(id uuid.UUID) error {
+	result := r.db.Delete(&entities.ApprovalPolicy{}, "id = ?", id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,103 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// AssignmentRequestRepositoryImpl adalah implementasi repositories.AssignmentRequestRepository dengan GORM.
+type AssignmentRequestRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAssignmentRequestRepository membuat instance baru dari AssignmentRequestRepositoryImpl.
+func NewAssignmentRequestRepository(db *gorm.DB) repositories.AssignmentRequestRepository {
+	return &AssignmentRequestRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari AssignmentRequestRepository.
+func (r *AssignmentRequestRepositoryImpl) Create(request *entities.AssignmentRequest) error {
+	return r.db.Omit(clause.Associations).Create(request).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari AssignmentRequestRepository.
+func (r *AssignmentRequestRepositoryImpl) FindByID(id uuid.UUID) (*entities.AssignmentRequest, error) {
+	var request entities.AssignmentRequest
+	result := r.withDetails().First(&request, "id = ?", id)
+	return &request, result.Error
+}
+
+// FindByStatus mengimplementasikan metode FindByStatus dari AssignmentRequestRepository.
+func (r *AssignmentRequestRepositoryImpl) FindByStatus(status string) ([]entities.AssignmentRequest, error) {
+	var requests []entities.AssignmentRequest
+	query := r.withDetails().Order("created_at ASC")
+	if status != "" {
+		query = query.Where("status = ?", status)
+	}
+	result := query.Find(&requests)
+	return requests, result.Error
+}
+
+// FindPendingFor mengimplementasikan metode FindPendingFor dari AssignmentRequestRepository.
+func (r *AssignmentRequestRepositoryImpl) FindPendingFor(userID uuid.UUID, roleID *uuid.UUID) (*entities.AssignmentRequest, error) {
+	var request entities.AssignmentRequest
+	query := r.db.Where("user_id = ? AND status = ?", userID, entities.AssignmentPending)
+	if roleID == nil {
+		query = query.Where("role_id IS NULL")
+	} else {
+		query = query.Where("role_id = ?", *roleID)
+	}
+	result := query.First(&request)
+	return &request, result.Error
+}
+
+// AddDecision mengimplementasikan metode AddDecision dari AssignmentRequestRepository.
+func (r *AssignmentRequestRepositoryImpl) AddDecision(decision *entities.AssignmentDecision) error {
+	result := r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(decision)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrDuplicatedKey
+	}
+	return nil
+}
+
+// UpdateStatus mengimplementasikan metode UpdateStatus dari AssignmentRequestRepository.
+func (r *AssignmentRequestRepositoryImpl) UpdateStatus(request *entities.AssignmentRequest) error {
+	result := r.db.Model(&entities.AssignmentRequest{}).
+		Where("id = ? AND status = ?", request.ID, entities.AssignmentPending).
+		Updates(map[string]interface{}{"status": request.Status, "decided_at": request.DecidedAt})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Reopen mengimplementasikan metode Reopen dari AssignmentRequestRepository.
+func (r *AssignmentRequestRepositoryImpl) Reopen(request *entities.AssignmentRequest) error {
+	result := r.db.Model(&entities.AssignmentRequest{}).
+		Where("id = ? AND status = ?", request.ID, entities.AssignmentApproved).
+		Updates(map[string]interface{}{"status": entities.AssignmentPending, "decided_at": nil})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// withDetails memuat penerima, Role dan keputusan permintaan, keputusan terlama lebih dulu.
+func (r *AssignmentRequestRepositoryImpl) withDetails() *gorm.DB {
+	return r.db.Preload("User").Preload("Role").Preload("Decisions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("created_at ASC")
+	})
+}
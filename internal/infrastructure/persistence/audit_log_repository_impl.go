@@ -0,0 +1,53 @@
+package persistence
+
+import (
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// AuditLogRepositoryImpl adalah implementasi repositories.AuditLogRepository dengan GORM.
+type AuditLogRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewAuditLogRepository membuat instance baru dari AuditLogRepositoryImpl.
+func NewAuditLogRepository(db *gorm.DB) repositories.AuditLogRepository {
+	return &AuditLogRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari AuditLogRepository.
+func (r *AuditLogRepositoryImpl) Create(entry *entities.AuditLog) error {
+	return r.db.Create(entry).Error
+}
+
+// Find mengimplementasikan metode Find dari AuditLogRepository.
+func (r *AuditLogRepositoryImpl) Find(filter repositories.AuditLogFilter) ([]entities.AuditLog, error) {
+	query := r.db.Order("created_at DESC")
+	if filter.ActorID != nil {
+		query = query.Where("actor_id = ?", *filter.ActorID)
+	}
+	if filter.Action != "" {
+		query = query.Where("action = ?", filter.Action)
+	}
+	if filter.TargetType != "" {
+		query = query.Where("target_type = ?", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		query = query.Where("target_id = ?", filter.TargetID)
+	}
+	if filter.Since != nil {
+		query = query.Where("created_at >= ?", *filter.Since)
+	}
+	if filter.Until != nil {
+		query = query.Where("created_at < ?", *filter.Until)
+	}
+	if filter.Limit > 0 {
+		query = query.Limit(filter.Limit)
+	}
+
+	var entries []entities.AuditLog
+	result := query.Find(&entries)
+	return entries, result.Error
+}
@@ -0,0 +1,41 @@
+package persistence
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// ChallengeStoreImpl adalah implementasi repositories.ChallengeStore di atas Redis.
+type ChallengeStoreImpl struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewChallengeStore membuat instance baru dari ChallengeStoreImpl.
+func NewChallengeStore(client *redis.Client) repositories.ChallengeStore {
+	return &ChallengeStoreImpl{client: client, prefix: "challenge:"}
+}
+
+// Save mengimplementasikan metode Save dari ChallengeStore.
+func (s *ChallengeStoreImpl) Save(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return s.client.Set(ctx, s.prefix+key, value, ttl).Err()
+}
+
+// SaveNew mengimplementasikan metode SaveNew dari ChallengeStore dengan SET NX.
+func (s *ChallengeStoreImpl) SaveNew(ctx context.Context, key string, value []byte, ttl time.Duration) (bool, error) {
+	return s.client.SetNX(ctx, s.prefix+key, value, ttl).Result()
+}
+
+// Take mengimplementasikan metode Take dari ChallengeStore dengan GETDEL.
+func (s *ChallengeStoreImpl) Take(ctx context.Context, key string) ([]byte, error) {
+	value, err := s.client.GetDel(ctx, s.prefix+key).Bytes()
+	if errors.Is(err, redis.Nil) {
+		return nil, repositories.ErrChallengeNotFound
+	}
+	return value, err
+}
@@ -0,0 +1,217 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// GroupRepositoryImpl adalah implementasi repositories.GroupRepository dengan GORM.
+type GroupRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewGroupRepository membuat instance baru dari GroupRepositoryImpl.
+func NewGroupRepository(db *gorm.DB) repositories.GroupRepository {
+	return &GroupRepositoryImpl{db: db}
+}
+
+// groupMember adalah baris tabel join group_members.
+type groupMember struct {
+	GroupID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	UserID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (groupMember) TableName() string {
+	return "group_members"
+}
+
+// groupSubgroup adalah baris tabel join group_subgroups.
+type groupSubgroup struct {
+	GroupID    uuid.UUID `gorm:"type:uuid;primaryKey"`
+	SubgroupID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (groupSubgroup) TableName() string {
+	return "group_subgroups"
+}
+
+// groupRole adalah baris tabel join group_roles.
+type groupRole struct {
+	GroupID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RoleID  uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (groupRole) TableName() string {
+	return "group_roles"
+}
+
+// groupAncestorsQuery menelusuri Group induk secara bertingkat. UNION (bukan UNION ALL) membuang baris
+// yang sudah pernah dikunjungi sehingga penelusuran tetap berhenti walaupun data mengandung siklus.
+const groupAncestorsQuery = `
+WITH RECURSIVE ancestors(id) AS (
+	SELECT group_subgroups.group_id FROM group_subgroups
+	JOIN groups ON groups.id = group_subgroups.group_id AND groups.deleted_at IS NULL
+	WHERE group_subgroups.subgroup_id = ?
+	UNION
+	SELECT group_subgroups.group_id FROM group_subgroups
+	JOIN ancestors ON group_subgroups.subgroup_id = ancestors.id
+	JOIN groups ON groups.id = group_subgroups.group_id AND groups.deleted_at IS NULL
+)
+SELECT id FROM ancestors`
+
+// groupMembersQuery menelusuri Group id beserta Subgroup-nya secara bertingkat lalu mengembalikan
+// semua anggotanya. Seperti groupAncestorsQuery, UNION menghentikan penelusuran pada data bersiklus.
+const groupMembersQuery = `
+WITH RECURSIVE descendants(id) AS (
+	SELECT groups.id FROM groups WHERE groups.id = @group AND groups.deleted_at IS NULL
+	UNION
+	SELECT group_subgroups.subgroup_id FROM group_subgroups
+	JOIN descendants ON group_subgroups.group_id = descendants.id
+	JOIN groups ON groups.id = group_subgroups.subgroup_id AND groups.deleted_at IS NULL
+)
+SELECT DISTINCT group_members.user_id FROM group_members
+JOIN descendants ON group_members.group_id = descendants.id`
+
+// Create mengimplementasikan metode Create dari GroupRepository.
+func (r *GroupRepositoryImpl) Create(group *entities.Group) error {
+	return r.db.Omit(clause.Associations).Create(group).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari GroupRepository.
+func (r *GroupRepositoryImpl) FindByID(id uuid.UUID) (*entities.Group, error) {
+	var group entities.Group
+	result := r.db.Preload("Members").Preload("Subgroups").Preload("Roles").First(&group, "id = ?", id)
+	return &group, result.Error
+}
+
+// FindByName mengimplementasikan metode FindByName dari GroupRepository.
+func (r *GroupRepositoryImpl) FindByName(name string) (*entities.Group, error) {
+	var group entities.Group
+	result := r.db.Where("name = ?", name).First(&group)
+	return &group, result.Error
+}
+
+// FindAll mengimplementasikan metode FindAll dari GroupRepository.
+func (r *GroupRepositoryImpl) FindAll() ([]entities.Group, error) {
+	var groups []entities.Group
+	result := r.db.Preload("Roles").Order("name ASC").Find(&groups)
+	return groups, result.Error
+}
+
+// FindByMember mengimplementasikan metode FindByMember dari GroupRepository.
+func (r *GroupRepositoryImpl) FindByMember(userID uuid.UUID) ([]entities.Group, error) {
+	var groups []entities.Group
+	result := r.db.Preload("Roles").
+		Joins("JOIN group_members ON group_members.group_id = groups.id").
+		Where("group_members.user_id = ?", userID).
+		Order("groups.name ASC").
+		Find(&groups)
+	return groups, result.Error
+}
+
+// FindParents mengimplementasikan metode FindParents dari GroupRepository.
+func (r *GroupRepositoryImpl) FindParents(id uuid.UUID) ([]entities.Group, error) {
+	var groups []entities.Group
+	result := r.db.Preload("Roles").
+		Joins("JOIN group_subgroups ON group_subgroups.group_id = groups.id").
+		Where("group_subgroups.subgroup_id = ?", id).
+		Order("groups.name ASC").
+		Find(&groups)
+	return groups, result.Error
+}
+
+// FindAncestorIDs mengimplementasikan metode FindAncestorIDs dari GroupRepository.
+func (r *GroupRepositoryImpl) FindAncestorIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	result := r.db.Raw(groupAncestorsQuery, id).Scan(&ids)
+	return ids, result.Error
+}
+
+// FindMemberIDs mengimplementasikan metode FindMemberIDs dari GroupRepository.
+func (r *GroupRepositoryImpl) FindMemberIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	result := r.db.Raw(groupMembersQuery, map[string]interface{}{"group": id}).Scan(&ids)
+	return ids, result.Error
+}
+
+// Update mengimplementasikan metode Update dari GroupRepository.
+func (r *GroupRepositoryImpl) Update(group *entities.Group) error {
+	return r.db.Model(group).Select("name", "description", "updated_at").Updates(group).Error
+}
+
+// Delete mengimplementasikan metode Delete dari GroupRepository.
+// Relasi ikut dihapus agar anggota tidak tetap menerima Role lewat Group yang sudah dihapus.
+func (r *GroupRepositoryImpl) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&entities.Group{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("group_id = ?", id).Delete(&groupMember{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("group_id = ? OR subgroup_id = ?", id, id).Delete(&groupSubgroup{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("group_id = ?", id).Delete(&groupRole{}).Error
+	})
+}
+
+// AddMember mengimplementasikan metode AddMember dari GroupRepository.
+func (r *GroupRepositoryImpl) AddMember(groupID, userID uuid.UUID) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&groupMember{GroupID: groupID, UserID: userID}).Error
+}
+
+// RemoveMember mengimplementasikan metode RemoveMember dari GroupRepository.
+func (r *GroupRepositoryImpl) RemoveMember(groupID, userID uuid.UUID) error {
+	result := r.db.Where("group_id = ? AND user_id = ?", groupID, userID).Delete(&groupMember{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// AddSubgroup mengimplementasikan metode AddSubgroup dari GroupRepository.
+func (r *GroupRepositoryImpl) AddSubgroup(groupID, subgroupID uuid.UUID) error {
+	return r.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&groupSubgroup{GroupID: groupID, SubgroupID: subgroupID}).Error
+}
+
+// RemoveSubgroup mengimplementasikan metode RemoveSubgroup dari GroupRepository.
+func (r *GroupRepositoryImpl) RemoveSubgroup(groupID, subgroupID uuid.UUID) error {
+	result := r.db.Where("group_id = ? AND subgroup_id = ?", groupID, subgroupID).Delete(&groupSubgroup{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// ReplaceRoles mengimplementasikan metode ReplaceRoles dari GroupRepository.
+func (r *GroupRepositoryImpl) ReplaceRoles(groupID uuid.UUID, roleIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("group_id = ?", groupID).Delete(&groupRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]groupRole, 0, len(roleIDs))
+		for _, roleID := range roleIDs {
+			rows = append(rows, groupRole{GroupID: groupID, RoleID: roleID})
+		}
+		return tx.Create(&rows).Error
+	})
+}
@@ -0,0 +1,92 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// InvitationRepositoryImpl adalah implementasi repositories.InvitationRepository dengan GORM.
+type InvitationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewInvitationRepository membuat instance baru dari InvitationRepositoryImpl.
+func NewInvitationRepository(db *gorm.DB) repositories.InvitationRepository {
+	return &InvitationRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari InvitationRepository.
+func (r *InvitationRepositoryImpl) Create(invitation *entities.Invitation) error {
+	return r.db.Omit("Organization").Create(invitation).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari InvitationRepository.
+func (r *InvitationRepositoryImpl) FindByID(organizationID, id uuid.UUID) (*entities.Invitation, error) {
+	var invitation entities.Invitation
+	result := r.db.Where("organization_id = ? AND id = ?", organizationID, id).First(&invitation)
+	return &invitation, result.Error
+}
+
+// FindByHash mengimplementasikan metode FindByHash dari InvitationRepository.
+func (r *InvitationRepositoryImpl) FindByHash(hash string) (*entities.Invitation, error) {
+	var invitation entities.Invitation
+	result := r.db.Preload("Organization").Where("token_hash = ?", hash).First(&invitation)
+	return &invitation, result.Error
+}
+
+// FindPending mengimplementasikan metode FindPending dari InvitationRepository.
+func (r *InvitationRepositoryImpl) FindPending(organizationID uuid.UUID, now time.Time) ([]entities.Invitation, error) {
+	var invitations []entities.Invitation
+	result := r.pending(now).Where("organization_id = ?", organizationID).Order("created_at DESC").Find(&invitations)
+	return invitations, result.Error
+}
+
+// FindPendingByEmail mengimplementasikan metode FindPendingByEmail dari InvitationRepository.
+func (r *InvitationRepositoryImpl) FindPendingByEmail(organizationID uuid.UUID, email string, now time.Time) (*entities.Invitation, error) {
+	var invitation entities.Invitation
+	result := r.pending(now).Where("organization_id = ? AND email = ?", organizationID, email).First(&invitation)
+	return &invitation, result.Error
+}
+
+// UpdateToken mengimplementasikan metode UpdateToken dari InvitationRepository.
+func (r *InvitationRepositoryImpl) UpdateToken(invitation *entities.Invitation) error {
+	return r.db.Model(invitation).Select("token_hash", "expires_at", "sent_at", "updated_at").Updates(invitation).Error
+}
+
+// MarkAccepted mengimplementasikan metode MarkAccepted dari InvitationRepository.
+func (r *InvitationRepositoryImpl) MarkAccepted(id, userID uuid.UUID, now time.Time) error {
+	result := r.db.Model(&entities.Invitation{}).
+		Where("id = ? AND accepted_at IS NULL AND revoked_at IS NULL", id).
+		Updates(map[string]interface{}{"accepted_at": now, "accepted_by": userID})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Revoke mengimplementasikan metode Revoke dari InvitationRepository.
+func (r *InvitationRepositoryImpl) Revoke(organizationID, id uuid.UUID, now time.Time) error {
+	result := r.db.Model(&entities.Invitation{}).
+		Where("organization_id = ? AND id = ? AND accepted_at IS NULL AND revoked_at IS NULL", organizationID, id).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// pending membatasi query ke undangan yang belum diterima, dicabut atau kedaluwarsa.
+func (r *InvitationRepositoryImpl) pending(now time.Time) *gorm.DB {
+	return r.db.Where("accepted_at IS NULL AND revoked_at IS NULL AND expires_at > ?", now)
+}
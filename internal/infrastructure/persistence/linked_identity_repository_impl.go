@@ -0,0 +1,66 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// LinkedIdentityRepositoryImpl adalah implementasi repositories.LinkedIdentityRepository dengan GORM.
+type LinkedIdentityRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewLinkedIdentityRepository membuat instance baru dari LinkedIdentityRepositoryImpl.
+func NewLinkedIdentityRepository(db *gorm.DB) repositories.LinkedIdentityRepository {
+	return &LinkedIdentityRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari LinkedIdentityRepository.
+func (r *LinkedIdentityRepositoryImpl) Create(identity *entities.LinkedIdentity) error {
+	return r.db.Create(identity).Error
+}
+
+// FindBySubject mengimplementasikan metode FindBySubject dari LinkedIdentityRepository.
+func (r *LinkedIdentityRepositoryImpl) FindBySubject(provider, subject string) (*entities.LinkedIdentity, error) {
+	var identity entities.LinkedIdentity
+	result := r.db.Where("provider = ? AND subject = ?", provider, subject).First(&identity)
+	return &identity, result.Error
+}
+
+// FindByUser mengimplementasikan metode FindByUser dari LinkedIdentityRepository.
+func (r *LinkedIdentityRepositoryImpl) FindByUser(userID uuid.UUID) ([]entities.LinkedIdentity, error) {
+	var identities []entities.LinkedIdentity
+	result := r.db.Where("user_id = ?", userID).Order("created_at ASC").Find(&identities)
+	return identities, result.Error
+}
+
+// FindByProvider mengimplementasikan metode FindByProvider dari LinkedIdentityRepository.
+func (r *LinkedIdentityRepositoryImpl) FindByProvider(provider string) ([]entities.LinkedIdentity, error) {
+	var identities []entities.LinkedIdentity
+	result := r.db.Where("provider = ?", provider).Find(&identities)
+	return identities, result.Error
+}
+
+// Touch mengimplementasikan metode Touch dari LinkedIdentityRepository.
+func (r *LinkedIdentityRepositoryImpl) Touch(id uuid.UUID, email string, loginAt time.Time) error {
+	return r.db.Model(&entities.LinkedIdentity{}).
+		Where("id = ?", id).
+		Updates(map[string]interface{}{"email": email, "last_login_at": loginAt}).Error
+}
+
+// Delete mengimplementasikan metode Delete dari LinkedIdentityRepository.
+func (r *LinkedIdentityRepositoryImpl) Delete(userID, id uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&entities.LinkedIdentity{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"context"
+	"strconv"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// LoginAttemptRepositoryImpl adalah implementasi repositories.LoginAttemptRepository di atas Redis.
+// Setiap key disimpan sebagai hash {count, last} dengan TTL sebesar window percobaan.
+type LoginAttemptRepositoryImpl struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewLoginAttemptRepository membuat instance baru dari LoginAttemptRepositoryImpl.
+func NewLoginAttemptRepository(client *redis.Client) repositories.LoginAttemptRepository {
+	return &LoginAttemptRepositoryImpl{client: client, prefix: "login_attempts:"}
+}
+
+// Get mengimplementasikan metode Get dari LoginAttemptRepository.
+func (r *LoginAttemptRepositoryImpl) Get(ctx context.Context, key string) (*entities.LoginAttempts, error) {
+	var values *redis.MapStringStringCmd
+	var ttl *redis.DurationCmd
+	_, err := r.client.Pipelined(ctx, func(pipe redis.Pipeliner) error {
+		values = pipe.HGetAll(ctx, r.prefix+key)
+		ttl = pipe.PTTL(ctx, r.prefix+key)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return toLoginAttempts(values.Val(), ttl.Val()), nil
+}
+
+// RegisterFailure mengimplementasikan metode RegisterFailure dari LoginAttemptRepository.
+func (r *LoginAttemptRepositoryImpl) RegisterFailure(ctx context.Context, key string, window time.Duration) (*entities.LoginAttempts, error) {
+	now := time.Now()
+	var count *redis.IntCmd
+	_, err := r.client.TxPipelined(ctx, func(pipe redis.Pipeliner) error {
+		count = pipe.HIncrBy(ctx, r.prefix+key, "count", 1)
+		pipe.HSet(ctx, r.prefix+key, "last", now.UnixMilli())
+		pipe.PExpire(ctx, r.prefix+key, window)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &entities.LoginAttempts{
+		Failures:      int(count.Val()),
+		LastFailureAt: now,
+		ExpiresIn:     window,
+	}, nil
+}
+
+// Reset mengimplementasikan metode Reset dari LoginAttemptRepository.
+func (r *LoginAttemptRepositoryImpl) Reset(ctx context.Context, key string) error {
+	return r.client.Del(ctx, r.prefix+key).Err()
+}
+
+// toLoginAttempts mengubah isi hash Redis menjadi entities.LoginAttempts.
+func toLoginAttempts(values map[string]string, ttl time.Duration) *entities.LoginAttempts {
+	attempts := &entities.LoginAttempts{}
+	if count, err := strconv.Atoi(values["count"]); err == nil {
+		attempts.Failures = count
+	}
+	if last, err := strconv.ParseInt(values["last"], 10, 64); err == nil {
+		attempts.LastFailureAt = time.UnixMilli(last)
+	}
+	if ttl > 0 {
+		attempts.ExpiresIn = ttl
+	}
+	return attempts
+}
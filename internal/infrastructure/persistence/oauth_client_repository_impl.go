@@ -0,0 +1,50 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// OAuthClientRepositoryImpl adalah implementasi repositories.OAuthClientRepository dengan GORM.
+type OAuthClientRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOAuthClientRepository membuat instance baru dari OAuthClientRepositoryImpl.
+func NewOAuthClientRepository(db *gorm.DB) repositories.OAuthClientRepository {
+	return &OAuthClientRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari OAuthClientRepository.
+func (r *OAuthClientRepositoryImpl) Create(client *entities.OAuthClient) error {
+	return r.db.Create(client).Error
+}
+
+// FindByClientID mengimplementasikan metode FindByClientID dari OAuthClientRepository.
+func (r *OAuthClientRepositoryImpl) FindByClientID(clientID string) (*entities.OAuthClient, error) {
+	var client entities.OAuthClient
+	result := r.db.Where("client_id = ?", clientID).First(&client)
+	return &client, result.Error
+}
+
+// FindAll mengimplementasikan metode FindAll dari OAuthClientRepository.
+func (r *OAuthClientRepositoryImpl) FindAll() ([]entities.OAuthClient, error) {
+	var clients []entities.OAuthClient
+	result := r.db.Order("created_at DESC").Find(&clients)
+	return clients, result.Error
+}
+
+// Delete mengimplementasikan metode Delete dari OAuthClientRepository.
+func (r *OAuthClientRepositoryImpl) Delete(id uuid.UUID) (*entities.OAuthClient, error) {
+	var client entities.OAuthClient
+	if err := r.db.Where("id = ?", id).First(&client).Error; err != nil {
+		return nil, err
+	}
+	if err := r.db.Delete(&client).Error; err != nil {
+		return nil, err
+	}
+	return &client, nil
+}
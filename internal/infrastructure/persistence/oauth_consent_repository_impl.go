@@ -0,0 +1,40 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// OAuthConsentRepositoryImpl adalah implementasi repositories.OAuthConsentRepository dengan GORM.
+type OAuthConsentRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOAuthConsentRepository membuat instance baru dari OAuthConsentRepositoryImpl.
+func NewOAuthConsentRepository(db *gorm.DB) repositories.OAuthConsentRepository {
+	return &OAuthConsentRepositoryImpl{db: db}
+}
+
+// Find mengimplementasikan metode Find dari OAuthConsentRepository.
+func (r *OAuthConsentRepositoryImpl) Find(userID uuid.UUID, clientID string) (*entities.OAuthConsent, error) {
+	var consent entities.OAuthConsent
+	result := r.db.Where("user_id = ? AND client_id = ?", userID, clientID).First(&consent)
+	return &consent, result.Error
+}
+
+// Save mengimplementasikan metode Save dari OAuthConsentRepository.
+func (r *OAuthConsentRepositoryImpl) Save(consent *entities.OAuthConsent) error {
+	return r.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "client_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"scopes", "updated_at"}),
+	}).Create(consent).Error
+}
+
+// DeleteByClient mengimplementasikan metode DeleteByClient dari OAuthConsentRepository.
+func (r *OAuthConsentRepositoryImpl) DeleteByClient(clientID string) error {
+	return r.db.Where("client_id = ?", clientID).Delete(&entities.OAuthConsent{}).Error
+}
@@ -0,0 +1,68 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// OAuthRefreshTokenRepositoryImpl adalah implementasi repositories.OAuthRefreshTokenRepository dengan GORM.
+type OAuthRefreshTokenRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOAuthRefreshTokenRepository membuat instance baru dari OAuthRefreshTokenRepositoryImpl.
+func NewOAuthRefreshTokenRepository(db *gorm.DB) repositories.OAuthRefreshTokenRepository {
+	return &OAuthRefreshTokenRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari OAuthRefreshTokenRepository.
+func (r *OAuthRefreshTokenRepositoryImpl) Create(token *entities.OAuthRefreshToken) error {
+	return r.db.Create(token).Error
+}
+
+// FindByHash mengimplementasikan metode FindByHash dari OAuthRefreshTokenRepository.
+func (r *OAuthRefreshTokenRepositoryImpl) FindByHash(hash string) (*entities.OAuthRefreshToken, error) {
+	var token entities.OAuthRefreshToken
+	result := r.db.Where("token_hash = ?", hash).First(&token)
+	return &token, result.Error
+}
+
+// MarkUsed mengimplementasikan metode MarkUsed dari OAuthRefreshTokenRepository.
+func (r *OAuthRefreshTokenRepositoryImpl) MarkUsed(id uuid.UUID, now time.Time) error {
+	result := r.db.Model(&entities.OAuthRefreshToken{}).
+		Where("id = ? AND used_at IS NULL AND revoked_at IS NULL", id).
+		Update("used_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// RevokeFamily mengimplementasikan metode RevokeFamily dari OAuthRefreshTokenRepository.
+func (r *OAuthRefreshTokenRepositoryImpl) RevokeFamily(familyID uuid.UUID, now time.Time) error {
+	return r.db.Model(&entities.OAuthRefreshToken{}).
+		Where("family_id = ? AND revoked_at IS NULL", familyID).
+		Update("revoked_at", now).Error
+}
+
+// RevokeBySession mengimplementasikan metode RevokeBySession dari OAuthRefreshTokenRepository.
+func (r *OAuthRefreshTokenRepositoryImpl) RevokeBySession(sessionID uuid.UUID, now time.Time) error {
+	return r.db.Model(&entities.OAuthRefreshToken{}).
+		Where("session_id = ? AND revoked_at IS NULL", sessionID).
+		Update("revoked_at", now).Error
+}
+
+// RevokeByClient mengimplementasikan metode RevokeByClient dari OAuthRefreshTokenRepository.
+func (r *OAuthRefreshTokenRepositoryImpl) RevokeByClient(clientID string, now time.Time) error {
+	return r.db.Model(&entities.OAuthRefreshToken{}).
+		Where("client_id = ? AND revoked_at IS NULL", clientID).
+		Update("revoked_at", now).Error
+}
@@ -0,0 +1,88 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// OrganizationMemberRepositoryImpl adalah implementasi repositories.OrganizationMemberRepository dengan GORM.
+type OrganizationMemberRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOrganizationMemberRepository membuat instance baru dari OrganizationMemberRepositoryImpl.
+func NewOrganizationMemberRepository(db *gorm.DB) repositories.OrganizationMemberRepository {
+	return &OrganizationMemberRepositoryImpl{db: db}
+}
+
+// organizationMemberRole adalah baris tabel join organization_member_roles.
+type organizationMemberRole struct {
+	OrganizationMemberID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RoleID               uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (organizationMemberRole) TableName() string {
+	return "organization_member_roles"
+}
+
+// Create mengimplementasikan metode Create dari OrganizationMemberRepository.
+// Role yang sudah dimuat di member ikut ditautkan tanpa membuat Role baru.
+func (r *OrganizationMemberRepositoryImpl) Create(member *entities.OrganizationMember) error {
+	return r.db.Omit("User", "Roles.*").Create(member).Error
+}
+
+// Find mengimplementasikan metode Find dari OrganizationMemberRepository.
+func (r *OrganizationMemberRepositoryImpl) Find(organizationID, userID uuid.UUID) (*entities.OrganizationMember, error) {
+	var member entities.OrganizationMember
+	result := r.db.Preload("Roles").
+		Where("organization_id = ? AND user_id = ?", organizationID, userID).
+		First(&member)
+	return &member, result.Error
+}
+
+// FindByOrganization mengimplementasikan metode FindByOrganization dari OrganizationMemberRepository.
+// Anggota yang akun penggunanya sudah dihapus (soft delete) tidak ikut dikembalikan.
+func (r *OrganizationMemberRepositoryImpl) FindByOrganization(organizationID uuid.UUID) ([]entities.OrganizationMember, error) {
+	var members []entities.OrganizationMember
+	result := r.db.Preload("User").Preload("Roles").
+		Joins("JOIN users ON users.id = organization_members.user_id AND users.deleted_at IS NULL").
+		Where("organization_members.organization_id = ?", organizationID).
+		Order("users.username ASC").
+		Find(&members)
+	return members, result.Error
+}
+
+// ReplaceRoles mengimplementasikan metode ReplaceRoles dari OrganizationMemberRepository.
+func (r *OrganizationMemberRepositoryImpl) ReplaceRoles(memberID uuid.UUID, roleIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("organization_member_id = ?", memberID).Delete(&organizationMemberRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]organizationMemberRole, 0, len(roleIDs))
+		for _, roleID := range roleIDs {
+			rows = append(rows, organizationMemberRole{OrganizationMemberID: memberID, RoleID: roleID})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// Delete mengimplementasikan metode Delete dari OrganizationMemberRepository.
+func (r *OrganizationMemberRepositoryImpl) Delete(organizationID, userID uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		var member entities.OrganizationMember
+		if err := tx.Where("organization_id = ? AND user_id = ?", organizationID, userID).First(&member).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("organization_member_id = ?", member.ID).Delete(&organizationMemberRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Delete(&member).Error
+	})
+}
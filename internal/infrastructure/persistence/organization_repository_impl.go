@@ -0,0 +1,81 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// OrganizationRepositoryImpl adalah implementasi repositories.OrganizationRepository dengan GORM.
+type OrganizationRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewOrganizationRepository membuat instance baru dari OrganizationRepositoryImpl.
+func NewOrganizationRepository(db *gorm.DB) repositories.OrganizationRepository {
+	return &OrganizationRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari OrganizationRepository.
+func (r *OrganizationRepositoryImpl) Create(organization *entities.Organization) error {
+	return r.db.Create(organization).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari OrganizationRepository.
+func (r *OrganizationRepositoryImpl) FindByID(id uuid.UUID) (*entities.Organization, error) {
+	var organization entities.Organization
+	result := r.db.Where("id = ?", id).First(&organization)
+	return &organization, result.Error
+}
+
+// FindBySlug mengimplementasikan metode FindBySlug dari OrganizationRepository.
+func (r *OrganizationRepositoryImpl) FindBySlug(slug string) (*entities.Organization, error) {
+	var organization entities.Organization
+	result := r.db.Where("slug = ?", slug).First(&organization)
+	return &organization, result.Error
+}
+
+// FindAll mengimplementasikan metode FindAll dari OrganizationRepository.
+func (r *OrganizationRepositoryImpl) FindAll() ([]entities.Organization, error) {
+	var organizations []entities.Organization
+	result := r.db.Order("name ASC").Find(&organizations)
+	return organizations, result.Error
+}
+
+// FindByUser mengimplementasikan metode FindByUser dari OrganizationRepository.
+func (r *OrganizationRepositoryImpl) FindByUser(userID uuid.UUID) ([]entities.Organization, error) {
+	var organizations []entities.Organization
+	result := r.db.
+		Joins("JOIN organization_members ON organization_members.organization_id = organizations.id").
+		Where("organization_members.user_id = ?", userID).
+		Order("organizations.name ASC").
+		Find(&organizations)
+	return organizations, result.Error
+}
+
+// Update mengimplementasikan metode Update dari OrganizationRepository.
+func (r *OrganizationRepositoryImpl) Update(organization *entities.Organization) error {
+	return r.db.Model(organization).Select("name", "updated_at").Updates(organization).Error
+}
+
+// Delete mengimplementasikan metode Delete dari OrganizationRepository.
+// Keanggotaan ikut dihapus agar tidak ada pengguna yang tetap bisa memilih organisasi yang sudah dihapus.
+func (r *OrganizationRepositoryImpl) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Where("id = ?", id).Delete(&entities.Organization{})
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+
+		members := tx.Model(&entities.OrganizationMember{}).Select("id").Where("organization_id = ?", id)
+		if err := tx.Where("organization_member_id IN (?)", members).Delete(&organizationMemberRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("organization_id = ?", id).Delete(&entities.OrganizationMember{}).Error
+	})
+}
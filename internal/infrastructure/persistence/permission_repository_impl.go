@@ -0,0 +1,110 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// PermissionRepositoryImpl adalah implementasi repositories.PermissionRepository dengan GORM.
+type PermissionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewPermissionRepository membuat instance baru dari PermissionRepositoryImpl.
+func NewPermissionRepository(db *gorm.DB) repositories.PermissionRepository {
+	return &PermissionRepositoryImpl{db: db}
+}
+
+// userPermissionsQuery mengumpulkan Role yang diberikan langsung ke User (hanya yang sedang berlaku) dan lewat semua Group-nya,
+// termasuk Group induk secara bertingkat, lalu semua Role yang diwarisi Role tersebut. UNION membuang
+// baris yang sudah dikunjungi sehingga penelusuran tetap berhenti walaupun data mengandung siklus.
+const userPermissionsQuery = `
+WITH RECURSIVE user_groups(id) AS (
+	SELECT group_members.group_id FROM group_members
+	JOIN groups ON groups.id = group_members.group_id AND groups.deleted_at IS NULL
+	WHERE group_members.user_id = @user
+	UNION
+	SELECT group_subgroups.group_id FROM group_subgroups
+	JOIN user_groups ON group_subgroups.subgroup_id = user_groups.id
+	JOIN groups ON groups.id = group_subgroups.group_id AND groups.deleted_at IS NULL
+), user_role_ids(id) AS (
+	SELECT user_roles.role_id FROM user_roles WHERE user_roles.user_id = @user AND ` + activeUserRoleCondition + `
+	UNION
+	SELECT group_roles.role_id FROM group_roles JOIN user_groups ON group_roles.group_id = user_groups.id
+), ` + effectiveRolesQuery
+
+// memberPermissionsQuery mengumpulkan Role anggota di satu Organization beserta semua Role yang diwarisinya.
+const memberPermissionsQuery = `
+WITH RECURSIVE user_role_ids(id) AS (
+	SELECT organization_member_roles.role_id FROM organization_member_roles
+	JOIN organization_members ON organization_members.id = organization_member_roles.organization_member_id
+	WHERE organization_members.organization_id = @organization AND organization_members.user_id = @user
+), ` + effectiveRolesQuery
+
+// effectiveRolesQuery melanjutkan CTE user_role_ids dengan Role yang diwarisi secara bertingkat
+// dan mengembalikan nama Permission efektifnya. Role yang sudah dihapus memutus rantai pewarisan.
+const effectiveRolesQuery = `effective_roles(id) AS (
+	SELECT roles.id FROM roles JOIN user_role_ids ON user_role_ids.id = roles.id
+	WHERE roles.deleted_at IS NULL
+	UNION
+	SELECT role_parents.parent_id FROM role_parents
+	JOIN effective_roles ON role_parents.role_id = effective_roles.id
+	JOIN roles ON roles.id = role_parents.parent_id AND roles.deleted_at IS NULL
+)
+SELECT DISTINCT permissions.name FROM permissions
+JOIN role_permissions ON role_permissions.permission_id = permissions.id
+JOIN effective_roles ON effective_roles.id = role_permissions.role_id
+WHERE permissions.deleted_at IS NULL`
+
+// FindNamesByUser mengimplementasikan metode FindNamesByUser dari PermissionRepository.
+// Role yang diterima lewat Group (termasuk Group induk) dan Role yang diwarisi ikut dihitung; Role, Group dan Permission
+// yang sudah dihapus (soft delete) tidak.
+func (r *PermissionRepositoryImpl) FindNamesByUser(userID uuid.UUID) ([]string, error) {
+	var names []string
+	result := r.db.Raw(userPermissionsQuery, map[string]interface{}{"user": userID}).Scan(&names)
+	return names, result.Error
+}
+
+// FindNamesByMember mengimplementasikan metode FindNamesByMember dari PermissionRepository.
+// Permission dari Role yang diwarisi ikut dihitung.
+func (r *PermissionRepositoryImpl) FindNamesByMember(organizationID, userID uuid.UUID) ([]string, error) {
+	var names []string
+	result := r.db.Raw(memberPermissionsQuery, map[string]interface{}{"organization": organizationID, "user": userID}).Scan(&names)
+	return names, result.Error
+}
+
+// EnsureNames mengimplementasikan metode EnsureNames dari PermissionRepository.
+func (r *PermissionRepositoryImpl) EnsureNames(names []string) ([]entities.Permission, error) {
+	permissions := make([]entities.Permission, 0, len(names))
+	if len(names) == 0 {
+		return permissions, nil
+	}
+
+	rows := make([]entities.Permission, 0, len(names))
+	for _, name := range names {
+		rows = append(rows, entities.Permission{Name: name})
+	}
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Omit(clause.Associations).Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error; err != nil {
+			return err
+		}
+		return tx.Where("name IN ?", names).Order("name ASC").Find(&permissions).Error
+	})
+	return permissions, err
+}
+
+// FindExistingNames mengimplementasikan metode FindExistingNames dari PermissionRepository.
+func (r *PermissionRepositoryImpl) FindExistingNames(names []string) ([]string, error) {
+	var existing []string
+	if len(names) == 0 {
+		return existing, nil
+	}
+	result := r.db.Model(&entities.Permission{}).
+		Where("name IN ?", names).
+		Pluck("name", &existing)
+	return existing, result.Error
+}
@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// RecoveryCodeRepositoryImpl adalah implementasi repositories.RecoveryCodeRepository dengan GORM.
+type RecoveryCodeRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRecoveryCodeRepository membuat instance baru dari RecoveryCodeRepositoryImpl.
+func NewRecoveryCodeRepository(db *gorm.DB) repositories.RecoveryCodeRepository {
+	return &RecoveryCodeRepositoryImpl{db: db}
+}
+
+// ReplaceForUser mengimplementasikan metode ReplaceForUser dari RecoveryCodeRepository.
+// Penghapusan dan penyimpanan dilakukan dalam satu transaksi.
+func (r *RecoveryCodeRepositoryImpl) ReplaceForUser(userID uuid.UUID, codes []entities.RecoveryCode) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("user_id = ?", userID).Delete(&entities.RecoveryCode{}).Error; err != nil {
+			return err
+		}
+		if len(codes) == 0 {
+			return nil
+		}
+		return tx.Create(&codes).Error
+	})
+}
+
+// FindUnused mengimplementasikan metode FindUnused dari RecoveryCodeRepository.
+func (r *RecoveryCodeRepositoryImpl) FindUnused(userID uuid.UUID, codeHash string) (*entities.RecoveryCode, error) {
+	var code entities.RecoveryCode
+	result := r.db.Where("user_id = ? AND code_hash = ? AND used_at IS NULL", userID, codeHash).First(&code)
+	return &code, result.Error
+}
+
+// MarkUsed mengimplementasikan metode MarkUsed dari RecoveryCodeRepository.
+// Kondisi used_at IS NULL mencegah kode yang sama dipakai dua kali secara bersamaan.
+func (r *RecoveryCodeRepositoryImpl) MarkUsed(id uuid.UUID) error {
+	result := r.db.Model(&entities.RecoveryCode{}).
+		Where("id = ? AND used_at IS NULL", id).
+		Update("used_at", time.Now())
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// DeleteByUser mengimplementasikan metode DeleteByUser dari RecoveryCodeRepository.
+func (r *RecoveryCodeRepositoryImpl) DeleteByUser(userID uuid.UUID) error {
+	return r.db.Where("user_id = ?", userID).Delete(&entities.RecoveryCode{}).Error
+}
@@ -0,0 +1,368 @@
+package persistence
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// RoleRepositoryImpl adalah implementasi repositories.RoleRepository dengan GORM.
+type RoleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRoleRepository membuat instance baru dari RoleRepositoryImpl.
+func NewRoleRepository(db *gorm.DB) repositories.RoleRepository {
+	return &RoleRepositoryImpl{db: db}
+}
+
+// activeUserRoleCondition menyaring baris user_roles yang berlaku sekarang. Waktu diambil dari server
+// database agar konsisten antar replika aplikasi.
+const activeUserRoleCondition = `(user_roles.starts_at IS NULL OR user_roles.starts_at <= NOW()) AND (user_roles.expires_at IS NULL OR user_roles.expires_at > NOW())`
+
+// unexpiredUserRoleCondition menyaring baris user_roles yang belum kedaluwarsa, termasuk yang baru akan
+// berlaku. Dipakai pemeriksaan pemisahan tugas agar penetapan terjadwal ikut dihitung.
+const unexpiredUserRoleCondition = `(user_roles.expires_at IS NULL OR user_roles.expires_at > NOW())`
+
+// roleAssignmentsQuery mengumpulkan pasangan User dan Role dari penetapan langsung, dari Group bertingkat
+// dan dari keanggotaan Organization, tanpa Role yang diwarisi, beserta Group tempat User menjadi anggota
+// langsung atau Organization tempat Role anggota itu berlaku. Placeholder %s diisi filter User untuk
+// ketiga sumber.
+const roleAssignmentsQuery = `
+WITH RECURSIVE user_groups(user_id, id, member_group_id) AS (
+	SELECT group_members.user_id, group_members.group_id, group_members.group_id FROM group_members
+	JOIN groups ON groups.id = group_members.group_id AND groups.deleted_at IS NULL
+	WHERE %[1]s
+	UNION
+	SELECT user_groups.user_id, group_subgroups.group_id, user_groups.member_group_id FROM group_subgroups
+	JOIN user_groups ON group_subgroups.subgroup_id = user_groups.id
+	JOIN groups ON groups.id = group_subgroups.group_id AND groups.deleted_at IS NULL
+), assignments(user_id, role_id, group_id, nested, organization_id) AS (
+	SELECT user_roles.user_id, user_roles.role_id, CAST(NULL AS uuid), FALSE, CAST(NULL AS uuid) FROM user_roles
+	WHERE ` + unexpiredUserRoleCondition + ` AND %[2]s
+	UNION
+	SELECT user_groups.user_id, group_roles.role_id, user_groups.member_group_id, user_groups.id <> user_groups.member_group_id,
+		CAST(NULL AS uuid)
+	FROM group_roles
+	JOIN user_groups ON group_roles.group_id = user_groups.id
+	UNION
+	SELECT organization_members.user_id, organization_member_roles.role_id, CAST(NULL AS uuid), FALSE,
+		organization_members.organization_id
+	FROM organization_member_roles
+	JOIN organization_members ON organization_members.id = organization_member_roles.organization_member_id
+	JOIN organizations ON organizations.id = organization_members.organization_id AND organizations.deleted_at IS NULL
+	WHERE %[3]s
+)
+SELECT assignments.user_id, assignments.role_id, assignments.group_id, assignments.nested, assignments.organization_id
+FROM assignments
+JOIN roles ON roles.id = assignments.role_id AND roles.deleted_at IS NULL
+JOIN users ON users.id = assignments.user_id AND users.deleted_at IS NULL`
+
+// rolePermission adalah baris tabel join role_permissions.
+type rolePermission struct {
+	RoleID       uuid.UUID `gorm:"type:uuid;primaryKey"`
+	PermissionID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (rolePermission) TableName() string {
+	return "role_permissions"
+}
+
+// roleParent adalah baris tabel join role_parents.
+type roleParent struct {
+	RoleID   uuid.UUID `gorm:"type:uuid;primaryKey"`
+	ParentID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (roleParent) TableName() string {
+	return "role_parents"
+}
+
+// Create mengimplementasikan metode Create dari RoleRepository.
+func (r *RoleRepositoryImpl) Create(role *entities.Role) error {
+	return r.db.Omit(clause.Associations).Create(role).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari RoleRepository.
+func (r *RoleRepositoryImpl) FindByID(id uuid.UUID) (*entities.Role, error) {
+	var role entities.Role
+	result := r.db.Preload("Users").First(&role, "id = ?", id)
+	return &role, result.Error
+}
+
+// FindByName mengimplementasikan metode FindByName dari RoleRepository.
+func (r *RoleRepositoryImpl) FindByName(name string) (*entities.Role, error) {
+	var role entities.Role
+	result := r.db.Where("name = ?", name).First(&role)
+	return &role, result.Error
+}
+
+// FindAll mengimplementasikan metode FindAll dari RoleRepository.
+func (r *RoleRepositoryImpl) FindAll() ([]entities.Role, error) {
+	var roles []entities.Role
+	result := r.db.Preload("Users").Order("name ASC").Find(&roles)
+	return roles, result.Error
+}
+
+// FindByUser mengimplementasikan metode FindByUser dari RoleRepository.
+// Penetapan yang belum mulai atau sudah berakhir tidak ikut dihitung.
+func (r *RoleRepositoryImpl) FindByUser(userID uuid.UUID) ([]entities.Role, error) {
+	var roles []entities.Role
+	result := r.db.Joins("JOIN user_roles ON user_roles.role_id = roles.id").
+		Where("user_roles.user_id = ? AND "+activeUserRoleCondition, userID).
+		Order("roles.name ASC").
+		Find(&roles)
+	return roles, result.Error
+}
+
+// FindGraph mengimplementasikan metode FindGraph dari RoleRepository.
+func (r *RoleRepositoryImpl) FindGraph() ([]entities.Role, error) {
+	var roles []entities.Role
+	result := r.db.Preload("Permissions", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name ASC")
+	}).Preload("Parents").Order("name ASC").Find(&roles)
+	return roles, result.Error
+}
+
+// FindByNames mengimplementasikan metode FindByNames dari RoleRepository.
+func (r *RoleRepositoryImpl) FindByNames(names []string) ([]entities.Role, error) {
+	var roles []entities.Role
+	if len(names) == 0 {
+		return roles, nil
+	}
+	result := r.db.Where("name IN ?", names).Find(&roles)
+	return roles, result.Error
+}
+
+// Update mengimplementasikan metode Update dari RoleRepository.
+func (r *RoleRepositoryImpl) Update(role *entities.Role) error {
+	return r.db.Model(role).Select("name", "description", "updated_at").Updates(role).Error
+}
+
+// UpdateOwner mengimplementasikan metode UpdateOwner dari RoleRepository.
+func (r *RoleRepositoryImpl) UpdateOwner(roleID uuid.UUID, ownerID *uuid.UUID) error {
+	result := r.db.Model(&entities.Role{}).Where("id = ?", roleID).Update("owner_id", ownerID)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateSCIMManaged mengimplementasikan metode UpdateSCIMManaged dari RoleRepository.
+func (r *RoleRepositoryImpl) UpdateSCIMManaged(roleID uuid.UUID, managed bool) error {
+	result := r.db.Model(&entities.Role{}).Where("id = ?", roleID).Update("scim_managed", managed)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// UpdateTenantRole mengimplementasikan metode UpdateTenantRole dari RoleRepository.
+func (r *RoleRepositoryImpl) UpdateTenantRole(roleID uuid.UUID, tenant bool) error {
+	result := r.db.Model(&entities.Role{}).Where("id = ?", roleID).Update("tenant_role", tenant)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Delete mengimplementasikan metode Delete dari RoleRepository.
+// Semua penetapan Role (ke User, anggota organisasi dan Group) ikut dihapus agar tidak ada yang
+// tetap memegang Role yang sudah dihapus.
+func (r *RoleRepositoryImpl) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&entities.Role{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&entities.UserRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&organizationMemberRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&groupRole{}).Error; err != nil {
+			return err
+		}
+		if err := tx.Where("role_id = ?", id).Delete(&sodRuleRole{}).Error; err != nil {
+			return err
+		}
+		return tx.Where("role_id = ? OR parent_id = ?", id, id).Delete(&roleParent{}).Error
+	})
+}
+
+// ReplacePermissions mengimplementasikan metode ReplacePermissions dari RoleRepository.
+func (r *RoleRepositoryImpl) ReplacePermissions(roleID uuid.UUID, permissionIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&rolePermission{}).Error; err != nil {
+			return err
+		}
+		if len(permissionIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]rolePermission, 0, len(permissionIDs))
+		for _, permissionID := range permissionIDs {
+			rows = append(rows, rolePermission{RoleID: roleID, PermissionID: permissionID})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// ReplaceParents mengimplementasikan metode ReplaceParents dari RoleRepository.
+func (r *RoleRepositoryImpl) ReplaceParents(roleID uuid.UUID, parentIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("role_id = ?", roleID).Delete(&roleParent{}).Error; err != nil {
+			return err
+		}
+		if len(parentIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]roleParent, 0, len(parentIDs))
+		for _, parentID := range parentIDs {
+			rows = append(rows, roleParent{RoleID: roleID, ParentID: parentID})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// UpdateUserRoles mengimplementasikan metode UpdateUserRoles dari RoleRepository.
+func (r *RoleRepositoryImpl) UpdateUserRoles(userID uuid.UUID, grant, revoke []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(revoke) > 0 {
+			if err := tx.Where("user_id = ? AND role_id IN ?", userID, revoke).Delete(&entities.UserRole{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(grant) == 0 {
+			return nil
+		}
+
+		rows := make([]entities.UserRole, 0, len(grant))
+		for _, roleID := range grant {
+			rows = append(rows, entities.UserRole{UserID: userID, RoleID: roleID})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	})
+}
+
+// FindGrants mengimplementasikan metode FindGrants dari RoleRepository.
+func (r *RoleRepositoryImpl) FindGrants(userID uuid.UUID) ([]entities.UserRole, error) {
+	var grants []entities.UserRole
+	result := r.db.Preload("Role").
+		Joins("JOIN roles ON roles.id = user_roles.role_id AND roles.deleted_at IS NULL").
+		Where("user_roles.user_id = ?", userID).
+		Order("roles.name ASC").
+		Find(&grants)
+	return grants, result.Error
+}
+
+// FindGrant mengimplementasikan metode FindGrant dari RoleRepository.
+func (r *RoleRepositoryImpl) FindGrant(userID, roleID uuid.UUID) (*entities.UserRole, error) {
+	var grant entities.UserRole
+	result := r.db.Preload("Role").Where("user_id = ? AND role_id = ?", userID, roleID).First(&grant)
+	return &grant, result.Error
+}
+
+// Grant mengimplementasikan metode Grant dari RoleRepository.
+func (r *RoleRepositoryImpl) Grant(grant *entities.UserRole) error {
+	return r.db.Omit(clause.Associations).Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "user_id"}, {Name: "role_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"starts_at", "expires_at", "reason", "granted_by", "granted_at"}),
+	}).Create(grant).Error
+}
+
+// Revoke mengimplementasikan metode Revoke dari RoleRepository.
+func (r *RoleRepositoryImpl) Revoke(userID, roleID uuid.UUID) error {
+	result := r.db.Where("user_id = ? AND role_id = ?", userID, roleID).Delete(&entities.UserRole{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// FindExpiredGrants mengimplementasikan metode FindExpiredGrants dari RoleRepository.
+func (r *RoleRepositoryImpl) FindExpiredGrants(now time.Time) ([]entities.UserRole, error) {
+	var grants []entities.UserRole
+	result := r.db.Preload("Role", func(db *gorm.DB) *gorm.DB {
+		return db.Unscoped()
+	}).Where("expires_at <= ?", now).Order("expires_at ASC").Find(&grants)
+	return grants, result.Error
+}
+
+// DeleteExpiredGrant mengimplementasikan metode DeleteExpiredGrant dari RoleRepository.
+func (r *RoleRepositoryImpl) DeleteExpiredGrant(userID, roleID uuid.UUID, now time.Time) (bool, error) {
+	result := r.db.Where("user_id = ? AND role_id = ? AND expires_at <= ?", userID, roleID, now).Delete(&entities.UserRole{})
+	return result.RowsAffected > 0, result.Error
+}
+
+// FindAssignments mengimplementasikan metode FindAssignments dari RoleRepository.
+func (r *RoleRepositoryImpl) FindAssignments(userID uuid.UUID) ([]repositories.RoleAssignment, error) {
+	var assignments []repositories.RoleAssignment
+	query := fmt.Sprintf(roleAssignmentsQuery,
+		"group_members.user_id = @user", "user_roles.user_id = @user", "organization_members.user_id = @user")
+	result := r.db.Raw(query, map[string]interface{}{"user": userID}).Scan(&assignments)
+	return assignments, result.Error
+}
+
+// FindGrantsByRoles mengimplementasikan metode FindGrantsByRoles dari RoleRepository.
+func (r *RoleRepositoryImpl) FindGrantsByRoles(roleIDs []uuid.UUID) ([]entities.UserRole, error) {
+	var grants []entities.UserRole
+	query := r.db.Preload("Role").
+		Joins("JOIN roles ON roles.id = user_roles.role_id AND roles.deleted_at IS NULL").
+		Where(unexpiredUserRoleCondition)
+	if len(roleIDs) > 0 {
+		query = query.Where("user_roles.role_id IN ?", roleIDs)
+	}
+	result := query.Order("user_roles.user_id ASC").Find(&grants)
+	return grants, result.Error
+}
+
+// FindAllAssignments mengimplementasikan metode FindAllAssignments dari RoleRepository.
+func (r *RoleRepositoryImpl) FindAllAssignments() ([]repositories.RoleAssignment, error) {
+	var assignments []repositories.RoleAssignment
+	result := r.db.Raw(fmt.Sprintf(roleAssignmentsQuery, "TRUE", "TRUE", "TRUE")).Scan(&assignments)
+	return assignments, result.Error
+}
+
+// UpdateMembers mengimplementasikan metode UpdateMembers dari RoleRepository.
+func (r *RoleRepositoryImpl) UpdateMembers(roleID uuid.UUID, add, remove []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if len(remove) > 0 {
+			if err := tx.Where("role_id = ? AND user_id IN ?", roleID, remove).Delete(&entities.UserRole{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(add) == 0 {
+			return nil
+		}
+
+		rows := make([]entities.UserRole, 0, len(add))
+		for _, userID := range add {
+			rows = append(rows, entities.UserRole{UserID: userID, RoleID: roleID})
+		}
+		return tx.Clauses(clause.OnConflict{DoNothing: true}).Create(&rows).Error
+	})
+}
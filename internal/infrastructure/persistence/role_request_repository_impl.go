@@ -0,0 +1,95 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// RoleRequestRepositoryImpl adalah implementasi repositories.RoleRequestRepository dengan GORM.
+type RoleRequestRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewRoleRequestRepository membuat instance baru dari RoleRequestRepositoryImpl.
+func NewRoleRequestRepository(db *gorm.DB) repositories.RoleRequestRepository {
+	return &RoleRequestRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari RoleRequestRepository.
+func (r *RoleRequestRepositoryImpl) Create(request *entities.RoleRequest) error {
+	return r.db.Omit(clause.Associations).Create(request).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari RoleRequestRepository.
+func (r *RoleRequestRepositoryImpl) FindByID(id uuid.UUID) (*entities.RoleRequest, error) {
+	var request entities.RoleRequest
+	result := r.db.Preload("User").Preload("Role").First(&request, "id = ?", id)
+	return &request, result.Error
+}
+
+// FindByUser mengimplementasikan metode FindByUser dari RoleRequestRepository.
+func (r *RoleRequestRepositoryImpl) FindByUser(userID uuid.UUID) ([]entities.RoleRequest, error) {
+	var requests []entities.RoleRequest
+	result := r.db.Preload("Role").Where("user_id = ?", userID).Order("created_at DESC").Find(&requests)
+	return requests, result.Error
+}
+
+// FindPending mengimplementasikan metode FindPending dari RoleRequestRepository.
+func (r *RoleRequestRepositoryImpl) FindPending() ([]entities.RoleRequest, error) {
+	var requests []entities.RoleRequest
+	result := r.db.Preload("User").Preload("Role").
+		Where("status = ?", entities.RoleRequestPending).
+		Order("created_at ASC").
+		Find(&requests)
+	return requests, result.Error
+}
+
+// FindPendingByUserAndRole mengimplementasikan metode FindPendingByUserAndRole dari RoleRequestRepository.
+func (r *RoleRequestRepositoryImpl) FindPendingByUserAndRole(userID, roleID uuid.UUID) (*entities.RoleRequest, error) {
+	var request entities.RoleRequest
+	result := r.db.Where("user_id = ? AND role_id = ? AND status = ?", userID, roleID, entities.RoleRequestPending).First(&request)
+	return &request, result.Error
+}
+
+// UpdateDecision mengimplementasikan metode UpdateDecision dari RoleRequestRepository.
+func (r *RoleRequestRepositoryImpl) UpdateDecision(request *entities.RoleRequest) error {
+	result := r.db.Model(&entities.RoleRequest{}).
+		Where("id = ? AND status = ?", request.ID, entities.RoleRequestPending).
+		Updates(map[string]interface{}{
+			"status":                request.Status,
+			"reviewed_by":           request.ReviewedBy,
+			"reviewed_at":           request.ReviewedAt,
+			"grant_expires_at":      request.GrantExpiresAt,
+			"assignment_request_id": request.AssignmentRequestID,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
+
+// Reopen mengimplementasikan metode Reopen dari RoleRequestRepository.
+func (r *RoleRequestRepositoryImpl) Reopen(request *entities.RoleRequest) error {
+	result := r.db.Model(&entities.RoleRequest{}).
+		Where("id = ? AND status = ?", request.ID, entities.RoleRequestApproved).
+		Updates(map[string]interface{}{
+			"status":           entities.RoleRequestPending,
+			"reviewed_by":      nil,
+			"reviewed_at":      nil,
+			"grant_expires_at": nil,
+		})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
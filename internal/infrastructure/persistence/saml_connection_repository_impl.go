@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// SAMLConnectionRepositoryImpl adalah implementasi repositories.SAMLConnectionRepository dengan GORM.
+type SAMLConnectionRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSAMLConnectionRepository membuat instance baru dari SAMLConnectionRepositoryImpl.
+func NewSAMLConnectionRepository(db *gorm.DB) repositories.SAMLConnectionRepository {
+	return &SAMLConnectionRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari SAMLConnectionRepository.
+func (r *SAMLConnectionRepositoryImpl) Create(connection *entities.SAMLConnection) error {
+	return r.db.Create(connection).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari SAMLConnectionRepository.
+func (r *SAMLConnectionRepositoryImpl) FindByID(id uuid.UUID) (*entities.SAMLConnection, error) {
+	var connection entities.SAMLConnection
+	result := r.db.Where("id = ?", id).First(&connection)
+	return &connection, result.Error
+}
+
+// FindByName mengimplementasikan metode FindByName dari SAMLConnectionRepository.
+func (r *SAMLConnectionRepositoryImpl) FindByName(name string) (*entities.SAMLConnection, error) {
+	var connection entities.SAMLConnection
+	result := r.db.Where("name = ?", name).First(&connection)
+	return &connection, result.Error
+}
+
+// FindAll mengimplementasikan metode FindAll dari SAMLConnectionRepository.
+func (r *SAMLConnectionRepositoryImpl) FindAll() ([]entities.SAMLConnection, error) {
+	var connections []entities.SAMLConnection
+	result := r.db.Order("name ASC").Find(&connections)
+	return connections, result.Error
+}
+
+// Update mengimplementasikan metode Update dari SAMLConnectionRepository.
+func (r *SAMLConnectionRepositoryImpl) Update(connection *entities.SAMLConnection) error {
+	return r.db.Save(connection).Error
+}
+
+// Delete mengimplementasikan metode Delete dari SAMLConnectionRepository.
+func (r *SAMLConnectionRepositoryImpl) Delete(id uuid.UUID) error {
+	result := r.db.Where("id = ?", id).Delete(&entities.SAMLConnection{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package persistence
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// SessionRepositoryImpl adalah implementasi repositories.SessionRepository.
+// Database adalah sumber kebenaran; Redis menyimpan salinan sesi aktif agar pemeriksaan
+// di setiap permintaan tidak menyentuh database. Sesi yang dicabut dihapus dari Redis.
+type SessionRepositoryImpl struct {
+	db     *gorm.DB
+	client *redis.Client
+	prefix string
+}
+
+// NewSessionRepository membuat instance baru dari SessionRepositoryImpl.
+func NewSessionRepository(db *gorm.DB, client *redis.Client) repositories.SessionRepository {
+	return &SessionRepositoryImpl{db: db, client: client, prefix: "session:"}
+}
+
+// Create mengimplementasikan metode Create dari SessionRepository.
+func (r *SessionRepositoryImpl) Create(ctx context.Context, session *entities.Session) error {
+	if err := r.db.WithContext(ctx).Create(session).Error; err != nil {
+		return err
+	}
+	r.cache(ctx, session)
+	return nil
+}
+
+// FindByID mengimplementasikan metode FindByID dari SessionRepository.
+// Redis diperiksa lebih dulu; jika tidak ada, sesi dibaca dari database lalu disimpan ulang di Redis bila masih aktif.
+func (r *SessionRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*entities.Session, error) {
+	data, err := r.client.Get(ctx, r.prefix+id.String()).Bytes()
+	if err == nil {
+		var session entities.Session
+		if err := json.Unmarshal(data, &session); err == nil {
+			return &session, nil
+		}
+	} else if !errors.Is(err, redis.Nil) {
+		log.Printf("Gagal membaca sesi %s dari Redis: %v", id, err)
+	}
+
+	var session entities.Session
+	if err := r.db.WithContext(ctx).First(&session, "id = ?", id).Error; err != nil {
+		return nil, err
+	}
+	r.cache(ctx, &session)
+	return &session, nil
+}
+
+// FindActiveByUser mengimplementasikan metode FindActiveByUser dari SessionRepository.
+func (r *SessionRepositoryImpl) FindActiveByUser(ctx context.Context, userID uuid.UUID, now time.Time) ([]entities.Session, error) {
+	var sessions []entities.Session
+	result := r.db.WithContext(ctx).
+		Where("user_id = ? AND revoked_at IS NULL AND expires_at > ?", userID, now).
+		Order("last_seen_at DESC").
+		Find(&sessions)
+	return sessions, result.Error
+}
+
+// Touch mengimplementasikan metode Touch dari SessionRepository.
+// Salinan Redis hanya ditimpa jika masih ada (SET XX), sehingga Touch yang berjalan bersamaan dengan
+// Revoke tidak pernah menulis ulang sesi yang sudah dihapus dari Redis.
+func (r *SessionRepositoryImpl) Touch(ctx context.Context, session *entities.Session, seenAt time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entities.Session{}).
+		Where("id = ? AND revoked_at IS NULL", session.ID).
+		Update("last_seen_at", seenAt)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	session.LastSeenAt = seenAt
+	data, err := json.Marshal(session)
+	if err != nil {
+		log.Printf("Gagal menyandikan sesi %s: %v", session.ID, err)
+		return nil
+	}
+	if err := r.client.SetXX(ctx, r.prefix+session.ID.String(), data, redis.KeepTTL).Err(); err != nil {
+		log.Printf("Gagal menyimpan sesi %s ke Redis: %v", session.ID, err)
+	}
+	return nil
+}
+
+// Revoke mengimplementasikan metode Revoke dari SessionRepository.
+// Database diperbarui sebelum salinan Redis dihapus agar pembacaan berikutnya tidak menyimpan ulang sesi yang dicabut.
+func (r *SessionRepositoryImpl) Revoke(ctx context.Context, id uuid.UUID, now time.Time) error {
+	result := r.db.WithContext(ctx).Model(&entities.Session{}).
+		Where("id = ? AND revoked_at IS NULL", id).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return r.client.Del(ctx, r.prefix+id.String()).Err()
+}
+
+// RevokeAllByUser mengimplementasikan metode RevokeAllByUser dari SessionRepository.
+func (r *SessionRepositoryImpl) RevokeAllByUser(ctx context.Context, userID uuid.UUID, now time.Time) (int64, error) {
+	var ids []uuid.UUID
+	if err := r.db.WithContext(ctx).Model(&entities.Session{}).
+		Where("user_id = ? AND revoked_at IS NULL", userID).
+		Pluck("id", &ids).Error; err != nil {
+		return 0, err
+	}
+	if len(ids) == 0 {
+		return 0, nil
+	}
+
+	result := r.db.WithContext(ctx).Model(&entities.Session{}).
+		Where("id IN ? AND revoked_at IS NULL", ids).
+		Update("revoked_at", now)
+	if result.Error != nil {
+		return 0, result.Error
+	}
+
+	keys := make([]string, 0, len(ids))
+	for _, id := range ids {
+		keys = append(keys, r.prefix+id.String())
+	}
+	return result.RowsAffected, r.client.Del(ctx, keys...).Err()
+}
+
+// cache menyimpan salinan sesi aktif di Redis sampai sesi kedaluwarsa.
+// Kegagalan hanya dicatat karena database tetap menjadi sumber kebenaran.
+func (r *SessionRepositoryImpl) cache(ctx context.Context, session *entities.Session) {
+	ttl := time.Until(session.ExpiresAt)
+	if !session.IsActive(time.Now()) || ttl <= 0 {
+		return
+	}
+
+	data, err := json.Marshal(session)
+	if err != nil {
+		log.Printf("Gagal menyandikan sesi %s: %v", session.ID, err)
+		return
+	}
+	if err := r.client.Set(ctx, r.prefix+session.ID.String(), data, ttl).Err(); err != nil {
+		log.Printf("Gagal menyimpan sesi %s ke Redis: %v", session.ID, err)
+	}
+}
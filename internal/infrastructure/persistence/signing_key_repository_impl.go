@@ -0,0 +1,48 @@
+package persistence
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// SigningKeyRepositoryImpl adalah implementasi repositories.SigningKeyRepository dengan GORM.
+type SigningKeyRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSigningKeyRepository membuat instance baru dari SigningKeyRepositoryImpl.
+func NewSigningKeyRepository(db *gorm.DB) repositories.SigningKeyRepository {
+	return &SigningKeyRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari SigningKeyRepository.
+func (r *SigningKeyRepositoryImpl) Create(key *entities.SigningKey) error {
+	return r.db.Create(key).Error
+}
+
+// FindValid mengimplementasikan metode FindValid dari SigningKeyRepository.
+func (r *SigningKeyRepositoryImpl) FindValid(now time.Time) ([]entities.SigningKey, error) {
+	var keys []entities.SigningKey
+	result := r.db.Where("expires_at IS NULL OR expires_at > ?", now).
+		Order("signing_from DESC").
+		Find(&keys)
+	return keys, result.Error
+}
+
+// Retire mengimplementasikan metode Retire dari SigningKeyRepository.
+func (r *SigningKeyRepositoryImpl) Retire(id uuid.UUID, expiresAt time.Time) error {
+	return r.db.Model(&entities.SigningKey{}).
+		Where("id = ? AND expires_at IS NULL", id).
+		Update("expires_at", expiresAt).Error
+}
+
+// DeleteExpired mengimplementasikan metode DeleteExpired dari SigningKeyRepository.
+func (r *SigningKeyRepositoryImpl) DeleteExpired(now time.Time) (int64, error) {
+	result := r.db.Where("expires_at IS NOT NULL AND expires_at <= ?", now).Delete(&entities.SigningKey{})
+	return result.RowsAffected, result.Error
+}
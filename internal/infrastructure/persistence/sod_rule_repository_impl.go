@@ -0,0 +1,99 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// SoDRuleRepositoryImpl adalah implementasi repositories.SoDRuleRepository dengan GORM.
+type SoDRuleRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewSoDRuleRepository membuat instance baru dari SoDRuleRepositoryImpl.
+func NewSoDRuleRepository(db *gorm.DB) repositories.SoDRuleRepository {
+	return &SoDRuleRepositoryImpl{db: db}
+}
+
+// sodRuleRole adalah baris tabel join sod_rule_roles.
+type sodRuleRole struct {
+	RuleID uuid.UUID `gorm:"type:uuid;primaryKey"`
+	RoleID uuid.UUID `gorm:"type:uuid;primaryKey"`
+}
+
+func (sodRuleRole) TableName() string {
+	return "sod_rule_roles"
+}
+
+// Create mengimplementasikan metode Create dari SoDRuleRepository.
+func (r *SoDRuleRepositoryImpl) Create(rule *entities.SoDRule) error {
+	return r.db.Omit(clause.Associations).Create(rule).Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari SoDRuleRepository.
+func (r *SoDRuleRepositoryImpl) FindByID(id uuid.UUID) (*entities.SoDRule, error) {
+	var rule entities.SoDRule
+	result := r.withRoles().First(&rule, "id = ?", id)
+	return &rule, result.Error
+}
+
+// FindByName mengimplementasikan metode FindByName dari SoDRuleRepository.
+func (r *SoDRuleRepositoryImpl) FindByName(name string) (*entities.SoDRule, error) {
+	var rule entities.SoDRule
+	result := r.db.First(&rule, "name = ?", name)
+	return &rule, result.Error
+}
+
+// FindAll mengimplementasikan metode FindAll dari SoDRuleRepository.
+func (r *SoDRuleRepositoryImpl) FindAll() ([]entities.SoDRule, error) {
+	var rules []entities.SoDRule
+	result := r.withRoles().Order("name ASC").Find(&rules)
+	return rules, result.Error
+}
+
+// Update mengimplementasikan metode Update dari SoDRuleRepository.
+func (r *SoDRuleRepositoryImpl) Update(rule *entities.SoDRule) error {
+	return r.db.Model(rule).Select("name", "description", "updated_at").Updates(rule).Error
+}
+
+// ReplaceRoles mengimplementasikan metode ReplaceRoles dari SoDRuleRepository.
+func (r *SoDRuleRepositoryImpl) ReplaceRoles(ruleID uuid.UUID, roleIDs []uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("rule_id = ?", ruleID).Delete(&sodRuleRole{}).Error; err != nil {
+			return err
+		}
+		if len(roleIDs) == 0 {
+			return nil
+		}
+
+		rows := make([]sodRuleRole, 0, len(roleIDs))
+		for _, roleID := range roleIDs {
+			rows = append(rows, sodRuleRole{RuleID: ruleID, RoleID: roleID})
+		}
+		return tx.Create(&rows).Error
+	})
+}
+
+// Delete mengimplementasikan metode Delete dari SoDRuleRepository.
+func (r *SoDRuleRepositoryImpl) Delete(id uuid.UUID) error {
+	return r.db.Transaction(func(tx *gorm.DB) error {
+		result := tx.Delete(&entities.SoDRule{}, "id = ?", id)
+		if result.Error != nil {
+			return result.Error
+		}
+		if result.RowsAffected == 0 {
+			return gorm.ErrRecordNotFound
+		}
+		return tx.Where("rule_id = ?", id).Delete(&sodRuleRole{}).Error
+	})
+}
+
+func (r *SoDRuleRepositoryImpl) withRoles() *gorm.DB {
+	return r.db.Preload("Roles", func(db *gorm.DB) *gorm.DB {
+		return db.Order("name ASC")
+	})
+}
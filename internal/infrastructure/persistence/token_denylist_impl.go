@@ -0,0 +1,37 @@
+package persistence
+
+import (
+	"context"
+	"time"
+
+	"github.com/redis/go-redis/v9"
+
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// TokenDenylistImpl adalah implementasi repositories.TokenDenylist di atas Redis.
+// Setiap entri kedaluwarsa bersamaan dengan tokennya sehingga daftar tidak tumbuh tanpa batas.
+type TokenDenylistImpl struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewTokenDenylist membuat instance baru dari TokenDenylistImpl.
+func NewTokenDenylist(client *redis.Client) repositories.TokenDenylist {
+	return &TokenDenylistImpl{client: client, prefix: "revoked_token:"}
+}
+
+// Add mengimplementasikan metode Add dari TokenDenylist.
+func (d *TokenDenylistImpl) Add(ctx context.Context, tokenID string, until time.Time) error {
+	ttl := time.Until(until)
+	if ttl <= 0 {
+		return nil // Token sudah kedaluwarsa, tidak perlu dicatat
+	}
+	return d.client.Set(ctx, d.prefix+tokenID, 1, ttl).Err()
+}
+
+// Contains mengimplementasikan metode Contains dari TokenDenylist.
+func (d *TokenDenylistImpl) Contains(ctx context.Context, tokenID string) (bool, error) {
+	count, err := d.client.Exists(ctx, d.prefix+tokenID).Result()
+	return count > 0, err
+}
@@ -1,7 +1,9 @@
 package persistence
 
 import (
+	"github.com/google/uuid"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 
 	"fiber-usermanagement/internal/domain/entities"
 	"fiber-usermanagement/internal/domain/repositories"
@@ -10,7 +12,8 @@ import (
 // UserRepositoryImpl adalah implementasi konkret dari interface repositories.UserRepository.
 // Ini menggunakan GORM untuk berinteraksi dengan database.
 type UserRepositoryImpl struct {
-	db *gorm.DB // Kumpulan koneksi database GORM
+	db             *gorm.DB  // Kumpulan koneksi database GORM
+	organizationID uuid.UUID // Tenant yang membatasi semua query; uuid.Nil berarti tanpa batasan
 }
 
 // NewUserRepository membuat instance baru dari UserRepositoryImpl.
@@ -19,42 +22,167 @@ func NewUserRepository(db *gorm.DB) repositories.UserRepository {
 	return &UserRepositoryImpl{db: db}
 }
 
+// InOrganization mengimplementasikan metode InOrganization dari UserRepository.
+func (r *UserRepositoryImpl) InOrganization(organizationID uuid.UUID) repositories.UserRepository {
+	return &UserRepositoryImpl{db: r.db, organizationID: organizationID}
+}
+
+// query mengembalikan query dasar yang sudah dibatasi ke anggota tenant, jika ada.
+// Semua metode baca harus memakai query agar batasan tenant tidak bisa terlewat.
+func (r *UserRepositoryImpl) query() *gorm.DB {
+	if r.organizationID == uuid.Nil {
+		return r.db
+	}
+	members := r.db.Model(&entities.OrganizationMember{}).Select("user_id").Where("organization_id = ?", r.organizationID)
+	return r.db.Where("users.id IN (?)", members)
+}
+
 // Create mengimplementasikan metode Create dari UserRepository.
 // Ini membuat record pengguna baru di database.
 func (r *UserRepositoryImpl) Create(user *entities.User) (*entities.User, error) {
-	result := r.db.Create(user) // GORM akan mengisi ID setelah pembuatan berhasil
-	return user, result.Error
+	if r.organizationID == uuid.Nil {
+		result := r.db.Create(user) // GORM akan mengisi ID setelah pembuatan berhasil
+		return user, result.Error
+	}
+
+	err := r.db.Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(user).Error; err != nil {
+			return err
+		}
+		return tx.Create(&entities.OrganizationMember{OrganizationID: r.organizationID, UserID: user.ID}).Error
+	})
+	return user, err
 }
 
 // FindByID mengimplementasikan metode FindByID dari UserRepository.
 // Ini mencari record pengguna berdasarkan ID.
-func (r *UserRepositoryImpl) FindByID(id uint) (*entities.User, error) {
+func (r *UserRepositoryImpl) FindByID(id uuid.UUID) (*entities.User, error) {
+	var user entities.User
+	result := r.query().Preload("Roles").First(&user, "users.id = ?", id) // Mencari record pertama yang cocok dengan ID
+	if result.Error != nil {
+		return &user, result.Error
+	}
+	return &user, r.withActiveRoles(&user)
+}
+
+// FindByUsernameOrEmail mengimplementasikan metode FindByUsernameOrEmail dari UserRepository.
+// Ini dipakai saat login, di mana pengguna boleh memasukkan username atau email.
+func (r *UserRepositoryImpl) FindByUsernameOrEmail(identifier string) (*entities.User, error) {
+	var user entities.User
+	result := r.query().Preload("Roles").Where("username = ? OR email = ?", identifier, identifier).First(&user)
+	if result.Error != nil {
+		return &user, result.Error
+	}
+	return &user, r.withActiveRoles(&user)
+}
+
+// FindByEmail mengimplementasikan metode FindByEmail dari UserRepository.
+// Ini dipakai untuk menghubungkan identitas eksternal ke akun yang sudah ada.
+func (r *UserRepositoryImpl) FindByEmail(email string) (*entities.User, error) {
 	var user entities.User
-	result := r.db.First(&user, id) // Mencari record pertama yang cocok dengan ID
-	return &user, result.Error
+	result := r.query().Preload("Roles").Where("LOWER(email) = LOWER(?)", email).First(&user)
+	if result.Error != nil {
+		return &user, result.Error
+	}
+	return &user, r.withActiveRoles(&user)
 }
 
 // FindAll mengimplementasikan metode FindAll dari UserRepository.
 // Ini mengembalikan semua record pengguna dari database.
 func (r *UserRepositoryImpl) FindAll() ([]entities.User, error) {
 	var users []entities.User
-	result := r.db.Find(&users) // Mengambil semua record
+	result := r.query().Find(&users) // Mengambil semua record
 	return users, result.Error
 }
 
+// FindAllWithRoles mengimplementasikan metode FindAllWithRoles dari UserRepository.
+// Ini dipakai saat Role setiap pengguna ikut ditampilkan, misalnya pada daftar pengguna SCIM.
+func (r *UserRepositoryImpl) FindAllWithRoles() ([]entities.User, error) {
+	var users []entities.User
+	result := r.query().Preload("Roles").Order("username ASC").Find(&users)
+	if result.Error != nil {
+		return users, result.Error
+	}
+
+	refs := make([]*entities.User, 0, len(users))
+	for n := range users {
+		refs = append(refs, &users[n])
+	}
+	return users, r.withActiveRoles(refs...)
+}
+
+// withActiveRoles membuang Role yang penetapannya belum mulai atau sudah berakhir dari hasil Preload("Roles").
+// Preload many2many tidak bisa menyaring kolom tabel join, sehingga penyaringan dilakukan setelahnya.
+func (r *UserRepositoryImpl) withActiveRoles(users ...*entities.User) error {
+	ids := make([]uuid.UUID, 0, len(users))
+	for _, user := range users {
+		if len(user.Roles) > 0 {
+			ids = append(ids, user.ID)
+		}
+	}
+	if len(ids) == 0 {
+		return nil
+	}
+
+	var inactive []entities.UserRole
+	if err := r.db.Where("user_id IN ? AND NOT ("+activeUserRoleCondition+")", ids).Find(&inactive).Error; err != nil {
+		return err
+	}
+	if len(inactive) == 0 {
+		return nil
+	}
+
+	skip := make(map[[2]uuid.UUID]bool, len(inactive))
+	for _, grant := range inactive {
+		skip[[2]uuid.UUID{grant.UserID, grant.RoleID}] = true
+	}
+	for _, user := range users {
+		roles := user.Roles[:0]
+		for _, role := range user.Roles {
+			if !skip[[2]uuid.UUID{user.ID, role.ID}] {
+				roles = append(roles, role)
+			}
+		}
+		user.Roles = roles
+	}
+	return nil
+}
+
 // Update mengimplementasikan metode Update dari UserRepository.
 // Ini memperbarui record pengguna yang sudah ada di database.
 func (r *UserRepositoryImpl) Update(user *entities.User) (*entities.User, error) {
+	// Pengguna di luar tenant tidak boleh tersentuh, termasuk lewat upsert Save di bawah
+	if r.organizationID != uuid.Nil {
+		if err := r.query().Select("users.id").First(&entities.User{}, "users.id = ?", user.ID).Error; err != nil {
+			return user, err
+		}
+	}
+
 	// `Save` akan melakukan operasi update jika record dengan ID tersebut sudah ada,
 	// atau insert jika belum ada (upsert). Pastikan `user.ID` diset.
-	result := r.db.Save(user)
+	// Relasi tidak ikut disimpan: Roles hasil FindByID bisa sudah usang, dan upsert user_roles akan
+	// menghidupkan kembali penetapan yang baru dicabut atau kedaluwarsa tanpa expires_at.
+	// Role diubah lewat RoleRepository.
+	result := r.db.Omit(clause.Associations).Save(user)
 	return user, result.Error
 }
 
 // Delete mengimplementasikan metode Delete dari UserRepository.
 // Ini menghapus record pengguna berdasarkan ID.
-func (r *UserRepositoryImpl) Delete(id uint) error {
+func (r *UserRepositoryImpl) Delete(id uuid.UUID) error {
+	if r.organizationID != uuid.Nil {
+		return NewOrganizationMemberRepository(r.db).Delete(r.organizationID, id)
+	}
+
 	// Menghapus record User berdasarkan ID. Menggunakan &entities.User{} sebagai model.
-	result := r.db.Delete(&entities.User{}, id)
+	result := r.db.Delete(&entities.User{}, "id = ?", id)
 	return result.Error
 }
+
+// FindOrganizationIDs mengimplementasikan metode FindOrganizationIDs dari UserRepository.
+// Sengaja tidak memakai query agar keanggotaan di organisasi lain ikut terbaca.
+func (r *UserRepositoryImpl) FindOrganizationIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	var ids []uuid.UUID
+	result := r.db.Model(&entities.OrganizationMember{}).Where("user_id = ?", userID).Pluck("organization_id", &ids)
+	return ids, result.Error
+}
@@ -0,0 +1,70 @@
+package persistence
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+	"gorm.io/driver/postgres"
+	"gorm.io/gorm"
+	"gorm.io/gorm/logger"
+)
+
+// newDryRunDB membuat koneksi GORM ke PostgreSQL dalam mode DryRun yang tidak pernah menghubungi
+// database, dan mengembalikan fungsi untuk membaca semua SQL yang dibuat sejauh ini.
+func newDryRunDB(t *testing.T) (*gorm.DB, func() []string) {
+	t.Helper()
+	db, err := gorm.Open(postgres.New(postgres.Config{DSN: "host=127.0.0.1 dbname=test"}), &gorm.Config{
+		DryRun:                 true,
+		DisableAutomaticPing:   true,
+		SkipDefaultTransaction: true,
+		Logger:                 logger.Discard,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var statements []string
+	record := func(tx *gorm.DB) { statements = append(statements, tx.Statement.SQL.String()) }
+	if err := db.Callback().Create().After("gorm:create").Register("test:record_create", record); err != nil {
+		t.Fatal(err)
+	}
+	if err := db.Callback().Update().After("gorm:update").Register("test:record_update", record); err != nil {
+		t.Fatal(err)
+	}
+	return db, func() []string { return statements }
+}
+
+func TestUserRepositoryUpdateDoesNotRestoreRoleGrants(t *testing.T) {
+	db, statements := newDryRunDB(t)
+	repo := NewUserRepository(db)
+
+	// Pengguna dimuat saat login bersama Role-nya. Sebelum LastLoginAt disimpan, penetapan role itu
+	// kedaluwarsa dan dihapus oleh ExpireDue; Update tidak boleh membuatnya kembali.
+	now := time.Now()
+	user := &entities.User{
+		ID:          uuid.New(),
+		Username:    "alice",
+		Email:       "alice@example.org",
+		IsActive:    true,
+		LastLoginAt: &now,
+		Roles:       []*entities.Role{{ID: uuid.New(), Name: "auditor"}},
+	}
+	if _, err := repo.Update(user); err != nil {
+		t.Fatalf("Update: %v", err)
+	}
+
+	var updatedUser bool
+	for _, statement := range statements() {
+		if strings.Contains(statement, "user_roles") || strings.Contains(statement, `"roles"`) {
+			t.Fatalf("Update menulis relasi role: %s", statement)
+		}
+		updatedUser = updatedUser || strings.HasPrefix(statement, `UPDATE "users"`)
+	}
+	if !updatedUser {
+		t.Fatalf("Update tidak memperbarui users: %v", statements())
+	}
+}
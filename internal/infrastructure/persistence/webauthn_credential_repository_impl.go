@@ -0,0 +1,62 @@
+package persistence
+
+import (
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+)
+
+// WebAuthnCredentialRepositoryImpl adalah implementasi repositories.WebAuthnCredentialRepository dengan GORM.
+type WebAuthnCredentialRepositoryImpl struct {
+	db *gorm.DB
+}
+
+// NewWebAuthnCredentialRepository membuat instance baru dari WebAuthnCredentialRepositoryImpl.
+func NewWebAuthnCredentialRepository(db *gorm.DB) repositories.WebAuthnCredentialRepository {
+	return &WebAuthnCredentialRepositoryImpl{db: db}
+}
+
+// Create mengimplementasikan metode Create dari WebAuthnCredentialRepository.
+func (r *WebAuthnCredentialRepositoryImpl) Create(credential *entities.WebAuthnCredential) error {
+	return r.db.Create(credential).Error
+}
+
+// FindByUser mengimplementasikan metode FindByUser dari WebAuthnCredentialRepository.
+func (r *WebAuthnCredentialRepositoryImpl) FindByUser(userID uuid.UUID) ([]entities.WebAuthnCredential, error) {
+	var credentials []entities.WebAuthnCredential
+	result := r.db.Where("user_id = ?", userID).Order("created_at DESC").Find(&credentials)
+	return credentials, result.Error
+}
+
+// FindByID mengimplementasikan metode FindByID dari WebAuthnCredentialRepository.
+func (r *WebAuthnCredentialRepositoryImpl) FindByID(userID, id uuid.UUID) (*entities.WebAuthnCredential, error) {
+	var credential entities.WebAuthnCredential
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).First(&credential)
+	return &credential, result.Error
+}
+
+// CountByUser mengimplementasikan metode CountByUser dari WebAuthnCredentialRepository.
+func (r *WebAuthnCredentialRepositoryImpl) CountByUser(userID uuid.UUID) (int64, error) {
+	var count int64
+	result := r.db.Model(&entities.WebAuthnCredential{}).Where("user_id = ?", userID).Count(&count)
+	return count, result.Error
+}
+
+// Update mengimplementasikan metode Update dari WebAuthnCredentialRepository.
+func (r *WebAuthnCredentialRepositoryImpl) Update(credential *entities.WebAuthnCredential) error {
+	return r.db.Save(credential).Error
+}
+
+// Delete mengimplementasikan metode Delete dari WebAuthnCredentialRepository.
+func (r *WebAuthnCredentialRepositoryImpl) Delete(userID, id uuid.UUID) error {
+	result := r.db.Where("id = ? AND user_id = ?", id, userID).Delete(&entities.WebAuthnCredential{})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+	return nil
+}
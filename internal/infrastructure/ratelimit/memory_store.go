@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// MemoryStore adalah Store dalam memori untuk mode satu instance.
+// Penghitung tidak dibagikan antar proses, sehingga jangan dipakai di belakang load balancer.
+type MemoryStore struct {
+	mu        sync.Mutex
+	windows   map[string][]time.Time
+	lastSweep time.Time
+	now       func() time.Time
+}
+
+// NewMemoryStore membuat instance baru dari MemoryStore.
+func NewMemoryStore() Store {
+	return &MemoryStore{
+		windows: make(map[string][]time.Time),
+		now:     time.Now,
+	}
+}
+
+// Allow mengimplementasikan metode Allow dari Store.
+func (s *MemoryStore) Allow(_ context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	now := s.now()
+	s.sweep(now, window)
+
+	hits := prune(s.windows[key], now.Add(-window))
+	allowed := len(hits) < limit
+	if allowed {
+		hits = append(hits, now)
+	}
+	s.windows[key] = hits
+
+	resetIn := window
+	if len(hits) > 0 {
+		resetIn = hits[0].Add(window).Sub(now)
+	}
+
+	return &Result{
+		Allowed:   allowed,
+		Limit:     limit,
+		Remaining: max(limit-len(hits), 0),
+		ResetIn:   resetIn,
+	}, nil
+}
+
+// sweep membuang key yang sudah tidak punya hit aktif agar map tidak tumbuh tanpa batas.
+func (s *MemoryStore) sweep(now time.Time, window time.Duration) {
+	if now.Sub(s.lastSweep) < window {
+		return
+	}
+	s.lastSweep = now
+
+	for key, hits := range s.windows {
+		if len(hits) == 0 || !hits[len(hits)-1].After(now.Add(-window)) {
+			delete(s.windows, key)
+		}
+	}
+}
+
+// prune menghapus hit yang berada di luar window.
+func prune(hits []time.Time, cutoff time.Time) []time.Time {
+	i := 0
+	for i < len(hits) && !hits[i].After(cutoff) {
+		i++
+	}
+	return hits[i:]
+}
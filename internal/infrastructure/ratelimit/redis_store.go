@@ -0,0 +1,75 @@
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+)
+
+// slidingWindowScript mengimplementasikan sliding window log dengan sorted set.
+// Waktu diambil dari server Redis agar konsisten antar replika aplikasi.
+// Mengembalikan {allowed, count, reset_ms}.
+var slidingWindowScript = redis.NewScript(`
+local key = KEYS[1]
+local window = tonumber(ARGV[1])
+local limit = tonumber(ARGV[2])
+local member = ARGV[3]
+
+local t = redis.call('TIME')
+local now = tonumber(t[1]) * 1000 + math.floor(tonumber(t[2]) / 1000)
+
+redis.call('ZREMRANGEBYSCORE', key, '-inf', now - window)
+local count = redis.call('ZCARD', key)
+local allowed = 0
+if count < limit then
+	redis.call('ZADD', key, now, member)
+	count = count + 1
+	allowed = 1
+end
+redis.call('PEXPIRE', key, window)
+
+local reset = window
+local oldest = redis.call('ZRANGE', key, 0, 0, 'WITHSCORES')
+if oldest[2] then
+	reset = tonumber(oldest[2]) + window - now
+end
+
+return {allowed, count, reset}
+`)
+
+// RedisStore adalah Store terdistribusi yang menyimpan window di Redis.
+type RedisStore struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisStore membuat instance baru dari RedisStore.
+func NewRedisStore(client *redis.Client) Store {
+	return &RedisStore{client: client, prefix: "ratelimit:"}
+}
+
+// Allow mengimplementasikan metode Allow dari Store.
+func (s *RedisStore) Allow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error) {
+	values, err := slidingWindowScript.Run(ctx, s.client,
+		[]string{s.prefix + key},
+		window.Milliseconds(), limit, uuid.NewString(),
+	).Int64Slice()
+	if err != nil {
+		return nil, fmt.Errorf("rate limit redis script: %w", err)
+	}
+
+	remaining := limit - int(values[1])
+	if remaining < 0 {
+		remaining = 0
+	}
+
+	return &Result{
+		Allowed:   values[0] == 1,
+		Limit:     limit,
+		Remaining: remaining,
+		ResetIn:   time.Duration(values[2]) * time.Millisecond,
+	}, nil
+}
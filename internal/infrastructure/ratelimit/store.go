@@ -0,0 +1,22 @@
+package ratelimit
+
+import (
+	"context"
+	"time"
+)
+
+// Result adalah hasil pemeriksaan satu permintaan terhadap sebuah sliding window.
+type Result struct {
+	Allowed   bool          // Apakah permintaan boleh diteruskan
+	Limit     int           // Jumlah maksimum permintaan dalam satu window
+	Remaining int           // Sisa permintaan yang masih diizinkan dalam window saat ini
+	ResetIn   time.Duration // Waktu sampai slot berikutnya kosong kembali
+}
+
+// Store mendefinisikan kontrak penyimpanan penghitung rate limit.
+// Implementasi Redis dipakai saat aplikasi berjalan di banyak instance,
+// implementasi memori untuk mode satu instance.
+type Store interface {
+	// Allow mencatat satu permintaan untuk key dan mengembalikan apakah masih dalam batas.
+	Allow(ctx context.Context, key string, limit int, window time.Duration) (*Result, error)
+}
@@ -0,0 +1,31 @@
+package security
+
+import (
+	"golang.org/x/crypto/bcrypt"
+
+	"fiber-usermanagement/internal/domain/services"
+)
+
+// BcryptHasher adalah implementasi services.PasswordHasher menggunakan bcrypt.
+type BcryptHasher struct {
+	cost int
+}
+
+// NewBcryptHasher membuat instance baru dari BcryptHasher dengan cost default bcrypt.
+func NewBcryptHasher() services.PasswordHasher {
+	return &BcryptHasher{cost: bcrypt.DefaultCost}
+}
+
+// Hash mengimplementasikan metode Hash dari PasswordHasher.
+func (h *BcryptHasher) Hash(password string) (string, error) {
+	hash, err := bcrypt.GenerateFromPassword([]byte(password), h.cost)
+	if err != nil {
+		return "", err
+	}
+	return string(hash), nil
+}
+
+// Compare mengimplementasikan metode Compare dari PasswordHasher.
+func (h *BcryptHasher) Compare(hash, password string) bool {
+	return bcrypt.CompareHashAndPassword([]byte(hash), []byte(password)) == nil
+}
@@ -0,0 +1,255 @@
+package security
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/services"
+)
+
+// tokenUseAccess menandai JWT sebagai token akses; token tantangan memakai nama tujuannya.
+const tokenUseAccess = "access"
+
+// accessTokenClaims adalah representasi JWT dari services.TokenClaims.
+type accessTokenClaims struct {
+	TokenUse    string      `json:"token_use"`
+	SessionID   string      `json:"sid,omitempty"`
+	Username    string      `json:"username,omitempty"`
+	IsSuperuser bool        `json:"is_superuser,omitempty"`
+	ClientID    string      `json:"client_id,omitempty"`
+	Scope       string      `json:"scope,omitempty"`                // Daftar scope dipisah spasi, seperti pada RFC 6749
+	Actor       *actorClaim `json:"act,omitempty"`                  // Pelaku impersonasi, seperti klaim act pada RFC 8693
+	Banner      string      `json:"impersonation_banner,omitempty"` // Teks penanda impersonasi untuk ditampilkan aplikasi
+	jwt.RegisteredClaims
+}
+
+// actorClaim adalah isi klaim act: pihak yang bertindak atas nama subjek token.
+type actorClaim struct {
+	Subject  string `json:"sub"`
+	Username string `json:"username,omitempty"`
+}
+
+// JWTTokenService adalah implementasi services.TokenService dengan JWT yang ditandatangani kunci asimetris
+// dari KeyRing (RS256 atau EdDSA), sehingga pihak lain bisa memverifikasi token lewat JWKS.
+type JWTTokenService struct {
+	keys       *KeyRing
+	issuer     string
+	expiration time.Duration
+}
+
+// NewJWTTokenService membuat instance baru dari JWTTokenService.
+func NewJWTTokenService(keys *KeyRing, issuer string, expiration time.Duration) services.TokenService {
+	return &JWTTokenService{keys: keys, issuer: issuer, expiration: expiration}
+}
+
+// GenerateAccessToken mengimplementasikan metode GenerateAccessToken dari TokenService.
+func (s *JWTTokenService) GenerateAccessToken(user *entities.User, sessionID uuid.UUID) (*services.IssuedToken, error) {
+	return s.signAccessToken(user, accessTokenClaims{SessionID: sessionID.String()}, s.expiration)
+}
+
+// GenerateDelegatedToken mengimplementasikan metode GenerateDelegatedToken dari TokenService.
+func (s *JWTTokenService) GenerateDelegatedToken(user *entities.User, grant services.DelegatedGrant) (*services.IssuedToken, error) {
+	claims := accessTokenClaims{
+		ClientID: grant.ClientID,
+		Scope:    strings.Join(grant.Scopes, " "),
+	}
+	if grant.SessionID != uuid.Nil {
+		claims.SessionID = grant.SessionID.String()
+	}
+	return s.signAccessToken(user, claims, grant.TTL)
+}
+
+// GenerateImpersonationToken mengimplementasikan metode GenerateImpersonationToken dari TokenService.
+func (s *JWTTokenService) GenerateImpersonationToken(user *entities.User, grant services.ImpersonationGrant) (*services.IssuedToken, error) {
+	claims := accessTokenClaims{
+		SessionID: grant.SessionID.String(),
+		Actor:     &actorClaim{Subject: grant.Actor.ID.String(), Username: grant.Actor.Username},
+		Banner:    grant.Banner,
+	}
+	return s.signAccessToken(user, claims, grant.TTL)
+}
+
+// signAccessToken melengkapi klaim standar token akses lalu menandatanganinya.
+func (s *JWTTokenService) signAccessToken(user *entities.User, claims accessTokenClaims, ttl time.Duration) (*services.IssuedToken, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims.TokenUse = tokenUseAccess
+	claims.Username = user.Username
+	claims.IsSuperuser = user.IsSuperuser
+	claims.RegisteredClaims = jwt.RegisteredClaims{
+		Issuer:    s.issuer,
+		ID:        uuid.NewString(),
+		Subject:   user.ID.String(),
+		IssuedAt:  jwt.NewNumericDate(now),
+		NotBefore: jwt.NewNumericDate(now),
+		ExpiresAt: jwt.NewNumericDate(expiresAt),
+	}
+
+	token, err := s.sign(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign access token: %w", err)
+	}
+
+	return &services.IssuedToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// GenerateIDToken mengimplementasikan metode GenerateIDToken dari TokenService.
+func (s *JWTTokenService) GenerateIDToken(user *entities.User, req services.IDTokenRequest) (*services.IssuedToken, error) {
+	now := time.Now()
+	expiresAt := now.Add(req.TTL)
+
+	claims := jwt.MapClaims{}
+	for name, value := range req.Claims {
+		claims[name] = value
+	}
+	claims["iss"] = s.issuer
+	claims["sub"] = user.ID.String()
+	claims["aud"] = req.ClientID
+	claims["azp"] = req.ClientID
+	claims["iat"] = now.Unix()
+	claims["exp"] = expiresAt.Unix()
+	if req.Nonce != "" {
+		claims["nonce"] = req.Nonce
+	}
+
+	token, err := s.sign(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign id token: %w", err)
+	}
+
+	return &services.IssuedToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// ParseAccessToken mengimplementasikan metode ParseAccessToken dari TokenService.
+func (s *JWTTokenService) ParseAccessToken(tokenString string) (*services.TokenClaims, error) {
+	claims, err := s.parse(tokenString, tokenUseAccess)
+	if err != nil {
+		return nil, err
+	}
+
+	userID, err := uuid.Parse(claims.Subject)
+	if err != nil {
+		return nil, errors.New("token subject is not a valid user id")
+	}
+
+	// Hanya token client_credentials yang boleh tidak terikat ke sesi
+	sessionID := uuid.Nil
+	if claims.SessionID != "" || claims.ClientID == "" {
+		sessionID, err = uuid.Parse(claims.SessionID)
+		if err != nil {
+			return nil, errors.New("token is not bound to a session")
+		}
+	}
+
+	var scopes []string
+	if claims.ClientID != "" {
+		scopes = strings.Fields(claims.Scope)
+		if scopes == nil {
+			scopes = []string{} // Token OAuth tanpa scope tetap dibatasi, bukan tanpa batas
+		}
+	}
+
+	result := &services.TokenClaims{
+		ID:          claims.ID,
+		UserID:      userID,
+		SessionID:   sessionID,
+		Username:    claims.Username,
+		IsSuperuser: claims.IsSuperuser,
+		IssuedAt:    claims.IssuedAt.Time,
+		ExpiresAt:   claims.ExpiresAt.Time,
+		ClientID:    claims.ClientID,
+		Scopes:      scopes,
+	}
+	if claims.Actor != nil {
+		// Impersonasi hanya diterbitkan sebagai token login, tidak pernah untuk klien OAuth
+		if claims.ClientID != "" {
+			return nil, errors.New("delegated token cannot carry an actor")
+		}
+		if result.ActorID, err = uuid.Parse(claims.Actor.Subject); err != nil {
+			return nil, errors.New("token actor is not a valid user id")
+		}
+		result.ActorUsername = claims.Actor.Username
+		result.Banner = claims.Banner
+	}
+	return result, nil
+}
+
+// GenerateChallengeToken mengimplementasikan metode GenerateChallengeToken dari TokenService.
+func (s *JWTTokenService) GenerateChallengeToken(user *entities.User, purpose string, ttl time.Duration) (*services.IssuedToken, error) {
+	now := time.Now()
+	expiresAt := now.Add(ttl)
+
+	claims := accessTokenClaims{
+		TokenUse: purpose,
+		RegisteredClaims: jwt.RegisteredClaims{
+			Issuer:    s.issuer,
+			ID:        uuid.NewString(),
+			Subject:   user.ID.String(),
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	}
+
+	token, err := s.sign(claims)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign challenge token: %w", err)
+	}
+
+	return &services.IssuedToken{Token: token, ExpiresAt: expiresAt}, nil
+}
+
+// ParseChallengeToken mengimplementasikan metode ParseChallengeToken dari TokenService.
+func (s *JWTTokenService) ParseChallengeToken(tokenString, purpose string) (uuid.UUID, error) {
+	claims, err := s.parse(tokenString, purpose)
+	if err != nil {
+		return uuid.Nil, err
+	}
+	return uuid.Parse(claims.Subject)
+}
+
+// sign menandatangani klaim dengan kunci aktif dan mencantumkan kid-nya di header.
+func (s *JWTTokenService) sign(claims jwt.Claims) (string, error) {
+	key, err := s.keys.signer()
+	if err != nil {
+		return "", err
+	}
+
+	token := jwt.NewWithClaims(key.method, claims)
+	token.Header["kid"] = key.id
+	return token.SignedString(key.private)
+}
+
+// parse memverifikasi tanda tangan, penerbit dan masa berlaku JWT serta memastikan token_use sesuai.
+func (s *JWTTokenService) parse(tokenString, tokenUse string) (*accessTokenClaims, error) {
+	claims := &accessTokenClaims{}
+	_, err := jwt.ParseWithClaims(tokenString, claims, func(token *jwt.Token) (interface{}, error) {
+		kid, _ := token.Header["kid"].(string)
+		key, err := s.keys.verifier(kid)
+		if err != nil {
+			return nil, err
+		}
+		if key.method.Alg() != token.Method.Alg() {
+			return nil, fmt.Errorf("signing key %s does not use %s", kid, token.Method.Alg())
+		}
+		return key.private.Public(), nil
+	},
+		jwt.WithValidMethods([]string{jwt.SigningMethodRS256.Alg(), jwt.SigningMethodEdDSA.Alg()}),
+		jwt.WithIssuer(s.issuer),
+		jwt.WithExpirationRequired(),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.TokenUse != tokenUse {
+		return nil, fmt.Errorf("unexpected token use %q", claims.TokenUse)
+	}
+	return claims, nil
+}
@@ -0,0 +1,273 @@
+package security
+
+import (
+	"crypto"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+)
+
+const (
+	// keyRingRefreshInterval membatasi seberapa lama daftar kunci di-cache sebelum dibaca ulang,
+	// agar rotasi oleh worker terlihat di semua instance.
+	keyRingRefreshInterval = time.Minute
+	// rsaKeyBits adalah ukuran kunci RSA baru.
+	rsaKeyBits = 2048
+)
+
+// KeyRotationPolicy adalah aturan rotasi kunci penandatanganan.
+type KeyRotationPolicy struct {
+	Algorithm        string        // entities.SigningAlgorithmRS256 atau entities.SigningAlgorithmEdDSA
+	RotationInterval time.Duration // Umur kunci sebelum digantikan
+	Prepublish       time.Duration // Kunci baru dipublikasikan di JWKS selama ini sebelum dipakai menandatangani
+	Retention        time.Duration // Kunci lama tetap diterima selama ini setelah digantikan; minimal umur token terpanjang
+}
+
+// signingKey adalah kunci yang sudah diurai dan siap dipakai.
+type signingKey struct {
+	id          string
+	method      jwt.SigningMethod
+	private     crypto.Signer
+	signingFrom time.Time
+}
+
+// KeyRing adalah implementasi services.KeyManager yang menyimpan kunci di database
+// dan menyimpan salinan kunci yang sudah diurai di memori.
+type KeyRing struct {
+	repo   repositories.SigningKeyRepository
+	policy KeyRotationPolicy
+
+	mu       sync.RWMutex
+	keys     []signingKey // SigningFrom terbaru lebih dulu
+	loadedAt time.Time
+}
+
+// NewKeyRing membuat instance baru dari KeyRing.
+func NewKeyRing(repo repositories.SigningKeyRepository, policy KeyRotationPolicy) (*KeyRing, error) {
+	if signingMethod(policy.Algorithm) == nil {
+		return nil, fmt.Errorf("unsupported signing algorithm %q", policy.Algorithm)
+	}
+	return &KeyRing{repo: repo, policy: policy}, nil
+}
+
+// Algorithm mengimplementasikan metode Algorithm dari KeyManager.
+func (r *KeyRing) Algorithm() string {
+	return r.policy.Algorithm
+}
+
+// PublicKeys mengimplementasikan metode PublicKeys dari KeyManager.
+func (r *KeyRing) PublicKeys() ([]services.JSONWebKey, error) {
+	keys, err := r.current(false)
+	if err != nil {
+		return nil, err
+	}
+
+	jwks := make([]services.JSONWebKey, 0, len(keys))
+	for _, key := range keys {
+		jwk := services.JSONWebKey{KeyID: key.id, Use: "sig", Algorithm: key.method.Alg()}
+		switch public := key.private.Public().(type) {
+		case *rsa.PublicKey:
+			jwk.KeyType = "RSA"
+			jwk.N = base64.RawURLEncoding.EncodeToString(public.N.Bytes())
+			jwk.E = base64.RawURLEncoding.EncodeToString(big.NewInt(int64(public.E)).Bytes())
+		case ed25519.PublicKey:
+			jwk.KeyType = "OKP"
+			jwk.Curve = "Ed25519"
+			jwk.X = base64.RawURLEncoding.EncodeToString(public)
+		default:
+			continue
+		}
+		jwks = append(jwks, jwk)
+	}
+	return jwks, nil
+}
+
+// Rotate mengimplementasikan metode Rotate dari KeyManager.
+// Aman dijalankan berulang kali; jika belum ada kunci sama sekali, kunci pertama langsung aktif.
+func (r *KeyRing) Rotate(now time.Time) error {
+	keys, err := r.repo.FindValid(now)
+	if err != nil {
+		return err
+	}
+
+	switch {
+	case len(keys) == 0:
+		key, err := r.generate(now)
+		if err != nil {
+			return err
+		}
+		keys = []entities.SigningKey{*key}
+	case !now.Add(r.policy.Prepublish).Before(keys[0].SigningFrom.Add(r.policy.RotationInterval)):
+		key, err := r.generate(now.Add(r.policy.Prepublish))
+		if err != nil {
+			return err
+		}
+		keys = append([]entities.SigningKey{*key}, keys...)
+	}
+
+	// Setiap kunci selain yang terbaru sudah punya pengganti; kunci tetap diterima selama Retention
+	// setelah penggantinya mulai menandatangani.
+	for idx := 1; idx < len(keys); idx++ {
+		if keys[idx].ExpiresAt != nil {
+			continue
+		}
+		if err := r.repo.Retire(keys[idx].ID, keys[idx-1].SigningFrom.Add(r.policy.Retention)); err != nil {
+			return err
+		}
+	}
+
+	if _, err := r.repo.DeleteExpired(now); err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	r.loadedAt = time.Time{}
+	r.mu.Unlock()
+	return nil
+}
+
+// Sign mengimplementasikan metode Sign dari KeyManager.
+func (r *KeyRing) Sign(payload []byte) (*services.Signature, error) {
+	key, err := r.signer()
+	if err != nil {
+		return nil, err
+	}
+	signature, err := key.method.Sign(string(payload), key.private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign payload: %w", err)
+	}
+	return &services.Signature{
+		KeyID:     key.id,
+		Algorithm: key.method.Alg(),
+		Value:     base64.RawURLEncoding.EncodeToString(signature),
+	}, nil
+}
+
+// signer mengembalikan kunci yang sedang dipakai menandatangani: kunci terbaru yang SigningFrom-nya sudah lewat.
+func (r *KeyRing) signer() (*signingKey, error) {
+	keys, err := r.current(false)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	for idx := range keys {
+		if !keys[idx].signingFrom.After(now) {
+			return &keys[idx], nil
+		}
+	}
+	return nil, errors.New("no active signing key")
+}
+
+// verifier mencari kunci berdasarkan kid. Jika tidak ditemukan, daftar kunci dibaca ulang sekali
+// karena kunci baru mungkin dibuat oleh instance lain.
+func (r *KeyRing) verifier(kid string) (*signingKey, error) {
+	for _, force := range []bool{false, true} {
+		keys, err := r.current(force)
+		if err != nil {
+			return nil, err
+		}
+		for idx := range keys {
+			if keys[idx].id == kid {
+				return &keys[idx], nil
+			}
+		}
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+// current mengembalikan daftar kunci dari cache, membacanya ulang dari database jika sudah usang atau force.
+func (r *KeyRing) current(force bool) ([]signingKey, error) {
+	r.mu.RLock()
+	keys, loadedAt := r.keys, r.loadedAt
+	r.mu.RUnlock()
+	if !force && time.Since(loadedAt) < keyRingRefreshInterval {
+		return keys, nil
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if !force && time.Since(r.loadedAt) < keyRingRefreshInterval {
+		return r.keys, nil
+	}
+
+	stored, err := r.repo.FindValid(time.Now())
+	if err != nil {
+		return nil, err
+	}
+
+	loaded := make([]signingKey, 0, len(stored))
+	for _, key := range stored {
+		method := signingMethod(key.Algorithm)
+		if method == nil {
+			return nil, fmt.Errorf("signing key %s uses unsupported algorithm %q", key.ID, key.Algorithm)
+		}
+		private, err := x509.ParsePKCS8PrivateKey(key.PrivateKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse signing key %s: %w", key.ID, err)
+		}
+		signer, ok := private.(crypto.Signer)
+		if !ok {
+			return nil, fmt.Errorf("signing key %s is not a signer", key.ID)
+		}
+		loaded = append(loaded, signingKey{id: key.ID.String(), method: method, private: signer, signingFrom: key.SigningFrom})
+	}
+
+	r.keys, r.loadedAt = loaded, time.Now()
+	return loaded, nil
+}
+
+// generate membuat dan menyimpan kunci baru yang mulai menandatangani pada signingFrom.
+func (r *KeyRing) generate(signingFrom time.Time) (*entities.SigningKey, error) {
+	var private crypto.Signer
+	var err error
+	switch r.policy.Algorithm {
+	case entities.SigningAlgorithmEdDSA:
+		_, private, err = ed25519.GenerateKey(rand.Reader)
+	default:
+		private, err = rsa.GenerateKey(rand.Reader, rsaKeyBits)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate signing key: %w", err)
+	}
+
+	der, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode signing key: %w", err)
+	}
+
+	key := &entities.SigningKey{
+		Algorithm:   r.policy.Algorithm,
+		PrivateKey:  der,
+		SigningFrom: signingFrom,
+	}
+	if err := r.repo.Create(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+// signingMethod memetakan nama algoritma ke metode tanda tangan JWT.
+func signingMethod(algorithm string) jwt.SigningMethod {
+	switch algorithm {
+	case entities.SigningAlgorithmRS256:
+		return jwt.SigningMethodRS256
+	case entities.SigningAlgorithmEdDSA:
+		return jwt.SigningMethodEdDSA
+	default:
+		return nil
+	}
+}
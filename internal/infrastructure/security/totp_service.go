@@ -0,0 +1,69 @@
+package security
+
+import (
+	"crypto/subtle"
+	"time"
+
+	"github.com/pquerna/otp"
+	"github.com/pquerna/otp/totp"
+
+	"fiber-usermanagement/internal/domain/services"
+)
+
+const (
+	totpPeriod = 30 // Detik per time step
+	totpSkew   = 1  // Jumlah time step sebelum dan sesudah saat ini yang masih diterima
+)
+
+// TOTPService adalah implementasi services.OTPService menggunakan pquerna/otp.
+type TOTPService struct {
+	issuer string
+}
+
+// NewTOTPService membuat instance baru dari TOTPService.
+// Issuer ditampilkan oleh aplikasi authenticator sebagai nama layanan.
+func NewTOTPService(issuer string) services.OTPService {
+	return &TOTPService{issuer: issuer}
+}
+
+// GenerateTOTP mengimplementasikan metode GenerateTOTP dari OTPService.
+func (s *TOTPService) GenerateTOTP(accountName string) (*services.TOTPEnrollment, error) {
+	key, err := totp.Generate(totp.GenerateOpts{
+		Issuer:      s.issuer,
+		AccountName: accountName,
+		Period:      totpPeriod,
+		Digits:      otp.DigitsSix,
+		Algorithm:   otp.AlgorithmSHA1, // SHA1 adalah satu-satunya algoritma yang didukung luas oleh authenticator
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &services.TOTPEnrollment{
+		Secret:          key.Secret(),
+		ProvisioningURI: key.URL(),
+	}, nil
+}
+
+// ValidateTOTP mengimplementasikan metode ValidateTOTP dari OTPService.
+// Setiap time step dalam toleransi dicoba satu per satu agar step yang cocok bisa dikembalikan.
+func (s *TOTPService) ValidateTOTP(code, secret string) (int64, bool) {
+	opts := totp.ValidateOpts{
+		Period:    totpPeriod,
+		Digits:    otp.DigitsSix,
+		Algorithm: otp.AlgorithmSHA1,
+	}
+
+	now := time.Now().UTC()
+	for offset := -totpSkew; offset <= totpSkew; offset++ {
+		at := now.Add(time.Duration(offset*totpPeriod) * time.Second)
+		expected, err := totp.GenerateCodeCustom(secret, at, opts)
+		if err != nil {
+			return 0, false
+		}
+		if subtle.ConstantTimeCompare([]byte(expected), []byte(code)) == 1 {
+			return at.Unix() / totpPeriod, true
+		}
+	}
+	return 0, false
+}
@@ -0,0 +1,152 @@
+package sso
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/coreos/go-oidc/v3/oidc"
+	"golang.org/x/oauth2"
+)
+
+// OIDCProviderConfig adalah konfigurasi satu identity provider OpenID Connect upstream.
+type OIDCProviderConfig struct {
+	Name         string
+	DisplayName  string
+	Issuer       string // Alamat discovery, misalnya https://accounts.google.com
+	ClientID     string
+	ClientSecret string
+	RedirectURL  string
+	Scopes       []string
+	TrustEmail   bool // Anggap email terverifikasi jika provider tidak mengirim klaim email_verified
+}
+
+// OIDCIdentityProvider adalah implementasi services.IdentityProvider dengan OpenID Connect.
+// Dokumen discovery baru diambil saat pertama kali dipakai agar aplikasi tetap bisa berjalan
+// ketika provider sedang tidak bisa dihubungi; kegagalan diulang pada permintaan berikutnya.
+type OIDCIdentityProvider struct {
+	config OIDCProviderConfig
+
+	mu       sync.Mutex
+	oauth    *oauth2.Config
+	verifier *oidc.IDTokenVerifier
+}
+
+// NewOIDCIdentityProvider membuat instance baru dari OIDCIdentityProvider.
+func NewOIDCIdentityProvider(config OIDCProviderConfig) services.IdentityProvider {
+	if len(config.Scopes) == 0 {
+		config.Scopes = []string{oidc.ScopeOpenID, "email", "profile"}
+	}
+	if config.DisplayName == "" {
+		config.DisplayName = config.Name
+	}
+	return &OIDCIdentityProvider{config: config}
+}
+
+// Name mengimplementasikan metode Name dari IdentityProvider.
+func (p *OIDCIdentityProvider) Name() string {
+	return p.config.Name
+}
+
+// DisplayName mengimplementasikan metode DisplayName dari IdentityProvider.
+func (p *OIDCIdentityProvider) DisplayName() string {
+	return p.config.DisplayName
+}
+
+// AuthCodeURL mengimplementasikan metode AuthCodeURL dari IdentityProvider.
+func (p *OIDCIdentityProvider) AuthCodeURL(ctx context.Context, state, nonce, codeVerifier string) (string, error) {
+	config, _, err := p.discover(ctx)
+	if err != nil {
+		return "", err
+	}
+	return config.AuthCodeURL(state, oidc.Nonce(nonce), oauth2.S256ChallengeOption(codeVerifier)), nil
+}
+
+// Exchange mengimplementasikan metode Exchange dari IdentityProvider.
+func (p *OIDCIdentityProvider) Exchange(ctx context.Context, code, codeVerifier, nonce string) (*services.ExternalIdentity, error) {
+	config, verifier, err := p.discover(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := config.Exchange(ctx, code, oauth2.VerifierOption(codeVerifier))
+	if err != nil {
+		return nil, fmt.Errorf("gagal menukar authorization code di %s: %w", p.config.Name, err)
+	}
+	rawIDToken, ok := token.Extra("id_token").(string)
+	if !ok || rawIDToken == "" {
+		return nil, fmt.Errorf("%s tidak mengembalikan id_token", p.config.Name)
+	}
+
+	idToken, err := verifier.Verify(ctx, rawIDToken)
+	if err != nil {
+		return nil, fmt.Errorf("id_token dari %s tidak valid: %w", p.config.Name, err)
+	}
+	if idToken.Nonce != nonce {
+		return nil, errors.New("nonce id_token tidak cocok")
+	}
+
+	var claims struct {
+		Email             string      `json:"email"`
+		EmailVerified     interface{} `json:"email_verified"` // Sebagian provider mengirim string "true"
+		PreferredUsername string      `json:"preferred_username"`
+		GivenName         string      `json:"given_name"`
+		FamilyName        string      `json:"family_name"`
+	}
+	if err := idToken.Claims(&claims); err != nil {
+		return nil, fmt.Errorf("gagal membaca klaim id_token dari %s: %w", p.config.Name, err)
+	}
+
+	return &services.ExternalIdentity{
+		Provider:          p.config.Name,
+		Subject:           idToken.Subject,
+		Email:             claims.Email,
+		EmailVerified:     claims.Email != "" && p.emailVerified(claims.EmailVerified),
+		PreferredUsername: claims.PreferredUsername,
+		FirstName:         claims.GivenName,
+		LastName:          claims.FamilyName,
+	}, nil
+}
+
+// discover mengambil dokumen discovery provider sekali lalu menyimpannya.
+func (p *OIDCIdentityProvider) discover(ctx context.Context) (*oauth2.Config, *oidc.IDTokenVerifier, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.oauth != nil {
+		return p.oauth, p.verifier, nil
+	}
+
+	// Context permintaan tidak dipakai untuk provider karena klien HTTP-nya disimpan untuk pengambilan JWKS berikutnya
+	provider, err := oidc.NewProvider(context.WithoutCancel(ctx), p.config.Issuer)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal memuat discovery %s: %w", p.config.Name, err)
+	}
+
+	p.oauth = &oauth2.Config{
+		ClientID:     p.config.ClientID,
+		ClientSecret: p.config.ClientSecret,
+		RedirectURL:  p.config.RedirectURL,
+		Endpoint:     provider.Endpoint(),
+		Scopes:       p.config.Scopes,
+	}
+	p.verifier = provider.Verifier(&oidc.Config{ClientID: p.config.ClientID})
+	return p.oauth, p.verifier, nil
+}
+
+// emailVerified menafsirkan klaim email_verified yang bisa berupa boolean atau string.
+func (p *OIDCIdentityProvider) emailVerified(claim interface{}) bool {
+	switch v := claim.(type) {
+	case bool:
+		return v
+	case string:
+		verified, _ := strconv.ParseBool(v)
+		return verified
+	default:
+		return p.config.TrustEmail
+	}
+}
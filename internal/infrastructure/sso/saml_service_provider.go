@@ -0,0 +1,259 @@
+package sso
+
+import (
+	"bytes"
+	"context"
+	"crypto/rsa"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/crewjam/saml"
+	xrv "github.com/mattermost/xml-roundtrip-validator"
+	dsig "github.com/russellhaering/goxmldsig"
+)
+
+// samlMetadataLimit membatasi ukuran metadata IdP yang diunduh; metadata federasi besar tidak didukung.
+const samlMetadataLimit = 1 << 20
+
+// SAMLConfig adalah konfigurasi service provider SAML.
+type SAMLConfig struct {
+	BaseURL     string // Alamat publik aplikasi; entity ID dan ACS koneksi berada di <BaseURL>/auth/saml/<name>
+	Certificate *x509.Certificate
+	Key         *rsa.PrivateKey // Opsional; jika ada, AuthnRequest ditandatangani dan assertion terenkripsi bisa dibaca
+}
+
+// SAMLServiceProviderImpl adalah implementasi services.SAMLServiceProvider dengan crewjam/saml.
+type SAMLServiceProviderImpl struct {
+	config SAMLConfig
+	client *http.Client
+}
+
+// NewSAMLServiceProvider membuat instance baru dari SAMLServiceProviderImpl.
+func NewSAMLServiceProvider(config SAMLConfig) services.SAMLServiceProvider {
+	config.BaseURL = strings.TrimSuffix(config.BaseURL, "/")
+	return &SAMLServiceProviderImpl{
+		config: config,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// LoadSAMLKeyPair membaca sertifikat dan kunci privat RSA service provider dari file PEM.
+func LoadSAMLKeyPair(certFile, keyFile string) (*x509.Certificate, *rsa.PrivateKey, error) {
+	pair, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal membaca sertifikat SAML: %w", err)
+	}
+	key, ok := pair.PrivateKey.(*rsa.PrivateKey)
+	if !ok {
+		return nil, nil, errors.New("kunci privat SAML harus berupa RSA")
+	}
+	certificate, err := x509.ParseCertificate(pair.Certificate[0])
+	if err != nil {
+		return nil, nil, fmt.Errorf("gagal membaca sertifikat SAML: %w", err)
+	}
+	return certificate, key, nil
+}
+
+// ParseIdPMetadata mengimplementasikan metode ParseIdPMetadata dari SAMLServiceProvider.
+func (p *SAMLServiceProviderImpl) ParseIdPMetadata(metadata []byte) (*services.SAMLIdPMetadata, error) {
+	descriptor, err := parseEntityDescriptor(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	sp := &saml.ServiceProvider{IDPMetadata: descriptor}
+	ssoURL := sp.GetSSOBindingLocation(saml.HTTPRedirectBinding)
+	if descriptor.EntityID == "" || ssoURL == "" {
+		return nil, fmt.Errorf("%w: entityID atau SingleSignOnService HTTP-Redirect tidak ada", services.ErrSAMLInvalidMetadata)
+	}
+	if !hasSigningCertificate(descriptor) {
+		return nil, fmt.Errorf("%w: tidak ada sertifikat penanda tangan", services.ErrSAMLInvalidMetadata)
+	}
+	return &services.SAMLIdPMetadata{EntityID: descriptor.EntityID, SSOURL: ssoURL}, nil
+}
+
+// FetchIdPMetadata mengimplementasikan metode FetchIdPMetadata dari SAMLServiceProvider.
+func (p *SAMLServiceProviderImpl) FetchIdPMetadata(ctx context.Context, metadataURL string) ([]byte, error) {
+	parsed, err := url.Parse(metadataURL)
+	if err != nil || parsed.Scheme != "https" {
+		return nil, fmt.Errorf("%w: URL metadata harus https", services.ErrSAMLInvalidMetadata)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, metadataURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("gagal mengunduh metadata IdP: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("gagal mengunduh metadata IdP: status %d", resp.StatusCode)
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, samlMetadataLimit))
+}
+
+// Metadata mengimplementasikan metode Metadata dari SAMLServiceProvider.
+func (p *SAMLServiceProviderImpl) Metadata(connection string) ([]byte, error) {
+	sp, err := p.serviceProvider(connection, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	descriptor := sp.Metadata()
+	// Hanya binding HTTP-POST yang diterima ACS; artifact resolution tidak didukung
+	for n := range descriptor.SPSSODescriptors {
+		descriptor.SPSSODescriptors[n].AssertionConsumerServices = descriptor.SPSSODescriptors[n].AssertionConsumerServices[:1]
+	}
+
+	raw, err := xml.MarshalIndent(descriptor, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(xml.Header), raw...), nil
+}
+
+// AuthnRequest mengimplementasikan metode AuthnRequest dari SAMLServiceProvider.
+func (p *SAMLServiceProviderImpl) AuthnRequest(connection string, idpMetadata []byte, relayState string) (*services.SAMLAuthnRequest, error) {
+	sp, err := p.serviceProvider(connection, idpMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := sp.MakeAuthenticationRequest(sp.GetSSOBindingLocation(saml.HTTPRedirectBinding), saml.HTTPRedirectBinding, saml.HTTPPostBinding)
+	if err != nil {
+		return nil, err
+	}
+	redirectURL, err := req.Redirect(url.QueryEscape(relayState), sp)
+	if err != nil {
+		return nil, err
+	}
+	return &services.SAMLAuthnRequest{ID: req.ID, RedirectURL: redirectURL.String()}, nil
+}
+
+// ParseResponse mengimplementasikan metode ParseResponse dari SAMLServiceProvider.
+func (p *SAMLServiceProviderImpl) ParseResponse(connection string, idpMetadata []byte, samlResponse, requestID string) (*services.SAMLAssertion, error) {
+	sp, err := p.serviceProvider(connection, idpMetadata)
+	if err != nil {
+		return nil, err
+	}
+
+	raw, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return nil, errors.New("SAMLResponse bukan base64 yang valid")
+	}
+	if sp.Key == nil && bytes.Contains(raw, []byte("EncryptedAssertion")) {
+		return nil, errors.New("assertion terenkripsi membutuhkan kunci privat service provider")
+	}
+
+	var possibleRequestIDs []string
+	if requestID == "" {
+		sp.AllowIDPInitiated = true
+	} else {
+		possibleRequestIDs = []string{requestID}
+	}
+
+	assertion, err := sp.ParseXMLResponse(raw, possibleRequestIDs)
+	if err != nil {
+		var invalid *saml.InvalidResponseError
+		if errors.As(err, &invalid) {
+			return nil, fmt.Errorf("SAMLResponse ditolak: %w", invalid.PrivateErr)
+		}
+		return nil, err
+	}
+	if assertion.Subject == nil || assertion.Subject.NameID == nil || assertion.Subject.NameID.Value == "" {
+		return nil, errors.New("assertion SAML tidak memiliki NameID")
+	}
+
+	attributes := make(map[string][]string)
+	for _, statement := range assertion.AttributeStatements {
+		for _, attribute := range statement.Attributes {
+			values := make([]string, 0, len(attribute.Values))
+			for _, value := range attribute.Values {
+				values = append(values, value.Value)
+			}
+			attributes[attribute.Name] = append(attributes[attribute.Name], values...)
+			if attribute.FriendlyName != "" && attribute.FriendlyName != attribute.Name {
+				attributes[attribute.FriendlyName] = append(attributes[attribute.FriendlyName], values...)
+			}
+		}
+	}
+
+	return &services.SAMLAssertion{
+		ID:         assertion.ID,
+		NameID:     assertion.Subject.NameID.Value,
+		Attributes: attributes,
+	}, nil
+}
+
+// serviceProvider membangun service provider untuk satu koneksi. idpMetadata boleh kosong
+// jika yang dibutuhkan hanya metadata service provider.
+func (p *SAMLServiceProviderImpl) serviceProvider(connection string, idpMetadata []byte) (*saml.ServiceProvider, error) {
+	base := p.config.BaseURL + "/auth/saml/" + url.PathEscape(connection)
+	metadataURL, err := url.Parse(base + "/metadata")
+	if err != nil {
+		return nil, err
+	}
+	acsURL, err := url.Parse(base + "/acs")
+	if err != nil {
+		return nil, err
+	}
+
+	sp := &saml.ServiceProvider{
+		EntityID:          metadataURL.String(),
+		Certificate:       p.config.Certificate,
+		MetadataURL:       *metadataURL,
+		AcsURL:            *acsURL,
+		AuthnNameIDFormat: saml.UnspecifiedNameIDFormat,
+	}
+	if p.config.Key != nil {
+		sp.Key = p.config.Key
+		sp.SignatureMethod = dsig.RSASHA256SignatureMethod
+	}
+
+	if len(idpMetadata) > 0 {
+		descriptor, err := parseEntityDescriptor(idpMetadata)
+		if err != nil {
+			return nil, err
+		}
+		sp.IDPMetadata = descriptor
+	}
+	return sp, nil
+}
+
+// parseEntityDescriptor membaca XML EntityDescriptor setelah memastikan XML tidak bisa ditafsirkan ganda.
+func parseEntityDescriptor(metadata []byte) (*saml.EntityDescriptor, error) {
+	if err := xrv.Validate(bytes.NewReader(metadata)); err != nil {
+		return nil, fmt.Errorf("%w: %v", services.ErrSAMLInvalidMetadata, err)
+	}
+	var descriptor saml.EntityDescriptor
+	if err := xml.Unmarshal(metadata, &descriptor); err != nil {
+		return nil, fmt.Errorf("%w: %v", services.ErrSAMLInvalidMetadata, err)
+	}
+	return &descriptor, nil
+}
+
+// hasSigningCertificate mengembalikan true jika IdP memiliki sertifikat untuk memverifikasi tanda tangan.
+func hasSigningCertificate(descriptor *saml.EntityDescriptor) bool {
+	for _, idp := range descriptor.IDPSSODescriptors {
+		for _, key := range idp.KeyDescriptors {
+			if (key.Use == "" || key.Use == "signing") && len(key.KeyInfo.X509Data.X509Certificates) > 0 {
+				return true
+			}
+		}
+	}
+	return false
+}
@@ -0,0 +1,243 @@
+package sso
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"encoding/xml"
+	"fmt"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/beevik/etree"
+	"github.com/crewjam/saml"
+)
+
+const (
+	testIdPEntityID = "https://idp.example.org/metadata"
+	testSPBaseURL   = "https://app.example.org"
+	testConnection  = "acme"
+)
+
+// testIdP adalah identity provider SAML uji dengan kunci dan sertifikat yang dibuat saat pengujian.
+type testIdP struct {
+	idp      *saml.IdentityProvider
+	metadata []byte
+}
+
+// newTestIdP membuat IdP uji dengan sertifikat self-signed beserta XML metadata-nya.
+func newTestIdP(t *testing.T) *testIdP {
+	t.Helper()
+	key, certificate := newTestKeyPair(t, "idp.example.org")
+	metadataURL, _ := url.Parse(testIdPEntityID)
+	ssoURL, _ := url.Parse("https://idp.example.org/sso")
+	idp := &saml.IdentityProvider{
+		Key:         key,
+		Certificate: certificate,
+		MetadataURL: *metadataURL,
+		SSOURL:      *ssoURL,
+	}
+	metadata, err := xml.Marshal(idp.Metadata())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return &testIdP{idp: idp, metadata: metadata}
+}
+
+// newTestKeyPair membuat kunci RSA dan sertifikat self-signed untuk nama host.
+func newTestKeyPair(t *testing.T, host string) (*rsa.PrivateKey, *x509.Certificate) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: host},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	raw, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	certificate, err := x509.ParseCertificate(raw)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return key, certificate
+}
+
+// testAssertion adalah isi SAMLResponse yang dibuat IdP uji. Nilai kosong diisi dengan nilai yang valid.
+type testAssertion struct {
+	id           string
+	inResponseTo string
+	audience     string
+	acs          string
+	issuedAt     time.Time
+	unsigned     bool
+	signer       *rsa.PrivateKey // Kunci lain selain kunci IdP untuk memalsukan tanda tangan
+}
+
+// response membuat SAMLResponse (base64) untuk service provider koneksi testConnection.
+func (p *testIdP) response(t *testing.T, a testAssertion) string {
+	t.Helper()
+	base := testSPBaseURL + "/auth/saml/" + testConnection
+	if a.id == "" {
+		a.id = fmt.Sprintf("id-%d", time.Now().UnixNano())
+	}
+	if a.audience == "" {
+		a.audience = base + "/metadata"
+	}
+	if a.acs == "" {
+		a.acs = base + "/acs"
+	}
+	if a.issuedAt.IsZero() {
+		a.issuedAt = time.Now()
+	}
+
+	req := &saml.IdpAuthnRequest{
+		IDP:             p.idp,
+		Request:         saml.AuthnRequest{ID: a.inResponseTo},
+		SPSSODescriptor: &saml.SPSSODescriptor{},
+		ACSEndpoint:     &saml.IndexedEndpoint{Location: a.acs},
+		Now:             a.issuedAt,
+		Assertion: &saml.Assertion{
+			ID:           a.id,
+			IssueInstant: a.issuedAt,
+			Version:      "2.0",
+			Issuer:       saml.Issuer{Format: "urn:oasis:names:tc:SAML:2.0:nameid-format:entity", Value: testIdPEntityID},
+			Subject: &saml.Subject{
+				NameID: &saml.NameID{Format: string(saml.EmailAddressNameIDFormat), Value: "alice@acme.example"},
+				SubjectConfirmations: []saml.SubjectConfirmation{{
+					Method: "urn:oasis:names:tc:SAML:2.0:cm:bearer",
+					SubjectConfirmationData: &saml.SubjectConfirmationData{
+						InResponseTo: a.inResponseTo,
+						NotOnOrAfter: a.issuedAt.Add(5 * time.Minute),
+						Recipient:    a.acs,
+					},
+				}},
+			},
+			Conditions: &saml.Conditions{
+				NotBefore:            a.issuedAt.Add(-time.Minute),
+				NotOnOrAfter:         a.issuedAt.Add(5 * time.Minute),
+				AudienceRestrictions: []saml.AudienceRestriction{{Audience: saml.Audience{Value: a.audience}}},
+			},
+			AuthnStatements: []saml.AuthnStatement{{AuthnInstant: a.issuedAt, SessionIndex: a.id}},
+			AttributeStatements: []saml.AttributeStatement{{Attributes: []saml.Attribute{{
+				Name:       "email",
+				NameFormat: "urn:oasis:names:tc:SAML:2.0:attrname-format:basic",
+				Values:     []saml.AttributeValue{{Type: "xs:string", Value: "alice@acme.example"}},
+			}}}},
+		},
+	}
+
+	var responseEl *etree.Element
+	if a.unsigned {
+		response := &saml.Response{
+			Destination:  a.acs,
+			ID:           a.id + "-response",
+			InResponseTo: a.inResponseTo,
+			IssueInstant: a.issuedAt,
+			Version:      "2.0",
+			Issuer:       &saml.Issuer{Format: "urn:oasis:names:tc:SAML:2.0:nameid-format:entity", Value: testIdPEntityID},
+			Status:       saml.Status{StatusCode: saml.StatusCode{Value: saml.StatusSuccess}},
+		}
+		responseEl = response.Element()
+		responseEl.AddChild(req.Assertion.Element())
+	} else {
+		if a.signer != nil {
+			signer := *p.idp
+			signer.Key = a.signer
+			req.IDP = &signer
+		}
+		if err := req.MakeResponse(); err != nil {
+			t.Fatal(err)
+		}
+		responseEl = req.ResponseEl
+	}
+
+	doc := etree.NewDocument()
+	doc.SetRoot(responseEl)
+	raw, err := doc.WriteToBytes()
+	if err != nil {
+		t.Fatal(err)
+	}
+	return base64.StdEncoding.EncodeToString(raw)
+}
+
+func newTestServiceProvider(t *testing.T) *SAMLServiceProviderImpl {
+	t.Helper()
+	_, certificate := newTestKeyPair(t, "app.example.org")
+	return NewSAMLServiceProvider(SAMLConfig{BaseURL: testSPBaseURL + "/", Certificate: certificate}).(*SAMLServiceProviderImpl)
+}
+
+func TestSAMLServiceProviderParseResponse(t *testing.T) {
+	idp := newTestIdP(t)
+	sp := newTestServiceProvider(t)
+
+	req, err := sp.AuthnRequest(testConnection, idp.metadata, "relay")
+	if err != nil {
+		t.Fatalf("AuthnRequest: %v", err)
+	}
+
+	assertion, err := sp.ParseResponse(testConnection, idp.metadata, idp.response(t, testAssertion{id: "id-ok", inResponseTo: req.ID}), req.ID)
+	if err != nil {
+		t.Fatalf("ParseResponse: %v", err)
+	}
+	if assertion.ID != "id-ok" || assertion.NameID != "alice@acme.example" || len(assertion.Attributes["email"]) != 1 {
+		t.Fatalf("assertion tidak sesuai: %+v", assertion)
+	}
+
+	// Login IdP-initiated tidak membawa InResponseTo dan hanya diterima tanpa ID permintaan
+	if _, err := sp.ParseResponse(testConnection, idp.metadata, idp.response(t, testAssertion{}), ""); err != nil {
+		t.Fatalf("ParseResponse IdP-initiated: %v", err)
+	}
+}
+
+func TestSAMLServiceProviderParseResponseRejects(t *testing.T) {
+	idp := newTestIdP(t)
+	sp := newTestServiceProvider(t)
+	forger, _ := newTestKeyPair(t, "idp.example.org")
+	const requestID = "id-request"
+
+	tests := []struct {
+		name      string
+		assertion testAssertion
+		requestID string
+	}{
+		{"tanpa tanda tangan", testAssertion{inResponseTo: requestID, unsigned: true}, requestID},
+		{"ditandatangani kunci lain", testAssertion{inResponseTo: requestID, signer: forger}, requestID},
+		{"audience koneksi lain", testAssertion{inResponseTo: requestID, audience: testSPBaseURL + "/auth/saml/other/metadata"}, requestID},
+		{"ACS koneksi lain", testAssertion{inResponseTo: requestID, acs: testSPBaseURL + "/auth/saml/other/acs"}, requestID},
+		{"kedaluwarsa", testAssertion{inResponseTo: requestID, issuedAt: time.Now().Add(-time.Hour)}, requestID},
+		{"belum berlaku", testAssertion{inResponseTo: requestID, issuedAt: time.Now().Add(time.Hour)}, requestID},
+		// Login SP-initiated tidak menerima respons tanpa permintaan (unsolicited)
+		{"respons tanpa permintaan", testAssertion{}, requestID},
+		// Respons untuk AuthnRequest lain yang dikirim ulang
+		{"respons untuk permintaan lain", testAssertion{inResponseTo: "id-other"}, requestID},
+		{"tanpa tanda tangan IdP-initiated", testAssertion{unsigned: true}, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if assertion, err := sp.ParseResponse(testConnection, idp.metadata, idp.response(t, tt.assertion), tt.requestID); err == nil {
+				t.Fatalf("ParseResponse menerima assertion %+v", assertion)
+			}
+		})
+	}
+}
+
+func TestSAMLServiceProviderParseResponseWrongIdP(t *testing.T) {
+	idp := newTestIdP(t)
+	other := newTestIdP(t)
+	sp := newTestServiceProvider(t)
+
+	// Assertion yang ditandatangani IdP koneksi lain ditolak meskipun entity ID-nya sama
+	if _, err := sp.ParseResponse(testConnection, other.metadata, idp.response(t, testAssertion{}), ""); err == nil {
+		t.Fatal("ParseResponse menerima assertion dari IdP lain")
+	}
+}
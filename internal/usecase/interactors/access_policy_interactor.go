@@ -0,0 +1,217 @@
+package interactors
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAccessPolicyNotFound dikembalikan jika kebijakan akses tidak ada.
+	ErrAccessPolicyNotFound = errors.New("kebijakan akses tidak ditemukan")
+	// ErrAccessPolicyNameRequired dikembalikan jika nama kebijakan kosong.
+	ErrAccessPolicyNameRequired = errors.New("nama kebijakan wajib diisi")
+	// ErrAccessPolicyNameTaken dikembalikan jika nama kebijakan sudah dipakai kebijakan lain.
+	ErrAccessPolicyNameTaken = errors.New("nama kebijakan sudah dipakai")
+	// ErrAccessPolicyEffectInvalid dikembalikan jika efek kebijakan bukan allow atau deny.
+	ErrAccessPolicyEffectInvalid = errors.New("efek kebijakan harus allow atau deny")
+	// ErrAccessPolicyActionsRequired dikembalikan jika kebijakan tidak mencakup permission apa pun.
+	ErrAccessPolicyActionsRequired = errors.New("kebijakan harus mencakup minimal satu permission")
+	// ErrAccessPolicyActionInvalid dikembalikan jika pola permission kebijakan tidak valid atau berupa deny.
+	ErrAccessPolicyActionInvalid = errors.New("pola permission kebijakan tidak valid")
+	// ErrPolicyConditionInvalid dikembalikan jika kondisi kebijakan tidak sesuai tata bahasa.
+	ErrPolicyConditionInvalid = errors.New("kondisi kebijakan tidak valid")
+	// ErrPolicyEvaluation dikembalikan jika kondisi gagal dievaluasi, misalnya membandingkan angka dengan string.
+	ErrPolicyEvaluation = errors.New("kondisi kebijakan gagal dievaluasi")
+)
+
+// AccessPolicyInput adalah isian untuk membuat atau mengganti kebijakan akses.
+type AccessPolicyInput struct {
+	Name        string
+	Description string
+	Effect      string
+	Actions     []string
+	Condition   string
+	Enabled     bool
+}
+
+// PolicyMatch adalah kebijakan yang terpenuhi saat pemeriksaan akses.
+type PolicyMatch struct {
+	Policy string `json:"policy"`
+	Effect string `json:"effect"`
+	Error  string `json:"error,omitempty"` // Terisi jika kebijakan deny gagal dievaluasi; kebijakan tetap menolak
+}
+
+// AccessPolicyInteractor adalah use case untuk pengelolaan dan evaluasi kebijakan akses ABAC.
+type AccessPolicyInteractor struct {
+	policyRepo repositories.AccessPolicyRepository
+	location   *time.Location
+}
+
+// NewAccessPolicyInteractor membuat instance baru dari AccessPolicyInteractor. location menentukan zona
+// waktu atribut env, misalnya env.hour untuk aturan jam kerja.
+func NewAccessPolicyInteractor(pr repositories.AccessPolicyRepository, location *time.Location) *AccessPolicyInteractor {
+	return &AccessPolicyInteractor{policyRepo: pr, location: location}
+}
+
+// Create membuat kebijakan akses baru setelah kondisinya berhasil di-parse.
+func (i *AccessPolicyInteractor) Create(input AccessPolicyInput) (*entities.AccessPolicy, error) {
+	policy := &entities.AccessPolicy{}
+	if err := i.apply(policy, input); err != nil {
+		return nil, err
+	}
+	if err := i.policyRepo.Create(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// List mengembalikan semua kebijakan akses.
+func (i *AccessPolicyInteractor) List() ([]entities.AccessPolicy, error) {
+	return i.policyRepo.FindAll()
+}
+
+// Get mengembalikan satu kebijakan akses.
+func (i *AccessPolicyInteractor) Get(id uuid.UUID) (*entities.AccessPolicy, error) {
+	policy, err := i.policyRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccessPolicyNotFound
+		}
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Update mengganti seluruh isi kebijakan akses.
+func (i *AccessPolicyInteractor) Update(id uuid.UUID, input AccessPolicyInput) (*entities.AccessPolicy, error) {
+	policy, err := i.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.apply(policy, input); err != nil {
+		return nil, err
+	}
+	if err := i.policyRepo.Update(policy); err != nil {
+		return nil, err
+	}
+	return policy, nil
+}
+
+// Delete menghapus kebijakan akses.
+func (i *AccessPolicyInteractor) Delete(id uuid.UUID) error {
+	if err := i.policyRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAccessPolicyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Applicable mengembalikan kebijakan aktif yang mencakup permission.
+func (i *AccessPolicyInteractor) Applicable(permission string) ([]entities.AccessPolicy, error) {
+	policies, err := i.policyRepo.FindEnabled()
+	if err != nil {
+		return nil, err
+	}
+	applicable := make([]entities.AccessPolicy, 0, len(policies))
+	for _, policy := range policies {
+		if policy.AppliesTo(permission) {
+			applicable = append(applicable, policy)
+		}
+	}
+	return applicable, nil
+}
+
+// Evaluate mengevaluasi kondisi policies terhadap atribut input (subject, resource, action dan env) dan
+// mengembalikan kebijakan yang terpenuhi. Kebijakan deny yang gagal dievaluasi dianggap terpenuhi agar
+// kesalahan data tidak membuka akses; kebijakan allow yang gagal dievaluasi diabaikan.
+func (i *AccessPolicyInteractor) Evaluate(policies []entities.AccessPolicy, input map[string]any) []PolicyMatch {
+	matches := []PolicyMatch{}
+	for _, policy := range policies {
+		expression, err := parsePolicyCondition(policy.Condition)
+		matched := false
+		if err == nil {
+			matched, err = evaluatePolicyCondition(expression, input)
+		}
+		if err != nil {
+			log.Printf("Kebijakan akses %s gagal dievaluasi: %v", policy.Name, err)
+			if policy.Effect == entities.PolicyEffectDeny {
+				matches = append(matches, PolicyMatch{Policy: policy.Name, Effect: policy.Effect, Error: err.Error()})
+			}
+			continue
+		}
+		if matched {
+			matches = append(matches, PolicyMatch{Policy: policy.Name, Effect: policy.Effect})
+		}
+	}
+	return matches
+}
+
+// Environment mengembalikan atribut env untuk waktu now: time (RFC 3339), date, hour, minute dan weekday
+// (1 = Senin sampai 7 = Minggu), dihitung di zona waktu yang dikonfigurasi.
+func (i *AccessPolicyInteractor) Environment(now time.Time) map[string]any {
+	now = now.In(i.location)
+	weekday := int(now.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	return map[string]any{
+		"time":    now.Format(time.RFC3339),
+		"date":    now.Format(time.DateOnly),
+		"hour":    float64(now.Hour()),
+		"minute":  float64(now.Minute()),
+		"weekday": float64(weekday),
+	}
+}
+
+// apply memvalidasi input lalu menyalinnya ke policy.
+func (i *AccessPolicyInteractor) apply(policy *entities.AccessPolicy, input AccessPolicyInput) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return ErrAccessPolicyNameRequired
+	}
+	existing, err := i.policyRepo.FindByName(name)
+	if err == nil && existing.ID != policy.ID {
+		return ErrAccessPolicyNameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	effect := strings.ToLower(strings.TrimSpace(input.Effect))
+	if effect != entities.PolicyEffectAllow && effect != entities.PolicyEffectDeny {
+		return ErrAccessPolicyEffectInvalid
+	}
+
+	actions := normalizeScopes(input.Actions)
+	if len(actions) == 0 {
+		return ErrAccessPolicyActionsRequired
+	}
+	for _, action := range actions {
+		if pattern, ok := entities.ParsePermission(action); !ok || pattern.Deny {
+			return fmt.Errorf("%w: %s", ErrAccessPolicyActionInvalid, action)
+		}
+	}
+
+	if _, err := parsePolicyCondition(input.Condition); err != nil {
+		return err
+	}
+
+	policy.Name = name
+	policy.Description = input.Description
+	policy.Effect = effect
+	policy.Actions = actions
+	policy.Condition = strings.TrimSpace(input.Condition)
+	policy.Enabled = input.Enabled
+	return nil
+}
@@ -0,0 +1,668 @@
+package interactors
+
+import (
+	"bytes"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAccessReviewNotFound dikembalikan jika kampanye tinjauan akses tidak ada.
+	ErrAccessReviewNotFound = errors.New("kampanye tinjauan akses tidak ditemukan")
+	// ErrAccessReviewNameRequired dikembalikan jika nama kampanye kosong.
+	ErrAccessReviewNameRequired = errors.New("nama kampanye tinjauan akses wajib diisi")
+	// ErrAccessReviewStrategyInvalid dikembalikan jika cara menentukan peninjau tidak dikenal.
+	ErrAccessReviewStrategyInvalid = errors.New("reviewer_strategy harus manager atau role_owner")
+	// ErrAccessReviewDueInvalid dikembalikan jika batas waktu kampanye tidak di masa depan.
+	ErrAccessReviewDueInvalid = errors.New("batas waktu kampanye harus di masa depan")
+	// ErrAccessReviewRoleNotFound dikembalikan jika salah satu Role dalam cakupan tidak terdaftar.
+	ErrAccessReviewRoleNotFound = errors.New("role tidak ditemukan")
+	// ErrAccessReviewReviewerNotFound dikembalikan jika peninjau cadangan tidak terdaftar.
+	ErrAccessReviewReviewerNotFound = errors.New("peninjau cadangan tidak ditemukan")
+	// ErrAccessReviewEmpty dikembalikan jika tidak ada penetapan Role dalam cakupan kampanye.
+	ErrAccessReviewEmpty = errors.New("tidak ada penetapan role dalam cakupan kampanye")
+	// ErrAccessReviewClosed dikembalikan jika kampanye sudah selesai.
+	ErrAccessReviewClosed = errors.New("kampanye tinjauan akses sudah selesai")
+	// ErrAccessReviewItemNotFound dikembalikan jika item tinjauan tidak ada.
+	ErrAccessReviewItemNotFound = errors.New("item tinjauan akses tidak ditemukan")
+	// ErrAccessReviewItemDecided dikembalikan jika item tinjauan sudah diputuskan.
+	ErrAccessReviewItemDecided = errors.New("item tinjauan akses sudah diputuskan")
+	// ErrAccessReviewDecisionInvalid dikembalikan jika keputusan bukan keep atau revoke.
+	ErrAccessReviewDecisionInvalid = errors.New("keputusan harus keep atau revoke")
+	// ErrAccessReviewForbidden dikembalikan jika pengguna bukan peninjau item dan bukan superuser.
+	ErrAccessReviewForbidden = errors.New("tidak berwenang meninjau item ini")
+)
+
+// Tindakan yang dicatat di jejak audit tinjauan akses.
+const (
+	AuditAccessReviewCreated   = "access_review.created"
+	AuditAccessReviewDecided   = "access_review.item_decided"
+	AuditAccessReviewRevoked   = "access_review.grant_revoked"
+	AuditAccessReviewCompleted = "access_review.completed"
+	AuditAccessReviewExported  = "access_review.exported"
+)
+
+// accessReviewReportHeader adalah kolom laporan CSV kampanye tinjauan akses.
+var accessReviewReportHeader = []string{
+	"campaign_id", "campaign", "user_id", "username", "role_id", "role", "source", "group_id", "group",
+	"granted_at", "expires_at", "reviewer_id", "decision", "comment", "decided_by", "decided_at", "revoked_at",
+	"organization_id", "organization",
+}
+
+// AccessReviewInput adalah isian pembuatan kampanye tinjauan akses. Roles kosong berarti semua Role;
+// FallbackReviewer (username atau email) meninjau penetapan yang tidak punya manajer atau pemilik Role.
+type AccessReviewInput struct {
+	Name             string
+	Description      string
+	Roles            []string
+	ReviewerStrategy string
+	FallbackReviewer string
+	DueAt            time.Time
+	RevokeOnExpiry   bool
+}
+
+// AccessReviewReport adalah laporan CSV kampanye beserta tanda tangan terpisahnya.
+type AccessReviewReport struct {
+	Filename  string
+	Content   []byte
+	Signature *services.Signature
+}
+
+// AccessReviewInteractor adalah use case untuk kampanye tinjauan akses berkala. Saat kampanye dibuat,
+// semua akses ke Role dalam cakupan diambil snapshot-nya, baik penetapan langsung, lewat Group dan
+// Subgroup-nya maupun Role anggota organisasi, dan setiap akses ditugaskan ke manajer pengguna atau pemilik
+// Role. Keputusan revoke langsung mencabut akses: penetapan langsung dicabut, akses lewat Group dicabut
+// dengan mengeluarkan pengguna dari Group tempat ia menjadi anggota langsung, dan Role anggota dicabut
+// dari keanggotaan organisasinya. Akses yang belum ditinjau saat kampanye berakhir dicabut jika
+// RevokeOnExpiry.
+type AccessReviewInteractor struct {
+	reviewRepo repositories.AccessReviewRepository
+	roleRepo   repositories.RoleRepository
+	groupRepo  repositories.GroupRepository
+	orgRepo    repositories.OrganizationRepository
+	memberRepo repositories.OrganizationMemberRepository
+	userRepo   repositories.UserRepository
+	grants     *RoleGrantInteractor
+	audit      *AuditInteractor
+	keys       services.KeyManager
+}
+
+// NewAccessReviewInteractor membuat instance baru dari AccessReviewInteractor.
+func NewAccessReviewInteractor(
+	arr repositories.AccessReviewRepository,
+	rr repositories.RoleRepository,
+	gr repositories.GroupRepository,
+	or repositories.OrganizationRepository,
+	omr repositories.OrganizationMemberRepository,
+	ur repositories.UserRepository,
+	grants *RoleGrantInteractor,
+	audit *AuditInteractor,
+	keys services.KeyManager,
+) *AccessReviewInteractor {
+	return &AccessReviewInteractor{
+		reviewRepo: arr,
+		roleRepo:   rr,
+		groupRepo:  gr,
+		orgRepo:    or,
+		memberRepo: omr,
+		userRepo:   ur,
+		grants:     grants,
+		audit:      audit,
+		keys:       keys,
+	}
+}
+
+// Create membuat kampanye tinjauan akses beserta snapshot penetapan dalam cakupannya.
+func (i *AccessReviewInteractor) Create(actorID uuid.UUID, input AccessReviewInput) (*entities.AccessReviewCampaign, error) {
+	campaign := &entities.AccessReviewCampaign{
+		Name:             strings.TrimSpace(input.Name),
+		Description:      input.Description,
+		ReviewerStrategy: input.ReviewerStrategy,
+		DueAt:            input.DueAt,
+		RevokeOnExpiry:   input.RevokeOnExpiry,
+		Status:           entities.AccessReviewActive,
+		CreatedBy:        actorID,
+	}
+	switch {
+	case campaign.Name == "":
+		return nil, ErrAccessReviewNameRequired
+	case campaign.ReviewerStrategy != entities.ReviewerManager && campaign.ReviewerStrategy != entities.ReviewerRoleOwner:
+		return nil, ErrAccessReviewStrategyInvalid
+	case !campaign.DueAt.After(time.Now()):
+		return nil, ErrAccessReviewDueInvalid
+	}
+
+	roles, err := resolveRoles(i.roleRepo, input.Roles, ErrAccessReviewRoleNotFound)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	campaign.Roles = make([]string, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+		campaign.Roles = append(campaign.Roles, role.Name)
+	}
+
+	if fallback := strings.TrimSpace(input.FallbackReviewer); fallback != "" {
+		reviewer, err := i.userRepo.FindByUsernameOrEmail(fallback)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrAccessReviewReviewerNotFound
+			}
+			return nil, err
+		}
+		campaign.FallbackReviewerID = &reviewer.ID
+	}
+
+	if campaign.Items, err = i.snapshot(campaign, roleIDs); err != nil {
+		return nil, err
+	}
+	if len(campaign.Items) == 0 {
+		return nil, ErrAccessReviewEmpty
+	}
+	if err := i.reviewRepo.CreateCampaign(campaign); err != nil {
+		return nil, err
+	}
+
+	i.audit.Record(actorID, AuditAccessReviewCreated, AuditTargetAccessReview, campaign.ID.String(), map[string]any{
+		"name":              campaign.Name,
+		"roles":             campaign.Roles,
+		"reviewer_strategy": campaign.ReviewerStrategy,
+		"due_at":            campaign.DueAt,
+		"revoke_on_expiry":  campaign.RevokeOnExpiry,
+		"items":             len(campaign.Items),
+	})
+	return campaign, nil
+}
+
+// List mengembalikan semua kampanye tanpa itemnya, terbaru lebih dulu.
+func (i *AccessReviewInteractor) List() ([]entities.AccessReviewCampaign, error) {
+	return i.reviewRepo.FindCampaigns()
+}
+
+// Get mengembalikan kampanye beserta seluruh item dan keputusannya.
+func (i *AccessReviewInteractor) Get(id uuid.UUID) (*entities.AccessReviewCampaign, error) {
+	campaign, err := i.reviewRepo.FindCampaign(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccessReviewNotFound
+		}
+		return nil, err
+	}
+	return campaign, nil
+}
+
+// ListPending mengembalikan item yang menunggu keputusan reviewerID di kampanye yang masih aktif.
+func (i *AccessReviewInteractor) ListPending(reviewerID uuid.UUID) ([]entities.AccessReviewItem, error) {
+	return i.reviewRepo.FindPendingItemsByReviewer(reviewerID)
+}
+
+// Decide mencatat keputusan keep atau revoke reviewerID atas item. Hanya peninjau item atau superuser
+// yang boleh memutuskan; keputusan revoke langsung mencabut penetapan Role-nya.
+func (i *AccessReviewInteractor) Decide(reviewerID, itemID uuid.UUID, decision, comment string) (*entities.AccessReviewItem, error) {
+	if decision != entities.ReviewDecisionKeep && decision != entities.ReviewDecisionRevoke {
+		return nil, ErrAccessReviewDecisionInvalid
+	}
+
+	item, err := i.reviewRepo.FindItem(itemID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAccessReviewItemNotFound
+		}
+		return nil, err
+	}
+	if err := i.checkReviewer(reviewerID, item); err != nil {
+		return nil, err
+	}
+	campaign, err := i.Get(item.CampaignID)
+	if err != nil {
+		return nil, err
+	}
+	if !campaign.IsActive() {
+		return nil, ErrAccessReviewClosed
+	}
+	if !item.IsPending() {
+		return nil, ErrAccessReviewItemDecided
+	}
+
+	now := time.Now()
+	item.Decision = decision
+	item.Comment = strings.TrimSpace(comment)
+	item.DecidedBy = &reviewerID
+	item.DecidedAt = &now
+	decided, err := i.reviewRepo.DecideItem(item)
+	if err != nil {
+		return nil, err
+	}
+	if !decided {
+		return nil, ErrAccessReviewItemDecided
+	}
+
+	i.audit.Record(reviewerID, AuditAccessReviewDecided, AuditTargetAccessReview, campaign.ID.String(), itemAuditData(item))
+	if decision == entities.ReviewDecisionRevoke {
+		if err := i.revoke(reviewerID, item, now); err != nil {
+			return nil, err
+		}
+	}
+	return item, nil
+}
+
+// Close menyelesaikan kampanye sebelum batas waktunya. Item yang belum ditinjau diperlakukan sama seperti
+// saat kampanye berakhir.
+func (i *AccessReviewInteractor) Close(actorID, id uuid.UUID) (*entities.AccessReviewCampaign, error) {
+	campaign, err := i.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if !campaign.IsActive() {
+		return nil, ErrAccessReviewClosed
+	}
+	if _, err := i.complete(actorID, campaign, time.Now()); err != nil {
+		return nil, err
+	}
+	return i.Get(id)
+}
+
+// ExpireDue menyelesaikan kampanye yang batas waktunya sudah lewat pada now dan mengembalikan jumlahnya.
+// Dijalankan berkala oleh worker.
+func (i *AccessReviewInteractor) ExpireDue(now time.Time) (int, error) {
+	due, err := i.reviewRepo.FindDueCampaigns(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for n := range due {
+		campaign, err := i.Get(due[n].ID)
+		if err != nil {
+			return expired, err
+		}
+		completed, err := i.complete(uuid.Nil, campaign, now)
+		if err != nil {
+			return expired, err
+		}
+		if completed {
+			expired++
+		}
+	}
+	return expired, nil
+}
+
+// Export menyusun laporan CSV kampanye dan menandatanganinya dengan kunci penanda tangan token yang
+// sedang aktif, sehingga keasliannya bisa diverifikasi dengan kunci publik di JWKS.
+func (i *AccessReviewInteractor) Export(actorID, id uuid.UUID) (*AccessReviewReport, error) {
+	campaign, err := i.Get(id)
+	if err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	writer := csv.NewWriter(&buf)
+	if err := writer.Write(accessReviewReportHeader); err != nil {
+		return nil, err
+	}
+	for _, item := range campaign.Items {
+		record := []string{
+			campaign.ID.String(), reportText(campaign.Name), item.UserID.String(), reportText(item.Username),
+			item.RoleID.String(), reportText(item.RoleName), item.Source, reportID(item.GroupID), reportText(item.GroupName),
+			reportTime(item.GrantedAt), reportTime(item.ExpiresAt), reportID(item.ReviewerID), item.Decision,
+			reportText(item.Comment), reportID(item.DecidedBy), reportTime(item.DecidedAt), reportTime(item.RevokedAt),
+			reportID(item.OrganizationID), reportText(item.OrganizationName),
+		}
+		if err := writer.Write(record); err != nil {
+			return nil, err
+		}
+	}
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return nil, err
+	}
+
+	signature, err := i.keys.Sign(buf.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	i.audit.Record(actorID, AuditAccessReviewExported, AuditTargetAccessReview, campaign.ID.String(), map[string]any{
+		"items":  len(campaign.Items),
+		"key_id": signature.KeyID,
+	})
+	return &AccessReviewReport{
+		Filename:  fmt.Sprintf("access-review-%s.csv", campaign.ID),
+		Content:   buf.Bytes(),
+		Signature: signature,
+	}, nil
+}
+
+// snapshot menyusun item kampanye dari semua akses ke Role roleIDs (semua Role jika kosong), satu item
+// untuk setiap sumber akses, dan menentukan peninjau setiap item.
+func (i *AccessReviewInteractor) snapshot(campaign *entities.AccessReviewCampaign, roleIDs []uuid.UUID) ([]entities.AccessReviewItem, error) {
+	assignments, err := i.roleRepo.FindAllAssignments()
+	if err != nil {
+		return nil, err
+	}
+	grants, err := i.roleRepo.FindGrantsByRoles(roleIDs)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := i.roleRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	groups, err := i.groupRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	organizations, err := i.orgRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	users, err := i.userRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	inScope := make(map[uuid.UUID]bool, len(roleIDs))
+	for _, id := range roleIDs {
+		inScope[id] = true
+	}
+	rolesByID := make(map[uuid.UUID]*entities.Role, len(roles))
+	for n := range roles {
+		rolesByID[roles[n].ID] = &roles[n]
+	}
+	groupNames := make(map[uuid.UUID]string, len(groups))
+	for _, group := range groups {
+		groupNames[group.ID] = group.Name
+	}
+	organizationNames := make(map[uuid.UUID]string, len(organizations))
+	for _, organization := range organizations {
+		organizationNames[organization.ID] = organization.Name
+	}
+	grantsByKey := make(map[[2]uuid.UUID]entities.UserRole, len(grants))
+	for _, grant := range grants {
+		grantsByKey[[2]uuid.UUID{grant.UserID, grant.RoleID}] = grant
+	}
+	byID := make(map[uuid.UUID]*entities.User, len(users))
+	byUsername := make(map[string]*entities.User, len(users))
+	for n := range users {
+		byID[users[n].ID] = &users[n]
+		byUsername[users[n].Username] = &users[n]
+	}
+
+	// Satu item per pengguna, Role dan Group keanggotaan langsung atau organisasi; keanggotaan Group yang
+	// memberi Role secara langsung didahulukan dari yang memberi lewat Group induk
+	seen := make(map[[3]uuid.UUID]int, len(assignments))
+	items := make([]entities.AccessReviewItem, 0, len(assignments))
+	for _, assignment := range assignments {
+		if len(inScope) > 0 && !inScope[assignment.RoleID] {
+			continue
+		}
+		user, ok := byID[assignment.UserID]
+		role, found := rolesByID[assignment.RoleID]
+		if !ok || !found {
+			continue
+		}
+
+		item := entities.AccessReviewItem{
+			UserID:   user.ID,
+			Username: user.Username,
+			RoleID:   role.ID,
+			RoleName: role.Name,
+			Source:   entities.ReviewSourceDirect,
+		}
+		key := [3]uuid.UUID{user.ID, role.ID, uuid.Nil}
+		switch {
+		case assignment.OrganizationID != nil:
+			item.Source = entities.ReviewSourceOrganization
+			item.OrganizationID = assignment.OrganizationID
+			item.OrganizationName = organizationNames[*assignment.OrganizationID]
+			key[2] = *assignment.OrganizationID
+		case assignment.GroupID != nil:
+			item.Source = entities.ReviewSourceGroup
+			if assignment.Nested {
+				item.Source = entities.ReviewSourceNestedGroup
+			}
+			item.GroupID = assignment.GroupID
+			item.GroupName = groupNames[*assignment.GroupID]
+			key[2] = *assignment.GroupID
+		default:
+			grant, ok := grantsByKey[[2]uuid.UUID{user.ID, role.ID}]
+			if !ok {
+				continue
+			}
+			item.GrantedAt = grant.GrantedAt
+			item.ExpiresAt = grant.ExpiresAt
+		}
+		if n, ok := seen[key]; ok {
+			if items[n].Source == entities.ReviewSourceNestedGroup {
+				items[n].Source = item.Source
+			}
+			continue
+		}
+
+		var reviewerID *uuid.UUID
+		switch campaign.ReviewerStrategy {
+		case entities.ReviewerManager:
+			if manager := userManager(user, byID, byUsername); manager != nil {
+				reviewerID = &manager.ID
+			}
+		case entities.ReviewerRoleOwner:
+			reviewerID = role.OwnerID
+		}
+		// Pengguna tidak boleh meninjau aksesnya sendiri
+		if reviewerID == nil || *reviewerID == user.ID {
+			reviewerID = campaign.FallbackReviewerID
+		}
+		if reviewerID != nil && *reviewerID == user.ID {
+			reviewerID = nil
+		}
+		item.ReviewerID = reviewerID
+
+		seen[key] = len(items)
+		items = append(items, item)
+	}
+
+	sort.SliceStable(items, func(a, b int) bool {
+		if items[a].Username != items[b].Username {
+			return items[a].Username < items[b].Username
+		}
+		if items[a].RoleName != items[b].RoleName {
+			return items[a].RoleName < items[b].RoleName
+		}
+		if items[a].GroupName != items[b].GroupName {
+			return items[a].GroupName < items[b].GroupName
+		}
+		return items[a].OrganizationName < items[b].OrganizationName
+	})
+	return items, nil
+}
+
+// checkReviewer memastikan reviewerID adalah peninjau item atau superuser.
+func (i *AccessReviewInteractor) checkReviewer(reviewerID uuid.UUID, item *entities.AccessReviewItem) error {
+	if item.ReviewerID != nil && *item.ReviewerID == reviewerID {
+		return nil
+	}
+	reviewer, err := i.userRepo.FindByID(reviewerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAccessReviewForbidden
+		}
+		return err
+	}
+	if !reviewer.IsSuperuser {
+		return ErrAccessReviewForbidden
+	}
+	return nil
+}
+
+// complete menandai item yang belum ditinjau sebagai expired, mencabut aksesnya jika RevokeOnExpiry,
+// lalu menandai kampanye selesai. Pencabutan yang belum tercatat, misalnya karena kegagalan pada pemanggilan
+// sebelumnya, ikut dilanjutkan; kampanye baru selesai setelah semua pencabutan berhasil sehingga worker
+// mengulanginya jika gagal. Mengembalikan false jika kampanye sudah diselesaikan oleh proses lain.
+func (i *AccessReviewInteractor) complete(actorID uuid.UUID, campaign *entities.AccessReviewCampaign, now time.Time) (bool, error) {
+	expired, revoked := 0, 0
+	for n := range campaign.Items {
+		item := &campaign.Items[n]
+		if item.IsPending() {
+			item.Decision = entities.ReviewDecisionExpired
+			item.DecidedAt = &now
+			decided, err := i.reviewRepo.DecideItem(item)
+			if err != nil {
+				return false, err
+			}
+			if !decided {
+				// Diputuskan peninjau sesaat sebelum kampanye selesai; Decide yang menanganinya
+				item.Decision, item.DecidedAt = "", nil
+				continue
+			}
+			expired++
+		}
+		if !awaitsRevocation(campaign, item) {
+			continue
+		}
+		if err := i.revoke(actorID, item, now); err != nil {
+			return false, err
+		}
+		revoked++
+	}
+
+	completed, err := i.reviewRepo.CompleteCampaign(campaign.ID, now)
+	if err != nil || !completed {
+		return false, err
+	}
+	i.audit.Record(actorID, AuditAccessReviewCompleted, AuditTargetAccessReview, campaign.ID.String(), map[string]any{
+		"expired_items": expired,
+		"revoked_items": revoked,
+	})
+	return true, nil
+}
+
+// awaitsRevocation mengembalikan true jika akses item harus dicabut tetapi pencabutannya belum tercatat.
+func awaitsRevocation(campaign *entities.AccessReviewCampaign, item *entities.AccessReviewItem) bool {
+	if item.RevokedAt != nil {
+		return false
+	}
+	return item.Decision == entities.ReviewDecisionRevoke ||
+		(item.Decision == entities.ReviewDecisionExpired && campaign.RevokeOnExpiry)
+}
+
+// revoke mencabut akses item atas nama actorID: penetapan langsung dicabut, akses lewat Group dicabut
+// dengan mengeluarkan pengguna dari Group keanggotaan langsungnya, dan Role anggota dicabut dari
+// keanggotaan organisasinya. Akses yang sudah tidak ada (dicabut atau kedaluwarsa sejak snapshot)
+// dianggap sudah dicabut.
+func (i *AccessReviewInteractor) revoke(actorID uuid.UUID, item *entities.AccessReviewItem, now time.Time) error {
+	switch {
+	case item.OrganizationID != nil:
+		if err := i.revokeMemberRole(*item.OrganizationID, item.UserID, item.RoleID); err != nil {
+			return err
+		}
+	case item.GroupID != nil:
+		if err := i.groupRepo.RemoveMember(*item.GroupID, item.UserID); err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return err
+		}
+	default:
+		if err := i.grants.Revoke(item.UserID, item.RoleID, actorID); err != nil && !errors.Is(err, ErrRoleGrantNotFound) {
+			return err
+		}
+	}
+	if err := i.reviewRepo.MarkItemRevoked(item.ID, now); err != nil {
+		return err
+	}
+	item.RevokedAt = &now
+
+	i.audit.Record(actorID, AuditAccessReviewRevoked, AuditTargetAccessReview, item.CampaignID.String(), itemAuditData(item))
+	return nil
+}
+
+// revokeMemberRole mencabut Role roleID dari keanggotaan userID di organisasi. Keanggotaan lain dan
+// Role anggota lainnya tetap ada.
+func (i *AccessReviewInteractor) revokeMemberRole(organizationID, userID, roleID uuid.UUID) error {
+	member, err := i.memberRepo.Find(organizationID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+
+	kept := make([]uuid.UUID, 0, len(member.Roles))
+	for _, role := range member.Roles {
+		if role.ID != roleID {
+			kept = append(kept, role.ID)
+		}
+	}
+	if len(kept) == len(member.Roles) {
+		return nil
+	}
+	return i.memberRepo.ReplaceRoles(member.ID, kept)
+}
+
+// userManager mencari manajer pengguna dari atribut UserAttributeManager yang berisi ID atau username.
+func userManager(user *entities.User, byID map[uuid.UUID]*entities.User, byUsername map[string]*entities.User) *entities.User {
+	value, ok := user.Attributes[entities.UserAttributeManager].(string)
+	if !ok {
+		return nil
+	}
+	value = strings.TrimSpace(value)
+	if id, err := uuid.Parse(value); err == nil {
+		return byID[id]
+	}
+	return byUsername[value]
+}
+
+func itemAuditData(item *entities.AccessReviewItem) map[string]any {
+	data := map[string]any{
+		"item_id":  item.ID,
+		"user_id":  item.UserID,
+		"username": item.Username,
+		"role":     item.RoleName,
+		"source":   item.Source,
+		"decision": item.Decision,
+	}
+	if item.GroupID != nil {
+		data["group_id"] = item.GroupID
+		data["group"] = item.GroupName
+	}
+	if item.OrganizationID != nil {
+		data["organization_id"] = item.OrganizationID
+		data["organization"] = item.OrganizationName
+	}
+	if item.Comment != "" {
+		data["comment"] = item.Comment
+	}
+	return data
+}
+
+// reportText mengawali teks yang dimulai karakter formula spreadsheet dengan tanda kutip agar tidak
+// dieksekusi saat laporan dibuka di aplikasi spreadsheet (CSV injection).
+func reportText(value string) string {
+	if value != "" && strings.ContainsRune("=+-@\t\r", rune(value[0])) {
+		return "'" + value
+	}
+	return value
+}
+
+func reportTime(t *time.Time) string {
+	if t == nil {
+		return ""
+	}
+	return t.UTC().Format(time.RFC3339)
+}
+
+func reportID(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
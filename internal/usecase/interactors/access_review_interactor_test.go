@@ -0,0 +1,208 @@
+package interactors
+
+import (
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryAccessReviewRepository adalah AccessReviewRepository di memori dengan satu kampanye.
+type memoryAccessReviewRepository struct {
+	repositories.AccessReviewRepository
+	campaign entities.AccessReviewCampaign
+}
+
+func (r *memoryAccessReviewRepository) FindCampaign(id uuid.UUID) (*entities.AccessReviewCampaign, error) {
+	if r.campaign.ID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	campaign := r.campaign
+	campaign.Items = append([]entities.AccessReviewItem(nil), r.campaign.Items...)
+	return &campaign, nil
+}
+
+func (r *memoryAccessReviewRepository) FindDueCampaigns(now time.Time) ([]entities.AccessReviewCampaign, error) {
+	if !r.campaign.IsActive() || r.campaign.DueAt.After(now) {
+		return nil, nil
+	}
+	return []entities.AccessReviewCampaign{r.campaign}, nil
+}
+
+func (r *memoryAccessReviewRepository) CompleteCampaign(id uuid.UUID, at time.Time) (bool, error) {
+	if r.campaign.ID != id || !r.campaign.IsActive() {
+		return false, nil
+	}
+	r.campaign.Status = entities.AccessReviewCompleted
+	r.campaign.CompletedAt = &at
+	return true, nil
+}
+
+func (r *memoryAccessReviewRepository) DecideItem(item *entities.AccessReviewItem) (bool, error) {
+	for n := range r.campaign.Items {
+		stored := &r.campaign.Items[n]
+		if stored.ID == item.ID && stored.IsPending() {
+			stored.Decision, stored.DecidedAt = item.Decision, item.DecidedAt
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+func (r *memoryAccessReviewRepository) MarkItemRevoked(id uuid.UUID, at time.Time) error {
+	for n := range r.campaign.Items {
+		if r.campaign.Items[n].ID == id {
+			r.campaign.Items[n].RevokedAt = &at
+		}
+	}
+	return nil
+}
+
+// failingGroupRepository adalah GroupRepository uji yang menggagalkan RemoveMember sebanyak failures kali.
+type failingGroupRepository struct {
+	repositories.GroupRepository
+	failures int
+	removed  []uuid.UUID
+}
+
+func (r *failingGroupRepository) RemoveMember(_, userID uuid.UUID) error {
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("koneksi terputus")
+	}
+	r.removed = append(r.removed, userID)
+	return nil
+}
+
+func (r *failingGroupRepository) FindAll() ([]entities.Group, error) {
+	return nil, nil
+}
+
+// discardAuditLogRepository adalah AuditLogRepository uji yang mengabaikan semua entri.
+type discardAuditLogRepository struct {
+	repositories.AuditLogRepository
+}
+
+func (discardAuditLogRepository) Create(*entities.AuditLog) error {
+	return nil
+}
+
+func TestExpireDueResumesFailedRevocation(t *testing.T) {
+	groupID, userID := uuid.New(), uuid.New()
+	reviews := &memoryAccessReviewRepository{campaign: entities.AccessReviewCampaign{
+		ID:             uuid.New(),
+		Status:         entities.AccessReviewActive,
+		DueAt:          time.Now().Add(-time.Hour),
+		RevokeOnExpiry: true,
+		Items: []entities.AccessReviewItem{{
+			ID:      uuid.New(),
+			UserID:  userID,
+			RoleID:  uuid.New(),
+			Source:  entities.ReviewSourceGroup,
+			GroupID: &groupID,
+		}},
+	}}
+	groups := &failingGroupRepository{failures: 1}
+	interactor := NewAccessReviewInteractor(reviews, nil, groups, nil, nil, nil, nil, NewAuditInteractor(discardAuditLogRepository{}), nil)
+
+	// Pencabutan gagal: kampanye tetap aktif agar worker mengulanginya
+	if _, err := interactor.ExpireDue(time.Now()); err == nil {
+		t.Fatal("ExpireDue tidak mengembalikan kegagalan pencabutan")
+	}
+	if !reviews.campaign.IsActive() {
+		t.Fatal("kampanye ditandai selesai sebelum aksesnya dicabut")
+	}
+
+	// Item sudah expired sehingga tidak lagi pending; pencabutannya tetap dilanjutkan
+	expired, err := interactor.ExpireDue(time.Now())
+	if err != nil {
+		t.Fatalf("ExpireDue: %v", err)
+	}
+	if expired != 1 || reviews.campaign.IsActive() {
+		t.Fatalf("expired = %d, status = %s; ingin 1 kampanye selesai", expired, reviews.campaign.Status)
+	}
+	if len(groups.removed) != 1 || groups.removed[0] != userID {
+		t.Fatalf("anggota yang dikeluarkan = %v, ingin %v", groups.removed, userID)
+	}
+	if item := reviews.campaign.Items[0]; item.Decision != entities.ReviewDecisionExpired || item.RevokedAt == nil {
+		t.Fatalf("item = %+v, ingin expired dan dicabut", item)
+	}
+}
+
+func TestAccessReviewCoversOrganizationMemberRoles(t *testing.T) {
+	admin := &entities.User{ID: uuid.New(), Username: "admin"}
+	alice := &entities.User{ID: uuid.New(), Username: "alice"}
+	users := newMemoryUserRepository(admin, alice)
+	roleRepo := &memoryRoleRepository{users: users, roles: []entities.Role{
+		{ID: uuid.New(), Name: "billing"},
+		{ID: uuid.New(), Name: "viewer"},
+	}}
+	billing, viewer := &roleRepo.roles[0], &roleRepo.roles[1]
+	members := &memoryOrganizationMemberRepository{roles: roleRepo}
+	roleRepo.members = members
+	acme := entities.Organization{ID: uuid.New(), Name: "Acme"}
+	alice.Roles = []*entities.Role{viewer}
+	if err := members.Create(&entities.OrganizationMember{OrganizationID: acme.ID, UserID: alice.ID, Roles: []*entities.Role{billing, viewer}}); err != nil {
+		t.Fatalf("Create: %v", err)
+	}
+
+	reviews := &memoryAccessReviewRepository{}
+	interactor := NewAccessReviewInteractor(reviews, roleRepo, &failingGroupRepository{}, &staticOrganizationRepository{organizations: []entities.Organization{acme}},
+		members, users, nil, NewAuditInteractor(discardAuditLogRepository{}), nil)
+	campaign := &entities.AccessReviewCampaign{ReviewerStrategy: entities.ReviewerManager, FallbackReviewerID: &admin.ID}
+
+	items, err := interactor.snapshot(campaign, nil)
+	if err != nil {
+		t.Fatalf("snapshot: %v", err)
+	}
+	var got []string
+	for _, item := range items {
+		got = append(got, item.RoleName+"/"+item.Source+"/"+item.OrganizationName)
+	}
+	want := []string{"billing/organization/Acme", "viewer/direct/", "viewer/organization/Acme"}
+	if strings.Join(got, ",") != strings.Join(want, ",") {
+		t.Fatalf("item = %v, ingin %v", got, want)
+	}
+
+	// Mencabut Role anggota hanya menghapusnya dari keanggotaan organisasi; Role global tetap ada
+	for range 2 {
+		if err := interactor.revoke(admin.ID, &items[0], time.Now()); err != nil {
+			t.Fatalf("revoke: %v", err)
+		}
+	}
+	if member, _ := members.Find(acme.ID, alice.ID); len(member.Roles) != 1 || member.Roles[0].ID != viewer.ID {
+		t.Fatalf("role anggota = %v, ingin [viewer]", member.Roles)
+	}
+	if stored, _ := users.FindByID(alice.ID); len(stored.Roles) != 1 || stored.Roles[0].ID != viewer.ID {
+		t.Fatalf("role global = %v, ingin [viewer]", stored.Roles)
+	}
+}
+
+func TestReportTextEscapesFormulas(t *testing.T) {
+	tests := []struct {
+		value string
+		want  string
+	}{
+		{"alice", "alice"},
+		{"", ""},
+		{"=HYPERLINK(\"http://evil\")", "'=HYPERLINK(\"http://evil\")"},
+		{"+1", "'+1"},
+		{"-1+2", "'-1+2"},
+		{"@SUM(A1)", "'@SUM(A1)"},
+		{"\t=1", "'\t=1"},
+		{"a=b", "a=b"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.value, func(t *testing.T) {
+			if got := reportText(tt.value); got != tt.want {
+				t.Fatalf("reportText(%q) = %q, ingin %q", tt.value, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,315 @@
+package interactors
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"errors"
+	"log"
+	"math/big"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidAPIKey dikembalikan jika API key tidak dikenal, salah, dicabut atau kedaluwarsa.
+	ErrInvalidAPIKey = errors.New("API key tidak valid")
+	// ErrAPIKeyNotFound dikembalikan jika API key tidak ada, bukan milik pengguna, atau sudah dicabut.
+	ErrAPIKeyNotFound = errors.New("API key tidak ditemukan")
+	// ErrAPIKeyNameRequired dikembalikan jika nama API key kosong.
+	ErrAPIKeyNameRequired = errors.New("nama API key wajib diisi")
+	// ErrAPIKeyScopesRequired dikembalikan jika API key dibuat tanpa scope.
+	ErrAPIKeyScopesRequired = errors.New("API key harus memiliki setidaknya satu scope")
+	// ErrAPIKeyExpiryTooLong dikembalikan jika masa berlaku yang diminta melebihi batas.
+	ErrAPIKeyExpiryTooLong = errors.New("masa berlaku API key melebihi batas")
+)
+
+// ScopeNotGrantedError dikembalikan jika scope yang diminta tidak dimiliki oleh pemilik API key.
+type ScopeNotGrantedError struct {
+	Scope string
+}
+
+func (e *ScopeNotGrantedError) Error() string {
+	return "scope " + e.Scope + " tidak dimiliki oleh pemilik API key"
+}
+
+const (
+	// apiKeyLookupLength adalah panjang bagian pencarian yang disimpan apa adanya.
+	apiKeyLookupLength = 8
+	// apiKeySecretLength adalah panjang bagian rahasia (~190 bit entropi).
+	apiKeySecretLength = 32
+	// apiKeyAlphabet hanya berisi karakter alfanumerik agar kunci mudah disalin.
+	apiKeyAlphabet = "ABCDEFGHIJKLMNOPQRSTUVWXYZabcdefghijklmnopqrstuvwxyz0123456789"
+	// apiKeyTouchInterval membatasi seberapa sering pemakaian terakhir ditulis ke database.
+	apiKeyTouchInterval = time.Minute
+)
+
+// APIKeyPolicy adalah aturan pembuatan API key.
+type APIKeyPolicy struct {
+	Prefix          string        // Penanda kunci, misalnya "umk" untuk umk_<lookup>_<secret>
+	DefaultLifetime time.Duration // Dipakai jika klien tidak meminta masa berlaku
+	MaxLifetime     time.Duration // Batas atas masa berlaku
+}
+
+// APIKeyInteractor adalah use case untuk API key (personal access token) milik pengguna dan service account.
+type APIKeyInteractor struct {
+	apiKeyRepo     repositories.APIKeyRepository
+	userRepo       repositories.UserRepository
+	permissionRepo repositories.PermissionRepository
+	memberRepo     repositories.OrganizationMemberRepository
+	policy         APIKeyPolicy
+}
+
+// NewAPIKeyInteractor membuat instance baru dari APIKeyInteractor.
+func NewAPIKeyInteractor(
+	kr repositories.APIKeyRepository,
+	ur repositories.UserRepository,
+	pr repositories.PermissionRepository,
+	mr repositories.OrganizationMemberRepository,
+	policy APIKeyPolicy,
+) *APIKeyInteractor {
+	return &APIKeyInteractor{apiKeyRepo: kr, userRepo: ur, permissionRepo: pr, memberRepo: mr, policy: policy}
+}
+
+// Create membuat API key baru untuk pengguna dan mengembalikan kunci plaintext yang hanya ditampilkan sekali.
+// Setiap scope harus merupakan permission yang dimiliki pemilik kunci; superuser boleh memakai scope apa pun.
+// expiresIn nol berarti masa berlaku default. organizationID selain uuid.Nil mengikat kunci ke organisasi tersebut;
+// pemilik harus anggotanya dan scope diperiksa terhadap Role pemilik di organisasi itu.
+func (i *APIKeyInteractor) Create(userID, organizationID uuid.UUID, name string, scopes []string, expiresIn time.Duration) (*entities.APIKey, string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, "", ErrAPIKeyNameRequired
+	}
+
+	scopes = normalizeScopes(scopes)
+	if len(scopes) == 0 {
+		return nil, "", ErrAPIKeyScopesRequired
+	}
+
+	if expiresIn <= 0 {
+		expiresIn = i.policy.DefaultLifetime
+	}
+	if expiresIn > i.policy.MaxLifetime {
+		return nil, "", ErrAPIKeyExpiryTooLong
+	}
+
+	user, err := i.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", errors.New("pengguna tidak ditemukan")
+		}
+		return nil, "", err
+	}
+	if err := i.checkScopesGranted(user, organizationID, scopes); err != nil {
+		return nil, "", err
+	}
+
+	lookup, err := randomString(apiKeyLookupLength)
+	if err != nil {
+		return nil, "", err
+	}
+	secret, err := randomString(apiKeySecretLength)
+	if err != nil {
+		return nil, "", err
+	}
+	plain := i.policy.Prefix + "_" + lookup + "_" + secret
+
+	expiresAt := time.Now().Add(expiresIn)
+	key := &entities.APIKey{
+		UserID:    user.ID,
+		Name:      name,
+		Prefix:    lookup,
+		KeyHash:   hashSecret(plain),
+		Scopes:    scopes,
+		ExpiresAt: &expiresAt,
+	}
+	if organizationID != uuid.Nil {
+		key.OrganizationID = &organizationID
+	}
+	if err := i.apiKeyRepo.Create(key); err != nil {
+		return nil, "", err
+	}
+	return key, plain, nil
+}
+
+// List mengembalikan semua API key milik pengguna, termasuk yang sudah dicabut atau kedaluwarsa.
+func (i *APIKeyInteractor) List(userID uuid.UUID) ([]entities.APIKey, error) {
+	return i.apiKeyRepo.FindByUser(userID)
+}
+
+// Revoke mencabut API key milik pengguna. Kunci langsung ditolak pada permintaan berikutnya.
+func (i *APIKeyInteractor) Revoke(userID, id uuid.UUID) error {
+	if err := i.apiKeyRepo.Revoke(userID, id, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAPIKeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// IsAPIKey mengembalikan true jika token berbentuk API key, bukan token login.
+func (i *APIKeyInteractor) IsAPIKey(token string) bool {
+	return strings.HasPrefix(token, i.policy.Prefix+"_")
+}
+
+// Authenticate memverifikasi API key plaintext dan mengembalikan kunci beserta pemiliknya.
+// Pemakaian terakhir dicatat paling sering sekali per apiKeyTouchInterval.
+func (i *APIKeyInteractor) Authenticate(plain, ip string) (*entities.APIKey, *entities.User, error) {
+	key, user, err := i.Inspect(plain)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	now := time.Now()
+	if key.LastUsedAt == nil || now.Sub(*key.LastUsedAt) >= apiKeyTouchInterval || key.LastUsedIP != ip {
+		if err := i.apiKeyRepo.Touch(key.ID, now, ip); err != nil {
+			log.Printf("Gagal mencatat pemakaian API key %s: %v", key.ID, err)
+		}
+	}
+	return key, user, nil
+}
+
+// Inspect memverifikasi API key plaintext tanpa mencatat pemakaian, misalnya untuk introspeksi token.
+// Kunci yang dicabut, kedaluwarsa atau pemiliknya tidak aktif ditolak dengan ErrInvalidAPIKey.
+func (i *APIKeyInteractor) Inspect(plain string) (*entities.APIKey, *entities.User, error) {
+	lookup, ok := i.parseLookup(plain)
+	if !ok {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	key, err := i.apiKeyRepo.FindByPrefix(lookup)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+
+	if subtle.ConstantTimeCompare([]byte(key.KeyHash), []byte(hashSecret(plain))) != 1 {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	if !key.IsActive(time.Now()) {
+		return nil, nil, ErrInvalidAPIKey
+	}
+
+	user, err := i.userRepo.FindByID(key.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrInvalidAPIKey
+		}
+		return nil, nil, err
+	}
+	if !user.IsActive {
+		return nil, nil, ErrInvalidAPIKey
+	}
+	return key, user, nil
+}
+
+// checkScopesGranted memastikan pemilik memiliki setiap permission yang diminta sebagai scope,
+// di organisasi organizationID jika kunci terikat ke organisasi.
+func (i *APIKeyInteractor) checkScopesGranted(user *entities.User, organizationID uuid.UUID, scopes []string) error {
+	if user.IsSuperuser {
+		return nil
+	}
+	if organizationID != uuid.Nil {
+		if _, err := i.memberRepo.Find(organizationID, user.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrNotOrganizationMember
+			}
+			return err
+		}
+	}
+
+	var granted []string
+	var err error
+	if organizationID != uuid.Nil {
+		granted, err = i.permissionRepo.FindNamesByMember(organizationID, user.ID)
+	} else {
+		granted, err = i.permissionRepo.FindNamesByUser(user.ID)
+	}
+	if err != nil {
+		return err
+	}
+	held := entities.NewPermissionSet(granted)
+
+	for _, scope := range scopes {
+		if scope == entities.ScopeAll {
+			continue // Berarti semua permission pemilik, dievaluasi ulang setiap permintaan
+		}
+		pattern, ok := entities.ParsePermission(scope)
+		if !ok {
+			return &ScopeNotGrantedError{Scope: scope}
+		}
+		if pattern.Deny {
+			continue // Deny hanya mempersempit kunci
+		}
+		// Scope wildcard hanya boleh jika pemilik memiliki pola yang sama persis; scope konkret cukup
+		// dicakup permission pemilik
+		if !containsString(granted, scope) && !held.Allows(scope) {
+			return &ScopeNotGrantedError{Scope: scope}
+		}
+	}
+	return nil
+}
+
+// parseLookup mengambil bagian pencarian dari kunci berformat <prefix>_<lookup>_<secret>.
+func (i *APIKeyInteractor) parseLookup(plain string) (string, bool) {
+	rest, ok := strings.CutPrefix(plain, i.policy.Prefix+"_")
+	if !ok {
+		return "", false
+	}
+	lookup, secret, ok := strings.Cut(rest, "_")
+	if !ok || len(lookup) != apiKeyLookupLength || len(secret) != apiKeySecretLength {
+		return "", false
+	}
+	return lookup, true
+}
+
+// normalizeScopes memangkas spasi, membuang scope kosong dan duplikat.
+func normalizeScopes(scopes []string) []string {
+	seen := make(map[string]struct{}, len(scopes))
+	normalized := make([]string, 0, len(scopes))
+	for _, scope := range scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		normalized = append(normalized, scope)
+	}
+	return normalized
+}
+
+// randomString membuat string acak dari apiKeyAlphabet dengan crypto/rand.
+func randomString(length int) (string, error) {
+	alphabetSize := big.NewInt(int64(len(apiKeyAlphabet)))
+
+	var value strings.Builder
+	for n := 0; n < length; n++ {
+		idx, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		value.WriteByte(apiKeyAlphabet[idx.Int64()])
+	}
+	return value.String(), nil
+}
+
+// hashSecret menghitung SHA-256 dari rahasia plaintext (API key, secret dan token OAuth).
+// SHA-256 cukup karena rahasia dibuat acak dengan entropi tinggi, bukan dipilih pengguna.
+func hashSecret(plain string) string {
+	sum := sha256.Sum256([]byte(plain))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,558 @@
+package interactors
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrApprovalPolicyNotFound dikembalikan jika kebijakan persetujuan tidak ada.
+	ErrApprovalPolicyNotFound = errors.New("kebijakan persetujuan tidak ditemukan")
+	// ErrApprovalPolicyExists dikembalikan jika Role (atau status superuser) sudah punya kebijakan persetujuan.
+	ErrApprovalPolicyExists = errors.New("kebijakan persetujuan untuk target ini sudah ada")
+	// ErrApprovalPolicyInvalid dikembalikan jika jumlah persetujuan kurang dari satu.
+	ErrApprovalPolicyInvalid = errors.New("jumlah persetujuan minimal 1")
+	// ErrApprovalRoleNotFound dikembalikan jika Role target atau Role peninjau tidak terdaftar.
+	ErrApprovalRoleNotFound = errors.New("role tidak ditemukan")
+	// ErrAssignmentNotFound dikembalikan jika permintaan penetapan tidak ada.
+	ErrAssignmentNotFound = errors.New("permintaan penetapan tidak ditemukan")
+	// ErrAssignmentUserNotFound dikembalikan jika penerima penetapan tidak ada.
+	ErrAssignmentUserNotFound = errors.New("pengguna tidak ditemukan")
+	// ErrAssignmentDuplicate dikembalikan jika masih ada permintaan yang menunggu untuk penetapan yang sama.
+	ErrAssignmentDuplicate = errors.New("masih ada permintaan yang menunggu untuk penetapan ini")
+	// ErrAssignmentNotPending dikembalikan jika permintaan sudah diterapkan, ditolak atau dibatalkan.
+	ErrAssignmentNotPending = errors.New("permintaan penetapan sudah diputuskan atau dibatalkan")
+	// ErrAssignmentForbidden dikembalikan jika peninjau tidak memegang Role peninjau kebijakan.
+	ErrAssignmentForbidden = errors.New("tidak berwenang memutuskan permintaan penetapan")
+	// ErrAssignmentSelfReview dikembalikan jika pengaju atau penerima mencoba memutuskan permintaannya sendiri.
+	ErrAssignmentSelfReview = errors.New("pengaju dan penerima tidak boleh memutuskan permintaan penetapan")
+	// ErrAssignmentAlreadyDecided dikembalikan jika peninjau sudah memutuskan permintaan ini.
+	ErrAssignmentAlreadyDecided = errors.New("peninjau sudah memutuskan permintaan ini")
+	// ErrAssignmentCommentRequired dikembalikan jika penolakan tidak disertai komentar.
+	ErrAssignmentCommentRequired = errors.New("komentar wajib diisi saat menolak")
+	// ErrAssignmentAlreadySuperuser dikembalikan jika penerima sudah superuser.
+	ErrAssignmentAlreadySuperuser = errors.New("pengguna sudah superuser")
+	// ErrAssignmentWindowUnsupported dikembalikan jika status superuser diminta dengan batas waktu.
+	ErrAssignmentWindowUnsupported = errors.New("status superuser tidak bisa dibatasi waktu")
+	// ErrAssignmentReasonRequired dikembalikan jika alasan permintaan penetapan kosong.
+	ErrAssignmentReasonRequired = errors.New("alasan permintaan penetapan wajib diisi")
+)
+
+// Tindakan yang dicatat di jejak audit alur persetujuan.
+const (
+	AuditApprovalPolicyCreated = "approval_policy.created"
+	AuditApprovalPolicyUpdated = "approval_policy.updated"
+	AuditApprovalPolicyDeleted = "approval_policy.deleted"
+	AuditAssignmentRequested   = "assignment.requested"
+	AuditAssignmentApproval    = "assignment.approval_recorded"
+	AuditAssignmentApproved    = "assignment.approved"
+	AuditAssignmentRejected    = "assignment.rejected"
+	AuditAssignmentCancelled   = "assignment.cancelled"
+)
+
+// defaultSuperuserPolicy berlaku jika belum ada kebijakan untuk status superuser: superuser lain harus
+// menyetujui, sehingga pemberian superuser tidak pernah menjadi tindakan satu orang.
+var defaultSuperuserPolicy = entities.ApprovalPolicy{RequiredApprovals: 1}
+
+// ApprovalPolicyInput adalah isian untuk membuat atau mengganti kebijakan persetujuan. Role kosong
+// berarti kebijakan status superuser; Role tidak bisa diubah setelah kebijakan dibuat.
+type ApprovalPolicyInput struct {
+	Role              string
+	RequiredApprovals int
+	ApproverRoles     []string
+}
+
+// AssignmentInput adalah isian permintaan penetapan sensitif: Role (dengan jendela waktu opsional) atau
+// status superuser.
+type AssignmentInput struct {
+	UserID    uuid.UUID
+	Superuser bool
+	Grant     RoleGrantInput
+}
+
+// ApprovalInteractor adalah use case untuk alur persetujuan penetapan sensitif. Role yang punya
+// ApprovalPolicy dan status superuser baru diberikan setelah jumlah persetujuan kebijakannya terpenuhi;
+// satu penolakan menghentikan permintaan. Setiap langkah dicatat di jejak audit.
+type ApprovalInteractor struct {
+	policyRepo  repositories.ApprovalPolicyRepository
+	requestRepo repositories.AssignmentRequestRepository
+	roleRepo    repositories.RoleRepository
+	userRepo    repositories.UserRepository
+	grants      *RoleGrantInteractor
+	audit       *AuditInteractor
+}
+
+// NewApprovalInteractor membuat instance baru dari ApprovalInteractor.
+func NewApprovalInteractor(
+	pr repositories.ApprovalPolicyRepository,
+	qr repositories.AssignmentRequestRepository,
+	rr repositories.RoleRepository,
+	ur repositories.UserRepository,
+	grants *RoleGrantInteractor,
+	audit *AuditInteractor,
+) *ApprovalInteractor {
+	return &ApprovalInteractor{
+		policyRepo:  pr,
+		requestRepo: qr,
+		roleRepo:    rr,
+		userRepo:    ur,
+		grants:      grants,
+		audit:       audit,
+	}
+}
+
+// CreatePolicy membuat kebijakan persetujuan untuk Role atau status superuser.
+func (i *ApprovalInteractor) CreatePolicy(actorID uuid.UUID, input ApprovalPolicyInput) (*entities.ApprovalPolicy, error) {
+	policy := &entities.ApprovalPolicy{}
+	if name := strings.TrimSpace(input.Role); name != "" {
+		role, err := i.roleRepo.FindByName(name)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, fmt.Errorf("%w: %s", ErrApprovalRoleNotFound, name)
+			}
+			return nil, err
+		}
+		policy.RoleID = &role.ID
+		policy.Role = role
+	}
+
+	if _, err := i.policyRepo.FindByRole(policy.RoleID); err == nil {
+		return nil, ErrApprovalPolicyExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := i.applyPolicy(policy, input); err != nil {
+		return nil, err
+	}
+	if err := i.policyRepo.Create(policy); err != nil {
+		return nil, err
+	}
+
+	i.audit.Record(actorID, AuditApprovalPolicyCreated, AuditTargetApprovalPolicy, policy.ID.String(), policyAuditData(policy))
+	return policy, nil
+}
+
+// ListPolicies mengembalikan semua kebijakan persetujuan.
+func (i *ApprovalInteractor) ListPolicies() ([]entities.ApprovalPolicy, error) {
+	return i.policyRepo.FindAll()
+}
+
+// UpdatePolicy mengganti jumlah persetujuan dan Role peninjau kebijakan. Permintaan yang sedang menunggu
+// tetap memakai aturan saat diajukan.
+func (i *ApprovalInteractor) UpdatePolicy(actorID, id uuid.UUID, input ApprovalPolicyInput) (*entities.ApprovalPolicy, error) {
+	policy, err := i.policyRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApprovalPolicyNotFound
+		}
+		return nil, err
+	}
+	if err := i.applyPolicy(policy, input); err != nil {
+		return nil, err
+	}
+	if err := i.policyRepo.Update(policy); err != nil {
+		return nil, err
+	}
+
+	i.audit.Record(actorID, AuditApprovalPolicyUpdated, AuditTargetApprovalPolicy, policy.ID.String(), policyAuditData(policy))
+	return policy, nil
+}
+
+// DeletePolicy menghapus kebijakan persetujuan sehingga Role bisa diberikan langsung lagi.
+func (i *ApprovalInteractor) DeletePolicy(actorID, id uuid.UUID) error {
+	if err := i.policyRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrApprovalPolicyNotFound
+		}
+		return err
+	}
+
+	i.audit.Record(actorID, AuditApprovalPolicyDeleted, AuditTargetApprovalPolicy, id.String(), nil)
+	return nil
+}
+
+// AssignRole menetapkan Role ke pengguna atas nama requesterID. Jika Role punya kebijakan persetujuan,
+// permintaan penetapan dibuat dan dikembalikan sebagai gantinya.
+func (i *ApprovalInteractor) AssignRole(requesterID, userID uuid.UUID, input RoleGrantInput) (*entities.UserRole, *entities.AssignmentRequest, error) {
+	grant, err := i.grants.Grant(userID, input, requesterID)
+	if !errors.Is(err, ErrRoleGrantApprovalRequired) {
+		return grant, nil, err
+	}
+
+	request, err := i.Submit(requesterID, AssignmentInput{UserID: userID, Grant: input})
+	return nil, request, err
+}
+
+// Submit membuat permintaan penetapan yang menunggu persetujuan sesuai kebijakan target.
+func (i *ApprovalInteractor) Submit(requesterID uuid.UUID, input AssignmentInput) (*entities.AssignmentRequest, error) {
+	reason := strings.TrimSpace(input.Grant.Reason)
+	if reason == "" {
+		return nil, ErrAssignmentReasonRequired
+	}
+	user, err := i.userRepo.FindByID(input.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAssignmentUserNotFound
+		}
+		return nil, err
+	}
+
+	request := &entities.AssignmentRequest{
+		UserID:      user.ID,
+		Reason:      reason,
+		RequestedBy: requesterID,
+		Status:      entities.AssignmentPending,
+	}
+	if input.Superuser {
+		if input.Grant.StartsAt != nil || input.Grant.ExpiresAt != nil || input.Grant.Hours != 0 {
+			return nil, ErrAssignmentWindowUnsupported
+		}
+		if user.IsSuperuser {
+			return nil, ErrAssignmentAlreadySuperuser
+		}
+	} else {
+		window := input.Grant
+		if err := resolveGrantWindow(&window, time.Now()); err != nil {
+			return nil, err
+		}
+		role, err := i.grants.findRole(input.Grant.Role)
+		if err != nil {
+			return nil, err
+		}
+		if err := i.grants.CheckConflicts(user.ID, role.ID); err != nil {
+			return nil, err
+		}
+		request.RoleID = &role.ID
+		request.Role = role
+		request.StartsAt = input.Grant.StartsAt
+		request.ExpiresAt = input.Grant.ExpiresAt
+		request.Hours = input.Grant.Hours // Dihitung saat penetapan diterapkan, bukan saat diajukan
+	}
+
+	policy, err := i.policyFor(request.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	request.RequiredApprovals = policy.RequiredApprovals
+	request.ApproverRoles = policy.ApproverRoles
+
+	if _, err := i.requestRepo.FindPendingFor(request.UserID, request.RoleID); err == nil {
+		return nil, ErrAssignmentDuplicate
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err := i.requestRepo.Create(request); err != nil {
+		return nil, err
+	}
+	request.User = user
+
+	i.record(requesterID, AuditAssignmentRequested, request, "")
+	return request, nil
+}
+
+// List mengembalikan permintaan penetapan dengan status tertentu (kosong untuk semua). Superuser melihat
+// semua permintaan; pengguna lain hanya melihat permintaan yang boleh mereka putuskan atau mereka ajukan.
+func (i *ApprovalInteractor) List(viewerID uuid.UUID, status string) ([]entities.AssignmentRequest, error) {
+	viewer, err := i.userRepo.FindByID(viewerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAssignmentForbidden
+		}
+		return nil, err
+	}
+	requests, err := i.requestRepo.FindByStatus(status)
+	if err != nil || viewer.IsSuperuser {
+		return requests, err
+	}
+
+	visible := make([]entities.AssignmentRequest, 0, len(requests))
+	for _, request := range requests {
+		if request.RequestedBy == viewerID || canReviewAssignment(viewer, &request) {
+			visible = append(visible, request)
+		}
+	}
+	return visible, nil
+}
+
+// Approve mencatat persetujuan reviewerID beserta komentarnya. Jika jumlah persetujuan kebijakan sudah
+// terpenuhi, penetapan langsung diterapkan.
+func (i *ApprovalInteractor) Approve(reviewerID, id uuid.UUID, comment string) (*entities.AssignmentRequest, error) {
+	request, err := i.findForReview(reviewerID, id)
+	if err != nil {
+		return nil, err
+	}
+	comment = strings.TrimSpace(comment)
+	if err := i.addDecision(request, reviewerID, true, comment); err != nil {
+		return nil, err
+	}
+	i.record(reviewerID, AuditAssignmentApproval, request, comment)
+
+	// Baca ulang agar persetujuan peninjau lain yang masuk bersamaan ikut terhitung
+	request, err = i.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if !request.IsPending() || request.Approvals() < request.RequiredApprovals {
+		return request, nil
+	}
+	if err := i.apply(request); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+// Reject mencatat penolakan reviewerID dan menghentikan permintaan. Komentar wajib diisi.
+func (i *ApprovalInteractor) Reject(reviewerID, id uuid.UUID, comment string) (*entities.AssignmentRequest, error) {
+	comment = strings.TrimSpace(comment)
+	if comment == "" {
+		return nil, ErrAssignmentCommentRequired
+	}
+	request, err := i.findForReview(reviewerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.addDecision(request, reviewerID, false, comment); err != nil {
+		return nil, err
+	}
+	if err := i.finish(request, entities.AssignmentRejected); err != nil {
+		return nil, err
+	}
+
+	i.record(reviewerID, AuditAssignmentRejected, request, comment)
+	return request, nil
+}
+
+// Cancel membatalkan permintaan yang masih menunggu. Hanya pengaju yang bisa membatalkan.
+func (i *ApprovalInteractor) Cancel(requesterID, id uuid.UUID) error {
+	request, err := i.find(id)
+	if err != nil {
+		return err
+	}
+	if request.RequestedBy != requesterID {
+		return ErrAssignmentNotFound
+	}
+	if err := i.finish(request, entities.AssignmentCancelled); err != nil {
+		return err
+	}
+
+	i.record(requesterID, AuditAssignmentCancelled, request, "")
+	return nil
+}
+
+// apply menerapkan penetapan yang kebijakannya sudah terpenuhi. Status diperbarui lebih dulu agar
+// persetujuan yang masuk bersamaan tidak menerapkan penetapan dua kali, sehingga aturan pemisahan tugas
+// diperiksa ulang sebelumnya. Jika penetapan gagal, permintaan dikembalikan ke status menunggu agar
+// tidak tercatat disetujui tanpa Role-nya.
+func (i *ApprovalInteractor) apply(request *entities.AssignmentRequest) error {
+	if request.RoleID != nil {
+		if err := i.grants.CheckConflicts(request.UserID, *request.RoleID); err != nil {
+			return err
+		}
+	}
+	if err := i.finish(request, entities.AssignmentApproved); err != nil {
+		return err
+	}
+
+	if err := i.assign(request); err != nil {
+		if reopenErr := i.requestRepo.Reopen(request); reopenErr != nil {
+			log.Printf("Gagal mengembalikan permintaan penetapan %s ke status menunggu: %v", request.ID, reopenErr)
+		} else {
+			request.Status = entities.AssignmentPending
+			request.DecidedAt = nil
+		}
+		return err
+	}
+
+	i.record(uuid.Nil, AuditAssignmentApproved, request, "")
+	return nil
+}
+
+// assign memberikan status superuser atau Role yang diminta tanpa memeriksa kebijakan persetujuan.
+func (i *ApprovalInteractor) assign(request *entities.AssignmentRequest) error {
+	if request.IsSuperuser() {
+		user, err := i.userRepo.FindByID(request.UserID)
+		if err != nil {
+			return err
+		}
+		user.IsSuperuser = true
+		_, err = i.userRepo.Update(user)
+		return err
+	}
+
+	if request.Role == nil {
+		return ErrApprovalRoleNotFound // Role dihapus setelah permintaan diajukan
+	}
+	_, err := i.grants.apply(request.UserID, request.Role, RoleGrantInput{
+		StartsAt:  request.StartsAt,
+		ExpiresAt: request.ExpiresAt,
+		Hours:     request.Hours,
+		Reason:    request.Reason,
+	}, request.RequestedBy)
+	return err
+}
+
+func (i *ApprovalInteractor) find(id uuid.UUID) (*entities.AssignmentRequest, error) {
+	request, err := i.requestRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAssignmentNotFound
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// findForReview memastikan permintaan masih menunggu dan reviewerID boleh memutuskannya.
+func (i *ApprovalInteractor) findForReview(reviewerID, id uuid.UUID) (*entities.AssignmentRequest, error) {
+	request, err := i.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if !request.IsPending() {
+		return nil, ErrAssignmentNotPending
+	}
+	if request.RequestedBy == reviewerID || request.UserID == reviewerID {
+		return nil, ErrAssignmentSelfReview
+	}
+
+	reviewer, err := i.userRepo.FindByID(reviewerID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAssignmentForbidden
+		}
+		return nil, err
+	}
+	if !canReviewAssignment(reviewer, request) {
+		return nil, ErrAssignmentForbidden
+	}
+	return request, nil
+}
+
+func (i *ApprovalInteractor) addDecision(request *entities.AssignmentRequest, reviewerID uuid.UUID, approved bool, comment string) error {
+	decision := &entities.AssignmentDecision{
+		RequestID:  request.ID,
+		ApproverID: reviewerID,
+		Approved:   approved,
+		Comment:    comment,
+	}
+	if err := i.requestRepo.AddDecision(decision); err != nil {
+		if errors.Is(err, gorm.ErrDuplicatedKey) {
+			return ErrAssignmentAlreadyDecided
+		}
+		return err
+	}
+	request.Decisions = append(request.Decisions, *decision)
+	return nil
+}
+
+// finish menyimpan status akhir permintaan yang masih menunggu.
+func (i *ApprovalInteractor) finish(request *entities.AssignmentRequest, status string) error {
+	if !request.IsPending() {
+		return ErrAssignmentNotPending
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.DecidedAt = &now
+	if err := i.requestRepo.UpdateStatus(request); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrAssignmentNotPending
+		}
+		return err
+	}
+	return nil
+}
+
+// policyFor mengembalikan kebijakan untuk Role roleID, atau kebijakan status superuser jika roleID nil.
+// Role tanpa kebijakan sendiri memakai kebijakan Role yang diwarisinya, dan status superuser tanpa
+// kebijakan memakai defaultSuperuserPolicy.
+func (i *ApprovalInteractor) policyFor(roleID *uuid.UUID) (*entities.ApprovalPolicy, error) {
+	if roleID != nil {
+		policy, err := i.grants.approvalPolicy(*roleID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrApprovalPolicyNotFound
+		}
+		return policy, err
+	}
+
+	policy, err := i.policyRepo.FindByRole(nil)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		fallback := defaultSuperuserPolicy
+		return &fallback, nil
+	}
+	return policy, err
+}
+
+// applyPolicy memvalidasi input lalu menyalinnya ke policy.
+func (i *ApprovalInteractor) applyPolicy(policy *entities.ApprovalPolicy, input ApprovalPolicyInput) error {
+	if input.RequiredApprovals < 1 {
+		return ErrApprovalPolicyInvalid
+	}
+	approverRoles, err := resolveRoles(i.roleRepo, input.ApproverRoles, ErrApprovalRoleNotFound)
+	if err != nil {
+		return err
+	}
+
+	policy.RequiredApprovals = input.RequiredApprovals
+	policy.ApproverRoles = make([]string, 0, len(approverRoles))
+	for _, role := range approverRoles {
+		policy.ApproverRoles = append(policy.ApproverRoles, role.Name)
+	}
+	return nil
+}
+
+// record mencatat langkah alur persetujuan ke jejak audit.
+func (i *ApprovalInteractor) record(actorID uuid.UUID, action string, request *entities.AssignmentRequest, comment string) {
+	data := map[string]any{
+		"user_id":            request.UserID,
+		"superuser":          request.IsSuperuser(),
+		"reason":             request.Reason,
+		"requested_by":       request.RequestedBy,
+		"status":             request.Status,
+		"approvals":          request.Approvals(),
+		"required_approvals": request.RequiredApprovals,
+	}
+	if request.Role != nil {
+		data["role"] = request.Role.Name
+	}
+	if comment != "" {
+		data["comment"] = comment
+	}
+	i.audit.Record(actorID, action, AuditTargetAssignmentRequest, request.ID.String(), data)
+}
+
+// canReviewAssignment mengembalikan true jika reviewer superuser atau memegang salah satu Role peninjau
+// permintaan secara langsung.
+func canReviewAssignment(reviewer *entities.User, request *entities.AssignmentRequest) bool {
+	if reviewer.IsSuperuser {
+		return true
+	}
+	for _, role := range request.ApproverRoles {
+		if reviewer.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// policyAuditData mengembalikan isi kebijakan untuk jejak audit.
+func policyAuditData(policy *entities.ApprovalPolicy) map[string]any {
+	data := map[string]any{
+		"superuser":          policy.RoleID == nil,
+		"required_approvals": policy.RequiredApprovals,
+		"approver_roles":     policy.ApproverRoles,
+	}
+	if policy.Role != nil {
+		data["role"] = policy.Role.Name
+	}
+	return data
+}
@@ -0,0 +1,237 @@
+package interactors
+
+import (
+	"errors"
+	"testing"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryAssignmentRequestRepository adalah AssignmentRequestRepository di memori untuk pengujian.
+type memoryAssignmentRequestRepository struct {
+	repositories.AssignmentRequestRepository
+	requests []entities.AssignmentRequest
+}
+
+func (r *memoryAssignmentRequestRepository) Create(request *entities.AssignmentRequest) error {
+	request.ID = uuid.New()
+	r.requests = append(r.requests, *request)
+	return nil
+}
+
+func (r *memoryAssignmentRequestRepository) FindByID(id uuid.UUID) (*entities.AssignmentRequest, error) {
+	for n := range r.requests {
+		if r.requests[n].ID == id {
+			request := r.requests[n]
+			request.Decisions = append([]entities.AssignmentDecision(nil), request.Decisions...)
+			return &request, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryAssignmentRequestRepository) FindPendingFor(userID uuid.UUID, roleID *uuid.UUID) (*entities.AssignmentRequest, error) {
+	for n := range r.requests {
+		request := &r.requests[n]
+		sameRole := (request.RoleID == nil && roleID == nil) || (request.RoleID != nil && roleID != nil && *request.RoleID == *roleID)
+		if request.UserID == userID && sameRole && request.IsPending() {
+			return r.FindByID(request.ID)
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryAssignmentRequestRepository) AddDecision(decision *entities.AssignmentDecision) error {
+	for n := range r.requests {
+		if r.requests[n].ID != decision.RequestID {
+			continue
+		}
+		for _, existing := range r.requests[n].Decisions {
+			if existing.ApproverID == decision.ApproverID {
+				return gorm.ErrDuplicatedKey
+			}
+		}
+		decision.ID = uuid.New()
+		r.requests[n].Decisions = append(r.requests[n].Decisions, *decision)
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryAssignmentRequestRepository) UpdateStatus(request *entities.AssignmentRequest) error {
+	for n := range r.requests {
+		if r.requests[n].ID == request.ID && r.requests[n].IsPending() {
+			r.requests[n].Status, r.requests[n].DecidedAt = request.Status, request.DecidedAt
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// assignmentFixture menyusun ApprovalInteractor dengan satu permintaan production-admin untuk alice yang
+// diajukan bob dan memerlukan dua persetujuan dari pemegang Role security. others ikut didaftarkan.
+type assignmentFixture struct {
+	approvals *ApprovalInteractor
+	requests  *memoryAssignmentRequestRepository
+	roleRepo  *failingGrantRoleRepository
+	request   uuid.UUID
+	alice     *entities.User // Penerima
+	bob       *entities.User // Pengaju
+	carol     *entities.User // Pemegang Role security
+	dave      *entities.User // Pemegang Role security
+	eve       *entities.User // Tanpa Role peninjau
+}
+
+func newAssignmentFixture(others ...*entities.User) *assignmentFixture {
+	production := entities.Role{ID: uuid.New(), Name: "production-admin"}
+	security := entities.Role{ID: uuid.New(), Name: "security"}
+	f := &assignmentFixture{
+		alice: &entities.User{ID: uuid.New(), Username: "alice"},
+		bob:   &entities.User{ID: uuid.New(), Username: "bob", Roles: []*entities.Role{&security}},
+		carol: &entities.User{ID: uuid.New(), Username: "carol", Roles: []*entities.Role{&security}},
+		dave:  &entities.User{ID: uuid.New(), Username: "dave", Roles: []*entities.Role{&security}},
+		eve:   &entities.User{ID: uuid.New(), Username: "eve"},
+	}
+	users := newMemoryUserRepository(append([]*entities.User{f.alice, f.bob, f.carol, f.dave, f.eve}, others...)...)
+	f.roleRepo = &failingGrantRoleRepository{memoryRoleRepository: &memoryRoleRepository{users: users, roles: []entities.Role{production, security}}}
+	policies := &staticApprovalPolicyRepository{roles: []uuid.UUID{production.ID}}
+	sod := NewSoDInteractor(&staticSoDRuleRepository{}, f.roleRepo, nil, users, nil)
+	audit := NewAuditInteractor(discardAuditLogRepository{})
+	grants := NewRoleGrantInteractor(f.roleRepo, users, policies, sod, audit, &recordingEventPublisher{})
+	f.requests = &memoryAssignmentRequestRepository{requests: []entities.AssignmentRequest{{
+		ID:                uuid.New(),
+		UserID:            f.alice.ID,
+		RoleID:            &production.ID,
+		Role:              &production,
+		Hours:             4,
+		Reason:            "migrasi basis data",
+		RequestedBy:       f.bob.ID,
+		Status:            entities.AssignmentPending,
+		RequiredApprovals: 2,
+		ApproverRoles:     []string{"security"},
+	}}}
+	f.request = f.requests.requests[0].ID
+	f.approvals = NewApprovalInteractor(policies, f.requests, f.roleRepo, users, grants, audit)
+	return f
+}
+
+func TestApprovalReviewDenials(t *testing.T) {
+	f := newAssignmentFixture()
+
+	tests := []struct {
+		name     string
+		reviewer uuid.UUID
+		id       uuid.UUID
+		reject   bool
+		comment  string
+		wantErr  error
+	}{
+		{name: "pengaju menyetujui", reviewer: f.bob.ID, id: f.request, wantErr: ErrAssignmentSelfReview},
+		{name: "penerima menyetujui", reviewer: f.alice.ID, id: f.request, wantErr: ErrAssignmentSelfReview},
+		{name: "penerima menolak", reviewer: f.alice.ID, id: f.request, reject: true, comment: "tidak perlu", wantErr: ErrAssignmentSelfReview},
+		{name: "tanpa Role peninjau", reviewer: f.eve.ID, id: f.request, wantErr: ErrAssignmentForbidden},
+		{name: "peninjau tidak terdaftar", reviewer: uuid.New(), id: f.request, wantErr: ErrAssignmentForbidden},
+		{name: "permintaan tidak ada", reviewer: f.carol.ID, id: uuid.New(), wantErr: ErrAssignmentNotFound},
+		{name: "penolakan tanpa komentar", reviewer: f.carol.ID, id: f.request, reject: true, comment: " ", wantErr: ErrAssignmentCommentRequired},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var err error
+			if tt.reject {
+				_, err = f.approvals.Reject(tt.reviewer, tt.id, tt.comment)
+			} else {
+				_, err = f.approvals.Approve(tt.reviewer, tt.id, "")
+			}
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, ingin %v", err, tt.wantErr)
+			}
+		})
+	}
+	if request := f.requests.requests[0]; !request.IsPending() || len(request.Decisions) != 0 {
+		t.Fatalf("permintaan = %s dengan %d keputusan, ingin menunggu tanpa keputusan", request.Status, len(request.Decisions))
+	}
+}
+
+func TestApprovalAppliesAfterRequiredApprovals(t *testing.T) {
+	f := newAssignmentFixture()
+
+	request, err := f.approvals.Approve(f.carol.ID, f.request, "sesuai tiket")
+	if err != nil {
+		t.Fatalf("Approve carol: %v", err)
+	}
+	if !request.IsPending() || len(f.roleRepo.granted) != 0 {
+		t.Fatalf("status = %s dengan %d penetapan, ingin menunggu persetujuan kedua", request.Status, len(f.roleRepo.granted))
+	}
+	if _, err := f.approvals.Approve(f.carol.ID, f.request, ""); !errors.Is(err, ErrAssignmentAlreadyDecided) {
+		t.Fatalf("persetujuan ulang: err = %v, ingin ErrAssignmentAlreadyDecided", err)
+	}
+
+	request, err = f.approvals.Approve(f.dave.ID, f.request, "")
+	if err != nil {
+		t.Fatalf("Approve dave: %v", err)
+	}
+	if request.Status != entities.AssignmentApproved || request.Approvals() != 2 {
+		t.Fatalf("status = %s dengan %d persetujuan, ingin approved dengan 2", request.Status, request.Approvals())
+	}
+	if len(f.roleRepo.granted) != 1 || f.roleRepo.granted[0].UserID != f.alice.ID || f.roleRepo.granted[0].ExpiresAt == nil {
+		t.Fatalf("penetapan = %+v, ingin satu penetapan sementara untuk alice", f.roleRepo.granted)
+	}
+
+	// Permintaan yang sudah diterapkan tidak bisa diputuskan lagi
+	if _, err := f.approvals.Reject(f.eve.ID, f.request, "terlambat"); !errors.Is(err, ErrAssignmentNotPending) {
+		t.Fatalf("Reject: err = %v, ingin ErrAssignmentNotPending", err)
+	}
+	if err := f.approvals.Cancel(f.bob.ID, f.request); !errors.Is(err, ErrAssignmentNotPending) {
+		t.Fatalf("Cancel: err = %v, ingin ErrAssignmentNotPending", err)
+	}
+}
+
+func TestApprovalRejectAndCancel(t *testing.T) {
+	f := newAssignmentFixture()
+
+	if err := f.approvals.Cancel(f.carol.ID, f.request); !errors.Is(err, ErrAssignmentNotFound) {
+		t.Fatalf("Cancel oleh bukan pengaju: err = %v, ingin ErrAssignmentNotFound", err)
+	}
+	request, err := f.approvals.Reject(f.carol.ID, f.request, "tidak ada tiket")
+	if err != nil {
+		t.Fatalf("Reject: %v", err)
+	}
+	if request.Status != entities.AssignmentRejected || len(f.roleRepo.granted) != 0 {
+		t.Fatalf("status = %s dengan %d penetapan, ingin rejected tanpa penetapan", request.Status, len(f.roleRepo.granted))
+	}
+	if _, err := f.approvals.Approve(f.dave.ID, f.request, ""); !errors.Is(err, ErrAssignmentNotPending) {
+		t.Fatalf("Approve setelah ditolak: err = %v, ingin ErrAssignmentNotPending", err)
+	}
+}
+
+func TestApprovalSubmitRejectsInvalidRequests(t *testing.T) {
+	root := &entities.User{ID: uuid.New(), Username: "root", IsSuperuser: true}
+	f := newAssignmentFixture(root)
+
+	tests := []struct {
+		name    string
+		input   AssignmentInput
+		wantErr error
+	}{
+		{"tanpa alasan", AssignmentInput{UserID: f.eve.ID, Grant: RoleGrantInput{Role: "production-admin", Reason: " "}}, ErrAssignmentReasonRequired},
+		{"pengguna tidak ada", AssignmentInput{UserID: uuid.New(), Grant: RoleGrantInput{Role: "production-admin", Reason: "audit"}}, ErrAssignmentUserNotFound},
+		{"masih ada yang menunggu", AssignmentInput{UserID: f.alice.ID, Grant: RoleGrantInput{Role: "production-admin", Reason: "audit"}}, ErrAssignmentDuplicate},
+		{"Role tanpa kebijakan", AssignmentInput{UserID: f.eve.ID, Grant: RoleGrantInput{Role: "security", Reason: "audit"}}, ErrApprovalPolicyNotFound},
+		{"superuser dengan batas waktu", AssignmentInput{UserID: f.eve.ID, Superuser: true, Grant: RoleGrantInput{Hours: 2, Reason: "audit"}}, ErrAssignmentWindowUnsupported},
+		{"sudah superuser", AssignmentInput{UserID: root.ID, Superuser: true, Grant: RoleGrantInput{Reason: "audit"}}, ErrAssignmentAlreadySuperuser},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := f.approvals.Submit(f.bob.ID, tt.input); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, ingin %v", err, tt.wantErr)
+			}
+		})
+	}
+	if len(f.requests.requests) != 1 {
+		t.Fatalf("permintaan = %d, ingin tetap 1", len(f.requests.requests))
+	}
+}
@@ -0,0 +1,64 @@
+package interactors
+
+import (
+	"log"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+// Jenis target catatan audit.
+const (
+	AuditTargetUser              = "user"
+	AuditTargetApprovalPolicy    = "approval_policy"
+	AuditTargetAssignmentRequest = "assignment_request"
+	AuditTargetSoDRule           = "sod_rule"
+	AuditTargetAccessReview      = "access_review"
+)
+
+// Batas jumlah catatan audit per permintaan.
+const (
+	defaultAuditLimit = 100
+	maxAuditLimit     = 1000
+)
+
+// AuditInteractor adalah use case untuk jejak audit tindakan sensitif.
+type AuditInteractor struct {
+	auditRepo repositories.AuditLogRepository
+}
+
+// NewAuditInteractor membuat instance baru dari AuditInteractor.
+func NewAuditInteractor(ar repositories.AuditLogRepository) *AuditInteractor {
+	return &AuditInteractor{auditRepo: ar}
+}
+
+// Record mencatat tindakan actorID terhadap target. actorID uuid.Nil berarti tindakan sistem, misalnya
+// worker. Kegagalan hanya dicatat ke log karena tindakannya sudah terjadi.
+func (i *AuditInteractor) Record(actorID uuid.UUID, action, targetType, targetID string, data map[string]any) {
+	entry := &entities.AuditLog{
+		Action:     action,
+		TargetType: targetType,
+		TargetID:   targetID,
+		Data:       data,
+	}
+	if actorID != uuid.Nil {
+		entry.ActorID = &actorID
+	}
+	if err := i.auditRepo.Create(entry); err != nil {
+		log.Printf("Gagal mencatat audit %s untuk %s %s: %v", action, targetType, targetID, err)
+	}
+}
+
+// List mengembalikan catatan audit yang sesuai filter, terbaru lebih dulu. Limit dibatasi agar satu
+// permintaan tidak memuat seluruh tabel.
+func (i *AuditInteractor) List(filter repositories.AuditLogFilter) ([]entities.AuditLog, error) {
+	if filter.Limit <= 0 {
+		filter.Limit = defaultAuditLimit
+	}
+	if filter.Limit > maxAuditLimit {
+		filter.Limit = maxAuditLimit
+	}
+	return i.auditRepo.Find(filter)
+}
@@ -0,0 +1,601 @@
+package interactors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidCredentials dikembalikan jika username/email atau password salah.
+	ErrInvalidCredentials = errors.New("username atau password salah")
+	// ErrAccountInactive dikembalikan jika akun sudah dinonaktifkan.
+	ErrAccountInactive = errors.New("akun tidak aktif")
+	// ErrInvalidChallenge dikembalikan jika token tantangan login tidak valid atau kedaluwarsa.
+	ErrInvalidChallenge = errors.New("token tantangan tidak valid atau kedaluwarsa")
+)
+
+// AccountLockedError dikembalikan jika akun sedang dikunci setelah terlalu banyak login gagal.
+type AccountLockedError struct {
+	Until time.Time
+}
+
+func (e *AccountLockedError) Error() string {
+	return fmt.Sprintf("akun dikunci sampai %s", e.Until.Format(time.RFC3339))
+}
+
+// LoginThrottledError dikembalikan jika percobaan login harus menunggu (delay progresif atau IP diblokir).
+type LoginThrottledError struct {
+	RetryAfter time.Duration
+}
+
+func (e *LoginThrottledError) Error() string {
+	return fmt.Sprintf("terlalu banyak percobaan login, coba lagi dalam %s", e.RetryAfter.Round(time.Second))
+}
+
+// LockoutPolicy adalah aturan perlindungan brute-force yang dipakai AuthInteractor.
+type LockoutPolicy struct {
+	MaxAttempts     int           // Kegagalan per akun sebelum akun dikunci
+	LockoutDuration time.Duration // Lama akun dikunci
+	IPMaxAttempts   int           // Kegagalan per IP sebelum IP diblokir sampai window berakhir
+	Window          time.Duration // Lama kegagalan diingat
+	DelayAfter      int           // Jumlah kegagalan sebelum delay progresif berlaku
+	BaseDelay       time.Duration // Delay pertama, digandakan setiap kegagalan berikutnya
+	MaxDelay        time.Duration // Batas atas delay progresif
+}
+
+// LoginResult adalah hasil satu langkah login.
+// Token terisi jika login selesai; jika masih ada langkah berikutnya, Challenge berisi
+// tujuan tantangan (services.ChallengeMFA atau services.ChallengeMFAEnrollment).
+type LoginResult struct {
+	Token          *services.IssuedToken
+	Challenge      string
+	ChallengeToken *services.IssuedToken
+	MFAMethods     []string // Faktor kedua yang tersedia untuk tantangan MFA: "totp", "recovery_code", "passkey"
+	RecoveryCodes  []string // Hanya terisi setelah enrollment MFA yang diwajibkan saat login
+}
+
+// AuthInteractor adalah use case untuk autentikasi pengguna.
+// Ini menangani login dengan password, passkey dan MFA beserta perlindungan brute-force per akun dan per IP.
+type AuthInteractor struct {
+	userRepo           repositories.UserRepository
+	attemptRepo        repositories.LoginAttemptRepository
+	hasher             services.PasswordHasher
+	tokenService       services.TokenService
+	mailer             services.Mailer
+	mfaInteractor      *MFAInteractor
+	webAuthnInteractor *WebAuthnInteractor
+	sessionInteractor  *SessionInteractor
+	directory          *DirectoryInteractor // nil jika LDAP/AD tidak diaktifkan
+	policy             LockoutPolicy
+	challengeTTL       time.Duration
+	dummyHash          string // Dibandingkan untuk identifier yang tidak terdaftar agar waktu respons sama
+}
+
+// NewAuthInteractor membuat instance baru dari AuthInteractor.
+func NewAuthInteractor(
+	ur repositories.UserRepository,
+	ar repositories.LoginAttemptRepository,
+	hasher services.PasswordHasher,
+	ts services.TokenService,
+	mailer services.Mailer,
+	mfa *MFAInteractor,
+	wa *WebAuthnInteractor,
+	si *SessionInteractor,
+	di *DirectoryInteractor,
+	policy LockoutPolicy,
+	challengeTTL time.Duration,
+) *AuthInteractor {
+	dummyHash, err := hasher.Hash(uuid.NewString())
+	if err != nil {
+		log.Printf("Gagal membuat hash tiruan untuk login: %v", err)
+	}
+
+	return &AuthInteractor{
+		userRepo:           ur,
+		attemptRepo:        ar,
+		hasher:             hasher,
+		tokenService:       ts,
+		mailer:             mailer,
+		mfaInteractor:      mfa,
+		webAuthnInteractor: wa,
+		sessionInteractor:  si,
+		directory:          di,
+		policy:             policy,
+		challengeTTL:       challengeTTL,
+		dummyHash:          dummyHash,
+	}
+}
+
+// Login memverifikasi kredensial dan menerbitkan token akses, atau token tantangan jika
+// pengguna masih harus memasukkan kode MFA atau mendaftarkan MFA yang diwajibkan.
+// Setiap kegagalan dicatat per akun dan per IP; akun dikunci sementara setelah
+// MaxAttempts kegagalan dan pemilik akun diberi tahu lewat email. Status kunci hanya
+// diungkapkan setelah password terbukti benar, dan identifier yang tidak terdaftar melewati
+// perbandingan hash dan delay progresif yang sama agar tidak bisa dibedakan dari akun yang ada.
+func (i *AuthInteractor) Login(ctx context.Context, identifier, password string, client ClientInfo) (*LoginResult, error) {
+	identifier = strings.TrimSpace(identifier)
+	if identifier == "" || password == "" {
+		return nil, ErrInvalidCredentials
+	}
+
+	now := time.Now()
+	ipKey := "ip:" + client.IP
+
+	// Periksa IP terlebih dahulu agar credential stuffing ke banyak akun ikut tertahan
+	if err := i.checkIP(ctx, ipKey, now); err != nil {
+		return nil, err
+	}
+
+	user, err := i.userRepo.FindByUsernameOrEmail(identifier)
+	if errors.Is(err, gorm.ErrRecordNotFound) && i.directory != nil {
+		// Pengguna direktori yang belum pernah login dibuat saat bind ke direktori berhasil
+		user, err = i.directory.Authenticate(identifier, password)
+		if err == nil {
+			return i.LoginWithExternalIdentity(ctx, user, client)
+		}
+		if errors.Is(err, ErrInvalidCredentials) {
+			err = gorm.ErrRecordNotFound
+		}
+	}
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, i.failUnknownIdentifier(ctx, ipKey, identifier, password, now)
+		}
+		return nil, err
+	}
+
+	accountKey := "user:" + user.ID.String()
+	accountAttempts, err := i.attemptRepo.Get(ctx, accountKey)
+	if err != nil {
+		return nil, err
+	}
+	if wait := i.remainingDelay(accountAttempts, now); wait > 0 {
+		return nil, &LoginThrottledError{RetryAfter: wait}
+	}
+
+	// Service account hanya boleh memakai API key; password-nya acak dan tidak pernah diketahui
+	if user.IsServiceAccount {
+		i.hasher.Compare(i.dummyHash, password)
+		return nil, ErrInvalidCredentials
+	}
+
+	verified, err := i.verifyPassword(user, password)
+	if err != nil {
+		return nil, err
+	}
+	if verified == nil {
+		i.registerFailure(ctx, ipKey)
+		// Kegagalan selama akun terkunci tetap dihitung sehingga kunci diperpanjang, dan akun yang
+		// baru terkunci dilaporkan sebagai kredensial salah; pemiliknya diberi tahu lewat email
+		var locked *AccountLockedError
+		if err := i.handleFailedAttempt(ctx, user, accountKey, now); err != nil && !errors.As(err, &locked) {
+			return nil, err
+		}
+		return nil, ErrInvalidCredentials
+	}
+	user = verified
+
+	if user.IsLocked(now) {
+		return nil, &AccountLockedError{Until: *user.LockedUntil}
+	}
+
+	if !user.IsActive {
+		return nil, ErrAccountInactive
+	}
+
+	return i.secondFactor(ctx, user, client, now)
+}
+
+// verifyPassword memeriksa password dengan bind ke direktori untuk pengguna yang dikelola LDAP/AD,
+// atau dengan hash lokal untuk pengguna lainnya. Mengembalikan nil jika password salah; pengguna
+// direktori dikembalikan dengan profil dan role yang baru disinkronkan.
+func (i *AuthInteractor) verifyPassword(user *entities.User, password string) (*entities.User, error) {
+	if i.directory != nil {
+		subject, err := i.directory.Manages(user.ID)
+		if err != nil {
+			return nil, err
+		}
+		if subject != "" {
+			synced, err := i.directory.Authenticate(subject, password)
+			if errors.Is(err, ErrInvalidCredentials) {
+				return nil, nil
+			}
+			return synced, err
+		}
+	}
+
+	if !i.hasher.Compare(user.Password, password) {
+		return nil, nil
+	}
+	return user, nil
+}
+
+// LoginWithExternalIdentity melanjutkan login pengguna yang sudah diautentikasi oleh identity provider
+// eksternal. Kebijakan akun dan MFA tetap berlaku seperti login dengan password.
+func (i *AuthInteractor) LoginWithExternalIdentity(ctx context.Context, user *entities.User, client ClientInfo) (*LoginResult, error) {
+	now := time.Now()
+	if user.IsServiceAccount {
+		return nil, ErrInvalidCredentials
+	}
+	if user.IsLocked(now) {
+		return nil, &AccountLockedError{Until: *user.LockedUntil}
+	}
+	if !user.IsActive {
+		return nil, ErrAccountInactive
+	}
+	return i.secondFactor(ctx, user, client, now)
+}
+
+// secondFactor menerbitkan tantangan MFA jika pengguna memiliki faktor kedua atau diwajibkan
+// mendaftarkannya, atau langsung menyelesaikan login jika tidak.
+func (i *AuthInteractor) secondFactor(ctx context.Context, user *entities.User, client ClientInfo, now time.Time) (*LoginResult, error) {
+	hasPasskeys, err := i.webAuthnInteractor.HasCredentials(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	// Penghitung kegagalan baru direset setelah faktor kedua lolos, agar password yang
+	// sudah bocor tidak bisa dipakai untuk menebak kode MFA tanpa batas
+	switch {
+	case user.MFAEnabled || hasPasskeys:
+		result, err := i.challenge(user, services.ChallengeMFA)
+		if err != nil {
+			return nil, err
+		}
+		if user.MFAEnabled {
+			result.MFAMethods = append(result.MFAMethods, "totp", "recovery_code")
+		}
+		if hasPasskeys {
+			result.MFAMethods = append(result.MFAMethods, "passkey")
+		}
+		return result, nil
+	case i.mfaInteractor.IsRequired(user):
+		return i.challenge(user, services.ChallengeMFAEnrollment)
+	}
+
+	return i.completeLogin(ctx, user, client, now)
+}
+
+// BeginPasskeyLogin memulai login tanpa password dengan passkey.
+func (i *AuthInteractor) BeginPasskeyLogin(ctx context.Context, ip string) (*protocol.CredentialAssertion, string, error) {
+	if err := i.checkIP(ctx, "ip:"+ip, time.Now()); err != nil {
+		return nil, "", err
+	}
+	return i.webAuthnInteractor.BeginLogin(ctx)
+}
+
+// LoginWithPasskey menyelesaikan login tanpa password. Passkey dengan user verification sudah
+// memenuhi kebijakan MFA sehingga token akses langsung diterbitkan.
+// Assertion yang gagal dihitung sebagai percobaan login gagal untuk IP tersebut.
+func (i *AuthInteractor) LoginWithPasskey(ctx context.Context, sessionID string, response []byte, client ClientInfo) (*LoginResult, error) {
+	now := time.Now()
+	ipKey := "ip:" + client.IP
+	if err := i.checkIP(ctx, ipKey, now); err != nil {
+		return nil, err
+	}
+
+	user, err := i.webAuthnInteractor.FinishLogin(ctx, sessionID, response)
+	if err != nil {
+		if errors.Is(err, ErrPasskeyVerificationFailed) {
+			i.registerFailure(ctx, ipKey)
+		}
+		return nil, err
+	}
+
+	if user.IsLocked(now) {
+		return nil, &AccountLockedError{Until: *user.LockedUntil}
+	}
+	if !user.IsActive {
+		return nil, ErrAccountInactive
+	}
+
+	return i.completeLogin(ctx, user, client, now)
+}
+
+// BeginPasskeyMFA memulai assertion passkey sebagai faktor kedua untuk token tantangan MFA.
+func (i *AuthInteractor) BeginPasskeyMFA(ctx context.Context, challengeToken string) (*protocol.CredentialAssertion, error) {
+	user, _, err := i.challengedUser(ctx, challengeToken, services.ChallengeMFA, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return i.webAuthnInteractor.BeginSecondFactor(ctx, user.ID)
+}
+
+// VerifyPasskeyMFA menyelesaikan login dengan passkey sebagai faktor kedua.
+// Assertion yang gagal dihitung sebagai percobaan login gagal untuk akun tersebut.
+func (i *AuthInteractor) VerifyPasskeyMFA(ctx context.Context, challengeToken string, response []byte, client ClientInfo) (*LoginResult, error) {
+	now := time.Now()
+	user, accountKey, err := i.challengedUser(ctx, challengeToken, services.ChallengeMFA, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.webAuthnInteractor.FinishSecondFactor(ctx, user.ID, response); err != nil {
+		if errors.Is(err, ErrPasskeyVerificationFailed) {
+			if lockErr := i.handleFailedAttempt(ctx, user, accountKey, now); lockErr != nil && !errors.Is(lockErr, ErrInvalidCredentials) {
+				return nil, lockErr
+			}
+		}
+		return nil, err
+	}
+
+	return i.completeLogin(ctx, user, client, now)
+}
+
+// VerifyMFA menyelesaikan login dengan kode TOTP atau kode pemulihan untuk token tantangan MFA.
+// Kode yang salah dihitung sebagai percobaan login gagal untuk akun tersebut.
+func (i *AuthInteractor) VerifyMFA(ctx context.Context, challengeToken, code, recoveryCode string, client ClientInfo) (*LoginResult, error) {
+	now := time.Now()
+	user, accountKey, err := i.challengedUser(ctx, challengeToken, services.ChallengeMFA, now)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.mfaInteractor.Verify(ctx, user, code, recoveryCode); err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if lockErr := i.handleFailedAttempt(ctx, user, accountKey, now); lockErr != nil && !errors.Is(lockErr, ErrInvalidCredentials) {
+				return nil, lockErr
+			}
+		}
+		return nil, err
+	}
+
+	return i.completeLogin(ctx, user, client, now)
+}
+
+// BeginMFAEnrollment memulai enrollment TOTP untuk pengguna yang diwajibkan MFA saat login.
+func (i *AuthInteractor) BeginMFAEnrollment(ctx context.Context, challengeToken string) (*services.TOTPEnrollment, error) {
+	user, _, err := i.challengedUser(ctx, challengeToken, services.ChallengeMFAEnrollment, time.Now())
+	if err != nil {
+		return nil, err
+	}
+	return i.mfaInteractor.BeginTOTPEnrollment(user.ID)
+}
+
+// ConfirmMFAEnrollment mengonfirmasi enrollment TOTP yang diwajibkan lalu menyelesaikan login.
+func (i *AuthInteractor) ConfirmMFAEnrollment(ctx context.Context, challengeToken, code string, client ClientInfo) (*LoginResult, error) {
+	now := time.Now()
+	user, accountKey, err := i.challengedUser(ctx, challengeToken, services.ChallengeMFAEnrollment, now)
+	if err != nil {
+		return nil, err
+	}
+
+	codes, err := i.mfaInteractor.ConfirmTOTPEnrollment(ctx, user.ID, code)
+	if err != nil {
+		if errors.Is(err, ErrInvalidMFACode) {
+			if lockErr := i.handleFailedAttempt(ctx, user, accountKey, now); lockErr != nil && !errors.Is(lockErr, ErrInvalidCredentials) {
+				return nil, lockErr
+			}
+		}
+		return nil, err
+	}
+
+	// Muat ulang agar perubahan MFAEnabled tidak tertimpa saat completeLogin menyimpan pengguna
+	user, err = i.userRepo.FindByID(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	result, err := i.completeLogin(ctx, user, client, now)
+	if err != nil {
+		return nil, err
+	}
+	result.RecoveryCodes = codes
+	return result, nil
+}
+
+// challenge menerbitkan token tantangan untuk langkah login berikutnya.
+func (i *AuthInteractor) challenge(user *entities.User, purpose string) (*LoginResult, error) {
+	token, err := i.tokenService.GenerateChallengeToken(user, purpose, i.challengeTTL)
+	if err != nil {
+		return nil, err
+	}
+	return &LoginResult{Challenge: purpose, ChallengeToken: token}, nil
+}
+
+// challengedUser memverifikasi token tantangan dan memastikan akun masih boleh login.
+func (i *AuthInteractor) challengedUser(ctx context.Context, challengeToken, purpose string, now time.Time) (*entities.User, string, error) {
+	userID, err := i.tokenService.ParseChallengeToken(challengeToken, purpose)
+	if err != nil {
+		return nil, "", ErrInvalidChallenge
+	}
+
+	user, err := i.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, "", ErrInvalidChallenge
+		}
+		return nil, "", err
+	}
+
+	if !user.IsActive {
+		return nil, "", ErrAccountInactive
+	}
+	if user.IsLocked(now) {
+		return nil, "", &AccountLockedError{Until: *user.LockedUntil}
+	}
+
+	accountKey := "user:" + user.ID.String()
+	attempts, err := i.attemptRepo.Get(ctx, accountKey)
+	if err != nil {
+		return nil, "", err
+	}
+	if wait := i.remainingDelay(attempts, now); wait > 0 {
+		return nil, "", &LoginThrottledError{RetryAfter: wait}
+	}
+
+	return user, accountKey, nil
+}
+
+// completeLogin mereset penghitung kegagalan, mencatat waktu login, membuat sesi dan menerbitkan
+// token akses yang terikat ke sesi tersebut.
+func (i *AuthInteractor) completeLogin(ctx context.Context, user *entities.User, client ClientInfo, now time.Time) (*LoginResult, error) {
+	if err := i.attemptRepo.Reset(ctx, "user:"+user.ID.String()); err != nil {
+		log.Printf("Gagal mereset penghitung login untuk %s: %v", user.ID, err)
+	}
+
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+	user.LastLoginAt = &now
+	if _, err := i.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+
+	sessionID := uuid.New()
+	token, err := i.tokenService.GenerateAccessToken(user, sessionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := i.sessionInteractor.Create(ctx, sessionID, user.ID, client, token.ExpiresAt); err != nil {
+		return nil, err
+	}
+	return &LoginResult{Token: token}, nil
+}
+
+// UnlockAccount membuka kunci akun secara manual oleh admin dan mereset penghitung kegagalan.
+func (i *AuthInteractor) UnlockAccount(ctx context.Context, userID uuid.UUID) (*entities.User, error) {
+	user, err := i.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pengguna tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	if err := i.attemptRepo.Reset(ctx, "user:"+user.ID.String()); err != nil {
+		return nil, err
+	}
+
+	user.FailedLoginAttempts = 0
+	user.LockedUntil = nil
+	return i.userRepo.Update(user)
+}
+
+// handleFailedAttempt mencatat kegagalan (password atau kode MFA) untuk akun dan mengunci akun
+// jika batas terlampaui.
+func (i *AuthInteractor) handleFailedAttempt(ctx context.Context, user *entities.User, accountKey string, now time.Time) error {
+	attempts, err := i.attemptRepo.RegisterFailure(ctx, accountKey, i.policy.Window)
+	if err != nil {
+		return err
+	}
+
+	user.FailedLoginAttempts = attempts.Failures
+	user.LastFailedLoginAt = &now
+
+	locked := attempts.Failures >= i.policy.MaxAttempts
+	if locked {
+		lockedUntil := now.Add(i.policy.LockoutDuration)
+		user.LockedUntil = &lockedUntil
+
+		// Penghitung dimulai dari nol lagi setelah masa kunci berakhir
+		if err := i.attemptRepo.Reset(ctx, accountKey); err != nil {
+			log.Printf("Gagal mereset penghitung login untuk %s: %v", user.ID, err)
+		}
+	}
+
+	if _, err := i.userRepo.Update(user); err != nil {
+		return err
+	}
+
+	if locked {
+		go i.notifyLockout(*user)
+		return &AccountLockedError{Until: *user.LockedUntil}
+	}
+	return ErrInvalidCredentials
+}
+
+// failUnknownIdentifier menjalankan alur kegagalan yang sama dengan akun yang ada untuk identifier
+// yang tidak terdaftar: delay progresif per identifier, perbandingan hash dan pencatatan kegagalan.
+func (i *AuthInteractor) failUnknownIdentifier(ctx context.Context, ipKey, identifier, password string, now time.Time) error {
+	key := "identifier:" + strings.ToLower(identifier)
+	attempts, err := i.attemptRepo.Get(ctx, key)
+	if err != nil {
+		return err
+	}
+	if wait := i.remainingDelay(attempts, now); wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+
+	i.hasher.Compare(i.dummyHash, password)
+	i.registerFailure(ctx, ipKey)
+
+	attempts, err = i.attemptRepo.RegisterFailure(ctx, key, i.policy.Window)
+	if err != nil {
+		log.Printf("Gagal mencatat percobaan login untuk %s: %v", key, err)
+		return ErrInvalidCredentials
+	}
+	// Sama seperti akun yang dikunci, penghitung dimulai dari nol lagi setelah MaxAttempts
+	if attempts.Failures >= i.policy.MaxAttempts {
+		if err := i.attemptRepo.Reset(ctx, key); err != nil {
+			log.Printf("Gagal mereset penghitung login untuk %s: %v", key, err)
+		}
+	}
+	return ErrInvalidCredentials
+}
+
+// checkIP menolak percobaan login dari IP yang diblokir atau masih dalam delay progresif.
+func (i *AuthInteractor) checkIP(ctx context.Context, ipKey string, now time.Time) error {
+	attempts, err := i.attemptRepo.Get(ctx, ipKey)
+	if err != nil {
+		return err
+	}
+	if attempts.Failures >= i.policy.IPMaxAttempts {
+		return &LoginThrottledError{RetryAfter: attempts.ExpiresIn}
+	}
+	if wait := i.remainingDelay(attempts, now); wait > 0 {
+		return &LoginThrottledError{RetryAfter: wait}
+	}
+	return nil
+}
+
+// registerFailure mencatat kegagalan tanpa menggagalkan alur login jika Redis bermasalah.
+func (i *AuthInteractor) registerFailure(ctx context.Context, key string) {
+	if _, err := i.attemptRepo.RegisterFailure(ctx, key, i.policy.Window); err != nil {
+		log.Printf("Gagal mencatat percobaan login untuk %s: %v", key, err)
+	}
+}
+
+// remainingDelay menghitung sisa waktu tunggu progresif sejak kegagalan terakhir.
+// Delay = BaseDelay * 2^(kegagalan - DelayAfter), dibatasi MaxDelay.
+func (i *AuthInteractor) remainingDelay(attempts *entities.LoginAttempts, now time.Time) time.Duration {
+	if attempts.Failures < i.policy.DelayAfter || i.policy.BaseDelay <= 0 {
+		return 0
+	}
+
+	delay := i.policy.BaseDelay
+	for n := i.policy.DelayAfter; n < attempts.Failures && delay < i.policy.MaxDelay; n++ {
+		delay *= 2
+	}
+	if delay > i.policy.MaxDelay {
+		delay = i.policy.MaxDelay
+	}
+
+	return attempts.LastFailureAt.Add(delay).Sub(now)
+}
+
+// notifyLockout memberi tahu pemilik akun bahwa akunnya dikunci sementara.
+func (i *AuthInteractor) notifyLockout(user entities.User) {
+	subject := "Akun Anda dikunci sementara"
+	body := fmt.Sprintf(
+		"Halo %s,\n\nAkun Anda dikunci sampai %s karena terlalu banyak percobaan login yang gagal.\n"+
+			"Jika ini bukan Anda, segera hubungi administrator dan ganti password Anda setelah akun dibuka kembali.\n",
+		user.Username, user.LockedUntil.Format(time.RFC1123),
+	)
+
+	if err := i.mailer.Send(user.Email, subject, body); err != nil {
+		log.Printf("Gagal mengirim notifikasi penguncian akun ke %s: %v", user.Email, err)
+	}
+}
@@ -0,0 +1,319 @@
+package interactors
+
+import (
+	"errors"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrAuthzSubjectNotFound dikembalikan jika pengguna yang diperiksa aksesnya tidak ada.
+	ErrAuthzSubjectNotFound = errors.New("subjek tidak ditemukan")
+	// ErrAuthzActionInvalid dikembalikan jika aksi yang diperiksa bukan permission konkret.
+	ErrAuthzActionInvalid = errors.New("aksi harus berupa permission tanpa wildcard, misalnya users:write")
+)
+
+// AccessRequest adalah permintaan akses yang diperiksa terhadap Role, Permission dan kebijakan ABAC.
+type AccessRequest struct {
+	Permission     string
+	OrganizationID uuid.UUID      // Nil jika di luar konteks organisasi
+	ResourceID     string         // ID resource yang diakses, misalnya parameter :id rute
+	Resource       map[string]any // Atribut resource tambahan dari pemanggil
+	Environment    map[string]any // Atribut env tambahan, misalnya ip
+}
+
+// AccessDecision adalah hasil pemeriksaan akses.
+type AccessDecision struct {
+	Allowed     bool          `json:"allowed"`
+	RoleAllowed bool          `json:"role_allowed"` // Hasil dari Role dan Permission saja, sebelum kebijakan ABAC
+	Policies    []PolicyMatch `json:"policies"`     // Kebijakan ABAC yang terpenuhi
+}
+
+// AuthorizationInteractor adalah use case untuk memeriksa apakah permintaan boleh memakai sebuah permission.
+type AuthorizationInteractor struct {
+	permissionRepo repositories.PermissionRepository
+	userRepo       repositories.UserRepository
+	roleRepo       repositories.RoleRepository
+	memberRepo     repositories.OrganizationMemberRepository
+	policies       *AccessPolicyInteractor
+}
+
+// NewAuthorizationInteractor membuat instance baru dari AuthorizationInteractor.
+func NewAuthorizationInteractor(
+	pr repositories.PermissionRepository,
+	ur repositories.UserRepository,
+	rr repositories.RoleRepository,
+	mr repositories.OrganizationMemberRepository,
+	policies *AccessPolicyInteractor,
+) *AuthorizationInteractor {
+	return &AuthorizationInteractor{permissionRepo: pr, userRepo: ur, roleRepo: rr, memberRepo: mr, policies: policies}
+}
+
+// Authorize memeriksa permission untuk permintaan yang sudah diautentikasi.
+// Permission harus dimiliki pengguna saat ini lewat Role-nya, baik untuk token login maupun kredensial
+// turunan. API key dan token OAuth juga hanya boleh memakai permission yang ada di scope-nya, sehingga
+// mencabut role ikut membatasi kredensial yang sudah ada. Token client_credentials bertindak atas nama
+// klien, sehingga cukup dibatasi scope yang diizinkan admin saat klien didaftarkan. Permission pengguna
+// boleh berupa wildcard, dan deny eksplisit dari Role mana pun mengalahkan allow. Superuser tidak
+// dibatasi Role.
+func (i *AuthorizationInteractor) Authorize(claims *services.TokenClaims, permission string) (bool, error) {
+	if !claims.HasScope(permission) {
+		return false, nil
+	}
+	if claims.IsSuperuser || claims.IsClientToken() {
+		return true, nil
+	}
+
+	granted, err := i.permissionRepo.FindNamesByUser(claims.UserID)
+	if err != nil {
+		return false, err
+	}
+	return entities.NewPermissionSet(granted).Allows(permission), nil
+}
+
+// AuthorizeInOrganization memeriksa permission untuk permintaan di dalam konteks organisasi.
+// Permission harus diberikan lewat Role anggota di organisasi tersebut, atau lewat Role global dalam
+// bentuk yang dibatasi ke organisasi ini (misalnya "orgs/<id>/users:write" atau "orgs/*/users:read"),
+// sehingga Role di satu organisasi tidak berlaku di organisasi lain. Deny eksplisit dari sumber mana pun mengalahkan allow.
+// Scope API key dan token OAuth tetap berlaku; superuser tidak dibatasi Role.
+func (i *AuthorizationInteractor) AuthorizeInOrganization(claims *services.TokenClaims, organizationID uuid.UUID, permission string) (bool, error) {
+	if !i.scopeAllows(claims, organizationID, permission) {
+		return false, nil
+	}
+	if claims.IsSuperuser {
+		return true, nil
+	}
+	return i.memberAllows(organizationID, claims.UserID, permission)
+}
+
+// Decide memeriksa permintaan dengan Role dan Permission (Authorize atau AuthorizeInOrganization), lalu
+// dengan kebijakan ABAC yang mencakup permission tersebut. Kebijakan deny yang terpenuhi selalu menolak;
+// kebijakan allow yang terpenuhi mengizinkan walaupun Role tidak, selama scope kredensial mengizinkan.
+// Superuser tidak dibatasi kebijakan.
+func (i *AuthorizationInteractor) Decide(claims *services.TokenClaims, req AccessRequest) (*AccessDecision, error) {
+	var roleAllowed bool
+	var err error
+	if req.OrganizationID != uuid.Nil {
+		roleAllowed, err = i.AuthorizeInOrganization(claims, req.OrganizationID, req.Permission)
+	} else {
+		roleAllowed, err = i.Authorize(claims, req.Permission)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decision := &AccessDecision{Allowed: roleAllowed, RoleAllowed: roleAllowed, Policies: []PolicyMatch{}}
+	if claims.IsSuperuser {
+		return decision, nil
+	}
+	policies, err := i.policies.Applicable(req.Permission)
+	if err != nil || len(policies) == 0 {
+		return decision, err
+	}
+
+	subject := map[string]any{}
+	if !claims.IsClientToken() {
+		user, err := i.userRepo.FindByID(claims.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if subject, err = i.userAttributes(user, req.OrganizationID); err != nil {
+			return nil, err
+		}
+	}
+	if claims.ClientID != "" {
+		subject["client_id"] = claims.ClientID
+	}
+
+	canAllow := i.scopeAllows(claims, req.OrganizationID, req.Permission)
+	return decision, i.applyPolicies(decision, policies, subject, req, canAllow)
+}
+
+// Check memeriksa apakah pengguna subjectID boleh memakai permission, untuk layanan lain lewat
+// POST /authz/check. Role dievaluasi seperti pada token login pengguna tersebut, lalu kebijakan ABAC.
+// Pengguna yang nonaktif atau sedang dikunci selalu ditolak karena tidak bisa login; pengguna yang
+// sudah dihapus tidak ditemukan.
+func (i *AuthorizationInteractor) Check(subjectID uuid.UUID, req AccessRequest) (*AccessDecision, error) {
+	if pattern, ok := entities.ParsePermission(req.Permission); !ok || !pattern.IsConcrete() {
+		return nil, ErrAuthzActionInvalid
+	}
+	user, err := i.userRepo.FindByID(subjectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrAuthzSubjectNotFound
+		}
+		return nil, err
+	}
+	if !user.IsActive || user.IsLocked(time.Now()) {
+		return &AccessDecision{Policies: []PolicyMatch{}}, nil
+	}
+
+	roleAllowed := user.IsSuperuser
+	if !roleAllowed && req.OrganizationID != uuid.Nil {
+		roleAllowed, err = i.memberAllows(req.OrganizationID, user.ID, req.Permission)
+	} else if !roleAllowed {
+		var granted []string
+		granted, err = i.permissionRepo.FindNamesByUser(user.ID)
+		roleAllowed = entities.NewPermissionSet(granted).Allows(req.Permission)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decision := &AccessDecision{Allowed: roleAllowed, RoleAllowed: roleAllowed, Policies: []PolicyMatch{}}
+	if user.IsSuperuser {
+		return decision, nil
+	}
+	policies, err := i.policies.Applicable(req.Permission)
+	if err != nil || len(policies) == 0 {
+		return decision, err
+	}
+	subject, err := i.userAttributes(user, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	return decision, i.applyPolicies(decision, policies, subject, req, true)
+}
+
+// applyPolicies mengevaluasi policies dan memperbarui decision. canAllow bernilai false jika kebijakan
+// allow tidak boleh memperluas akses, misalnya karena scope kredensial tidak mencakup permission.
+func (i *AuthorizationInteractor) applyPolicies(decision *AccessDecision, policies []entities.AccessPolicy, subject map[string]any, req AccessRequest, canAllow bool) error {
+	resource, err := i.resourceAttributes(req)
+	if err != nil {
+		return err
+	}
+	environment := i.policies.Environment(time.Now())
+	for name, value := range req.Environment {
+		environment[name] = value
+	}
+	if req.OrganizationID != uuid.Nil {
+		environment["organization_id"] = req.OrganizationID.String()
+	}
+
+	decision.Policies = i.policies.Evaluate(policies, map[string]any{
+		"subject":  subject,
+		"resource": resource,
+		"action":   req.Permission,
+		"env":      environment,
+	})
+	for _, match := range decision.Policies {
+		if match.Effect == entities.PolicyEffectDeny {
+			decision.Allowed = false
+			return nil
+		}
+		if canAllow {
+			decision.Allowed = true
+		}
+	}
+	return nil
+}
+
+// userAttributes menyusun atribut subject atau resource dari pengguna: atribut bebasnya, ditambah id,
+// username, email, superuser, service_account, active dan roles (Role langsung dan Role di organisasi).
+func (i *AuthorizationInteractor) userAttributes(user *entities.User, organizationID uuid.UUID) (map[string]any, error) {
+	attributes := make(map[string]any, len(user.Attributes)+7)
+	for name, value := range user.Attributes {
+		attributes[name] = value
+	}
+	attributes["id"] = user.ID.String()
+	attributes["username"] = user.Username
+	attributes["email"] = user.Email
+	attributes["superuser"] = user.IsSuperuser
+	attributes["service_account"] = user.IsServiceAccount
+	attributes["active"] = user.IsActive
+
+	roles, err := i.roleRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	names := make([]any, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	if organizationID != uuid.Nil {
+		member, err := i.memberRepo.Find(organizationID, user.ID)
+		if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, err
+		}
+		if err == nil {
+			for _, role := range member.Roles {
+				names = append(names, role.Name)
+			}
+		}
+	}
+	attributes["roles"] = names
+	return attributes, nil
+}
+
+// resourceAttributes menyusun atribut resource: atribut dari pemanggil, type (bagian resource terakhir
+// dari permission, misalnya "users") dan id. Untuk resource users, atribut pengguna target dimuat dari
+// database dan menimpa atribut dari pemanggil.
+func (i *AuthorizationInteractor) resourceAttributes(req AccessRequest) (map[string]any, error) {
+	attributes := make(map[string]any, len(req.Resource)+2)
+	for name, value := range req.Resource {
+		attributes[name] = value
+	}
+	pattern, _ := entities.ParsePermission(req.Permission)
+	if _, ok := attributes["type"]; !ok && len(pattern.Resource) > 0 {
+		attributes["type"] = pattern.Resource[len(pattern.Resource)-1]
+	}
+	if req.ResourceID != "" {
+		attributes["id"] = req.ResourceID
+	}
+
+	id, err := uuid.Parse(req.ResourceID)
+	if attributes["type"] != "users" || err != nil {
+		return attributes, nil
+	}
+	user, err := i.userRepo.FindByID(id)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return attributes, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	loaded, err := i.userAttributes(user, req.OrganizationID)
+	if err != nil {
+		return nil, err
+	}
+	for name, value := range loaded {
+		attributes[name] = value
+	}
+	return attributes, nil
+}
+
+// scopeAllows mengembalikan true jika scope kredensial mencakup permission, termasuk bentuk yang
+// dibatasi ke organisasi organizationID.
+func (i *AuthorizationInteractor) scopeAllows(claims *services.TokenClaims, organizationID uuid.UUID, permission string) bool {
+	if claims.HasScope(permission) {
+		return true
+	}
+	return organizationID != uuid.Nil && claims.HasScope(entities.OrganizationPermission(organizationID, permission))
+}
+
+// memberAllows memeriksa permission pengguna di organisasi: Role anggota, atau Role global dalam bentuk
+// yang dibatasi ke organisasi tersebut. Deny eksplisit dari sumber mana pun mengalahkan allow.
+func (i *AuthorizationInteractor) memberAllows(organizationID, userID uuid.UUID, permission string) (bool, error) {
+	memberGranted, err := i.permissionRepo.FindNamesByMember(organizationID, userID)
+	if err != nil {
+		return false, err
+	}
+	userGranted, err := i.permissionRepo.FindNamesByUser(userID)
+	if err != nil {
+		return false, err
+	}
+
+	scoped := entities.OrganizationPermission(organizationID, permission)
+	member := entities.NewPermissionSet(memberGranted)
+	user := entities.NewPermissionSet(userGranted)
+	if member.Denies(permission) || member.Denies(scoped) || user.Denies(scoped) {
+		return false, nil
+	}
+	return member.Allows(permission) || member.Allows(scoped) || user.Allows(scoped), nil
+}
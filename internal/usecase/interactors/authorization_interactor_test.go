@@ -0,0 +1,120 @@
+package interactors
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+)
+
+// staticPermissionRepository adalah PermissionRepository uji dengan permission tetap per pengguna
+// (Role global) dan per keanggotaan organisasi.
+type staticPermissionRepository struct {
+	repositories.PermissionRepository
+	user   map[uuid.UUID][]string
+	member map[uuid.UUID]map[uuid.UUID][]string // organisasi -> pengguna -> permission
+}
+
+func (r *staticPermissionRepository) FindNamesByUser(userID uuid.UUID) ([]string, error) {
+	return r.user[userID], nil
+}
+
+func (r *staticPermissionRepository) FindNamesByMember(organizationID, userID uuid.UUID) ([]string, error) {
+	return r.member[organizationID][userID], nil
+}
+
+func TestAuthorizeInOrganizationIsolatesOrganizations(t *testing.T) {
+	orgA, orgB := uuid.New(), uuid.New()
+	scopedA := "orgs/" + orgA.String() + "/"
+
+	tests := []struct {
+		name       string
+		member     []string // Role anggota di orgA
+		global     []string // Role global pengguna
+		scopes     []string // Scope API key; nil berarti token login
+		superuser  bool
+		permission string
+		wantA      bool
+		wantB      bool
+	}{
+		{name: "role anggota hanya berlaku di organisasinya", member: []string{"users:write"}, permission: "users:write", wantA: true},
+		{name: "role anggota dengan wildcard", member: []string{"users:*"}, permission: "users:delete", wantA: true},
+		{name: "role global untuk satu organisasi", global: []string{scopedA + "users:write"}, permission: "users:write", wantA: true},
+		{name: "role global untuk semua organisasi", global: []string{"orgs/*/users:read"}, permission: "users:read", wantA: true, wantB: true},
+		{name: "role global tanpa organisasi tidak berlaku", global: []string{"users:write", "*:*"}, permission: "users:write"},
+		{name: "deny global mengalahkan role anggota", member: []string{"users:*"}, global: []string{"!orgs/*/users:delete"}, permission: "users:delete"},
+		{name: "deny anggota mengalahkan role global", member: []string{"!users:delete"}, global: []string{"orgs/*/users:*"}, permission: "users:delete", wantB: true},
+		{name: "deny anggota dalam bentuk organisasi", member: []string{"users:*", "!" + scopedA + "users:delete"}, permission: "users:delete"},
+		{name: "scope API key membatasi role anggota", member: []string{"users:*"}, scopes: []string{"users:read"}, permission: "users:write"},
+		{name: "scope API key untuk satu organisasi", member: []string{"users:*"}, global: []string{"orgs/*/users:*"}, scopes: []string{scopedA + "users:write"}, permission: "users:write", wantA: true},
+		{name: "superuser tidak dibatasi role", superuser: true, permission: "users:delete", wantA: true, wantB: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			interactor := NewAuthorizationInteractor(&staticPermissionRepository{
+				user:   map[uuid.UUID][]string{userID: tt.global},
+				member: map[uuid.UUID]map[uuid.UUID][]string{orgA: {userID: tt.member}},
+			}, nil, nil, nil, nil)
+			claims := &services.TokenClaims{UserID: userID, IsSuperuser: tt.superuser, Scopes: tt.scopes}
+			if tt.scopes != nil {
+				claims.APIKeyID = uuid.New()
+			}
+
+			for _, check := range []struct {
+				organizationID uuid.UUID
+				want           bool
+			}{{orgA, tt.wantA}, {orgB, tt.wantB}} {
+				allowed, err := interactor.AuthorizeInOrganization(claims, check.organizationID, tt.permission)
+				if err != nil {
+					t.Fatalf("AuthorizeInOrganization: %v", err)
+				}
+				if allowed != check.want {
+					name := "A"
+					if check.organizationID == orgB {
+						name = "B"
+					}
+					t.Fatalf("AuthorizeInOrganization di organisasi %s = %v, ingin %v", name, allowed, check.want)
+				}
+			}
+		})
+	}
+}
+
+func TestCheckDeniesInactiveSubjects(t *testing.T) {
+	lockedUntil := time.Now().Add(time.Hour)
+	tests := []struct {
+		name string
+		user entities.User
+		want bool
+	}{
+		{name: "aktif", user: entities.User{IsActive: true, IsSuperuser: true}, want: true},
+		{name: "nonaktif", user: entities.User{IsActive: false, IsSuperuser: true}},
+		{name: "dikunci", user: entities.User{IsActive: true, IsSuperuser: true, LockedUntil: &lockedUntil}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			user := tt.user
+			user.ID = uuid.New()
+			interactor := NewAuthorizationInteractor(&staticPermissionRepository{}, newMemoryUserRepository(&user), nil, nil, nil)
+
+			decision, err := interactor.Check(user.ID, AccessRequest{Permission: "users:delete"})
+			if err != nil {
+				t.Fatalf("Check: %v", err)
+			}
+			if decision.Allowed != tt.want {
+				t.Fatalf("Allowed = %v, ingin %v", decision.Allowed, tt.want)
+			}
+		})
+	}
+
+	interactor := NewAuthorizationInteractor(&staticPermissionRepository{}, newMemoryUserRepository(), nil, nil, nil)
+	if _, err := interactor.Check(uuid.New(), AccessRequest{Permission: "users:delete"}); !errors.Is(err, ErrAuthzSubjectNotFound) {
+		t.Fatalf("err = %v, ingin ErrAuthzSubjectNotFound", err)
+	}
+}
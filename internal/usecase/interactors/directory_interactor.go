@@ -0,0 +1,219 @@
+package interactors
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// DirectoryProvider adalah nama provider identitas untuk pengguna yang dikelola direktori LDAP/AD.
+const DirectoryProvider = "ldap"
+
+// ErrDirectoryEmpty dikembalikan jika direktori tidak mengembalikan pengguna sama sekali saat sinkronisasi.
+// Sinkronisasi dibatalkan agar kesalahan filter atau base DN tidak menonaktifkan semua akun.
+var ErrDirectoryEmpty = errors.New("direktori tidak mengembalikan pengguna, sinkronisasi dibatalkan")
+
+// DirectoryPolicy adalah aturan pemetaan direktori ke akun lokal.
+type DirectoryPolicy struct {
+	// GroupRoles memetakan DN grup direktori ke nama Role. Role yang muncul di pemetaan ini dikelola
+	// sepenuhnya oleh direktori: diberikan saat pengguna masuk grup dan dicabut saat keluar.
+	GroupRoles map[string][]string
+}
+
+// DirectorySyncResult adalah ringkasan satu kali sinkronisasi direktori.
+type DirectorySyncResult struct {
+	Updated     int // Pengguna yang profil dan role-nya diperbarui
+	Deactivated int // Pengguna yang dinonaktifkan karena sudah tidak ada di direktori
+}
+
+// DirectoryInteractor adalah use case autentikasi dan sinkronisasi pengguna dari direktori LDAP/Active Directory.
+// Pengguna direktori ditandai dengan LinkedIdentity ber-provider DirectoryProvider dan subject username direktori.
+type DirectoryInteractor struct {
+	directory    services.Directory
+	identityRepo repositories.LinkedIdentityRepository
+	userRepo     repositories.UserRepository
+	users        *UserInteractor
+	sessions     *SessionInteractor
+	groupRoles   groupRoles
+}
+
+// NewDirectoryInteractor membuat instance baru dari DirectoryInteractor.
+func NewDirectoryInteractor(
+	directory services.Directory,
+	ir repositories.LinkedIdentityRepository,
+	ur repositories.UserRepository,
+	rr repositories.RoleRepository,
+	users *UserInteractor,
+	sessions *SessionInteractor,
+	grants *RoleGrantInteractor,
+	policy DirectoryPolicy,
+) *DirectoryInteractor {
+	return &DirectoryInteractor{
+		directory:    directory,
+		identityRepo: ir,
+		userRepo:     ur,
+		users:        users,
+		sessions:     sessions,
+		groupRoles:   newGroupRoles(policy.GroupRoles, rr, grants),
+	}
+}
+
+// Manages mengembalikan username direktori jika pengguna dikelola direktori, atau string kosong jika tidak.
+func (i *DirectoryInteractor) Manages(userID uuid.UUID) (string, error) {
+	identities, err := i.identityRepo.FindByUser(userID)
+	if err != nil {
+		return "", err
+	}
+	for _, identity := range identities {
+		if identity.Provider == DirectoryProvider {
+			return identity.Subject, nil
+		}
+	}
+	return "", nil
+}
+
+// Authenticate memverifikasi password pengguna direktori dengan bind, lalu memperbarui profil dan role-nya.
+// Pengguna yang belum punya akun lokal dibuat saat itu juga (just-in-time provisioning); akun lokal
+// dengan email yang sama dihubungkan ke direktori. Password yang salah dilaporkan sebagai ErrInvalidCredentials.
+func (i *DirectoryInteractor) Authenticate(username, password string) (*entities.User, error) {
+	entry, err := i.directory.Authenticate(username, password)
+	if err != nil {
+		if errors.Is(err, services.ErrDirectoryInvalidCredentials) {
+			return nil, ErrInvalidCredentials
+		}
+		return nil, err
+	}
+
+	subject := strings.ToLower(entry.Username)
+	now := time.Now()
+
+	identity, err := i.identityRepo.FindBySubject(DirectoryProvider, subject)
+	switch {
+	case err == nil:
+		if err := i.identityRepo.Touch(identity.ID, entry.Email, now); err != nil {
+			log.Printf("Gagal mencatat login direktori %s: %v", subject, err)
+		}
+		user, err := i.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			return nil, err
+		}
+		return user, i.apply(user, entry)
+	case !errors.Is(err, gorm.ErrRecordNotFound):
+		return nil, err
+	}
+
+	email := entry.Email
+	if email == "" {
+		// Kolom email wajib unik, domain .invalid menjamin alamat ini tidak pernah bisa dikirimi email
+		email = subject + "@directory.invalid"
+	}
+
+	provisioned := false
+	user, err := i.userRepo.FindByEmail(email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		provisioned = true
+		user, err = i.users.ProvisionExternalUser(entry.Username, email, entry.FirstName, entry.LastName)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.identityRepo.Create(&entities.LinkedIdentity{
+		UserID:      user.ID,
+		Provider:    DirectoryProvider,
+		Subject:     subject,
+		Email:       entry.Email,
+		Provisioned: provisioned,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, err
+	}
+	return user, i.apply(user, entry)
+}
+
+// Sync mencocokkan semua pengguna direktori dengan akun lokal: profil dan role diperbarui, sedangkan
+// pengguna yang sudah tidak ada (atau dinonaktifkan) di direktori dinonaktifkan dan semua sesinya dicabut.
+// Akun yang sudah dinonaktifkan tidak diaktifkan kembali secara otomatis.
+func (i *DirectoryInteractor) Sync(ctx context.Context) (*DirectorySyncResult, error) {
+	entries, err := i.directory.ListUsers()
+	if err != nil {
+		return nil, err
+	}
+	if len(entries) == 0 {
+		return nil, ErrDirectoryEmpty
+	}
+
+	bySubject := make(map[string]*services.DirectoryUser, len(entries))
+	for n := range entries {
+		bySubject[strings.ToLower(entries[n].Username)] = &entries[n]
+	}
+
+	identities, err := i.identityRepo.FindByProvider(DirectoryProvider)
+	if err != nil {
+		return nil, err
+	}
+
+	result := &DirectorySyncResult{}
+	for _, identity := range identities {
+		user, err := i.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return result, err
+		}
+		if !user.IsActive {
+			continue
+		}
+
+		entry, found := bySubject[identity.Subject]
+		if !found {
+			if err := i.deactivate(ctx, user); err != nil {
+				return result, err
+			}
+			result.Deactivated++
+			continue
+		}
+
+		if err := i.apply(user, entry); err != nil {
+			log.Printf("Gagal memperbarui pengguna direktori %s: %v", identity.Subject, err)
+			continue
+		}
+		result.Updated++
+	}
+	return result, nil
+}
+
+// apply menyalin profil dari direktori dan menyesuaikan role yang dikelola direktori.
+func (i *DirectoryInteractor) apply(user *entities.User, entry *services.DirectoryUser) error {
+	if user.FirstName != entry.FirstName || user.LastName != entry.LastName {
+		user.FirstName = entry.FirstName
+		user.LastName = entry.LastName
+		if _, err := i.userRepo.Update(user); err != nil {
+			return err
+		}
+	}
+	return i.groupRoles.sync(user, entry.Groups)
+}
+
+// deactivate menonaktifkan pengguna dan mengeluarkannya dari semua perangkat.
+func (i *DirectoryInteractor) deactivate(ctx context.Context, user *entities.User) error {
+	user.IsActive = false
+	if _, err := i.userRepo.Update(user); err != nil {
+		return err
+	}
+	if _, err := i.sessions.RevokeAll(ctx, user.ID); err != nil {
+		return err
+	}
+	log.Printf("Pengguna direktori %s dinonaktifkan karena sudah tidak ada di direktori", user.Username)
+	return nil
+}
@@ -0,0 +1,264 @@
+package interactors
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+)
+
+// staticDirectory adalah services.Directory dengan isi tetap untuk pengujian.
+type staticDirectory struct {
+	users     []services.DirectoryUser
+	passwords map[string]string
+}
+
+func (d *staticDirectory) Authenticate(username, password string) (*services.DirectoryUser, error) {
+	for n := range d.users {
+		if d.users[n].Username == username && password != "" && d.passwords[username] == password {
+			user := d.users[n]
+			return &user, nil
+		}
+	}
+	return nil, services.ErrDirectoryInvalidCredentials
+}
+
+func (d *staticDirectory) ListUsers() ([]services.DirectoryUser, error) {
+	return d.users, nil
+}
+
+// directoryFixture menyiapkan DirectoryInteractor dengan repositori di memori.
+type directoryFixture struct {
+	directory  *staticDirectory
+	users      *memoryUserRepository
+	identities *memoryLinkedIdentityRepository
+	sessions   *memorySessionRepository
+	roles      *memoryRoleRepository
+	policies   *staticApprovalPolicyRepository
+	rules      *staticSoDRuleRepository
+	interactor *DirectoryInteractor
+}
+
+func newDirectoryFixture(groupRoles map[string][]string, roles ...string) *directoryFixture {
+	f := &directoryFixture{
+		directory:  &staticDirectory{passwords: map[string]string{}},
+		users:      newMemoryUserRepository(),
+		identities: &memoryLinkedIdentityRepository{},
+		sessions:   &memorySessionRepository{},
+		policies:   &staticApprovalPolicyRepository{},
+		rules:      &staticSoDRuleRepository{},
+	}
+	f.roles = &memoryRoleRepository{users: f.users}
+	for _, name := range roles {
+		f.roles.roles = append(f.roles.roles, entities.Role{ID: uuid.New(), Name: name})
+	}
+	sod := NewSoDInteractor(f.rules, f.roles, nil, nil, nil)
+	f.interactor = NewDirectoryInteractor(
+		f.directory, f.identities, f.users, f.roles, nil, NewSessionInteractor(f.sessions),
+		NewRoleGrantInteractor(f.roles, f.users, f.policies, sod, nil, nil), DirectoryPolicy{GroupRoles: groupRoles},
+	)
+	return f
+}
+
+// addLinkedUser membuat pengguna lokal yang sudah terhubung ke direktori dengan satu sesi aktif.
+func (f *directoryFixture) addLinkedUser(t *testing.T, username string, roles ...string) *entities.User {
+	t.Helper()
+	user := &entities.User{ID: uuid.New(), Username: username, Email: username + "@example.org", IsActive: true}
+	for _, name := range roles {
+		found, _ := f.roles.FindByNames([]string{name})
+		if len(found) != 1 {
+			t.Fatalf("role %s tidak ada", name)
+		}
+		user.Roles = append(user.Roles, &found[0])
+	}
+	if _, err := f.users.Create(user); err != nil {
+		t.Fatal(err)
+	}
+	if err := f.identities.Create(&entities.LinkedIdentity{UserID: user.ID, Provider: DirectoryProvider, Subject: username}); err != nil {
+		t.Fatal(err)
+	}
+	f.sessions.sessions = append(f.sessions.sessions, entities.Session{
+		ID: uuid.New(), UserID: user.ID, ExpiresAt: time.Now().Add(time.Hour),
+	})
+	return user
+}
+
+// roleNames mengembalikan nama Role milik pengguna yang tersimpan, terurut.
+func (f *directoryFixture) roleNames(t *testing.T, userID uuid.UUID) []string {
+	t.Helper()
+	user, err := f.users.FindByID(userID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, 0, len(user.Roles))
+	for _, role := range user.Roles {
+		names = append(names, role.Name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// activeSessions menghitung sesi aktif milik pengguna.
+func (f *directoryFixture) activeSessions(userID uuid.UUID) int {
+	count := 0
+	for _, session := range f.sessions.sessions {
+		if session.UserID == userID && session.IsActive(time.Now()) {
+			count++
+		}
+	}
+	return count
+}
+
+func TestDirectorySyncDeactivatesRemovedUsers(t *testing.T) {
+	f := newDirectoryFixture(nil)
+	alice := f.addLinkedUser(t, "alice")
+	bob := f.addLinkedUser(t, "bob")
+	f.directory.users = []services.DirectoryUser{{Username: "Alice", FirstName: "Alice", LastName: "Liddell"}}
+
+	result, err := f.interactor.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	if result.Updated != 1 || result.Deactivated != 1 {
+		t.Fatalf("hasil Sync = %+v, ingin 1 diperbarui dan 1 dinonaktifkan", result)
+	}
+
+	stored, _ := f.users.FindByID(bob.ID)
+	if stored.IsActive {
+		t.Fatal("bob yang sudah tidak ada di direktori masih aktif")
+	}
+	if f.activeSessions(bob.ID) != 0 {
+		t.Fatal("sesi bob tidak dicabut")
+	}
+
+	stored, _ = f.users.FindByID(alice.ID)
+	if !stored.IsActive || stored.FirstName != "Alice" || stored.LastName != "Liddell" {
+		t.Fatalf("alice tidak diperbarui dengan benar: %+v", stored)
+	}
+	if f.activeSessions(alice.ID) != 1 {
+		t.Fatal("sesi alice ikut dicabut")
+	}
+
+	// Pengguna yang sudah dinonaktifkan tidak dihitung ulang dan tidak diaktifkan kembali
+	f.directory.users = append(f.directory.users, services.DirectoryUser{Username: "bob"})
+	result, err = f.interactor.Sync(context.Background())
+	if err != nil {
+		t.Fatalf("Sync kedua: %v", err)
+	}
+	if result.Deactivated != 0 {
+		t.Fatalf("Sync kedua menonaktifkan %d pengguna", result.Deactivated)
+	}
+	if stored, _ := f.users.FindByID(bob.ID); stored.IsActive {
+		t.Fatal("bob diaktifkan kembali secara otomatis")
+	}
+}
+
+func TestDirectorySyncRefusesEmptyDirectory(t *testing.T) {
+	f := newDirectoryFixture(nil)
+	alice := f.addLinkedUser(t, "alice")
+
+	if _, err := f.interactor.Sync(context.Background()); !errors.Is(err, ErrDirectoryEmpty) {
+		t.Fatalf("err = %v, ingin ErrDirectoryEmpty", err)
+	}
+	if stored, _ := f.users.FindByID(alice.ID); !stored.IsActive || f.activeSessions(alice.ID) != 1 {
+		t.Fatal("direktori kosong menonaktifkan pengguna")
+	}
+}
+
+func TestDirectoryGroupRoleSync(t *testing.T) {
+	f := newDirectoryFixture(map[string][]string{
+		"CN=Admins, OU=Groups, DC=example, DC=org": {"admin"},
+		"cn=auditors,ou=groups,dc=example,dc=org":  {"auditor"},
+	}, "admin", "auditor", "billing")
+	alice := f.addLinkedUser(t, "alice", "auditor", "billing")
+	f.directory.users = []services.DirectoryUser{{
+		Username: "alice",
+		Groups:   []string{"cn=admins,ou=groups,dc=example,dc=org", "cn=staff,ou=groups,dc=example,dc=org"},
+	}}
+
+	if _, err := f.interactor.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	// admin diberikan dari grup, auditor dicabut karena keluar grup, billing tidak dikelola direktori
+	if got := f.roleNames(t, alice.ID); len(got) != 2 || got[0] != "admin" || got[1] != "billing" {
+		t.Fatalf("role setelah Sync = %v, ingin [admin billing]", got)
+	}
+
+	// Login memakai pemetaan yang sama dan memperbarui Roles di memori untuk kebijakan MFA
+	f.directory.passwords["alice"] = "secret"
+	f.directory.users[0].Groups = []string{"cn=auditors,ou=groups,dc=example,dc=org"}
+	user, err := f.interactor.Authenticate("alice", "secret")
+	if err != nil {
+		t.Fatalf("Authenticate: %v", err)
+	}
+	if !user.HasRole("auditor") || user.HasRole("admin") || !user.HasRole("billing") {
+		t.Fatalf("role di memori setelah login tidak sesuai: %v", user.Roles)
+	}
+	if got := f.roleNames(t, alice.ID); len(got) != 2 || got[0] != "auditor" || got[1] != "billing" {
+		t.Fatalf("role setelah login = %v, ingin [auditor billing]", got)
+	}
+
+	if _, err := f.interactor.Authenticate("alice", "salah"); !errors.Is(err, ErrInvalidCredentials) {
+		t.Fatalf("err = %v, ingin ErrInvalidCredentials", err)
+	}
+}
+
+func TestDirectoryGroupRoleSyncSkipsApprovalRoles(t *testing.T) {
+	f := newDirectoryFixture(map[string][]string{
+		"cn=admins,ou=groups,dc=example,dc=org":   {"admin"},
+		"cn=support,ou=groups,dc=example,dc=org":  {"support"},
+		"cn=auditors,ou=groups,dc=example,dc=org": {"auditor"},
+	}, "admin", "support", "auditor")
+	admin, _ := f.roles.FindByNames([]string{"admin"})
+	f.policies.roles = []uuid.UUID{admin[0].ID}
+	alice := f.addLinkedUser(t, "alice", "auditor")
+	f.directory.users = []services.DirectoryUser{{
+		Username: "alice",
+		Groups:   []string{"cn=admins,ou=groups,dc=example,dc=org", "cn=support,ou=groups,dc=example,dc=org"},
+	}}
+
+	if _, err := f.interactor.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	// admin memerlukan persetujuan sehingga dilewati; support tetap diberikan dan auditor tetap dicabut
+	if got := f.roleNames(t, alice.ID); len(got) != 1 || got[0] != "support" {
+		t.Fatalf("role setelah Sync = %v, ingin [support]", got)
+	}
+}
+
+func TestDirectoryGroupRoleSyncSkipsConflictingRoles(t *testing.T) {
+	f := newDirectoryFixture(map[string][]string{
+		"cn=payments,ou=groups,dc=example,dc=org":  {"payments"},
+		"cn=approvers,ou=groups,dc=example,dc=org": {"approver"},
+		"cn=support,ou=groups,dc=example,dc=org":   {"support"},
+	}, "payments", "approver", "support", "auditor")
+	roles, _ := f.roles.FindByNames([]string{"payments", "approver", "auditor"})
+	f.rules.rules = []entities.SoDRule{
+		{Name: "pembayaran", Roles: []*entities.Role{&roles[0], &roles[1]}},
+		{Name: "dukungan-audit", Roles: []*entities.Role{&roles[2], &f.roles.roles[2]}},
+	}
+	alice := f.addLinkedUser(t, "alice", "auditor")
+	f.directory.users = []services.DirectoryUser{{
+		Username: "alice",
+		Groups: []string{
+			"cn=payments,ou=groups,dc=example,dc=org",
+			"cn=approvers,ou=groups,dc=example,dc=org",
+			"cn=support,ou=groups,dc=example,dc=org",
+		},
+	}}
+
+	if _, err := f.interactor.Sync(context.Background()); err != nil {
+		t.Fatalf("Sync: %v", err)
+	}
+	// payments diberikan lebih dulu sehingga approver dilewati; support bertentangan dengan auditor yang
+	// sudah dipegang
+	if got := f.roleNames(t, alice.ID); len(got) != 2 || got[0] != "auditor" || got[1] != "payments" {
+		t.Fatalf("role setelah Sync = %v, ingin [auditor payments]", got)
+	}
+}
@@ -0,0 +1,200 @@
+package interactors
+
+import (
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// externalAccounts mencocokkan identitas dari identity provider eksternal (OIDC, SAML) dengan akun lokal.
+type externalAccounts struct {
+	identityRepo repositories.LinkedIdentityRepository
+	userRepo     repositories.UserRepository
+	users        *UserInteractor
+}
+
+// resolve mencari pengguna pemilik identitas eksternal: lewat identitas yang sudah terhubung,
+// lewat email terverifikasi, atau dengan membuat akun baru jika allowSignup bernilai true.
+// Akun superuser tidak pernah dihubungkan lewat email.
+func (a externalAccounts) resolve(external *services.ExternalIdentity, allowSignup bool) (*entities.User, error) {
+	now := time.Now()
+
+	identity, err := a.identityRepo.FindBySubject(external.Provider, external.Subject)
+	if err == nil {
+		if err := a.identityRepo.Touch(identity.ID, external.Email, now); err != nil {
+			log.Printf("Gagal mencatat login identitas %s: %v", identity.ID, err)
+		}
+		user, err := a.userRepo.FindByID(identity.UserID)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrExternalAccountNotFound
+			}
+			return nil, err
+		}
+		return user, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	// Menghubungkan lewat email tanpa verifikasi membuka jalan pengambilalihan akun
+	if !external.EmailVerified {
+		return nil, ErrExternalEmailNotVerified
+	}
+
+	provisioned := false
+	user, err := a.userRepo.FindByEmail(external.Email)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		if !allowSignup {
+			return nil, ErrExternalAccountNotFound
+		}
+		provisioned = true
+		user, err = a.users.ProvisionExternalUser(external.PreferredUsername, external.Email, external.FirstName, external.LastName)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if !provisioned && user.IsSuperuser {
+		log.Printf("Identitas %s:%s tidak dihubungkan otomatis ke superuser %s", external.Provider, external.Subject, user.ID)
+		return nil, ErrExternalLinkRequired
+	}
+
+	if err := a.identityRepo.Create(&entities.LinkedIdentity{
+		UserID:      user.ID,
+		Provider:    external.Provider,
+		Subject:     external.Subject,
+		Email:       external.Email,
+		Provisioned: provisioned,
+		LastLoginAt: &now,
+	}); err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// groupRoles memetakan grup dari sumber identitas eksternal (DN grup LDAP, atribut grup SAML) ke Role.
+// Role yang muncul di pemetaan dikelola sepenuhnya oleh sumber tersebut: diberikan saat pengguna masuk
+// grup dan dicabut saat keluar. Role lain tidak disentuh sehingga tetap bisa diberikan secara manual.
+// Role yang punya ApprovalPolicy tidak pernah diberikan lewat grup eksternal.
+type groupRoles struct {
+	mapping  map[string][]string // Kunci sudah dinormalisasi dengan normalizeGroup
+	roleRepo repositories.RoleRepository
+	grants   *RoleGrantInteractor
+}
+
+// newGroupRoles membuat groupRoles dari pemetaan nama grup ke nama Role.
+func newGroupRoles(mapping map[string][]string, rr repositories.RoleRepository, grants *RoleGrantInteractor) groupRoles {
+	normalized := make(map[string][]string, len(mapping))
+	for group, roles := range mapping {
+		normalized[normalizeGroup(group)] = roles
+	}
+	return groupRoles{mapping: normalized, roleRepo: rr, grants: grants}
+}
+
+// sync memberikan role dari grup pengguna dan mencabut role terkelola yang tidak lagi diberikan grup mana pun.
+// Role yang tidak boleh diberikan dilewati dan dicatat ke log agar role lain, termasuk pencabutan, tetap
+// disinkronkan.
+func (g groupRoles) sync(user *entities.User, groups []string) error {
+	if len(g.mapping) == 0 {
+		return nil
+	}
+
+	granted := make(map[string]bool)
+	for _, group := range groups {
+		for _, role := range g.mapping[normalizeGroup(group)] {
+			granted[role] = true
+		}
+	}
+
+	var managed []string
+	for _, roles := range g.mapping {
+		managed = append(managed, roles...)
+	}
+	roles, err := g.roleRepo.FindByNames(managed)
+	if err != nil {
+		return err
+	}
+
+	var grant, revoke []uuid.UUID
+	for n := range roles {
+		role := &roles[n]
+		switch {
+		case granted[role.Name] && !user.HasRole(role.Name):
+			admitted, err := g.admits(user, role, grant)
+			if err != nil {
+				return err
+			}
+			if !admitted {
+				granted[role.Name] = false
+				continue
+			}
+			grant = append(grant, role.ID)
+		case !granted[role.Name] && user.HasRole(role.Name):
+			revoke = append(revoke, role.ID)
+		}
+	}
+	if len(grant) == 0 && len(revoke) == 0 {
+		return nil
+	}
+	if err := g.roleRepo.UpdateUserRoles(user.ID, grant, revoke); err != nil {
+		return err
+	}
+
+	// Samakan Roles di memori agar kebijakan MFA pada login yang sama memakai role terbaru
+	current := make([]*entities.Role, 0, len(user.Roles)+len(grant))
+	for _, role := range user.Roles {
+		if !granted[role.Name] && containsRole(roles, role.Name) {
+			continue
+		}
+		current = append(current, role)
+	}
+	for n := range roles {
+		if granted[roles[n].Name] && !user.HasRole(roles[n].Name) {
+			current = append(current, &roles[n])
+		}
+	}
+	user.Roles = current
+	return nil
+}
+
+// admits mengembalikan false jika role tidak boleh diberikan ke pengguna lewat grup eksternal karena
+// memerlukan persetujuan, atau karena melanggar aturan pemisahan tugas bersama Role yang dipegang
+// pengguna dan Role granted yang sudah diterima dari sinkronisasi yang sama.
+func (g groupRoles) admits(user *entities.User, role *entities.Role, granted []uuid.UUID) (bool, error) {
+	err := g.grants.CheckNoApproval([]*entities.Role{role})
+	if err == nil {
+		err = g.grants.CheckConflicts(user.ID, append(append([]uuid.UUID(nil), granted...), role.ID)...)
+	}
+	if errors.Is(err, ErrRoleGrantApprovalRequired) || errors.Is(err, ErrSoDViolation) {
+		log.Printf("Role %s tidak diberikan ke %s lewat grup eksternal: %v", role.Name, user.Username, err)
+		return false, nil
+	}
+	return err == nil, err
+}
+
+// containsRole mengembalikan true jika roles berisi Role dengan nama tersebut.
+func containsRole(roles []entities.Role, name string) bool {
+	for _, role := range roles {
+		if role.Name == name {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeGroup menyeragamkan nama atau DN grup untuk perbandingan: huruf kecil dan tanpa spasi setelah koma.
+func normalizeGroup(group string) string {
+	parts := strings.Split(group, ",")
+	for n := range parts {
+		parts[n] = strings.TrimSpace(parts[n])
+	}
+	return strings.ToLower(strings.Join(parts, ","))
+}
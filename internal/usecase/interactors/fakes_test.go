@@ -0,0 +1,609 @@
+package interactors
+
+import (
+	"context"
+	"sort"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/infrastructure/persistence"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"gorm.io/gorm"
+)
+
+// newTestChallengeStore membuat ChallengeStore Redis asli di atas miniredis agar semantik
+// GETDEL dan SET NX ikut teruji.
+func newTestChallengeStore(t *testing.T) repositories.ChallengeStore {
+	t.Helper()
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	t.Cleanup(func() { client.Close() })
+	return persistence.NewChallengeStore(client)
+}
+
+// memoryUserRepository adalah UserRepository di memori untuk pengujian. Metode yang tidak
+// dipakai pengujian tidak diimplementasikan dan akan panic lewat interface tertanam yang nil.
+type memoryUserRepository struct {
+	repositories.UserRepository
+	mu    sync.Mutex
+	users map[uuid.UUID]*entities.User
+}
+
+func newMemoryUserRepository(users ...*entities.User) *memoryUserRepository {
+	repo := &memoryUserRepository{users: map[uuid.UUID]*entities.User{}}
+	for _, user := range users {
+		repo.users[user.ID] = user
+	}
+	return repo
+}
+
+func (r *memoryUserRepository) Create(user *entities.User) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if user.ID == uuid.Nil {
+		user.ID = uuid.New()
+	}
+	stored := *user
+	r.users[user.ID] = &stored
+	return user, nil
+}
+
+func (r *memoryUserRepository) FindByID(id uuid.UUID) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	user, ok := r.users[id]
+	if !ok {
+		return nil, gorm.ErrRecordNotFound
+	}
+	found := *user
+	return &found, nil
+}
+
+func (r *memoryUserRepository) FindByEmail(email string) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if strings.EqualFold(user.Email, email) {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryUserRepository) FindByUsernameOrEmail(identifier string) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, user := range r.users {
+		if user.Username == identifier || user.Email == identifier {
+			found := *user
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryUserRepository) FindAll() ([]entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	users := make([]entities.User, 0, len(r.users))
+	for _, user := range r.users {
+		users = append(users, *user)
+	}
+	return users, nil
+}
+
+func (r *memoryUserRepository) FindAllWithRoles() ([]entities.User, error) {
+	users, _ := r.FindAll()
+	sort.Slice(users, func(a, b int) bool { return users[a].Username < users[b].Username })
+	return users, nil
+}
+
+func (r *memoryUserRepository) Update(user *entities.User) (*entities.User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if _, ok := r.users[user.ID]; !ok {
+		return user, gorm.ErrRecordNotFound
+	}
+	stored := *user
+	r.users[user.ID] = &stored
+	return user, nil
+}
+
+// memoryWebAuthnCredentialRepository adalah WebAuthnCredentialRepository di memori untuk pengujian.
+type memoryWebAuthnCredentialRepository struct {
+	mu          sync.Mutex
+	credentials []entities.WebAuthnCredential
+}
+
+func (r *memoryWebAuthnCredentialRepository) Create(credential *entities.WebAuthnCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	credential.ID = uuid.New()
+	r.credentials = append(r.credentials, *credential)
+	return nil
+}
+
+func (r *memoryWebAuthnCredentialRepository) FindByUser(userID uuid.UUID) ([]entities.WebAuthnCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []entities.WebAuthnCredential
+	for _, credential := range r.credentials {
+		if credential.UserID == userID {
+			found = append(found, credential)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryWebAuthnCredentialRepository) FindByID(userID, id uuid.UUID) (*entities.WebAuthnCredential, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, credential := range r.credentials {
+		if credential.UserID == userID && credential.ID == id {
+			found := credential
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryWebAuthnCredentialRepository) CountByUser(userID uuid.UUID) (int64, error) {
+	found, err := r.FindByUser(userID)
+	return int64(len(found)), err
+}
+
+func (r *memoryWebAuthnCredentialRepository) Update(credential *entities.WebAuthnCredential) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n := range r.credentials {
+		if r.credentials[n].ID == credential.ID {
+			r.credentials[n] = *credential
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryWebAuthnCredentialRepository) Delete(userID, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, credential := range r.credentials {
+		if credential.UserID == userID && credential.ID == id {
+			r.credentials = append(r.credentials[:n], r.credentials[n+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// memoryLinkedIdentityRepository adalah LinkedIdentityRepository di memori untuk pengujian.
+type memoryLinkedIdentityRepository struct {
+	mu         sync.Mutex
+	identities []entities.LinkedIdentity
+}
+
+func (r *memoryLinkedIdentityRepository) Create(identity *entities.LinkedIdentity) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return gorm.ErrDuplicatedKey
+		}
+	}
+	identity.ID = uuid.New()
+	r.identities = append(r.identities, *identity)
+	return nil
+}
+
+func (r *memoryLinkedIdentityRepository) FindBySubject(provider, subject string) (*entities.LinkedIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, identity := range r.identities {
+		if identity.Provider == provider && identity.Subject == subject {
+			found := identity
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryLinkedIdentityRepository) FindByUser(userID uuid.UUID) ([]entities.LinkedIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []entities.LinkedIdentity
+	for _, identity := range r.identities {
+		if identity.UserID == userID {
+			found = append(found, identity)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryLinkedIdentityRepository) FindByProvider(provider string) ([]entities.LinkedIdentity, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var found []entities.LinkedIdentity
+	for _, identity := range r.identities {
+		if identity.Provider == provider {
+			found = append(found, identity)
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryLinkedIdentityRepository) Touch(id uuid.UUID, email string, loginAt time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n := range r.identities {
+		if r.identities[n].ID == id {
+			r.identities[n].Email = email
+			r.identities[n].LastLoginAt = &loginAt
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryLinkedIdentityRepository) Delete(userID, id uuid.UUID) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n, identity := range r.identities {
+		if identity.UserID == userID && identity.ID == id {
+			r.identities = append(r.identities[:n], r.identities[n+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// memoryRoleRepository adalah RoleRepository di memori untuk pengujian. Pemberian dan pencabutan
+// Role langsung tercermin pada pengguna di memoryUserRepository yang sama, dan Role anggota organisasi
+// dibaca dari members jika diisi.
+type memoryRoleRepository struct {
+	repositories.RoleRepository
+	users   *memoryUserRepository
+	members *memoryOrganizationMemberRepository
+	roles   []entities.Role
+}
+
+func (r *memoryRoleRepository) FindGraph() ([]entities.Role, error) {
+	return append([]entities.Role(nil), r.roles...), nil
+}
+
+func (r *memoryRoleRepository) FindAssignments(userID uuid.UUID) ([]repositories.RoleAssignment, error) {
+	var assignments []repositories.RoleAssignment
+	r.users.mu.Lock()
+	if user, ok := r.users.users[userID]; ok {
+		for _, role := range user.Roles {
+			assignments = append(assignments, repositories.RoleAssignment{UserID: userID, RoleID: role.ID})
+		}
+	}
+	r.users.mu.Unlock()
+	if r.members == nil {
+		return assignments, nil
+	}
+	for _, member := range r.members.members {
+		if member.UserID != userID {
+			continue
+		}
+		organizationID := member.OrganizationID
+		for _, role := range member.Roles {
+			assignments = append(assignments, repositories.RoleAssignment{UserID: userID, RoleID: role.ID, OrganizationID: &organizationID})
+		}
+	}
+	return assignments, nil
+}
+
+func (r *memoryRoleRepository) FindAllAssignments() ([]repositories.RoleAssignment, error) {
+	users, _ := r.users.FindAllWithRoles()
+	var assignments []repositories.RoleAssignment
+	for _, user := range users {
+		found, _ := r.FindAssignments(user.ID)
+		assignments = append(assignments, found...)
+	}
+	return assignments, nil
+}
+
+func (r *memoryRoleRepository) FindGrantsByRoles(roleIDs []uuid.UUID) ([]entities.UserRole, error) {
+	inScope := make(map[uuid.UUID]bool, len(roleIDs))
+	for _, id := range roleIDs {
+		inScope[id] = true
+	}
+	users, _ := r.users.FindAllWithRoles()
+	var grants []entities.UserRole
+	for _, user := range users {
+		for _, role := range user.Roles {
+			if len(inScope) == 0 || inScope[role.ID] {
+				grants = append(grants, entities.UserRole{UserID: user.ID, RoleID: role.ID})
+			}
+		}
+	}
+	return grants, nil
+}
+
+func (r *memoryRoleRepository) Create(role *entities.Role) error {
+	role.ID = uuid.New()
+	r.roles = append(r.roles, *role)
+	return nil
+}
+
+func (r *memoryRoleRepository) FindByID(id uuid.UUID) (*entities.Role, error) {
+	for n := range r.roles {
+		if r.roles[n].ID == id {
+			role := r.withUsers(r.roles[n])
+			return &role, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryRoleRepository) FindByName(name string) (*entities.Role, error) {
+	for n := range r.roles {
+		if r.roles[n].Name == name {
+			role := r.roles[n]
+			return &role, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryRoleRepository) FindAll() ([]entities.Role, error) {
+	roles := make([]entities.Role, 0, len(r.roles))
+	for _, role := range r.roles {
+		roles = append(roles, r.withUsers(role))
+	}
+	sort.Slice(roles, func(a, b int) bool { return roles[a].Name < roles[b].Name })
+	return roles, nil
+}
+
+func (r *memoryRoleRepository) Update(role *entities.Role) error {
+	for n := range r.roles {
+		if r.roles[n].ID == role.ID {
+			r.roles[n].Name, r.roles[n].Description = role.Name, role.Description
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryRoleRepository) Delete(id uuid.UUID) error {
+	for n := range r.roles {
+		if r.roles[n].ID == id {
+			r.roles = append(r.roles[:n], r.roles[n+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryRoleRepository) UpdateMembers(roleID uuid.UUID, add, remove []uuid.UUID) error {
+	for _, userID := range add {
+		if err := r.UpdateUserRoles(userID, []uuid.UUID{roleID}, nil); err != nil {
+			return err
+		}
+	}
+	for _, userID := range remove {
+		if err := r.UpdateUserRoles(userID, nil, []uuid.UUID{roleID}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// withUsers mengisi Users role dengan pengguna memoryUserRepository yang memegangnya secara langsung.
+func (r *memoryRoleRepository) withUsers(role entities.Role) entities.Role {
+	r.users.mu.Lock()
+	defer r.users.mu.Unlock()
+	role.Users = nil
+	for _, user := range r.users.users {
+		for _, held := range user.Roles {
+			if held.ID == role.ID {
+				role.Users = append(role.Users, user)
+				break
+			}
+		}
+	}
+	sort.Slice(role.Users, func(a, b int) bool { return role.Users[a].Username < role.Users[b].Username })
+	return role
+}
+
+func (r *memoryRoleRepository) FindByUser(userID uuid.UUID) ([]entities.Role, error) {
+	r.users.mu.Lock()
+	defer r.users.mu.Unlock()
+	var roles []entities.Role
+	if user, ok := r.users.users[userID]; ok {
+		for _, role := range user.Roles {
+			roles = append(roles, *role)
+		}
+	}
+	return roles, nil
+}
+
+func (r *memoryRoleRepository) FindByNames(names []string) ([]entities.Role, error) {
+	var found []entities.Role
+	for _, role := range r.roles {
+		for _, name := range names {
+			if role.Name == name {
+				found = append(found, role)
+				break
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *memoryRoleRepository) UpdateUserRoles(userID uuid.UUID, grant, revoke []uuid.UUID) error {
+	r.users.mu.Lock()
+	defer r.users.mu.Unlock()
+	user, ok := r.users.users[userID]
+	if !ok {
+		return gorm.ErrRecordNotFound
+	}
+
+	roles := make([]*entities.Role, 0, len(user.Roles)+len(grant))
+	for _, role := range user.Roles {
+		revoked := false
+		for _, id := range revoke {
+			revoked = revoked || role.ID == id
+		}
+		if !revoked {
+			roles = append(roles, role)
+		}
+	}
+	for _, id := range grant {
+		for n := range r.roles {
+			if r.roles[n].ID == id {
+				role := r.roles[n]
+				roles = append(roles, &role)
+			}
+		}
+	}
+	user.Roles = roles
+	return nil
+}
+
+// memoryOrganizationMemberRepository adalah OrganizationMemberRepository di memori untuk pengujian. Role
+// anggota diambil dari memoryRoleRepository yang sama.
+type memoryOrganizationMemberRepository struct {
+	repositories.OrganizationMemberRepository
+	roles   *memoryRoleRepository
+	members []entities.OrganizationMember
+}
+
+func (r *memoryOrganizationMemberRepository) Create(member *entities.OrganizationMember) error {
+	member.ID = uuid.New()
+	r.members = append(r.members, *member)
+	return nil
+}
+
+func (r *memoryOrganizationMemberRepository) Find(organizationID, userID uuid.UUID) (*entities.OrganizationMember, error) {
+	for _, member := range r.members {
+		if member.OrganizationID == organizationID && member.UserID == userID {
+			found := member
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryOrganizationMemberRepository) ReplaceRoles(memberID uuid.UUID, roleIDs []uuid.UUID) error {
+	for n := range r.members {
+		if r.members[n].ID != memberID {
+			continue
+		}
+		roles := make([]*entities.Role, 0, len(roleIDs))
+		for _, id := range roleIDs {
+			for m := range r.roles.roles {
+				if r.roles.roles[m].ID == id {
+					roles = append(roles, &r.roles.roles[m])
+				}
+			}
+		}
+		r.members[n].Roles = roles
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// staticOrganizationRepository adalah OrganizationRepository uji dengan daftar organisasi tetap.
+type staticOrganizationRepository struct {
+	repositories.OrganizationRepository
+	organizations []entities.Organization
+}
+
+func (r *staticOrganizationRepository) FindByID(id uuid.UUID) (*entities.Organization, error) {
+	for n := range r.organizations {
+		if r.organizations[n].ID == id {
+			return &r.organizations[n], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *staticOrganizationRepository) FindAll() ([]entities.Organization, error) {
+	return r.organizations, nil
+}
+
+// staticApprovalPolicyRepository adalah ApprovalPolicyRepository uji dengan kebijakan persetujuan tetap
+// untuk Role roles.
+type staticApprovalPolicyRepository struct {
+	repositories.ApprovalPolicyRepository
+	roles []uuid.UUID
+}
+
+func (r *staticApprovalPolicyRepository) FindByRole(roleID *uuid.UUID) (*entities.ApprovalPolicy, error) {
+	for _, id := range r.roles {
+		if roleID != nil && *roleID == id {
+			return &entities.ApprovalPolicy{RoleID: roleID, RequiredApprovals: 1}, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *staticApprovalPolicyRepository) FindAll() ([]entities.ApprovalPolicy, error) {
+	policies := make([]entities.ApprovalPolicy, 0, len(r.roles))
+	for _, id := range r.roles {
+		roleID := id
+		policies = append(policies, entities.ApprovalPolicy{RoleID: &roleID, RequiredApprovals: 1})
+	}
+	return policies, nil
+}
+
+// memorySessionRepository adalah SessionRepository di memori untuk pengujian.
+type memorySessionRepository struct {
+	repositories.SessionRepository
+	mu       sync.Mutex
+	sessions []entities.Session
+}
+
+func (r *memorySessionRepository) Create(_ context.Context, session *entities.Session) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.sessions = append(r.sessions, *session)
+	return nil
+}
+
+func (r *memorySessionRepository) FindByID(_ context.Context, id uuid.UUID) (*entities.Session, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n := range r.sessions {
+		if r.sessions[n].ID == id {
+			session := r.sessions[n]
+			return &session, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memorySessionRepository) Revoke(_ context.Context, id uuid.UUID, now time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for n := range r.sessions {
+		if r.sessions[n].ID == id && r.sessions[n].RevokedAt == nil {
+			r.sessions[n].RevokedAt = &now
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memorySessionRepository) RevokeAllByUser(_ context.Context, userID uuid.UUID, now time.Time) (int64, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var revoked int64
+	for n := range r.sessions {
+		if r.sessions[n].UserID == userID && r.sessions[n].IsActive(now) {
+			r.sessions[n].RevokedAt = &now
+			revoked++
+		}
+	}
+	return revoked, nil
+}
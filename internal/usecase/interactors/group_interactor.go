@@ -0,0 +1,384 @@
+package interactors
+
+import (
+	"errors"
+	"strings"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrGroupNotFound dikembalikan jika grup tidak ada.
+	ErrGroupNotFound = errors.New("grup tidak ditemukan")
+	// ErrGroupNameRequired dikembalikan jika nama grup kosong.
+	ErrGroupNameRequired = errors.New("nama grup wajib diisi")
+	// ErrGroupNameTaken dikembalikan jika nama grup sudah dipakai grup lain.
+	ErrGroupNameTaken = errors.New("nama grup sudah dipakai")
+	// ErrGroupCycle dikembalikan jika penambahan subgrup akan membuat grup memuat dirinya sendiri.
+	ErrGroupCycle = errors.New("subgrup tidak boleh memuat grup induknya sendiri")
+	// ErrGroupMemberNotFound dikembalikan jika pengguna bukan anggota langsung grup.
+	ErrGroupMemberNotFound = errors.New("pengguna bukan anggota grup")
+	// ErrSubgroupNotFound dikembalikan jika grup bukan subgrup langsung dari grup induk.
+	ErrSubgroupNotFound = errors.New("subgrup tidak ditemukan")
+	// ErrGroupUserNotFound dikembalikan jika pengguna yang akan ditambahkan tidak ada.
+	ErrGroupUserNotFound = errors.New("pengguna tidak ditemukan")
+	// ErrGroupRoleNotFound dikembalikan jika salah satu role yang diminta tidak terdaftar.
+	ErrGroupRoleNotFound = errors.New("role tidak ditemukan")
+)
+
+// PermissionGrant adalah satu jalur yang memberikan (atau menolak secara eksplisit) permission ke pengguna.
+type PermissionGrant struct {
+	Role       string   `json:"role"`
+	Permission string   `json:"permission"`       // Permission Role yang cocok, bisa berupa wildcard
+	Deny       bool     `json:"deny,omitempty"`   // Deny eksplisit; mengalahkan semua grant lain
+	Groups     []string `json:"groups,omitempty"` // Dari grup tempat pengguna menjadi anggota langsung sampai grup pemberi Role; kosong berarti Role langsung
+	Via        []string `json:"via,omitempty"`    // Rantai Role induk dari Role sampai Role yang memuat permission; kosong berarti Role itu sendiri
+}
+
+// PermissionExplanation menjelaskan mengapa pengguna memiliki (atau tidak memiliki) sebuah permission.
+type PermissionExplanation struct {
+	UserID     uuid.UUID         `json:"user_id"`
+	Permission string            `json:"permission"`
+	Granted    bool              `json:"granted"`
+	Superuser  bool              `json:"superuser"` // Superuser memiliki semua permission tanpa Role
+	Grants     []PermissionGrant `json:"grants"`
+}
+
+// GroupInteractor adalah use case untuk grup pengguna bertingkat dan Role yang diberikan ke grup.
+// Perubahan yang memberi anggota Role baru ditolak jika melanggar aturan pemisahan tugas, atau jika Role
+// itu punya kebijakan persetujuan: Role seperti itu hanya bisa diberikan per pengguna lewat ApprovalInteractor.
+type GroupInteractor struct {
+	groupRepo      repositories.GroupRepository
+	userRepo       repositories.UserRepository
+	roleRepo       repositories.RoleRepository
+	permissionRepo repositories.PermissionRepository
+	sod            *SoDInteractor
+	grants         *RoleGrantInteractor
+}
+
+// NewGroupInteractor membuat instance baru dari GroupInteractor.
+func NewGroupInteractor(
+	gr repositories.GroupRepository,
+	ur repositories.UserRepository,
+	rr repositories.RoleRepository,
+	pr repositories.PermissionRepository,
+	sod *SoDInteractor,
+	grants *RoleGrantInteractor,
+) *GroupInteractor {
+	return &GroupInteractor{groupRepo: gr, userRepo: ur, roleRepo: rr, permissionRepo: pr, sod: sod, grants: grants}
+}
+
+// Create membuat grup baru.
+func (i *GroupInteractor) Create(name, description string) (*entities.Group, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrGroupNameRequired
+	}
+	if err := i.checkNameAvailable(name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	group := &entities.Group{Name: name, Description: description}
+	if err := i.groupRepo.Create(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// List mengembalikan semua grup beserta Role-nya.
+func (i *GroupInteractor) List() ([]entities.Group, error) {
+	return i.groupRepo.FindAll()
+}
+
+// Get mengembalikan grup beserta anggota langsung, subgrup dan Role-nya.
+func (i *GroupInteractor) Get(id uuid.UUID) (*entities.Group, error) {
+	group, err := i.groupRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupNotFound
+		}
+		return nil, err
+	}
+	return group, nil
+}
+
+// Update mengganti nama dan deskripsi grup.
+func (i *GroupInteractor) Update(id uuid.UUID, name, description string) (*entities.Group, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrGroupNameRequired
+	}
+
+	group, err := i.Get(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.checkNameAvailable(name, id); err != nil {
+		return nil, err
+	}
+
+	group.Name = name
+	group.Description = description
+	if err := i.groupRepo.Update(group); err != nil {
+		return nil, err
+	}
+	return group, nil
+}
+
+// Delete menghapus grup. Anggotanya langsung kehilangan Role yang diterima lewat grup ini.
+func (i *GroupInteractor) Delete(id uuid.UUID) error {
+	if err := i.groupRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrGroupNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AddMember menambahkan pengguna sebagai anggota langsung grup.
+func (i *GroupInteractor) AddMember(groupID, userID uuid.UUID) (*entities.Group, error) {
+	if _, err := i.Get(groupID); err != nil {
+		return nil, err
+	}
+	if _, err := i.userRepo.FindByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupUserNotFound
+		}
+		return nil, err
+	}
+	if err := i.checkNoApproval(groupID); err != nil {
+		return nil, err
+	}
+	if err := i.sod.CheckGroupMember(groupID, userID); err != nil {
+		return nil, err
+	}
+
+	if err := i.groupRepo.AddMember(groupID, userID); err != nil {
+		return nil, err
+	}
+	return i.Get(groupID)
+}
+
+// RemoveMember mengeluarkan pengguna dari keanggotaan langsung grup.
+func (i *GroupInteractor) RemoveMember(groupID, userID uuid.UUID) error {
+	if err := i.groupRepo.RemoveMember(groupID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrGroupMemberNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// AddSubgroup menjadikan grup subgroupID bagian dari grup groupID sehingga anggotanya ikut menerima
+// Role grup groupID dan semua induknya. Ditolak jika subgroupID adalah groupID sendiri atau salah satu induknya.
+func (i *GroupInteractor) AddSubgroup(groupID, subgroupID uuid.UUID) (*entities.Group, error) {
+	if groupID == subgroupID {
+		return nil, ErrGroupCycle
+	}
+	if _, err := i.Get(groupID); err != nil {
+		return nil, err
+	}
+	if _, err := i.Get(subgroupID); err != nil {
+		return nil, err
+	}
+
+	ancestors, err := i.groupRepo.FindAncestorIDs(groupID)
+	if err != nil {
+		return nil, err
+	}
+	for _, ancestor := range ancestors {
+		if ancestor == subgroupID {
+			return nil, ErrGroupCycle
+		}
+	}
+	if err := i.checkNoApproval(groupID); err != nil {
+		return nil, err
+	}
+	if err := i.sod.CheckSubgroup(groupID, subgroupID); err != nil {
+		return nil, err
+	}
+
+	if err := i.groupRepo.AddSubgroup(groupID, subgroupID); err != nil {
+		return nil, err
+	}
+	return i.Get(groupID)
+}
+
+// RemoveSubgroup melepas subgrup dari grup induknya.
+func (i *GroupInteractor) RemoveSubgroup(groupID, subgroupID uuid.UUID) error {
+	if err := i.groupRepo.RemoveSubgroup(groupID, subgroupID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSubgroupNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// SetRoles mengganti seluruh Role yang diberikan ke grup. Role baru yang punya kebijakan persetujuan
+// ditolak; Role yang sudah diberikan ke grup sebelumnya boleh tetap ada.
+func (i *GroupInteractor) SetRoles(groupID uuid.UUID, roleNames []string) (*entities.Group, error) {
+	group, err := i.Get(groupID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := resolveRoles(i.roleRepo, roleNames, ErrGroupRoleNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	current := make(map[uuid.UUID]bool, len(group.Roles))
+	for _, role := range group.Roles {
+		current[role.ID] = true
+	}
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	var added []*entities.Role
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+		if !current[role.ID] {
+			added = append(added, role)
+		}
+	}
+	if err := i.grants.CheckNoApproval(added); err != nil {
+		return nil, err
+	}
+	if err := i.sod.CheckGroupRoles(groupID, roleIDs); err != nil {
+		return nil, err
+	}
+	if err := i.groupRepo.ReplaceRoles(groupID, roleIDs); err != nil {
+		return nil, err
+	}
+	return i.Get(groupID)
+}
+
+// EffectivePermissions mengembalikan semua permission pengguna dari Role langsung dan Role grupnya.
+func (i *GroupInteractor) EffectivePermissions(userID uuid.UUID) ([]string, error) {
+	if _, err := i.userRepo.FindByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupUserNotFound
+		}
+		return nil, err
+	}
+	return i.permissionRepo.FindNamesByUser(userID)
+}
+
+// Explain menjelaskan dari mana saja pengguna menerima permission: Role langsung, atau Role grup
+// beserta rantai grup dari keanggotaan langsung sampai grup pemberi Role, dan rantai pewarisan Role
+// jika permission berasal dari Role induk.
+func (i *GroupInteractor) Explain(userID uuid.UUID, permission string) (*PermissionExplanation, error) {
+	user, err := i.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrGroupUserNotFound
+		}
+		return nil, err
+	}
+
+	explanation := &PermissionExplanation{
+		UserID:     user.ID,
+		Permission: permission,
+		Superuser:  user.IsSuperuser,
+		Grants:     []PermissionGrant{},
+	}
+
+	all, err := i.roleRepo.FindGraph()
+	if err != nil {
+		return nil, err
+	}
+	graph := newRoleGraph(all)
+
+	roles, err := i.roleRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	for _, role := range roles {
+		for _, grant := range graph.grants(role.ID, permission) {
+			explanation.Grants = append(explanation.Grants, PermissionGrant{Role: role.Name, Permission: grant.matched, Deny: grant.deny, Via: grant.via})
+		}
+	}
+
+	// Telusuri grup ke atas secara melebar; grup yang sudah dikunjungi dilewati agar siklus tidak berulang
+	type step struct {
+		group entities.Group
+		path  []string
+	}
+	direct, err := i.groupRepo.FindByMember(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	queue := make([]step, 0, len(direct))
+	visited := make(map[uuid.UUID]bool, len(direct))
+	for _, group := range direct {
+		visited[group.ID] = true
+		queue = append(queue, step{group: group, path: []string{group.Name}})
+	}
+
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+
+		for _, role := range current.group.Roles {
+			for _, grant := range graph.grants(role.ID, permission) {
+				explanation.Grants = append(explanation.Grants, PermissionGrant{
+					Role:       role.Name,
+					Permission: grant.matched,
+					Deny:       grant.deny,
+					Groups:     current.path,
+					Via:        grant.via,
+				})
+			}
+		}
+
+		parents, err := i.groupRepo.FindParents(current.group.ID)
+		if err != nil {
+			return nil, err
+		}
+		for _, parent := range parents {
+			if visited[parent.ID] {
+				continue
+			}
+			visited[parent.ID] = true
+			path := append(append([]string{}, current.path...), parent.Name)
+			queue = append(queue, step{group: parent, path: path})
+		}
+	}
+
+	allowed, denied := false, false
+	for _, grant := range explanation.Grants {
+		if grant.Deny {
+			denied = true
+		} else {
+			allowed = true
+		}
+	}
+	explanation.Granted = explanation.Superuser || (allowed && !denied)
+	return explanation, nil
+}
+
+// checkNoApproval memastikan grup dan grup induknya tidak memberikan Role yang punya kebijakan persetujuan
+// sebelum pengguna atau subgrup baru menerima Role-nya.
+func (i *GroupInteractor) checkNoApproval(groupID uuid.UUID) error {
+	roles, err := inheritedGroupRoles(i.groupRepo, groupID)
+	if err != nil {
+		return err
+	}
+	return i.grants.CheckNoApproval(roles)
+}
+
+// checkNameAvailable memastikan nama grup belum dipakai grup lain selain except.
+func (i *GroupInteractor) checkNameAvailable(name string, except uuid.UUID) error {
+	existing, err := i.groupRepo.FindByName(name)
+	if err == nil && existing.ID != except {
+		return ErrGroupNameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
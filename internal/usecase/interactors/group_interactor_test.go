@@ -0,0 +1,354 @@
+package interactors
+
+import (
+	"errors"
+	"reflect"
+	"sort"
+	"testing"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryGroupRepository adalah GroupRepository di memori untuk pengujian. Anggota langsung dan subgrup
+// disimpan per grup; Role grup dibaca dari memoryRoleRepository yang sama.
+type memoryGroupRepository struct {
+	repositories.GroupRepository
+	roles     *memoryRoleRepository
+	groups    []entities.Group
+	members   map[uuid.UUID][]uuid.UUID
+	subgroups map[uuid.UUID][]uuid.UUID
+}
+
+func newMemoryGroupRepository(roles *memoryRoleRepository) *memoryGroupRepository {
+	return &memoryGroupRepository{roles: roles, members: map[uuid.UUID][]uuid.UUID{}, subgroups: map[uuid.UUID][]uuid.UUID{}}
+}
+
+func (r *memoryGroupRepository) Create(group *entities.Group) error {
+	group.ID = uuid.New()
+	r.groups = append(r.groups, *group)
+	return nil
+}
+
+func (r *memoryGroupRepository) FindByID(id uuid.UUID) (*entities.Group, error) {
+	for n := range r.groups {
+		if r.groups[n].ID == id {
+			group := r.groups[n]
+			return &group, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryGroupRepository) FindByName(name string) (*entities.Group, error) {
+	for n := range r.groups {
+		if r.groups[n].Name == name {
+			group := r.groups[n]
+			return &group, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryGroupRepository) FindAll() ([]entities.Group, error) {
+	groups := append([]entities.Group(nil), r.groups...)
+	sort.Slice(groups, func(a, b int) bool { return groups[a].Name < groups[b].Name })
+	return groups, nil
+}
+
+func (r *memoryGroupRepository) FindByMember(userID uuid.UUID) ([]entities.Group, error) {
+	var groups []entities.Group
+	for _, group := range r.groups {
+		if containsID(r.members[group.ID], userID) {
+			groups = append(groups, group)
+		}
+	}
+	return groups, nil
+}
+
+func (r *memoryGroupRepository) FindParents(id uuid.UUID) ([]entities.Group, error) {
+	var parents []entities.Group
+	for _, group := range r.groups {
+		if containsID(r.subgroups[group.ID], id) {
+			parents = append(parents, group)
+		}
+	}
+	return parents, nil
+}
+
+func (r *memoryGroupRepository) FindAncestorIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	var ancestors []uuid.UUID
+	visited := map[uuid.UUID]bool{id: true}
+	queue := []uuid.UUID{id}
+	for len(queue) > 0 {
+		parents, _ := r.FindParents(queue[0])
+		queue = queue[1:]
+		for _, parent := range parents {
+			if !visited[parent.ID] {
+				visited[parent.ID] = true
+				ancestors = append(ancestors, parent.ID)
+				queue = append(queue, parent.ID)
+			}
+		}
+	}
+	return ancestors, nil
+}
+
+func (r *memoryGroupRepository) FindMemberIDs(id uuid.UUID) ([]uuid.UUID, error) {
+	var userIDs []uuid.UUID
+	visited := map[uuid.UUID]bool{id: true}
+	queue := []uuid.UUID{id}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		userIDs = append(userIDs, r.members[current]...)
+		for _, subgroup := range r.subgroups[current] {
+			if !visited[subgroup] {
+				visited[subgroup] = true
+				queue = append(queue, subgroup)
+			}
+		}
+	}
+	return userIDs, nil
+}
+
+func (r *memoryGroupRepository) Update(group *entities.Group) error {
+	for n := range r.groups {
+		if r.groups[n].ID == group.ID {
+			r.groups[n].Name, r.groups[n].Description = group.Name, group.Description
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func (r *memoryGroupRepository) AddMember(groupID, userID uuid.UUID) error {
+	if !containsID(r.members[groupID], userID) {
+		r.members[groupID] = append(r.members[groupID], userID)
+	}
+	return nil
+}
+
+func (r *memoryGroupRepository) RemoveMember(groupID, userID uuid.UUID) error {
+	return removeID(r.members, groupID, userID)
+}
+
+func (r *memoryGroupRepository) AddSubgroup(groupID, subgroupID uuid.UUID) error {
+	if !containsID(r.subgroups[groupID], subgroupID) {
+		r.subgroups[groupID] = append(r.subgroups[groupID], subgroupID)
+	}
+	return nil
+}
+
+func (r *memoryGroupRepository) RemoveSubgroup(groupID, subgroupID uuid.UUID) error {
+	return removeID(r.subgroups, groupID, subgroupID)
+}
+
+func (r *memoryGroupRepository) ReplaceRoles(groupID uuid.UUID, roleIDs []uuid.UUID) error {
+	for n := range r.groups {
+		if r.groups[n].ID != groupID {
+			continue
+		}
+		r.groups[n].Roles = nil
+		for _, id := range roleIDs {
+			role, err := r.roles.FindByID(id)
+			if err != nil {
+				return err
+			}
+			r.groups[n].Roles = append(r.groups[n].Roles, role)
+		}
+		return nil
+	}
+	return gorm.ErrRecordNotFound
+}
+
+func containsID(ids []uuid.UUID, id uuid.UUID) bool {
+	for _, candidate := range ids {
+		if candidate == id {
+			return true
+		}
+	}
+	return false
+}
+
+// removeID menghapus id dari daftar milik key, atau gagal dengan gorm.ErrRecordNotFound jika tidak ada.
+func removeID(lists map[uuid.UUID][]uuid.UUID, key, id uuid.UUID) error {
+	for n, candidate := range lists[key] {
+		if candidate == id {
+			lists[key] = append(lists[key][:n], lists[key][n+1:]...)
+			return nil
+		}
+	}
+	return gorm.ErrRecordNotFound
+}
+
+// groupFixture menyusun GroupInteractor di atas repository memori.
+type groupFixture struct {
+	groups    *GroupInteractor
+	groupRepo *memoryGroupRepository
+	roleRepo  *memoryRoleRepository
+	users     *memoryUserRepository
+	policies  *staticApprovalPolicyRepository
+	ids       map[string]uuid.UUID // ID grup berdasarkan nama
+}
+
+func newGroupFixture(t *testing.T, roles []entities.Role, users ...*entities.User) *groupFixture {
+	t.Helper()
+	f := &groupFixture{users: newMemoryUserRepository(users...), policies: &staticApprovalPolicyRepository{}, ids: map[string]uuid.UUID{}}
+	f.roleRepo = &memoryRoleRepository{users: f.users, roles: roles}
+	f.groupRepo = newMemoryGroupRepository(f.roleRepo)
+	sod := NewSoDInteractor(&staticSoDRuleRepository{}, f.roleRepo, f.groupRepo, f.users, nil)
+	grants := NewRoleGrantInteractor(f.roleRepo, f.users, f.policies, sod, NewAuditInteractor(discardAuditLogRepository{}), nil)
+	f.groups = NewGroupInteractor(f.groupRepo, f.users, f.roleRepo, nil, sod, grants)
+	return f
+}
+
+// create membuat grup name dengan Role roles, lalu menjadikannya subgrup parent jika parent tidak kosong.
+func (f *groupFixture) create(t *testing.T, name, parent string, roles ...string) uuid.UUID {
+	t.Helper()
+	group, err := f.groups.Create(name, "")
+	if err != nil {
+		t.Fatalf("Create(%s): %v", name, err)
+	}
+	if _, err := f.groups.SetRoles(group.ID, roles); err != nil {
+		t.Fatalf("SetRoles(%s): %v", name, err)
+	}
+	if parent != "" {
+		if _, err := f.groups.AddSubgroup(f.ids[parent], group.ID); err != nil {
+			t.Fatalf("AddSubgroup(%s, %s): %v", parent, name, err)
+		}
+	}
+	f.ids[name] = group.ID
+	return group.ID
+}
+
+func TestGroupRejectsInvalidChanges(t *testing.T) {
+	alice := &entities.User{ID: uuid.New(), Username: "alice"}
+	f := newGroupFixture(t, nil, alice)
+	engineering := f.create(t, "engineering", "")
+	backend := f.create(t, "backend", "engineering")
+	api := f.create(t, "api", "backend")
+	missing := uuid.New()
+
+	tests := []struct {
+		name    string
+		call    func() error
+		wantErr error
+	}{
+		{"grup menjadi subgrup dirinya", func() error { _, err := f.groups.AddSubgroup(api, api); return err }, ErrGroupCycle},
+		{"induk langsung menjadi subgrup", func() error { _, err := f.groups.AddSubgroup(api, backend); return err }, ErrGroupCycle},
+		{"induk bertingkat menjadi subgrup", func() error { _, err := f.groups.AddSubgroup(api, engineering); return err }, ErrGroupCycle},
+		{"grup induk tidak ada", func() error { _, err := f.groups.AddSubgroup(missing, api); return err }, ErrGroupNotFound},
+		{"subgrup tidak ada", func() error { _, err := f.groups.AddSubgroup(api, missing); return err }, ErrGroupNotFound},
+		{"bukan subgrup langsung", func() error { return f.groups.RemoveSubgroup(engineering, api) }, ErrSubgroupNotFound},
+		{"pengguna tidak ada", func() error { _, err := f.groups.AddMember(api, missing); return err }, ErrGroupUserNotFound},
+		{"bukan anggota", func() error { return f.groups.RemoveMember(api, alice.ID) }, ErrGroupMemberNotFound},
+		{"nama kosong", func() error { _, err := f.groups.Create(" ", ""); return err }, ErrGroupNameRequired},
+		{"nama dipakai", func() error { _, err := f.groups.Create("backend", ""); return err }, ErrGroupNameTaken},
+		{"ganti ke nama grup lain", func() error { _, err := f.groups.Update(api, "backend", ""); return err }, ErrGroupNameTaken},
+		{"Role tidak ada", func() error { _, err := f.groups.SetRoles(api, []string{"ghost"}); return err }, ErrGroupRoleNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := tt.call(); !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, ingin %v", err, tt.wantErr)
+			}
+		})
+	}
+
+	if ancestors, _ := f.groupRepo.FindAncestorIDs(engineering); len(ancestors) != 0 {
+		t.Fatalf("induk engineering = %v, ingin tidak ada", ancestors)
+	}
+	if _, err := f.groups.Update(api, "api", "layanan API"); err != nil {
+		t.Fatalf("Update dengan nama sendiri: %v", err)
+	}
+}
+
+func TestGroupRejectsApprovalRoles(t *testing.T) {
+	production := entities.Role{ID: uuid.New(), Name: "production-admin"}
+	viewer := entities.Role{ID: uuid.New(), Name: "viewer"}
+	alice := &entities.User{ID: uuid.New(), Username: "alice"}
+	f := newGroupFixture(t, []entities.Role{production, viewer}, alice)
+	oncall := f.create(t, "oncall", "", "production-admin")
+	primary := f.create(t, "primary", "")
+	f.policies.roles = []uuid.UUID{production.ID}
+
+	// Anggota dan subgrup baru akan menerima Role berkebijakan persetujuan tanpa persetujuan
+	if _, err := f.groups.AddMember(oncall, alice.ID); !errors.Is(err, ErrRoleGrantApprovalRequired) {
+		t.Fatalf("AddMember: err = %v, ingin ErrRoleGrantApprovalRequired", err)
+	}
+	if _, err := f.groups.AddSubgroup(oncall, primary); !errors.Is(err, ErrRoleGrantApprovalRequired) {
+		t.Fatalf("AddSubgroup: err = %v, ingin ErrRoleGrantApprovalRequired", err)
+	}
+	if _, err := f.groups.SetRoles(primary, []string{"viewer", "production-admin"}); !errors.Is(err, ErrRoleGrantApprovalRequired) {
+		t.Fatalf("SetRoles: err = %v, ingin ErrRoleGrantApprovalRequired", err)
+	}
+	if members, _ := f.groupRepo.FindMemberIDs(oncall); len(members) != 0 {
+		t.Fatalf("anggota oncall = %v, ingin tidak ada", members)
+	}
+
+	// Role yang sudah diberikan sebelum kebijakan dibuat boleh tetap ada
+	group, err := f.groups.SetRoles(oncall, []string{"production-admin", "viewer"})
+	if err != nil {
+		t.Fatalf("SetRoles: %v", err)
+	}
+	if len(group.Roles) != 2 {
+		t.Fatalf("Role oncall = %d, ingin 2", len(group.Roles))
+	}
+}
+
+func TestExplainNestedGroupPermissions(t *testing.T) {
+	// engineering memberi developer yang mewarisi reporter; backend memberi contractor yang menolak
+	// ekspor laporan; alice anggota langsung api yang berada di bawah backend dan engineering.
+	reporter := entities.Role{ID: uuid.New(), Name: "reporter", Permissions: []*entities.Permission{{Name: "reports:*"}}}
+	developer := entities.Role{ID: uuid.New(), Name: "developer", Parents: []*entities.Role{&reporter}}
+	contractor := entities.Role{ID: uuid.New(), Name: "contractor", Permissions: []*entities.Permission{{Name: entities.PermissionDenyPrefix + "reports:export"}}}
+	viewer := entities.Role{ID: uuid.New(), Name: "viewer", Permissions: []*entities.Permission{{Name: "reports:read"}}}
+	alice := &entities.User{ID: uuid.New(), Username: "alice", Roles: []*entities.Role{&viewer}}
+	root := &entities.User{ID: uuid.New(), Username: "root", IsSuperuser: true}
+	f := newGroupFixture(t, []entities.Role{reporter, developer, contractor, viewer}, alice, root)
+	f.create(t, "engineering", "", "developer")
+	f.create(t, "backend", "engineering", "contractor")
+	api := f.create(t, "api", "backend")
+	if _, err := f.groups.AddMember(api, alice.ID); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+
+	explanation, err := f.groups.Explain(alice.ID, "reports:read")
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	want := []PermissionGrant{
+		{Role: "viewer", Permission: "reports:read"},
+		{Role: "developer", Permission: "reports:*", Groups: []string{"api", "backend", "engineering"}, Via: []string{"reporter"}},
+	}
+	if !explanation.Granted || !reflect.DeepEqual(explanation.Grants, want) {
+		t.Fatalf("reports:read = %v %+v, ingin diberikan lewat %+v", explanation.Granted, explanation.Grants, want)
+	}
+
+	// Deny dari grup perantara mengalahkan grant dari grup induk
+	explanation, err = f.groups.Explain(alice.ID, "reports:export")
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	if explanation.Granted || len(explanation.Grants) != 2 || !explanation.Grants[0].Deny ||
+		!reflect.DeepEqual(explanation.Grants[0].Groups, []string{"api", "backend"}) {
+		t.Fatalf("reports:export = %v %+v, ingin ditolak contractor lewat api, backend", explanation.Granted, explanation.Grants)
+	}
+
+	// Superuser memiliki permission tanpa Role
+	explanation, err = f.groups.Explain(root.ID, "reports:export")
+	if err != nil {
+		t.Fatalf("Explain: %v", err)
+	}
+	if !explanation.Granted || !explanation.Superuser || len(explanation.Grants) != 0 {
+		t.Fatalf("superuser = %+v, ingin diberikan tanpa grant", explanation)
+	}
+
+	if _, err := f.groups.Explain(uuid.New(), "reports:read"); !errors.Is(err, ErrGroupUserNotFound) {
+		t.Fatalf("err = %v, ingin ErrGroupUserNotFound", err)
+	}
+}
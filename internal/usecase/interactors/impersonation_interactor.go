@@ -0,0 +1,207 @@
+package interactors
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrImpersonationForbidden dikembalikan jika pelaku bukan superuser atau tidak memegang permission
+	// users:impersonate.
+	ErrImpersonationForbidden = errors.New("tidak berwenang melakukan impersonasi")
+	// ErrImpersonationUserNotFound dikembalikan jika pengguna yang akan diimpersonasi tidak ada.
+	ErrImpersonationUserNotFound = errors.New("pengguna tidak ditemukan")
+	// ErrImpersonationSelf dikembalikan jika pelaku mencoba mengimpersonasi dirinya sendiri.
+	ErrImpersonationSelf = errors.New("tidak bisa mengimpersonasi diri sendiri")
+	// ErrImpersonationTargetProtected dikembalikan jika target adalah superuser, sehingga impersonasi tidak
+	// bisa dipakai untuk meminjam hak superuser lain.
+	ErrImpersonationTargetProtected = errors.New("superuser tidak bisa diimpersonasi")
+	// ErrImpersonationTargetInactive dikembalikan jika target nonaktif atau service account.
+	ErrImpersonationTargetInactive = errors.New("pengguna nonaktif atau service account tidak bisa diimpersonasi")
+	// ErrImpersonationReasonRequired dikembalikan jika alasan impersonasi kosong.
+	ErrImpersonationReasonRequired = errors.New("alasan impersonasi wajib diisi")
+	// ErrImpersonationDurationInvalid dikembalikan jika durasi impersonasi negatif atau melebihi batas.
+	ErrImpersonationDurationInvalid = errors.New("durasi impersonasi melebihi batas yang diizinkan")
+	// ErrNotImpersonating dikembalikan jika permintaan tidak memakai token impersonasi.
+	ErrNotImpersonating = errors.New("sesi ini bukan sesi impersonasi")
+)
+
+// Tindakan yang dicatat di jejak audit impersonasi.
+const (
+	AuditImpersonationStarted = "impersonation.started"
+	AuditImpersonationEnded   = "impersonation.ended"
+	AuditImpersonationRequest = "impersonation.request"
+)
+
+// ImpersonationPolicy adalah aturan impersonasi dari konfigurasi.
+type ImpersonationPolicy struct {
+	MaxDuration time.Duration // Batas waktu mutlak satu sesi impersonasi
+}
+
+// ImpersonationInput adalah isian untuk memulai impersonasi. Minutes nol berarti batas maksimum.
+type ImpersonationInput struct {
+	Reason  string
+	Minutes int
+}
+
+// ImpersonationResult adalah token sesi impersonasi yang baru diterbitkan.
+type ImpersonationResult struct {
+	Token     *services.IssuedToken
+	SessionID uuid.UUID
+	Subject   *entities.User
+	Banner    string
+}
+
+// ImpersonationInteractor adalah use case impersonasi oleh superuser untuk kebutuhan dukungan. Token
+// impersonasi milik pengguna subjek tetapi membawa identitas pelakunya, berlaku paling lama
+// MaxDuration tanpa bisa diperpanjang, dan setiap permintaan yang memakainya dicatat di jejak audit.
+type ImpersonationInteractor struct {
+	userRepo       repositories.UserRepository
+	permissionRepo repositories.PermissionRepository
+	sessions       *SessionInteractor
+	tokenService   services.TokenService
+	audit          *AuditInteractor
+	policy         ImpersonationPolicy
+}
+
+// NewImpersonationInteractor membuat instance baru dari ImpersonationInteractor.
+func NewImpersonationInteractor(
+	ur repositories.UserRepository,
+	pr repositories.PermissionRepository,
+	sessions *SessionInteractor,
+	ts services.TokenService,
+	audit *AuditInteractor,
+	policy ImpersonationPolicy,
+) *ImpersonationInteractor {
+	return &ImpersonationInteractor{
+		userRepo:       ur,
+		permissionRepo: pr,
+		sessions:       sessions,
+		tokenService:   ts,
+		audit:          audit,
+		policy:         policy,
+	}
+}
+
+// Start memulai sesi impersonasi actorID sebagai subjectID dan menerbitkan token aksesnya.
+func (i *ImpersonationInteractor) Start(ctx context.Context, actorID, subjectID uuid.UUID, input ImpersonationInput, client ClientInfo) (*ImpersonationResult, error) {
+	actor, err := i.ensureImpersonator(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if actorID == subjectID {
+		return nil, ErrImpersonationSelf
+	}
+
+	reason := strings.TrimSpace(input.Reason)
+	if reason == "" {
+		return nil, ErrImpersonationReasonRequired
+	}
+	ttl := i.policy.MaxDuration
+	if input.Minutes != 0 {
+		ttl = time.Duration(input.Minutes) * time.Minute
+	}
+	if ttl <= 0 || ttl > i.policy.MaxDuration {
+		return nil, fmt.Errorf("%w (%d menit)", ErrImpersonationDurationInvalid, int(i.policy.MaxDuration.Minutes()))
+	}
+
+	subject, err := i.userRepo.FindByID(subjectID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImpersonationUserNotFound
+		}
+		return nil, err
+	}
+	switch {
+	case subject.IsSuperuser:
+		return nil, ErrImpersonationTargetProtected
+	case !subject.IsActive || subject.IsServiceAccount:
+		return nil, ErrImpersonationTargetInactive
+	}
+
+	sessionID := uuid.New()
+	banner := fmt.Sprintf("%s sedang masuk sebagai %s", actor.Username, subject.Username)
+	token, err := i.tokenService.GenerateImpersonationToken(subject, services.ImpersonationGrant{
+		SessionID: sessionID,
+		Actor:     actor,
+		Banner:    banner,
+		TTL:       ttl,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if client.Device == "" {
+		client.Device = "Impersonasi oleh " + actor.Username
+	}
+	if _, err := i.sessions.CreateImpersonation(ctx, sessionID, subject.ID, actor.ID, client, token.ExpiresAt); err != nil {
+		return nil, err
+	}
+
+	i.audit.Record(actorID, AuditImpersonationStarted, AuditTargetUser, subject.ID.String(), map[string]any{
+		"session_id": sessionID,
+		"username":   subject.Username,
+		"reason":     reason,
+		"expires_at": token.ExpiresAt,
+		"ip":         client.IP,
+	})
+	return &ImpersonationResult{Token: token, SessionID: sessionID, Subject: subject, Banner: banner}, nil
+}
+
+// End mengakhiri sesi impersonasi yang dipakai claims sebelum batas waktunya.
+func (i *ImpersonationInteractor) End(ctx context.Context, claims *services.TokenClaims) error {
+	if !claims.IsImpersonation() {
+		return ErrNotImpersonating
+	}
+	if err := i.sessions.Revoke(ctx, claims.UserID, claims.SessionID); err != nil {
+		return err
+	}
+
+	i.audit.Record(claims.ActorID, AuditImpersonationEnded, AuditTargetUser, claims.UserID.String(), map[string]any{
+		"session_id": claims.SessionID,
+		"username":   claims.Username,
+	})
+	return nil
+}
+
+// RecordRequest mencatat satu permintaan yang dilakukan dengan token impersonasi atas nama pelakunya.
+func (i *ImpersonationInteractor) RecordRequest(claims *services.TokenClaims, method, path string, status int) {
+	i.audit.Record(claims.ActorID, AuditImpersonationRequest, AuditTargetUser, claims.UserID.String(), map[string]any{
+		"session_id": claims.SessionID,
+		"method":     method,
+		"path":       path,
+		"status":     status,
+	})
+}
+
+// ensureImpersonator memastikan pelaku adalah superuser yang juga memegang permission users:impersonate.
+func (i *ImpersonationInteractor) ensureImpersonator(actorID uuid.UUID) (*entities.User, error) {
+	actor, err := i.userRepo.FindByID(actorID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrImpersonationForbidden
+		}
+		return nil, err
+	}
+	if !actor.IsSuperuser {
+		return nil, ErrImpersonationForbidden
+	}
+
+	names, err := i.permissionRepo.FindNamesByUser(actorID)
+	if err != nil {
+		return nil, err
+	}
+	if !entities.NewPermissionSet(names).Allows(entities.PermissionUsersImpersonate) {
+		return nil, ErrImpersonationForbidden
+	}
+	return actor, nil
+}
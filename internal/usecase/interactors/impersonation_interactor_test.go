@@ -0,0 +1,160 @@
+package interactors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+)
+
+// impersonationTokenService adalah TokenService uji yang mencatat grant token impersonasi.
+type impersonationTokenService struct {
+	services.TokenService
+	grants []services.ImpersonationGrant
+}
+
+func (s *impersonationTokenService) GenerateImpersonationToken(user *entities.User, grant services.ImpersonationGrant) (*services.IssuedToken, error) {
+	s.grants = append(s.grants, grant)
+	return &services.IssuedToken{Token: "impersonasi-" + user.Username, ExpiresAt: time.Now().Add(grant.TTL)}, nil
+}
+
+// recordingAuditLogRepository adalah AuditLogRepository uji yang menyimpan semua catatan audit.
+type recordingAuditLogRepository struct {
+	repositories.AuditLogRepository
+	entries []entities.AuditLog
+}
+
+func (r *recordingAuditLogRepository) Create(entry *entities.AuditLog) error {
+	r.entries = append(r.entries, *entry)
+	return nil
+}
+
+// impersonationFixture menyusun ImpersonationInteractor dengan batas impersonasi 30 menit. admin adalah
+// superuser yang memegang users:impersonate.
+type impersonationFixture struct {
+	impersonation *ImpersonationInteractor
+	sessions      *memorySessionRepository
+	tokens        *impersonationTokenService
+	permissions   *staticPermissionRepository
+	audit         *recordingAuditLogRepository
+	admin         *entities.User
+}
+
+func newImpersonationFixture(users ...*entities.User) *impersonationFixture {
+	f := &impersonationFixture{
+		sessions: &memorySessionRepository{},
+		tokens:   &impersonationTokenService{},
+		audit:    &recordingAuditLogRepository{},
+		admin:    &entities.User{ID: uuid.New(), Username: "admin", IsSuperuser: true, IsActive: true},
+	}
+	f.permissions = &staticPermissionRepository{user: map[uuid.UUID][]string{f.admin.ID: {entities.PermissionUsersImpersonate}}}
+	f.impersonation = NewImpersonationInteractor(
+		newMemoryUserRepository(append([]*entities.User{f.admin}, users...)...),
+		f.permissions,
+		NewSessionInteractor(f.sessions),
+		f.tokens,
+		NewAuditInteractor(f.audit),
+		ImpersonationPolicy{MaxDuration: 30 * time.Minute},
+	)
+	return f
+}
+
+func TestStartImpersonationDenials(t *testing.T) {
+	alice := &entities.User{ID: uuid.New(), Username: "alice", IsActive: true}
+	support := &entities.User{ID: uuid.New(), Username: "support", IsActive: true}              // Memegang users:impersonate tanpa superuser
+	root := &entities.User{ID: uuid.New(), Username: "root", IsSuperuser: true, IsActive: true} // Tanpa users:impersonate
+	ops := &entities.User{ID: uuid.New(), Username: "ops", IsSuperuser: true, IsActive: true}
+	inactive := &entities.User{ID: uuid.New(), Username: "mantan"}
+	robot := &entities.User{ID: uuid.New(), Username: "ci-bot", IsActive: true, IsServiceAccount: true}
+	f := newImpersonationFixture(alice, support, root, ops, inactive, robot)
+	f.permissions.user[support.ID] = []string{entities.PermissionUsersImpersonate}
+	admin := f.admin.ID
+
+	tests := []struct {
+		name    string
+		actor   uuid.UUID
+		subject uuid.UUID
+		input   ImpersonationInput
+		wantErr error
+	}{
+		{"bukan superuser", support.ID, alice.ID, ImpersonationInput{Reason: "tiket"}, ErrImpersonationForbidden},
+		{"superuser tanpa permission", root.ID, alice.ID, ImpersonationInput{Reason: "tiket"}, ErrImpersonationForbidden},
+		{"pelaku tidak terdaftar", uuid.New(), alice.ID, ImpersonationInput{Reason: "tiket"}, ErrImpersonationForbidden},
+		{"diri sendiri", admin, admin, ImpersonationInput{Reason: "tiket"}, ErrImpersonationSelf},
+		{"tanpa alasan", admin, alice.ID, ImpersonationInput{Reason: " "}, ErrImpersonationReasonRequired},
+		{"melebihi batas", admin, alice.ID, ImpersonationInput{Reason: "tiket", Minutes: 31}, ErrImpersonationDurationInvalid},
+		{"durasi negatif", admin, alice.ID, ImpersonationInput{Reason: "tiket", Minutes: -5}, ErrImpersonationDurationInvalid},
+		{"target tidak ada", admin, uuid.New(), ImpersonationInput{Reason: "tiket"}, ErrImpersonationUserNotFound},
+		{"target superuser", admin, ops.ID, ImpersonationInput{Reason: "tiket"}, ErrImpersonationTargetProtected},
+		{"target nonaktif", admin, inactive.ID, ImpersonationInput{Reason: "tiket"}, ErrImpersonationTargetInactive},
+		{"target service account", admin, robot.ID, ImpersonationInput{Reason: "tiket"}, ErrImpersonationTargetInactive},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := f.impersonation.Start(context.Background(), tt.actor, tt.subject, tt.input, ClientInfo{})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, ingin %v", err, tt.wantErr)
+			}
+		})
+	}
+	if len(f.tokens.grants) != 0 || len(f.sessions.sessions) != 0 || len(f.audit.entries) != 0 {
+		t.Fatalf("token = %d, sesi = %d, audit = %d; ingin tidak ada", len(f.tokens.grants), len(f.sessions.sessions), len(f.audit.entries))
+	}
+}
+
+func TestImpersonationSession(t *testing.T) {
+	alice := &entities.User{ID: uuid.New(), Username: "alice", IsActive: true}
+	f := newImpersonationFixture(alice)
+	ctx := context.Background()
+
+	// Minutes nol memakai batas maksimum
+	result, err := f.impersonation.Start(ctx, f.admin.ID, alice.ID, ImpersonationInput{Reason: "tiket 42"}, ClientInfo{IP: "10.0.0.1"})
+	if err != nil {
+		t.Fatalf("Start: %v", err)
+	}
+	grant := f.tokens.grants[0]
+	if grant.TTL != 30*time.Minute || grant.Actor.ID != f.admin.ID || grant.SessionID != result.SessionID {
+		t.Fatalf("grant = %+v, ingin 30 menit oleh admin pada sesi %s", grant, result.SessionID)
+	}
+	if !strings.Contains(result.Banner, "admin") || !strings.Contains(result.Banner, "alice") {
+		t.Fatalf("banner = %q, ingin menyebut pelaku dan subjek", result.Banner)
+	}
+	session := f.sessions.sessions[0]
+	if session.UserID != alice.ID || session.ImpersonatorID == nil || *session.ImpersonatorID != f.admin.ID {
+		t.Fatalf("sesi = %+v, ingin sesi alice yang diimpersonasi admin", session)
+	}
+	started := f.audit.entries[0]
+	if started.Action != AuditImpersonationStarted || *started.ActorID != f.admin.ID || started.Data["reason"] != "tiket 42" {
+		t.Fatalf("audit = %+v, ingin %s oleh admin dengan alasannya", started, AuditImpersonationStarted)
+	}
+
+	if _, err := f.impersonation.Start(ctx, f.admin.ID, alice.ID, ImpersonationInput{Reason: "tiket 43", Minutes: 10}, ClientInfo{}); err != nil {
+		t.Fatalf("Start 10 menit: %v", err)
+	}
+	if f.tokens.grants[1].TTL != 10*time.Minute {
+		t.Fatalf("TTL = %v, ingin 10 menit", f.tokens.grants[1].TTL)
+	}
+
+	// Token biasa tidak bisa mengakhiri impersonasi
+	if err := f.impersonation.End(ctx, &services.TokenClaims{UserID: alice.ID, SessionID: result.SessionID}); !errors.Is(err, ErrNotImpersonating) {
+		t.Fatalf("End tanpa pelaku: err = %v, ingin ErrNotImpersonating", err)
+	}
+	claims := &services.TokenClaims{UserID: alice.ID, SessionID: result.SessionID, Username: "alice", ActorID: f.admin.ID}
+	if err := f.impersonation.End(ctx, claims); err != nil {
+		t.Fatalf("End: %v", err)
+	}
+	if f.sessions.sessions[0].RevokedAt == nil {
+		t.Fatal("sesi impersonasi tidak dicabut")
+	}
+	ended := f.audit.entries[len(f.audit.entries)-1]
+	if ended.Action != AuditImpersonationEnded || *ended.ActorID != f.admin.ID || ended.TargetID != alice.ID.String() {
+		t.Fatalf("audit = %+v, ingin %s oleh admin untuk alice", ended, AuditImpersonationEnded)
+	}
+}
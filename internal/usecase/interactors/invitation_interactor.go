@@ -0,0 +1,361 @@
+package interactors
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"net/mail"
+	"net/url"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvitationNotFound dikembalikan jika undangan tidak ada, sudah diterima atau sudah dicabut.
+	ErrInvitationNotFound = errors.New("undangan tidak ditemukan")
+	// ErrInvalidInvitation dikembalikan jika tautan undangan tidak dikenal, kedaluwarsa, dicabut atau sudah dipakai.
+	ErrInvalidInvitation = errors.New("undangan tidak valid atau sudah kedaluwarsa")
+	// ErrInvitationEmailInvalid dikembalikan jika email tujuan undangan tidak valid.
+	ErrInvitationEmailInvalid = errors.New("email undangan tidak valid")
+	// ErrInvitationPending dikembalikan jika email sudah memiliki undangan yang masih menunggu.
+	ErrInvitationPending = errors.New("email ini sudah memiliki undangan yang masih menunggu")
+	// ErrInvitationEmailMismatch dikembalikan jika undangan diterima oleh akun dengan email lain.
+	ErrInvitationEmailMismatch = errors.New("undangan ini ditujukan untuk email lain")
+	// ErrInvitationLoginRequired dikembalikan jika email undangan sudah punya akun; penerima harus login lebih dulu.
+	ErrInvitationLoginRequired = errors.New("email undangan sudah terdaftar, silakan login untuk menerima undangan")
+	// ErrInvitationUsernameTaken dikembalikan jika username untuk akun baru sudah dipakai.
+	ErrInvitationUsernameTaken = errors.New("username sudah dipakai")
+	// ErrInvitationAccountRequired dikembalikan jika username atau password akun baru kosong.
+	ErrInvitationAccountRequired = errors.New("username dan password wajib diisi untuk membuat akun")
+)
+
+// invitationTokenLength adalah panjang token tautan undangan (~190 bit entropi).
+const invitationTokenLength = 32
+
+// InvitationPolicy adalah aturan undangan organisasi.
+type InvitationPolicy struct {
+	TTL       time.Duration // Masa berlaku tautan sejak terakhir dikirim
+	AcceptURL string        // Halaman penerimaan undangan; token ditambahkan sebagai parameter token
+}
+
+// NewAccount adalah data akun baru untuk penerima undangan yang belum terdaftar.
+type NewAccount struct {
+	Username  string
+	Password  string
+	FirstName string
+	LastName  string
+}
+
+// InvitationPreview adalah informasi undangan yang ditampilkan sebelum diterima.
+type InvitationPreview struct {
+	Organization  *entities.Organization `json:"organization"`
+	Email         string                 `json:"email"`
+	Roles         []string               `json:"roles"`
+	ExpiresAt     time.Time              `json:"expires_at"`
+	AccountExists bool                   `json:"account_exists"` // Jika true, penerima harus login untuk menerima
+}
+
+// InvitationInteractor adalah use case untuk undangan bergabung ke organisasi.
+type InvitationInteractor struct {
+	invitationRepo repositories.InvitationRepository
+	userRepo       repositories.UserRepository
+	roleRepo       repositories.RoleRepository
+	organizations  *OrganizationInteractor
+	users          *UserInteractor
+	mailer         services.Mailer
+	policy         InvitationPolicy
+}
+
+// NewInvitationInteractor membuat instance baru dari InvitationInteractor.
+func NewInvitationInteractor(
+	ir repositories.InvitationRepository,
+	ur repositories.UserRepository,
+	rr repositories.RoleRepository,
+	organizations *OrganizationInteractor,
+	users *UserInteractor,
+	mailer services.Mailer,
+	policy InvitationPolicy,
+) *InvitationInteractor {
+	return &InvitationInteractor{
+		invitationRepo: ir,
+		userRepo:       ur,
+		roleRepo:       rr,
+		organizations:  organizations,
+		users:          users,
+		mailer:         mailer,
+		policy:         policy,
+	}
+}
+
+// Invite membuat undangan untuk email dengan Role roleNames di organisasi dan mengirim tautannya.
+func (i *InvitationInteractor) Invite(organizationID, inviterID uuid.UUID, email string, roleNames []string) (*entities.Invitation, error) {
+	address, err := mail.ParseAddress(strings.TrimSpace(email))
+	if err != nil || address.Name != "" {
+		return nil, ErrInvitationEmailInvalid
+	}
+	email = strings.ToLower(address.Address)
+
+	organization, err := i.organizations.find(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	roleNames = normalizeScopes(roleNames)
+	roles, err := resolveRoles(i.roleRepo, roleNames, ErrOrganizationRoleNotFound)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTenantRoles(roles); err != nil {
+		return nil, err
+	}
+	if err := i.organizations.grants.CheckNoApproval(roles); err != nil {
+		return nil, err
+	}
+
+	if user, err := i.userRepo.FindByEmail(email); err == nil {
+		if _, err := i.organizations.Membership(organizationID, user.ID); err == nil {
+			return nil, ErrOrganizationMemberExists
+		} else if !errors.Is(err, ErrNotOrganizationMember) {
+			return nil, err
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	now := time.Now()
+	if _, err := i.invitationRepo.FindPendingByEmail(organizationID, email, now); err == nil {
+		return nil, ErrInvitationPending
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	token, err := randomString(invitationTokenLength)
+	if err != nil {
+		return nil, err
+	}
+	invitation := &entities.Invitation{
+		OrganizationID: organizationID,
+		Email:          email,
+		Roles:          roleNames,
+		TokenHash:      hashSecret(token),
+		InvitedBy:      inviterID,
+		ExpiresAt:      now.Add(i.policy.TTL),
+		SentAt:         now,
+	}
+	if err := i.invitationRepo.Create(invitation); err != nil {
+		return nil, err
+	}
+
+	i.send(organization, invitation, token)
+	return invitation, nil
+}
+
+// ListPending mengembalikan undangan organisasi yang belum diterima, dicabut atau kedaluwarsa.
+func (i *InvitationInteractor) ListPending(organizationID uuid.UUID) ([]entities.Invitation, error) {
+	return i.invitationRepo.FindPending(organizationID, time.Now())
+}
+
+// Resend mengirim ulang undangan dengan tautan baru dan masa berlaku yang diperpanjang.
+// Tautan sebelumnya langsung tidak berlaku. Undangan yang sudah kedaluwarsa juga bisa dikirim ulang.
+func (i *InvitationInteractor) Resend(organizationID, id uuid.UUID) (*entities.Invitation, error) {
+	invitation, err := i.invitationRepo.FindByID(organizationID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvitationNotFound
+		}
+		return nil, err
+	}
+	if invitation.AcceptedAt != nil || invitation.RevokedAt != nil {
+		return nil, ErrInvitationNotFound
+	}
+
+	organization, err := i.organizations.find(organizationID)
+	if err != nil {
+		return nil, err
+	}
+
+	token, err := randomString(invitationTokenLength)
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now()
+	invitation.TokenHash = hashSecret(token)
+	invitation.ExpiresAt = now.Add(i.policy.TTL)
+	invitation.SentAt = now
+	if err := i.invitationRepo.UpdateToken(invitation); err != nil {
+		return nil, err
+	}
+
+	i.send(organization, invitation, token)
+	return invitation, nil
+}
+
+// Revoke mencabut undangan yang belum diterima sehingga tautannya tidak bisa dipakai lagi.
+func (i *InvitationInteractor) Revoke(organizationID, id uuid.UUID) error {
+	if err := i.invitationRepo.Revoke(organizationID, id, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrInvitationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Preview mengembalikan isi undangan dari token tautannya tanpa menerimanya.
+func (i *InvitationInteractor) Preview(token string) (*InvitationPreview, error) {
+	invitation, err := i.findPending(token)
+	if err != nil {
+		return nil, err
+	}
+
+	_, err = i.userRepo.FindByEmail(invitation.Email)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	return &InvitationPreview{
+		Organization:  invitation.Organization,
+		Email:         invitation.Email,
+		Roles:         invitation.Roles,
+		ExpiresAt:     invitation.ExpiresAt,
+		AccountExists: err == nil,
+	}, nil
+}
+
+// Accept menerima undangan dengan akun pengguna yang sedang login. Email akun harus sama dengan email undangan.
+func (i *InvitationInteractor) Accept(token string, userID uuid.UUID) (*entities.OrganizationMember, error) {
+	invitation, err := i.findPending(token)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := i.userRepo.FindByID(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !strings.EqualFold(user.Email, invitation.Email) {
+		return nil, ErrInvitationEmailMismatch
+	}
+	roleNames, err := i.roles(invitation, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return i.join(invitation, user.ID, roleNames)
+}
+
+// AcceptWithNewAccount menerima undangan sekaligus membuat akun untuk email undangan.
+// Jika email sudah terdaftar, penerima harus login dan memakai Accept agar akun orang lain tidak bisa diambil alih.
+func (i *InvitationInteractor) AcceptWithNewAccount(token string, account NewAccount) (*entities.OrganizationMember, error) {
+	invitation, err := i.findPending(token)
+	if err != nil {
+		return nil, err
+	}
+
+	account.Username = strings.TrimSpace(account.Username)
+	if account.Username == "" || account.Password == "" {
+		return nil, ErrInvitationAccountRequired
+	}
+	if _, err := i.userRepo.FindByEmail(invitation.Email); err == nil {
+		return nil, ErrInvitationLoginRequired
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if _, err := i.userRepo.FindByUsernameOrEmail(account.Username); err == nil {
+		return nil, ErrInvitationUsernameTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	// Akun baru belum memegang Role apa pun sehingga hanya Role undangan yang diperiksa satu sama lain
+	roleNames, err := i.roles(invitation, uuid.Nil)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := i.users.CreateUser(&entities.User{
+		Username:  account.Username,
+		Email:     invitation.Email, // Terverifikasi karena tautan hanya dikirim ke alamat ini
+		Password:  account.Password,
+		FirstName: account.FirstName,
+		LastName:  account.LastName,
+		IsActive:  true,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return i.join(invitation, user.ID, roleNames)
+}
+
+// roles mengembalikan nama Role undangan yang masih terdaftar untuk diberikan ke userID. Role yang dihapus
+// setelah undangan dibuat tidak lagi diberikan, sedangkan Role yang sejak itu tidak lagi boleh diberikan ke
+// anggota organisasi, diberi kebijakan persetujuan atau melanggar aturan pemisahan tugas membuat undangan
+// ditolak sebelum akun dibuat atau undangan terpakai.
+func (i *InvitationInteractor) roles(invitation *entities.Invitation, userID uuid.UUID) ([]string, error) {
+	found, err := i.roleRepo.FindByNames(invitation.Roles)
+	if err != nil {
+		return nil, err
+	}
+	roles := make([]*entities.Role, 0, len(found))
+	names := make([]string, 0, len(found))
+	for n := range found {
+		roles = append(roles, &found[n])
+		names = append(names, found[n].Name)
+	}
+	if err := checkTenantRoles(roles); err != nil {
+		return nil, err
+	}
+	if err := i.organizations.grants.CheckNoApproval(roles); err != nil {
+		return nil, err
+	}
+	if err := i.organizations.grants.CheckMemberConflicts(invitation.OrganizationID, userID, roles); err != nil {
+		return nil, err
+	}
+	return names, nil
+}
+
+// join menandai undangan diterima lalu menjadikan pengguna anggota organisasi dengan Role roleNames.
+func (i *InvitationInteractor) join(invitation *entities.Invitation, userID uuid.UUID, roleNames []string) (*entities.OrganizationMember, error) {
+	if err := i.invitationRepo.MarkAccepted(invitation.ID, userID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, err
+	}
+	return i.organizations.Join(invitation.OrganizationID, userID, roleNames)
+}
+
+// findPending mencari undangan yang masih menunggu dari token tautannya.
+func (i *InvitationInteractor) findPending(token string) (*entities.Invitation, error) {
+	invitation, err := i.invitationRepo.FindByHash(hashSecret(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrInvalidInvitation
+		}
+		return nil, err
+	}
+	if !invitation.IsPending(time.Now()) || invitation.Organization == nil {
+		return nil, ErrInvalidInvitation
+	}
+	return invitation, nil
+}
+
+// send mengirim tautan undangan. Kegagalan hanya dicatat; admin bisa mengirim ulang undangan.
+func (i *InvitationInteractor) send(organization *entities.Organization, invitation *entities.Invitation, token string) {
+	link := i.policy.AcceptURL + "?token=" + url.QueryEscape(token)
+	subject := "Undangan bergabung ke " + organization.Name
+	body := fmt.Sprintf(
+		"Halo,\n\nAnda diundang untuk bergabung ke organisasi %s.\n"+
+			"Buka tautan berikut untuk menerima undangan sebelum %s:\n\n%s\n\n"+
+			"Jika Anda tidak mengenal organisasi ini, abaikan email ini.\n",
+		organization.Name, invitation.ExpiresAt.Format(time.RFC1123), link,
+	)
+
+	if err := i.mailer.Send(invitation.Email, subject, body); err != nil {
+		log.Printf("Gagal mengirim undangan %s ke %s: %v", invitation.ID, invitation.Email, err)
+	}
+}
@@ -0,0 +1,280 @@
+package interactors
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryInvitationRepository adalah InvitationRepository di memori. Organization undangan diisi dari orgs.
+type memoryInvitationRepository struct {
+	repositories.InvitationRepository
+	orgs        *staticOrganizationRepository
+	invitations []entities.Invitation
+}
+
+func (r *memoryInvitationRepository) Create(invitation *entities.Invitation) error {
+	invitation.ID = uuid.New()
+	r.invitations = append(r.invitations, *invitation)
+	return nil
+}
+
+func (r *memoryInvitationRepository) FindByID(organizationID, id uuid.UUID) (*entities.Invitation, error) {
+	for _, invitation := range r.invitations {
+		if invitation.OrganizationID == organizationID && invitation.ID == id {
+			return &invitation, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryInvitationRepository) FindByHash(hash string) (*entities.Invitation, error) {
+	for _, invitation := range r.invitations {
+		if invitation.TokenHash == hash {
+			invitation.Organization, _ = r.orgs.FindByID(invitation.OrganizationID)
+			return &invitation, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryInvitationRepository) FindPendingByEmail(organizationID uuid.UUID, email string, now time.Time) (*entities.Invitation, error) {
+	for _, invitation := range r.invitations {
+		if invitation.OrganizationID == organizationID && invitation.Email == email && invitation.IsPending(now) {
+			return &invitation, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *memoryInvitationRepository) UpdateToken(invitation *entities.Invitation) error {
+	stored := r.find(invitation.ID)
+	stored.TokenHash, stored.ExpiresAt, stored.SentAt = invitation.TokenHash, invitation.ExpiresAt, invitation.SentAt
+	return nil
+}
+
+func (r *memoryInvitationRepository) MarkAccepted(id, userID uuid.UUID, now time.Time) error {
+	stored := r.find(id)
+	if stored == nil || stored.AcceptedAt != nil || stored.RevokedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	stored.AcceptedAt, stored.AcceptedBy = &now, &userID
+	return nil
+}
+
+func (r *memoryInvitationRepository) Revoke(organizationID, id uuid.UUID, now time.Time) error {
+	stored := r.find(id)
+	if stored == nil || stored.OrganizationID != organizationID || stored.AcceptedAt != nil || stored.RevokedAt != nil {
+		return gorm.ErrRecordNotFound
+	}
+	stored.RevokedAt = &now
+	return nil
+}
+
+func (r *memoryInvitationRepository) find(id uuid.UUID) *entities.Invitation {
+	for n := range r.invitations {
+		if r.invitations[n].ID == id {
+			return &r.invitations[n]
+		}
+	}
+	return nil
+}
+
+// recordingMailer adalah Mailer uji yang menyimpan token tautan dari email terakhir ke setiap penerima.
+type recordingMailer struct {
+	tokens map[string]string
+}
+
+func (m *recordingMailer) Send(to, _, body string) error {
+	_, query, _ := strings.Cut(body, "?")
+	values, _ := url.ParseQuery(strings.Fields(query)[0])
+	m.tokens[to] = values.Get("token")
+	return nil
+}
+
+// plainPasswordHasher adalah PasswordHasher uji tanpa hashing sungguhan.
+type plainPasswordHasher struct{}
+
+func (plainPasswordHasher) Hash(password string) (string, error) { return "hash:" + password, nil }
+
+func (plainPasswordHasher) Compare(hash, password string) bool { return hash == "hash:"+password }
+
+// invitationFixture adalah InvitationInteractor untuk organisasi acme di atas organizationFixture dengan
+// satu pengguna terdaftar.
+type invitationFixture struct {
+	*organizationFixture
+	acme        entities.Organization
+	repo        *memoryInvitationRepository
+	mailer      *recordingMailer
+	invitations *InvitationInteractor
+}
+
+func newTestInvitations(user *entities.User) *invitationFixture {
+	f := &invitationFixture{organizationFixture: newTestOrganizations(user, "viewer", "billing-admin")}
+	f.acme = entities.Organization{ID: uuid.New(), Name: "Acme"}
+	f.orgs.organizations = []entities.Organization{f.acme}
+	f.repo = &memoryInvitationRepository{orgs: f.orgs}
+	f.mailer = &recordingMailer{tokens: map[string]string{}}
+	userRepo := f.organizations.userRepo
+	f.invitations = NewInvitationInteractor(f.repo, userRepo, f.organizations.roleRepo, f.organizations,
+		NewUserInteractor(userRepo, plainPasswordHasher{}), f.mailer, InvitationPolicy{TTL: time.Hour, AcceptURL: "https://app.test/accept"})
+	return f
+}
+
+func TestInvitationNewAccount(t *testing.T) {
+	alice := &entities.User{ID: uuid.New(), Username: "alice", Email: "alice@example.org", IsActive: true}
+	f := newTestInvitations(alice)
+	inviter := uuid.New()
+
+	for _, email := range []string{"", "bukan-email", "Carol <carol@example.org>"} {
+		if _, err := f.invitations.Invite(f.acme.ID, inviter, email, []string{"viewer"}); !errors.Is(err, ErrInvitationEmailInvalid) {
+			t.Fatalf("Invite(%q): err = %v, ingin ErrInvitationEmailInvalid", email, err)
+		}
+	}
+	if _, err := f.invitations.Invite(uuid.New(), inviter, "carol@example.org", []string{"viewer"}); !errors.Is(err, ErrOrganizationNotFound) {
+		t.Fatalf("Invite organisasi lain: err = %v, ingin ErrOrganizationNotFound", err)
+	}
+	if _, err := f.invitations.Invite(f.acme.ID, inviter, "carol@example.org", []string{"owner"}); !errors.Is(err, ErrOrganizationRoleNotFound) {
+		t.Fatalf("Invite role tidak dikenal: err = %v, ingin ErrOrganizationRoleNotFound", err)
+	}
+
+	invitation, err := f.invitations.Invite(f.acme.ID, inviter, " Carol@Example.org ", []string{"viewer"})
+	if err != nil {
+		t.Fatalf("Invite: %v", err)
+	}
+	if invitation.Email != "carol@example.org" || f.mailer.tokens["carol@example.org"] == "" {
+		t.Fatalf("undangan = %+v, ingin email huruf kecil dan tautan terkirim", invitation)
+	}
+	if _, err := f.invitations.Invite(f.acme.ID, inviter, "carol@example.org", []string{"viewer"}); !errors.Is(err, ErrInvitationPending) {
+		t.Fatalf("Invite ulang: err = %v, ingin ErrInvitationPending", err)
+	}
+
+	// Kirim ulang mengganti tautan sehingga tautan lama tidak berlaku
+	oldToken := f.mailer.tokens["carol@example.org"]
+	if _, err := f.invitations.Resend(f.acme.ID, invitation.ID); err != nil {
+		t.Fatalf("Resend: %v", err)
+	}
+	token := f.mailer.tokens["carol@example.org"]
+	if _, err := f.invitations.Preview(oldToken); !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("Preview tautan lama: err = %v, ingin ErrInvalidInvitation", err)
+	}
+	if preview, err := f.invitations.Preview(token); err != nil || preview.AccountExists || preview.Organization.Name != "Acme" {
+		t.Fatalf("Preview = %+v, %v; ingin undangan Acme tanpa akun", preview, err)
+	}
+
+	denied := []struct {
+		name    string
+		account NewAccount
+		want    error
+	}{
+		{"tanpa password", NewAccount{Username: "carol"}, ErrInvitationAccountRequired},
+		{"tanpa username", NewAccount{Username: "  ", Password: "rahasia"}, ErrInvitationAccountRequired},
+		{"username dipakai", NewAccount{Username: "alice", Password: "rahasia"}, ErrInvitationUsernameTaken},
+	}
+	for _, tt := range denied {
+		if _, err := f.invitations.AcceptWithNewAccount(token, tt.account); !errors.Is(err, tt.want) {
+			t.Fatalf("%s: err = %v, ingin %v", tt.name, err, tt.want)
+		}
+	}
+	if _, err := f.invitations.Accept(token, alice.ID); !errors.Is(err, ErrInvitationEmailMismatch) {
+		t.Fatalf("Accept akun lain: err = %v, ingin ErrInvitationEmailMismatch", err)
+	}
+
+	member, err := f.invitations.AcceptWithNewAccount(token, NewAccount{Username: "carol", Password: "rahasia"})
+	if err != nil {
+		t.Fatalf("AcceptWithNewAccount: %v", err)
+	}
+	if len(member.Roles) != 1 || member.Roles[0].Name != "viewer" {
+		t.Fatalf("role anggota = %v, ingin [viewer]", member.Roles)
+	}
+	carol, err := f.organizations.userRepo.FindByEmail("carol@example.org")
+	if err != nil || carol.Password != "hash:rahasia" || member.UserID != carol.ID {
+		t.Fatalf("akun baru = %+v, %v", carol, err)
+	}
+
+	// Tautan hanya bisa dipakai sekali
+	if _, err := f.invitations.AcceptWithNewAccount(token, NewAccount{Username: "carol2", Password: "rahasia"}); !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("AcceptWithNewAccount ulang: err = %v, ingin ErrInvalidInvitation", err)
+	}
+	if _, err := f.invitations.Resend(f.acme.ID, invitation.ID); !errors.Is(err, ErrInvitationNotFound) {
+		t.Fatalf("Resend setelah diterima: err = %v, ingin ErrInvitationNotFound", err)
+	}
+	if _, err := f.invitations.Invite(f.acme.ID, inviter, "carol@example.org", []string{"viewer"}); !errors.Is(err, ErrOrganizationMemberExists) {
+		t.Fatalf("Invite anggota: err = %v, ingin ErrOrganizationMemberExists", err)
+	}
+}
+
+func TestInvitationExistingAccount(t *testing.T) {
+	bob := &entities.User{ID: uuid.New(), Username: "bob", Email: "bob@example.org", IsActive: true}
+	f := newTestInvitations(bob)
+	inviter := uuid.New()
+
+	invite := func() (*entities.Invitation, string) {
+		t.Helper()
+		invitation, err := f.invitations.Invite(f.acme.ID, inviter, bob.Email, []string{"viewer", "billing-admin"})
+		if err != nil {
+			t.Fatalf("Invite: %v", err)
+		}
+		return invitation, f.mailer.tokens[bob.Email]
+	}
+
+	// Email yang sudah terdaftar harus login agar akunnya tidak diambil alih
+	invitation, token := invite()
+	if preview, err := f.invitations.Preview(token); err != nil || !preview.AccountExists {
+		t.Fatalf("Preview = %+v, %v; ingin akun sudah ada", preview, err)
+	}
+	if _, err := f.invitations.AcceptWithNewAccount(token, NewAccount{Username: "bob2", Password: "rahasia"}); !errors.Is(err, ErrInvitationLoginRequired) {
+		t.Fatalf("AcceptWithNewAccount: err = %v, ingin ErrInvitationLoginRequired", err)
+	}
+
+	// Undangan yang dicabut tidak bisa diterima atau dikirim ulang
+	if err := f.invitations.Revoke(f.acme.ID, invitation.ID); err != nil {
+		t.Fatalf("Revoke: %v", err)
+	}
+	if _, err := f.invitations.Accept(token, bob.ID); !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("Accept setelah dicabut: err = %v, ingin ErrInvalidInvitation", err)
+	}
+	if err := f.invitations.Revoke(f.acme.ID, invitation.ID); !errors.Is(err, ErrInvitationNotFound) {
+		t.Fatalf("Revoke ulang: err = %v, ingin ErrInvitationNotFound", err)
+	}
+	if _, err := f.invitations.Resend(f.acme.ID, invitation.ID); !errors.Is(err, ErrInvitationNotFound) {
+		t.Fatalf("Resend setelah dicabut: err = %v, ingin ErrInvitationNotFound", err)
+	}
+
+	// Undangan kedaluwarsa
+	invitation, token = invite()
+	f.repo.find(invitation.ID).ExpiresAt = time.Now().Add(-time.Minute)
+	if _, err := f.invitations.Accept(token, bob.ID); !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("Accept kedaluwarsa: err = %v, ingin ErrInvalidInvitation", err)
+	}
+
+	// Role yang sejak undangan dibuat memerlukan persetujuan membuat undangan ditolak tanpa terpakai
+	invitation, token = invite()
+	f.policies.roles = []uuid.UUID{f.role("billing-admin").ID}
+	if _, err := f.invitations.Accept(token, bob.ID); !errors.Is(err, ErrRoleGrantApprovalRequired) {
+		t.Fatalf("Accept role berpersetujuan: err = %v, ingin ErrRoleGrantApprovalRequired", err)
+	}
+	if f.repo.find(invitation.ID).AcceptedAt != nil {
+		t.Fatal("undangan ditandai diterima meskipun ditolak")
+	}
+	f.policies.roles = nil
+
+	member, err := f.invitations.Accept(token, bob.ID)
+	if err != nil {
+		t.Fatalf("Accept: %v", err)
+	}
+	if len(member.Roles) != 2 {
+		t.Fatalf("role anggota = %v, ingin viewer dan billing-admin", member.Roles)
+	}
+	if _, err := f.invitations.Accept(token, bob.ID); !errors.Is(err, ErrInvalidInvitation) {
+		t.Fatalf("Accept ulang: err = %v, ingin ErrInvalidInvitation", err)
+	}
+}
@@ -0,0 +1,276 @@
+package interactors
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrMFAAlreadyEnabled dikembalikan jika pengguna mencoba enrollment ulang saat MFA sudah aktif.
+	ErrMFAAlreadyEnabled = errors.New("MFA sudah aktif")
+	// ErrMFANotEnabled dikembalikan jika operasi memerlukan MFA yang aktif.
+	ErrMFANotEnabled = errors.New("MFA belum aktif")
+	// ErrMFAEnrollmentNotStarted dikembalikan jika konfirmasi dilakukan sebelum enrollment dimulai.
+	ErrMFAEnrollmentNotStarted = errors.New("enrollment MFA belum dimulai")
+	// ErrMFARequired dikembalikan jika pengguna mencoba menonaktifkan MFA yang diwajibkan kebijakan.
+	ErrMFARequired = errors.New("MFA diwajibkan untuk akun ini")
+	// ErrInvalidMFACode dikembalikan jika kode TOTP atau kode pemulihan salah.
+	ErrInvalidMFACode = errors.New("kode MFA tidak valid")
+)
+
+// recoveryCodeAlphabet menghindari karakter yang mudah tertukar (0/o, 1/l).
+const recoveryCodeAlphabet = "abcdefghjkmnpqrstuvwxyz23456789"
+
+// totpReplayTTL adalah lama time step TOTP yang sudah dipakai diingat; mencakup toleransi
+// satu periode sebelum dan sesudah saat ini.
+const totpReplayTTL = 90 * time.Second
+
+// MFAPolicy adalah aturan kapan MFA diwajibkan.
+type MFAPolicy struct {
+	RequiredForSuperusers bool     // Superuser wajib memakai MFA
+	RequiredRoles         []string // Nama role yang wajib memakai MFA
+	RecoveryCodeCount     int      // Jumlah kode pemulihan yang dibuat setiap kali
+}
+
+// MFAInteractor adalah use case untuk pendaftaran dan verifikasi multi-factor authentication.
+type MFAInteractor struct {
+	userRepo     repositories.UserRepository
+	recoveryRepo repositories.RecoveryCodeRepository
+	usedSteps    repositories.ChallengeStore // Time step TOTP yang sudah diterima per pengguna
+	otpService   services.OTPService
+	policy       MFAPolicy
+}
+
+// NewMFAInteractor membuat instance baru dari MFAInteractor.
+func NewMFAInteractor(
+	ur repositories.UserRepository,
+	rr repositories.RecoveryCodeRepository,
+	cs repositories.ChallengeStore,
+	otp services.OTPService,
+	policy MFAPolicy,
+) *MFAInteractor {
+	return &MFAInteractor{userRepo: ur, recoveryRepo: rr, usedSteps: cs, otpService: otp, policy: policy}
+}
+
+// IsRequired mengembalikan true jika kebijakan mewajibkan MFA untuk pengguna.
+// Roles pengguna harus sudah dimuat.
+func (i *MFAInteractor) IsRequired(user *entities.User) bool {
+	if user.IsSuperuser && i.policy.RequiredForSuperusers {
+		return true
+	}
+	for _, role := range i.policy.RequiredRoles {
+		if user.HasRole(role) {
+			return true
+		}
+	}
+	return false
+}
+
+// BeginTOTPEnrollment membuat secret TOTP baru yang belum aktif sampai dikonfirmasi.
+// Memanggil ulang sebelum konfirmasi akan mengganti secret sebelumnya.
+func (i *MFAInteractor) BeginTOTPEnrollment(userID uuid.UUID) (*services.TOTPEnrollment, error) {
+	user, err := i.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+
+	enrollment, err := i.otpService.GenerateTOTP(user.Email)
+	if err != nil {
+		return nil, err
+	}
+
+	user.TOTPSecret = enrollment.Secret
+	if _, err := i.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return enrollment, nil
+}
+
+// ConfirmTOTPEnrollment mengaktifkan MFA jika kode cocok dengan secret yang sedang didaftarkan,
+// lalu mengembalikan kode pemulihan plaintext yang hanya ditampilkan sekali.
+func (i *MFAInteractor) ConfirmTOTPEnrollment(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := i.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if user.MFAEnabled {
+		return nil, ErrMFAAlreadyEnabled
+	}
+	if user.TOTPSecret == "" {
+		return nil, ErrMFAEnrollmentNotStarted
+	}
+	if err := i.validateTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+
+	codes, err := i.replaceRecoveryCodes(user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	user.MFAEnabled = true
+	if _, err := i.userRepo.Update(user); err != nil {
+		return nil, err
+	}
+	return codes, nil
+}
+
+// DisableTOTP menonaktifkan MFA setelah pengguna membuktikan kepemilikan faktor kedua.
+func (i *MFAInteractor) DisableTOTP(ctx context.Context, userID uuid.UUID, code, recoveryCode string) error {
+	user, err := i.findUser(userID)
+	if err != nil {
+		return err
+	}
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+	if i.IsRequired(user) {
+		return ErrMFARequired
+	}
+	if err := i.Verify(ctx, user, code, recoveryCode); err != nil {
+		return err
+	}
+
+	if err := i.recoveryRepo.DeleteByUser(user.ID); err != nil {
+		return err
+	}
+
+	user.MFAEnabled = false
+	user.TOTPSecret = ""
+	_, err = i.userRepo.Update(user)
+	return err
+}
+
+// RegenerateRecoveryCodes mengganti semua kode pemulihan setelah verifikasi kode TOTP.
+func (i *MFAInteractor) RegenerateRecoveryCodes(ctx context.Context, userID uuid.UUID, code string) ([]string, error) {
+	user, err := i.findUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if !user.MFAEnabled {
+		return nil, ErrMFANotEnabled
+	}
+	if err := i.validateTOTP(ctx, user, code); err != nil {
+		return nil, err
+	}
+	return i.replaceRecoveryCodes(user.ID)
+}
+
+// Verify memeriksa kode TOTP atau, jika diisi, kode pemulihan sekali pakai.
+func (i *MFAInteractor) Verify(ctx context.Context, user *entities.User, code, recoveryCode string) error {
+	if !user.MFAEnabled {
+		return ErrMFANotEnabled
+	}
+
+	if recoveryCode != "" {
+		stored, err := i.recoveryRepo.FindUnused(user.ID, hashRecoveryCode(recoveryCode))
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidMFACode
+			}
+			return err
+		}
+		if err := i.recoveryRepo.MarkUsed(stored.ID); err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return ErrInvalidMFACode
+			}
+			return err
+		}
+		return nil
+	}
+
+	return i.validateTOTP(ctx, user, code)
+}
+
+// validateTOTP memeriksa kode TOTP lalu menandai time step-nya sebagai terpakai dengan SET NX,
+// sehingga kode yang disadap tidak bisa dipakai ulang selama masih dalam toleransi validasi.
+func (i *MFAInteractor) validateTOTP(ctx context.Context, user *entities.User, code string) error {
+	step, ok := i.otpService.ValidateTOTP(code, user.TOTPSecret)
+	if !ok {
+		return ErrInvalidMFACode
+	}
+
+	fresh, err := i.usedSteps.SaveNew(ctx, fmt.Sprintf("totp:%s:%d", user.ID, step), []byte{1}, totpReplayTTL)
+	if err != nil {
+		return err
+	}
+	if !fresh {
+		return ErrInvalidMFACode
+	}
+	return nil
+}
+
+// replaceRecoveryCodes membuat kode pemulihan baru, menyimpan hash-nya dan mengembalikan plaintext.
+func (i *MFAInteractor) replaceRecoveryCodes(userID uuid.UUID) ([]string, error) {
+	plain := make([]string, 0, i.policy.RecoveryCodeCount)
+	stored := make([]entities.RecoveryCode, 0, i.policy.RecoveryCodeCount)
+
+	for n := 0; n < i.policy.RecoveryCodeCount; n++ {
+		code, err := generateRecoveryCode()
+		if err != nil {
+			return nil, err
+		}
+		plain = append(plain, code)
+		stored = append(stored, entities.RecoveryCode{UserID: userID, CodeHash: hashRecoveryCode(code)})
+	}
+
+	if err := i.recoveryRepo.ReplaceForUser(userID, stored); err != nil {
+		return nil, err
+	}
+	return plain, nil
+}
+
+// findUser mengambil pengguna dan menerjemahkan error record tidak ditemukan.
+func (i *MFAInteractor) findUser(userID uuid.UUID) (*entities.User, error) {
+	user, err := i.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pengguna tidak ditemukan")
+		}
+		return nil, err
+	}
+	return user, nil
+}
+
+// generateRecoveryCode membuat kode acak berformat "xxxxx-xxxxx".
+func generateRecoveryCode() (string, error) {
+	alphabetSize := big.NewInt(int64(len(recoveryCodeAlphabet)))
+
+	var code strings.Builder
+	for n := 0; n < 10; n++ {
+		if n == 5 {
+			code.WriteByte('-')
+		}
+		idx, err := rand.Int(rand.Reader, alphabetSize)
+		if err != nil {
+			return "", err
+		}
+		code.WriteByte(recoveryCodeAlphabet[idx.Int64()])
+	}
+	return code.String(), nil
+}
+
+// hashRecoveryCode menormalkan kode (huruf kecil, tanpa tanda hubung/spasi) lalu menghitung SHA-256.
+// SHA-256 cukup karena kode acak berentropi tinggi, dan memungkinkan pencarian berdasarkan hash.
+func hashRecoveryCode(code string) string {
+	normalized := strings.ToLower(strings.NewReplacer("-", "", " ", "").Replace(code))
+	sum := sha256.Sum256([]byte(normalized))
+	return hex.EncodeToString(sum[:])
+}
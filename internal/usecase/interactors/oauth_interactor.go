@@ -0,0 +1,803 @@
+package interactors
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrOAuthClientNotFound dikembalikan jika klien OAuth tidak terdaftar.
+	ErrOAuthClientNotFound = errors.New("klien OAuth tidak ditemukan")
+	// ErrOAuthClientNameRequired dikembalikan jika nama klien kosong.
+	ErrOAuthClientNameRequired = errors.New("nama klien wajib diisi")
+	// ErrOAuthScopesRequired dikembalikan jika klien didaftarkan tanpa scope.
+	ErrOAuthScopesRequired = errors.New("klien harus memiliki setidaknya satu scope")
+	// ErrOAuthRedirectURIRequired dikembalikan jika klien authorization_code didaftarkan tanpa redirect URI.
+	ErrOAuthRedirectURIRequired = errors.New("klien authorization_code wajib memiliki redirect URI")
+	// ErrOAuthPublicClientCredentials dikembalikan jika klien publik meminta grant client_credentials.
+	ErrOAuthPublicClientCredentials = errors.New("grant client_credentials hanya untuk klien confidential")
+	// ErrOAuthPublicClientIntrospection dikembalikan jika klien publik meminta hak introspeksi.
+	ErrOAuthPublicClientIntrospection = errors.New("hak introspeksi hanya untuk klien confidential")
+	// ErrInvalidRedirectURI dikembalikan jika redirect_uri tidak terdaftar untuk klien.
+	// Kesalahan ini tidak boleh dikirim balik ke redirect_uri karena alamatnya tidak tepercaya.
+	ErrInvalidRedirectURI = errors.New("redirect_uri tidak valid")
+)
+
+// Kode error OAuth 2.0 dari RFC 6749 bagian 4.1.2.1 dan 5.2.
+const (
+	OAuthErrInvalidRequest          = "invalid_request"
+	OAuthErrInvalidClient           = "invalid_client"
+	OAuthErrInvalidGrant            = "invalid_grant"
+	OAuthErrUnauthorizedClient      = "unauthorized_client"
+	OAuthErrUnsupportedGrantType    = "unsupported_grant_type"
+	OAuthErrUnsupportedResponseType = "unsupported_response_type"
+	OAuthErrInvalidScope            = "invalid_scope"
+	OAuthErrAccessDenied            = "access_denied"
+)
+
+// OAuthError adalah error protokol OAuth 2.0 yang dikirim ke klien apa adanya.
+// Jika redirectURI terisi, error dikirim lewat redirect ke klien, bukan ditampilkan ke pengguna.
+type OAuthError struct {
+	Code        string
+	Description string
+	redirectURI string
+	state       string
+}
+
+func (e *OAuthError) Error() string {
+	return e.Code + ": " + e.Description
+}
+
+// RedirectURL mengembalikan URL redirect ke klien yang membawa error, jika error boleh di-redirect.
+func (e *OAuthError) RedirectURL() (string, bool) {
+	if e.redirectURI == "" {
+		return "", false
+	}
+	params := url.Values{"error": {e.Code}, "error_description": {e.Description}}
+	if e.state != "" {
+		params.Set("state", e.state)
+	}
+	return appendQuery(e.redirectURI, params), true
+}
+
+// UnknownScopeError dikembalikan jika scope klien bukan nama Permission yang terdaftar.
+type UnknownScopeError struct {
+	Scope string
+}
+
+func (e *UnknownScopeError) Error() string {
+	return "scope " + e.Scope + " bukan permission yang terdaftar"
+}
+
+const (
+	// oauthSecretLength adalah panjang client secret, authorization code dan refresh token.
+	oauthSecretLength = 43
+	// oauthClientIDLength adalah panjang client_id publik.
+	oauthClientIDLength = 24
+	// pkceMethodS256 adalah satu-satunya metode PKCE yang diterima; "plain" tidak melindungi apa pun.
+	pkceMethodS256 = "S256"
+)
+
+// OAuthPolicy adalah masa berlaku token yang diterbitkan authorization server.
+type OAuthPolicy struct {
+	AccessTokenTTL  time.Duration
+	RefreshTokenTTL time.Duration // Batas mutlak sejak persetujuan; rotasi tidak memperpanjangnya
+	CodeTTL         time.Duration
+	IDTokenTTL      time.Duration
+}
+
+// OAuthClientRegistration adalah data pendaftaran klien OAuth oleh admin.
+type OAuthClientRegistration struct {
+	Name         string
+	RedirectURIs []string
+	GrantTypes   []string // Kosong berarti authorization_code dan refresh_token
+	Scopes       []string
+	Confidential bool
+	// Introspection mengizinkan klien (misalnya API gateway) memeriksa dan mencabut token apa pun,
+	// bukan hanya token yang diterbitkan untuknya. Hanya untuk klien confidential.
+	Introspection bool
+}
+
+// AuthorizeRequest adalah parameter permintaan otorisasi dari klien (RFC 6749 bagian 4.1.1 dan RFC 7636).
+type AuthorizeRequest struct {
+	ResponseType        string
+	ClientID            string
+	RedirectURI         string
+	Scope               string
+	State               string
+	CodeChallenge       string
+	CodeChallengeMethod string
+	Nonce               string // OpenID Connect: dikembalikan di ID token untuk mencegah replay
+}
+
+// AuthorizePrompt adalah hasil pemeriksaan permintaan otorisasi.
+// Jika ConsentRequired bernilai false, kode sudah diterbitkan dan RedirectURL siap dikunjungi.
+type AuthorizePrompt struct {
+	Client          *entities.OAuthClient
+	Scopes          []string
+	ConsentRequired bool
+	RedirectURL     string
+}
+
+// TokenRequest adalah parameter permintaan ke token endpoint (RFC 6749 bagian 4.1.3, 4.4.2 dan 6).
+type TokenRequest struct {
+	GrantType    string
+	Code         string
+	RedirectURI  string
+	CodeVerifier string
+	RefreshToken string
+	Scope        string
+	ClientID     string
+	ClientSecret string
+}
+
+// OAuthTokens adalah token yang diterbitkan token endpoint.
+type OAuthTokens struct {
+	AccessToken  *services.IssuedToken
+	RefreshToken string // Kosong jika klien tidak memakai grant refresh_token
+	IDToken      string // Terisi jika scope openid disetujui
+	Scopes       []string
+}
+
+// authorizationCode adalah data yang disimpan di balik authorization code sampai ditukar.
+type authorizationCode struct {
+	ClientID      string    `json:"client_id"`
+	UserID        uuid.UUID `json:"user_id"`
+	RedirectURI   string    `json:"redirect_uri"`
+	Scopes        []string  `json:"scopes"`
+	CodeChallenge string    `json:"code_challenge"`
+	Nonce         string    `json:"nonce,omitempty"`
+}
+
+// OAuthInteractor adalah use case authorization server OAuth 2.0: pendaftaran klien,
+// persetujuan pengguna dan penerbitan token.
+type OAuthInteractor struct {
+	clientRepo       repositories.OAuthClientRepository
+	refreshTokenRepo repositories.OAuthRefreshTokenRepository
+	consentRepo      repositories.OAuthConsentRepository
+	userRepo         repositories.UserRepository
+	permissionRepo   repositories.PermissionRepository
+	challengeStore   repositories.ChallengeStore
+	tokenService     services.TokenService
+	users            *UserInteractor
+	sessions         *SessionInteractor
+	policy           OAuthPolicy
+}
+
+// NewOAuthInteractor membuat instance baru dari OAuthInteractor.
+func NewOAuthInteractor(
+	cr repositories.OAuthClientRepository,
+	rr repositories.OAuthRefreshTokenRepository,
+	consents repositories.OAuthConsentRepository,
+	ur repositories.UserRepository,
+	pr repositories.PermissionRepository,
+	cs repositories.ChallengeStore,
+	ts services.TokenService,
+	users *UserInteractor,
+	sessions *SessionInteractor,
+	policy OAuthPolicy,
+) *OAuthInteractor {
+	return &OAuthInteractor{
+		clientRepo:       cr,
+		refreshTokenRepo: rr,
+		consentRepo:      consents,
+		userRepo:         ur,
+		permissionRepo:   pr,
+		challengeStore:   cs,
+		tokenService:     ts,
+		users:            users,
+		sessions:         sessions,
+		policy:           policy,
+	}
+}
+
+// RegisterClient mendaftarkan klien OAuth baru dan mengembalikan client secret plaintext yang hanya
+// ditampilkan sekali (kosong untuk klien publik). Klien client_credentials mendapat service account
+// sendiri sebagai subjek token.
+func (i *OAuthInteractor) RegisterClient(createdBy uuid.UUID, reg OAuthClientRegistration) (*entities.OAuthClient, string, error) {
+	name := strings.TrimSpace(reg.Name)
+	if name == "" {
+		return nil, "", ErrOAuthClientNameRequired
+	}
+
+	grantTypes := normalizeScopes(reg.GrantTypes)
+	if len(grantTypes) == 0 {
+		grantTypes = []string{entities.GrantTypeAuthorizationCode, entities.GrantTypeRefreshToken}
+	}
+	for _, grantType := range grantTypes {
+		switch grantType {
+		case entities.GrantTypeAuthorizationCode, entities.GrantTypeRefreshToken:
+		case entities.GrantTypeClientCredentials:
+			if !reg.Confidential {
+				return nil, "", ErrOAuthPublicClientCredentials
+			}
+		default:
+			return nil, "", &OAuthError{Code: OAuthErrUnsupportedGrantType, Description: "grant type " + grantType + " tidak didukung"}
+		}
+	}
+
+	redirectURIs := normalizeScopes(reg.RedirectURIs)
+	for _, uri := range redirectURIs {
+		if !validRedirectURI(uri) {
+			return nil, "", ErrInvalidRedirectURI
+		}
+	}
+	if containsString(grantTypes, entities.GrantTypeAuthorizationCode) && len(redirectURIs) == 0 {
+		return nil, "", ErrOAuthRedirectURIRequired
+	}
+
+	if reg.Introspection && !reg.Confidential {
+		return nil, "", ErrOAuthPublicClientIntrospection
+	}
+
+	scopes := normalizeScopes(reg.Scopes)
+	if len(scopes) == 0 {
+		return nil, "", ErrOAuthScopesRequired
+	}
+	if err := i.checkScopesExist(scopes); err != nil {
+		return nil, "", err
+	}
+
+	clientID, err := randomString(oauthClientIDLength)
+	if err != nil {
+		return nil, "", err
+	}
+
+	client := &entities.OAuthClient{
+		ClientID:      clientID,
+		Name:          name,
+		RedirectURIs:  redirectURIs,
+		GrantTypes:    grantTypes,
+		Scopes:        scopes,
+		Confidential:  reg.Confidential,
+		Introspection: reg.Introspection,
+		CreatedBy:     createdBy,
+	}
+
+	var secret string
+	if reg.Confidential {
+		if secret, err = randomString(oauthSecretLength); err != nil {
+			return nil, "", err
+		}
+		client.SecretHash = hashSecret(secret)
+	}
+
+	if client.AllowsGrant(entities.GrantTypeClientCredentials) {
+		account, err := i.users.CreateServiceAccount("oauth-"+strings.ToLower(clientID), "", name)
+		if err != nil {
+			return nil, "", err
+		}
+		client.ServiceAccountID = &account.ID
+	}
+
+	if err := i.clientRepo.Create(client); err != nil {
+		return nil, "", err
+	}
+	return client, secret, nil
+}
+
+// ListClients mengembalikan semua klien OAuth terdaftar.
+func (i *OAuthInteractor) ListClients() ([]entities.OAuthClient, error) {
+	return i.clientRepo.FindAll()
+}
+
+// DeleteClient menghapus klien beserta persetujuan dan refresh token-nya.
+// Token akses yang sudah terbit tetap berlaku sampai kedaluwarsa (paling lama AccessTokenTTL).
+func (i *OAuthInteractor) DeleteClient(id uuid.UUID) error {
+	client, err := i.clientRepo.Delete(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOAuthClientNotFound
+		}
+		return err
+	}
+
+	if err := i.refreshTokenRepo.RevokeByClient(client.ClientID, time.Now()); err != nil {
+		return err
+	}
+	return i.consentRepo.DeleteByClient(client.ClientID)
+}
+
+// PrepareAuthorization memeriksa permintaan otorisasi untuk pengguna yang sedang login.
+// Jika pengguna sudah pernah menyetujui semua scope yang diminta, kode langsung diterbitkan;
+// jika belum, pemanggil harus menampilkan layar persetujuan lalu memanggil Authorize.
+func (i *OAuthInteractor) PrepareAuthorization(ctx context.Context, userID uuid.UUID, req AuthorizeRequest) (*AuthorizePrompt, error) {
+	client, scopes, err := i.validateAuthorizeRequest(userID, req)
+	if err != nil {
+		return nil, err
+	}
+
+	prompt := &AuthorizePrompt{Client: client, Scopes: scopes, ConsentRequired: true}
+
+	consent, err := i.consentRepo.Find(userID, client.ClientID)
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+	if err == nil && consent.Covers(scopes) {
+		redirectURL, err := i.issueCode(ctx, userID, client, scopes, req)
+		if err != nil {
+			return nil, err
+		}
+		prompt.ConsentRequired = false
+		prompt.RedirectURL = redirectURL
+	}
+	return prompt, nil
+}
+
+// Authorize mencatat keputusan pengguna di layar persetujuan dan mengembalikan URL redirect ke klien,
+// berisi authorization code jika disetujui atau error access_denied jika ditolak.
+func (i *OAuthInteractor) Authorize(ctx context.Context, userID uuid.UUID, req AuthorizeRequest, approved bool) (string, error) {
+	client, scopes, err := i.validateAuthorizeRequest(userID, req)
+	if err != nil {
+		return "", err
+	}
+
+	if !approved {
+		redirectURL, _ := redirectError(req, OAuthErrAccessDenied, "pengguna menolak permintaan akses").RedirectURL()
+		return redirectURL, nil
+	}
+
+	if err := i.consentRepo.Save(&entities.OAuthConsent{UserID: userID, ClientID: client.ClientID, Scopes: scopes}); err != nil {
+		return "", err
+	}
+	return i.issueCode(ctx, userID, client, scopes, req)
+}
+
+// Token memproses permintaan ke token endpoint untuk grant authorization_code, refresh_token
+// dan client_credentials. Semua kegagalan protokol dikembalikan sebagai *OAuthError.
+func (i *OAuthInteractor) Token(ctx context.Context, req TokenRequest, info ClientInfo) (*OAuthTokens, error) {
+	client, err := i.AuthenticateClient(req.ClientID, req.ClientSecret)
+	if err != nil {
+		return nil, err
+	}
+	if !client.AllowsGrant(req.GrantType) {
+		switch req.GrantType {
+		case entities.GrantTypeAuthorizationCode, entities.GrantTypeRefreshToken, entities.GrantTypeClientCredentials:
+			return nil, &OAuthError{Code: OAuthErrUnauthorizedClient, Description: "klien tidak terdaftar untuk grant ini"}
+		default:
+			return nil, &OAuthError{Code: OAuthErrUnsupportedGrantType, Description: "grant_type tidak didukung"}
+		}
+	}
+
+	switch req.GrantType {
+	case entities.GrantTypeAuthorizationCode:
+		return i.exchangeCode(ctx, client, req, info)
+	case entities.GrantTypeRefreshToken:
+		return i.refresh(ctx, client, req)
+	default:
+		return i.clientCredentials(client, req)
+	}
+}
+
+// exchangeCode menukar authorization code dengan token akses dan refresh token.
+// Setiap penukaran membuat sesi baru sehingga aplikasi tampil dan bisa dicabut di daftar sesi pengguna.
+func (i *OAuthInteractor) exchangeCode(ctx context.Context, client *entities.OAuthClient, req TokenRequest, info ClientInfo) (*OAuthTokens, error) {
+	if req.Code == "" || req.CodeVerifier == "" {
+		return nil, &OAuthError{Code: OAuthErrInvalidRequest, Description: "code dan code_verifier wajib diisi"}
+	}
+
+	raw, err := i.challengeStore.Take(ctx, oauthCodeKey(req.Code))
+	if err != nil {
+		if errors.Is(err, repositories.ErrChallengeNotFound) {
+			return nil, &OAuthError{Code: OAuthErrInvalidGrant, Description: "authorization code tidak valid atau kedaluwarsa"}
+		}
+		return nil, err
+	}
+
+	var code authorizationCode
+	if err := json.Unmarshal(raw, &code); err != nil {
+		return nil, err
+	}
+	if code.ClientID != client.ClientID || code.RedirectURI != req.RedirectURI {
+		return nil, &OAuthError{Code: OAuthErrInvalidGrant, Description: "authorization code tidak diterbitkan untuk klien atau redirect_uri ini"}
+	}
+	if !verifyPKCE(code.CodeChallenge, req.CodeVerifier) {
+		return nil, &OAuthError{Code: OAuthErrInvalidGrant, Description: "code_verifier tidak cocok"}
+	}
+
+	user, err := i.activeUser(code.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	sessionID := uuid.New()
+	expiresAt := time.Now().Add(i.policy.RefreshTokenTTL)
+	info.Device = client.Name
+	if _, err := i.sessions.Create(ctx, sessionID, user.ID, info, expiresAt); err != nil {
+		return nil, err
+	}
+
+	refresh := &entities.OAuthRefreshToken{
+		FamilyID:  uuid.New(),
+		ClientID:  client.ClientID,
+		UserID:    user.ID,
+		SessionID: sessionID,
+		Scopes:    code.Scopes,
+		ExpiresAt: expiresAt,
+	}
+	return i.issueTokens(client, user, refresh, code.Nonce)
+}
+
+// refresh menukar refresh token dengan pasangan token baru (rotasi).
+// Pemakaian ulang token yang sudah dirotasi dianggap pencurian: seluruh keluarga token dan sesinya dicabut.
+func (i *OAuthInteractor) refresh(ctx context.Context, client *entities.OAuthClient, req TokenRequest) (*OAuthTokens, error) {
+	invalid := &OAuthError{Code: OAuthErrInvalidGrant, Description: "refresh token tidak valid atau kedaluwarsa"}
+	if req.RefreshToken == "" {
+		return nil, &OAuthError{Code: OAuthErrInvalidRequest, Description: "refresh_token wajib diisi"}
+	}
+
+	token, err := i.refreshTokenRepo.FindByHash(hashSecret(req.RefreshToken))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+	if token.ClientID != client.ClientID {
+		return nil, invalid
+	}
+
+	now := time.Now()
+	if token.UsedAt != nil && token.RevokedAt == nil {
+		i.revokeFamily(ctx, token, now)
+		return nil, invalid
+	}
+	if !token.IsActive(now) {
+		return nil, invalid
+	}
+	if err := i.refreshTokenRepo.MarkUsed(token.ID, now); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			i.revokeFamily(ctx, token, now)
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	if _, err := i.sessions.Validate(ctx, token.SessionID, token.UserID); err != nil {
+		if errors.Is(err, ErrSessionRevoked) {
+			i.revokeFamily(ctx, token, now)
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	scopes := token.Scopes
+	if req.Scope != "" {
+		// Klien boleh mempersempit scope, tidak boleh memperluasnya
+		scopes = strings.Fields(req.Scope)
+		for _, scope := range scopes {
+			if !containsString(token.Scopes, scope) {
+				return nil, &OAuthError{Code: OAuthErrInvalidScope, Description: "scope melebihi persetujuan awal"}
+			}
+		}
+	}
+
+	user, err := i.activeUser(token.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	next := &entities.OAuthRefreshToken{
+		FamilyID:  token.FamilyID,
+		ClientID:  client.ClientID,
+		UserID:    user.ID,
+		SessionID: token.SessionID,
+		Scopes:    scopes,
+		ExpiresAt: token.ExpiresAt,
+	}
+	return i.issueTokens(client, user, next, "")
+}
+
+// clientCredentials menerbitkan token akses atas nama klien itu sendiri, tanpa refresh token.
+func (i *OAuthInteractor) clientCredentials(client *entities.OAuthClient, req TokenRequest) (*OAuthTokens, error) {
+	if client.ServiceAccountID == nil {
+		return nil, &OAuthError{Code: OAuthErrUnauthorizedClient, Description: "klien tidak memiliki service account"}
+	}
+
+	scopes, oauthErr := requestedScopes(client, req.Scope)
+	if oauthErr != nil {
+		return nil, oauthErr
+	}
+
+	account, err := i.activeUser(*client.ServiceAccountID)
+	if err != nil {
+		return nil, err
+	}
+
+	access, err := i.tokenService.GenerateDelegatedToken(account, services.DelegatedGrant{
+		ClientID: client.ClientID,
+		Scopes:   scopes,
+		TTL:      i.policy.AccessTokenTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &OAuthTokens{AccessToken: access, Scopes: scopes}, nil
+}
+
+// issueTokens menerbitkan token akses yang terikat ke sesi refresh, ID token jika scope openid disetujui,
+// lalu menyimpan refresh token jika klien memakai grant refresh_token.
+func (i *OAuthInteractor) issueTokens(client *entities.OAuthClient, user *entities.User, refresh *entities.OAuthRefreshToken, nonce string) (*OAuthTokens, error) {
+	access, err := i.tokenService.GenerateDelegatedToken(user, services.DelegatedGrant{
+		SessionID: refresh.SessionID,
+		ClientID:  client.ClientID,
+		Scopes:    refresh.Scopes,
+		TTL:       i.policy.AccessTokenTTL,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	tokens := &OAuthTokens{AccessToken: access, Scopes: refresh.Scopes}
+	if containsString(refresh.Scopes, entities.ScopeOpenID) {
+		idToken, err := i.tokenService.GenerateIDToken(user, services.IDTokenRequest{
+			ClientID: client.ClientID,
+			Nonce:    nonce,
+			Claims:   userInfoClaims(user, refresh.Scopes),
+			TTL:      i.policy.IDTokenTTL,
+		})
+		if err != nil {
+			return nil, err
+		}
+		tokens.IDToken = idToken.Token
+	}
+	if !client.AllowsGrant(entities.GrantTypeRefreshToken) {
+		return tokens, nil
+	}
+
+	plain, err := randomString(oauthSecretLength)
+	if err != nil {
+		return nil, err
+	}
+	refresh.TokenHash = hashSecret(plain)
+	if err := i.refreshTokenRepo.Create(refresh); err != nil {
+		return nil, err
+	}
+	tokens.RefreshToken = plain
+	return tokens, nil
+}
+
+// validateAuthorizeRequest memeriksa permintaan otorisasi dan menghitung scope yang bisa diberikan:
+// scope yang diminta (atau semua scope klien jika kosong) yang juga dimiliki pengguna.
+// Scope OpenID Connect selalu bisa diberikan karena hanya menyangkut data pengguna itu sendiri.
+func (i *OAuthInteractor) validateAuthorizeRequest(userID uuid.UUID, req AuthorizeRequest) (*entities.OAuthClient, []string, error) {
+	client, err := i.clientRepo.FindByClientID(req.ClientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil, ErrOAuthClientNotFound
+		}
+		return nil, nil, err
+	}
+	if req.RedirectURI == "" || !client.AllowsRedirectURI(req.RedirectURI) {
+		return nil, nil, ErrInvalidRedirectURI
+	}
+
+	// Mulai di sini redirect_uri sudah tepercaya, error dikirim balik ke klien
+	if req.ResponseType != "code" {
+		return nil, nil, redirectError(req, OAuthErrUnsupportedResponseType, "hanya response_type=code yang didukung")
+	}
+	if !client.AllowsGrant(entities.GrantTypeAuthorizationCode) {
+		return nil, nil, redirectError(req, OAuthErrUnauthorizedClient, "klien tidak terdaftar untuk grant authorization_code")
+	}
+	if req.CodeChallenge == "" || req.CodeChallengeMethod != pkceMethodS256 {
+		return nil, nil, redirectError(req, OAuthErrInvalidRequest, "PKCE wajib dengan code_challenge_method=S256")
+	}
+
+	scopes, oauthErr := requestedScopes(client, req.Scope)
+	if oauthErr != nil {
+		return nil, nil, redirectError(req, oauthErr.Code, oauthErr.Description)
+	}
+
+	user, err := i.activeUser(userID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if !user.IsSuperuser {
+		held, err := i.permissionRepo.FindNamesByUser(user.ID)
+		if err != nil {
+			return nil, nil, err
+		}
+		heldSet := entities.NewPermissionSet(held)
+		granted := make([]string, 0, len(scopes))
+		for _, scope := range scopes {
+			if entities.IsOIDCScope(scope) || containsString(held, scope) || heldSet.Allows(scope) {
+				granted = append(granted, scope)
+			}
+		}
+		scopes = granted
+	}
+	if len(scopes) == 0 {
+		return nil, nil, redirectError(req, OAuthErrInvalidScope, "pengguna tidak memiliki permission untuk scope yang diminta")
+	}
+	return client, scopes, nil
+}
+
+// issueCode menerbitkan authorization code sekali pakai dan mengembalikan URL redirect ke klien.
+func (i *OAuthInteractor) issueCode(ctx context.Context, userID uuid.UUID, client *entities.OAuthClient, scopes []string, req AuthorizeRequest) (string, error) {
+	plain, err := randomString(oauthSecretLength)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := json.Marshal(authorizationCode{
+		ClientID:      client.ClientID,
+		UserID:        userID,
+		RedirectURI:   req.RedirectURI,
+		Scopes:        scopes,
+		CodeChallenge: req.CodeChallenge,
+		Nonce:         req.Nonce,
+	})
+	if err != nil {
+		return "", err
+	}
+	if err := i.challengeStore.Save(ctx, oauthCodeKey(plain), raw, i.policy.CodeTTL); err != nil {
+		return "", err
+	}
+
+	params := url.Values{"code": {plain}}
+	if req.State != "" {
+		params.Set("state", req.State)
+	}
+	return appendQuery(req.RedirectURI, params), nil
+}
+
+// AuthenticateClient memverifikasi client_id dan client_secret. Klien publik tidak boleh mengirim secret.
+// Kegagalan dikembalikan sebagai *OAuthError dengan kode invalid_client.
+func (i *OAuthInteractor) AuthenticateClient(clientID, secret string) (*entities.OAuthClient, error) {
+	invalid := &OAuthError{Code: OAuthErrInvalidClient, Description: "autentikasi klien gagal"}
+	if clientID == "" {
+		return nil, invalid
+	}
+
+	client, err := i.clientRepo.FindByClientID(clientID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, invalid
+		}
+		return nil, err
+	}
+
+	if !client.Confidential {
+		if secret != "" {
+			return nil, invalid
+		}
+		return client, nil
+	}
+	if subtle.ConstantTimeCompare([]byte(client.SecretHash), []byte(hashSecret(secret))) != 1 {
+		return nil, invalid
+	}
+	return client, nil
+}
+
+// activeUser memuat pengguna dan memastikan akunnya masih aktif.
+func (i *OAuthInteractor) activeUser(userID uuid.UUID) (*entities.User, error) {
+	user, err := i.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, &OAuthError{Code: OAuthErrInvalidGrant, Description: "pengguna tidak ditemukan"}
+		}
+		return nil, err
+	}
+	if !user.IsActive {
+		return nil, &OAuthError{Code: OAuthErrInvalidGrant, Description: "akun pengguna tidak aktif"}
+	}
+	return user, nil
+}
+
+// revokeFamily mencabut seluruh keluarga refresh token beserta sesinya.
+// Kegagalan hanya dicatat karena permintaan tetap ditolak.
+func (i *OAuthInteractor) revokeFamily(ctx context.Context, token *entities.OAuthRefreshToken, now time.Time) {
+	if err := i.refreshTokenRepo.RevokeFamily(token.FamilyID, now); err != nil {
+		log.Printf("Gagal mencabut keluarga refresh token %s: %v", token.FamilyID, err)
+	}
+	if err := i.sessions.Revoke(ctx, token.UserID, token.SessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		log.Printf("Gagal mencabut sesi OAuth %s: %v", token.SessionID, err)
+	}
+}
+
+// checkScopesExist memastikan setiap scope adalah nama Permission yang terdaftar atau scope OpenID Connect.
+func (i *OAuthInteractor) checkScopesExist(scopes []string) error {
+	existing, err := i.permissionRepo.FindExistingNames(scopes)
+	if err != nil {
+		return err
+	}
+	for _, scope := range scopes {
+		if !entities.IsOIDCScope(scope) && !containsString(existing, scope) {
+			return &UnknownScopeError{Scope: scope}
+		}
+	}
+	return nil
+}
+
+// requestedScopes mengurai parameter scope dan memastikan semuanya diizinkan untuk klien.
+// Parameter kosong berarti semua scope klien.
+func requestedScopes(client *entities.OAuthClient, scope string) ([]string, *OAuthError) {
+	scopes := normalizeScopes(strings.Fields(scope))
+	if len(scopes) == 0 {
+		return client.Scopes, nil
+	}
+	for _, s := range scopes {
+		if !client.AllowsScope(s) {
+			return nil, &OAuthError{Code: OAuthErrInvalidScope, Description: "scope " + s + " tidak diizinkan untuk klien"}
+		}
+	}
+	return scopes, nil
+}
+
+// redirectError membuat error otorisasi yang dikirim balik ke redirect_uri klien.
+func redirectError(req AuthorizeRequest, code, description string) *OAuthError {
+	return &OAuthError{Code: code, Description: description, redirectURI: req.RedirectURI, state: req.State}
+}
+
+// verifyPKCE memeriksa code_verifier terhadap code_challenge S256 (RFC 7636 bagian 4.6).
+func verifyPKCE(challenge, verifier string) bool {
+	if len(verifier) < 43 || len(verifier) > 128 {
+		return false
+	}
+	sum := sha256.Sum256([]byte(verifier))
+	expected := base64.RawURLEncoding.EncodeToString(sum[:])
+	return subtle.ConstantTimeCompare([]byte(expected), []byte(challenge)) == 1
+}
+
+// validRedirectURI menerima URI absolut tanpa fragment. Skema http hanya boleh untuk loopback
+// (aplikasi desktop dan pengembangan); skema kustom untuk aplikasi mobile harus berbentuk nama domain
+// terbalik seperti com.example.app (RFC 8252 bagian 7.1), sehingga javascript:, data: dan file: ditolak.
+func validRedirectURI(raw string) bool {
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" || u.Fragment != "" {
+		return false
+	}
+	switch u.Scheme {
+	case "https":
+		return u.Host != ""
+	case "http":
+		host := u.Hostname()
+		return host == "localhost" || host == "127.0.0.1" || host == "::1"
+	default:
+		return strings.Contains(u.Scheme, ".")
+	}
+}
+
+// appendQuery menambahkan parameter ke URI yang mungkin sudah memiliki query string.
+func appendQuery(uri string, params url.Values) string {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return uri
+	}
+	query := u.Query()
+	for key, values := range params {
+		query[key] = values
+	}
+	u.RawQuery = query.Encode()
+	return u.String()
+}
+
+// oauthCodeKey membentuk key ChallengeStore untuk authorization code; hanya hash kode yang disimpan.
+func oauthCodeKey(code string) string {
+	return "oauth:code:" + hashSecret(code)
+}
+
+// containsString mengembalikan true jika value ada di values.
+func containsString(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,33 @@
+package interactors
+
+import "testing"
+
+func TestValidRedirectURI(t *testing.T) {
+	tests := []struct {
+		uri  string
+		want bool
+	}{
+		{"https://app.example.com/callback", true},
+		{"https://app.example.com/callback?tenant=acme", true},
+		{"http://localhost:8080/callback", true},
+		{"http://127.0.0.1/callback", true},
+		{"http://[::1]:3000/callback", true},
+		{"com.example.app:/oauth2redirect", true},
+		{"com.example.app://callback", true},
+		{"https:///callback", false},
+		{"https://app.example.com/callback#token", false},
+		{"http://app.example.com/callback", false},
+		{"/callback", false},
+		{"javascript:alert(document.cookie)", false},
+		{"JavaScript:alert(1)", false},
+		{"data:text/html,<script>alert(1)</script>", false},
+		{"file:///etc/passwd", false},
+		{"vbscript:msgbox(1)", false},
+		{"myapp://callback", false},
+	}
+	for _, tt := range tests {
+		if got := validRedirectURI(tt.uri); got != tt.want {
+			t.Errorf("validRedirectURI(%q) = %v, ingin %v", tt.uri, got, tt.want)
+		}
+	}
+}
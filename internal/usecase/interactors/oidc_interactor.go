@@ -0,0 +1,119 @@
+package interactors
+
+import (
+	"errors"
+	"strings"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"gorm.io/gorm"
+)
+
+// ErrOpenIDScopeRequired dikembalikan jika token OAuth atau API key dipakai ke /userinfo tanpa scope openid.
+var ErrOpenIDScopeRequired = errors.New("token tidak memiliki scope openid")
+
+// OIDCProvider adalah alamat publik OpenID Provider.
+type OIDCProvider struct {
+	Issuer           string // Nilai "iss" di semua token
+	AuthorizationURL string // Halaman persetujuan yang memanggil GET /oauth/authorize
+}
+
+// ProviderMetadata adalah dokumen discovery OpenID Connect (OpenID Connect Discovery 1.0 bagian 3).
+type ProviderMetadata struct {
+	Issuer                            string   `json:"issuer"`
+	AuthorizationEndpoint             string   `json:"authorization_endpoint"`
+	TokenEndpoint                     string   `json:"token_endpoint"`
+	UserInfoEndpoint                  string   `json:"userinfo_endpoint"`
+	JWKSURI                           string   `json:"jwks_uri"`
+	IntrospectionEndpoint             string   `json:"introspection_endpoint"`
+	RevocationEndpoint                string   `json:"revocation_endpoint"`
+	ScopesSupported                   []string `json:"scopes_supported"`
+	ResponseTypesSupported            []string `json:"response_types_supported"`
+	GrantTypesSupported               []string `json:"grant_types_supported"`
+	SubjectTypesSupported             []string `json:"subject_types_supported"`
+	IDTokenSigningAlgValuesSupported  []string `json:"id_token_signing_alg_values_supported"`
+	TokenEndpointAuthMethodsSupported []string `json:"token_endpoint_auth_methods_supported"`
+	CodeChallengeMethodsSupported     []string `json:"code_challenge_methods_supported"`
+	ClaimsSupported                   []string `json:"claims_supported"`
+}
+
+// OIDCInteractor adalah use case OpenID Provider: discovery, kunci publik dan klaim pengguna.
+type OIDCInteractor struct {
+	keyManager services.KeyManager
+	userRepo   repositories.UserRepository
+	provider   OIDCProvider
+}
+
+// NewOIDCInteractor membuat instance baru dari OIDCInteractor.
+func NewOIDCInteractor(km services.KeyManager, ur repositories.UserRepository, provider OIDCProvider) *OIDCInteractor {
+	return &OIDCInteractor{keyManager: km, userRepo: ur, provider: provider}
+}
+
+// Metadata mengembalikan dokumen discovery untuk /.well-known/openid-configuration.
+func (i *OIDCInteractor) Metadata() *ProviderMetadata {
+	base := strings.TrimSuffix(i.provider.Issuer, "/")
+	return &ProviderMetadata{
+		Issuer:                            i.provider.Issuer,
+		AuthorizationEndpoint:             i.provider.AuthorizationURL,
+		TokenEndpoint:                     base + "/oauth/token",
+		UserInfoEndpoint:                  base + "/userinfo",
+		JWKSURI:                           base + "/jwks.json",
+		IntrospectionEndpoint:             base + "/oauth/introspect",
+		RevocationEndpoint:                base + "/oauth/revoke",
+		ScopesSupported:                   []string{entities.ScopeOpenID, entities.ScopeProfile, entities.ScopeEmail},
+		ResponseTypesSupported:            []string{"code"},
+		GrantTypesSupported:               []string{entities.GrantTypeAuthorizationCode, entities.GrantTypeRefreshToken, entities.GrantTypeClientCredentials},
+		SubjectTypesSupported:             []string{"public"},
+		IDTokenSigningAlgValuesSupported:  []string{i.keyManager.Algorithm()},
+		TokenEndpointAuthMethodsSupported: []string{"client_secret_basic", "client_secret_post", "none"},
+		CodeChallengeMethodsSupported:     []string{pkceMethodS256},
+		ClaimsSupported:                   []string{"sub", "iss", "aud", "exp", "iat", "nonce", "name", "given_name", "family_name", "preferred_username", "updated_at", "email"},
+	}
+}
+
+// PublicKeys mengembalikan kunci publik untuk /jwks.json.
+func (i *OIDCInteractor) PublicKeys() ([]services.JSONWebKey, error) {
+	return i.keyManager.PublicKeys()
+}
+
+// UserInfo mengembalikan klaim pengguna untuk /userinfo sesuai scope token.
+// Token OAuth wajib memiliki scope openid; token login mendapat semua klaim.
+func (i *OIDCInteractor) UserInfo(claims *services.TokenClaims) (map[string]interface{}, error) {
+	scopes := []string{entities.ScopeOpenID, entities.ScopeProfile, entities.ScopeEmail}
+	if claims.IsDelegated() {
+		if !containsString(claims.Scopes, entities.ScopeOpenID) {
+			return nil, ErrOpenIDScopeRequired
+		}
+		scopes = claims.Scopes
+	}
+
+	user, err := i.userRepo.FindByID(claims.UserID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pengguna tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	info := userInfoClaims(user, scopes)
+	info["sub"] = user.ID.String()
+	return info, nil
+}
+
+// userInfoClaims membentuk klaim standar OpenID Connect dari User sesuai scope yang disetujui.
+func userInfoClaims(user *entities.User, scopes []string) map[string]interface{} {
+	claims := map[string]interface{}{}
+	if containsString(scopes, entities.ScopeProfile) {
+		claims["name"] = user.WebAuthnDisplayName()
+		claims["given_name"] = user.FirstName
+		claims["family_name"] = user.LastName
+		claims["preferred_username"] = user.Username
+		claims["updated_at"] = user.UpdatedAt.Unix()
+	}
+	if containsString(scopes, entities.ScopeEmail) {
+		claims["email"] = user.Email
+	}
+	return claims
+}
@@ -0,0 +1,317 @@
+package interactors
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strings"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrOrganizationNotFound dikembalikan jika organisasi tidak ada.
+	ErrOrganizationNotFound = errors.New("organisasi tidak ditemukan")
+	// ErrOrganizationNameRequired dikembalikan jika nama organisasi kosong.
+	ErrOrganizationNameRequired = errors.New("nama organisasi wajib diisi")
+	// ErrOrganizationSlugInvalid dikembalikan jika slug bukan label subdomain yang valid.
+	ErrOrganizationSlugInvalid = errors.New("slug organisasi hanya boleh berisi huruf kecil, angka dan tanda hubung")
+	// ErrOrganizationSlugTaken dikembalikan jika slug sudah dipakai organisasi lain.
+	ErrOrganizationSlugTaken = errors.New("slug organisasi sudah dipakai")
+	// ErrNotOrganizationMember dikembalikan jika pengguna bukan anggota organisasi.
+	ErrNotOrganizationMember = errors.New("pengguna bukan anggota organisasi")
+	// ErrOrganizationMemberExists dikembalikan jika pengguna sudah menjadi anggota organisasi.
+	ErrOrganizationMemberExists = errors.New("pengguna sudah menjadi anggota organisasi")
+	// ErrOrganizationUserNotFound dikembalikan jika pengguna yang akan ditambahkan tidak ada.
+	ErrOrganizationUserNotFound = errors.New("pengguna tidak ditemukan")
+	// ErrOrganizationRoleNotFound dikembalikan jika salah satu role yang diminta tidak terdaftar.
+	ErrOrganizationRoleNotFound = errors.New("role tidak ditemukan")
+	// ErrOrganizationRoleNotAssignable dikembalikan jika role tidak ditandai boleh diberikan ke anggota organisasi.
+	ErrOrganizationRoleNotAssignable = errors.New("role tidak dapat diberikan ke anggota organisasi")
+)
+
+// organizationSlugPattern membatasi slug ke satu label DNS agar bisa dipakai sebagai subdomain.
+var organizationSlugPattern = regexp.MustCompile(`^[a-z0-9]([a-z0-9-]{0,61}[a-z0-9])?$`)
+
+// OrganizationInteractor adalah use case untuk organisasi (tenant), keanggotaan dan Role per organisasi.
+// Role anggota hanya boleh berupa Role yang ditandai TenantRole. Role anggota diberikan tanpa
+// penetapan langsung sehingga Role yang punya ApprovalPolicy ditolak, dan tidak boleh melanggar aturan
+// pemisahan tugas bersama Role global anggota tersebut.
+type OrganizationInteractor struct {
+	organizationRepo repositories.OrganizationRepository
+	memberRepo       repositories.OrganizationMemberRepository
+	userRepo         repositories.UserRepository
+	roleRepo         repositories.RoleRepository
+	grants           *RoleGrantInteractor
+}
+
+// NewOrganizationInteractor membuat instance baru dari OrganizationInteractor.
+func NewOrganizationInteractor(
+	or repositories.OrganizationRepository,
+	mr repositories.OrganizationMemberRepository,
+	ur repositories.UserRepository,
+	rr repositories.RoleRepository,
+	grants *RoleGrantInteractor,
+) *OrganizationInteractor {
+	return &OrganizationInteractor{organizationRepo: or, memberRepo: mr, userRepo: ur, roleRepo: rr, grants: grants}
+}
+
+// Create membuat organisasi baru.
+func (i *OrganizationInteractor) Create(name, slug string) (*entities.Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrOrganizationNameRequired
+	}
+	slug = strings.ToLower(strings.TrimSpace(slug))
+	if !organizationSlugPattern.MatchString(slug) {
+		return nil, ErrOrganizationSlugInvalid
+	}
+
+	if _, err := i.organizationRepo.FindBySlug(slug); err == nil {
+		return nil, ErrOrganizationSlugTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	organization := &entities.Organization{Name: name, Slug: slug}
+	if err := i.organizationRepo.Create(organization); err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+// List mengembalikan semua organisasi.
+func (i *OrganizationInteractor) List() ([]entities.Organization, error) {
+	return i.organizationRepo.FindAll()
+}
+
+// ListForUser mengembalikan organisasi tempat pengguna menjadi anggota.
+func (i *OrganizationInteractor) ListForUser(userID uuid.UUID) ([]entities.Organization, error) {
+	return i.organizationRepo.FindByUser(userID)
+}
+
+// Rename mengganti nama organisasi. Slug tidak bisa diubah karena dipakai di subdomain dan integrasi.
+func (i *OrganizationInteractor) Rename(id uuid.UUID, name string) (*entities.Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrOrganizationNameRequired
+	}
+
+	organization, err := i.find(id)
+	if err != nil {
+		return nil, err
+	}
+	organization.Name = name
+	if err := i.organizationRepo.Update(organization); err != nil {
+		return nil, err
+	}
+	return organization, nil
+}
+
+// Delete menghapus organisasi beserta seluruh keanggotaannya. Akun pengguna tidak ikut dihapus.
+func (i *OrganizationInteractor) Delete(id uuid.UUID) error {
+	if err := i.organizationRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrOrganizationNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Resolve mencari organisasi dari ID atau slug, misalnya dari header X-Organization atau subdomain.
+func (i *OrganizationInteractor) Resolve(identifier string) (*entities.Organization, error) {
+	identifier = strings.TrimSpace(identifier)
+	if id, err := uuid.Parse(identifier); err == nil {
+		return i.find(id)
+	}
+
+	organization, err := i.organizationRepo.FindBySlug(strings.ToLower(identifier))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return organization, nil
+}
+
+// Membership mengembalikan keanggotaan pengguna di organisasi beserta Role-nya.
+func (i *OrganizationInteractor) Membership(organizationID, userID uuid.UUID) (*entities.OrganizationMember, error) {
+	member, err := i.memberRepo.Find(organizationID, userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrNotOrganizationMember
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+// Members mengembalikan semua anggota organisasi beserta Role-nya.
+func (i *OrganizationInteractor) Members(organizationID uuid.UUID) ([]entities.OrganizationMember, error) {
+	return i.memberRepo.FindByOrganization(organizationID)
+}
+
+// AddMember menjadikan pengguna yang sudah ada anggota organisasi dengan Role roleNames.
+func (i *OrganizationInteractor) AddMember(organizationID, userID uuid.UUID, roleNames []string) (*entities.OrganizationMember, error) {
+	if _, err := i.userRepo.FindByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationUserNotFound
+		}
+		return nil, err
+	}
+	if _, err := i.memberRepo.Find(organizationID, userID); err == nil {
+		return nil, ErrOrganizationMemberExists
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	roles, err := resolveRoles(i.roleRepo, roleNames, ErrOrganizationRoleNotFound)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkTenantRoles(roles); err != nil {
+		return nil, err
+	}
+	if err := i.grants.CheckNoApproval(roles); err != nil {
+		return nil, err
+	}
+	if err := i.grants.CheckMemberConflicts(organizationID, userID, roles); err != nil {
+		return nil, err
+	}
+
+	member := &entities.OrganizationMember{OrganizationID: organizationID, UserID: userID, Roles: roles}
+	if err := i.memberRepo.Create(member); err != nil {
+		return nil, err
+	}
+	return member, nil
+}
+
+// SetMemberRoles mengganti Role anggota di organisasi. Hanya Role yang baru ditambahkan yang diperiksa
+// penandaan TenantRole, kebijakan persetujuan dan aturan pemisahan tugasnya, sehingga Role yang
+// sudah dipegang tidak menghalangi perubahan lain.
+func (i *OrganizationInteractor) SetMemberRoles(organizationID, userID uuid.UUID, roleNames []string) (*entities.OrganizationMember, error) {
+	member, err := i.Membership(organizationID, userID)
+	if err != nil {
+		return nil, err
+	}
+	roles, err := resolveRoles(i.roleRepo, roleNames, ErrOrganizationRoleNotFound)
+	if err != nil {
+		return nil, err
+	}
+	added := addedRoles(member.Roles, roles)
+	if err := checkTenantRoles(added); err != nil {
+		return nil, err
+	}
+	if err := i.grants.CheckNoApproval(added); err != nil {
+		return nil, err
+	}
+	if err := i.grants.CheckMemberConflicts(organizationID, userID, added); err != nil {
+		return nil, err
+	}
+
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	if err := i.memberRepo.ReplaceRoles(member.ID, roleIDs); err != nil {
+		return nil, err
+	}
+	member.Roles = roles
+	return member, nil
+}
+
+// Join menjadikan pengguna anggota organisasi dengan tambahan Role roleNames. Jika pengguna sudah anggota,
+// Role yang sudah dimiliki tetap dipertahankan. Dipakai saat undangan diterima.
+func (i *OrganizationInteractor) Join(organizationID, userID uuid.UUID, roleNames []string) (*entities.OrganizationMember, error) {
+	member, err := i.memberRepo.Find(organizationID, userID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return i.AddMember(organizationID, userID, roleNames)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	names := make([]string, 0, len(member.Roles)+len(roleNames))
+	for _, role := range member.Roles {
+		names = append(names, role.Name)
+	}
+	return i.SetMemberRoles(organizationID, userID, append(names, roleNames...))
+}
+
+// RemoveMember mengeluarkan pengguna dari organisasi. Akun penggunanya tetap ada.
+func (i *OrganizationInteractor) RemoveMember(organizationID, userID uuid.UUID) error {
+	if err := i.memberRepo.Delete(organizationID, userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrNotOrganizationMember
+		}
+		return err
+	}
+	return nil
+}
+
+func (i *OrganizationInteractor) find(id uuid.UUID) (*entities.Organization, error) {
+	organization, err := i.organizationRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return organization, nil
+}
+
+// checkTenantRoles memastikan semua Role roles boleh diberikan ke anggota organisasi.
+func checkTenantRoles(roles []*entities.Role) error {
+	for _, role := range roles {
+		if !role.TenantRole {
+			return fmt.Errorf("%w: %s", ErrOrganizationRoleNotAssignable, role.Name)
+		}
+	}
+	return nil
+}
+
+// addedRoles mengembalikan Role roles yang belum ada di current.
+func addedRoles(current, roles []*entities.Role) []*entities.Role {
+	held := make(map[uuid.UUID]bool, len(current))
+	for _, role := range current {
+		held[role.ID] = true
+	}
+	var added []*entities.Role
+	for _, role := range roles {
+		if !held[role.ID] {
+			added = append(added, role)
+		}
+	}
+	return added
+}
+
+// resolveRoles memastikan semua nama role terdaftar dan mengembalikan Role-nya sesuai urutan names.
+// Nama yang tidak terdaftar menghasilkan error notFound beserta nama role-nya.
+func resolveRoles(roleRepo repositories.RoleRepository, names []string, notFound error) ([]*entities.Role, error) {
+	names = normalizeScopes(names)
+	found, err := roleRepo.FindByNames(names)
+	if err != nil {
+		return nil, err
+	}
+
+	byName := make(map[string]*entities.Role, len(found))
+	for n := range found {
+		byName[found[n].Name] = &found[n]
+	}
+
+	roles := make([]*entities.Role, 0, len(names))
+	for _, name := range names {
+		role, ok := byName[name]
+		if !ok {
+			return nil, fmt.Errorf("%w: %s", notFound, name)
+		}
+		roles = append(roles, role)
+	}
+	return roles, nil
+}
@@ -0,0 +1,161 @@
+package interactors
+
+import (
+	"errors"
+	"testing"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// organizationFixture adalah OrganizationInteractor dengan repositori di memori.
+type organizationFixture struct {
+	organizations *OrganizationInteractor
+	orgs          *staticOrganizationRepository
+	members       *memoryOrganizationMemberRepository
+	policies      *staticApprovalPolicyRepository // Role yang dimasukkan ke sini memerlukan persetujuan
+	rules         *staticSoDRuleRepository
+}
+
+// newTestOrganizations membuat organizationFixture dengan pengguna user dan Role roles yang semuanya
+// boleh diberikan ke anggota organisasi.
+func newTestOrganizations(user *entities.User, roles ...string) *organizationFixture {
+	users := newMemoryUserRepository(user)
+	roleRepo := &memoryRoleRepository{users: users}
+	for _, name := range roles {
+		roleRepo.roles = append(roleRepo.roles, entities.Role{ID: uuid.New(), Name: name, TenantRole: true})
+	}
+	f := &organizationFixture{
+		orgs:     &staticOrganizationRepository{},
+		members:  &memoryOrganizationMemberRepository{roles: roleRepo},
+		policies: &staticApprovalPolicyRepository{},
+		rules:    &staticSoDRuleRepository{},
+	}
+	roleRepo.members = f.members
+	sod := NewSoDInteractor(f.rules, roleRepo, nil, nil, nil)
+	grants := NewRoleGrantInteractor(roleRepo, users, f.policies, sod, nil, nil)
+	f.organizations = NewOrganizationInteractor(f.orgs, f.members, users, roleRepo, grants)
+	return f
+}
+
+// role mengembalikan Role terdaftar bernama name.
+func (f *organizationFixture) role(name string) *entities.Role {
+	found, _ := f.organizations.roleRepo.FindByNames([]string{name})
+	return &found[0]
+}
+
+func TestOrganizationMemberRolesRequireTenantRoles(t *testing.T) {
+	user := &entities.User{ID: uuid.New(), Username: "alice", IsActive: true}
+	f := newTestOrganizations(user, "viewer", "superadmin")
+	roleRepo := f.organizations.roleRepo.(*memoryRoleRepository)
+	roleRepo.roles[1].TenantRole = false
+	orgID := uuid.New()
+
+	if _, err := f.organizations.AddMember(orgID, user.ID, []string{"viewer", "superadmin"}); !errors.Is(err, ErrOrganizationRoleNotAssignable) {
+		t.Fatalf("AddMember: err = %v, ingin ErrOrganizationRoleNotAssignable", err)
+	}
+	if len(f.members.members) != 0 {
+		t.Fatal("anggota tetap ditambahkan dengan role yang bukan role organisasi")
+	}
+
+	if _, err := f.organizations.AddMember(orgID, user.ID, []string{"viewer"}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	if _, err := f.organizations.SetMemberRoles(orgID, user.ID, []string{"viewer", "superadmin"}); !errors.Is(err, ErrOrganizationRoleNotAssignable) {
+		t.Fatalf("SetMemberRoles: err = %v, ingin ErrOrganizationRoleNotAssignable", err)
+	}
+	if _, err := f.organizations.Join(orgID, user.ID, []string{"superadmin"}); !errors.Is(err, ErrOrganizationRoleNotAssignable) {
+		t.Fatalf("Join: err = %v, ingin ErrOrganizationRoleNotAssignable", err)
+	}
+	if member, _ := f.members.Find(orgID, user.ID); len(member.Roles) != 1 || member.Roles[0].Name != "viewer" {
+		t.Fatalf("role anggota = %v, ingin [viewer]", member.Roles)
+	}
+}
+
+func TestOrganizationMemberRolesRefuseApprovalRoles(t *testing.T) {
+	user := &entities.User{ID: uuid.New(), Username: "alice", IsActive: true}
+	f := newTestOrganizations(user, "viewer", "billing-admin")
+	organizations, members, policies := f.organizations, f.members, f.policies
+	gated := f.role("billing-admin")
+	policies.roles = []uuid.UUID{gated.ID}
+	orgID := uuid.New()
+
+	if _, err := organizations.AddMember(orgID, user.ID, []string{"viewer", "billing-admin"}); !errors.Is(err, ErrRoleGrantApprovalRequired) {
+		t.Fatalf("AddMember: err = %v, ingin ErrRoleGrantApprovalRequired", err)
+	}
+	if len(members.members) != 0 {
+		t.Fatal("anggota tetap ditambahkan dengan role yang memerlukan persetujuan")
+	}
+
+	if _, err := organizations.AddMember(orgID, user.ID, []string{"viewer"}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	for _, change := range []func() error{
+		func() error {
+			_, err := organizations.SetMemberRoles(orgID, user.ID, []string{"viewer", "billing-admin"})
+			return err
+		},
+		func() error {
+			_, err := organizations.Join(orgID, user.ID, []string{"billing-admin"})
+			return err
+		},
+	} {
+		if err := change(); !errors.Is(err, ErrRoleGrantApprovalRequired) {
+			t.Fatalf("err = %v, ingin ErrRoleGrantApprovalRequired", err)
+		}
+	}
+	if member, _ := members.Find(orgID, user.ID); len(member.Roles) != 1 || member.Roles[0].Name != "viewer" {
+		t.Fatalf("role anggota = %v, ingin [viewer]", member.Roles)
+	}
+
+	// Role berpersetujuan yang sudah dipegang tidak menghalangi perubahan lain
+	policies.roles = nil
+	if _, err := organizations.SetMemberRoles(orgID, user.ID, []string{"viewer", "billing-admin"}); err != nil {
+		t.Fatalf("SetMemberRoles: %v", err)
+	}
+	policies.roles = []uuid.UUID{gated.ID}
+	if _, err := organizations.SetMemberRoles(orgID, user.ID, []string{"billing-admin"}); err != nil {
+		t.Fatalf("SetMemberRoles tanpa role baru: %v", err)
+	}
+}
+
+func TestOrganizationMemberRolesCheckSoD(t *testing.T) {
+	// payments dan approver tidak boleh dipegang bersamaan. alice memegang payments secara global sehingga
+	// tidak boleh menerima approver di organisasi mana pun, sedangkan Role anggota di satu organisasi tidak
+	// membatasi Role anggota di organisasi lain.
+	user := &entities.User{ID: uuid.New(), Username: "alice", IsActive: true}
+	f := newTestOrganizations(user, "payments", "approver", "requester", "viewer")
+	f.rules.rules = []entities.SoDRule{
+		{Name: "pembayaran", Roles: []*entities.Role{f.role("payments"), f.role("approver")}},
+		{Name: "pengajuan", Roles: []*entities.Role{f.role("requester"), f.role("approver")}},
+	}
+	user.Roles = []*entities.Role{f.role("payments")}
+	acme, globex := uuid.New(), uuid.New()
+
+	if _, err := f.organizations.AddMember(acme, user.ID, []string{"viewer", "approver"}); !errors.Is(err, ErrSoDViolation) {
+		t.Fatalf("AddMember: err = %v, ingin ErrSoDViolation", err)
+	}
+	if len(f.members.members) != 0 {
+		t.Fatal("anggota tetap ditambahkan meskipun melanggar aturan")
+	}
+
+	user.Roles = nil
+	if _, err := f.organizations.AddMember(acme, user.ID, []string{"requester"}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	if _, err := f.organizations.SetMemberRoles(acme, user.ID, []string{"requester", "approver"}); !errors.Is(err, ErrSoDViolation) {
+		t.Fatalf("SetMemberRoles: err = %v, ingin ErrSoDViolation", err)
+	}
+	if _, err := f.organizations.Join(acme, user.ID, []string{"approver"}); !errors.Is(err, ErrSoDViolation) {
+		t.Fatalf("Join: err = %v, ingin ErrSoDViolation", err)
+	}
+	if _, err := f.organizations.Join(globex, user.ID, []string{"approver"}); err != nil {
+		t.Fatalf("Join organisasi lain: %v", err)
+	}
+
+	// Role global yang bertentangan dengan Role anggota di salah satu organisasi ditolak
+	if err := f.organizations.grants.CheckConflicts(user.ID, f.role("requester").ID); !errors.Is(err, ErrSoDViolation) {
+		t.Fatalf("CheckConflicts: err = %v, ingin ErrSoDViolation", err)
+	}
+}
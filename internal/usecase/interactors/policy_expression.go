@@ -0,0 +1,538 @@
+package interactors
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// policyRoots adalah akar atribut yang bisa dipakai di kondisi AccessPolicy.
+var policyRoots = map[string]bool{"subject": true, "resource": true, "action": true, "env": true}
+
+// policyComparisonOperators adalah operator perbandingan selain in.
+var policyComparisonOperators = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// policyExpression adalah kondisi AccessPolicy yang sudah di-parse. Nilai hasil evaluasi adalah string,
+// float64, bool, nil (atribut tidak ada atau null), []any atau map[string]any.
+type policyExpression interface {
+	eval(input map[string]any) (any, error)
+}
+
+// policyLiteral adalah nilai tetap: string, angka, true, false atau null.
+type policyLiteral struct {
+	value any
+}
+
+func (e policyLiteral) eval(map[string]any) (any, error) {
+	return e.value, nil
+}
+
+// policyAttribute adalah rujukan atribut, misalnya subject.department.
+type policyAttribute struct {
+	path []string
+}
+
+func (e policyAttribute) eval(input map[string]any) (any, error) {
+	var node any = input
+	for _, name := range e.path {
+		object, ok := node.(map[string]any)
+		if !ok {
+			return nil, nil
+		}
+		node = object[name]
+	}
+	return normalizePolicyValue(node), nil
+}
+
+// policyList adalah daftar nilai, misalnya [1, 2, 3] untuk operator in.
+type policyList struct {
+	items []policyExpression
+}
+
+func (e policyList) eval(input map[string]any) (any, error) {
+	values := make([]any, 0, len(e.items))
+	for _, item := range e.items {
+		value, err := item.eval(input)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, value)
+	}
+	return values, nil
+}
+
+// policyNot membalik nilai boolean.
+type policyNot struct {
+	inner policyExpression
+}
+
+func (e policyNot) eval(input map[string]any) (any, error) {
+	value, err := e.inner.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	truth, err := policyTruth(value)
+	if err != nil {
+		return nil, err
+	}
+	return !truth, nil
+}
+
+// policyLogical menggabungkan dua kondisi dengan && atau ||, dievaluasi secara short-circuit.
+type policyLogical struct {
+	or          bool
+	left, right policyExpression
+}
+
+func (e policyLogical) eval(input map[string]any) (any, error) {
+	value, err := e.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	left, err := policyTruth(value)
+	if err != nil {
+		return nil, err
+	}
+	if left == e.or {
+		return left, nil
+	}
+
+	value, err = e.right.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	return policyTruth(value)
+}
+
+// policyComparison membandingkan dua nilai dengan ==, !=, <, <=, >, >= atau in.
+// Perbandingan dengan atribut yang tidak ada selalu bernilai false, kecuali dibandingkan dengan literal
+// null, sehingga kebijakan allow tidak terpenuhi hanya karena kedua sisi sama-sama kosong.
+type policyComparison struct {
+	op          string
+	left, right policyExpression
+}
+
+func (e policyComparison) eval(input map[string]any) (any, error) {
+	left, err := e.left.eval(input)
+	if err != nil {
+		return nil, err
+	}
+	right, err := e.right.eval(input)
+	if err != nil {
+		return nil, err
+	}
+
+	if left == nil || right == nil {
+		if (e.op == "==" || e.op == "!=") && (isPolicyNull(e.left) || isPolicyNull(e.right)) {
+			return (left == nil && right == nil) == (e.op == "=="), nil
+		}
+		return false, nil
+	}
+
+	switch e.op {
+	case "==":
+		return policyEqual(left, right), nil
+	case "!=":
+		return !policyEqual(left, right), nil
+	case "in":
+		switch container := right.(type) {
+		case []any:
+			for _, item := range container {
+				if policyEqual(left, item) {
+					return true, nil
+				}
+			}
+			return false, nil
+		case string:
+			text, ok := left.(string)
+			if !ok {
+				return nil, fmt.Errorf("%w: operand kiri in harus string jika operand kanan string", ErrPolicyEvaluation)
+			}
+			return strings.Contains(container, text), nil
+		default:
+			return nil, fmt.Errorf("%w: operand kanan in harus daftar atau string", ErrPolicyEvaluation)
+		}
+	}
+
+	order, err := policyOrder(left, right)
+	if err != nil {
+		return nil, err
+	}
+	switch e.op {
+	case "<":
+		return order < 0, nil
+	case "<=":
+		return order <= 0, nil
+	case ">":
+		return order > 0, nil
+	default:
+		return order >= 0, nil
+	}
+}
+
+// evaluatePolicyCondition mengevaluasi kondisi terhadap atribut input. Kondisi kosong (nil) selalu terpenuhi.
+func evaluatePolicyCondition(expression policyExpression, input map[string]any) (bool, error) {
+	if expression == nil {
+		return true, nil
+	}
+	value, err := expression.eval(input)
+	if err != nil {
+		return false, err
+	}
+	return policyTruth(value)
+}
+
+// policyTruth mengubah nilai menjadi boolean. Atribut yang tidak ada dianggap false.
+func policyTruth(value any) (bool, error) {
+	switch value := value.(type) {
+	case bool:
+		return value, nil
+	case nil:
+		return false, nil
+	default:
+		return false, fmt.Errorf("%w: nilai %v bukan boolean", ErrPolicyEvaluation, value)
+	}
+}
+
+func isPolicyNull(expression policyExpression) bool {
+	literal, ok := expression.(policyLiteral)
+	return ok && literal.value == nil
+}
+
+func policyEqual(left, right any) bool {
+	switch left := left.(type) {
+	case string:
+		right, ok := right.(string)
+		return ok && left == right
+	case float64:
+		right, ok := right.(float64)
+		return ok && left == right
+	case bool:
+		right, ok := right.(bool)
+		return ok && left == right
+	default:
+		return false // Daftar dan objek tidak bisa dibandingkan langsung
+	}
+}
+
+// policyOrder membandingkan dua angka atau dua string (leksikografis, sehingga "09:00" < "17:00" dan
+// waktu RFC 3339 bisa dibandingkan).
+func policyOrder(left, right any) (int, error) {
+	switch left := left.(type) {
+	case float64:
+		if right, ok := right.(float64); ok {
+			switch {
+			case left < right:
+				return -1, nil
+			case left > right:
+				return 1, nil
+			}
+			return 0, nil
+		}
+	case string:
+		if right, ok := right.(string); ok {
+			return strings.Compare(left, right), nil
+		}
+	}
+	return 0, fmt.Errorf("%w: %v dan %v tidak bisa dibandingkan", ErrPolicyEvaluation, left, right)
+}
+
+// normalizePolicyValue menyeragamkan tipe atribut dari Go dan JSON agar bisa dibandingkan.
+func normalizePolicyValue(value any) any {
+	switch value := value.(type) {
+	case int:
+		return float64(value)
+	case int64:
+		return float64(value)
+	case int32:
+		return float64(value)
+	case float32:
+		return float64(value)
+	case []string:
+		items := make([]any, 0, len(value))
+		for _, item := range value {
+			items = append(items, item)
+		}
+		return items
+	case []any:
+		items := make([]any, 0, len(value))
+		for _, item := range value {
+			items = append(items, normalizePolicyValue(item))
+		}
+		return items
+	}
+	return value
+}
+
+// parsePolicyCondition mem-parse teks kondisi AccessPolicy. Kondisi kosong menghasilkan nil.
+func parsePolicyCondition(text string) (policyExpression, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	tokens, err := policyTokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &policyParser{tokens: tokens}
+	expression, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("%w: token %q tidak diharapkan", ErrPolicyConditionInvalid, p.peek().text)
+	}
+	return expression, nil
+}
+
+// Jenis token kondisi.
+const (
+	policyTokenWord = iota
+	policyTokenString
+	policyTokenNumber
+	policyTokenSymbol
+)
+
+// policyToken adalah satu token kondisi; literal string sudah di-decode.
+type policyToken struct {
+	kind int
+	text string
+}
+
+// policyTokenize memecah kondisi menjadi kata, angka, literal string, operator dan tanda baca.
+func policyTokenize(text string) ([]policyToken, error) {
+	var tokens []policyToken
+	for pos := 0; pos < len(text); {
+		char := text[pos]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			pos++
+		case strings.HasPrefix(text[pos:], "==") || strings.HasPrefix(text[pos:], "!=") ||
+			strings.HasPrefix(text[pos:], "<=") || strings.HasPrefix(text[pos:], ">=") ||
+			strings.HasPrefix(text[pos:], "&&") || strings.HasPrefix(text[pos:], "||"):
+			tokens = append(tokens, policyToken{kind: policyTokenSymbol, text: text[pos : pos+2]})
+			pos += 2
+		case strings.IndexByte("()[],<>!", char) >= 0:
+			tokens = append(tokens, policyToken{kind: policyTokenSymbol, text: string(char)})
+			pos++
+		case char == '"' || char == '\'':
+			var value strings.Builder
+			end := pos + 1
+			for end < len(text) && text[end] != char {
+				if text[end] == '\\' && end+1 < len(text) {
+					end++
+				}
+				value.WriteByte(text[end])
+				end++
+			}
+			if end >= len(text) {
+				return nil, fmt.Errorf("%w: string tidak ditutup", ErrPolicyConditionInvalid)
+			}
+			tokens = append(tokens, policyToken{kind: policyTokenString, text: value.String()})
+			pos = end + 1
+		case isPolicyDigit(char) || (char == '-' && pos+1 < len(text) && isPolicyDigit(text[pos+1])):
+			end := pos + 1
+			for end < len(text) && (isPolicyDigit(text[end]) || text[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, policyToken{kind: policyTokenNumber, text: text[pos:end]})
+			pos = end
+		case char == '_' || unicode.IsLetter(rune(char)):
+			end := pos
+			for end < len(text) && (text[end] == '_' || text[end] == '.' || isPolicyDigit(text[end]) || unicode.IsLetter(rune(text[end]))) {
+				end++
+			}
+			tokens = append(tokens, policyToken{kind: policyTokenWord, text: text[pos:end]})
+			pos = end
+		default:
+			return nil, fmt.Errorf("%w: karakter %q tidak dikenal", ErrPolicyConditionInvalid, char)
+		}
+	}
+	return tokens, nil
+}
+
+func isPolicyDigit(char byte) bool {
+	return char >= '0' && char <= '9'
+}
+
+// policyParser adalah parser recursive descent untuk tata bahasa kondisi:
+//
+//	condition  = or
+//	or         = and *("||" and)
+//	and        = unary *("&&" unary)
+//	unary      = "!" unary / comparison
+//	comparison = operand [("==" / "!=" / "<" / "<=" / ">" / ">=" / "in") operand]
+//	operand    = "(" condition ")" / "[" [operand *("," operand)] "]" / string / number
+//	             / "true" / "false" / "null" / attribute
+//	attribute  = ("subject" / "resource" / "action" / "env") *("." name)
+type policyParser struct {
+	tokens []policyToken
+	pos    int
+}
+
+func (p *policyParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *policyParser) peek() policyToken {
+	if p.done() {
+		return policyToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+// symbol mengonsumsi token berikutnya jika berupa operator atau tanda baca tersebut.
+func (p *policyParser) symbol(text string) bool {
+	token := p.peek()
+	if p.done() || token.kind != policyTokenSymbol || token.text != text {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *policyParser) expect(text string) error {
+	if !p.symbol(text) {
+		return fmt.Errorf("%w: %q diharapkan", ErrPolicyConditionInvalid, text)
+	}
+	return nil
+}
+
+func (p *policyParser) parseOr() (policyExpression, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.symbol("||") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = policyLogical{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *policyParser) parseAnd() (policyExpression, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.symbol("&&") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = policyLogical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *policyParser) parseUnary() (policyExpression, error) {
+	if p.symbol("!") {
+		inner, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		return policyNot{inner: inner}, nil
+	}
+	return p.parseComparison()
+}
+
+func (p *policyParser) parseComparison() (policyExpression, error) {
+	left, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+
+	token := p.peek()
+	var op string
+	switch {
+	case p.done():
+		return left, nil
+	case token.kind == policyTokenWord && token.text == "in":
+		op = "in"
+	case token.kind == policyTokenSymbol && policyComparisonOperators[token.text]:
+		op = token.text
+	default:
+		return left, nil
+	}
+	p.pos++
+
+	right, err := p.parseOperand()
+	if err != nil {
+		return nil, err
+	}
+	return policyComparison{op: op, left: left, right: right}, nil
+}
+
+func (p *policyParser) parseOperand() (policyExpression, error) {
+	if p.symbol("(") {
+		inner, err := p.parseOr()
+		if err != nil {
+			return nil, err
+		}
+		if err := p.expect(")"); err != nil {
+			return nil, err
+		}
+		return inner, nil
+	}
+	if p.symbol("[") {
+		list := policyList{}
+		if p.symbol("]") {
+			return list, nil
+		}
+		for {
+			item, err := p.parseOperand()
+			if err != nil {
+				return nil, err
+			}
+			list.items = append(list.items, item)
+			if p.symbol("]") {
+				return list, nil
+			}
+			if err := p.expect(","); err != nil {
+				return nil, err
+			}
+		}
+	}
+
+	if p.done() {
+		return nil, fmt.Errorf("%w: kondisi tidak lengkap", ErrPolicyConditionInvalid)
+	}
+	token := p.tokens[p.pos]
+	p.pos++
+
+	switch token.kind {
+	case policyTokenString:
+		return policyLiteral{value: token.text}, nil
+	case policyTokenNumber:
+		number, err := strconv.ParseFloat(token.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("%w: angka %q tidak valid", ErrPolicyConditionInvalid, token.text)
+		}
+		return policyLiteral{value: number}, nil
+	case policyTokenWord:
+		switch token.text {
+		case "true":
+			return policyLiteral{value: true}, nil
+		case "false":
+			return policyLiteral{value: false}, nil
+		case "null":
+			return policyLiteral{value: nil}, nil
+		}
+		path := strings.Split(token.text, ".")
+		if !policyRoots[path[0]] {
+			return nil, fmt.Errorf("%w: atribut %q harus diawali subject, resource, action atau env", ErrPolicyConditionInvalid, token.text)
+		}
+		for _, name := range path {
+			if name == "" {
+				return nil, fmt.Errorf("%w: atribut %q tidak valid", ErrPolicyConditionInvalid, token.text)
+			}
+		}
+		return policyAttribute{path: path}, nil
+	default:
+		return nil, fmt.Errorf("%w: token %q tidak diharapkan", ErrPolicyConditionInvalid, token.text)
+	}
+}
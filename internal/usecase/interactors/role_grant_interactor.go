@@ -0,0 +1,388 @@
+package interactors
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrRoleGrantNotFound dikembalikan jika Role tidak sedang ditetapkan ke pengguna.
+	ErrRoleGrantNotFound = errors.New("penetapan role tidak ditemukan")
+	// ErrRoleGrantUserNotFound dikembalikan jika pengguna penerima Role tidak ada.
+	ErrRoleGrantUserNotFound = errors.New("pengguna tidak ditemukan")
+	// ErrRoleGrantRoleNotFound dikembalikan jika Role yang akan ditetapkan tidak terdaftar.
+	ErrRoleGrantRoleNotFound = errors.New("role tidak ditemukan")
+	// ErrRoleGrantWindowInvalid dikembalikan jika waktu berakhir sudah lewat, tidak setelah waktu mulai,
+	// atau diisi bersamaan dengan durasi jam.
+	ErrRoleGrantWindowInvalid = errors.New("jendela waktu penetapan role tidak valid")
+	// ErrRoleGrantApprovalRequired dikembalikan jika Role punya kebijakan persetujuan sehingga tidak bisa
+	// diberikan langsung.
+	ErrRoleGrantApprovalRequired = errors.New("penetapan role ini memerlukan persetujuan")
+)
+
+// Jenis event penetapan Role langsung ke pengguna.
+const (
+	EventRoleGrantGranted = "role_grant.granted"
+	EventRoleGrantRevoked = "role_grant.revoked"
+	EventRoleGrantExpired = "role_grant.expired"
+)
+
+// RoleGrantInput adalah isian untuk menetapkan Role ke pengguna. Tanpa ExpiresAt dan Hours penetapan
+// bersifat permanen; Hours menghitung ExpiresAt dari StartsAt, atau dari sekarang jika StartsAt kosong.
+type RoleGrantInput struct {
+	Role      string
+	StartsAt  *time.Time
+	ExpiresAt *time.Time
+	Hours     int
+	Reason    string
+}
+
+// RoleGrantInteractor adalah use case untuk penetapan Role langsung ke pengguna, termasuk penetapan
+// sementara yang berakhir otomatis. Role yang punya ApprovalPolicy hanya bisa diberikan lewat
+// ApprovalInteractor setelah kebijakannya terpenuhi, dan tidak ada penetapan yang boleh melanggar
+// aturan pemisahan tugas.
+type RoleGrantInteractor struct {
+	roleRepo   repositories.RoleRepository
+	userRepo   repositories.UserRepository
+	policyRepo repositories.ApprovalPolicyRepository
+	sod        *SoDInteractor
+	audit      *AuditInteractor
+	events     services.EventPublisher
+}
+
+// NewRoleGrantInteractor membuat instance baru dari RoleGrantInteractor.
+func NewRoleGrantInteractor(
+	rr repositories.RoleRepository,
+	ur repositories.UserRepository,
+	pr repositories.ApprovalPolicyRepository,
+	sod *SoDInteractor,
+	audit *AuditInteractor,
+	events services.EventPublisher,
+) *RoleGrantInteractor {
+	return &RoleGrantInteractor{roleRepo: rr, userRepo: ur, policyRepo: pr, sod: sod, audit: audit, events: events}
+}
+
+// List mengembalikan semua penetapan Role langsung ke pengguna, termasuk yang belum mulai berlaku.
+func (i *RoleGrantInteractor) List(userID uuid.UUID) ([]entities.UserRole, error) {
+	if err := i.ensureUser(userID); err != nil {
+		return nil, err
+	}
+	return i.roleRepo.FindGrants(userID)
+}
+
+// Grant menetapkan Role ke pengguna atas nama grantedBy. Penetapan yang sudah ada diganti jendela waktu
+// dan alasannya, sehingga Grant juga dipakai untuk memperpanjang atau menjadikan penetapan permanen.
+// Gagal dengan ErrRoleGrantApprovalRequired jika Role punya kebijakan persetujuan.
+func (i *RoleGrantInteractor) Grant(userID uuid.UUID, input RoleGrantInput, grantedBy uuid.UUID) (*entities.UserRole, error) {
+	if err := resolveGrantWindow(&input, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := i.ensureUser(userID); err != nil {
+		return nil, err
+	}
+	role, err := i.findRole(input.Role)
+	if err != nil {
+		return nil, err
+	}
+
+	required, err := i.RequiresApproval(role.ID)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return nil, ErrRoleGrantApprovalRequired
+	}
+	return i.apply(userID, role, input, grantedBy)
+}
+
+// RequiresApproval mengembalikan true jika Role, atau salah satu Role yang diwarisinya, punya kebijakan
+// persetujuan.
+func (i *RoleGrantInteractor) RequiresApproval(roleID uuid.UUID) (bool, error) {
+	gate, err := i.gate()
+	if err != nil {
+		return false, err
+	}
+	return gate.policy(roleID) != nil, nil
+}
+
+// CheckNoApproval memastikan tidak ada Role roles, termasuk lewat Role yang diwarisinya, yang punya
+// kebijakan persetujuan. Dipakai jalur yang memberi Role tanpa penetapan langsung, seperti grup dan SCIM,
+// sehingga tidak bisa diteruskan ke ApprovalInteractor. Gagal dengan ErrRoleGrantApprovalRequired beserta
+// nama Role-nya.
+func (i *RoleGrantInteractor) CheckNoApproval(roles []*entities.Role) error {
+	if len(roles) == 0 {
+		return nil
+	}
+	gate, err := i.gate()
+	if err != nil {
+		return err
+	}
+	for _, role := range roles {
+		if gate.policy(role.ID) != nil {
+			return fmt.Errorf("%w: %s", ErrRoleGrantApprovalRequired, role.Name)
+		}
+	}
+	return nil
+}
+
+// CheckRoleParents menolak Role roleID mewarisi parentIDs jika pewarisan itu membuatnya memerlukan
+// persetujuan padahal Role tersebut sudah dipegang pengguna, termasuk lewat grup, keanggotaan organisasi
+// dan Role yang mewarisinya, yang menerimanya tanpa persetujuan.
+func (i *RoleGrantInteractor) CheckRoleParents(roleID uuid.UUID, parentIDs []uuid.UUID) error {
+	gate, err := i.gate()
+	if err != nil || len(gate.policies) == 0 || gate.policy(roleID) != nil {
+		return err
+	}
+	var gated *entities.Role
+	for _, id := range parentIDs {
+		if gate.policy(id) != nil {
+			gated = gate.graph[id]
+			break
+		}
+	}
+	if gated == nil {
+		return nil
+	}
+
+	assignments, err := i.roleRepo.FindAllAssignments()
+	if err != nil {
+		return err
+	}
+	for _, holdings := range holdingsByUser(assignments) {
+		for _, held := range holdings.contexts(gate.graph, uuid.Nil) {
+			if held[roleID] {
+				return fmt.Errorf("%w: %s", ErrRoleParentApprovalRequired, gated.Name)
+			}
+		}
+	}
+	return nil
+}
+
+// CheckConflicts memastikan penetapan Role roleIDs ke pengguna tidak melanggar aturan pemisahan tugas.
+func (i *RoleGrantInteractor) CheckConflicts(userID uuid.UUID, roleIDs ...uuid.UUID) error {
+	return i.sod.CheckUser(userID, roleIDs)
+}
+
+// CheckMemberConflicts memastikan pemberian Role roles ke pengguna sebagai anggota organisasi
+// organizationID tidak melanggar aturan pemisahan tugas.
+func (i *RoleGrantInteractor) CheckMemberConflicts(organizationID, userID uuid.UUID, roles []*entities.Role) error {
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	return i.sod.CheckMember(organizationID, userID, roleIDs)
+}
+
+// approvalPolicy mengembalikan kebijakan persetujuan yang berlaku untuk Role roleID (lihat
+// approvalGate.policy). Gagal dengan gorm.ErrRecordNotFound jika Role dan semua Role warisannya tidak
+// punya kebijakan.
+func (i *RoleGrantInteractor) approvalPolicy(roleID uuid.UUID) (*entities.ApprovalPolicy, error) {
+	gate, err := i.gate()
+	if err != nil {
+		return nil, err
+	}
+	policy := gate.policy(roleID)
+	if policy == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+	return policy, nil
+}
+
+// gate memuat kebijakan persetujuan semua Role. Graf pewarisan hanya dimuat jika ada Role yang punya kebijakan.
+func (i *RoleGrantInteractor) gate() (approvalGate, error) {
+	policies, err := i.policyRepo.FindAll()
+	if err != nil {
+		return approvalGate{}, err
+	}
+	gate := approvalGate{policies: make(map[uuid.UUID]*entities.ApprovalPolicy, len(policies))}
+	for n := range policies {
+		if policies[n].RoleID != nil {
+			gate.policies[*policies[n].RoleID] = &policies[n]
+		}
+	}
+	if len(gate.policies) == 0 {
+		return gate, nil
+	}
+
+	roles, err := i.roleRepo.FindGraph()
+	if err != nil {
+		return approvalGate{}, err
+	}
+	gate.graph = newRoleGraph(roles)
+	return gate, nil
+}
+
+// approvalGate adalah kebijakan persetujuan per Role beserta graf pewarisan Role, sehingga Role yang
+// mewarisi Role berkebijakan ikut memerlukan persetujuan.
+type approvalGate struct {
+	policies map[uuid.UUID]*entities.ApprovalPolicy
+	graph    roleGraph
+}
+
+// policy mengembalikan kebijakan yang berlaku untuk Role roleID: kebijakan Role itu sendiri, atau jika
+// tidak ada, kebijakan Role warisannya dengan jumlah persetujuan terbanyak (terdekat jika sama). Nil jika
+// Role dan semua Role warisannya tidak punya kebijakan.
+func (g approvalGate) policy(roleID uuid.UUID) *entities.ApprovalPolicy {
+	if policy, ok := g.policies[roleID]; ok {
+		return policy
+	}
+	var strictest *entities.ApprovalPolicy
+	for _, ancestor := range g.graph.ancestors(roleID) {
+		policy, ok := g.policies[ancestor.ID]
+		if ok && (strictest == nil || policy.RequiredApprovals > strictest.RequiredApprovals) {
+			strictest = policy
+		}
+	}
+	return strictest
+}
+
+// apply menetapkan Role tanpa memeriksa kebijakan persetujuan. Dipakai Grant dan ApprovalInteractor
+// setelah kebijakannya terpenuhi.
+func (i *RoleGrantInteractor) apply(userID uuid.UUID, role *entities.Role, input RoleGrantInput, grantedBy uuid.UUID) (*entities.UserRole, error) {
+	now := time.Now()
+	if err := resolveGrantWindow(&input, now); err != nil {
+		return nil, err
+	}
+	if err := i.CheckConflicts(userID, role.ID); err != nil {
+		return nil, err
+	}
+
+	grant := &entities.UserRole{
+		UserID:    userID,
+		RoleID:    role.ID,
+		StartsAt:  input.StartsAt,
+		ExpiresAt: input.ExpiresAt,
+		Reason:    strings.TrimSpace(input.Reason),
+		GrantedBy: &grantedBy,
+		GrantedAt: &now,
+	}
+	if err := i.roleRepo.Grant(grant); err != nil {
+		return nil, err
+	}
+	grant.Role = role
+
+	i.publish(EventRoleGrantGranted, grant, now, grantedBy, nil)
+	return grant, nil
+}
+
+// Revoke mencabut Role dari pengguna atas nama revokedBy sebelum waktunya berakhir.
+func (i *RoleGrantInteractor) Revoke(userID, roleID, revokedBy uuid.UUID) error {
+	grant, err := i.roleRepo.FindGrant(userID, roleID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleGrantNotFound
+		}
+		return err
+	}
+	if err := i.roleRepo.Revoke(userID, roleID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleGrantNotFound
+		}
+		return err
+	}
+
+	i.publish(EventRoleGrantRevoked, grant, time.Now(), revokedBy, map[string]any{"revoked_by": revokedBy})
+	return nil
+}
+
+// ExpireDue menghapus penetapan Role yang sudah lewat waktu berakhirnya pada now dan menerbitkan event
+// untuk setiap penetapan yang dihapus. Dijalankan berkala oleh worker.
+func (i *RoleGrantInteractor) ExpireDue(now time.Time) (int, error) {
+	grants, err := i.roleRepo.FindExpiredGrants(now)
+	if err != nil {
+		return 0, err
+	}
+
+	expired := 0
+	for n := range grants {
+		grant := &grants[n]
+		deleted, err := i.roleRepo.DeleteExpiredGrant(grant.UserID, grant.RoleID, now)
+		if err != nil {
+			return expired, err
+		}
+		if !deleted {
+			continue // Sudah dicabut atau diperpanjang sejak dibaca
+		}
+		expired++
+		i.publish(EventRoleGrantExpired, grant, now, uuid.Nil, nil)
+	}
+	return expired, nil
+}
+
+func (i *RoleGrantInteractor) findRole(name string) (*entities.Role, error) {
+	role, err := i.roleRepo.FindByName(strings.TrimSpace(name))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleGrantRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (i *RoleGrantInteractor) ensureUser(userID uuid.UUID) error {
+	if _, err := i.userRepo.FindByID(userID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleGrantUserNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// publish mencatat perubahan penetapan Role ke jejak audit atas nama actorID dan menerbitkan event-nya.
+// Kegagalan penerbitan hanya dicatat karena perubahan sudah tersimpan.
+func (i *RoleGrantInteractor) publish(eventType string, grant *entities.UserRole, now time.Time, actorID uuid.UUID, extra map[string]any) {
+	data := map[string]any{
+		"user_id":    grant.UserID,
+		"role_id":    grant.RoleID,
+		"starts_at":  grant.StartsAt,
+		"expires_at": grant.ExpiresAt,
+		"reason":     grant.Reason,
+		"granted_by": grant.GrantedBy,
+	}
+	if grant.Role != nil {
+		data["role"] = grant.Role.Name
+	}
+	for key, value := range extra {
+		data[key] = value
+	}
+
+	i.audit.Record(actorID, eventType, AuditTargetUser, grant.UserID.String(), data)
+	if err := i.events.Publish(services.Event{Type: eventType, OccurredAt: now, Data: data}); err != nil {
+		log.Printf("Gagal menerbitkan event %s untuk pengguna %s: %v", eventType, grant.UserID, err)
+	}
+}
+
+// resolveGrantWindow mengubah Hours menjadi ExpiresAt (dihitung dari StartsAt atau now) lalu memastikan
+// penetapan berakhir setelah now dan setelah waktu mulainya.
+func resolveGrantWindow(input *RoleGrantInput, now time.Time) error {
+	if input.Hours != 0 {
+		if input.ExpiresAt != nil || input.Hours < 0 {
+			return ErrRoleGrantWindowInvalid
+		}
+		start := now
+		if input.StartsAt != nil {
+			start = *input.StartsAt
+		}
+		expiresAt := start.Add(time.Duration(input.Hours) * time.Hour)
+		input.ExpiresAt = &expiresAt
+		input.Hours = 0
+	}
+	if input.ExpiresAt != nil {
+		if !input.ExpiresAt.After(now) || (input.StartsAt != nil && !input.ExpiresAt.After(*input.StartsAt)) {
+			return ErrRoleGrantWindowInvalid
+		}
+	}
+	return nil
+}
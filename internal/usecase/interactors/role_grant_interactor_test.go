@@ -0,0 +1,113 @@
+package interactors
+
+import (
+	"errors"
+	"testing"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+)
+
+func TestRequiresApprovalFollowsInheritance(t *testing.T) {
+	// treasury memerlukan persetujuan; payments mewarisinya dan cashier mewarisi payments
+	treasury := entities.Role{ID: uuid.New(), Name: "treasury"}
+	payments := entities.Role{ID: uuid.New(), Name: "payments", Parents: []*entities.Role{&treasury}}
+	cashier := entities.Role{ID: uuid.New(), Name: "cashier", Parents: []*entities.Role{&payments}}
+	viewer := entities.Role{ID: uuid.New(), Name: "viewer"}
+	roleRepo := &graphRoleRepository{roles: []entities.Role{treasury, payments, cashier, viewer}}
+	grants := NewRoleGrantInteractor(roleRepo, nil, &staticApprovalPolicyRepository{roles: []uuid.UUID{treasury.ID}}, nil, nil, nil)
+
+	tests := []struct {
+		role entities.Role
+		want bool
+	}{
+		{treasury, true},
+		{payments, true},
+		{cashier, true},
+		{viewer, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.role.Name, func(t *testing.T) {
+			required, err := grants.RequiresApproval(tt.role.ID)
+			if err != nil {
+				t.Fatalf("RequiresApproval: %v", err)
+			}
+			if required != tt.want {
+				t.Fatalf("RequiresApproval = %v, ingin %v", required, tt.want)
+			}
+			err = grants.CheckNoApproval([]*entities.Role{&tt.role})
+			if got := errors.Is(err, ErrRoleGrantApprovalRequired); got != tt.want {
+				t.Fatalf("CheckNoApproval: err = %v, ingin ditolak %v", err, tt.want)
+			}
+		})
+	}
+
+	// Role tanpa kebijakan sendiri diajukan dengan kebijakan Role yang diwarisinya
+	policy, err := grants.approvalPolicy(cashier.ID)
+	if err != nil {
+		t.Fatalf("approvalPolicy: %v", err)
+	}
+	if policy.RoleID == nil || *policy.RoleID != treasury.ID {
+		t.Fatalf("kebijakan cashier = %+v, ingin kebijakan treasury", policy)
+	}
+}
+
+func TestSetParentsRefusesApprovalParentWithHolders(t *testing.T) {
+	// treasury memerlukan persetujuan dan akan diwarisi payments; cashier sudah mewarisi payments
+	treasury := entities.Role{ID: uuid.New(), Name: "treasury"}
+	payments := entities.Role{ID: uuid.New(), Name: "payments"}
+	cashier := entities.Role{ID: uuid.New(), Name: "cashier", Parents: []*entities.Role{&payments}}
+	viewer := entities.Role{ID: uuid.New(), Name: "viewer"}
+	organizationID := uuid.New()
+
+	tests := []struct {
+		name       string
+		assignment *repositories.RoleAssignment
+		gated      []uuid.UUID // Role yang punya kebijakan persetujuan selain treasury
+		wantErr    bool
+	}{
+		{name: "tanpa pemegang"},
+		{name: "pemegang langsung", assignment: &repositories.RoleAssignment{RoleID: payments.ID}, wantErr: true},
+		{name: "pemegang lewat pewarisan", assignment: &repositories.RoleAssignment{RoleID: cashier.ID}, wantErr: true},
+		{
+			name:       "pemegang sebagai anggota organisasi",
+			assignment: &repositories.RoleAssignment{RoleID: payments.ID, OrganizationID: &organizationID},
+			wantErr:    true,
+		},
+		{name: "pemegang role lain", assignment: &repositories.RoleAssignment{RoleID: viewer.ID}},
+		{
+			name:       "role sudah memerlukan persetujuan",
+			assignment: &repositories.RoleAssignment{RoleID: payments.ID},
+			gated:      []uuid.UUID{payments.ID},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			roleRepo := &graphRoleRepository{roles: []entities.Role{treasury, payments, cashier, viewer}}
+			if tt.assignment != nil {
+				assignment := *tt.assignment
+				assignment.UserID = uuid.New()
+				roleRepo.assignments = []repositories.RoleAssignment{assignment}
+			}
+			policies := &staticApprovalPolicyRepository{roles: append([]uuid.UUID{treasury.ID}, tt.gated...)}
+			sod := NewSoDInteractor(&staticSoDRuleRepository{}, roleRepo, nil, nil, nil)
+			grants := NewRoleGrantInteractor(roleRepo, nil, policies, sod, nil, nil)
+			interactor := NewRoleInteractor(roleRepo, nil, nil, sod, grants)
+
+			if !tt.wantErr {
+				if err := grants.CheckRoleParents(payments.ID, []uuid.UUID{treasury.ID}); err != nil {
+					t.Fatalf("CheckRoleParents: %v", err)
+				}
+				return
+			}
+			if _, err := interactor.SetParents(payments.ID, []string{"treasury"}); !errors.Is(err, ErrRoleParentApprovalRequired) {
+				t.Fatalf("err = %v, ingin ErrRoleParentApprovalRequired", err)
+			}
+			if roleRepo.replaced {
+				t.Fatal("induk role tetap diganti meskipun pemegangnya belum disetujui")
+			}
+		})
+	}
+}
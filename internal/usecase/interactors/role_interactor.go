@@ -0,0 +1,436 @@
+package interactors
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrRoleNotFound dikembalikan jika role tidak ada.
+	ErrRoleNotFound = errors.New("role tidak ditemukan")
+	// ErrRoleNameRequired dikembalikan jika nama role kosong.
+	ErrRoleNameRequired = errors.New("nama role wajib diisi")
+	// ErrRoleNameTaken dikembalikan jika nama role sudah dipakai role lain.
+	ErrRoleNameTaken = errors.New("nama role sudah dipakai")
+	// ErrRoleCycle dikembalikan jika pewarisan role akan membuat role mewarisi dirinya sendiri.
+	ErrRoleCycle = errors.New("role tidak boleh mewarisi dirinya sendiri, langsung maupun bertingkat")
+	// ErrRoleParentNotFound dikembalikan jika salah satu role induk yang diminta tidak terdaftar.
+	ErrRoleParentNotFound = errors.New("role induk tidak ditemukan")
+	// ErrRoleParentApprovalRequired dikembalikan jika role induk baru memerlukan persetujuan sedangkan role
+	// tersebut sudah dipegang pengguna tanpa persetujuan.
+	ErrRoleParentApprovalRequired = errors.New("role induk memerlukan persetujuan sedangkan role ini sudah dipegang pengguna")
+	// ErrPermissionNameInvalid dikembalikan jika nama permission tidak sesuai tata bahasa resource:aksi.
+	ErrPermissionNameInvalid = errors.New("nama permission tidak valid, gunakan format [!]resource:aksi")
+	// ErrRoleOwnerNotFound dikembalikan jika pengguna yang ditunjuk sebagai pemilik role tidak terdaftar.
+	ErrRoleOwnerNotFound = errors.New("pemilik role tidak ditemukan")
+)
+
+// InheritedPermission adalah permission yang dimiliki role karena diwarisi dari role lain.
+type InheritedPermission struct {
+	Name string `json:"name"`
+	From string `json:"from"` // Role terdekat yang memuat permission ini secara langsung
+}
+
+// RoleDetail adalah role beserta permission langsung dan permission warisannya.
+type RoleDetail struct {
+	ID                   uuid.UUID             `json:"id"`
+	Name                 string                `json:"name"`
+	Description          string                `json:"description"`
+	Parents              []string              `json:"parents"`
+	OwnerID              *uuid.UUID            `json:"owner_id,omitempty"`
+	SCIMManaged          bool                  `json:"scim_managed"`
+	TenantRole           bool                  `json:"tenant_role"`
+	DirectPermissions    []string              `json:"direct_permissions"`
+	InheritedPermissions []InheritedPermission `json:"inherited_permissions"`
+	CreatedAt            time.Time             `json:"created_at"`
+	UpdatedAt            time.Time             `json:"updated_at"`
+}
+
+// RoleInteractor adalah use case untuk pengelolaan role, permission-nya dan pewarisan antar role.
+type RoleInteractor struct {
+	roleRepo       repositories.RoleRepository
+	permissionRepo repositories.PermissionRepository
+	userRepo       repositories.UserRepository
+	sod            *SoDInteractor
+	grants         *RoleGrantInteractor
+}
+
+// NewRoleInteractor membuat instance baru dari RoleInteractor.
+func NewRoleInteractor(
+	rr repositories.RoleRepository,
+	pr repositories.PermissionRepository,
+	ur repositories.UserRepository,
+	sod *SoDInteractor,
+	grants *RoleGrantInteractor,
+) *RoleInteractor {
+	return &RoleInteractor{roleRepo: rr, permissionRepo: pr, userRepo: ur, sod: sod, grants: grants}
+}
+
+// Create membuat role baru tanpa permission dan tanpa induk.
+func (i *RoleInteractor) Create(name, description string) (*RoleDetail, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrRoleNameRequired
+	}
+	if err := i.checkNameAvailable(name, uuid.Nil); err != nil {
+		return nil, err
+	}
+
+	role := &entities.Role{Name: name, Description: description}
+	if err := i.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+	return i.Get(role.ID)
+}
+
+// List mengembalikan semua role beserta permission langsung dan warisannya.
+func (i *RoleInteractor) List() ([]RoleDetail, error) {
+	roles, err := i.roleRepo.FindGraph()
+	if err != nil {
+		return nil, err
+	}
+	graph := newRoleGraph(roles)
+
+	details := make([]RoleDetail, 0, len(roles))
+	for _, role := range roles {
+		details = append(details, *graph.detail(role.ID))
+	}
+	return details, nil
+}
+
+// Get mengembalikan role beserta permission langsung dan warisannya.
+func (i *RoleInteractor) Get(id uuid.UUID) (*RoleDetail, error) {
+	graph, err := i.graph()
+	if err != nil {
+		return nil, err
+	}
+	if _, ok := graph[id]; !ok {
+		return nil, ErrRoleNotFound
+	}
+	return graph.detail(id), nil
+}
+
+// Update mengganti nama dan deskripsi role.
+func (i *RoleInteractor) Update(id uuid.UUID, name, description string) (*RoleDetail, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrRoleNameRequired
+	}
+
+	role, err := i.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.checkNameAvailable(name, id); err != nil {
+		return nil, err
+	}
+
+	role.Name = name
+	role.Description = description
+	if err := i.roleRepo.Update(role); err != nil {
+		return nil, err
+	}
+	return i.Get(id)
+}
+
+// Delete menghapus role. Role yang mewarisinya kehilangan permission warisan dari role ini.
+func (i *RoleInteractor) Delete(id uuid.UUID) error {
+	if err := i.roleRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// SetPermissions mengganti seluruh permission langsung role. Permission yang belum terdaftar dibuat.
+// Nama boleh berupa wildcard, dibatasi resource, atau deny eksplisit (lihat entities.ParsePermission).
+func (i *RoleInteractor) SetPermissions(id uuid.UUID, names []string) (*RoleDetail, error) {
+	if _, err := i.find(id); err != nil {
+		return nil, err
+	}
+
+	names = normalizeScopes(names)
+	for _, name := range names {
+		if _, ok := entities.ParsePermission(name); !ok {
+			return nil, fmt.Errorf("%w: %s", ErrPermissionNameInvalid, name)
+		}
+	}
+
+	permissions, err := i.permissionRepo.EnsureNames(names)
+	if err != nil {
+		return nil, err
+	}
+	permissionIDs := make([]uuid.UUID, 0, len(permissions))
+	for _, permission := range permissions {
+		permissionIDs = append(permissionIDs, permission.ID)
+	}
+	if err := i.roleRepo.ReplacePermissions(id, permissionIDs); err != nil {
+		return nil, err
+	}
+	return i.Get(id)
+}
+
+// SetParents mengganti seluruh role yang diwarisi role. Ditolak jika salah satu induk adalah role itu
+// sendiri atau sudah (langsung maupun bertingkat) mewarisi role tersebut, jika pemegang role akan
+// melanggar aturan pemisahan tugas karena induk barunya, atau jika induk barunya memerlukan persetujuan
+// sedangkan role tersebut sudah punya pemegang.
+func (i *RoleInteractor) SetParents(id uuid.UUID, parentNames []string) (*RoleDetail, error) {
+	if _, err := i.find(id); err != nil {
+		return nil, err
+	}
+	parents, err := resolveRoles(i.roleRepo, parentNames, ErrRoleParentNotFound)
+	if err != nil {
+		return nil, err
+	}
+
+	graph, err := i.graph()
+	if err != nil {
+		return nil, err
+	}
+	parentIDs := make([]uuid.UUID, 0, len(parents))
+	for _, parent := range parents {
+		if parent.ID == id || graph.inherits(parent.ID, id) {
+			return nil, fmt.Errorf("%w: %s", ErrRoleCycle, parent.Name)
+		}
+		parentIDs = append(parentIDs, parent.ID)
+	}
+	if err := i.sod.CheckRoleParents(id, parentIDs); err != nil {
+		return nil, err
+	}
+	if err := i.grants.CheckRoleParents(id, parentIDs); err != nil {
+		return nil, err
+	}
+
+	if err := i.roleRepo.ReplaceParents(id, parentIDs); err != nil {
+		return nil, err
+	}
+	return i.Get(id)
+}
+
+// SetOwner menetapkan pengguna (username atau email) sebagai pemilik role. Pemilik meninjau penetapan
+// role ini dalam kampanye tinjauan akses. owner kosong menghapus pemiliknya.
+func (i *RoleInteractor) SetOwner(id uuid.UUID, owner string) (*RoleDetail, error) {
+	if _, err := i.find(id); err != nil {
+		return nil, err
+	}
+
+	var ownerID *uuid.UUID
+	if owner = strings.TrimSpace(owner); owner != "" {
+		user, err := i.userRepo.FindByUsernameOrEmail(owner)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return nil, ErrRoleOwnerNotFound
+			}
+			return nil, err
+		}
+		ownerID = &user.ID
+	}
+
+	if err := i.roleRepo.UpdateOwner(id, ownerID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return i.Get(id)
+}
+
+// SetSCIMManaged mengatur apakah role boleh dilihat dan diubah klien SCIM sebagai Group, termasuk
+// anggota, nama dan penghapusannya. Role yang dibuat lewat SCIM otomatis dikelola SCIM.
+func (i *RoleInteractor) SetSCIMManaged(id uuid.UUID, managed bool) (*RoleDetail, error) {
+	if err := i.roleRepo.UpdateSCIMManaged(id, managed); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return i.Get(id)
+}
+
+// SetTenantRole mengatur apakah role boleh diberikan ke anggota organisasi, termasuk lewat
+// undangan. Anggota yang sudah memegang role tidak terpengaruh.
+func (i *RoleInteractor) SetTenantRole(id uuid.UUID, tenant bool) (*RoleDetail, error) {
+	if err := i.roleRepo.UpdateTenantRole(id, tenant); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return i.Get(id)
+}
+
+func (i *RoleInteractor) find(id uuid.UUID) (*entities.Role, error) {
+	role, err := i.roleRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleNotFound
+		}
+		return nil, err
+	}
+	return role, nil
+}
+
+func (i *RoleInteractor) graph() (roleGraph, error) {
+	roles, err := i.roleRepo.FindGraph()
+	if err != nil {
+		return nil, err
+	}
+	return newRoleGraph(roles), nil
+}
+
+// checkNameAvailable memastikan nama role belum dipakai role lain selain except.
+func (i *RoleInteractor) checkNameAvailable(name string, except uuid.UUID) error {
+	existing, err := i.roleRepo.FindByName(name)
+	if err == nil && existing.ID != except {
+		return ErrRoleNameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// roleGraph adalah semua role yang diindeks berdasarkan ID, dengan Permissions dan Parents sudah dimuat.
+// Penelusuran pewarisan selalu menandai role yang sudah dikunjungi sehingga aman terhadap data bersiklus.
+type roleGraph map[uuid.UUID]*entities.Role
+
+func newRoleGraph(roles []entities.Role) roleGraph {
+	graph := make(roleGraph, len(roles))
+	for n := range roles {
+		graph[roles[n].ID] = &roles[n]
+	}
+	return graph
+}
+
+// ancestors mengembalikan semua role yang diwarisi role id, terdekat lebih dulu, tanpa role id sendiri.
+func (g roleGraph) ancestors(id uuid.UUID) []*entities.Role {
+	var ordered []*entities.Role
+	visited := map[uuid.UUID]bool{id: true}
+	queue := []uuid.UUID{id}
+	for len(queue) > 0 {
+		role := g[queue[0]]
+		queue = queue[1:]
+		if role == nil {
+			continue
+		}
+		for _, parent := range role.Parents {
+			if visited[parent.ID] || g[parent.ID] == nil {
+				continue
+			}
+			visited[parent.ID] = true
+			ordered = append(ordered, g[parent.ID])
+			queue = append(queue, parent.ID)
+		}
+	}
+	return ordered
+}
+
+// expand mengembalikan himpunan Role ids beserta semua Role yang diwarisinya.
+func (g roleGraph) expand(ids []uuid.UUID) map[uuid.UUID]bool {
+	expanded := make(map[uuid.UUID]bool, len(ids))
+	for _, id := range ids {
+		expanded[id] = true
+		for _, ancestor := range g.ancestors(id) {
+			expanded[ancestor.ID] = true
+		}
+	}
+	return expanded
+}
+
+// inherits mengembalikan true jika role id mewarisi role ancestor, langsung maupun bertingkat.
+func (g roleGraph) inherits(id, ancestor uuid.UUID) bool {
+	for _, role := range g.ancestors(id) {
+		if role.ID == ancestor {
+			return true
+		}
+	}
+	return false
+}
+
+// roleGrant adalah permission sebuah Role yang mencakup permission yang diperiksa.
+type roleGrant struct {
+	via     []string // Rantai Role induk sampai Role yang memuat permission; kosong berarti Role itu sendiri
+	matched string   // Nama permission yang cocok, bisa berupa wildcard atau deny
+	deny    bool
+}
+
+// grants mengembalikan semua permission role id dan role yang diwarisinya yang mencakup permission,
+// baik allow maupun deny, terdekat lebih dulu.
+func (g roleGraph) grants(id uuid.UUID, permission string) []roleGrant {
+	type step struct {
+		id   uuid.UUID
+		path []string
+	}
+	var grants []roleGrant
+	visited := map[uuid.UUID]bool{id: true}
+	queue := []step{{id: id}}
+	for len(queue) > 0 {
+		current := queue[0]
+		queue = queue[1:]
+		role := g[current.id]
+		if role == nil {
+			continue
+		}
+		for _, granted := range role.Permissions {
+			if entities.PermissionMatches(granted.Name, permission) {
+				pattern, _ := entities.ParsePermission(granted.Name)
+				grants = append(grants, roleGrant{via: current.path, matched: granted.Name, deny: pattern.Deny})
+			}
+		}
+		for _, parent := range role.Parents {
+			if visited[parent.ID] {
+				continue
+			}
+			visited[parent.ID] = true
+			queue = append(queue, step{id: parent.ID, path: append(append([]string{}, current.path...), parent.Name)})
+		}
+	}
+	return grants
+}
+
+// detail menyusun RoleDetail untuk role id yang ada di graph.
+func (g roleGraph) detail(id uuid.UUID) *RoleDetail {
+	role := g[id]
+	detail := &RoleDetail{
+		ID:                   role.ID,
+		Name:                 role.Name,
+		Description:          role.Description,
+		OwnerID:              role.OwnerID,
+		SCIMManaged:          role.SCIMManaged,
+		TenantRole:           role.TenantRole,
+		Parents:              make([]string, 0, len(role.Parents)),
+		DirectPermissions:    make([]string, 0, len(role.Permissions)),
+		InheritedPermissions: []InheritedPermission{},
+		CreatedAt:            role.CreatedAt,
+		UpdatedAt:            role.UpdatedAt,
+	}
+	for _, parent := range role.Parents {
+		detail.Parents = append(detail.Parents, parent.Name)
+	}
+
+	seen := make(map[string]bool, len(role.Permissions))
+	for _, permission := range role.Permissions {
+		seen[permission.Name] = true
+		detail.DirectPermissions = append(detail.DirectPermissions, permission.Name)
+	}
+	for _, ancestor := range g.ancestors(id) {
+		for _, permission := range ancestor.Permissions {
+			if seen[permission.Name] {
+				continue
+			}
+			seen[permission.Name] = true
+			detail.InheritedPermissions = append(detail.InheritedPermissions, InheritedPermission{Name: permission.Name, From: ancestor.Name})
+		}
+	}
+	return detail
+}
@@ -0,0 +1,122 @@
+package interactors
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// hierarchyRoleRepository adalah graphRoleRepository yang benar-benar mengganti induk Role.
+type hierarchyRoleRepository struct {
+	*graphRoleRepository
+}
+
+func (r *hierarchyRoleRepository) ReplaceParents(roleID uuid.UUID, parentIDs []uuid.UUID) error {
+	var parents []*entities.Role
+	for _, id := range parentIDs {
+		parent, err := r.FindByID(id)
+		if err != nil {
+			return err
+		}
+		parents = append(parents, parent)
+	}
+	role, err := r.FindByID(roleID)
+	if err != nil {
+		return err
+	}
+	role.Parents = parents
+	r.replaced = true
+	return nil
+}
+
+func newHierarchyRoleInteractor(roles ...entities.Role) (*RoleInteractor, *hierarchyRoleRepository) {
+	roleRepo := &hierarchyRoleRepository{graphRoleRepository: &graphRoleRepository{roles: roles}}
+	sod := NewSoDInteractor(&staticSoDRuleRepository{}, roleRepo, nil, nil, nil)
+	grants := NewRoleGrantInteractor(roleRepo, nil, &staticApprovalPolicyRepository{}, sod, nil, nil)
+	return NewRoleInteractor(roleRepo, nil, nil, sod, grants), roleRepo
+}
+
+func TestSetParentsRejectsCycles(t *testing.T) {
+	// admin mewarisi editor, editor mewarisi viewer.
+	viewer := entities.Role{ID: uuid.New(), Name: "viewer"}
+	editor := entities.Role{ID: uuid.New(), Name: "editor", Parents: []*entities.Role{&viewer}}
+	admin := entities.Role{ID: uuid.New(), Name: "admin", Parents: []*entities.Role{&editor}}
+	auditor := entities.Role{ID: uuid.New(), Name: "auditor"}
+
+	tests := []struct {
+		name    string
+		role    uuid.UUID
+		parents []string
+		wantErr error
+	}{
+		{"mewarisi dirinya", editor.ID, []string{"editor"}, ErrRoleCycle},
+		{"induk mewarisi role langsung", viewer.ID, []string{"editor"}, ErrRoleCycle},
+		{"induk mewarisi role bertingkat", viewer.ID, []string{"auditor", "admin"}, ErrRoleCycle},
+		{"induk tidak ada", viewer.ID, []string{"ghost"}, ErrRoleParentNotFound},
+		{"role tidak ada", uuid.New(), []string{"viewer"}, ErrRoleNotFound},
+		{"tanpa siklus", viewer.ID, []string{"auditor"}, nil},
+		{"induk yang sama dengan induk induknya", admin.ID, []string{"editor", "viewer"}, nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			interactor, roleRepo := newHierarchyRoleInteractor(viewer, editor, admin, auditor)
+			detail, err := interactor.SetParents(tt.role, tt.parents)
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, ingin %v", err, tt.wantErr)
+			}
+			if tt.wantErr != nil {
+				if roleRepo.replaced {
+					t.Fatal("induk role tetap diganti")
+				}
+				return
+			}
+			if !reflect.DeepEqual(detail.Parents, tt.parents) {
+				t.Fatalf("induk = %v, ingin %v", detail.Parents, tt.parents)
+			}
+		})
+	}
+}
+
+func TestRoleDetailInheritedPermissions(t *testing.T) {
+	viewer := entities.Role{ID: uuid.New(), Name: "viewer", Permissions: []*entities.Permission{{Name: "users:read"}, {Name: "reports:read"}}}
+	editor := entities.Role{ID: uuid.New(), Name: "editor", Parents: []*entities.Role{&viewer}, Permissions: []*entities.Permission{{Name: "users:write"}, {Name: "users:read"}}}
+	admin := entities.Role{ID: uuid.New(), Name: "admin", Parents: []*entities.Role{&editor}, Permissions: []*entities.Permission{{Name: "!users:delete"}}}
+	interactor, _ := newHierarchyRoleInteractor(viewer, editor, admin)
+
+	detail, err := interactor.Get(admin.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !reflect.DeepEqual(detail.Parents, []string{"editor"}) || !reflect.DeepEqual(detail.DirectPermissions, []string{"!users:delete"}) {
+		t.Fatalf("detail = %+v, ingin induk editor dan deny users:delete", detail)
+	}
+	// Permission warisan berasal dari Role terdekat yang memuatnya dan tidak diulang
+	want := []InheritedPermission{
+		{Name: "users:write", From: "editor"},
+		{Name: "users:read", From: "editor"},
+		{Name: "reports:read", From: "viewer"},
+	}
+	if !reflect.DeepEqual(detail.InheritedPermissions, want) {
+		t.Fatalf("warisan = %+v, ingin %+v", detail.InheritedPermissions, want)
+	}
+
+	// Permission yang juga dimiliki langsung tidak dicantumkan sebagai warisan
+	detail, err = interactor.Get(editor.ID)
+	if err != nil {
+		t.Fatalf("Get: %v", err)
+	}
+	if !reflect.DeepEqual(detail.InheritedPermissions, []InheritedPermission{{Name: "reports:read", From: "viewer"}}) {
+		t.Fatalf("warisan editor = %+v, ingin reports:read dari viewer", detail.InheritedPermissions)
+	}
+
+	if _, err := interactor.Get(uuid.New()); !errors.Is(err, ErrRoleNotFound) {
+		t.Fatalf("err = %v, ingin ErrRoleNotFound", err)
+	}
+	if _, err := interactor.SetPermissions(viewer.ID, []string{"users"}); !errors.Is(err, ErrPermissionNameInvalid) {
+		t.Fatalf("SetPermissions: err = %v, ingin ErrPermissionNameInvalid", err)
+	}
+}
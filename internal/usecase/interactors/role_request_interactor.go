@@ -0,0 +1,378 @@
+package interactors
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrRoleRequestNotFound dikembalikan jika permintaan Role tidak ada atau bukan milik pengguna.
+	ErrRoleRequestNotFound = errors.New("permintaan role tidak ditemukan")
+	// ErrRoleRequestRoleNotFound dikembalikan jika Role yang diminta tidak terdaftar.
+	ErrRoleRequestRoleNotFound = errors.New("role tidak ditemukan")
+	// ErrRoleRequestHoursInvalid dikembalikan jika durasi yang diminta di luar batas konfigurasi.
+	ErrRoleRequestHoursInvalid = errors.New("durasi permintaan role tidak valid")
+	// ErrRoleRequestReasonRequired dikembalikan jika alasan permintaan kosong.
+	ErrRoleRequestReasonRequired = errors.New("alasan permintaan role wajib diisi")
+	// ErrRoleRequestAlreadyHeld dikembalikan jika pengguna sudah memegang Role secara permanen.
+	ErrRoleRequestAlreadyHeld = errors.New("role sudah dimiliki secara permanen")
+	// ErrRoleRequestDuplicate dikembalikan jika masih ada permintaan yang menunggu untuk Role yang sama.
+	ErrRoleRequestDuplicate = errors.New("masih ada permintaan yang menunggu untuk role ini")
+	// ErrRoleRequestNotPending dikembalikan jika permintaan sudah diputuskan atau dibatalkan.
+	ErrRoleRequestNotPending = errors.New("permintaan role sudah diputuskan atau dibatalkan")
+	// ErrRoleRequestForbidden dikembalikan jika peninjau tidak berwenang memutuskan permintaan Role.
+	ErrRoleRequestForbidden = errors.New("tidak berwenang memutuskan permintaan role")
+	// ErrRoleRequestSelfReview dikembalikan jika peninjau mencoba memutuskan permintaannya sendiri.
+	ErrRoleRequestSelfReview = errors.New("tidak boleh memutuskan permintaan role sendiri")
+)
+
+// Jenis event permintaan Role sementara.
+const (
+	EventRoleRequestCreated   = "role_request.created"
+	EventRoleRequestApproved  = "role_request.approved"
+	EventRoleRequestRejected  = "role_request.rejected"
+	EventRoleRequestCancelled = "role_request.cancelled"
+)
+
+// roleRequestDecisionEvents memetakan status keputusan ke jenis event-nya.
+var roleRequestDecisionEvents = map[string]string{
+	entities.RoleRequestApproved:  EventRoleRequestApproved,
+	entities.RoleRequestRejected:  EventRoleRequestRejected,
+	entities.RoleRequestCancelled: EventRoleRequestCancelled,
+}
+
+// RoleRequestPolicy adalah aturan permintaan Role sementara.
+type RoleRequestPolicy struct {
+	MaxHours int // Durasi terlama yang boleh diminta
+}
+
+// RoleRequestInteractor adalah use case untuk permintaan swalayan Role sementara ("just-in-time"):
+// pengguna meminta Role selama N jam, lalu peninjau yang berwenang menyetujui atau menolaknya.
+// Persetujuan menetapkan Role lewat RoleGrantInteractor dengan waktu berakhir, sehingga Role dicabut
+// otomatis oleh worker. Role yang punya ApprovalPolicy diteruskan ke ApprovalInteractor.
+type RoleRequestInteractor struct {
+	requestRepo    repositories.RoleRequestRepository
+	roleRepo       repositories.RoleRepository
+	userRepo       repositories.UserRepository
+	permissionRepo repositories.PermissionRepository
+	grants         *RoleGrantInteractor
+	approvals      *ApprovalInteractor
+	events         services.EventPublisher
+	policy         RoleRequestPolicy
+}
+
+// NewRoleRequestInteractor membuat instance baru dari RoleRequestInteractor.
+func NewRoleRequestInteractor(
+	qr repositories.RoleRequestRepository,
+	rr repositories.RoleRepository,
+	ur repositories.UserRepository,
+	pr repositories.PermissionRepository,
+	grants *RoleGrantInteractor,
+	approvals *ApprovalInteractor,
+	events services.EventPublisher,
+	policy RoleRequestPolicy,
+) *RoleRequestInteractor {
+	return &RoleRequestInteractor{
+		requestRepo:    qr,
+		roleRepo:       rr,
+		userRepo:       ur,
+		permissionRepo: pr,
+		grants:         grants,
+		approvals:      approvals,
+		events:         events,
+		policy:         policy,
+	}
+}
+
+// Create mencatat permintaan pengguna untuk memegang Role roleName selama hours jam.
+func (i *RoleRequestInteractor) Create(userID uuid.UUID, roleName string, hours int, reason string) (*entities.RoleRequest, error) {
+	if hours <= 0 || hours > i.policy.MaxHours {
+		return nil, fmt.Errorf("%w: harus antara 1 dan %d jam", ErrRoleRequestHoursInvalid, i.policy.MaxHours)
+	}
+	reason = strings.TrimSpace(reason)
+	if reason == "" {
+		return nil, ErrRoleRequestReasonRequired
+	}
+
+	role, err := i.roleRepo.FindByName(strings.TrimSpace(roleName))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleRequestRoleNotFound
+		}
+		return nil, err
+	}
+	if err := i.ensureNotPermanent(userID, role.ID); err != nil {
+		return nil, err
+	}
+	if err := i.grants.CheckConflicts(userID, role.ID); err != nil {
+		return nil, err
+	}
+	if _, err := i.requestRepo.FindPendingByUserAndRole(userID, role.ID); err == nil {
+		return nil, ErrRoleRequestDuplicate
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	request := &entities.RoleRequest{
+		UserID: userID,
+		RoleID: role.ID,
+		Hours:  hours,
+		Reason: reason,
+		Status: entities.RoleRequestPending,
+	}
+	if err := i.requestRepo.Create(request); err != nil {
+		return nil, err
+	}
+	request.Role = role
+
+	i.publish(EventRoleRequestCreated, request)
+	return request, nil
+}
+
+// ListMine mengembalikan semua permintaan milik pengguna.
+func (i *RoleRequestInteractor) ListMine(userID uuid.UUID) ([]entities.RoleRequest, error) {
+	return i.requestRepo.FindByUser(userID)
+}
+
+// Cancel membatalkan permintaan milik pengguna yang masih menunggu.
+func (i *RoleRequestInteractor) Cancel(userID, id uuid.UUID) error {
+	request, err := i.find(id)
+	if err != nil {
+		return err
+	}
+	if request.UserID != userID {
+		return ErrRoleRequestNotFound
+	}
+	return i.decide(request, entities.RoleRequestCancelled, userID, nil)
+}
+
+// ListPending mengembalikan permintaan yang menunggu keputusan. Hanya untuk peninjau yang berwenang.
+func (i *RoleRequestInteractor) ListPending(reviewerID uuid.UUID) ([]entities.RoleRequest, error) {
+	if err := i.ensureReviewer(reviewerID); err != nil {
+		return nil, err
+	}
+	return i.requestRepo.FindPending()
+}
+
+// Approve menyetujui permintaan dan menetapkan Role mulai sekarang selama durasi yang diminta.
+// Jika pengguna sudah memegang Role sementara yang berakhir lebih lambat, waktu berakhir itu dipertahankan.
+// Untuk Role yang punya kebijakan persetujuan, Role baru diberikan setelah kebijakannya terpenuhi.
+// Status diperbarui sebelum Role ditetapkan agar persetujuan bersamaan tidak menetapkannya dua kali; jika
+// penetapan gagal, permintaan dikembalikan ke status menunggu agar tidak tercatat disetujui tanpa Role-nya.
+func (i *RoleRequestInteractor) Approve(reviewerID, id uuid.UUID) (*entities.RoleRequest, error) {
+	request, err := i.findForReview(reviewerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.ensureNotPermanent(request.UserID, request.RoleID); err != nil {
+		return nil, err
+	}
+	if err := i.grants.CheckConflicts(request.UserID, request.RoleID); err != nil {
+		return nil, err
+	}
+
+	required, err := i.grants.RequiresApproval(request.RoleID)
+	if err != nil {
+		return nil, err
+	}
+	if required {
+		return i.forward(reviewerID, request)
+	}
+
+	now := time.Now()
+	expiresAt := now.Add(time.Duration(request.Hours) * time.Hour)
+	if existing, err := i.roleRepo.FindGrant(request.UserID, request.RoleID); err == nil {
+		if existing.ExpiresAt != nil && existing.ExpiresAt.After(expiresAt) {
+			expiresAt = *existing.ExpiresAt
+		}
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	if err := i.save(request, entities.RoleRequestApproved, reviewerID, &expiresAt); err != nil {
+		return nil, err
+	}
+	_, err = i.grants.Grant(request.UserID, RoleGrantInput{
+		Role:      request.Role.Name,
+		StartsAt:  &now,
+		ExpiresAt: &expiresAt,
+		Reason:    request.Reason,
+	}, reviewerID)
+	if err != nil {
+		if reopenErr := i.requestRepo.Reopen(request); reopenErr != nil {
+			log.Printf("Gagal mengembalikan permintaan Role %s ke status menunggu: %v", request.ID, reopenErr)
+		} else {
+			request.Status = entities.RoleRequestPending
+			request.ReviewedBy, request.ReviewedAt, request.GrantExpiresAt = nil, nil, nil
+		}
+		return nil, err
+	}
+
+	i.publish(roleRequestDecisionEvents[request.Status], request)
+	return request, nil
+}
+
+// forward meneruskan permintaan yang disetujui ke alur persetujuan atas nama reviewerID. Durasi dihitung
+// saat penetapan diterapkan, bukan saat diteruskan.
+func (i *RoleRequestInteractor) forward(reviewerID uuid.UUID, request *entities.RoleRequest) (*entities.RoleRequest, error) {
+	assignment, err := i.approvals.Submit(reviewerID, AssignmentInput{
+		UserID: request.UserID,
+		Grant:  RoleGrantInput{Role: request.Role.Name, Hours: request.Hours, Reason: request.Reason},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	request.AssignmentRequestID = &assignment.ID
+	if err := i.decide(request, entities.RoleRequestApproved, reviewerID, nil); err != nil {
+		if cancelErr := i.approvals.Cancel(reviewerID, assignment.ID); cancelErr != nil {
+			log.Printf("Gagal membatalkan permintaan penetapan %s: %v", assignment.ID, cancelErr)
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// Reject menolak permintaan yang masih menunggu.
+func (i *RoleRequestInteractor) Reject(reviewerID, id uuid.UUID) (*entities.RoleRequest, error) {
+	request, err := i.findForReview(reviewerID, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.decide(request, entities.RoleRequestRejected, reviewerID, nil); err != nil {
+		return nil, err
+	}
+	return request, nil
+}
+
+func (i *RoleRequestInteractor) find(id uuid.UUID) (*entities.RoleRequest, error) {
+	request, err := i.requestRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrRoleRequestNotFound
+		}
+		return nil, err
+	}
+	return request, nil
+}
+
+// findForReview memastikan reviewerID berwenang dan bukan pemilik permintaan yang masih menunggu.
+func (i *RoleRequestInteractor) findForReview(reviewerID, id uuid.UUID) (*entities.RoleRequest, error) {
+	if err := i.ensureReviewer(reviewerID); err != nil {
+		return nil, err
+	}
+	request, err := i.find(id)
+	if err != nil {
+		return nil, err
+	}
+	if request.UserID == reviewerID {
+		return nil, ErrRoleRequestSelfReview
+	}
+	if !request.IsPending() {
+		return nil, ErrRoleRequestNotPending
+	}
+	if request.Role == nil {
+		return nil, ErrRoleRequestRoleNotFound // Role dihapus setelah permintaan dibuat
+	}
+	return request, nil
+}
+
+// ensureReviewer memastikan pengguna adalah superuser atau memegang permission role-requests:approve.
+func (i *RoleRequestInteractor) ensureReviewer(userID uuid.UUID) error {
+	user, err := i.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleRequestForbidden
+		}
+		return err
+	}
+	if user.IsSuperuser {
+		return nil
+	}
+
+	names, err := i.permissionRepo.FindNamesByUser(userID)
+	if err != nil {
+		return err
+	}
+	if !entities.NewPermissionSet(names).Allows(entities.PermissionRoleRequestsApprove) {
+		return ErrRoleRequestForbidden
+	}
+	return nil
+}
+
+// ensureNotPermanent menolak permintaan untuk Role yang sudah dipegang pengguna tanpa batas waktu.
+func (i *RoleRequestInteractor) ensureNotPermanent(userID, roleID uuid.UUID) error {
+	grant, err := i.roleRepo.FindGrant(userID, roleID)
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	if !grant.IsTemporary() && grant.IsActive(time.Now()) {
+		return ErrRoleRequestAlreadyHeld
+	}
+	return nil
+}
+
+// decide menyimpan keputusan permintaan lalu menerbitkan event-nya.
+func (i *RoleRequestInteractor) decide(request *entities.RoleRequest, status string, reviewerID uuid.UUID, grantExpiresAt *time.Time) error {
+	if err := i.save(request, status, reviewerID, grantExpiresAt); err != nil {
+		return err
+	}
+
+	i.publish(roleRequestDecisionEvents[status], request)
+	return nil
+}
+
+// save menyimpan keputusan permintaan tanpa menerbitkan event-nya.
+func (i *RoleRequestInteractor) save(request *entities.RoleRequest, status string, reviewerID uuid.UUID, grantExpiresAt *time.Time) error {
+	if !request.IsPending() {
+		return ErrRoleRequestNotPending
+	}
+
+	now := time.Now()
+	request.Status = status
+	request.ReviewedBy = &reviewerID
+	request.ReviewedAt = &now
+	request.GrantExpiresAt = grantExpiresAt
+	if err := i.requestRepo.UpdateDecision(request); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrRoleRequestNotPending
+		}
+		return err
+	}
+	return nil
+}
+
+// publish menerbitkan event permintaan Role. Kegagalan hanya dicatat karena perubahan sudah tersimpan.
+func (i *RoleRequestInteractor) publish(eventType string, request *entities.RoleRequest) {
+	data := map[string]any{
+		"request_id":            request.ID,
+		"user_id":               request.UserID,
+		"role_id":               request.RoleID,
+		"hours":                 request.Hours,
+		"reason":                request.Reason,
+		"status":                request.Status,
+		"reviewed_by":           request.ReviewedBy,
+		"grant_expires_at":      request.GrantExpiresAt,
+		"assignment_request_id": request.AssignmentRequestID,
+	}
+	if request.Role != nil {
+		data["role"] = request.Role.Name
+	}
+
+	if err := i.events.Publish(services.Event{Type: eventType, OccurredAt: time.Now(), Data: data}); err != nil {
+		log.Printf("Gagal menerbitkan event %s untuk permintaan %s: %v", eventType, request.ID, err)
+	}
+}
@@ -0,0 +1,120 @@
+package interactors
+
+import (
+	"errors"
+	"testing"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// memoryRoleRequestRepository adalah RoleRequestRepository di memori dengan satu permintaan.
+type memoryRoleRequestRepository struct {
+	repositories.RoleRequestRepository
+	request entities.RoleRequest
+}
+
+func (r *memoryRoleRequestRepository) FindByID(id uuid.UUID) (*entities.RoleRequest, error) {
+	if r.request.ID != id {
+		return nil, gorm.ErrRecordNotFound
+	}
+	request := r.request
+	return &request, nil
+}
+
+func (r *memoryRoleRequestRepository) UpdateDecision(request *entities.RoleRequest) error {
+	if r.request.ID != request.ID || !r.request.IsPending() {
+		return gorm.ErrRecordNotFound
+	}
+	r.request.Status, r.request.ReviewedBy, r.request.GrantExpiresAt = request.Status, request.ReviewedBy, request.GrantExpiresAt
+	return nil
+}
+
+func (r *memoryRoleRequestRepository) Reopen(request *entities.RoleRequest) error {
+	if r.request.ID != request.ID || r.request.Status != entities.RoleRequestApproved {
+		return gorm.ErrRecordNotFound
+	}
+	r.request.Status, r.request.ReviewedBy, r.request.GrantExpiresAt = entities.RoleRequestPending, nil, nil
+	return nil
+}
+
+// failingGrantRoleRepository adalah memoryRoleRepository yang menggagalkan Grant sebanyak failures kali.
+type failingGrantRoleRepository struct {
+	*memoryRoleRepository
+	failures int
+	granted  []entities.UserRole
+}
+
+func (r *failingGrantRoleRepository) FindGrant(_, _ uuid.UUID) (*entities.UserRole, error) {
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *failingGrantRoleRepository) Grant(grant *entities.UserRole) error {
+	if r.failures > 0 {
+		r.failures--
+		return errors.New("koneksi terputus")
+	}
+	r.granted = append(r.granted, *grant)
+	return nil
+}
+
+// recordingEventPublisher adalah EventPublisher uji yang mencatat jenis event yang diterbitkan.
+type recordingEventPublisher struct {
+	types []string
+}
+
+func (p *recordingEventPublisher) Publish(event services.Event) error {
+	p.types = append(p.types, event.Type)
+	return nil
+}
+
+func TestApproveRoleRequestReopensOnGrantFailure(t *testing.T) {
+	reviewer := &entities.User{ID: uuid.New(), Username: "admin", IsSuperuser: true}
+	user := &entities.User{ID: uuid.New(), Username: "alice"}
+	users := newMemoryUserRepository(reviewer, user)
+	role := entities.Role{ID: uuid.New(), Name: "deployer"}
+	roleRepo := &failingGrantRoleRepository{memoryRoleRepository: &memoryRoleRepository{users: users, roles: []entities.Role{role}}, failures: 1}
+	requests := &memoryRoleRequestRepository{request: entities.RoleRequest{
+		ID:     uuid.New(),
+		UserID: user.ID,
+		RoleID: role.ID,
+		Role:   &role,
+		Hours:  4,
+		Reason: "perbaikan produksi",
+		Status: entities.RoleRequestPending,
+	}}
+	events := &recordingEventPublisher{}
+	sod := NewSoDInteractor(&staticSoDRuleRepository{}, roleRepo, nil, nil, nil)
+	grants := NewRoleGrantInteractor(roleRepo, users, &staticApprovalPolicyRepository{}, sod, NewAuditInteractor(discardAuditLogRepository{}), events)
+	interactor := NewRoleRequestInteractor(requests, roleRepo, users, nil, grants, nil, events, RoleRequestPolicy{MaxHours: 8})
+
+	// Penetapan gagal: permintaan kembali menunggu tanpa event persetujuan
+	if _, err := interactor.Approve(reviewer.ID, requests.request.ID); err == nil {
+		t.Fatal("Approve tidak mengembalikan kegagalan penetapan")
+	}
+	if !requests.request.IsPending() || requests.request.ReviewedBy != nil {
+		t.Fatalf("status = %s, ingin pending tanpa peninjau", requests.request.Status)
+	}
+	if len(events.types) != 0 {
+		t.Fatalf("event = %v, ingin tidak ada", events.types)
+	}
+
+	// Persetujuan ulang berhasil menetapkan Role
+	request, err := interactor.Approve(reviewer.ID, requests.request.ID)
+	if err != nil {
+		t.Fatalf("Approve: %v", err)
+	}
+	if request.Status != entities.RoleRequestApproved || requests.request.Status != entities.RoleRequestApproved {
+		t.Fatalf("status = %s, ingin approved", requests.request.Status)
+	}
+	if len(roleRepo.granted) != 1 || roleRepo.granted[0].ExpiresAt == nil {
+		t.Fatalf("penetapan = %+v, ingin satu penetapan sementara", roleRepo.granted)
+	}
+	if n := len(events.types); n == 0 || events.types[n-1] != EventRoleRequestApproved {
+		t.Fatalf("event = %v, ingin diakhiri %s", events.types, EventRoleRequestApproved)
+	}
+}
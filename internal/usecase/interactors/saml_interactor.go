@@ -0,0 +1,412 @@
+package interactors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"regexp"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSAMLConnectionNotFound dikembalikan jika koneksi SAML tidak ada.
+	ErrSAMLConnectionNotFound = errors.New("koneksi SAML tidak ditemukan")
+	// ErrSAMLConnectionNameInvalid dikembalikan jika nama koneksi tidak bisa dipakai di URL.
+	ErrSAMLConnectionNameInvalid = errors.New("nama koneksi SAML hanya boleh berisi huruf kecil, angka dan tanda hubung")
+	// ErrSAMLConnectionNameTaken dikembalikan jika nama koneksi sudah dipakai koneksi lain.
+	ErrSAMLConnectionNameTaken = errors.New("nama koneksi SAML sudah dipakai")
+	// ErrSAMLMetadataRequired dikembalikan jika koneksi dibuat tanpa metadata IdP.
+	ErrSAMLMetadataRequired = errors.New("metadata XML atau URL metadata IdP wajib diisi")
+	// ErrInvalidSAMLRelayState dikembalikan jika RelayState tidak dikenal, sudah dipakai atau kedaluwarsa
+	// dan koneksi tidak menerima login IdP-initiated.
+	ErrInvalidSAMLRelayState = errors.New("RelayState SAML tidak valid atau kedaluwarsa")
+)
+
+// samlReplayTTL adalah lama ID assertion diingat untuk menolak assertion yang dikirim ulang.
+// Harus lebih lama dari batas umur assertion yang diterima (90 detik ditambah toleransi jam).
+const samlReplayTTL = 10 * time.Minute
+
+// samlConnectionName membatasi nama koneksi agar aman dipakai di URL dan entity ID.
+var samlConnectionName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,62}$`)
+
+// SAMLPolicy adalah aturan login SAML.
+type SAMLPolicy struct {
+	StateTTL time.Duration // Batas waktu antara redirect ke IdP dan kembalinya assertion ke ACS
+}
+
+// SAMLConnectionInput adalah data koneksi SAML dari admin. Metadata IdP diambil dari MetadataXML,
+// atau diunduh dari MetadataURL jika MetadataXML kosong.
+type SAMLConnectionInput struct {
+	Name              string
+	DisplayName       string
+	MetadataXML       string
+	MetadataURL       string
+	AllowIDPInitiated bool
+	AllowSignup       bool
+	AttributeMapping  entities.SAMLAttributeMapping
+	GroupRoles        map[string][]string
+	EmailDomains      []string
+}
+
+// samlState adalah permintaan login SP-initiated yang disimpan sampai assertion diterima.
+type samlState struct {
+	Connection string `json:"connection"`
+	RequestID  string `json:"request_id"`
+}
+
+// SAMLInteractor adalah use case login dengan identity provider SAML 2.0 perusahaan
+// dan pengelolaan koneksinya oleh admin.
+type SAMLInteractor struct {
+	connectionRepo repositories.SAMLConnectionRepository
+	userRepo       repositories.UserRepository
+	roleRepo       repositories.RoleRepository
+	challengeStore repositories.ChallengeStore
+	sp             services.SAMLServiceProvider
+	accounts       externalAccounts
+	auth           *AuthInteractor
+	grants         *RoleGrantInteractor
+	policy         SAMLPolicy
+}
+
+// NewSAMLInteractor membuat instance baru dari SAMLInteractor.
+func NewSAMLInteractor(
+	cr repositories.SAMLConnectionRepository,
+	ir repositories.LinkedIdentityRepository,
+	ur repositories.UserRepository,
+	rr repositories.RoleRepository,
+	cs repositories.ChallengeStore,
+	sp services.SAMLServiceProvider,
+	users *UserInteractor,
+	auth *AuthInteractor,
+	grants *RoleGrantInteractor,
+	policy SAMLPolicy,
+) *SAMLInteractor {
+	return &SAMLInteractor{
+		connectionRepo: cr,
+		userRepo:       ur,
+		roleRepo:       rr,
+		challengeStore: cs,
+		sp:             sp,
+		accounts:       externalAccounts{identityRepo: ir, userRepo: ur, users: users},
+		auth:           auth,
+		grants:         grants,
+		policy:         policy,
+	}
+}
+
+// Connections mengembalikan koneksi SAML untuk ditampilkan di halaman login.
+func (i *SAMLInteractor) Connections() ([]IdentityProviderInfo, error) {
+	connections, err := i.connectionRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+	infos := make([]IdentityProviderInfo, 0, len(connections))
+	for _, connection := range connections {
+		infos = append(infos, IdentityProviderInfo{Name: connection.Name, DisplayName: connection.DisplayName})
+	}
+	return infos, nil
+}
+
+// ListConnections mengembalikan semua koneksi SAML untuk admin.
+func (i *SAMLInteractor) ListConnections() ([]entities.SAMLConnection, error) {
+	return i.connectionRepo.FindAll()
+}
+
+// CreateConnection mengimpor metadata IdP dan menyimpan koneksi SAML baru.
+func (i *SAMLInteractor) CreateConnection(ctx context.Context, createdBy uuid.UUID, input SAMLConnectionInput) (*entities.SAMLConnection, error) {
+	name := strings.TrimSpace(input.Name)
+	if !samlConnectionName.MatchString(name) {
+		return nil, ErrSAMLConnectionNameInvalid
+	}
+	if _, err := i.connectionRepo.FindByName(name); err == nil {
+		return nil, ErrSAMLConnectionNameTaken
+	} else if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	connection := &entities.SAMLConnection{Name: name, CreatedBy: createdBy}
+	if err := i.importMetadata(ctx, connection, input); err != nil {
+		return nil, err
+	}
+	if connection.IdPMetadata == "" {
+		return nil, ErrSAMLMetadataRequired
+	}
+	i.applyInput(connection, input)
+
+	if err := i.connectionRepo.Create(connection); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+// UpdateConnection memperbarui koneksi SAML. Nama koneksi tidak bisa diubah karena menjadi bagian
+// dari entity ID yang didaftarkan di IdP; metadata IdP hanya diganti jika dikirim ulang.
+func (i *SAMLInteractor) UpdateConnection(ctx context.Context, id uuid.UUID, input SAMLConnectionInput) (*entities.SAMLConnection, error) {
+	connection, err := i.connectionRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSAMLConnectionNotFound
+		}
+		return nil, err
+	}
+
+	if err := i.importMetadata(ctx, connection, input); err != nil {
+		return nil, err
+	}
+	i.applyInput(connection, input)
+
+	if err := i.connectionRepo.Update(connection); err != nil {
+		return nil, err
+	}
+	return connection, nil
+}
+
+// DeleteConnection menghapus koneksi SAML. Identitas yang terhubung lewat koneksi ini tidak lagi bisa dipakai login.
+func (i *SAMLInteractor) DeleteConnection(id uuid.UUID) error {
+	if err := i.connectionRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSAMLConnectionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// Metadata mengembalikan XML metadata service provider untuk didaftarkan di IdP.
+func (i *SAMLInteractor) Metadata(name string) ([]byte, error) {
+	connection, err := i.connection(name)
+	if err != nil {
+		return nil, err
+	}
+	return i.sp.Metadata(connection.Name)
+}
+
+// BeginLogin memulai login SP-initiated dan mengembalikan alamat login di IdP.
+func (i *SAMLInteractor) BeginLogin(ctx context.Context, name string) (string, error) {
+	connection, err := i.connection(name)
+	if err != nil {
+		return "", err
+	}
+
+	relayState, err := randomString(oauthSecretLength)
+	if err != nil {
+		return "", err
+	}
+	req, err := i.sp.AuthnRequest(connection.Name, []byte(connection.IdPMetadata), relayState)
+	if err != nil {
+		log.Printf("Gagal membuat AuthnRequest SAML %s: %v", connection.Name, err)
+		return "", ErrExternalLoginFailed
+	}
+
+	raw, err := json.Marshal(samlState{Connection: connection.Name, RequestID: req.ID})
+	if err != nil {
+		return "", err
+	}
+	if err := i.challengeStore.Save(ctx, samlStateKey(relayState), raw, i.policy.StateTTL); err != nil {
+		return "", err
+	}
+	return req.RedirectURL, nil
+}
+
+// ACS memverifikasi SAMLResponse yang dikirim browser dari IdP lalu melanjutkan login. RelayState yang
+// dikenal berarti login SP-initiated dan InResponseTo harus cocok; tanpa RelayState yang dikenal, assertion
+// hanya diterima jika koneksi mengizinkan login IdP-initiated. Setiap assertion hanya bisa dipakai sekali.
+func (i *SAMLInteractor) ACS(ctx context.Context, name, samlResponse, relayState string, client ClientInfo) (*LoginResult, error) {
+	connection, err := i.connection(name)
+	if err != nil {
+		return nil, err
+	}
+
+	requestID, err := i.takeRequestID(ctx, connection.Name, relayState)
+	if err != nil {
+		return nil, err
+	}
+	if requestID == "" && !connection.AllowIDPInitiated {
+		return nil, ErrInvalidSAMLRelayState
+	}
+
+	assertion, err := i.sp.ParseResponse(connection.Name, []byte(connection.IdPMetadata), samlResponse, requestID)
+	if err != nil {
+		log.Printf("Login SAML %s gagal: %v", connection.Name, err)
+		return nil, ErrExternalLoginFailed
+	}
+
+	fresh, err := i.challengeStore.SaveNew(ctx, "saml:assertion:"+hashSecret(connection.Name+":"+assertion.ID), []byte{1}, samlReplayTTL)
+	if err != nil {
+		return nil, err
+	}
+	if !fresh {
+		log.Printf("Assertion SAML %s dari %s dikirim ulang", assertion.ID, connection.Name)
+		return nil, ErrExternalLoginFailed
+	}
+
+	external := samlExternalIdentity(connection, assertion)
+	user, err := i.accounts.resolve(external, connection.AllowSignup)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.applyAssertion(user, connection, external, assertion); err != nil {
+		return nil, err
+	}
+	return i.auth.LoginWithExternalIdentity(ctx, user, client)
+}
+
+// connection mencari koneksi berdasarkan nama di URL.
+func (i *SAMLInteractor) connection(name string) (*entities.SAMLConnection, error) {
+	connection, err := i.connectionRepo.FindByName(name)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSAMLConnectionNotFound
+		}
+		return nil, err
+	}
+	return connection, nil
+}
+
+// takeRequestID mengambil ID AuthnRequest milik RelayState, atau string kosong jika RelayState tidak dikenal
+// (misalnya alamat tujuan yang dikirim IdP pada login IdP-initiated).
+func (i *SAMLInteractor) takeRequestID(ctx context.Context, connection, relayState string) (string, error) {
+	if relayState == "" {
+		return "", nil
+	}
+	raw, err := i.challengeStore.Take(ctx, samlStateKey(relayState))
+	if err != nil {
+		if errors.Is(err, repositories.ErrChallengeNotFound) {
+			return "", nil
+		}
+		return "", err
+	}
+
+	var pending samlState
+	if err := json.Unmarshal(raw, &pending); err != nil || pending.Connection != connection {
+		return "", ErrInvalidSAMLRelayState
+	}
+	return pending.RequestID, nil
+}
+
+// importMetadata membaca metadata IdP dari input, mengunduhnya jika hanya URL yang diberikan.
+// Koneksi tidak diubah jika input tidak berisi metadata.
+func (i *SAMLInteractor) importMetadata(ctx context.Context, connection *entities.SAMLConnection, input SAMLConnectionInput) error {
+	metadataURL := strings.TrimSpace(input.MetadataURL)
+	raw := []byte(strings.TrimSpace(input.MetadataXML))
+	if len(raw) == 0 {
+		if metadataURL == "" {
+			return nil
+		}
+		fetched, err := i.sp.FetchIdPMetadata(ctx, metadataURL)
+		if err != nil {
+			return fmt.Errorf("%w: %v", services.ErrSAMLInvalidMetadata, err)
+		}
+		raw = fetched
+	}
+
+	metadata, err := i.sp.ParseIdPMetadata(raw)
+	if err != nil {
+		return err
+	}
+	connection.IdPEntityID = metadata.EntityID
+	connection.IdPMetadata = string(raw)
+	connection.MetadataURL = metadataURL
+	return nil
+}
+
+// applyInput menyalin pengaturan koneksi dari input admin dan melengkapi pemetaan atribut default.
+func (i *SAMLInteractor) applyInput(connection *entities.SAMLConnection, input SAMLConnectionInput) {
+	connection.DisplayName = strings.TrimSpace(input.DisplayName)
+	if connection.DisplayName == "" {
+		connection.DisplayName = connection.Name
+	}
+	connection.AllowIDPInitiated = input.AllowIDPInitiated
+	connection.AllowSignup = input.AllowSignup
+
+	mapping := input.AttributeMapping
+	if mapping.Email == "" {
+		mapping.Email = "email"
+	}
+	if mapping.FirstName == "" {
+		mapping.FirstName = "firstName"
+	}
+	if mapping.LastName == "" {
+		mapping.LastName = "lastName"
+	}
+	if mapping.Groups == "" {
+		mapping.Groups = "groups"
+	}
+	connection.AttributeMapping = mapping
+
+	connection.GroupRoles = input.GroupRoles
+	if connection.GroupRoles == nil {
+		connection.GroupRoles = map[string][]string{}
+	}
+
+	connection.EmailDomains = make([]string, 0, len(input.EmailDomains))
+	for _, domain := range input.EmailDomains {
+		domain = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(domain), "@"))
+		if domain != "" {
+			connection.EmailDomains = append(connection.EmailDomains, domain)
+		}
+	}
+}
+
+// applyAssertion menyalin nama dari assertion ke profil pengguna dan menyesuaikan role dari atribut grup.
+func (i *SAMLInteractor) applyAssertion(user *entities.User, connection *entities.SAMLConnection, external *services.ExternalIdentity, assertion *services.SAMLAssertion) error {
+	changed := false
+	if external.FirstName != "" && external.FirstName != user.FirstName {
+		user.FirstName = external.FirstName
+		changed = true
+	}
+	if external.LastName != "" && external.LastName != user.LastName {
+		user.LastName = external.LastName
+		changed = true
+	}
+	if changed {
+		if _, err := i.userRepo.Update(user); err != nil {
+			return err
+		}
+	}
+
+	groups := assertion.Attributes[connection.AttributeMapping.Groups]
+	return newGroupRoles(connection.GroupRoles, i.roleRepo, i.grants).sync(user, groups)
+}
+
+// samlExternalIdentity memetakan assertion ke identitas eksternal. Email hanya dianggap terverifikasi jika
+// domainnya termasuk EmailDomains koneksi, agar IdP tidak bisa mengklaim akun lokal di domain lain lewat
+// email; NameID dipakai sebagai email jika atribut email tidak ada.
+func samlExternalIdentity(connection *entities.SAMLConnection, assertion *services.SAMLAssertion) *services.ExternalIdentity {
+	attribute := func(name string) string {
+		if name == "" || len(assertion.Attributes[name]) == 0 {
+			return ""
+		}
+		return strings.TrimSpace(assertion.Attributes[name][0])
+	}
+
+	email := attribute(connection.AttributeMapping.Email)
+	if email == "" && strings.Contains(assertion.NameID, "@") {
+		email = assertion.NameID
+	}
+
+	return &services.ExternalIdentity{
+		Provider:          connection.ProviderName(),
+		Subject:           assertion.NameID,
+		Email:             email,
+		EmailVerified:     connection.TrustsEmail(email),
+		PreferredUsername: attribute(connection.AttributeMapping.Username),
+		FirstName:         attribute(connection.AttributeMapping.FirstName),
+		LastName:          attribute(connection.AttributeMapping.LastName),
+	}
+}
+
+// samlStateKey mengembalikan key ChallengeStore untuk RelayState login SAML.
+func samlStateKey(relayState string) string {
+	return "saml:state:" + hashSecret(relayState)
+}
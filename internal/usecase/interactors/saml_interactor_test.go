@@ -0,0 +1,196 @@
+package interactors
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// staticSAMLServiceProvider adalah SAMLServiceProvider uji yang menerima setiap SAMLResponse sebagai
+// assertion yang sama. Verifikasi XML sendiri diuji di paket sso; di sini yang diuji adalah ACS.
+type staticSAMLServiceProvider struct {
+	services.SAMLServiceProvider
+	assertion  services.SAMLAssertion
+	requestIDs []string // requestID yang diterima ParseResponse
+}
+
+func (p *staticSAMLServiceProvider) ParseResponse(_ string, _ []byte, _ string, requestID string) (*services.SAMLAssertion, error) {
+	p.requestIDs = append(p.requestIDs, requestID)
+	assertion := p.assertion
+	return &assertion, nil
+}
+
+// memorySAMLConnectionRepository adalah SAMLConnectionRepository di memori untuk pengujian.
+type memorySAMLConnectionRepository struct {
+	repositories.SAMLConnectionRepository
+	connections []entities.SAMLConnection
+}
+
+func (r *memorySAMLConnectionRepository) FindByName(name string) (*entities.SAMLConnection, error) {
+	for _, connection := range r.connections {
+		if connection.Name == name {
+			found := connection
+			return &found, nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func newTestSAML(t *testing.T, connection entities.SAMLConnection, users ...*entities.User) (*SAMLInteractor, *staticSAMLServiceProvider, *memoryLinkedIdentityRepository) {
+	t.Helper()
+	sp := &staticSAMLServiceProvider{assertion: services.SAMLAssertion{
+		ID:         "id-assertion",
+		NameID:     "alice@acme.example",
+		Attributes: map[string][]string{"email": {"alice@acme.example"}},
+	}}
+	identities := &memoryLinkedIdentityRepository{}
+	interactor := NewSAMLInteractor(
+		&memorySAMLConnectionRepository{connections: []entities.SAMLConnection{connection}},
+		identities,
+		newMemoryUserRepository(users...),
+		nil,
+		newTestChallengeStore(t),
+		sp,
+		nil,
+		nil,
+		nil,
+		SAMLPolicy{StateTTL: time.Minute},
+	)
+	return interactor, sp, identities
+}
+
+func testSAMLConnection() entities.SAMLConnection {
+	return entities.SAMLConnection{
+		ID:               uuid.New(),
+		Name:             "acme",
+		AttributeMapping: entities.SAMLAttributeMapping{Email: "email"},
+		EmailDomains:     []string{"acme.example"},
+	}
+}
+
+func TestSAMLACSRejectsUnsolicitedResponse(t *testing.T) {
+	interactor, sp, _ := newTestSAML(t, testSAMLConnection())
+	ctx := context.Background()
+
+	// Tanpa RelayState yang dikenal, koneksi tanpa AllowIDPInitiated menolak sebelum memeriksa assertion
+	for _, relayState := range []string{"", "relay-tidak-dikenal"} {
+		if _, err := interactor.ACS(ctx, "acme", "response", relayState, ClientInfo{}); !errors.Is(err, ErrInvalidSAMLRelayState) {
+			t.Fatalf("RelayState %q: err = %v, ingin ErrInvalidSAMLRelayState", relayState, err)
+		}
+	}
+	if len(sp.requestIDs) != 0 {
+		t.Fatalf("ParseResponse dipanggil untuk respons unsolicited: %v", sp.requestIDs)
+	}
+}
+
+func TestSAMLACSRejectsReusedRelayState(t *testing.T) {
+	interactor, sp, _ := newTestSAML(t, testSAMLConnection())
+	ctx := context.Background()
+
+	// RelayState seperti yang disimpan BeginLogin untuk AuthnRequest id-request
+	relayState := "relay"
+	if err := interactor.challengeStore.Save(ctx, samlStateKey(relayState), []byte(`{"connection":"acme","request_id":"id-request"}`), time.Minute); err != nil {
+		t.Fatal(err)
+	}
+
+	// Login pertama sampai ke pencocokan akun; tidak ada akun dan pendaftaran tidak diizinkan
+	if _, err := interactor.ACS(ctx, "acme", "response", relayState, ClientInfo{}); !errors.Is(err, ErrExternalAccountNotFound) {
+		t.Fatalf("ACS pertama: err = %v, ingin ErrExternalAccountNotFound", err)
+	}
+	if len(sp.requestIDs) != 1 || sp.requestIDs[0] != "id-request" {
+		t.Fatalf("ParseResponse menerima requestID %v, ingin id-request", sp.requestIDs)
+	}
+
+	// RelayState sudah diambil sehingga respons yang sama diperlakukan sebagai unsolicited
+	if _, err := interactor.ACS(ctx, "acme", "response", relayState, ClientInfo{}); !errors.Is(err, ErrInvalidSAMLRelayState) {
+		t.Fatalf("ACS kedua: err = %v, ingin ErrInvalidSAMLRelayState", err)
+	}
+}
+
+func TestSAMLACSRejectsReplayedAssertion(t *testing.T) {
+	connection := testSAMLConnection()
+	connection.AllowIDPInitiated = true
+	interactor, _, _ := newTestSAML(t, connection)
+	ctx := context.Background()
+
+	if _, err := interactor.ACS(ctx, "acme", "response", "", ClientInfo{}); !errors.Is(err, ErrExternalAccountNotFound) {
+		t.Fatalf("ACS pertama: err = %v, ingin ErrExternalAccountNotFound", err)
+	}
+	// Assertion dengan ID yang sama ditolak meskipun tanda tangannya valid
+	if _, err := interactor.ACS(ctx, "acme", "response", "", ClientInfo{}); !errors.Is(err, ErrExternalLoginFailed) {
+		t.Fatalf("ACS kedua: err = %v, ingin ErrExternalLoginFailed", err)
+	}
+}
+
+func TestSAMLExternalIdentityTrustsOnlyConnectionDomains(t *testing.T) {
+	connection := testSAMLConnection()
+	connection.EmailDomains = []string{"acme.example", "acme-corp.example"}
+
+	tests := []struct {
+		email    string
+		verified bool
+	}{
+		{"alice@acme.example", true},
+		{"Alice@ACME-Corp.example", true},
+		{"alice@other.example", false},
+		{"alice@sub.acme.example", false},
+		{"alice@acme.example.evil", false},
+		{"alice", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.email, func(t *testing.T) {
+			external := samlExternalIdentity(&connection, &services.SAMLAssertion{
+				NameID:     "subject",
+				Attributes: map[string][]string{"email": {tt.email}},
+			})
+			if external.EmailVerified != tt.verified {
+				t.Fatalf("EmailVerified = %v, ingin %v", external.EmailVerified, tt.verified)
+			}
+		})
+	}
+
+	connection.EmailDomains = nil
+	external := samlExternalIdentity(&connection, &services.SAMLAssertion{NameID: "alice@acme.example"})
+	if external.EmailVerified {
+		t.Fatal("email dianggap terverifikasi pada koneksi tanpa EmailDomains")
+	}
+}
+
+func TestSAMLACSDoesNotLinkOutsideDomainOrSuperuser(t *testing.T) {
+	connection := testSAMLConnection()
+	connection.AllowIDPInitiated = true
+	ctx := context.Background()
+
+	t.Run("domain lain", func(t *testing.T) {
+		victim := &entities.User{ID: uuid.New(), Username: "victim", Email: "victim@other.example", IsActive: true}
+		interactor, sp, identities := newTestSAML(t, connection, victim)
+		sp.assertion.Attributes = map[string][]string{"email": {victim.Email}}
+
+		if _, err := interactor.ACS(ctx, "acme", "response", "", ClientInfo{}); !errors.Is(err, ErrExternalEmailNotVerified) {
+			t.Fatalf("err = %v, ingin ErrExternalEmailNotVerified", err)
+		}
+		if linked, _ := identities.FindByUser(victim.ID); len(linked) != 0 {
+			t.Fatalf("identitas SAML dihubungkan ke akun di domain lain: %+v", linked)
+		}
+	})
+
+	t.Run("superuser", func(t *testing.T) {
+		admin := &entities.User{ID: uuid.New(), Username: "root", Email: "alice@acme.example", IsActive: true, IsSuperuser: true}
+		interactor, _, identities := newTestSAML(t, connection, admin)
+
+		if _, err := interactor.ACS(ctx, "acme", "response", "", ClientInfo{}); !errors.Is(err, ErrExternalLinkRequired) {
+			t.Fatalf("err = %v, ingin ErrExternalLinkRequired", err)
+		}
+		if linked, _ := identities.FindByUser(admin.ID); len(linked) != 0 {
+			t.Fatalf("identitas SAML dihubungkan otomatis ke superuser: %+v", linked)
+		}
+	})
+}
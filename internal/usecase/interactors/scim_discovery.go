@@ -0,0 +1,220 @@
+package interactors
+
+// SCIMSupported menandai fitur opsional SCIM yang didukung atau tidak.
+type SCIMSupported struct {
+	Supported bool `json:"supported"`
+}
+
+// SCIMFilterSupport menjelaskan dukungan filter dan batas jumlah hasil per halaman.
+type SCIMFilterSupport struct {
+	Supported  bool `json:"supported"`
+	MaxResults int  `json:"maxResults"`
+}
+
+// SCIMBulkSupport menjelaskan dukungan operasi bulk.
+type SCIMBulkSupport struct {
+	Supported      bool `json:"supported"`
+	MaxOperations  int  `json:"maxOperations"`
+	MaxPayloadSize int  `json:"maxPayloadSize"`
+}
+
+// SCIMAuthenticationScheme menjelaskan cara klien provisioning mengautentikasi diri.
+type SCIMAuthenticationScheme struct {
+	Type        string `json:"type"`
+	Name        string `json:"name"`
+	Description string `json:"description"`
+	Primary     bool   `json:"primary"`
+}
+
+// SCIMServiceProviderConfig adalah dokumen /ServiceProviderConfig (RFC 7643 bagian 5).
+type SCIMServiceProviderConfig struct {
+	Schemas               []string                   `json:"schemas"`
+	Patch                 SCIMSupported              `json:"patch"`
+	Bulk                  SCIMBulkSupport            `json:"bulk"`
+	Filter                SCIMFilterSupport          `json:"filter"`
+	ChangePassword        SCIMSupported              `json:"changePassword"`
+	Sort                  SCIMSupported              `json:"sort"`
+	ETag                  SCIMSupported              `json:"etag"`
+	AuthenticationSchemes []SCIMAuthenticationScheme `json:"authenticationSchemes"`
+	Meta                  SCIMMeta                   `json:"meta"`
+}
+
+// SCIMResourceType adalah dokumen /ResourceTypes (RFC 7643 bagian 6).
+type SCIMResourceType struct {
+	Schemas     []string `json:"schemas"`
+	ID          string   `json:"id"`
+	Name        string   `json:"name"`
+	Endpoint    string   `json:"endpoint"`
+	Description string   `json:"description"`
+	Schema      string   `json:"schema"`
+	Meta        SCIMMeta `json:"meta"`
+}
+
+// SCIMAttribute adalah definisi atribut pada dokumen /Schemas (RFC 7643 bagian 7).
+type SCIMAttribute struct {
+	Name           string          `json:"name"`
+	Type           string          `json:"type"`
+	MultiValued    bool            `json:"multiValued"`
+	Description    string          `json:"description"`
+	Required       bool            `json:"required"`
+	CaseExact      bool            `json:"caseExact"`
+	Mutability     string          `json:"mutability"`
+	Returned       string          `json:"returned"`
+	Uniqueness     string          `json:"uniqueness"`
+	ReferenceTypes []string        `json:"referenceTypes,omitempty"`
+	SubAttributes  []SCIMAttribute `json:"subAttributes,omitempty"`
+}
+
+// SCIMSchema adalah definisi skema resource pada dokumen /Schemas.
+type SCIMSchema struct {
+	Schemas     []string        `json:"schemas"`
+	ID          string          `json:"id"`
+	Name        string          `json:"name"`
+	Description string          `json:"description"`
+	Attributes  []SCIMAttribute `json:"attributes"`
+	Meta        SCIMMeta        `json:"meta"`
+}
+
+// ServiceProviderConfig mengembalikan fitur SCIM yang didukung server.
+func (i *SCIMInteractor) ServiceProviderConfig() *SCIMServiceProviderConfig {
+	return &SCIMServiceProviderConfig{
+		Schemas:        []string{SCIMSchemaServiceProviderConfig},
+		Patch:          SCIMSupported{Supported: true},
+		Filter:         SCIMFilterSupport{Supported: true, MaxResults: i.policy.MaxResults},
+		ChangePassword: SCIMSupported{Supported: true},
+		AuthenticationSchemes: []SCIMAuthenticationScheme{{
+			Type:        "oauthbearertoken",
+			Name:        "Bearer Token",
+			Description: "API key service account dengan scope scim:provision, dikirim sebagai Authorization: Bearer",
+			Primary:     true,
+		}},
+		Meta: SCIMMeta{
+			ResourceType: "ServiceProviderConfig",
+			Location:     i.policy.BaseURL + "/scim/v2/ServiceProviderConfig",
+		},
+	}
+}
+
+// ResourceTypes mengembalikan resource yang bisa di-provision.
+func (i *SCIMInteractor) ResourceTypes() []SCIMResourceType {
+	return []SCIMResourceType{
+		i.resourceType("User", "/Users", "Akun pengguna", SCIMSchemaUser),
+		i.resourceType("Group", "/Groups", "Grup, dipetakan ke Role", SCIMSchemaGroup),
+	}
+}
+
+// ResourceType mengembalikan satu resource type berdasarkan ID-nya.
+func (i *SCIMInteractor) ResourceType(id string) (*SCIMResourceType, error) {
+	for _, resourceType := range i.ResourceTypes() {
+		if resourceType.ID == id {
+			return &resourceType, nil
+		}
+	}
+	return nil, ErrSCIMResourceTypeNotFound
+}
+
+// Schemas mengembalikan definisi atribut User dan Group yang didukung.
+func (i *SCIMInteractor) Schemas() []SCIMSchema {
+	return []SCIMSchema{
+		i.schema(SCIMSchemaUser, "User", "Akun pengguna", []SCIMAttribute{
+			scimAttribute("userName", "string", "Nama unik untuk login", true, "readWrite", "server"),
+			scimComplexAttribute("name", false, "Nama pengguna", "readWrite", []SCIMAttribute{
+				scimAttribute("formatted", "string", "Nama lengkap", false, "readOnly", "none"),
+				scimAttribute("givenName", "string", "Nama depan", false, "readWrite", "none"),
+				scimAttribute("familyName", "string", "Nama belakang", false, "readWrite", "none"),
+			}),
+			scimAttribute("displayName", "string", "Nama tampilan, dibentuk dari name atau userName", false, "readOnly", "none"),
+			scimComplexAttribute("emails", true, "Alamat email; hanya email primary yang disimpan", "readWrite", []SCIMAttribute{
+				scimAttribute("value", "string", "Alamat email", true, "readWrite", "server"),
+				scimAttribute("type", "string", "Jenis email", false, "readWrite", "none"),
+				scimAttribute("primary", "boolean", "Email utama", false, "readWrite", "none"),
+			}),
+			scimAttribute("active", "boolean", "Pengguna bisa login", false, "readWrite", "none"),
+			{
+				Name:        "password",
+				Type:        "string",
+				Description: "Password awal atau password baru",
+				Mutability:  "writeOnly",
+				Returned:    "never",
+				Uniqueness:  "none",
+			},
+			scimComplexAttribute("groups", true, "Grup pengguna; diubah lewat resource Group", "readOnly", []SCIMAttribute{
+				scimAttribute("value", "string", "ID grup", false, "readOnly", "none"),
+				scimAttribute("display", "string", "Nama grup", false, "readOnly", "none"),
+				scimReferenceAttribute("Group"),
+			}),
+		}),
+		i.schema(SCIMSchemaGroup, "Group", "Grup, dipetakan ke Role", []SCIMAttribute{
+			scimAttribute("displayName", "string", "Nama Role", true, "readWrite", "server"),
+			scimComplexAttribute("members", true, "Anggota grup", "readWrite", []SCIMAttribute{
+				scimAttribute("value", "string", "ID pengguna", false, "immutable", "none"),
+				scimAttribute("display", "string", "Username anggota", false, "readOnly", "none"),
+				scimReferenceAttribute("User"),
+			}),
+		}),
+	}
+}
+
+// Schema mengembalikan satu definisi skema berdasarkan URN-nya.
+func (i *SCIMInteractor) Schema(id string) (*SCIMSchema, error) {
+	for _, schema := range i.Schemas() {
+		if schema.ID == id {
+			return &schema, nil
+		}
+	}
+	return nil, ErrSCIMSchemaNotFound
+}
+
+func (i *SCIMInteractor) resourceType(name, endpoint, description, schema string) SCIMResourceType {
+	return SCIMResourceType{
+		Schemas:     []string{SCIMSchemaResourceType},
+		ID:          name,
+		Name:        name,
+		Endpoint:    endpoint,
+		Description: description,
+		Schema:      schema,
+		Meta: SCIMMeta{
+			ResourceType: "ResourceType",
+			Location:     i.policy.BaseURL + "/scim/v2/ResourceTypes/" + name,
+		},
+	}
+}
+
+func (i *SCIMInteractor) schema(id, name, description string, attributes []SCIMAttribute) SCIMSchema {
+	return SCIMSchema{
+		Schemas:     []string{SCIMSchemaSchema},
+		ID:          id,
+		Name:        name,
+		Description: description,
+		Attributes:  attributes,
+		Meta: SCIMMeta{
+			ResourceType: "Schema",
+			Location:     i.policy.BaseURL + "/scim/v2/Schemas/" + id,
+		},
+	}
+}
+
+func scimAttribute(name, kind, description string, required bool, mutability, uniqueness string) SCIMAttribute {
+	return SCIMAttribute{
+		Name:        name,
+		Type:        kind,
+		Description: description,
+		Required:    required,
+		Mutability:  mutability,
+		Returned:    "default",
+		Uniqueness:  uniqueness,
+	}
+}
+
+func scimComplexAttribute(name string, multiValued bool, description, mutability string, sub []SCIMAttribute) SCIMAttribute {
+	attribute := scimAttribute(name, "complex", description, false, mutability, "none")
+	attribute.MultiValued = multiValued
+	attribute.SubAttributes = sub
+	return attribute
+}
+
+func scimReferenceAttribute(referenceType string) SCIMAttribute {
+	attribute := scimAttribute("$ref", "reference", "URI resource "+referenceType, false, "readOnly", "none")
+	attribute.ReferenceTypes = []string{referenceType}
+	return attribute
+}
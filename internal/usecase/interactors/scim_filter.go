@@ -0,0 +1,498 @@
+package interactors
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// scimFilter adalah ekspresi filter SCIM (RFC 7644 bagian 3.4.2.2) yang sudah di-parse.
+// Ekspresi dievaluasi terhadap representasi JSON resource, sehingga atribut yang bisa difilter
+// sama dengan atribut yang dikembalikan.
+type scimFilter interface {
+	match(resource map[string]any) bool
+}
+
+// scimLogical adalah gabungan dua ekspresi dengan "and" atau "or".
+type scimLogical struct {
+	or          bool
+	left, right scimFilter
+}
+
+func (f scimLogical) match(resource map[string]any) bool {
+	if f.or {
+		return f.left.match(resource) || f.right.match(resource)
+	}
+	return f.left.match(resource) && f.right.match(resource)
+}
+
+// scimNot membalik hasil ekspresi di dalam "not (...)".
+type scimNot struct {
+	inner scimFilter
+}
+
+func (f scimNot) match(resource map[string]any) bool {
+	return !f.inner.match(resource)
+}
+
+// scimComparison membandingkan atribut dengan nilai, misalnya userName eq "budi".
+type scimComparison struct {
+	path  []string
+	op    string // eq, ne, co, sw, ew, gt, ge, lt, le atau pr
+	value any    // string, float64, bool atau nil
+}
+
+func (f scimComparison) match(resource map[string]any) bool {
+	values := scimValues(resource, f.path)
+	switch f.op {
+	case "pr":
+		for _, value := range values {
+			if !scimIsEmpty(value) {
+				return true
+			}
+		}
+		return false
+	case "ne":
+		return !scimComparison{path: f.path, op: "eq", value: f.value}.match(resource)
+	}
+
+	if f.op == "eq" && f.value == nil {
+		return len(values) == 0
+	}
+	for _, value := range values {
+		if scimCompare(value, f.op, f.value) {
+			return true
+		}
+	}
+	return false
+}
+
+// scimValuePath memfilter elemen atribut multi-valued, misalnya emails[type eq "work"].
+type scimValuePath struct {
+	attribute string
+	filter    scimFilter
+}
+
+func (f scimValuePath) match(resource map[string]any) bool {
+	for _, element := range scimElements(resource, f.attribute) {
+		if f.filter.match(element) {
+			return true
+		}
+	}
+	return false
+}
+
+// scimPath adalah target operasi PATCH: attr, attr.sub, attr[filter] atau attr[filter].sub.
+type scimPath struct {
+	attribute string
+	filter    scimFilter // nil jika tidak ada filter nilai
+	sub       string
+}
+
+// parseSCIMFilter mem-parse teks filter SCIM. Filter kosong menghasilkan nil.
+func parseSCIMFilter(text string) (scimFilter, error) {
+	if strings.TrimSpace(text) == "" {
+		return nil, nil
+	}
+	tokens, err := scimTokenize(text)
+	if err != nil {
+		return nil, err
+	}
+
+	p := &scimParser{tokens: tokens}
+	filter, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if !p.done() {
+		return nil, fmt.Errorf("%w: token %q tidak diharapkan", ErrSCIMInvalidFilter, p.peek().text)
+	}
+	return filter, nil
+}
+
+// parseSCIMPath mem-parse atribut "path" pada operasi PATCH (RFC 7644 bagian 3.5.2).
+func parseSCIMPath(text string) (*scimPath, error) {
+	text = strings.TrimSpace(text)
+	if text == "" {
+		return nil, fmt.Errorf("%w: path kosong", ErrSCIMInvalidPath)
+	}
+
+	open := strings.IndexByte(text, '[')
+	if open < 0 {
+		path := scimAttributePath(text)
+		if len(path) > 2 {
+			return nil, fmt.Errorf("%w: %s", ErrSCIMInvalidPath, text)
+		}
+		result := &scimPath{attribute: path[0]}
+		if len(path) == 2 {
+			result.sub = path[1]
+		}
+		return result, nil
+	}
+
+	closing := strings.LastIndexByte(text, ']')
+	if closing < open {
+		return nil, fmt.Errorf("%w: %s", ErrSCIMInvalidPath, text)
+	}
+	filter, err := parseSCIMFilter(text[open+1 : closing])
+	if err != nil || filter == nil {
+		return nil, fmt.Errorf("%w: %s", ErrSCIMInvalidPath, text)
+	}
+
+	result := &scimPath{attribute: scimAttributePath(text[:open])[0], filter: filter}
+	if rest := text[closing+1:]; rest != "" {
+		sub, ok := strings.CutPrefix(rest, ".")
+		if !ok || sub == "" || strings.Contains(sub, ".") {
+			return nil, fmt.Errorf("%w: %s", ErrSCIMInvalidPath, text)
+		}
+		result.sub = sub
+	}
+	return result, nil
+}
+
+// scimAttributePath memecah nama atribut menjadi bagian-bagiannya dan membuang awalan URN skema,
+// misalnya "urn:ietf:params:scim:schemas:core:2.0:User:name.givenName" menjadi [name givenName].
+func scimAttributePath(attribute string) []string {
+	if strings.HasPrefix(strings.ToLower(attribute), "urn:") {
+		attribute = attribute[strings.LastIndexByte(attribute, ':')+1:]
+	}
+	return strings.Split(attribute, ".")
+}
+
+// scimToken adalah satu token filter; literal string sudah di-decode.
+type scimToken struct {
+	text    string
+	literal bool
+}
+
+// scimTokenize memecah filter menjadi kata, tanda kurung dan literal string JSON.
+func scimTokenize(text string) ([]scimToken, error) {
+	var tokens []scimToken
+	for pos := 0; pos < len(text); {
+		char := text[pos]
+		switch {
+		case char == ' ' || char == '\t' || char == '\n' || char == '\r':
+			pos++
+		case char == '(' || char == ')' || char == '[' || char == ']':
+			tokens = append(tokens, scimToken{text: string(char)})
+			pos++
+		case char == '"':
+			end := pos + 1
+			for end < len(text) && text[end] != '"' {
+				if text[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(text) {
+				return nil, fmt.Errorf("%w: string tidak ditutup", ErrSCIMInvalidFilter)
+			}
+			var value string
+			if err := json.Unmarshal([]byte(text[pos:end+1]), &value); err != nil {
+				return nil, fmt.Errorf("%w: string tidak valid", ErrSCIMInvalidFilter)
+			}
+			tokens = append(tokens, scimToken{text: value, literal: true})
+			pos = end + 1
+		default:
+			end := pos
+			for end < len(text) && !strings.ContainsRune(" \t\n\r()[]\"", rune(text[end])) {
+				end++
+			}
+			tokens = append(tokens, scimToken{text: text[pos:end]})
+			pos = end
+		}
+	}
+	return tokens, nil
+}
+
+// scimParser adalah parser recursive descent untuk tata bahasa filter SCIM:
+//
+//	filter     = orExpr
+//	orExpr     = andExpr *("or" andExpr)
+//	andExpr    = unary *("and" unary)
+//	unary      = "not" "(" filter ")" / "(" filter ")" / attrPath "[" filter "]" / attrExp
+//	attrExp    = attrPath "pr" / attrPath compareOp compValue
+type scimParser struct {
+	tokens []scimToken
+	pos    int
+}
+
+func (p *scimParser) done() bool {
+	return p.pos >= len(p.tokens)
+}
+
+func (p *scimParser) peek() scimToken {
+	if p.done() {
+		return scimToken{}
+	}
+	return p.tokens[p.pos]
+}
+
+// keyword mengonsumsi token berikutnya jika sama dengan kata kunci tersebut (tanpa membedakan huruf).
+func (p *scimParser) keyword(word string) bool {
+	token := p.peek()
+	if p.done() || token.literal || !strings.EqualFold(token.text, word) {
+		return false
+	}
+	p.pos++
+	return true
+}
+
+func (p *scimParser) expect(symbol string) error {
+	if !p.keyword(symbol) {
+		return fmt.Errorf("%w: %q diharapkan", ErrSCIMInvalidFilter, symbol)
+	}
+	return nil
+}
+
+func (p *scimParser) parseOr() (scimFilter, error) {
+	left, err := p.parseAnd()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("or") {
+		right, err := p.parseAnd()
+		if err != nil {
+			return nil, err
+		}
+		left = scimLogical{or: true, left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimParser) parseAnd() (scimFilter, error) {
+	left, err := p.parseUnary()
+	if err != nil {
+		return nil, err
+	}
+	for p.keyword("and") {
+		right, err := p.parseUnary()
+		if err != nil {
+			return nil, err
+		}
+		left = scimLogical{left: left, right: right}
+	}
+	return left, nil
+}
+
+func (p *scimParser) parseUnary() (scimFilter, error) {
+	if p.keyword("not") {
+		if err := p.expect("("); err != nil {
+			return nil, err
+		}
+		inner, err := p.parseGroup(")")
+		if err != nil {
+			return nil, err
+		}
+		return scimNot{inner: inner}, nil
+	}
+	if p.keyword("(") {
+		return p.parseGroup(")")
+	}
+
+	attribute := p.peek()
+	if p.done() || attribute.literal || !isSCIMAttributeName(attribute.text) {
+		return nil, fmt.Errorf("%w: nama atribut diharapkan", ErrSCIMInvalidFilter)
+	}
+	p.pos++
+
+	if p.keyword("[") {
+		inner, err := p.parseGroup("]")
+		if err != nil {
+			return nil, err
+		}
+		return scimValuePath{attribute: scimAttributePath(attribute.text)[0], filter: inner}, nil
+	}
+
+	operator := p.peek()
+	if p.done() || operator.literal {
+		return nil, fmt.Errorf("%w: operator diharapkan setelah %s", ErrSCIMInvalidFilter, attribute.text)
+	}
+	p.pos++
+
+	op := strings.ToLower(operator.text)
+	switch op {
+	case "pr":
+		return scimComparison{path: scimAttributePath(attribute.text), op: op}, nil
+	case "eq", "ne", "co", "sw", "ew", "gt", "ge", "lt", "le":
+	default:
+		return nil, fmt.Errorf("%w: operator %q tidak dikenal", ErrSCIMInvalidFilter, operator.text)
+	}
+
+	if p.done() {
+		return nil, fmt.Errorf("%w: nilai diharapkan setelah %s", ErrSCIMInvalidFilter, operator.text)
+	}
+	value, err := scimLiteral(p.peek())
+	if err != nil {
+		return nil, err
+	}
+	p.pos++
+	return scimComparison{path: scimAttributePath(attribute.text), op: op, value: value}, nil
+}
+
+// parseGroup mem-parse filter di dalam tanda kurung sampai simbol penutupnya.
+func (p *scimParser) parseGroup(closing string) (scimFilter, error) {
+	inner, err := p.parseOr()
+	if err != nil {
+		return nil, err
+	}
+	if err := p.expect(closing); err != nil {
+		return nil, err
+	}
+	return inner, nil
+}
+
+// scimLiteral mengubah token nilai perbandingan menjadi string, angka, boolean atau null.
+func scimLiteral(token scimToken) (any, error) {
+	if token.literal {
+		return token.text, nil
+	}
+	switch strings.ToLower(token.text) {
+	case "true":
+		return true, nil
+	case "false":
+		return false, nil
+	case "null":
+		return nil, nil
+	}
+	number, err := strconv.ParseFloat(token.text, 64)
+	if err != nil {
+		return nil, fmt.Errorf("%w: nilai %q tidak valid", ErrSCIMInvalidFilter, token.text)
+	}
+	return number, nil
+}
+
+// isSCIMAttributeName mengembalikan true jika teks bisa menjadi nama atribut (dengan URN dan sub-atribut).
+func isSCIMAttributeName(text string) bool {
+	if text == "" || !unicode.IsLetter(rune(text[0])) {
+		return false
+	}
+	for _, char := range text {
+		if !unicode.IsLetter(char) && !unicode.IsDigit(char) && !strings.ContainsRune(".:_-$", char) {
+			return false
+		}
+	}
+	return true
+}
+
+// scimLookup mencari kunci pada objek JSON tanpa membedakan huruf besar dan kecil,
+// karena nama atribut SCIM bersifat case-insensitive.
+func scimLookup(object map[string]any, name string) (string, any, bool) {
+	if value, ok := object[name]; ok {
+		return name, value, true
+	}
+	for key, value := range object {
+		if strings.EqualFold(key, name) {
+			return key, value, true
+		}
+	}
+	return "", nil, false
+}
+
+// scimElements mengembalikan elemen objek dari atribut multi-valued.
+func scimElements(resource map[string]any, attribute string) []map[string]any {
+	_, value, _ := scimLookup(resource, attribute)
+	list, _ := value.([]any)
+	elements := make([]map[string]any, 0, len(list))
+	for _, item := range list {
+		if element, ok := item.(map[string]any); ok {
+			elements = append(elements, element)
+		}
+	}
+	return elements
+}
+
+// scimValues mengumpulkan semua nilai di ujung path. Atribut multi-valued menghasilkan satu nilai per
+// elemen, dan elemen kompleks tanpa sub-atribut dibandingkan lewat sub-atribut "value"-nya.
+func scimValues(node any, path []string) []any {
+	switch current := node.(type) {
+	case []any:
+		var values []any
+		for _, item := range current {
+			values = append(values, scimValues(item, path)...)
+		}
+		return values
+	case map[string]any:
+		if len(path) == 0 {
+			path = []string{"value"}
+		}
+		_, value, ok := scimLookup(current, path[0])
+		if !ok {
+			return nil
+		}
+		return scimValues(value, path[1:])
+	case nil:
+		return nil
+	default:
+		if len(path) > 0 {
+			return nil
+		}
+		return []any{current}
+	}
+}
+
+// scimIsEmpty mengembalikan true untuk nilai yang dianggap tidak ada oleh operator "pr".
+func scimIsEmpty(value any) bool {
+	switch v := value.(type) {
+	case nil:
+		return true
+	case string:
+		return v == ""
+	default:
+		return false
+	}
+}
+
+// scimCompare membandingkan satu nilai atribut dengan nilai di filter. String dibandingkan tanpa
+// membedakan huruf besar dan kecil; tanggal dibandingkan sebagai string RFC 3339 dengan zona yang sama.
+func scimCompare(actual any, op string, expected any) bool {
+	switch want := expected.(type) {
+	case string:
+		got, ok := actual.(string)
+		if !ok {
+			return false
+		}
+		got, want = strings.ToLower(got), strings.ToLower(want)
+		switch op {
+		case "eq":
+			return got == want
+		case "co":
+			return strings.Contains(got, want)
+		case "sw":
+			return strings.HasPrefix(got, want)
+		case "ew":
+			return strings.HasSuffix(got, want)
+		case "gt":
+			return got > want
+		case "ge":
+			return got >= want
+		case "lt":
+			return got < want
+		case "le":
+			return got <= want
+		}
+	case float64:
+		got, ok := actual.(float64)
+		if !ok {
+			return false
+		}
+		switch op {
+		case "eq":
+			return got == want
+		case "gt":
+			return got > want
+		case "ge":
+			return got >= want
+		case "lt":
+			return got < want
+		case "le":
+			return got <= want
+		}
+	case bool:
+		got, ok := actual.(bool)
+		return ok && op == "eq" && got == want
+	}
+	return false
+}
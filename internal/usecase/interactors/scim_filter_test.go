@@ -0,0 +1,120 @@
+package interactors
+
+import (
+	"errors"
+	"testing"
+)
+
+// scimTestUser adalah representasi JSON User SCIM untuk pengujian filter.
+func scimTestUser() map[string]any {
+	return map[string]any{
+		"userName": "budi",
+		"active":   true,
+		"name":     map[string]any{"givenName": "Budi", "familyName": "Santoso"},
+		"emails": []any{
+			map[string]any{"value": "budi@kantor.test", "type": "work", "primary": true},
+			map[string]any{"value": "budi@rumah.test", "type": "home"},
+		},
+		"meta": map[string]any{"created": "2024-03-01T00:00:00Z"},
+	}
+}
+
+func TestSCIMFilterMatch(t *testing.T) {
+	tests := []struct {
+		filter string
+		want   bool
+	}{
+		{`userName eq "BUDI"`, true},
+		{`USERNAME EQ "budi"`, true},
+		{`urn:ietf:params:scim:schemas:core:2.0:User:userName eq "budi"`, true},
+		{`userName ne "budi"`, false},
+		{`name.givenName sw "bu" and name.familyName ew "TOSO"`, true},
+		{`title pr`, false},
+		{`name.givenName pr`, true},
+		{`title eq null`, true},
+		{`active eq false`, false},
+		{`meta.created gt "2024-01-01T00:00:00Z"`, true},
+		{`emails.value co "@rumah"`, true},
+		{`emails co "@kantor"`, true},
+
+		// and lebih kuat dari or: true or (false and false)
+		{`userName eq "budi" or userName eq "andi" and active eq false`, true},
+		// (false and true) or true
+		{`userName eq "andi" and active eq true or name.givenName eq "budi"`, true},
+		// Tanda kurung mengubah urutan: (true or false) and false
+		{`(userName eq "budi" or userName eq "andi") and active eq false`, false},
+		// not hanya berlaku untuk ekspresi di dalam kurungnya
+		{`not (userName eq "budi") or active eq false`, false},
+		{`not (userName eq "andi") and active eq true`, true},
+		{`not (userName eq "budi" or active eq false)`, false},
+
+		// Filter nilai harus cocok pada elemen yang sama
+		{`emails[type eq "work" and value co "@kantor"]`, true},
+		{`emails[type eq "work" and value co "@rumah"]`, false},
+		{`emails[type eq "home"] and not (emails[primary eq true and type eq "home"])`, true},
+	}
+	for _, tt := range tests {
+		filter, err := parseSCIMFilter(tt.filter)
+		if err != nil {
+			t.Errorf("parseSCIMFilter(%q): %v", tt.filter, err)
+			continue
+		}
+		if got := filter.match(scimTestUser()); got != tt.want {
+			t.Errorf("%s = %v, ingin %v", tt.filter, got, tt.want)
+		}
+	}
+}
+
+func TestSCIMFilterRejectsInvalid(t *testing.T) {
+	for _, filter := range []string{
+		`userName eq`,
+		`userName like "budi"`,
+		`(userName eq "budi"`,
+		`userName eq "budi")`,
+		`userName eq "budi" active eq true`,
+		`not userName eq "budi"`,
+		`emails[type eq "work"`,
+		`"budi" eq userName`,
+		`userName eq "budi`,
+		`userName eq budi`,
+		`userName eq "budi" and`,
+	} {
+		if _, err := parseSCIMFilter(filter); !errors.Is(err, ErrSCIMInvalidFilter) {
+			t.Errorf("parseSCIMFilter(%q): err = %v, ingin ErrSCIMInvalidFilter", filter, err)
+		}
+	}
+	if filter, err := parseSCIMFilter("  "); filter != nil || err != nil {
+		t.Errorf("filter kosong = %v, %v; ingin nil", filter, err)
+	}
+}
+
+func TestParseSCIMPath(t *testing.T) {
+	tests := []struct {
+		path      string
+		attribute string
+		sub       string
+		filtered  bool
+	}{
+		{"active", "active", "", false},
+		{"name.givenName", "name", "givenName", false},
+		{"urn:ietf:params:scim:schemas:core:2.0:User:name.familyName", "name", "familyName", false},
+		{`emails[type eq "work"]`, "emails", "", true},
+		{`emails[type eq "work"].value`, "emails", "value", true},
+	}
+	for _, tt := range tests {
+		path, err := parseSCIMPath(tt.path)
+		if err != nil {
+			t.Errorf("parseSCIMPath(%q): %v", tt.path, err)
+			continue
+		}
+		if path.attribute != tt.attribute || path.sub != tt.sub || (path.filter != nil) != tt.filtered {
+			t.Errorf("parseSCIMPath(%q) = %+v, ingin %s.%s", tt.path, path, tt.attribute, tt.sub)
+		}
+	}
+
+	for _, path := range []string{"", "name.givenName.first", `emails[]`, `emails[type eq "work"]value`, `emails[type eq "work"].value.x`, `emails]type eq "work"[`} {
+		if _, err := parseSCIMPath(path); !errors.Is(err, ErrSCIMInvalidPath) {
+			t.Errorf("parseSCIMPath(%q): err = %v, ingin ErrSCIMInvalidPath", path, err)
+		}
+	}
+}
@@ -0,0 +1,1018 @@
+package interactors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// URN skema dan pesan SCIM 2.0 (RFC 7643 dan RFC 7644).
+const (
+	SCIMSchemaUser                  = "urn:ietf:params:scim:schemas:core:2.0:User"
+	SCIMSchemaGroup                 = "urn:ietf:params:scim:schemas:core:2.0:Group"
+	SCIMSchemaServiceProviderConfig = "urn:ietf:params:scim:schemas:core:2.0:ServiceProviderConfig"
+	SCIMSchemaResourceType          = "urn:ietf:params:scim:schemas:core:2.0:ResourceType"
+	SCIMSchemaSchema                = "urn:ietf:params:scim:schemas:core:2.0:Schema"
+	SCIMMessageListResponse         = "urn:ietf:params:scim:api:messages:2.0:ListResponse"
+	SCIMMessagePatchOp              = "urn:ietf:params:scim:api:messages:2.0:PatchOp"
+	SCIMMessageError                = "urn:ietf:params:scim:api:messages:2.0:Error"
+)
+
+var (
+	ErrSCIMUserNotFound         = errors.New("pengguna SCIM tidak ditemukan")
+	ErrSCIMGroupNotFound        = errors.New("grup SCIM tidak ditemukan")
+	ErrSCIMSchemaNotFound       = errors.New("skema SCIM tidak ditemukan")
+	ErrSCIMResourceTypeNotFound = errors.New("resource type SCIM tidak ditemukan")
+	ErrSCIMInvalidFilter        = errors.New("filter SCIM tidak valid")
+	ErrSCIMInvalidPath          = errors.New("path PATCH SCIM tidak valid")
+	ErrSCIMInvalidSyntax        = errors.New("permintaan SCIM tidak valid")
+	ErrSCIMInvalidValue         = errors.New("nilai atribut SCIM tidak valid")
+	ErrSCIMNoTarget             = errors.New("path PATCH SCIM tidak menunjuk atribut mana pun")
+	ErrSCIMUniqueness           = errors.New("nilai atribut SCIM sudah dipakai")
+	ErrSCIMSuperuserProtected   = errors.New("superuser tidak dapat diubah lewat SCIM")
+)
+
+// SCIMName adalah atribut kompleks "name" pada resource User.
+type SCIMName struct {
+	Formatted  string `json:"formatted,omitempty"`
+	GivenName  string `json:"givenName,omitempty"`
+	FamilyName string `json:"familyName,omitempty"`
+}
+
+// SCIMValue adalah elemen atribut multi-valued seperti emails, groups dan members.
+type SCIMValue struct {
+	Value   string `json:"value"`
+	Display string `json:"display,omitempty"`
+	Type    string `json:"type,omitempty"`
+	Primary bool   `json:"primary,omitempty"`
+	Ref     string `json:"$ref,omitempty"`
+}
+
+// SCIMMeta adalah atribut "meta" yang dikelola server.
+type SCIMMeta struct {
+	ResourceType string     `json:"resourceType"`
+	Created      *time.Time `json:"created,omitempty"`
+	LastModified *time.Time `json:"lastModified,omitempty"`
+	Location     string     `json:"location"`
+}
+
+// SCIMUser adalah representasi SCIM dari entities.User.
+// Password hanya diterima sebagai masukan dan tidak pernah dikembalikan.
+type SCIMUser struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	ExternalID  string      `json:"externalId,omitempty"` // Disimpan per klien provisioning
+	UserName    string      `json:"userName"`
+	Name        *SCIMName   `json:"name,omitempty"`
+	DisplayName string      `json:"displayName,omitempty"`
+	Emails      []SCIMValue `json:"emails,omitempty"`
+	Active      *bool       `json:"active,omitempty"`
+	Password    string      `json:"password,omitempty"`
+	Groups      []SCIMValue `json:"groups,omitempty"` // Hanya baca; keanggotaan diubah lewat Group
+	Meta        *SCIMMeta   `json:"meta,omitempty"`
+}
+
+// SCIMGroup adalah representasi SCIM dari entities.Role.
+type SCIMGroup struct {
+	Schemas     []string    `json:"schemas"`
+	ID          string      `json:"id,omitempty"`
+	DisplayName string      `json:"displayName"`
+	Members     []SCIMValue `json:"members,omitempty"`
+	Meta        *SCIMMeta   `json:"meta,omitempty"`
+}
+
+// SCIMListResponse adalah hasil query resource dengan pagination berbasis indeks (RFC 7644 bagian 3.4.2).
+type SCIMListResponse struct {
+	Schemas      []string `json:"schemas"`
+	TotalResults int      `json:"totalResults"`
+	StartIndex   int      `json:"startIndex"`
+	ItemsPerPage int      `json:"itemsPerPage"`
+	Resources    []any    `json:"Resources"`
+}
+
+// SCIMPatchOperation adalah satu operasi di dalam permintaan PatchOp.
+type SCIMPatchOperation struct {
+	Op    string `json:"op"`
+	Path  string `json:"path,omitempty"`
+	Value any    `json:"value,omitempty"`
+}
+
+// SCIMQuery adalah parameter query daftar resource.
+type SCIMQuery struct {
+	Filter     string
+	StartIndex int // Berbasis 1; nilai di bawah 1 dianggap 1
+	Count      int // Negatif berarti SCIMPolicy.MaxResults
+}
+
+// SCIMPolicy mengatur endpoint SCIM.
+type SCIMPolicy struct {
+	BaseURL    string // URL publik tempat /scim/v2 dilayani, untuk meta.location
+	MaxResults int    // Batas jumlah resource per halaman
+}
+
+// SCIMInteractor adalah use case provisioning SCIM 2.0: User dipetakan ke entities.User dan
+// Group ke entities.Role yang ditandai SCIMManaged, yaitu Role yang dibuat lewat SCIM atau yang diizinkan
+// admin. Role lain tidak terlihat lewat SCIM sehingga klien tidak bisa menghapus, mengganti nama atau
+// menambah anggota Role istimewa. Setiap klien provisioning adalah service account dengan API key-nya sendiri;
+// externalId yang dikirim klien disimpan sebagai LinkedIdentity milik klien tersebut.
+// Service account tidak pernah terlihat lewat SCIM agar klien tidak bisa mengubah kredensialnya sendiri.
+// Anggota tidak bisa ditambahkan ke grup yang Role-nya punya kebijakan persetujuan atau jika melanggar
+// aturan pemisahan tugas.
+type SCIMInteractor struct {
+	userRepo     repositories.UserRepository
+	roleRepo     repositories.RoleRepository
+	identityRepo repositories.LinkedIdentityRepository
+	users        *UserInteractor
+	sessions     *SessionInteractor
+	grants       *RoleGrantInteractor
+	policy       SCIMPolicy
+}
+
+// NewSCIMInteractor membuat instance baru dari SCIMInteractor.
+func NewSCIMInteractor(
+	ur repositories.UserRepository,
+	rr repositories.RoleRepository,
+	ir repositories.LinkedIdentityRepository,
+	users *UserInteractor,
+	sessions *SessionInteractor,
+	grants *RoleGrantInteractor,
+	policy SCIMPolicy,
+) *SCIMInteractor {
+	return &SCIMInteractor{userRepo: ur, roleRepo: rr, identityRepo: ir, users: users, sessions: sessions, grants: grants, policy: policy}
+}
+
+// ListUsers mengembalikan pengguna yang cocok dengan filter. Filter dievaluasi di memori terhadap
+// representasi SCIM sehingga semua atribut yang dikembalikan bisa difilter.
+func (i *SCIMInteractor) ListUsers(client uuid.UUID, query SCIMQuery) (*SCIMListResponse, error) {
+	filter, err := parseSCIMFilter(query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	users, err := i.userRepo.FindAllWithRoles()
+	if err != nil {
+		return nil, err
+	}
+	identities, err := i.identityRepo.FindByProvider(scimProvider(client))
+	if err != nil {
+		return nil, err
+	}
+	externalIDs := make(map[uuid.UUID]string, len(identities))
+	for _, identity := range identities {
+		externalIDs[identity.UserID] = identity.Subject
+	}
+
+	var resources []any
+	for n := range users {
+		if users[n].IsServiceAccount {
+			continue
+		}
+		resource := i.userResource(&users[n], externalIDs[users[n].ID])
+		if filter != nil && !filter.match(scimObject(resource)) {
+			continue
+		}
+		resources = append(resources, resource)
+	}
+	return i.page(resources, query), nil
+}
+
+// GetUser mengembalikan satu pengguna.
+func (i *SCIMInteractor) GetUser(client, id uuid.UUID) (*SCIMUser, error) {
+	user, err := i.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := i.externalIdentity(client, user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return i.userResource(user, identity.Subject), nil
+}
+
+// CreateUser membuat pengguna dari resource SCIM. Tanpa password, password diisi acak sehingga
+// pengguna hanya bisa login lewat SSO atau reset password.
+func (i *SCIMInteractor) CreateUser(client uuid.UUID, input *SCIMUser) (*SCIMUser, error) {
+	user := &entities.User{}
+	if err := i.applyUser(user, input); err != nil {
+		return nil, err
+	}
+	externalID := strings.TrimSpace(input.ExternalID)
+	if err := i.checkExternalID(client, uuid.Nil, externalID); err != nil {
+		return nil, err
+	}
+
+	password := input.Password
+	if password == "" {
+		generated, err := randomString(apiKeySecretLength)
+		if err != nil {
+			return nil, err
+		}
+		password = generated
+	}
+	user.Password = password
+
+	active := user.IsActive
+	user, err := i.users.CreateUser(user)
+	if err != nil {
+		return nil, err
+	}
+	if !active {
+		// Kolom is_active memiliki default true sehingga nilai false diabaikan saat insert
+		user.IsActive = false
+		if user, err = i.userRepo.Update(user); err != nil {
+			return nil, err
+		}
+	}
+
+	if externalID != "" {
+		if err := i.identityRepo.Create(&entities.LinkedIdentity{
+			UserID:      user.ID,
+			Provider:    scimProvider(client),
+			Subject:     externalID,
+			Email:       user.Email,
+			Provisioned: input.Password == "",
+		}); err != nil {
+			return nil, err
+		}
+	}
+	return i.userResource(user, externalID), nil
+}
+
+// ReplaceUser mengganti atribut pengguna dengan resource SCIM (PUT).
+func (i *SCIMInteractor) ReplaceUser(ctx context.Context, client, id uuid.UUID, input *SCIMUser) (*SCIMUser, error) {
+	user, err := i.findWritableUser(id)
+	if err != nil {
+		return nil, err
+	}
+	return i.saveUser(ctx, client, user, input)
+}
+
+// PatchUser menerapkan operasi PatchOp ke pengguna. Klien seperti Azure AD mengirim active sebagai
+// string "False", sehingga nilai tersebut ikut diterima.
+func (i *SCIMInteractor) PatchUser(ctx context.Context, client, id uuid.UUID, operations []SCIMPatchOperation) (*SCIMUser, error) {
+	user, err := i.findWritableUser(id)
+	if err != nil {
+		return nil, err
+	}
+	identity, err := i.externalIdentity(client, user.ID)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := scimObject(i.userResource(user, identity.Subject))
+	if err := scimPatch(resource, operations); err != nil {
+		return nil, err
+	}
+	if key, value, ok := scimLookup(resource, "active"); ok {
+		if text, isString := value.(string); isString {
+			active, err := strconv.ParseBool(text)
+			if err != nil {
+				return nil, fmt.Errorf("%w: active harus boolean", ErrSCIMInvalidValue)
+			}
+			resource[key] = active
+		}
+	}
+
+	var input SCIMUser
+	if err := scimDecode(resource, &input); err != nil {
+		return nil, err
+	}
+	return i.saveUser(ctx, client, user, &input)
+}
+
+// DeleteUser menghapus pengguna dan mengakhiri semua sesinya.
+func (i *SCIMInteractor) DeleteUser(ctx context.Context, id uuid.UUID) error {
+	user, err := i.findWritableUser(id)
+	if err != nil {
+		return err
+	}
+	if err := i.userRepo.Delete(user.ID); err != nil {
+		return err
+	}
+	if _, err := i.sessions.RevokeAll(ctx, user.ID); err != nil {
+		log.Printf("Gagal mencabut sesi pengguna SCIM %s: %v", user.ID, err)
+	}
+	return nil
+}
+
+// ListGroups mengembalikan grup yang cocok dengan filter. withMembers false menghilangkan atribut members,
+// yang diminta klien lewat excludedAttributes=members untuk grup besar.
+func (i *SCIMInteractor) ListGroups(query SCIMQuery, withMembers bool) (*SCIMListResponse, error) {
+	filter, err := parseSCIMFilter(query.Filter)
+	if err != nil {
+		return nil, err
+	}
+
+	roles, err := i.roleRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	var resources []any
+	for n := range roles {
+		if !roles[n].SCIMManaged {
+			continue
+		}
+		resource := i.groupResource(&roles[n])
+		if filter != nil && !filter.match(scimObject(resource)) {
+			continue
+		}
+		if !withMembers {
+			resource.Members = nil
+		}
+		resources = append(resources, resource)
+	}
+	return i.page(resources, query), nil
+}
+
+// GetGroup mengembalikan satu grup.
+func (i *SCIMInteractor) GetGroup(id uuid.UUID, withMembers bool) (*SCIMGroup, error) {
+	role, err := i.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	resource := i.groupResource(role)
+	if !withMembers {
+		resource.Members = nil
+	}
+	return resource, nil
+}
+
+// CreateGroup membuat Role dari resource SCIM beserta anggotanya.
+func (i *SCIMInteractor) CreateGroup(input *SCIMGroup) (*SCIMGroup, error) {
+	name := strings.TrimSpace(input.DisplayName)
+	if err := i.checkRoleName(uuid.Nil, name); err != nil {
+		return nil, err
+	}
+	members, err := i.resolveMembers(input.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	role := &entities.Role{Name: name, SCIMManaged: true}
+	if err := i.roleRepo.Create(role); err != nil {
+		return nil, err
+	}
+	if err := i.checkMembers(role, members); err != nil {
+		if deleteErr := i.roleRepo.Delete(role.ID); deleteErr != nil {
+			log.Printf("Gagal menghapus grup SCIM %s yang anggotanya ditolak: %v", role.ID, deleteErr)
+		}
+		return nil, err
+	}
+	if err := i.roleRepo.UpdateMembers(role.ID, members, nil); err != nil {
+		return nil, err
+	}
+
+	created, err := i.findRole(role.ID)
+	if err != nil {
+		return nil, err
+	}
+	return i.groupResource(created), nil
+}
+
+// ReplaceGroup mengganti nama dan anggota grup (PUT).
+func (i *SCIMInteractor) ReplaceGroup(id uuid.UUID, input *SCIMGroup) (*SCIMGroup, error) {
+	role, err := i.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+	return i.saveGroup(role, input)
+}
+
+// PatchGroup menerapkan operasi PatchOp ke grup, misalnya menambah atau mengeluarkan anggota.
+func (i *SCIMInteractor) PatchGroup(id uuid.UUID, operations []SCIMPatchOperation) (*SCIMGroup, error) {
+	role, err := i.findRole(id)
+	if err != nil {
+		return nil, err
+	}
+
+	resource := scimObject(i.groupResource(role))
+	if err := scimPatch(resource, operations); err != nil {
+		return nil, err
+	}
+
+	var input SCIMGroup
+	if err := scimDecode(resource, &input); err != nil {
+		return nil, err
+	}
+	return i.saveGroup(role, &input)
+}
+
+// DeleteGroup menghapus Role beserta keanggotaannya.
+func (i *SCIMInteractor) DeleteGroup(id uuid.UUID) error {
+	if _, err := i.findRole(id); err != nil {
+		return err
+	}
+	if err := i.roleRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSCIMGroupNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// saveUser menerapkan resource SCIM ke pengguna yang sudah ada dan menyimpannya.
+// Menonaktifkan pengguna langsung mengakhiri semua sesinya.
+func (i *SCIMInteractor) saveUser(ctx context.Context, client uuid.UUID, user *entities.User, input *SCIMUser) (*SCIMUser, error) {
+	wasActive := user.IsActive
+	if err := i.applyUser(user, input); err != nil {
+		return nil, err
+	}
+	externalID := strings.TrimSpace(input.ExternalID)
+	if err := i.checkExternalID(client, user.ID, externalID); err != nil {
+		return nil, err
+	}
+
+	if input.Password != "" {
+		hash, err := i.users.hasher.Hash(input.Password)
+		if err != nil {
+			return nil, err
+		}
+		user.Password = hash
+	}
+
+	user, err := i.userRepo.Update(user)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.replaceExternalID(client, user, externalID); err != nil {
+		return nil, err
+	}
+
+	if wasActive && !user.IsActive {
+		if _, err := i.sessions.RevokeAll(ctx, user.ID); err != nil {
+			log.Printf("Gagal mencabut sesi pengguna SCIM %s: %v", user.ID, err)
+		}
+	}
+	return i.userResource(user, externalID), nil
+}
+
+// applyUser menyalin atribut yang bisa ditulis dari resource SCIM ke entitas. Email yang dipakai adalah
+// email primary, atau email pertama jika tidak ada yang ditandai primary.
+func (i *SCIMInteractor) applyUser(user *entities.User, input *SCIMUser) error {
+	username := strings.TrimSpace(input.UserName)
+	if username == "" {
+		return fmt.Errorf("%w: userName wajib diisi", ErrSCIMInvalidValue)
+	}
+
+	var email string
+	for _, candidate := range input.Emails {
+		if email == "" || candidate.Primary {
+			email = strings.TrimSpace(candidate.Value)
+		}
+		if candidate.Primary {
+			break
+		}
+	}
+	if email == "" {
+		return fmt.Errorf("%w: emails wajib berisi minimal satu alamat", ErrSCIMInvalidValue)
+	}
+
+	if err := i.checkUserUnique(user.ID, username, email); err != nil {
+		return err
+	}
+
+	user.Username = username
+	user.Email = email
+	user.FirstName, user.LastName = "", ""
+	if input.Name != nil {
+		user.FirstName = strings.TrimSpace(input.Name.GivenName)
+		user.LastName = strings.TrimSpace(input.Name.FamilyName)
+	}
+	user.IsActive = input.Active == nil || *input.Active
+	return nil
+}
+
+// checkUserUnique memastikan username dan email tidak dipakai pengguna lain.
+func (i *SCIMInteractor) checkUserUnique(id uuid.UUID, username, email string) error {
+	existing, err := i.userRepo.FindByUsernameOrEmail(username)
+	if err == nil && existing.ID != id {
+		return fmt.Errorf("%w: userName %q", ErrSCIMUniqueness, username)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+
+	existing, err = i.userRepo.FindByEmail(email)
+	if err == nil && existing.ID != id {
+		return fmt.Errorf("%w: email %q", ErrSCIMUniqueness, email)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// checkExternalID memastikan externalId belum dipakai pengguna lain pada klien yang sama.
+func (i *SCIMInteractor) checkExternalID(client, userID uuid.UUID, externalID string) error {
+	if externalID == "" {
+		return nil
+	}
+	identity, err := i.identityRepo.FindBySubject(scimProvider(client), externalID)
+	if err == nil && identity.UserID != userID {
+		return fmt.Errorf("%w: externalId %q", ErrSCIMUniqueness, externalID)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// replaceExternalID mengganti identitas klien provisioning milik pengguna jika externalId berubah.
+func (i *SCIMInteractor) replaceExternalID(client uuid.UUID, user *entities.User, externalID string) error {
+	identity, err := i.externalIdentity(client, user.ID)
+	if err != nil {
+		return err
+	}
+	if identity.Subject == externalID {
+		return nil
+	}
+
+	if identity.ID != uuid.Nil {
+		if err := i.identityRepo.Delete(user.ID, identity.ID); err != nil {
+			return err
+		}
+	}
+	if externalID == "" {
+		return nil
+	}
+	return i.identityRepo.Create(&entities.LinkedIdentity{
+		UserID:      user.ID,
+		Provider:    scimProvider(client),
+		Subject:     externalID,
+		Email:       user.Email,
+		Provisioned: identity.Provisioned,
+	})
+}
+
+// externalIdentity mengembalikan identitas klien provisioning milik pengguna, atau identitas kosong.
+func (i *SCIMInteractor) externalIdentity(client, userID uuid.UUID) (*entities.LinkedIdentity, error) {
+	identities, err := i.identityRepo.FindByUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	provider := scimProvider(client)
+	for n := range identities {
+		if identities[n].Provider == provider {
+			return &identities[n], nil
+		}
+	}
+	return &entities.LinkedIdentity{}, nil
+}
+
+// saveGroup menerapkan nama dan anggota dari resource SCIM ke Role.
+// Service account tidak pernah dikirim ke klien, sehingga keanggotaannya tidak ikut dicabut.
+func (i *SCIMInteractor) saveGroup(role *entities.Role, input *SCIMGroup) (*SCIMGroup, error) {
+	name := strings.TrimSpace(input.DisplayName)
+	if err := i.checkRoleName(role.ID, name); err != nil {
+		return nil, err
+	}
+	desired, err := i.resolveMembers(input.Members)
+	if err != nil {
+		return nil, err
+	}
+
+	if name != role.Name {
+		role.Name = name
+		if err := i.roleRepo.Update(role); err != nil {
+			return nil, err
+		}
+	}
+
+	wanted := make(map[uuid.UUID]bool, len(desired))
+	for _, id := range desired {
+		wanted[id] = true
+	}
+	current := make(map[uuid.UUID]bool, len(role.Users))
+	var add, remove []uuid.UUID
+	for _, user := range role.Users {
+		current[user.ID] = true
+		if !wanted[user.ID] && !user.IsServiceAccount {
+			remove = append(remove, user.ID)
+		}
+	}
+	for _, id := range desired {
+		if !current[id] {
+			add = append(add, id)
+		}
+	}
+	if len(add) > 0 {
+		if err := i.grants.CheckNoApproval([]*entities.Role{role}); err != nil {
+			return nil, err
+		}
+		if err := i.checkMembers(role, add); err != nil {
+			return nil, err
+		}
+	}
+	if len(add) > 0 || len(remove) > 0 {
+		if err := i.roleRepo.UpdateMembers(role.ID, add, remove); err != nil {
+			return nil, err
+		}
+	}
+
+	updated, err := i.findRole(role.ID)
+	if err != nil {
+		return nil, err
+	}
+	return i.groupResource(updated), nil
+}
+
+// checkMembers memastikan pengguna userIDs tidak melanggar aturan pemisahan tugas jika menjadi anggota
+// grup role.
+func (i *SCIMInteractor) checkMembers(role *entities.Role, userIDs []uuid.UUID) error {
+	for _, userID := range userIDs {
+		if err := i.grants.CheckConflicts(userID, role.ID); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// checkRoleName memastikan nama grup diisi dan belum dipakai Role lain.
+func (i *SCIMInteractor) checkRoleName(id uuid.UUID, name string) error {
+	if name == "" {
+		return fmt.Errorf("%w: displayName wajib diisi", ErrSCIMInvalidValue)
+	}
+	existing, err := i.roleRepo.FindByName(name)
+	if err == nil && existing.ID != id {
+		return fmt.Errorf("%w: displayName %q", ErrSCIMUniqueness, name)
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return err
+	}
+	return nil
+}
+
+// resolveMembers memastikan setiap anggota adalah pengguna yang terlihat lewat SCIM.
+func (i *SCIMInteractor) resolveMembers(members []SCIMValue) ([]uuid.UUID, error) {
+	seen := make(map[uuid.UUID]bool, len(members))
+	ids := make([]uuid.UUID, 0, len(members))
+	for _, member := range members {
+		id, err := uuid.Parse(member.Value)
+		if err != nil {
+			return nil, fmt.Errorf("%w: anggota %q bukan ID pengguna", ErrSCIMInvalidValue, member.Value)
+		}
+		if seen[id] {
+			continue
+		}
+		if _, err := i.findUser(id); err != nil {
+			if errors.Is(err, ErrSCIMUserNotFound) {
+				return nil, fmt.Errorf("%w: anggota %s tidak ditemukan", ErrSCIMInvalidValue, id)
+			}
+			return nil, err
+		}
+		seen[id] = true
+		ids = append(ids, id)
+	}
+	return ids, nil
+}
+
+// findUser mencari pengguna yang boleh dilihat lewat SCIM.
+func (i *SCIMInteractor) findUser(id uuid.UUID) (*entities.User, error) {
+	user, err := i.userRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSCIMUserNotFound
+		}
+		return nil, err
+	}
+	if user.IsServiceAccount {
+		return nil, ErrSCIMUserNotFound
+	}
+	return user, nil
+}
+
+// findWritableUser mencari pengguna yang boleh diubah lewat SCIM. Superuser hanya bisa dilihat agar
+// klien provisioning tidak bisa mengambil alih akun admin lewat perubahan password atau email.
+func (i *SCIMInteractor) findWritableUser(id uuid.UUID) (*entities.User, error) {
+	user, err := i.findUser(id)
+	if err != nil {
+		return nil, err
+	}
+	if user.IsSuperuser {
+		return nil, ErrSCIMSuperuserProtected
+	}
+	return user, nil
+}
+
+// findRole mencari Role yang dikelola SCIM beserta anggotanya.
+func (i *SCIMInteractor) findRole(id uuid.UUID) (*entities.Role, error) {
+	role, err := i.roleRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSCIMGroupNotFound
+		}
+		return nil, err
+	}
+	if !role.SCIMManaged {
+		return nil, ErrSCIMGroupNotFound
+	}
+	return role, nil
+}
+
+// userResource membentuk representasi SCIM dari pengguna.
+func (i *SCIMInteractor) userResource(user *entities.User, externalID string) *SCIMUser {
+	active := user.IsActive
+	resource := &SCIMUser{
+		Schemas:     []string{SCIMSchemaUser},
+		ID:          user.ID.String(),
+		ExternalID:  externalID,
+		UserName:    user.Username,
+		DisplayName: user.WebAuthnDisplayName(),
+		Emails:      []SCIMValue{{Value: user.Email, Type: "work", Primary: true}},
+		Active:      &active,
+		Meta:        i.meta("User", "/Users/", user.ID, user.CreatedAt, user.UpdatedAt),
+	}
+	if user.FirstName != "" || user.LastName != "" {
+		resource.Name = &SCIMName{
+			Formatted:  strings.TrimSpace(user.FirstName + " " + user.LastName),
+			GivenName:  user.FirstName,
+			FamilyName: user.LastName,
+		}
+	}
+	for _, role := range user.Roles {
+		if !role.SCIMManaged {
+			continue
+		}
+		resource.Groups = append(resource.Groups, SCIMValue{
+			Value:   role.ID.String(),
+			Display: role.Name,
+			Ref:     i.location("/Groups/", role.ID),
+		})
+	}
+	return resource
+}
+
+// groupResource membentuk representasi SCIM dari Role.
+func (i *SCIMInteractor) groupResource(role *entities.Role) *SCIMGroup {
+	resource := &SCIMGroup{
+		Schemas:     []string{SCIMSchemaGroup},
+		ID:          role.ID.String(),
+		DisplayName: role.Name,
+		Meta:        i.meta("Group", "/Groups/", role.ID, role.CreatedAt, role.UpdatedAt),
+	}
+	for _, user := range role.Users {
+		if user.IsServiceAccount {
+			continue
+		}
+		resource.Members = append(resource.Members, SCIMValue{
+			Value:   user.ID.String(),
+			Display: user.Username,
+			Ref:     i.location("/Users/", user.ID),
+		})
+	}
+	return resource
+}
+
+func (i *SCIMInteractor) meta(resourceType, endpoint string, id uuid.UUID, created, modified time.Time) *SCIMMeta {
+	created, modified = created.UTC(), modified.UTC()
+	return &SCIMMeta{
+		ResourceType: resourceType,
+		Created:      &created,
+		LastModified: &modified,
+		Location:     i.location(endpoint, id),
+	}
+}
+
+func (i *SCIMInteractor) location(endpoint string, id uuid.UUID) string {
+	return i.policy.BaseURL + "/scim/v2" + endpoint + id.String()
+}
+
+// page memotong hasil query sesuai startIndex dan count.
+func (i *SCIMInteractor) page(resources []any, query SCIMQuery) *SCIMListResponse {
+	start := max(query.StartIndex, 1)
+	count := query.Count
+	if count < 0 || count > i.policy.MaxResults {
+		count = i.policy.MaxResults
+	}
+
+	from := min(start-1, len(resources))
+	to := min(from+count, len(resources))
+	return &SCIMListResponse{
+		Schemas:      []string{SCIMMessageListResponse},
+		TotalResults: len(resources),
+		StartIndex:   start,
+		ItemsPerPage: to - from,
+		Resources:    append([]any{}, resources[from:to]...),
+	}
+}
+
+// scimProvider adalah nama provider LinkedIdentity untuk externalId dari satu klien provisioning.
+func scimProvider(client uuid.UUID) string {
+	return "scim:" + client.String()
+}
+
+// scimObject mengubah resource menjadi objek JSON generik untuk filter dan PATCH.
+func scimObject(resource any) map[string]any {
+	data, _ := json.Marshal(resource)
+	var object map[string]any
+	_ = json.Unmarshal(data, &object)
+	return object
+}
+
+// scimDecode mengubah objek JSON generik hasil PATCH kembali menjadi resource.
+func scimDecode(object map[string]any, resource any) error {
+	data, err := json.Marshal(object)
+	if err != nil {
+		return err
+	}
+	if err := json.Unmarshal(data, resource); err != nil {
+		return fmt.Errorf("%w: %v", ErrSCIMInvalidValue, err)
+	}
+	return nil
+}
+
+// scimPatch menerapkan operasi PatchOp (RFC 7644 bagian 3.5.2) ke objek JSON resource.
+// Operasi tanpa path memakai value berupa objek yang kuncinya boleh berupa path, seperti yang
+// dikirim Azure AD ({"name.givenName": "..."}).
+func scimPatch(resource map[string]any, operations []SCIMPatchOperation) error {
+	if len(operations) == 0 {
+		return fmt.Errorf("%w: Operations kosong", ErrSCIMInvalidSyntax)
+	}
+	for _, operation := range operations {
+		op := strings.ToLower(operation.Op)
+		if op != "add" && op != "replace" && op != "remove" {
+			return fmt.Errorf("%w: operasi %q tidak dikenal", ErrSCIMInvalidSyntax, operation.Op)
+		}
+
+		if operation.Path == "" {
+			if op == "remove" {
+				return fmt.Errorf("%w: remove memerlukan path", ErrSCIMNoTarget)
+			}
+			values, ok := operation.Value.(map[string]any)
+			if !ok {
+				return fmt.Errorf("%w: value harus berupa objek jika path kosong", ErrSCIMInvalidValue)
+			}
+			for key, value := range values {
+				path, err := parseSCIMPath(key)
+				if err != nil {
+					return err
+				}
+				if err := scimApply(resource, op, path, value); err != nil {
+					return err
+				}
+			}
+			continue
+		}
+
+		path, err := parseSCIMPath(operation.Path)
+		if err != nil {
+			return err
+		}
+		if err := scimApply(resource, op, path, operation.Value); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// scimApply menerapkan satu operasi ke atribut yang ditunjuk path.
+func scimApply(resource map[string]any, op string, path *scimPath, value any) error {
+	key, current, exists := scimLookup(resource, path.attribute)
+	if !exists {
+		key = path.attribute
+	}
+
+	if path.filter != nil {
+		list, _ := current.([]any)
+		kept := make([]any, 0, len(list))
+		matched := false
+		for _, item := range list {
+			element, ok := item.(map[string]any)
+			if !ok || !path.filter.match(element) {
+				kept = append(kept, item)
+				continue
+			}
+			matched = true
+			switch {
+			case op == "remove" && path.sub == "":
+				continue
+			case op == "remove":
+				if subKey, _, ok := scimLookup(element, path.sub); ok {
+					delete(element, subKey)
+				}
+			case path.sub != "":
+				scimSet(element, path.sub, value)
+			default:
+				replacement, ok := value.(map[string]any)
+				if !ok {
+					return fmt.Errorf("%w: value untuk %s harus berupa objek", ErrSCIMInvalidValue, path.attribute)
+				}
+				for name, sub := range replacement {
+					scimSet(element, name, sub)
+				}
+			}
+			kept = append(kept, element)
+		}
+		if !matched {
+			return fmt.Errorf("%w: %s", ErrSCIMNoTarget, path.attribute)
+		}
+		resource[key] = kept
+		return nil
+	}
+
+	if path.sub != "" {
+		object, _ := current.(map[string]any)
+		if object == nil {
+			if op == "remove" {
+				return nil
+			}
+			object = make(map[string]any)
+		}
+		if op == "remove" {
+			if subKey, _, ok := scimLookup(object, path.sub); ok {
+				delete(object, subKey)
+			}
+		} else {
+			scimSet(object, path.sub, value)
+		}
+		resource[key] = object
+		return nil
+	}
+
+	switch op {
+	case "remove":
+		list, isList := current.([]any)
+		removals, hasValues := value.([]any)
+		if !isList || !hasValues {
+			delete(resource, key)
+			return nil
+		}
+		// Okta mengeluarkan anggota dengan path "members" dan daftar {"value": id} yang dikeluarkan
+		kept := make([]any, 0, len(list))
+		for _, item := range list {
+			if !scimContainsValue(removals, item) {
+				kept = append(kept, item)
+			}
+		}
+		resource[key] = kept
+	case "add":
+		list, isList := current.([]any)
+		additions, hasValues := value.([]any)
+		switch {
+		case isList || hasValues:
+			if !hasValues {
+				additions = []any{value}
+			}
+			for _, item := range additions {
+				if !scimContainsValue(list, item) {
+					list = append(list, item)
+				}
+			}
+			resource[key] = list
+		default:
+			scimMerge(resource, key, current, value)
+		}
+	default:
+		scimMerge(resource, key, current, value)
+	}
+	return nil
+}
+
+// scimMerge mengisi atribut: atribut kompleks digabung per sub-atribut, selain itu diganti.
+func scimMerge(resource map[string]any, key string, current, value any) {
+	object, isObject := current.(map[string]any)
+	values, hasValues := value.(map[string]any)
+	if !isObject || !hasValues {
+		resource[key] = value
+		return
+	}
+	for name, sub := range values {
+		scimSet(object, name, sub)
+	}
+}
+
+// scimSet mengisi kunci objek tanpa membedakan huruf besar dan kecil.
+func scimSet(object map[string]any, name string, value any) {
+	if key, _, ok := scimLookup(object, name); ok {
+		name = key
+	}
+	object[name] = value
+}
+
+// scimContainsValue mengembalikan true jika list berisi elemen dengan sub-atribut value yang sama dengan item.
+func scimContainsValue(list []any, item any) bool {
+	want := scimValues(item, nil)
+	if len(want) == 0 {
+		return false
+	}
+	for _, candidate := range list {
+		got := scimValues(candidate, nil)
+		if len(got) > 0 && fmt.Sprint(got[0]) == fmt.Sprint(want[0]) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,330 @@
+package interactors
+
+import (
+	"errors"
+	"sort"
+	"strings"
+	"testing"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// scimFixture adalah SCIMInteractor dengan repositori di memori.
+type scimFixture struct {
+	users    *memoryUserRepository
+	roles    *memoryRoleRepository
+	policies *staticApprovalPolicyRepository
+	scim     *SCIMInteractor
+}
+
+func newSCIMFixture(users ...*entities.User) *scimFixture {
+	f := &scimFixture{users: newMemoryUserRepository(users...), policies: &staticApprovalPolicyRepository{}}
+	f.roles = &memoryRoleRepository{users: f.users}
+	sod := NewSoDInteractor(&staticSoDRuleRepository{}, f.roles, nil, nil, nil)
+	grants := NewRoleGrantInteractor(f.roles, f.users, f.policies, sod, nil, nil)
+	f.scim = NewSCIMInteractor(f.users, f.roles, &memoryLinkedIdentityRepository{}, nil, nil, grants, SCIMPolicy{MaxResults: 100})
+	return f
+}
+
+// addRole mendaftarkan Role dan mengembalikan salinannya.
+func (f *scimFixture) addRole(name string, managed bool) entities.Role {
+	role := entities.Role{ID: uuid.New(), Name: name, SCIMManaged: managed}
+	f.roles.roles = append(f.roles.roles, role)
+	return role
+}
+
+func TestSCIMGroupsOnlyExposeManagedRoles(t *testing.T) {
+	alice := &entities.User{ID: uuid.New(), Username: "alice", Email: "alice@example.org", IsActive: true}
+	f := newSCIMFixture(alice)
+	admin := f.addRole("admin", false)
+	engineering := f.addRole("engineering", true)
+	alice.Roles = []*entities.Role{&admin}
+
+	list, err := f.scim.ListGroups(SCIMQuery{Count: -1}, true)
+	if err != nil {
+		t.Fatalf("ListGroups: %v", err)
+	}
+	if list.TotalResults != 1 || list.Resources[0].(*SCIMGroup).DisplayName != "engineering" {
+		t.Fatalf("grup = %+v, ingin hanya engineering", list.Resources)
+	}
+	user, err := f.scim.GetUser(uuid.New(), alice.ID)
+	if err != nil {
+		t.Fatalf("GetUser: %v", err)
+	}
+	if len(user.Groups) != 0 {
+		t.Fatalf("groups pengguna = %+v, ingin tanpa role yang tidak dikelola SCIM", user.Groups)
+	}
+
+	add := []SCIMPatchOperation{{Op: "add", Path: "members", Value: []any{map[string]any{"value": alice.ID.String()}}}}
+	denied := map[string]func() error{
+		"GetGroup": func() error {
+			_, err := f.scim.GetGroup(admin.ID, true)
+			return err
+		},
+		"ReplaceGroup": func() error {
+			_, err := f.scim.ReplaceGroup(admin.ID, &SCIMGroup{DisplayName: "admin-lama"})
+			return err
+		},
+		"PatchGroup": func() error {
+			_, err := f.scim.PatchGroup(admin.ID, add)
+			return err
+		},
+		"DeleteGroup": func() error {
+			return f.scim.DeleteGroup(admin.ID)
+		},
+	}
+	for name, call := range denied {
+		t.Run(name, func(t *testing.T) {
+			if err := call(); !errors.Is(err, ErrSCIMGroupNotFound) {
+				t.Fatalf("err = %v, ingin ErrSCIMGroupNotFound", err)
+			}
+			if role, err := f.roles.FindByID(admin.ID); err != nil || role.Name != "admin" {
+				t.Fatalf("role admin berubah: %+v, %v", role, err)
+			}
+		})
+	}
+
+	if _, err := f.scim.PatchGroup(engineering.ID, add); err != nil {
+		t.Fatalf("PatchGroup: %v", err)
+	}
+	if stored, _ := f.users.FindByID(alice.ID); !stored.HasRole("engineering") {
+		t.Fatalf("role alice = %v, ingin termasuk engineering", stored.Roles)
+	}
+	if err := f.scim.DeleteGroup(engineering.ID); err != nil {
+		t.Fatalf("DeleteGroup: %v", err)
+	}
+}
+
+func TestSCIMCreateGroupIsManaged(t *testing.T) {
+	f := newSCIMFixture()
+	f.addRole("admin", false)
+
+	if _, err := f.scim.CreateGroup(&SCIMGroup{DisplayName: "admin"}); !errors.Is(err, ErrSCIMUniqueness) {
+		t.Fatalf("err = %v, ingin ErrSCIMUniqueness", err)
+	}
+	group, err := f.scim.CreateGroup(&SCIMGroup{DisplayName: "support"})
+	if err != nil {
+		t.Fatalf("CreateGroup: %v", err)
+	}
+	if _, err := f.scim.GetGroup(uuid.MustParse(group.ID), false); err != nil {
+		t.Fatalf("GetGroup grup buatan SCIM: %v", err)
+	}
+}
+
+func TestSCIMManagedGroupRefusesApprovalRole(t *testing.T) {
+	alice := &entities.User{ID: uuid.New(), Username: "alice", Email: "alice@example.org", IsActive: true}
+	f := newSCIMFixture(alice)
+	finance := f.addRole("finance", true)
+	f.policies.roles = []uuid.UUID{finance.ID}
+
+	_, err := f.scim.ReplaceGroup(finance.ID, &SCIMGroup{
+		DisplayName: "finance",
+		Members:     []SCIMValue{{Value: alice.ID.String()}},
+	})
+	if !errors.Is(err, ErrRoleGrantApprovalRequired) {
+		t.Fatalf("err = %v, ingin ErrRoleGrantApprovalRequired", err)
+	}
+	if stored, _ := f.users.FindByID(alice.ID); stored.HasRole("finance") {
+		t.Fatal("anggota tetap ditambahkan ke grup yang memerlukan persetujuan")
+	}
+}
+
+func TestSCIMListUsersPagination(t *testing.T) {
+	f := newSCIMFixture(
+		&entities.User{ID: uuid.New(), Username: "andi", Email: "andi@example.org", IsActive: true},
+		&entities.User{ID: uuid.New(), Username: "budi", Email: "budi@example.org", IsActive: true},
+		&entities.User{ID: uuid.New(), Username: "citra", Email: "citra@example.org"},
+		&entities.User{ID: uuid.New(), Username: "dewi", Email: "dewi@example.org", IsActive: true},
+		&entities.User{ID: uuid.New(), Username: "scim-client", Email: "scim@example.org", IsActive: true, IsServiceAccount: true},
+	)
+	f.scim.policy.MaxResults = 2
+
+	tests := []struct {
+		name       string
+		query      SCIMQuery
+		total      int
+		startIndex int
+		want       []string
+	}{
+		{"startIndex di bawah 1", SCIMQuery{StartIndex: 0, Count: 1}, 4, 1, []string{"andi"}},
+		{"count negatif memakai batas", SCIMQuery{StartIndex: 1, Count: -1}, 4, 1, []string{"andi", "budi"}},
+		{"count di atas batas", SCIMQuery{StartIndex: 2, Count: 10}, 4, 2, []string{"budi", "citra"}},
+		{"count nol", SCIMQuery{StartIndex: 1, Count: 0}, 4, 1, nil},
+		{"halaman terakhir terpotong", SCIMQuery{StartIndex: 4, Count: 2}, 4, 4, []string{"dewi"}},
+		{"startIndex melewati akhir", SCIMQuery{StartIndex: 9, Count: 2}, 4, 9, nil},
+		{"dengan filter", SCIMQuery{Filter: `active eq true and not (userName eq "andi")`, Count: -1}, 2, 1, []string{"budi", "dewi"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			list, err := f.scim.ListUsers(uuid.New(), tt.query)
+			if err != nil {
+				t.Fatalf("ListUsers: %v", err)
+			}
+			var got []string
+			for _, resource := range list.Resources {
+				got = append(got, resource.(*SCIMUser).UserName)
+			}
+			if list.TotalResults != tt.total || list.StartIndex != tt.startIndex || list.ItemsPerPage != len(got) ||
+				strings.Join(got, ",") != strings.Join(tt.want, ",") {
+				t.Fatalf("total = %d, startIndex = %d, itemsPerPage = %d, pengguna = %v; ingin %d, %d, %v",
+					list.TotalResults, list.StartIndex, list.ItemsPerPage, got, tt.total, tt.startIndex, tt.want)
+			}
+		})
+	}
+
+	if _, err := f.scim.ListUsers(uuid.New(), SCIMQuery{Filter: `userName eq`}); !errors.Is(err, ErrSCIMInvalidFilter) {
+		t.Fatalf("ListUsers: err = %v, ingin ErrSCIMInvalidFilter", err)
+	}
+}
+
+func TestSCIMPatchOperations(t *testing.T) {
+	tests := []struct {
+		name       string
+		operations []SCIMPatchOperation
+		check      func(resource map[string]any) bool
+	}{
+		{
+			"replace sub-atribut",
+			[]SCIMPatchOperation{{Op: "Replace", Path: "name.givenName", Value: "Bambang"}},
+			func(r map[string]any) bool {
+				name := r["name"].(map[string]any)
+				return name["givenName"] == "Bambang" && name["familyName"] == "Santoso"
+			},
+		},
+		{
+			"add ke atribut multi-valued tanpa duplikat",
+			[]SCIMPatchOperation{{Op: "add", Path: "emails", Value: []any{
+				map[string]any{"value": "budi@kantor.test", "type": "work"},
+				map[string]any{"value": "budi@lain.test", "type": "other"},
+			}}},
+			func(r map[string]any) bool { return len(r["emails"].([]any)) == 3 },
+		},
+		{
+			"replace lewat filter nilai dan sub-atribut",
+			[]SCIMPatchOperation{{Op: "replace", Path: `emails[type eq "work"].value`, Value: "budi@baru.test"}},
+			func(r map[string]any) bool {
+				emails := r["emails"].([]any)
+				return emails[0].(map[string]any)["value"] == "budi@baru.test" && emails[1].(map[string]any)["value"] == "budi@rumah.test"
+			},
+		},
+		{
+			"remove elemen yang cocok dengan filter",
+			[]SCIMPatchOperation{{Op: "remove", Path: `emails[type eq "home"]`}},
+			func(r map[string]any) bool {
+				emails := r["emails"].([]any)
+				return len(emails) == 1 && emails[0].(map[string]any)["type"] == "work"
+			},
+		},
+		{
+			"remove sub-atribut elemen",
+			[]SCIMPatchOperation{{Op: "remove", Path: `emails[type eq "work"].primary`}},
+			func(r map[string]any) bool {
+				_, ok := r["emails"].([]any)[0].(map[string]any)["primary"]
+				return !ok
+			},
+		},
+		{
+			"remove nilai tertentu dari daftar",
+			[]SCIMPatchOperation{{Op: "remove", Path: "emails", Value: []any{map[string]any{"value": "budi@rumah.test"}}}},
+			func(r map[string]any) bool { return len(r["emails"].([]any)) == 1 },
+		},
+		{
+			"remove atribut",
+			[]SCIMPatchOperation{{Op: "remove", Path: "name"}},
+			func(r map[string]any) bool { _, ok := r["name"]; return !ok },
+		},
+		{
+			"tanpa path dengan kunci berupa path",
+			[]SCIMPatchOperation{{Op: "replace", Value: map[string]any{"name.familyName": "Wijaya", "ACTIVE": false}}},
+			func(r map[string]any) bool {
+				_, added := r["ACTIVE"]
+				return r["name"].(map[string]any)["familyName"] == "Wijaya" && r["active"] == false && !added
+			},
+		},
+		{
+			"beberapa operasi berurutan",
+			[]SCIMPatchOperation{
+				{Op: "remove", Path: "emails"},
+				{Op: "add", Path: "emails", Value: []any{map[string]any{"value": "budi@baru.test", "type": "work"}}},
+			},
+			func(r map[string]any) bool {
+				emails := r["emails"].([]any)
+				return len(emails) == 1 && emails[0].(map[string]any)["value"] == "budi@baru.test"
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resource := scimTestUser()
+			if err := scimPatch(resource, tt.operations); err != nil {
+				t.Fatalf("scimPatch: %v", err)
+			}
+			if !tt.check(resource) {
+				t.Fatalf("resource = %v", resource)
+			}
+		})
+	}
+
+	failures := []struct {
+		name       string
+		operations []SCIMPatchOperation
+		want       error
+	}{
+		{"tanpa operasi", nil, ErrSCIMInvalidSyntax},
+		{"operasi tidak dikenal", []SCIMPatchOperation{{Op: "move", Path: "active"}}, ErrSCIMInvalidSyntax},
+		{"remove tanpa path", []SCIMPatchOperation{{Op: "remove"}}, ErrSCIMNoTarget},
+		{"filter tidak cocok", []SCIMPatchOperation{{Op: "replace", Path: `emails[type eq "fax"].value`, Value: "x"}}, ErrSCIMNoTarget},
+		{"path tidak valid", []SCIMPatchOperation{{Op: "replace", Path: "name.givenName.first", Value: "x"}}, ErrSCIMInvalidPath},
+		{"tanpa path dengan nilai bukan objek", []SCIMPatchOperation{{Op: "add", Value: "x"}}, ErrSCIMInvalidValue},
+		{"filter nilai dengan nilai bukan objek", []SCIMPatchOperation{{Op: "replace", Path: `emails[type eq "work"]`, Value: "x"}}, ErrSCIMInvalidValue},
+	}
+	for _, tt := range failures {
+		t.Run(tt.name, func(t *testing.T) {
+			if err := scimPatch(scimTestUser(), tt.operations); !errors.Is(err, tt.want) {
+				t.Fatalf("err = %v, ingin %v", err, tt.want)
+			}
+		})
+	}
+}
+
+func TestSCIMPatchGroupMembers(t *testing.T) {
+	alice := &entities.User{ID: uuid.New(), Username: "alice", Email: "alice@example.org", IsActive: true}
+	bob := &entities.User{ID: uuid.New(), Username: "bob", Email: "bob@example.org", IsActive: true}
+	f := newSCIMFixture(alice, bob)
+	engineering := f.addRole("engineering", true)
+
+	members := func(ids ...uuid.UUID) []any {
+		values := make([]any, 0, len(ids))
+		for _, id := range ids {
+			values = append(values, map[string]any{"value": id.String()})
+		}
+		return values
+	}
+	steps := []struct {
+		operations []SCIMPatchOperation
+		name       string
+		members    []string
+	}{
+		{[]SCIMPatchOperation{{Op: "add", Path: "members", Value: members(alice.ID, bob.ID)}}, "engineering", []string{"alice", "bob"}},
+		{[]SCIMPatchOperation{{Op: "remove", Path: `members[value eq "` + bob.ID.String() + `"]`}}, "engineering", []string{"alice"}},
+		{[]SCIMPatchOperation{{Op: "replace", Value: map[string]any{"displayName": "platform"}}}, "platform", []string{"alice"}},
+		{[]SCIMPatchOperation{{Op: "replace", Path: "members", Value: members(bob.ID)}}, "platform", []string{"bob"}},
+		{[]SCIMPatchOperation{{Op: "remove", Path: "members"}}, "platform", nil},
+	}
+	for n, step := range steps {
+		group, err := f.scim.PatchGroup(engineering.ID, step.operations)
+		if err != nil {
+			t.Fatalf("langkah %d: PatchGroup: %v", n, err)
+		}
+		var got []string
+		for _, member := range group.Members {
+			got = append(got, member.Display)
+		}
+		sort.Strings(got)
+		if group.DisplayName != step.name || strings.Join(got, ",") != strings.Join(step.members, ",") {
+			t.Fatalf("langkah %d: grup = %s %v, ingin %s %v", n, group.DisplayName, got, step.name, step.members)
+		}
+	}
+}
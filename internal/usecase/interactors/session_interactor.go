@@ -0,0 +1,186 @@
+package interactors
+
+import (
+	"context"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSessionNotFound dikembalikan jika sesi tidak ada, bukan milik pengguna, atau sudah tidak aktif.
+	ErrSessionNotFound = errors.New("sesi tidak ditemukan")
+	// ErrSessionRevoked dikembalikan jika token akses merujuk ke sesi yang sudah dicabut atau kedaluwarsa.
+	ErrSessionRevoked = errors.New("sesi sudah berakhir")
+)
+
+// sessionTouchInterval membatasi seberapa sering LastSeenAt ditulis agar tidak setiap permintaan menulis ke database.
+const sessionTouchInterval = time.Minute
+
+// ClientInfo adalah informasi klien yang melakukan login, dipakai untuk mencatat sesi.
+type ClientInfo struct {
+	IP        string
+	UserAgent string
+	Device    string // Nama perangkat atau aplikasi; jika kosong disimpulkan dari UserAgent
+}
+
+// SessionInteractor adalah use case untuk sesi login di sisi server: pembuatan, validasi dan pencabutan.
+type SessionInteractor struct {
+	sessionRepo repositories.SessionRepository
+}
+
+// NewSessionInteractor membuat instance baru dari SessionInteractor.
+func NewSessionInteractor(sr repositories.SessionRepository) *SessionInteractor {
+	return &SessionInteractor{sessionRepo: sr}
+}
+
+// Create mencatat sesi baru untuk token akses yang akan diterbitkan dengan ID sesi tersebut.
+func (i *SessionInteractor) Create(ctx context.Context, sessionID, userID uuid.UUID, client ClientInfo, expiresAt time.Time) (*entities.Session, error) {
+	session := newSession(sessionID, userID, client, expiresAt)
+	if err := i.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+// CreateImpersonation mencatat sesi impersonasi actorID sebagai userID. Sesi tampil di daftar sesi
+// pengguna tersebut sehingga bisa dilihat dan dicabut seperti sesi lainnya.
+func (i *SessionInteractor) CreateImpersonation(ctx context.Context, sessionID, userID, actorID uuid.UUID, client ClientInfo, expiresAt time.Time) (*entities.Session, error) {
+	session := newSession(sessionID, userID, client, expiresAt)
+	session.ImpersonatorID = &actorID
+	if err := i.sessionRepo.Create(ctx, session); err != nil {
+		return nil, err
+	}
+	return session, nil
+}
+
+func newSession(sessionID, userID uuid.UUID, client ClientInfo, expiresAt time.Time) *entities.Session {
+	device := client.Device
+	if device == "" {
+		device = describeDevice(client.UserAgent)
+	}
+
+	now := time.Now()
+	return &entities.Session{
+		ID:         sessionID,
+		UserID:     userID,
+		Device:     device,
+		UserAgent:  client.UserAgent,
+		IPAddress:  client.IP,
+		CreatedAt:  now,
+		LastSeenAt: now,
+		ExpiresAt:  expiresAt,
+	}
+}
+
+// Validate memastikan sesi milik pengguna masih aktif lalu memperbarui waktu terakhir terlihat.
+func (i *SessionInteractor) Validate(ctx context.Context, sessionID, userID uuid.UUID) (*entities.Session, error) {
+	session, err := i.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSessionRevoked
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if session.UserID != userID || !session.IsActive(now) {
+		return nil, ErrSessionRevoked
+	}
+
+	if now.Sub(session.LastSeenAt) >= sessionTouchInterval {
+		if err := i.sessionRepo.Touch(ctx, session, now); errors.Is(err, gorm.ErrRecordNotFound) {
+			// Sesi dicabut di antara pembacaan dan pembaruan
+			return nil, ErrSessionRevoked
+		} else if err != nil {
+			log.Printf("Gagal memperbarui waktu terakhir sesi %s: %v", session.ID, err)
+		}
+	}
+	return session, nil
+}
+
+// ListActive mengembalikan semua sesi aktif milik pengguna.
+func (i *SessionInteractor) ListActive(ctx context.Context, userID uuid.UUID) ([]entities.Session, error) {
+	return i.sessionRepo.FindActiveByUser(ctx, userID, time.Now())
+}
+
+// Revoke mencabut satu sesi milik pengguna. Token akses yang terikat ke sesi itu langsung ditolak.
+func (i *SessionInteractor) Revoke(ctx context.Context, userID, sessionID uuid.UUID) error {
+	session, err := i.sessionRepo.FindByID(ctx, sessionID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	if session.UserID != userID {
+		return ErrSessionNotFound
+	}
+
+	if err := i.sessionRepo.Revoke(ctx, session.ID, time.Now()); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSessionNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// RevokeAll mencabut semua sesi aktif milik pengguna ("keluar dari semua perangkat").
+func (i *SessionInteractor) RevokeAll(ctx context.Context, userID uuid.UUID) (int64, error) {
+	return i.sessionRepo.RevokeAllByUser(ctx, userID, time.Now())
+}
+
+// describeDevice membuat ringkasan perangkat yang mudah dibaca dari User-Agent, misalnya "Firefox di Linux".
+// Deteksi sengaja sederhana; User-Agent lengkap tetap disimpan.
+func describeDevice(userAgent string) string {
+	if userAgent == "" {
+		return "Perangkat tidak dikenal"
+	}
+
+	ua := strings.ToLower(userAgent)
+
+	browser := "Klien lain"
+	for _, candidate := range []struct{ token, name string }{
+		{"edg/", "Edge"},
+		{"opr/", "Opera"},
+		{"firefox/", "Firefox"},
+		{"chrome/", "Chrome"},
+		{"safari/", "Safari"},
+		{"curl/", "curl"},
+		{"postman", "Postman"},
+	} {
+		if strings.Contains(ua, candidate.token) {
+			browser = candidate.name
+			break
+		}
+	}
+
+	os := ""
+	for _, candidate := range []struct{ token, name string }{
+		{"android", "Android"},
+		{"iphone", "iOS"},
+		{"ipad", "iPadOS"},
+		{"windows", "Windows"},
+		{"mac os x", "macOS"},
+		{"cros", "ChromeOS"},
+		{"linux", "Linux"},
+	} {
+		if strings.Contains(ua, candidate.token) {
+			os = candidate.name
+			break
+		}
+	}
+
+	if os == "" {
+		return browser
+	}
+	return browser + " di " + os
+}
@@ -0,0 +1,275 @@
+package interactors
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"sort"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrIdentityProviderNotFound dikembalikan jika provider login sosial tidak dikonfigurasi.
+	ErrIdentityProviderNotFound = errors.New("identity provider tidak ditemukan")
+	// ErrInvalidSocialState dikembalikan jika parameter state callback tidak valid, sudah dipakai atau kedaluwarsa.
+	ErrInvalidSocialState = errors.New("state login sosial tidak valid atau kedaluwarsa")
+	// ErrExternalLoginFailed dikembalikan jika provider menolak login atau ID token-nya tidak valid.
+	ErrExternalLoginFailed = errors.New("login melalui identity provider gagal")
+	// ErrExternalEmailNotVerified dikembalikan jika akun eksternal belum terhubung dan email-nya tidak terverifikasi.
+	ErrExternalEmailNotVerified = errors.New("email dari identity provider belum terverifikasi")
+	// ErrExternalAccountNotFound dikembalikan jika tidak ada akun dengan email tersebut dan pendaftaran tidak diizinkan.
+	ErrExternalAccountNotFound = errors.New("tidak ada akun yang cocok dengan identitas ini")
+	// ErrExternalLinkRequired dikembalikan jika email identitas eksternal milik superuser. Akun superuser
+	// tidak pernah dihubungkan otomatis lewat email; identitas harus dihubungkan setelah login.
+	ErrExternalLinkRequired = errors.New("akun ini harus dihubungkan secara manual setelah login")
+	// ErrIdentityAlreadyLinked dikembalikan jika akun eksternal sudah terhubung ke pengguna lain.
+	ErrIdentityAlreadyLinked = errors.New("akun eksternal sudah terhubung ke pengguna lain")
+	// ErrLinkedIdentityNotFound dikembalikan jika identitas tidak ada atau bukan milik pengguna.
+	ErrLinkedIdentityNotFound = errors.New("identitas terhubung tidak ditemukan")
+	// ErrLastLoginMethod dikembalikan jika identitas yang diputus adalah satu-satunya cara login pengguna.
+	ErrLastLoginMethod = errors.New("identitas ini satu-satunya cara login ke akun")
+)
+
+// IdentityProviderInfo adalah provider login sosial untuk ditampilkan di halaman login.
+type IdentityProviderInfo struct {
+	Name        string `json:"name"`
+	DisplayName string `json:"display_name"`
+}
+
+// SocialLoginPolicy adalah aturan login sosial.
+type SocialLoginPolicy struct {
+	StateTTL    time.Duration   // Batas waktu antara redirect ke provider dan callback
+	AllowSignup map[string]bool // Provider yang boleh membuat akun baru jika tidak ada email yang cocok
+}
+
+// SocialCallbackResult adalah hasil callback provider: login (Login terisi) atau
+// penghubungan identitas ke akun yang sedang login (Linked terisi).
+type SocialCallbackResult struct {
+	Login  *LoginResult
+	Linked *entities.LinkedIdentity
+}
+
+// socialState adalah data permintaan login sosial yang disimpan sampai callback.
+type socialState struct {
+	Provider     string     `json:"provider"`
+	Nonce        string     `json:"nonce"`
+	CodeVerifier string     `json:"code_verifier"`
+	LinkUserID   *uuid.UUID `json:"link_user_id,omitempty"` // Terisi jika permintaan berasal dari pengguna yang menghubungkan akun
+}
+
+// SocialLoginInteractor adalah use case login dengan identity provider OpenID Connect eksternal
+// serta pengelolaan identitas yang terhubung ke akun.
+type SocialLoginInteractor struct {
+	providers      map[string]services.IdentityProvider
+	identityRepo   repositories.LinkedIdentityRepository
+	challengeStore repositories.ChallengeStore
+	accounts       externalAccounts
+	auth           *AuthInteractor
+	policy         SocialLoginPolicy
+}
+
+// NewSocialLoginInteractor membuat instance baru dari SocialLoginInteractor.
+func NewSocialLoginInteractor(
+	providers []services.IdentityProvider,
+	ir repositories.LinkedIdentityRepository,
+	ur repositories.UserRepository,
+	cs repositories.ChallengeStore,
+	users *UserInteractor,
+	auth *AuthInteractor,
+	policy SocialLoginPolicy,
+) *SocialLoginInteractor {
+	byName := make(map[string]services.IdentityProvider, len(providers))
+	for _, provider := range providers {
+		byName[provider.Name()] = provider
+	}
+	return &SocialLoginInteractor{
+		providers:      byName,
+		identityRepo:   ir,
+		challengeStore: cs,
+		accounts:       externalAccounts{identityRepo: ir, userRepo: ur, users: users},
+		auth:           auth,
+		policy:         policy,
+	}
+}
+
+// Providers mengembalikan provider yang dikonfigurasi, urut berdasarkan nama.
+func (i *SocialLoginInteractor) Providers() []IdentityProviderInfo {
+	infos := make([]IdentityProviderInfo, 0, len(i.providers))
+	for _, provider := range i.providers {
+		infos = append(infos, IdentityProviderInfo{Name: provider.Name(), DisplayName: provider.DisplayName()})
+	}
+	sort.Slice(infos, func(a, b int) bool { return infos[a].Name < infos[b].Name })
+	return infos
+}
+
+// BeginLogin mengembalikan alamat login di provider untuk pengguna yang belum login.
+func (i *SocialLoginInteractor) BeginLogin(ctx context.Context, providerName string) (string, error) {
+	return i.begin(ctx, providerName, nil)
+}
+
+// BeginLink mengembalikan alamat login di provider untuk menghubungkan akun eksternal ke pengguna yang sedang login.
+func (i *SocialLoginInteractor) BeginLink(ctx context.Context, userID uuid.UUID, providerName string) (string, error) {
+	return i.begin(ctx, providerName, &userID)
+}
+
+// Callback menyelesaikan permintaan dari BeginLogin atau BeginLink setelah provider mengarahkan pengguna kembali.
+// Saat login, akun eksternal yang belum terhubung otomatis dihubungkan ke pengguna dengan email yang sama
+// jika provider menyatakan email tersebut terverifikasi.
+func (i *SocialLoginInteractor) Callback(ctx context.Context, providerName, code, state string, client ClientInfo) (*SocialCallbackResult, error) {
+	provider, ok := i.providers[providerName]
+	if !ok {
+		return nil, ErrIdentityProviderNotFound
+	}
+	if code == "" || state == "" {
+		return nil, ErrInvalidSocialState
+	}
+
+	raw, err := i.challengeStore.Take(ctx, socialStateKey(state))
+	if err != nil {
+		if errors.Is(err, repositories.ErrChallengeNotFound) {
+			return nil, ErrInvalidSocialState
+		}
+		return nil, err
+	}
+	var pending socialState
+	if err := json.Unmarshal(raw, &pending); err != nil || pending.Provider != providerName {
+		return nil, ErrInvalidSocialState
+	}
+
+	external, err := provider.Exchange(ctx, code, pending.CodeVerifier, pending.Nonce)
+	if err != nil {
+		log.Printf("Login sosial %s gagal: %v", providerName, err)
+		return nil, ErrExternalLoginFailed
+	}
+
+	if pending.LinkUserID != nil {
+		linked, err := i.link(*pending.LinkUserID, external)
+		if err != nil {
+			return nil, err
+		}
+		return &SocialCallbackResult{Linked: linked}, nil
+	}
+
+	// Akun eksternal yang belum terhubung hanya dicocokkan lewat email yang terverifikasi
+	user, err := i.accounts.resolve(external, i.policy.AllowSignup[external.Provider])
+	if err != nil {
+		return nil, err
+	}
+	result, err := i.auth.LoginWithExternalIdentity(ctx, user, client)
+	if err != nil {
+		return nil, err
+	}
+	return &SocialCallbackResult{Login: result}, nil
+}
+
+// ListIdentities mengembalikan identitas eksternal yang terhubung ke pengguna.
+func (i *SocialLoginInteractor) ListIdentities(userID uuid.UUID) ([]entities.LinkedIdentity, error) {
+	return i.identityRepo.FindByUser(userID)
+}
+
+// Unlink memutus hubungan identitas eksternal dari pengguna. Identitas terakhir milik akun yang
+// dibuat lewat login sosial tidak bisa diputus karena pemiliknya tidak mengetahui password akun tersebut.
+func (i *SocialLoginInteractor) Unlink(userID, identityID uuid.UUID) error {
+	identities, err := i.identityRepo.FindByUser(userID)
+	if err != nil {
+		return err
+	}
+
+	var target *entities.LinkedIdentity
+	provisioned := false
+	for n := range identities {
+		if identities[n].ID == identityID {
+			target = &identities[n]
+		}
+		provisioned = provisioned || identities[n].Provisioned
+	}
+	if target == nil {
+		return ErrLinkedIdentityNotFound
+	}
+	if provisioned && len(identities) == 1 {
+		return ErrLastLoginMethod
+	}
+
+	if err := i.identityRepo.Delete(userID, identityID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrLinkedIdentityNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// begin menyimpan state, nonce dan PKCE verifier lalu membuat alamat login di provider.
+func (i *SocialLoginInteractor) begin(ctx context.Context, providerName string, linkUserID *uuid.UUID) (string, error) {
+	provider, ok := i.providers[providerName]
+	if !ok {
+		return "", ErrIdentityProviderNotFound
+	}
+
+	state, err := randomString(oauthSecretLength)
+	if err != nil {
+		return "", err
+	}
+	nonce, err := randomString(oauthSecretLength)
+	if err != nil {
+		return "", err
+	}
+	verifier, err := randomString(oauthSecretLength)
+	if err != nil {
+		return "", err
+	}
+
+	raw, err := json.Marshal(socialState{Provider: providerName, Nonce: nonce, CodeVerifier: verifier, LinkUserID: linkUserID})
+	if err != nil {
+		return "", err
+	}
+	if err := i.challengeStore.Save(ctx, socialStateKey(state), raw, i.policy.StateTTL); err != nil {
+		return "", err
+	}
+
+	authURL, err := provider.AuthCodeURL(ctx, state, nonce, verifier)
+	if err != nil {
+		log.Printf("Gagal memulai login sosial %s: %v", providerName, err)
+		return "", ErrExternalLoginFailed
+	}
+	return authURL, nil
+}
+
+// link menghubungkan identitas eksternal ke pengguna yang memulai BeginLink.
+// Email tidak perlu sama karena pengguna sudah membuktikan kepemilikan kedua akun.
+func (i *SocialLoginInteractor) link(userID uuid.UUID, external *services.ExternalIdentity) (*entities.LinkedIdentity, error) {
+	existing, err := i.identityRepo.FindBySubject(external.Provider, external.Subject)
+	if err == nil {
+		if existing.UserID != userID {
+			return nil, ErrIdentityAlreadyLinked
+		}
+		return existing, nil
+	}
+	if !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	identity := &entities.LinkedIdentity{
+		UserID:   userID,
+		Provider: external.Provider,
+		Subject:  external.Subject,
+		Email:    external.Email,
+	}
+	if err := i.identityRepo.Create(identity); err != nil {
+		return nil, err
+	}
+	return identity, nil
+}
+
+// socialStateKey mengembalikan key ChallengeStore untuk state login sosial.
+func socialStateKey(state string) string {
+	return "social:state:" + hashSecret(state)
+}
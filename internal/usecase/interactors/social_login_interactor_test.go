@@ -0,0 +1,349 @@
+package interactors
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"sync"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/services"
+	"fiber-usermanagement/internal/infrastructure/sso"
+
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/google/uuid"
+)
+
+const testOIDCClientID = "usermanagement"
+
+// mockOIDCServer adalah identity provider OpenID Connect tiruan dengan discovery, JWKS dan token
+// endpoint yang memeriksa PKCE S256. Authorization code diterbitkan langsung oleh pengujian lewat authorize.
+type mockOIDCServer struct {
+	*httptest.Server
+	key *rsa.PrivateKey
+
+	mu    sync.Mutex
+	codes map[string]pendingCode
+}
+
+// pendingCode adalah authorization code yang menunggu ditukar di token endpoint.
+type pendingCode struct {
+	challenge string
+	claims    jwt.MapClaims
+}
+
+func newMockOIDCServer(t *testing.T) *mockOIDCServer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	server := &mockOIDCServer{key: key, codes: map[string]pendingCode{}}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{
+			"issuer":                                server.URL,
+			"authorization_endpoint":                server.URL + "/authorize",
+			"token_endpoint":                        server.URL + "/token",
+			"jwks_uri":                              server.URL + "/jwks",
+			"id_token_signing_alg_values_supported": []string{"RS256"},
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		writeJSON(w, http.StatusOK, map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"alg": "RS256",
+			"use": "sig",
+			"kid": "test",
+			"n":   base64.RawURLEncoding.EncodeToString(key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", server.token)
+	server.Server = httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// token menukar authorization code setelah memastikan code_verifier cocok dengan code_challenge.
+func (s *mockOIDCServer) token(w http.ResponseWriter, r *http.Request) {
+	if err := r.ParseForm(); err != nil {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_request"})
+		return
+	}
+
+	s.mu.Lock()
+	pending, ok := s.codes[r.PostForm.Get("code")]
+	delete(s.codes, r.PostForm.Get("code"))
+	s.mu.Unlock()
+
+	sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+	if !ok || base64.RawURLEncoding.EncodeToString(sum[:]) != pending.challenge {
+		writeJSON(w, http.StatusBadRequest, map[string]string{"error": "invalid_grant"})
+		return
+	}
+
+	idToken := jwt.NewWithClaims(jwt.SigningMethodRS256, pending.claims)
+	idToken.Header["kid"] = "test"
+	signed, err := idToken.SignedString(s.key)
+	if err != nil {
+		writeJSON(w, http.StatusInternalServerError, map[string]string{"error": "server_error"})
+		return
+	}
+	writeJSON(w, http.StatusOK, map[string]any{
+		"access_token": "access",
+		"token_type":   "Bearer",
+		"expires_in":   300,
+		"id_token":     signed,
+	})
+}
+
+// authorize mensimulasikan pengguna yang login di provider untuk alamat dari BeginLogin/BeginLink,
+// lalu mengembalikan code dan state yang dikirim provider ke callback. mutate boleh mengubah klaim
+// atau code_challenge sebelum code diterbitkan.
+func (s *mockOIDCServer) authorize(t *testing.T, authURL string, claims jwt.MapClaims, mutate func(*pendingCode)) (code, state string) {
+	t.Helper()
+	parsed, err := url.Parse(authURL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	query := parsed.Query()
+	if query.Get("code_challenge_method") != "S256" {
+		t.Fatalf("code_challenge_method = %q, want S256", query.Get("code_challenge_method"))
+	}
+
+	now := time.Now()
+	full := jwt.MapClaims{
+		"iss":   s.URL,
+		"aud":   testOIDCClientID,
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": query.Get("nonce"),
+	}
+	for name, value := range claims {
+		full[name] = value
+	}
+
+	pending := pendingCode{challenge: query.Get("code_challenge"), claims: full}
+	if mutate != nil {
+		mutate(&pending)
+	}
+
+	code = uuid.NewString()
+	s.mu.Lock()
+	s.codes[code] = pending
+	s.mu.Unlock()
+	return code, query.Get("state")
+}
+
+func writeJSON(w http.ResponseWriter, status int, body any) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	_ = json.NewEncoder(w).Encode(body)
+}
+
+// newTestSocialLogin menyiapkan SocialLoginInteractor dengan provider "mock" yang mengarah ke server tiruan.
+// AuthInteractor tidak disiapkan sehingga pengujian hanya mencakup jalur yang berhenti sebelum login selesai.
+func newTestSocialLogin(t *testing.T, users ...*entities.User) (*SocialLoginInteractor, *mockOIDCServer, *memoryLinkedIdentityRepository) {
+	t.Helper()
+	server := newMockOIDCServer(t)
+	provider := sso.NewOIDCIdentityProvider(sso.OIDCProviderConfig{
+		Name:         "mock",
+		Issuer:       server.URL,
+		ClientID:     testOIDCClientID,
+		ClientSecret: "secret",
+		RedirectURL:  "https://example.com/auth/social/mock/callback",
+	})
+	other := sso.NewOIDCIdentityProvider(sso.OIDCProviderConfig{Name: "other", Issuer: server.URL, ClientID: testOIDCClientID})
+
+	identities := &memoryLinkedIdentityRepository{}
+	interactor := NewSocialLoginInteractor(
+		[]services.IdentityProvider{provider, other},
+		identities,
+		newMemoryUserRepository(users...),
+		newTestChallengeStore(t),
+		nil,
+		nil,
+		SocialLoginPolicy{StateTTL: time.Minute},
+	)
+	return interactor, server, identities
+}
+
+func TestOIDCIdentityProviderExchange(t *testing.T) {
+	server := newMockOIDCServer(t)
+	provider := sso.NewOIDCIdentityProvider(sso.OIDCProviderConfig{Name: "mock", Issuer: server.URL, ClientID: testOIDCClientID})
+	ctx := context.Background()
+
+	authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", "verifier-verifier-verifier-verifier-verifier")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, _ := server.authorize(t, authURL, jwt.MapClaims{"sub": "123", "email": "alice@example.com", "email_verified": "true"}, nil)
+
+	external, err := provider.Exchange(ctx, code, "verifier-verifier-verifier-verifier-verifier", "nonce")
+	if err != nil {
+		t.Fatalf("Exchange: %v", err)
+	}
+	if external.Subject != "123" || external.Email != "alice@example.com" || !external.EmailVerified {
+		t.Fatalf("external = %+v, want verified alice@example.com with subject 123", external)
+	}
+}
+
+func TestOIDCIdentityProviderRejectsTamperedResponses(t *testing.T) {
+	const verifier = "verifier-verifier-verifier-verifier-verifier"
+	tests := []struct {
+		name     string
+		verifier string
+		nonce    string
+		mutate   func(*pendingCode)
+	}{
+		{name: "nonce mismatch", verifier: verifier, nonce: "another-nonce"},
+		{name: "pkce verifier mismatch", verifier: "wrong-verifier-wrong-verifier-wrong-verifier", nonce: "nonce"},
+		{name: "wrong audience", verifier: verifier, nonce: "nonce", mutate: func(p *pendingCode) { p.claims["aud"] = "someone-else" }},
+		{name: "wrong issuer", verifier: verifier, nonce: "nonce", mutate: func(p *pendingCode) { p.claims["iss"] = "https://evil.example" }},
+		{name: "expired id token", verifier: verifier, nonce: "nonce", mutate: func(p *pendingCode) { p.claims["exp"] = time.Now().Add(-time.Hour).Unix() }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := newMockOIDCServer(t)
+			provider := sso.NewOIDCIdentityProvider(sso.OIDCProviderConfig{Name: "mock", Issuer: server.URL, ClientID: testOIDCClientID})
+			ctx := context.Background()
+
+			authURL, err := provider.AuthCodeURL(ctx, "state", "nonce", verifier)
+			if err != nil {
+				t.Fatal(err)
+			}
+			code, _ := server.authorize(t, authURL, jwt.MapClaims{"sub": "123", "email": "alice@example.com", "email_verified": true}, tt.mutate)
+
+			if _, err := provider.Exchange(ctx, code, tt.verifier, tt.nonce); err == nil {
+				t.Fatal("Exchange succeeded, want error")
+			}
+		})
+	}
+}
+
+func TestSocialLoginCallbackRejectsInvalidState(t *testing.T) {
+	ctx := context.Background()
+	interactor, server, _ := newTestSocialLogin(t)
+
+	authURL, err := interactor.BeginLogin(ctx, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := server.authorize(t, authURL, jwt.MapClaims{"sub": "123"}, nil)
+
+	if _, err := interactor.Callback(ctx, "mock", code, state+"x", ClientInfo{}); !errors.Is(err, ErrInvalidSocialState) {
+		t.Fatalf("unknown state err = %v, want ErrInvalidSocialState", err)
+	}
+	if _, err := interactor.Callback(ctx, "other", code, state, ClientInfo{}); !errors.Is(err, ErrInvalidSocialState) {
+		t.Fatalf("state for another provider err = %v, want ErrInvalidSocialState", err)
+	}
+	// State sudah terpakai oleh percobaan sebelumnya walaupun provider-nya salah
+	if _, err := interactor.Callback(ctx, "mock", code, state, ClientInfo{}); !errors.Is(err, ErrInvalidSocialState) {
+		t.Fatalf("reused state err = %v, want ErrInvalidSocialState", err)
+	}
+}
+
+func TestSocialLoginCallbackRejectsNonceAndPKCEMismatch(t *testing.T) {
+	tests := []struct {
+		name   string
+		mutate func(*pendingCode)
+	}{
+		{name: "nonce", mutate: func(p *pendingCode) { p.claims["nonce"] = "replayed-nonce" }},
+		{name: "pkce", mutate: func(p *pendingCode) { p.challenge = "attacker-challenge" }},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			interactor, server, identities := newTestSocialLogin(t)
+
+			authURL, err := interactor.BeginLogin(ctx, "mock")
+			if err != nil {
+				t.Fatal(err)
+			}
+			code, state := server.authorize(t, authURL, jwt.MapClaims{"sub": "123", "email": "alice@example.com", "email_verified": true}, tt.mutate)
+
+			if _, err := interactor.Callback(ctx, "mock", code, state, ClientInfo{}); !errors.Is(err, ErrExternalLoginFailed) {
+				t.Fatalf("err = %v, want ErrExternalLoginFailed", err)
+			}
+			if len(identities.identities) != 0 {
+				t.Fatalf("linked %d identities, want none", len(identities.identities))
+			}
+		})
+	}
+}
+
+func TestSocialLoginRefusesUnverifiedEmail(t *testing.T) {
+	ctx := context.Background()
+	alice := &entities.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", IsActive: true}
+	interactor, server, identities := newTestSocialLogin(t, alice)
+
+	for _, verified := range []any{false, "false", nil} {
+		authURL, err := interactor.BeginLogin(ctx, "mock")
+		if err != nil {
+			t.Fatal(err)
+		}
+		claims := jwt.MapClaims{"sub": "attacker", "email": "alice@example.com"}
+		if verified != nil {
+			claims["email_verified"] = verified
+		}
+		code, state := server.authorize(t, authURL, claims, nil)
+
+		if _, err := interactor.Callback(ctx, "mock", code, state, ClientInfo{}); !errors.Is(err, ErrExternalEmailNotVerified) {
+			t.Fatalf("email_verified=%v err = %v, want ErrExternalEmailNotVerified", verified, err)
+		}
+	}
+	if len(identities.identities) != 0 {
+		t.Fatalf("linked %d identities, want none", len(identities.identities))
+	}
+}
+
+func TestSocialLinkRejectsIdentityOwnedByAnotherUser(t *testing.T) {
+	ctx := context.Background()
+	alice := &entities.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", IsActive: true}
+	bob := &entities.User{ID: uuid.New(), Username: "bob", Email: "bob@example.com", IsActive: true}
+	interactor, server, identities := newTestSocialLogin(t, alice, bob)
+	if err := identities.Create(&entities.LinkedIdentity{UserID: bob.ID, Provider: "mock", Subject: "shared"}); err != nil {
+		t.Fatal(err)
+	}
+
+	authURL, err := interactor.BeginLink(ctx, alice.ID, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state := server.authorize(t, authURL, jwt.MapClaims{"sub": "shared", "email": "alice@example.com", "email_verified": true}, nil)
+
+	if _, err := interactor.Callback(ctx, "mock", code, state, ClientInfo{}); !errors.Is(err, ErrIdentityAlreadyLinked) {
+		t.Fatalf("err = %v, want ErrIdentityAlreadyLinked", err)
+	}
+	if linked, _ := identities.FindByUser(alice.ID); len(linked) != 0 {
+		t.Fatalf("alice has %d identities, want none", len(linked))
+	}
+
+	// Identitas yang belum dimiliki siapa pun berhasil dihubungkan ke pengguna yang memulai BeginLink
+	authURL, err = interactor.BeginLink(ctx, alice.ID, "mock")
+	if err != nil {
+		t.Fatal(err)
+	}
+	code, state = server.authorize(t, authURL, jwt.MapClaims{"sub": "alice-sub", "email": "personal@example.org"}, nil)
+	result, err := interactor.Callback(ctx, "mock", code, state, ClientInfo{})
+	if err != nil {
+		t.Fatalf("Callback: %v", err)
+	}
+	if result.Linked == nil || result.Linked.UserID != alice.ID || result.Linked.Subject != "alice-sub" {
+		t.Fatalf("linked = %+v, want alice-sub linked to alice", result.Linked)
+	}
+}
@@ -0,0 +1,505 @@
+package interactors
+
+import (
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrSoDRuleNotFound dikembalikan jika aturan pemisahan tugas tidak ada.
+	ErrSoDRuleNotFound = errors.New("aturan pemisahan tugas tidak ditemukan")
+	// ErrSoDRuleNameRequired dikembalikan jika nama aturan kosong.
+	ErrSoDRuleNameRequired = errors.New("nama aturan pemisahan tugas wajib diisi")
+	// ErrSoDRuleNameTaken dikembalikan jika nama aturan sudah dipakai aturan lain.
+	ErrSoDRuleNameTaken = errors.New("nama aturan pemisahan tugas sudah dipakai")
+	// ErrSoDRuleRolesInvalid dikembalikan jika aturan mencakup kurang dari dua Role berbeda.
+	ErrSoDRuleRolesInvalid = errors.New("aturan pemisahan tugas harus mencakup minimal dua role berbeda")
+	// ErrSoDRuleRoleNotFound dikembalikan jika salah satu Role aturan tidak terdaftar.
+	ErrSoDRuleRoleNotFound = errors.New("role tidak ditemukan")
+	// ErrSoDViolation dikembalikan jika penetapan membuat pengguna memegang Role yang tidak boleh dipegang bersamaan.
+	ErrSoDViolation = errors.New("penetapan role melanggar aturan pemisahan tugas")
+)
+
+// Tindakan yang dicatat di jejak audit untuk aturan pemisahan tugas.
+const (
+	AuditSoDRuleCreated = "sod_rule.created"
+	AuditSoDRuleUpdated = "sod_rule.updated"
+	AuditSoDRuleDeleted = "sod_rule.deleted"
+)
+
+// SoDRuleInput adalah isian untuk membuat atau mengganti aturan pemisahan tugas.
+type SoDRuleInput struct {
+	Name        string
+	Description string
+	Roles       []string
+}
+
+// SoDViolation adalah pengguna yang saat ini memegang lebih dari satu Role sebuah aturan.
+type SoDViolation struct {
+	UserID   uuid.UUID `json:"user_id"`
+	Username string    `json:"username"`
+	RuleID   uuid.UUID `json:"rule_id"`
+	Rule     string    `json:"rule"`
+	Roles    []string  `json:"roles"` // Role aturan yang dipegang bersamaan, termasuk lewat grup dan pewarisan
+
+	// OrganizationID diisi jika pelanggaran hanya terjadi di organisasi ini, yaitu gabungan Role global
+	// dan Role anggota pengguna di organisasi tersebut
+	OrganizationID *uuid.UUID `json:"organization_id,omitempty"`
+}
+
+// SoDInteractor adalah use case untuk aturan pemisahan tugas statis antar Role. Aturan ditegakkan saat
+// Role diberikan langsung, lewat grup atau grup SCIM, lewat sinkronisasi grup LDAP dan SAML, sebagai Role
+// anggota organisasi, dan saat Role mewarisi Role lain. Role global berlaku di semua organisasi, sedangkan
+// Role anggota hanya digabungkan dengan Role global pengguna di organisasinya sendiri. Penetapan yang sudah
+// ada sebelum aturan dibuat dilaporkan lewat Violations.
+type SoDInteractor struct {
+	ruleRepo  repositories.SoDRuleRepository
+	roleRepo  repositories.RoleRepository
+	groupRepo repositories.GroupRepository
+	userRepo  repositories.UserRepository
+	audit     *AuditInteractor
+}
+
+// NewSoDInteractor membuat instance baru dari SoDInteractor.
+func NewSoDInteractor(
+	sr repositories.SoDRuleRepository,
+	rr repositories.RoleRepository,
+	gr repositories.GroupRepository,
+	ur repositories.UserRepository,
+	audit *AuditInteractor,
+) *SoDInteractor {
+	return &SoDInteractor{ruleRepo: sr, roleRepo: rr, groupRepo: gr, userRepo: ur, audit: audit}
+}
+
+// CreateRule membuat aturan pemisahan tugas baru.
+func (i *SoDInteractor) CreateRule(actorID uuid.UUID, input SoDRuleInput) (*entities.SoDRule, error) {
+	rule := &entities.SoDRule{}
+	roleIDs, err := i.apply(rule, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.ruleRepo.Create(rule); err != nil {
+		return nil, err
+	}
+	if err := i.ruleRepo.ReplaceRoles(rule.ID, roleIDs); err != nil {
+		return nil, err
+	}
+
+	i.audit.Record(actorID, AuditSoDRuleCreated, AuditTargetSoDRule, rule.ID.String(), sodRuleAuditData(rule))
+	return rule, nil
+}
+
+// ListRules mengembalikan semua aturan pemisahan tugas beserta Role-nya.
+func (i *SoDInteractor) ListRules() ([]entities.SoDRule, error) {
+	return i.ruleRepo.FindAll()
+}
+
+// GetRule mengembalikan satu aturan pemisahan tugas beserta Role-nya.
+func (i *SoDInteractor) GetRule(id uuid.UUID) (*entities.SoDRule, error) {
+	rule, err := i.ruleRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrSoDRuleNotFound
+		}
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule mengganti seluruh isi aturan pemisahan tugas. Pelanggaran yang sudah ada tidak dicabut
+// otomatis; lihat Violations.
+func (i *SoDInteractor) UpdateRule(actorID, id uuid.UUID, input SoDRuleInput) (*entities.SoDRule, error) {
+	rule, err := i.GetRule(id)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs, err := i.apply(rule, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := i.ruleRepo.Update(rule); err != nil {
+		return nil, err
+	}
+	if err := i.ruleRepo.ReplaceRoles(rule.ID, roleIDs); err != nil {
+		return nil, err
+	}
+
+	i.audit.Record(actorID, AuditSoDRuleUpdated, AuditTargetSoDRule, rule.ID.String(), sodRuleAuditData(rule))
+	return rule, nil
+}
+
+// DeleteRule menghapus aturan pemisahan tugas.
+func (i *SoDInteractor) DeleteRule(actorID, id uuid.UUID) error {
+	if err := i.ruleRepo.Delete(id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrSoDRuleNotFound
+		}
+		return err
+	}
+
+	i.audit.Record(actorID, AuditSoDRuleDeleted, AuditTargetSoDRule, id.String(), nil)
+	return nil
+}
+
+// CheckUser memastikan pengguna tidak melanggar aturan apa pun jika menerima Role global roleIDs.
+func (i *SoDInteractor) CheckUser(userID uuid.UUID, roleIDs []uuid.UUID) error {
+	return i.check(uuid.Nil, []uuid.UUID{userID}, roleIDs)
+}
+
+// CheckMember memastikan pengguna tidak melanggar aturan apa pun jika menerima Role roleIDs sebagai
+// anggota organisasi organizationID.
+func (i *SoDInteractor) CheckMember(organizationID, userID uuid.UUID, roleIDs []uuid.UUID) error {
+	return i.check(organizationID, []uuid.UUID{userID}, roleIDs)
+}
+
+// CheckGroupMember memastikan pengguna tidak melanggar aturan apa pun jika menjadi anggota grup, yang
+// memberinya Role grup tersebut dan semua grup induknya.
+func (i *SoDInteractor) CheckGroupMember(groupID, userID uuid.UUID) error {
+	roleIDs, err := i.groupRoleIDs(groupID)
+	if err != nil {
+		return err
+	}
+	return i.check(uuid.Nil, []uuid.UUID{userID}, roleIDs)
+}
+
+// CheckSubgroup memastikan anggota grup subgroupID (termasuk anggota subgrupnya) tidak melanggar aturan
+// apa pun jika subgroupID menjadi subgrup groupID.
+func (i *SoDInteractor) CheckSubgroup(groupID, subgroupID uuid.UUID) error {
+	roleIDs, err := i.groupRoleIDs(groupID)
+	if err != nil {
+		return err
+	}
+	userIDs, err := i.groupRepo.FindMemberIDs(subgroupID)
+	if err != nil {
+		return err
+	}
+	return i.check(uuid.Nil, userIDs, roleIDs)
+}
+
+// CheckGroupRoles memastikan anggota grup (termasuk anggota subgrupnya) tidak melanggar aturan apa pun
+// jika grup diberi Role roleIDs.
+func (i *SoDInteractor) CheckGroupRoles(groupID uuid.UUID, roleIDs []uuid.UUID) error {
+	userIDs, err := i.groupRepo.FindMemberIDs(groupID)
+	if err != nil {
+		return err
+	}
+	return i.check(uuid.Nil, userIDs, roleIDs)
+}
+
+// CheckRoleParents memastikan pemegang Role roleID, termasuk lewat grup, lewat keanggotaan organisasi dan
+// lewat Role yang mewarisinya, tidak melanggar aturan apa pun jika Role itu mewarisi parentIDs.
+func (i *SoDInteractor) CheckRoleParents(roleID uuid.UUID, parentIDs []uuid.UUID) error {
+	if len(parentIDs) == 0 {
+		return nil
+	}
+	rules, err := i.ruleRepo.FindAll()
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	graph, err := i.graph()
+	if err != nil {
+		return err
+	}
+	assignments, err := i.roleRepo.FindAllAssignments()
+	if err != nil {
+		return err
+	}
+
+	added := graph.expand(parentIDs)
+	for userID, holdings := range holdingsByUser(assignments) {
+		for _, held := range holdings.contexts(graph, uuid.Nil) {
+			if !held[roleID] {
+				continue
+			}
+			if err := conflictIn(rules, userID, held, added); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// Violations mengembalikan semua pengguna yang saat ini memegang lebih dari satu Role sebuah aturan,
+// diurutkan berdasarkan username, nama aturan lalu organisasi. Pelanggaran yang sudah terjadi pada Role
+// global tidak dilaporkan ulang untuk setiap organisasi pengguna.
+func (i *SoDInteractor) Violations() ([]SoDViolation, error) {
+	violations := []SoDViolation{}
+	rules, err := i.ruleRepo.FindAll()
+	if err != nil || len(rules) == 0 {
+		return violations, err
+	}
+	graph, err := i.graph()
+	if err != nil {
+		return nil, err
+	}
+	assignments, err := i.roleRepo.FindAllAssignments()
+	if err != nil {
+		return nil, err
+	}
+
+	for userID, holdings := range holdingsByUser(assignments) {
+		contexts := holdings.contexts(graph, uuid.Nil)
+		var username string
+		for contextID, held := range contexts {
+			for n := range rules {
+				conflicting := rules[n].Conflicts(held)
+				if len(conflicting) < 2 {
+					continue
+				}
+				if contextID != uuid.Nil && !introducesRole(conflicting, contexts[uuid.Nil]) {
+					continue
+				}
+				if username == "" {
+					user, err := i.userRepo.FindByID(userID)
+					if err != nil {
+						return nil, err
+					}
+					username = user.Username
+				}
+				violation := SoDViolation{
+					UserID:   userID,
+					Username: username,
+					RuleID:   rules[n].ID,
+					Rule:     rules[n].Name,
+					Roles:    roleNames(conflicting),
+				}
+				if contextID != uuid.Nil {
+					organizationID := contextID
+					violation.OrganizationID = &organizationID
+				}
+				violations = append(violations, violation)
+			}
+		}
+	}
+
+	sort.Slice(violations, func(a, b int) bool {
+		if violations[a].Username != violations[b].Username {
+			return violations[a].Username < violations[b].Username
+		}
+		if violations[a].Rule != violations[b].Rule {
+			return violations[a].Rule < violations[b].Rule
+		}
+		return organizationKey(violations[a].OrganizationID) < organizationKey(violations[b].OrganizationID)
+	})
+	return violations, nil
+}
+
+// check menolak penetapan Role roleIDs ke userIDs jika membuat salah satu pengguna memegang lebih dari
+// satu Role sebuah aturan. Role yang diwarisi ikut dihitung. organizationID uuid.Nil berarti Role global,
+// yang diperiksa terhadap Role global pengguna dan Role anggotanya di setiap organisasi; selain itu Role
+// anggota organizationID, yang hanya diperiksa di organisasi tersebut. Pelanggaran yang sudah ada
+// sebelumnya dan tidak disentuh penetapan ini tidak menghalangi.
+func (i *SoDInteractor) check(organizationID uuid.UUID, userIDs, roleIDs []uuid.UUID) error {
+	if len(userIDs) == 0 || len(roleIDs) == 0 {
+		return nil
+	}
+	rules, err := i.ruleRepo.FindAll()
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+	graph, err := i.graph()
+	if err != nil {
+		return err
+	}
+
+	added := graph.expand(roleIDs)
+	for _, userID := range userIDs {
+		assignments, err := i.roleRepo.FindAssignments(userID)
+		if err != nil {
+			return err
+		}
+		for contextID, held := range holdingsByUser(assignments)[userID].contexts(graph, organizationID) {
+			if organizationID != uuid.Nil && contextID != organizationID {
+				continue
+			}
+			if err := conflictIn(rules, userID, held, added); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// roleHoldings adalah Role yang dipegang seorang pengguna tanpa pewarisan, dipisah antara Role global
+// (langsung dan lewat grup) dan Role anggota per organisasi.
+type roleHoldings struct {
+	global        []uuid.UUID
+	organizations map[uuid.UUID][]uuid.UUID
+}
+
+// holdingsByUser mengelompokkan assignments per pengguna.
+func holdingsByUser(assignments []repositories.RoleAssignment) map[uuid.UUID]roleHoldings {
+	byUser := make(map[uuid.UUID]roleHoldings)
+	for _, assignment := range assignments {
+		holdings := byUser[assignment.UserID]
+		if assignment.OrganizationID == nil {
+			holdings.global = append(holdings.global, assignment.RoleID)
+		} else {
+			if holdings.organizations == nil {
+				holdings.organizations = make(map[uuid.UUID][]uuid.UUID)
+			}
+			organizationID := *assignment.OrganizationID
+			holdings.organizations[organizationID] = append(holdings.organizations[organizationID], assignment.RoleID)
+		}
+		byUser[assignment.UserID] = holdings
+	}
+	return byUser
+}
+
+// contexts mengembalikan Role yang dipegang pengguna beserta yang diwarisinya per konteks: uuid.Nil untuk
+// Role global, dan ID setiap organisasi untuk Role global ditambah Role anggota di organisasi itu.
+// organizationID selain uuid.Nil selalu punya konteks, termasuk jika pengguna belum menjadi anggotanya.
+func (h roleHoldings) contexts(graph roleGraph, organizationID uuid.UUID) map[uuid.UUID]map[uuid.UUID]bool {
+	contexts := map[uuid.UUID]map[uuid.UUID]bool{uuid.Nil: graph.expand(h.global)}
+	for id, roleIDs := range h.organizations {
+		contexts[id] = graph.expand(append(append([]uuid.UUID(nil), h.global...), roleIDs...))
+	}
+	if _, ok := contexts[organizationID]; !ok {
+		contexts[organizationID] = contexts[uuid.Nil]
+	}
+	return contexts
+}
+
+// conflictIn mengembalikan ErrSoDViolation jika menambahkan Role added ke Role held membuat pengguna
+// memegang lebih dari satu Role sebuah aturan yang sebelumnya belum dipegangnya bersamaan.
+func conflictIn(rules []entities.SoDRule, userID uuid.UUID, held, added map[uuid.UUID]bool) error {
+	combined := make(map[uuid.UUID]bool, len(held)+len(added))
+	for id := range held {
+		combined[id] = true
+	}
+	for id := range added {
+		combined[id] = true
+	}
+
+	for n := range rules {
+		conflicting := rules[n].Conflicts(combined)
+		if len(conflicting) < 2 || !introducesRole(conflicting, held) {
+			continue
+		}
+		return fmt.Errorf("%w: %s (%s) untuk pengguna %s",
+			ErrSoDViolation, rules[n].Name, strings.Join(roleNames(conflicting), ", "), userID)
+	}
+	return nil
+}
+
+// organizationKey mengembalikan kunci urut organisasi; Role global diurutkan lebih dulu.
+func organizationKey(id *uuid.UUID) string {
+	if id == nil {
+		return ""
+	}
+	return id.String()
+}
+
+// groupRoleIDs mengembalikan ID Role yang diberikan ke grup dan semua grup induknya.
+func (i *SoDInteractor) groupRoleIDs(groupID uuid.UUID) ([]uuid.UUID, error) {
+	roles, err := inheritedGroupRoles(i.groupRepo, groupID)
+	if err != nil {
+		return nil, err
+	}
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	return roleIDs, nil
+}
+
+// inheritedGroupRoles mengembalikan Role yang diberikan ke grup dan semua grup induknya, yaitu Role yang
+// diterima anggota grup tersebut.
+func inheritedGroupRoles(groupRepo repositories.GroupRepository, groupID uuid.UUID) ([]*entities.Role, error) {
+	ancestors, err := groupRepo.FindAncestorIDs(groupID)
+	if err != nil {
+		return nil, err
+	}
+	groups, err := groupRepo.FindAll()
+	if err != nil {
+		return nil, err
+	}
+
+	included := map[uuid.UUID]bool{groupID: true}
+	for _, id := range ancestors {
+		included[id] = true
+	}
+	var roles []*entities.Role
+	for _, group := range groups {
+		if included[group.ID] {
+			roles = append(roles, group.Roles...)
+		}
+	}
+	return roles, nil
+}
+
+func (i *SoDInteractor) graph() (roleGraph, error) {
+	roles, err := i.roleRepo.FindGraph()
+	if err != nil {
+		return nil, err
+	}
+	return newRoleGraph(roles), nil
+}
+
+// apply memvalidasi input, menyalinnya ke rule dan mengembalikan ID Role aturan.
+func (i *SoDInteractor) apply(rule *entities.SoDRule, input SoDRuleInput) ([]uuid.UUID, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrSoDRuleNameRequired
+	}
+	existing, err := i.ruleRepo.FindByName(name)
+	if err == nil && existing.ID != rule.ID {
+		return nil, ErrSoDRuleNameTaken
+	}
+	if err != nil && !errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, err
+	}
+
+	roles, err := resolveRoles(i.roleRepo, input.Roles, ErrSoDRuleRoleNotFound)
+	if err != nil {
+		return nil, err
+	}
+	if len(roles) < 2 {
+		return nil, ErrSoDRuleRolesInvalid
+	}
+
+	roleIDs := make([]uuid.UUID, 0, len(roles))
+	for _, role := range roles {
+		roleIDs = append(roleIDs, role.ID)
+	}
+	rule.Name = name
+	rule.Description = input.Description
+	rule.Roles = roles
+	return roleIDs, nil
+}
+
+// introducesRole mengembalikan true jika salah satu Role roles belum ada di held.
+func introducesRole(roles []*entities.Role, held map[uuid.UUID]bool) bool {
+	for _, role := range roles {
+		if !held[role.ID] {
+			return true
+		}
+	}
+	return false
+}
+
+// roleNames mengembalikan nama roles sesuai urutannya.
+func roleNames(roles []*entities.Role) []string {
+	names := make([]string, 0, len(roles))
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return names
+}
+
+// sodRuleAuditData mengembalikan isi aturan untuk jejak audit.
+func sodRuleAuditData(rule *entities.SoDRule) map[string]any {
+	return map[string]any{
+		"name":        rule.Name,
+		"description": rule.Description,
+		"roles":       roleNames(rule.Roles),
+	}
+}
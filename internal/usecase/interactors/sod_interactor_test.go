@@ -0,0 +1,167 @@
+package interactors
+
+import (
+	"errors"
+	"testing"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+// staticSoDRuleRepository adalah SoDRuleRepository uji dengan aturan tetap.
+type staticSoDRuleRepository struct {
+	repositories.SoDRuleRepository
+	rules []entities.SoDRule
+}
+
+func (r *staticSoDRuleRepository) FindAll() ([]entities.SoDRule, error) {
+	return r.rules, nil
+}
+
+// graphRoleRepository adalah RoleRepository uji dengan pewarisan Role dan penetapan tetap.
+type graphRoleRepository struct {
+	repositories.RoleRepository
+	roles       []entities.Role
+	assignments []repositories.RoleAssignment
+	replaced    bool // true jika ReplaceParents dipanggil
+}
+
+func (r *graphRoleRepository) FindByID(id uuid.UUID) (*entities.Role, error) {
+	for n := range r.roles {
+		if r.roles[n].ID == id {
+			return &r.roles[n], nil
+		}
+	}
+	return nil, gorm.ErrRecordNotFound
+}
+
+func (r *graphRoleRepository) FindByNames(names []string) ([]entities.Role, error) {
+	var found []entities.Role
+	for _, role := range r.roles {
+		for _, name := range names {
+			if role.Name == name {
+				found = append(found, role)
+			}
+		}
+	}
+	return found, nil
+}
+
+func (r *graphRoleRepository) FindGraph() ([]entities.Role, error) {
+	return append([]entities.Role(nil), r.roles...), nil
+}
+
+func (r *graphRoleRepository) FindAllAssignments() ([]repositories.RoleAssignment, error) {
+	return r.assignments, nil
+}
+
+func (r *graphRoleRepository) FindAssignments(userID uuid.UUID) ([]repositories.RoleAssignment, error) {
+	var assignments []repositories.RoleAssignment
+	for _, assignment := range r.assignments {
+		if assignment.UserID == userID {
+			assignments = append(assignments, assignment)
+		}
+	}
+	return assignments, nil
+}
+
+func (r *graphRoleRepository) ReplaceParents(uuid.UUID, []uuid.UUID) error {
+	r.replaced = true
+	return nil
+}
+
+func TestSetParentsChecksRoleHolders(t *testing.T) {
+	// operator dan auditor tidak boleh dipegang bersamaan. support akan mewarisi operator; helpdesk
+	// sudah mewarisi support sehingga pemegangnya ikut menerima operator.
+	operator := entities.Role{ID: uuid.New(), Name: "operator"}
+	auditor := entities.Role{ID: uuid.New(), Name: "auditor"}
+	support := entities.Role{ID: uuid.New(), Name: "support"}
+	helpdesk := entities.Role{ID: uuid.New(), Name: "helpdesk", Parents: []*entities.Role{&support}}
+	rules := &staticSoDRuleRepository{rules: []entities.SoDRule{{
+		Name:  "operasi-audit",
+		Roles: []*entities.Role{&operator, &auditor},
+	}}}
+
+	tests := []struct {
+		name    string
+		holding []uuid.UUID // Role yang dipegang pengguna yang juga memegang auditor
+		wantErr bool
+	}{
+		{name: "pemegang langsung", holding: []uuid.UUID{support.ID}, wantErr: true},
+		{name: "pemegang lewat pewarisan", holding: []uuid.UUID{helpdesk.ID}, wantErr: true},
+		{name: "bukan pemegang", holding: []uuid.UUID{operator.ID}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			userID := uuid.New()
+			roleRepo := &graphRoleRepository{
+				roles:       []entities.Role{operator, auditor, support, helpdesk},
+				assignments: []repositories.RoleAssignment{{UserID: userID, RoleID: auditor.ID}},
+			}
+			for _, roleID := range tt.holding {
+				roleRepo.assignments = append(roleRepo.assignments, repositories.RoleAssignment{UserID: userID, RoleID: roleID})
+			}
+			sod := NewSoDInteractor(rules, roleRepo, nil, nil, nil)
+			grants := NewRoleGrantInteractor(roleRepo, nil, &staticApprovalPolicyRepository{}, sod, nil, nil)
+			interactor := NewRoleInteractor(roleRepo, nil, nil, sod, grants)
+
+			_, err := interactor.SetParents(support.ID, []string{"operator"})
+			if tt.wantErr {
+				if !errors.Is(err, ErrSoDViolation) {
+					t.Fatalf("err = %v, ingin ErrSoDViolation", err)
+				}
+				if roleRepo.replaced {
+					t.Fatal("induk role tetap diganti meskipun melanggar aturan")
+				}
+				return
+			}
+			if err := sod.CheckRoleParents(support.ID, []uuid.UUID{operator.ID}); err != nil {
+				t.Fatalf("CheckRoleParents: %v", err)
+			}
+		})
+	}
+}
+
+func TestViolationsIncludeOrganizationMemberRoles(t *testing.T) {
+	operator := entities.Role{ID: uuid.New(), Name: "operator"}
+	auditor := entities.Role{ID: uuid.New(), Name: "auditor"}
+	rules := &staticSoDRuleRepository{rules: []entities.SoDRule{{
+		ID:    uuid.New(),
+		Name:  "operasi-audit",
+		Roles: []*entities.Role{&operator, &auditor},
+	}}}
+	alice := &entities.User{ID: uuid.New(), Username: "alice"}
+	bob := &entities.User{ID: uuid.New(), Username: "bob"}
+	acme, globex := uuid.New(), uuid.New()
+	roleRepo := &graphRoleRepository{
+		roles: []entities.Role{operator, auditor},
+		assignments: []repositories.RoleAssignment{
+			// alice memegang operator secara global dan auditor sebagai anggota acme
+			{UserID: alice.ID, RoleID: operator.ID},
+			{UserID: alice.ID, RoleID: auditor.ID, OrganizationID: &acme},
+			// bob memegang kedua Role secara global: dilaporkan sekali meskipun anggota dua organisasi
+			{UserID: bob.ID, RoleID: operator.ID},
+			{UserID: bob.ID, RoleID: auditor.ID},
+			{UserID: bob.ID, RoleID: operator.ID, OrganizationID: &acme},
+			{UserID: bob.ID, RoleID: auditor.ID, OrganizationID: &globex},
+		},
+	}
+	sod := NewSoDInteractor(rules, roleRepo, nil, newMemoryUserRepository(alice, bob), nil)
+
+	violations, err := sod.Violations()
+	if err != nil {
+		t.Fatalf("Violations: %v", err)
+	}
+	if len(violations) != 2 {
+		t.Fatalf("jumlah pelanggaran = %d, ingin 2: %+v", len(violations), violations)
+	}
+	if got := violations[0]; got.Username != "alice" || got.OrganizationID == nil || *got.OrganizationID != acme {
+		t.Fatalf("pelanggaran pertama = %+v, ingin alice di acme", got)
+	}
+	if got := violations[1]; got.Username != "bob" || got.OrganizationID != nil {
+		t.Fatalf("pelanggaran kedua = %+v, ingin bob secara global", got)
+	}
+}
@@ -0,0 +1,299 @@
+package interactors
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
+
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrInvalidToken dikembalikan jika token akses tidak bisa diverifikasi atau sudah kedaluwarsa.
+	ErrInvalidToken = errors.New("token tidak valid")
+	// ErrTokenRevoked dikembalikan jika token akses dicabut sebelum kedaluwarsa.
+	ErrTokenRevoked = errors.New("token sudah dicabut")
+)
+
+// Jenis token yang dilaporkan introspeksi (RFC 7662 bagian 2.2).
+const (
+	TokenTypeAccessToken  = "access_token"
+	TokenTypeRefreshToken = "refresh_token"
+	TokenTypeAPIKey       = "api_key"
+)
+
+// TokenIntrospection adalah status token untuk resource server. Jika Active bernilai false,
+// field lain sengaja dibiarkan kosong agar tidak membocorkan informasi tentang token.
+type TokenIntrospection struct {
+	Active    bool
+	TokenType string
+	TokenID   string
+	Subject   uuid.UUID
+	Username  string
+	ClientID  string
+	Scopes    []string // Nil untuk token login yang tidak dibatasi scope
+	IssuedAt  time.Time
+	ExpiresAt time.Time // Nol untuk API key tanpa masa berlaku
+}
+
+// TokenInteractor adalah use case validasi, introspeksi dan pencabutan semua jenis token
+// yang diterbitkan layanan ini: token akses, refresh token OAuth dan API key.
+type TokenInteractor struct {
+	tokenService     services.TokenService
+	sessions         *SessionInteractor
+	apiKeys          *APIKeyInteractor
+	refreshTokenRepo repositories.OAuthRefreshTokenRepository
+	denylist         repositories.TokenDenylist
+}
+
+// NewTokenInteractor membuat instance baru dari TokenInteractor.
+func NewTokenInteractor(
+	ts services.TokenService,
+	sessions *SessionInteractor,
+	apiKeys *APIKeyInteractor,
+	rr repositories.OAuthRefreshTokenRepository,
+	denylist repositories.TokenDenylist,
+) *TokenInteractor {
+	return &TokenInteractor{
+		tokenService:     ts,
+		sessions:         sessions,
+		apiKeys:          apiKeys,
+		refreshTokenRepo: rr,
+		denylist:         denylist,
+	}
+}
+
+// ValidateAccessToken memverifikasi token akses dan memastikan belum dicabut: token yang terikat sesi
+// ditolak jika sesinya berakhir (ErrSessionRevoked), token client_credentials ditolak jika ada di denylist.
+// Token impersonasi hanya diterima untuk sesi impersonasi milik pelaku yang sama.
+func (i *TokenInteractor) ValidateAccessToken(ctx context.Context, token string) (*services.TokenClaims, error) {
+	claims, err := i.tokenService.ParseAccessToken(token)
+	if err != nil {
+		return nil, ErrInvalidToken
+	}
+
+	if claims.IsClientToken() {
+		revoked, err := i.denylist.Contains(ctx, claims.ID)
+		if err != nil {
+			return nil, err
+		}
+		if revoked {
+			return nil, ErrTokenRevoked
+		}
+		return claims, nil
+	}
+
+	session, err := i.sessions.Validate(ctx, claims.SessionID, claims.UserID)
+	if err != nil {
+		return nil, err
+	}
+	if claims.IsImpersonation() != session.IsImpersonation() ||
+		(session.IsImpersonation() && *session.ImpersonatorID != claims.ActorID) {
+		return nil, ErrInvalidToken
+	}
+	return claims, nil
+}
+
+// Introspect mengembalikan status token untuk klien yang sudah diautentikasi (RFC 7662).
+// Klien tanpa hak introspeksi hanya bisa melihat token yang diterbitkan untuknya sendiri;
+// token lain dilaporkan tidak aktif.
+func (i *TokenInteractor) Introspect(ctx context.Context, client *entities.OAuthClient, token string) (*TokenIntrospection, error) {
+	inactive := &TokenIntrospection{Active: false}
+
+	var result *TokenIntrospection
+	var err error
+	switch {
+	case i.apiKeys.IsAPIKey(token):
+		result, err = i.introspectAPIKey(token)
+	case isJWT(token):
+		result, err = i.introspectAccessToken(ctx, token)
+	default:
+		result, err = i.introspectRefreshToken(ctx, token)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if result == nil || !canManageToken(client, result.ClientID) {
+		return inactive, nil
+	}
+	return result, nil
+}
+
+// Revoke mencabut token untuk klien yang sudah diautentikasi (RFC 7009). Token yang tidak dikenal,
+// sudah tidak aktif atau kedaluwarsa dianggap berhasil dicabut. Mencabut token OAuth milik pengguna
+// juga mengakhiri sesinya, sehingga token akses dan refresh token dari persetujuan yang sama ikut tercabut.
+func (i *TokenInteractor) Revoke(ctx context.Context, client *entities.OAuthClient, token string) error {
+	switch {
+	case i.apiKeys.IsAPIKey(token):
+		return i.revokeAPIKey(client, token)
+	case isJWT(token):
+		return i.revokeAccessToken(ctx, client, token)
+	default:
+		return i.revokeRefreshToken(ctx, client, token)
+	}
+}
+
+// introspectAccessToken memeriksa token akses. Nil berarti token tidak aktif.
+func (i *TokenInteractor) introspectAccessToken(ctx context.Context, token string) (*TokenIntrospection, error) {
+	claims, err := i.ValidateAccessToken(ctx, token)
+	if err != nil {
+		if errors.Is(err, ErrInvalidToken) || errors.Is(err, ErrTokenRevoked) || errors.Is(err, ErrSessionRevoked) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &TokenIntrospection{
+		Active:    true,
+		TokenType: TokenTypeAccessToken,
+		TokenID:   claims.ID,
+		Subject:   claims.UserID,
+		Username:  claims.Username,
+		ClientID:  claims.ClientID,
+		Scopes:    claims.Scopes,
+		IssuedAt:  claims.IssuedAt,
+		ExpiresAt: claims.ExpiresAt,
+	}, nil
+}
+
+// introspectRefreshToken memeriksa refresh token OAuth. Nil berarti token tidak aktif.
+func (i *TokenInteractor) introspectRefreshToken(ctx context.Context, token string) (*TokenIntrospection, error) {
+	refresh, err := i.findRefreshToken(token)
+	if err != nil || refresh == nil {
+		return nil, err
+	}
+
+	if _, err := i.sessions.Validate(ctx, refresh.SessionID, refresh.UserID); err != nil {
+		if errors.Is(err, ErrSessionRevoked) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	return &TokenIntrospection{
+		Active:    true,
+		TokenType: TokenTypeRefreshToken,
+		TokenID:   refresh.ID.String(),
+		Subject:   refresh.UserID,
+		ClientID:  refresh.ClientID,
+		Scopes:    refresh.Scopes,
+		IssuedAt:  refresh.CreatedAt,
+		ExpiresAt: refresh.ExpiresAt,
+	}, nil
+}
+
+// introspectAPIKey memeriksa API key tanpa mencatatnya sebagai pemakaian. Nil berarti kunci tidak aktif.
+func (i *TokenInteractor) introspectAPIKey(token string) (*TokenIntrospection, error) {
+	key, user, err := i.apiKeys.Inspect(token)
+	if err != nil {
+		if errors.Is(err, ErrInvalidAPIKey) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	result := &TokenIntrospection{
+		Active:    true,
+		TokenType: TokenTypeAPIKey,
+		TokenID:   key.ID.String(),
+		Subject:   user.ID,
+		Username:  user.Username,
+		Scopes:    key.Scopes,
+		IssuedAt:  key.CreatedAt,
+	}
+	if key.ExpiresAt != nil {
+		result.ExpiresAt = *key.ExpiresAt
+	}
+	return result, nil
+}
+
+// revokeAccessToken mencabut token akses: sesi untuk token yang terikat sesi, denylist untuk token client_credentials.
+func (i *TokenInteractor) revokeAccessToken(ctx context.Context, client *entities.OAuthClient, token string) error {
+	claims, err := i.tokenService.ParseAccessToken(token)
+	if err != nil {
+		return nil // Token tidak valid atau kedaluwarsa tidak perlu dicabut
+	}
+	if !canManageToken(client, claims.ClientID) {
+		return &OAuthError{Code: OAuthErrUnauthorizedClient, Description: "token tidak diterbitkan untuk klien ini"}
+	}
+
+	if claims.IsClientToken() {
+		return i.denylist.Add(ctx, claims.ID, claims.ExpiresAt)
+	}
+	return i.endSession(ctx, claims.UserID, claims.SessionID)
+}
+
+// revokeRefreshToken mencabut refresh token beserta sesinya.
+func (i *TokenInteractor) revokeRefreshToken(ctx context.Context, client *entities.OAuthClient, token string) error {
+	refresh, err := i.findRefreshToken(token)
+	if err != nil || refresh == nil {
+		return err
+	}
+	if !canManageToken(client, refresh.ClientID) {
+		return &OAuthError{Code: OAuthErrUnauthorizedClient, Description: "token tidak diterbitkan untuk klien ini"}
+	}
+	return i.endSession(ctx, refresh.UserID, refresh.SessionID)
+}
+
+// revokeAPIKey mencabut API key. API key tidak diterbitkan untuk klien OAuth, sehingga hanya klien
+// dengan hak introspeksi yang boleh mencabutnya.
+func (i *TokenInteractor) revokeAPIKey(client *entities.OAuthClient, token string) error {
+	key, _, err := i.apiKeys.Inspect(token)
+	if err != nil {
+		if errors.Is(err, ErrInvalidAPIKey) {
+			return nil
+		}
+		return err
+	}
+	if !canManageToken(client, "") {
+		return &OAuthError{Code: OAuthErrUnauthorizedClient, Description: "klien tidak berhak mencabut API key"}
+	}
+
+	if err := i.apiKeys.Revoke(key.UserID, key.ID); err != nil && !errors.Is(err, ErrAPIKeyNotFound) {
+		return err
+	}
+	return nil
+}
+
+// endSession mengakhiri sesi beserta semua refresh token yang terikat padanya.
+func (i *TokenInteractor) endSession(ctx context.Context, userID, sessionID uuid.UUID) error {
+	if err := i.refreshTokenRepo.RevokeBySession(sessionID, time.Now()); err != nil {
+		return err
+	}
+	if err := i.sessions.Revoke(ctx, userID, sessionID); err != nil && !errors.Is(err, ErrSessionNotFound) {
+		return err
+	}
+	return nil
+}
+
+// findRefreshToken mencari refresh token yang masih aktif. Nil berarti token tidak dikenal atau tidak aktif.
+func (i *TokenInteractor) findRefreshToken(token string) (*entities.OAuthRefreshToken, error) {
+	refresh, err := i.refreshTokenRepo.FindByHash(hashSecret(token))
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	if !refresh.IsActive(time.Now()) {
+		return nil, nil
+	}
+	return refresh, nil
+}
+
+// canManageToken mengembalikan true jika klien boleh memeriksa atau mencabut token milik tokenClientID.
+// tokenClientID kosong berarti token tidak diterbitkan untuk klien OAuth (token login atau API key).
+func canManageToken(client *entities.OAuthClient, tokenClientID string) bool {
+	return client.Introspection || (tokenClientID != "" && tokenClientID == client.ClientID)
+}
+
+// isJWT mengembalikan true jika token berbentuk JWT (tiga bagian dipisah titik).
+func isJWT(token string) bool {
+	return strings.Count(token, ".") == 2
+}
@@ -2,23 +2,44 @@ package interactors
 
 import (
 	"errors"
+	"strings"
 
 	"fiber-usermanagement/internal/domain/entities"
 	"fiber-usermanagement/internal/domain/repositories"
+	"fiber-usermanagement/internal/domain/services"
 
+	"github.com/google/uuid"
 	"gorm.io/gorm"
 )
 
+var (
+	// ErrUserNotFound dikembalikan jika pengguna yang diubah tidak ada atau berada di luar organisasi.
+	ErrUserNotFound = errors.New("pengguna tidak ditemukan")
+	// ErrUserEmailRequired dikembalikan jika email pengguna dikosongkan.
+	ErrUserEmailRequired = errors.New("email pengguna wajib diisi")
+	// ErrUserEmailShared dikembalikan jika admin organisasi mengganti email pengguna yang juga anggota
+	// organisasi lain. Email dipakai untuk menghubungkan login eksternal, sehingga hanya admin global
+	// yang boleh menggantinya.
+	ErrUserEmailShared = errors.New("email pengguna yang juga anggota organisasi lain tidak dapat diubah")
+)
+
 // UserInteractor adalah use case untuk operasi terkait entitas User.
 // Ini mengimplementasikan logika bisnis yang berinteraksi dengan UserRepository.
 type UserInteractor struct {
-	userRepo repositories.UserRepository // Dependensi ke interface UserRepository
+	userRepo       repositories.UserRepository // Dependensi ke interface UserRepository
+	hasher         services.PasswordHasher     // Dependensi untuk hashing password
+	organizationID uuid.UUID                   // Organisasi yang membatasi interactor; uuid.Nil berarti tanpa batasan
 }
 
 // NewUserInteractor membuat instance baru dari UserInteractor.
-// Menerima implementasi UserRepository untuk dipasangkan.
-func NewUserInteractor(ur repositories.UserRepository) *UserInteractor {
-	return &UserInteractor{userRepo: ur}
+// Menerima implementasi UserRepository dan PasswordHasher untuk dipasangkan.
+func NewUserInteractor(ur repositories.UserRepository, hasher services.PasswordHasher) *UserInteractor {
+	return &UserInteractor{userRepo: ur, hasher: hasher}
+}
+
+// ForOrganization mengembalikan UserInteractor yang hanya bisa membaca dan mengubah anggota organisasi tersebut.
+func (i *UserInteractor) ForOrganization(organizationID uuid.UUID) *UserInteractor {
+	return &UserInteractor{userRepo: i.userRepo.InOrganization(organizationID), hasher: i.hasher, organizationID: organizationID}
 }
 
 // CreateUser adalah use case untuk membuat pengguna baru.
@@ -28,15 +49,110 @@ func (i *UserInteractor) CreateUser(user *entities.User) (*entities.User, error)
 	if user.Email == "" || user.Password == "" {
 		return nil, errors.New("email dan password tidak boleh kosong")
 	}
-	// TODO: Dalam aplikasi nyata, Anda harus melakukan hashing password di sini
-	// Contoh: user.Password, _ = bcrypt.GenerateFromPassword([]byte(user.Password), bcrypt.DefaultCost)
+
+	// Simpan hanya hash password, tidak pernah plaintext
+	hash, err := i.hasher.Hash(user.Password)
+	if err != nil {
+		return nil, err
+	}
+	user.Password = hash
 
 	// Panggil repository untuk menyimpan data
 	return i.userRepo.Create(user)
 }
 
+// CreateServiceAccount adalah use case untuk membuat service account bagi klien mesin.
+// Service account tidak bisa login dengan password; aksesnya hanya melalui API key.
+func (i *UserInteractor) CreateServiceAccount(username, email, displayName string) (*entities.User, error) {
+	username = strings.TrimSpace(username)
+	if username == "" {
+		return nil, errors.New("username tidak boleh kosong")
+	}
+	if email == "" {
+		// Kolom email wajib unik, domain .invalid menjamin alamat ini tidak pernah bisa dikirimi email
+		email = username + "@service-account.invalid"
+	}
+
+	// Password acak yang tidak pernah diketahui siapa pun, sekadar mengisi kolom wajib
+	password, err := randomString(apiKeySecretLength)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := i.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.userRepo.Create(&entities.User{
+		Username:         username,
+		Email:            email,
+		Password:         hash,
+		FirstName:        displayName,
+		IsActive:         true,
+		IsServiceAccount: true,
+	})
+}
+
+// ProvisionExternalUser adalah use case untuk membuat pengguna saat pertama kali login lewat
+// direktori atau identity provider eksternal (just-in-time provisioning). Password diisi acak
+// karena pengguna login lewat sumber eksternal; username dibuat unik jika sudah dipakai.
+func (i *UserInteractor) ProvisionExternalUser(username, email, firstName, lastName string) (*entities.User, error) {
+	email = strings.TrimSpace(email)
+	if email == "" {
+		return nil, errors.New("email tidak boleh kosong")
+	}
+
+	username = strings.TrimSpace(username)
+	if username == "" {
+		username, _, _ = strings.Cut(email, "@")
+	}
+	username, err := i.availableUsername(username)
+	if err != nil {
+		return nil, err
+	}
+
+	password, err := randomString(apiKeySecretLength)
+	if err != nil {
+		return nil, err
+	}
+	hash, err := i.hasher.Hash(password)
+	if err != nil {
+		return nil, err
+	}
+
+	return i.userRepo.Create(&entities.User{
+		Username:  username,
+		Email:     email,
+		Password:  hash,
+		FirstName: firstName,
+		LastName:  lastName,
+		IsActive:  true,
+	})
+}
+
+// availableUsername mengembalikan username jika belum dipakai, atau username dengan akhiran acak.
+func (i *UserInteractor) availableUsername(username string) (string, error) {
+	candidate := username
+	for attempt := 0; attempt < 5; attempt++ {
+		_, err := i.userRepo.FindByUsernameOrEmail(candidate)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return candidate, nil
+		}
+		if err != nil {
+			return "", err
+		}
+
+		suffix, err := randomString(4)
+		if err != nil {
+			return "", err
+		}
+		candidate = username + "-" + strings.ToLower(suffix)
+	}
+	return "", errors.New("gagal membuat username unik")
+}
+
 // GetUserByID adalah use case untuk mendapatkan pengguna berdasarkan ID.
-func (i *UserInteractor) GetUserByID(id uint) (*entities.User, error) {
+func (i *UserInteractor) GetUserByID(id uuid.UUID) (*entities.User, error) {
 	// Panggil repository untuk mengambil data
 	user, err := i.userRepo.FindByID(id)
 	if err != nil {
@@ -49,6 +165,19 @@ func (i *UserInteractor) GetUserByID(id uint) (*entities.User, error) {
 	return user, nil
 }
 
+// SetAttributes adalah use case untuk mengganti atribut bebas pengguna yang dipakai kebijakan ABAC.
+func (i *UserInteractor) SetAttributes(id uuid.UUID, attributes map[string]any) (*entities.User, error) {
+	user, err := i.userRepo.FindByID(id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrUserNotFound
+		}
+		return nil, err
+	}
+	user.Attributes = attributes
+	return i.userRepo.Update(user)
+}
+
 // GetAllUsers adalah use case untuk mendapatkan semua pengguna.
 func (i *UserInteractor) GetAllUsers() ([]entities.User, error) {
 	// Panggil repository untuk mengambil semua data
@@ -57,7 +186,13 @@ func (i *UserInteractor) GetAllUsers() ([]entities.User, error) {
 
 // UpdateUser adalah use case untuk memperbarui pengguna.
 // Ini mengambil pengguna yang ada, memperbarui bidang yang diizinkan, dan menyimpan perubahan.
-func (i *UserInteractor) UpdateUser(id uint, user *entities.User) (*entities.User, error) {
+// Email tidak boleh kosong, dan interactor organisasi tidak boleh mengganti email pengguna yang juga
+// anggota organisasi lain karena login eksternal dihubungkan ke akun lewat email.
+func (i *UserInteractor) UpdateUser(id uuid.UUID, user *entities.User) (*entities.User, error) {
+	if strings.TrimSpace(user.Email) == "" {
+		return nil, ErrUserEmailRequired
+	}
+
 	// Ambil pengguna yang ada terlebih dahulu
 	existingUser, err := i.userRepo.FindByID(id)
 	if err != nil {
@@ -67,6 +202,18 @@ func (i *UserInteractor) UpdateUser(id uint, user *entities.User) (*entities.Use
 		return nil, err
 	}
 
+	if existingUser.Email != user.Email && i.organizationID != uuid.Nil {
+		organizationIDs, err := i.userRepo.FindOrganizationIDs(id)
+		if err != nil {
+			return nil, err
+		}
+		for _, organizationID := range organizationIDs {
+			if organizationID != i.organizationID {
+				return nil, ErrUserEmailShared
+			}
+		}
+	}
+
 	// Perbarui hanya field yang diizinkan oleh logika bisnis
 	existingUser.FirstName = user.FirstName
 	existingUser.Email = user.Email
@@ -78,7 +225,7 @@ func (i *UserInteractor) UpdateUser(id uint, user *entities.User) (*entities.Use
 
 // DeleteUser adalah use case untuk menghapus pengguna.
 // Ini dapat mencakup logika bisnis pra-penghapusan, seperti memeriksa dependensi.
-func (i *UserInteractor) DeleteUser(id uint) error {
+func (i *UserInteractor) DeleteUser(id uuid.UUID) error {
 	// Contoh logika bisnis: periksa apakah pengguna memiliki relasi yang tidak boleh dihapus
 	// Misalnya, jika pengguna memiliki pesanan aktif, mungkin tidak bisa dihapus.
 
@@ -0,0 +1,60 @@
+package interactors
+
+import (
+	"errors"
+	"testing"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/google/uuid"
+)
+
+// memberUserRepository adalah memoryUserRepository yang mengetahui keanggotaan organisasi setiap pengguna.
+type memberUserRepository struct {
+	*memoryUserRepository
+	organizations map[uuid.UUID][]uuid.UUID
+}
+
+func (r *memberUserRepository) FindOrganizationIDs(userID uuid.UUID) ([]uuid.UUID, error) {
+	return r.organizations[userID], nil
+}
+
+func TestUpdateUserProtectsEmail(t *testing.T) {
+	acme, globex := uuid.New(), uuid.New()
+	alice := &entities.User{ID: uuid.New(), Username: "alice", Email: "alice@acme.test"}
+	bob := &entities.User{ID: uuid.New(), Username: "bob", Email: "bob@acme.test"}
+	repo := &memberUserRepository{
+		memoryUserRepository: newMemoryUserRepository(alice, bob),
+		organizations:        map[uuid.UUID][]uuid.UUID{alice.ID: {acme}, bob.ID: {acme, globex}},
+	}
+	users := &UserInteractor{userRepo: repo, organizationID: acme}
+
+	tests := []struct {
+		name    string
+		users   *UserInteractor
+		user    *entities.User
+		email   string
+		wantErr error
+	}{
+		{"email kosong", users, alice, " ", ErrUserEmailRequired},
+		{"anggota satu organisasi", users, alice, "alice@example.test", nil},
+		{"anggota organisasi lain", users, bob, "attacker@example.test", ErrUserEmailShared},
+		{"email tidak berubah", users, bob, "bob@acme.test", nil},
+		{"admin global", &UserInteractor{userRepo: repo}, bob, "bob@example.test", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := tt.users.UpdateUser(tt.user.ID, &entities.User{FirstName: "Baru", Email: tt.email})
+			if !errors.Is(err, tt.wantErr) {
+				t.Fatalf("err = %v, ingin %v", err, tt.wantErr)
+			}
+			stored, _ := repo.FindByID(tt.user.ID)
+			if tt.wantErr == nil && stored.Email != tt.email {
+				t.Fatalf("email = %q, ingin %q", stored.Email, tt.email)
+			}
+			if tt.wantErr != nil && stored.FirstName == "Baru" {
+				t.Fatal("pengguna tetap diperbarui")
+			}
+		})
+	}
+}
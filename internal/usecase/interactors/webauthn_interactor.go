@@ -0,0 +1,359 @@
+package interactors
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"log"
+	"strings"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+	"fiber-usermanagement/internal/domain/repositories"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+	"gorm.io/gorm"
+)
+
+var (
+	// ErrPasskeyNotFound dikembalikan jika passkey tidak ada atau bukan milik pengguna.
+	ErrPasskeyNotFound = errors.New("passkey tidak ditemukan")
+	// ErrPasskeyNameRequired dikembalikan jika nama passkey kosong.
+	ErrPasskeyNameRequired = errors.New("nama passkey wajib diisi")
+	// ErrPasskeyCeremonyExpired dikembalikan jika ceremony WebAuthn belum dimulai, sudah dipakai, atau kedaluwarsa.
+	ErrPasskeyCeremonyExpired = errors.New("sesi WebAuthn tidak ditemukan atau kedaluwarsa")
+	// ErrPasskeyVerificationFailed dikembalikan jika respons authenticator tidak lolos verifikasi.
+	ErrPasskeyVerificationFailed = errors.New("verifikasi passkey gagal")
+	// ErrPasskeyNotRegistered dikembalikan jika pengguna belum memiliki passkey.
+	ErrPasskeyNotRegistered = errors.New("pengguna belum memiliki passkey")
+)
+
+// passkeyNameMaxLength membatasi panjang nama passkey yang diberikan pengguna.
+const passkeyNameMaxLength = 64
+
+// registrationCeremony adalah data ceremony registrasi yang disimpan di antara begin dan finish.
+type registrationCeremony struct {
+	Session webauthn.SessionData `json:"session"`
+	Name    string               `json:"name"`
+}
+
+// WebAuthnInteractor adalah use case untuk ceremony WebAuthn (passkey) dan pengelolaan kredensial.
+// Data sesi ceremony disimpan di ChallengeStore sehingga setiap tantangan hanya bisa dipakai sekali.
+type WebAuthnInteractor struct {
+	userRepo       repositories.UserRepository
+	credentialRepo repositories.WebAuthnCredentialRepository
+	challengeStore repositories.ChallengeStore
+	webAuthn       *webauthn.WebAuthn
+	ceremonyTTL    time.Duration
+}
+
+// NewWebAuthnInteractor membuat instance baru dari WebAuthnInteractor.
+func NewWebAuthnInteractor(
+	ur repositories.UserRepository,
+	cr repositories.WebAuthnCredentialRepository,
+	cs repositories.ChallengeStore,
+	wa *webauthn.WebAuthn,
+	ceremonyTTL time.Duration,
+) *WebAuthnInteractor {
+	return &WebAuthnInteractor{
+		userRepo:       ur,
+		credentialRepo: cr,
+		challengeStore: cs,
+		webAuthn:       wa,
+		ceremonyTTL:    ceremonyTTL,
+	}
+}
+
+// BeginRegistration memulai pendaftaran passkey baru dengan nama yang dipilih pengguna.
+// Kredensial yang sudah terdaftar dikecualikan agar authenticator yang sama tidak didaftarkan dua kali.
+func (i *WebAuthnInteractor) BeginRegistration(ctx context.Context, userID uuid.UUID, name string) (*protocol.CredentialCreation, error) {
+	name, err := normalizePasskeyName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	user, err := i.loadUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	exclusions := make([]protocol.CredentialDescriptor, 0, len(user.Passkeys))
+	for _, credential := range user.WebAuthnCredentials() {
+		exclusions = append(exclusions, credential.Descriptor())
+	}
+
+	creation, session, err := i.webAuthn.BeginRegistration(
+		user,
+		webauthn.WithExclusions(exclusions),
+		webauthn.WithResidentKeyRequirement(protocol.ResidentKeyRequirementPreferred),
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.saveCeremony(ctx, registrationKey(user.ID), registrationCeremony{Session: *session, Name: name}); err != nil {
+		return nil, err
+	}
+	return creation, nil
+}
+
+// FinishRegistration memverifikasi respons attestation dari authenticator lalu menyimpan passkey.
+func (i *WebAuthnInteractor) FinishRegistration(ctx context.Context, userID uuid.UUID, response []byte) (*entities.WebAuthnCredential, error) {
+	var ceremony registrationCeremony
+	if err := i.takeCeremony(ctx, registrationKey(userID), &ceremony); err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialCreationResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, ErrPasskeyVerificationFailed
+	}
+
+	user, err := i.loadUser(userID)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := i.webAuthn.CreateCredential(user, ceremony.Session, parsed)
+	if err != nil {
+		log.Printf("Registrasi passkey untuk %s gagal: %v", user.ID, err)
+		return nil, ErrPasskeyVerificationFailed
+	}
+
+	stored := entities.NewWebAuthnCredential(user.ID, ceremony.Name, credential)
+	if err := i.credentialRepo.Create(stored); err != nil {
+		return nil, err
+	}
+	return stored, nil
+}
+
+// BeginLogin memulai login tanpa password dengan passkey yang dapat ditemukan (discoverable credential).
+// Pengguna belum diketahui pada tahap ini, sehingga sesi disimpan di bawah ID acak yang dikembalikan ke klien.
+func (i *WebAuthnInteractor) BeginLogin(ctx context.Context) (*protocol.CredentialAssertion, string, error) {
+	assertion, session, err := i.webAuthn.BeginDiscoverableLogin(
+		webauthn.WithUserVerification(protocol.VerificationRequired),
+	)
+	if err != nil {
+		return nil, "", err
+	}
+
+	sessionID := uuid.NewString()
+	if err := i.saveCeremony(ctx, loginKey(sessionID), session); err != nil {
+		return nil, "", err
+	}
+	return assertion, sessionID, nil
+}
+
+// FinishLogin memverifikasi assertion login tanpa password dan mengembalikan pemilik passkey.
+// User verification diwajibkan sehingga passkey sudah mewakili dua faktor.
+func (i *WebAuthnInteractor) FinishLogin(ctx context.Context, sessionID string, response []byte) (*entities.User, error) {
+	var session webauthn.SessionData
+	if err := i.takeCeremony(ctx, loginKey(sessionID), &session); err != nil {
+		return nil, err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return nil, ErrPasskeyVerificationFailed
+	}
+
+	var owner *entities.User
+	credential, err := i.webAuthn.ValidateDiscoverableLogin(func(_, userHandle []byte) (webauthn.User, error) {
+		userID, err := uuid.FromBytes(userHandle)
+		if err != nil {
+			return nil, err
+		}
+		user, err := i.loadUser(userID)
+		if err != nil {
+			return nil, err
+		}
+		owner = user
+		return user, nil
+	}, session, parsed)
+	if err != nil {
+		log.Printf("Login passkey gagal: %v", err)
+		return nil, ErrPasskeyVerificationFailed
+	}
+
+	if err := i.recordUse(owner, credential); err != nil {
+		return nil, err
+	}
+	return owner, nil
+}
+
+// BeginSecondFactor memulai assertion passkey sebagai faktor kedua untuk pengguna yang sudah lolos password.
+func (i *WebAuthnInteractor) BeginSecondFactor(ctx context.Context, userID uuid.UUID) (*protocol.CredentialAssertion, error) {
+	user, err := i.loadUser(userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(user.Passkeys) == 0 {
+		return nil, ErrPasskeyNotRegistered
+	}
+
+	assertion, session, err := i.webAuthn.BeginLogin(user)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := i.saveCeremony(ctx, secondFactorKey(user.ID), session); err != nil {
+		return nil, err
+	}
+	return assertion, nil
+}
+
+// FinishSecondFactor memverifikasi assertion passkey sebagai faktor kedua.
+func (i *WebAuthnInteractor) FinishSecondFactor(ctx context.Context, userID uuid.UUID, response []byte) error {
+	var session webauthn.SessionData
+	if err := i.takeCeremony(ctx, secondFactorKey(userID), &session); err != nil {
+		return err
+	}
+
+	parsed, err := protocol.ParseCredentialRequestResponseBody(bytes.NewReader(response))
+	if err != nil {
+		return ErrPasskeyVerificationFailed
+	}
+
+	user, err := i.loadUser(userID)
+	if err != nil {
+		return err
+	}
+
+	credential, err := i.webAuthn.ValidateLogin(user, session, parsed)
+	if err != nil {
+		log.Printf("Verifikasi passkey untuk %s gagal: %v", user.ID, err)
+		return ErrPasskeyVerificationFailed
+	}
+	return i.recordUse(user, credential)
+}
+
+// HasCredentials mengembalikan true jika pengguna memiliki setidaknya satu passkey.
+func (i *WebAuthnInteractor) HasCredentials(userID uuid.UUID) (bool, error) {
+	count, err := i.credentialRepo.CountByUser(userID)
+	return count > 0, err
+}
+
+// ListCredentials mengembalikan semua passkey milik pengguna.
+func (i *WebAuthnInteractor) ListCredentials(userID uuid.UUID) ([]entities.WebAuthnCredential, error) {
+	return i.credentialRepo.FindByUser(userID)
+}
+
+// RenameCredential mengganti nama passkey milik pengguna.
+func (i *WebAuthnInteractor) RenameCredential(userID, id uuid.UUID, name string) (*entities.WebAuthnCredential, error) {
+	name, err := normalizePasskeyName(name)
+	if err != nil {
+		return nil, err
+	}
+
+	credential, err := i.credentialRepo.FindByID(userID, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, ErrPasskeyNotFound
+		}
+		return nil, err
+	}
+
+	credential.Name = name
+	if err := i.credentialRepo.Update(credential); err != nil {
+		return nil, err
+	}
+	return credential, nil
+}
+
+// DeleteCredential menghapus passkey milik pengguna.
+func (i *WebAuthnInteractor) DeleteCredential(userID, id uuid.UUID) error {
+	if err := i.credentialRepo.Delete(userID, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return ErrPasskeyNotFound
+		}
+		return err
+	}
+	return nil
+}
+
+// recordUse menyimpan penghitung tanda tangan dan waktu pakai kredensial setelah assertion berhasil.
+// Penghitung yang mundur menandakan authenticator mungkin dikloning sehingga login ditolak.
+func (i *WebAuthnInteractor) recordUse(user *entities.User, credential *webauthn.Credential) error {
+	if credential.Authenticator.CloneWarning {
+		log.Printf("Penghitung passkey milik %s mundur, kemungkinan authenticator dikloning", user.ID)
+		return ErrPasskeyVerificationFailed
+	}
+
+	for n := range user.Passkeys {
+		stored := &user.Passkeys[n]
+		if !bytes.Equal(stored.CredentialID, credential.ID) {
+			continue
+		}
+		stored.MarkUsed(credential, time.Now())
+		return i.credentialRepo.Update(stored)
+	}
+	return ErrPasskeyNotFound
+}
+
+// loadUser mengambil pengguna beserta semua passkey-nya.
+func (i *WebAuthnInteractor) loadUser(userID uuid.UUID) (*entities.User, error) {
+	user, err := i.userRepo.FindByID(userID)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, errors.New("pengguna tidak ditemukan")
+		}
+		return nil, err
+	}
+
+	user.Passkeys, err = i.credentialRepo.FindByUser(user.ID)
+	if err != nil {
+		return nil, err
+	}
+	return user, nil
+}
+
+// saveCeremony menyimpan data sesi ceremony sebagai JSON.
+func (i *WebAuthnInteractor) saveCeremony(ctx context.Context, key string, value any) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return i.challengeStore.Save(ctx, key, data, i.ceremonyTTL)
+}
+
+// takeCeremony mengambil dan menghapus data sesi ceremony.
+func (i *WebAuthnInteractor) takeCeremony(ctx context.Context, key string, value any) error {
+	data, err := i.challengeStore.Take(ctx, key)
+	if err != nil {
+		if errors.Is(err, repositories.ErrChallengeNotFound) {
+			return ErrPasskeyCeremonyExpired
+		}
+		return err
+	}
+	return json.Unmarshal(data, value)
+}
+
+// normalizePasskeyName memangkas spasi dan memvalidasi nama passkey.
+func normalizePasskeyName(name string) (string, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrPasskeyNameRequired
+	}
+	if len([]rune(name)) > passkeyNameMaxLength {
+		name = string([]rune(name)[:passkeyNameMaxLength])
+	}
+	return name, nil
+}
+
+// registrationKey adalah key ChallengeStore untuk ceremony registrasi milik pengguna.
+func registrationKey(userID uuid.UUID) string {
+	return "webauthn:register:" + userID.String()
+}
+
+// loginKey adalah key ChallengeStore untuk ceremony login tanpa password.
+func loginKey(sessionID string) string {
+	return "webauthn:login:" + sessionID
+}
+
+// secondFactorKey adalah key ChallengeStore untuk ceremony passkey sebagai faktor kedua.
+func secondFactorKey(userID uuid.UUID) string {
+	return "webauthn:mfa:" + userID.String()
+}
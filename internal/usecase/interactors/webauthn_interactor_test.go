@@ -0,0 +1,333 @@
+package interactors
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"fiber-usermanagement/internal/domain/entities"
+
+	"github.com/go-webauthn/webauthn/protocol"
+	"github.com/go-webauthn/webauthn/protocol/webauthncbor"
+	"github.com/go-webauthn/webauthn/protocol/webauthncose"
+	"github.com/go-webauthn/webauthn/webauthn"
+	"github.com/google/uuid"
+)
+
+const (
+	testRPID   = "example.com"
+	testOrigin = "https://example.com"
+)
+
+// softAuthenticator adalah authenticator WebAuthn perangkat lunak dengan kunci ES256 dan
+// attestation "none". rpID, origin dan signCount bisa diubah untuk mensimulasikan serangan.
+type softAuthenticator struct {
+	key       *ecdsa.PrivateKey
+	id        []byte
+	rpID      string
+	origin    string
+	signCount uint32
+}
+
+func newSoftAuthenticator(t *testing.T) *softAuthenticator {
+	t.Helper()
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		t.Fatal(err)
+	}
+	return &softAuthenticator{key: key, id: id, rpID: testRPID, origin: testOrigin}
+}
+
+// create membuat respons attestation untuk navigator.credentials.create().
+func (a *softAuthenticator) create(t *testing.T, creation *protocol.CredentialCreation) []byte {
+	t.Helper()
+	clientData := a.clientData(t, "webauthn.create", creation.Response.Challenge.String())
+
+	publicKey, err := webauthncbor.Marshal(webauthncose.EC2PublicKeyData{
+		PublicKeyData: webauthncose.PublicKeyData{
+			KeyType:   int64(webauthncose.EllipticKey),
+			Algorithm: int64(webauthncose.AlgES256),
+		},
+		Curve:  1, // P-256
+		XCoord: a.key.PublicKey.X.FillBytes(make([]byte, 32)),
+		YCoord: a.key.PublicKey.Y.FillBytes(make([]byte, 32)),
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	authData := a.authData(0x45) // UP | UV | AT
+	authData = append(authData, make([]byte, 16)...)
+	authData = binary.BigEndian.AppendUint16(authData, uint16(len(a.id)))
+	authData = append(authData, a.id...)
+	authData = append(authData, publicKey...)
+
+	attestation, err := webauthncbor.Marshal(map[string]any{
+		"fmt":      "none",
+		"attStmt":  map[string]any{},
+		"authData": authData,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.response(t, map[string]string{
+		"clientDataJSON":    encode(clientData),
+		"attestationObject": encode(attestation),
+	})
+}
+
+// get membuat respons assertion untuk navigator.credentials.get() dengan penghitung saat ini.
+func (a *softAuthenticator) get(t *testing.T, assertion *protocol.CredentialAssertion, userHandle []byte) []byte {
+	t.Helper()
+	clientData := a.clientData(t, "webauthn.get", assertion.Response.Challenge.String())
+	authData := a.authData(0x05) // UP | UV
+
+	clientDataHash := sha256.Sum256(clientData)
+	digest := sha256.Sum256(append(append([]byte{}, authData...), clientDataHash[:]...))
+	signature, err := ecdsa.SignASN1(rand.Reader, a.key, digest[:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return a.response(t, map[string]string{
+		"clientDataJSON":    encode(clientData),
+		"authenticatorData": encode(authData),
+		"signature":         encode(signature),
+		"userHandle":        encode(userHandle),
+	})
+}
+
+func (a *softAuthenticator) clientData(t *testing.T, ceremony, challenge string) []byte {
+	t.Helper()
+	data, err := json.Marshal(map[string]string{"type": ceremony, "challenge": challenge, "origin": a.origin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return data
+}
+
+func (a *softAuthenticator) authData(flags byte) []byte {
+	rpIDHash := sha256.Sum256([]byte(a.rpID))
+	data := append(rpIDHash[:], flags)
+	return binary.BigEndian.AppendUint32(data, a.signCount)
+}
+
+func (a *softAuthenticator) response(t *testing.T, response map[string]string) []byte {
+	t.Helper()
+	body, err := json.Marshal(map[string]any{
+		"id":       encode(a.id),
+		"rawId":    encode(a.id),
+		"type":     "public-key",
+		"response": response,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return body
+}
+
+func encode(data []byte) string {
+	return base64.RawURLEncoding.EncodeToString(data)
+}
+
+// newTestWebAuthnInteractor menyiapkan WebAuthnInteractor dengan satu pengguna dan penyimpanan di memori.
+func newTestWebAuthnInteractor(t *testing.T) (*WebAuthnInteractor, *entities.User, *memoryWebAuthnCredentialRepository) {
+	t.Helper()
+	wa, err := webauthn.New(&webauthn.Config{
+		RPID:          testRPID,
+		RPDisplayName: "User Management",
+		RPOrigins:     []string{testOrigin},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	user := &entities.User{ID: uuid.New(), Username: "alice", Email: "alice@example.com", IsActive: true}
+	credentials := &memoryWebAuthnCredentialRepository{}
+	interactor := NewWebAuthnInteractor(newMemoryUserRepository(user), credentials, newTestChallengeStore(t), wa, time.Minute)
+	return interactor, user, credentials
+}
+
+// register menjalankan ceremony registrasi lengkap dan gagal jika passkey tidak tersimpan.
+func register(t *testing.T, interactor *WebAuthnInteractor, userID uuid.UUID, authenticator *softAuthenticator) {
+	t.Helper()
+	ctx := context.Background()
+	creation, err := interactor.BeginRegistration(ctx, userID, "Laptop")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := interactor.FinishRegistration(ctx, userID, authenticator.create(t, creation)); err != nil {
+		t.Fatalf("FinishRegistration: %v", err)
+	}
+}
+
+func TestWebAuthnRegistrationAndLoginRoundTrip(t *testing.T) {
+	ctx := context.Background()
+	interactor, user, credentials := newTestWebAuthnInteractor(t)
+	authenticator := newSoftAuthenticator(t)
+
+	register(t, interactor, user.ID, authenticator)
+	stored, _ := credentials.FindByUser(user.ID)
+	if len(stored) != 1 || stored[0].Name != "Laptop" {
+		t.Fatalf("stored credentials = %+v, want one named Laptop", stored)
+	}
+
+	assertion, sessionID, err := interactor.BeginLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.signCount = 1
+	owner, err := interactor.FinishLogin(ctx, sessionID, authenticator.get(t, assertion, user.WebAuthnID()))
+	if err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+	if owner.ID != user.ID {
+		t.Fatalf("owner = %s, want %s", owner.ID, user.ID)
+	}
+
+	assertion, err = interactor.BeginSecondFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.signCount = 2
+	if err := interactor.FinishSecondFactor(ctx, user.ID, authenticator.get(t, assertion, user.WebAuthnID())); err != nil {
+		t.Fatalf("FinishSecondFactor: %v", err)
+	}
+
+	stored, _ = credentials.FindByUser(user.ID)
+	if stored[0].SignCount != 2 || stored[0].LastUsedAt == nil {
+		t.Fatalf("sign count = %d, last used = %v; want 2 and set", stored[0].SignCount, stored[0].LastUsedAt)
+	}
+}
+
+func TestWebAuthnRejectsSignCountRegression(t *testing.T) {
+	ctx := context.Background()
+	interactor, user, credentials := newTestWebAuthnInteractor(t)
+	authenticator := newSoftAuthenticator(t)
+	register(t, interactor, user.ID, authenticator)
+
+	assertion, err := interactor.BeginSecondFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.signCount = 5
+	if err := interactor.FinishSecondFactor(ctx, user.ID, authenticator.get(t, assertion, user.WebAuthnID())); err != nil {
+		t.Fatalf("FinishSecondFactor: %v", err)
+	}
+
+	// Authenticator hasil kloning masih memakai penghitung lama
+	assertion, err = interactor.BeginSecondFactor(ctx, user.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.signCount = 3
+	err = interactor.FinishSecondFactor(ctx, user.ID, authenticator.get(t, assertion, user.WebAuthnID()))
+	if !errors.Is(err, ErrPasskeyVerificationFailed) {
+		t.Fatalf("err = %v, want ErrPasskeyVerificationFailed", err)
+	}
+
+	stored, _ := credentials.FindByUser(user.ID)
+	if stored[0].SignCount != 5 {
+		t.Fatalf("sign count = %d, want 5 to be kept", stored[0].SignCount)
+	}
+}
+
+func TestWebAuthnChallengeCannotBeReused(t *testing.T) {
+	ctx := context.Background()
+	interactor, user, _ := newTestWebAuthnInteractor(t)
+	authenticator := newSoftAuthenticator(t)
+	register(t, interactor, user.ID, authenticator)
+
+	assertion, sessionID, err := interactor.BeginLogin(ctx)
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator.signCount = 1
+	response := authenticator.get(t, assertion, user.WebAuthnID())
+	if _, err := interactor.FinishLogin(ctx, sessionID, response); err != nil {
+		t.Fatalf("FinishLogin: %v", err)
+	}
+
+	authenticator.signCount = 2
+	_, err = interactor.FinishLogin(ctx, sessionID, authenticator.get(t, assertion, user.WebAuthnID()))
+	if !errors.Is(err, ErrPasskeyCeremonyExpired) {
+		t.Fatalf("replayed login err = %v, want ErrPasskeyCeremonyExpired", err)
+	}
+
+	// Registrasi yang gagal juga menghabiskan tantangannya
+	creation, err := interactor.BeginRegistration(ctx, user.ID, "Phone")
+	if err != nil {
+		t.Fatal(err)
+	}
+	phone := newSoftAuthenticator(t)
+	phone.origin = "https://evil.example"
+	if _, err := interactor.FinishRegistration(ctx, user.ID, phone.create(t, creation)); !errors.Is(err, ErrPasskeyVerificationFailed) {
+		t.Fatalf("FinishRegistration err = %v, want ErrPasskeyVerificationFailed", err)
+	}
+	phone.origin = testOrigin
+	if _, err := interactor.FinishRegistration(ctx, user.ID, phone.create(t, creation)); !errors.Is(err, ErrPasskeyCeremonyExpired) {
+		t.Fatalf("retried registration err = %v, want ErrPasskeyCeremonyExpired", err)
+	}
+}
+
+func TestWebAuthnRejectsWrongOriginAndRPID(t *testing.T) {
+	tests := []struct {
+		name   string
+		origin string
+		rpID   string
+	}{
+		{name: "wrong origin", origin: "https://evil.example", rpID: testRPID},
+		{name: "sibling origin", origin: "https://login.example.com", rpID: testRPID},
+		{name: "wrong rp id", origin: testOrigin, rpID: "evil.example"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name+" on registration", func(t *testing.T) {
+			ctx := context.Background()
+			interactor, user, credentials := newTestWebAuthnInteractor(t)
+			authenticator := newSoftAuthenticator(t)
+			authenticator.origin, authenticator.rpID = tt.origin, tt.rpID
+
+			creation, err := interactor.BeginRegistration(ctx, user.ID, "Laptop")
+			if err != nil {
+				t.Fatal(err)
+			}
+			if _, err := interactor.FinishRegistration(ctx, user.ID, authenticator.create(t, creation)); !errors.Is(err, ErrPasskeyVerificationFailed) {
+				t.Fatalf("err = %v, want ErrPasskeyVerificationFailed", err)
+			}
+			if count, _ := credentials.CountByUser(user.ID); count != 0 {
+				t.Fatalf("stored %d credentials, want none", count)
+			}
+		})
+
+		t.Run(tt.name+" on login", func(t *testing.T) {
+			ctx := context.Background()
+			interactor, user, _ := newTestWebAuthnInteractor(t)
+			authenticator := newSoftAuthenticator(t)
+			register(t, interactor, user.ID, authenticator)
+
+			assertion, sessionID, err := interactor.BeginLogin(ctx)
+			if err != nil {
+				t.Fatal(err)
+			}
+			authenticator.origin, authenticator.rpID = tt.origin, tt.rpID
+			authenticator.signCount = 1
+			if _, err := interactor.FinishLogin(ctx, sessionID, authenticator.get(t, assertion, user.WebAuthnID())); !errors.Is(err, ErrPasskeyVerificationFailed) {
+				t.Fatalf("err = %v, want ErrPasskeyVerificationFailed", err)
+			}
+		})
+	}
+}
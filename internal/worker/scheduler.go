@@ -0,0 +1,63 @@
+package worker
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// Job adalah pekerjaan terjadwal yang dijalankan berulang oleh Scheduler.
+type Job struct {
+	Name     string
+	Interval time.Duration
+	Run      func(ctx context.Context) error
+}
+
+// Scheduler menjalankan setiap Job segera saat mulai lalu berulang sesuai Interval-nya.
+// Satu Job tidak pernah berjalan tumpang tindih dengan dirinya sendiri.
+type Scheduler struct {
+	jobs   []Job
+	logger *zap.Logger
+}
+
+// NewScheduler membuat instance baru dari Scheduler.
+func NewScheduler(logger *zap.Logger, jobs ...Job) *Scheduler {
+	return &Scheduler{jobs: jobs, logger: logger}
+}
+
+// Run menjalankan semua Job sampai ctx dibatalkan, lalu menunggu Job yang sedang berjalan selesai.
+func (s *Scheduler) Run(ctx context.Context) {
+	var wg sync.WaitGroup
+	for _, job := range s.jobs {
+		wg.Add(1)
+		go func(job Job) {
+			defer wg.Done()
+			s.loop(ctx, job)
+		}(job)
+	}
+	wg.Wait()
+}
+
+// loop menjalankan satu Job berulang sampai ctx dibatalkan.
+func (s *Scheduler) loop(ctx context.Context, job Job) {
+	ticker := time.NewTicker(job.Interval)
+	defer ticker.Stop()
+
+	s.logger.Info("Scheduled job started", zap.String("job", job.Name), zap.Duration("interval", job.Interval))
+	for {
+		start := time.Now()
+		if err := job.Run(ctx); err != nil {
+			s.logger.Error("Scheduled job failed", zap.String("job", job.Name), zap.Error(err))
+		} else {
+			s.logger.Debug("Scheduled job finished", zap.String("job", job.Name), zap.Duration("took", time.Since(start)))
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}